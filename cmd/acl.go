@@ -0,0 +1,161 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// aclFlag sets a canned ACL on the objects or buckets written.
+var aclFlag = cli.StringFlag{
+	Name:  "acl",
+	Usage: "set a canned ACL on the objects written to the target, e.g. bucket-owner-full-control",
+}
+
+// Header of the canned ACL of new objects and buckets.
+const amzACL = "X-Amz-Acl"
+
+// Canned ACLs of objects.
+var cannedACLs = []string{
+	"private",
+	"public-read",
+	"public-read-write",
+	"authenticated-read",
+	"aws-exec-read",
+	"bucket-owner-read",
+	"bucket-owner-full-control",
+}
+
+// Canned ACLs of buckets.
+var cannedBucketACLs = []string{
+	"private",
+	"public-read",
+	"public-read-write",
+	"authenticated-read",
+	"log-delivery-write",
+}
+
+// Groups of grantees of ACLs.
+const (
+	aclAllUsers           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	aclAuthenticatedUsers = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+	aclLogDelivery        = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+)
+
+// checkCannedACL - validate a canned ACL against the valid ones.
+func checkCannedACL(acl string, valid []string) {
+	for _, cannedACL := range valid {
+		if acl == cannedACL {
+			return
+		}
+	}
+	fatalIf(errInvalidArgument().Trace(acl), "Unrecognized canned ACL `"+acl+"`. Valid options are `["+strings.Join(valid, ", ")+"]`.")
+}
+
+// checkACLFlag - validate the canned ACL of objects of '--acl'.
+func checkACLFlag(ctx *cli.Context) {
+	if acl := ctx.String("acl"); acl != "" {
+		checkCannedACL(acl, cannedACLs)
+	}
+}
+
+// accessControlPolicy - ACL of an object or bucket.
+type accessControlPolicy struct {
+	Owner struct {
+		ID string `xml:"ID"`
+	} `xml:"Owner"`
+	Grants []struct {
+		Grantee struct {
+			ID  string `xml:"ID"`
+			URI string `xml:"URI"`
+		} `xml:"Grantee"`
+		Permission string `xml:"Permission"`
+	} `xml:"AccessControlList>Grant"`
+}
+
+// cannedACL - the canned ACL granting the same permissions as a policy,
+// 'custom' for policies no canned ACL grants. Another canonical user
+// granted an object is taken as the owner of the bucket.
+func (p accessControlPolicy) cannedACL() string {
+	var grants []string
+	for _, grant := range p.Grants {
+		grantee := grant.Grantee.URI
+		switch {
+		case grantee == "" && grant.Grantee.ID == p.Owner.ID:
+			if grant.Permission == "FULL_CONTROL" {
+				continue
+			}
+			grantee = "owner"
+		case grantee == "":
+			grantee = "user"
+		}
+		grants = append(grants, grantee+" "+grant.Permission)
+	}
+	sort.Strings(grants)
+	switch strings.Join(grants, ", ") {
+	case "":
+		return "private"
+	case aclAllUsers + " READ":
+		return "public-read"
+	case aclAllUsers + " READ, " + aclAllUsers + " WRITE":
+		return "public-read-write"
+	case aclAuthenticatedUsers + " READ":
+		return "authenticated-read"
+	case aclLogDelivery + " READ_ACP, " + aclLogDelivery + " WRITE":
+		return "log-delivery-write"
+	case "user READ":
+		return "bucket-owner-read"
+	case "user FULL_CONTROL":
+		return "bucket-owner-full-control"
+	}
+	return "custom"
+}
+
+// getCannedACL - the canned ACL of an object, or of the bucket for an
+// empty object.
+func (c *s3Client) getCannedACL(bucket, object string) (string, *probe.Error) {
+	reqParams := make(url.Values)
+	reqParams.Set("acl", "")
+	resp, err := c.presignedDo(http.MethodGet, bucket, object, reqParams)
+	if err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	defer resp.Body.Close()
+	var policy accessControlPolicy
+	if e := xml.NewDecoder(resp.Body).Decode(&policy); e != nil {
+		return "", probe.NewError(e).Trace(bucket, object)
+	}
+	return policy.cannedACL(), nil
+}
+
+// SetBucketACL - replace the ACL of the bucket by a canned ACL.
+func (c *s3Client) SetBucketACL(region, acl string) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	header := make(http.Header)
+	header.Set(amzACL, acl)
+	return c.putBucketConfig(bucket, "acl", region, header, nil)
+}
@@ -0,0 +1,56 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestCannedACL(t *testing.T) {
+	const owner = "<Grant><Grantee><ID>owner</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>"
+	testCases := []struct {
+		grants string
+		acl    string
+	}{
+		{owner, "private"},
+		{"", "private"},
+		{owner + "<Grant><Grantee><URI>" + aclAllUsers + "</URI></Grantee><Permission>READ</Permission></Grant>", "public-read"},
+		{owner + "<Grant><Grantee><URI>" + aclAllUsers + "</URI></Grantee><Permission>WRITE</Permission></Grant>" +
+			"<Grant><Grantee><URI>" + aclAllUsers + "</URI></Grantee><Permission>READ</Permission></Grant>", "public-read-write"},
+		{owner + "<Grant><Grantee><URI>" + aclAuthenticatedUsers + "</URI></Grantee><Permission>READ</Permission></Grant>", "authenticated-read"},
+		{owner + "<Grant><Grantee><URI>" + aclLogDelivery + "</URI></Grantee><Permission>WRITE</Permission></Grant>" +
+			"<Grant><Grantee><URI>" + aclLogDelivery + "</URI></Grantee><Permission>READ_ACP</Permission></Grant>", "log-delivery-write"},
+		{owner + "<Grant><Grantee><ID>bucket-owner</ID></Grantee><Permission>READ</Permission></Grant>", "bucket-owner-read"},
+		{owner + "<Grant><Grantee><ID>bucket-owner</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>", "bucket-owner-full-control"},
+		// Grants no canned ACL has.
+		{"<Grant><Grantee><ID>owner</ID></Grantee><Permission>READ</Permission></Grant>", "custom"},
+		{owner + "<Grant><Grantee><URI>" + aclAllUsers + "</URI></Grantee><Permission>WRITE</Permission></Grant>", "custom"},
+		{owner + "<Grant><Grantee><ID>a</ID></Grantee><Permission>READ</Permission></Grant>" +
+			"<Grant><Grantee><ID>b</ID></Grantee><Permission>READ</Permission></Grant>", "custom"},
+	}
+	for i, testCase := range testCases {
+		var policy accessControlPolicy
+		data := "<AccessControlPolicy><Owner><ID>owner</ID></Owner><AccessControlList>" + testCase.grants + "</AccessControlList></AccessControlPolicy>"
+		if e := xml.Unmarshal([]byte(data), &policy); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if acl := policy.cannedACL(); acl != testCase.acl {
+			t.Errorf("Test %d: expected `%s`, found `%s`", i+1, testCase.acl, acl)
+		}
+	}
+}
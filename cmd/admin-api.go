@@ -0,0 +1,126 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// adminAPIClient - client of the admin APIs of the deployment of an alias
+// which the vendored madmin does not expose, such as site replication.
+type adminAPIClient struct {
+	*s3Client
+	alias  string
+	region string
+}
+
+// newAdminAPIClient - client of the deployment of an alias, URLs of
+// buckets are refused.
+func newAdminAPIClient(aliasedURL string) (*adminAPIClient, *probe.Error) {
+	alias, urlStrFull, hostCfg, err := expandAlias(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	if hostCfg == nil {
+		return nil, errInvalidAliasedURL(aliasedURL).Trace(aliasedURL)
+	}
+	clnt, err := newClientFromAlias(alias, urlStrFull)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return nil, errInvalidArgument().Trace(aliasedURL)
+	}
+	if bucket, _ := s3Clnt.url2BucketAndObject(); bucket != "" {
+		return nil, errInvalidArgument().Trace(aliasedURL)
+	}
+	return &adminAPIClient{s3Client: s3Clnt, alias: alias, region: hostCfg.Region}, nil
+}
+
+// do - send a signed request to an admin API path and decode the JSON
+// response into result, if not nil. A result which is an io.Writer gets
+// the response as it is, such as the binary data of inspect.
+func (c *adminAPIClient) do(method, path string, query url.Values, body []byte, result interface{}) *probe.Error {
+	reqURL := c.targetURL.Scheme + "://" + c.targetURL.Host + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, e := http.NewRequest(method, reqURL, bytes.NewReader(body))
+	if e != nil {
+		return probe.NewError(e)
+	}
+	req.ContentLength = int64(len(body))
+	sha256Sum := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum[:]))
+	region := c.region
+	if region == "" {
+		region = "us-east-1"
+	}
+	req = s3signer.SignV4(*req, c.accessKey, c.secretKey, c.sessionToken, region)
+
+	transport := c.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, e := (&http.Client{Transport: transport}).Do(req)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return probe.NewError(adminRespToError(resp))
+	}
+	if result == nil {
+		return nil
+	}
+	if w, ok := result.(io.Writer); ok {
+		_, e = io.Copy(w, resp.Body)
+	} else {
+		e = json.NewDecoder(resp.Body).Decode(result)
+	}
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// adminRespToError - decode the JSON error of a failed admin response.
+func adminRespToError(resp *http.Response) error {
+	errResp := madmin.ErrorResponse{}
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if e := json.Unmarshal(data, &errResp); e != nil || errResp.Code == "" {
+		errResp.Code = resp.Status
+		errResp.Message = strings.TrimSpace(string(data))
+		if errResp.Message == "" {
+			errResp.Message = http.StatusText(resp.StatusCode)
+		}
+	}
+	return errResp
+}
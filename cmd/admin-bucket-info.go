@@ -0,0 +1,233 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var adminBucketInfoFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "sort",
+		Value: "name",
+		Usage: "sort buckets by name, objects, size or versions, counts and sizes sort largest first",
+	},
+	cli.BoolFlag{
+		Name:  "versions",
+		Usage: "count object versions and delete markers as well",
+	},
+}
+
+var adminBucketInfoCmd = cli.Command{
+	Name:   "info",
+	Usage:  "report object count and size of buckets",
+	Action: mainAdminBucketInfo,
+	Before: setGlobalsFromContext,
+	Flags:  append(adminBucketInfoFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Objects are counted by listing the buckets, MinIO servers of this admin API version
+  keep no data usage statistics and have no bucket quotas to report.
+
+EXAMPLES:
+  1. Report the object count and size of all buckets on MinIO server, largest first.
+     $ {{.HelpName}} --sort size myminio
+
+  2. Report the object count, size and number of versions of the bucket 'mybucket'.
+     $ {{.HelpName}} --versions myminio/mybucket
+
+  3. Export the usage of all buckets as CSV for capacity planning.
+     $ {{.HelpName}} --output csv myminio > usage.csv
+`,
+}
+
+// Sort orders of 'admin bucket info'.
+var bucketInfoSortOrders = []string{"name", "objects", "size", "versions"}
+
+// bucketInfoMessage - usage of a bucket.
+type bucketInfoMessage struct {
+	Status  string `json:"status"`
+	Bucket  string `json:"bucket"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+	// Number of object versions and delete markers, -1 if not counted.
+	Versions int64 `json:"versions"`
+}
+
+// bucketInfoTable - columns of the bucket usage table.
+func bucketInfoTable() PrettyTable {
+	return newPrettyTable("  ",
+		Field{"Bucket", 30},
+		Field{"Objects", 14},
+		Field{"Size", 12},
+		Field{"Versions", -1},
+	)
+}
+
+// String colorized bucket usage message.
+func (b bucketInfoMessage) String() string {
+	versions := "-"
+	if b.Versions >= 0 {
+		versions = humanize.Comma(b.Versions)
+	}
+	return console.Colorize("BucketInfo", bucketInfoTable().buildRow(
+		b.Bucket, humanize.Comma(b.Objects), formatSize(b.Size), versions))
+}
+
+// JSON jsonified bucket usage message.
+func (b bucketInfoMessage) JSON() string {
+	b.Status = "success"
+	jsonBytes, e := json.MarshalIndent(b, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonBytes)
+}
+
+// sortBucketInfos - sort by name, or by the given count or size
+// largest first, with ties sorted by name.
+func sortBucketInfos(infos []bucketInfoMessage, order string) {
+	key := func(b bucketInfoMessage) int64 {
+		switch order {
+		case "objects":
+			return b.Objects
+		case "size":
+			return b.Size
+		case "versions":
+			return b.Versions
+		}
+		return 0
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		if ki, kj := key(infos[i]), key(infos[j]); ki != kj {
+			return ki > kj
+		}
+		return infos[i].Bucket < infos[j].Bucket
+	})
+}
+
+// getBucketInfo - count the objects, and the versions if requested, of
+// a bucket.
+func getBucketInfo(alias string, clnt *s3Client, bucket string, withVersions bool) (bucketInfoMessage, *probe.Error) {
+	info := bucketInfoMessage{Bucket: bucket, Versions: -1}
+	bucketURL := clnt.joinPath(bucket)
+	bucketClnt, err := newClientFromAlias(alias, bucketURL)
+	if err != nil {
+		return info, err.Trace(bucketURL)
+	}
+	for content := range bucketClnt.List(globalContext, true, false, DirNone) {
+		if content.Err != nil {
+			return info, content.Err.Trace(bucketURL)
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		info.Objects++
+		info.Size += content.Size
+	}
+	if withVersions {
+		versions, err := clnt.countVersions(bucket)
+		if err != nil {
+			return info, err.Trace(bucketURL)
+		}
+		info.Versions = versions
+	}
+	return info, nil
+}
+
+// checkAdminBucketInfoSyntax - validate all the passed arguments
+func checkAdminBucketInfoSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "info", 1) // last argument is exit code
+	}
+	order := ctx.String("sort")
+	for _, valid := range bucketInfoSortOrders {
+		if order == valid {
+			if order == "versions" && !ctx.Bool("versions") {
+				fatalIf(errInvalidArgument().Trace(order), "Sorting by versions requires `--versions`.")
+			}
+			return
+		}
+	}
+	fatalIf(errInvalidArgument().Trace(order), "Unrecognized sort order `"+order+"`. Valid options are `["+strings.Join(bucketInfoSortOrders, ", ")+"]`.")
+}
+
+// mainAdminBucketInfo is the handle for "mc admin bucket info" command.
+func mainAdminBucketInfo(ctx *cli.Context) error {
+	checkAdminBucketInfoSyntax(ctx)
+
+	console.SetColor("BucketInfo", color.New(color.FgGreen))
+	console.SetColor("Headers", color.New(color.FgGreen, color.Bold))
+
+	aliasedURL := filepath.ToSlash(ctx.Args().Get(0))
+	alias, urlStrFull, hostCfg, err := expandAlias(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to expand `"+aliasedURL+"`.")
+	if hostCfg == nil {
+		fatalIf(errInvalidAliasedURL(aliasedURL).Trace(aliasedURL), "No MinIO server configured for `"+aliasedURL+"`.")
+	}
+	clnt, err := newClientFromAlias(alias, urlStrFull)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize `"+aliasedURL+"`.")
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		fatalIf(errInvalidAliasedURL(aliasedURL).Trace(aliasedURL), "No MinIO server configured for `"+aliasedURL+"`.")
+	}
+
+	bucket, object := s3Clnt.url2BucketAndObject()
+	if object != "" {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "`"+aliasedURL+"` is not a bucket.")
+	}
+	buckets := []string{bucket}
+	if bucket == "" {
+		bucketsInfo, e := s3Clnt.api.ListBuckets()
+		fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to list buckets of `"+aliasedURL+"`.")
+		buckets = buckets[:0]
+		for _, b := range bucketsInfo {
+			buckets = append(buckets, b.Name)
+		}
+	}
+
+	var infos []bucketInfoMessage
+	for _, bucket := range buckets {
+		info, err := getBucketInfo(alias, s3Clnt, bucket, ctx.Bool("versions"))
+		fatalIf(err, "Unable to get the usage of bucket `"+bucket+"`.")
+		infos = append(infos, info)
+	}
+	sortBucketInfos(infos, ctx.String("sort"))
+
+	if !globalJSON {
+		console.Println(console.Colorize("Headers", bucketInfoTable().buildRow("Bucket", "Objects", "Size", "Versions")))
+	}
+	for _, info := range infos {
+		printMsg(info)
+	}
+	return nil
+}
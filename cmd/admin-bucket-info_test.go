@@ -0,0 +1,49 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortBucketInfos(t *testing.T) {
+	infos := []bucketInfoMessage{
+		{Bucket: "logs", Objects: 900, Size: 10 << 20, Versions: 1000},
+		{Bucket: "backups", Objects: 20, Size: 80 << 30, Versions: 20},
+		{Bucket: "archive", Objects: 20, Size: 1 << 30, Versions: 40},
+	}
+	testCases := []struct {
+		order    string
+		expected []string
+	}{
+		{"name", []string{"archive", "backups", "logs"}},
+		{"objects", []string{"logs", "archive", "backups"}},
+		{"size", []string{"backups", "archive", "logs"}},
+		{"versions", []string{"logs", "archive", "backups"}},
+	}
+	for i, testCase := range testCases {
+		sortBucketInfos(infos, testCase.order)
+		var buckets []string
+		for _, info := range infos {
+			buckets = append(buckets, info.Bucket)
+		}
+		if !reflect.DeepEqual(buckets, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, buckets)
+		}
+	}
+}
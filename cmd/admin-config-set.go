@@ -30,7 +30,7 @@ import (
 var adminConfigSetCmd = cli.Command{
 	Name:   "set",
 	Usage:  "set new config file to a MinIO server/cluster.",
-	Before: setGlobalsFromContext,
+	Before: setGlobalsFromMutatingContext,
 	Action: mainAdminConfigSet,
 	Flags:  globalFlags,
 	CustomHelpTemplate: `NAME:
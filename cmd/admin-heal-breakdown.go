@@ -0,0 +1,188 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	healBreakdownSet   = "set"
+	healBreakdownDrive = "drive"
+)
+
+// Health colors from healthy to unrecoverable.
+var healCols = []col{colGreen, colYellow, colRed, colGrey}
+
+// parseHealFilter - the lower case health colors of a comma separated
+// --filter list.
+func parseHealFilter(filter string) (map[string]bool, *probe.Error) {
+	cols := make(map[string]bool)
+	for _, c := range strings.Split(filter, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		valid := false
+		for _, healCol := range healCols {
+			if c == strings.ToLower(string(healCol)) {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, errInvalidArgument().Trace(filter)
+		}
+		cols[c] = true
+	}
+	return cols, nil
+}
+
+// showItem - whether items of a lower case health color pass --filter.
+func (ui *uiData) showItem(c string) bool {
+	return ui.Filter == nil || ui.Filter[c]
+}
+
+// healSetKey - an erasure set is named after the first and the last of
+// its drives, which are consecutive endpoints of the server.
+func healSetKey(drives []madmin.HealDriveInfo) string {
+	keys := make([]string, 0, len(drives))
+	for _, drive := range drives {
+		keys = append(keys, healDriveKey(drive))
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	if len(keys) == 1 {
+		return keys[0]
+	}
+	return keys[0] + ".." + keys[len(keys)-1]
+}
+
+// updateBreakdown - count the health color of an item for its erasure
+// set or for each of its drives. Only objects belong to a single erasure
+// set, other items are left out of the sets.
+func (ui *uiData) updateBreakdown(i madmin.HealResultItem, c col) {
+	count := func(key string) {
+		cols, ok := ui.Breakdown[key]
+		if !ok {
+			cols = make(map[col]int64)
+			ui.Breakdown[key] = cols
+		}
+		cols[c]++
+	}
+	switch ui.BreakdownBy {
+	case healBreakdownSet:
+		if i.Type == madmin.HealItemObject {
+			if key := healSetKey(i.After.Drives); key != "" {
+				count(key)
+			}
+		}
+	case healBreakdownDrive:
+		for _, drive := range i.After.Drives {
+			count(healDriveKey(drive))
+		}
+	}
+}
+
+// sortedBreakdown - the erasure sets or drives of the breakdown, sorted.
+func (ui *uiData) sortedBreakdown() []string {
+	keys := make([]string, 0, len(ui.Breakdown))
+	for key := range ui.Breakdown {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// breakdownCol - the least healthy color counted for a set or drive.
+func breakdownCol(cols map[col]int64) col {
+	worst := colGreen
+	for _, c := range healCols {
+		if cols[c] > 0 {
+			worst = c
+		}
+	}
+	return worst
+}
+
+// printBreakdownTable - print the health colors per erasure set or per
+// drive as a table, returns the number of printed lines.
+func (ui *uiData) printBreakdownTable() int {
+	header := []string{strings.Title(ui.BreakdownBy)}
+	for _, c := range healCols {
+		header = append(header, string(c))
+	}
+	rowColors := []*color.Color{color.New(color.Bold)}
+	rows := [][]string{header}
+	for _, key := range ui.sortedBreakdown() {
+		cols := ui.Breakdown[key]
+		row := []string{key}
+		for _, c := range healCols {
+			row = append(row, humanize.Comma(cols[c]))
+		}
+		rowColors = append(rowColors, getPrintCol(breakdownCol(cols)))
+		rows = append(rows, row)
+	}
+	t := console.NewTable(rowColors, []bool{false, true, true, true, true}, 4)
+	t.DisplayTable(rows)
+	// Rows and the borders of the table.
+	return len(rows) + 2
+}
+
+// breakdownJSON - the health colors per erasure set or per drive, with
+// lower case colors.
+func (ui *uiData) breakdownJSON() map[string]map[string]int64 {
+	if ui.Breakdown == nil {
+		return nil
+	}
+	breakdown := make(map[string]map[string]int64, len(ui.Breakdown))
+	for key, cols := range ui.Breakdown {
+		breakdown[key] = make(map[string]int64, len(healCols))
+		for _, c := range healCols {
+			breakdown[key][strings.ToLower(string(c))] = cols[c]
+		}
+	}
+	return breakdown
+}
+
+// printFilteredItems - print the items passing --filter above the heal
+// status, they stay on the terminal while the status is updated.
+func (ui *uiData) printFilteredItems(s *madmin.HealTaskStatus) (err error) {
+	var a col
+	for _, item := range s.Items {
+		h := newHRI(&item)
+		switch h.Type {
+		case madmin.HealItemMetadata, madmin.HealItemBucket:
+			_, a, err = h.getReplicatedFileHCCChange()
+		default:
+			_, a, err = h.getObjectHCCChange()
+		}
+		if err != nil {
+			return err
+		}
+		if ui.showItem(strings.ToLower(string(a))) {
+			console.PrintC(getPrintCol(a).Sprint(fmt.Sprintf("%-6s ", string(a))), h.getHealResultStr(), "\n")
+		}
+	}
+	return nil
+}
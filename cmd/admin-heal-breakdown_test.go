@@ -0,0 +1,112 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestParseHealFilter(t *testing.T) {
+	testCases := []struct {
+		filter   string
+		expected map[string]bool
+		success  bool
+	}{
+		{"red", map[string]bool{"red": true}, true},
+		{"Red, grey", map[string]bool{"red": true, "grey": true}, true},
+		{"red,blue", nil, false},
+		{"", nil, false},
+	}
+	for i, testCase := range testCases {
+		filter, err := parseHealFilter(testCase.filter)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+		if err == nil && !reflect.DeepEqual(filter, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, filter)
+		}
+	}
+}
+
+func TestUpdateBreakdown(t *testing.T) {
+	drives := func(endpoints ...string) []madmin.HealDriveInfo {
+		var infos []madmin.HealDriveInfo
+		for _, endpoint := range endpoints {
+			infos = append(infos, madmin.HealDriveInfo{Endpoint: endpoint, State: madmin.DriveStateOk})
+		}
+		return infos
+	}
+	item := func(typ madmin.HealItemType, after []madmin.HealDriveInfo) madmin.HealResultItem {
+		i := madmin.HealResultItem{Type: typ}
+		i.After.Drives = after
+		return i
+	}
+	set1, set2 := drives("/d2", "/d1"), drives("/d3", "/d4")
+	items := []struct {
+		item madmin.HealResultItem
+		col  col
+	}{
+		{item(madmin.HealItemObject, set1), colGreen},
+		{item(madmin.HealItemObject, set1), colRed},
+		{item(madmin.HealItemObject, set2), colYellow},
+		// Buckets span all erasure sets.
+		{item(madmin.HealItemBucket, append(set1, set2...)), colGreen},
+	}
+
+	testCases := []struct {
+		by       string
+		expected map[string]map[col]int64
+	}{
+		{healBreakdownSet, map[string]map[col]int64{
+			"/d1../d2": {colGreen: 1, colRed: 1},
+			"/d3../d4": {colYellow: 1},
+		}},
+		{healBreakdownDrive, map[string]map[col]int64{
+			"/d1": {colGreen: 2, colRed: 1},
+			"/d2": {colGreen: 2, colRed: 1},
+			"/d3": {colGreen: 1, colYellow: 1},
+			"/d4": {colGreen: 1, colYellow: 1},
+		}},
+	}
+	for i, testCase := range testCases {
+		ui := uiData{Breakdown: make(map[string]map[col]int64), BreakdownBy: testCase.by}
+		for _, i := range items {
+			ui.updateBreakdown(i.item, i.col)
+		}
+		if !reflect.DeepEqual(ui.Breakdown, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, ui.Breakdown)
+		}
+	}
+
+	cols := []struct {
+		cols     map[col]int64
+		expected col
+	}{
+		{map[col]int64{colGreen: 3}, colGreen},
+		{map[col]int64{colGreen: 3, colYellow: 1}, colYellow},
+		{map[col]int64{colRed: 1, colGrey: 1}, colGrey},
+		{map[col]int64{}, colGreen},
+	}
+	for i, testCase := range cols {
+		if c := breakdownCol(testCase.cols); c != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, c)
+		}
+	}
+}
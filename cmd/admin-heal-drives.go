@@ -0,0 +1,184 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// healDriveStats - heal results of the items scanned so far, aggregated
+// for one drive.
+type healDriveStats struct {
+	Endpoint string `json:"endpoint"`
+	// State of the drive after healing the last scanned item.
+	State string `json:"state"`
+
+	Items     int64 `json:"items"`
+	Healed    int64 `json:"healed"`
+	Missing   int64 `json:"missing"`
+	Corrupted int64 `json:"corrupted"`
+	Offline   int64 `json:"offline"`
+
+	// Number of times the drive went offline or came back online
+	// between scanned items.
+	OfflineTransitions int64 `json:"offline_transitions"`
+}
+
+// progress - percentage of the items missing or corrupted on the drive
+// which are healed, a drive without such items is complete.
+func (d healDriveStats) progress() float64 {
+	needHeal := d.Missing + d.Corrupted
+	if needHeal == 0 {
+		return 100
+	}
+	return float64(d.Healed) * 100 / float64(needHeal)
+}
+
+// healDriveKey - drives are identified by endpoint, or by UUID when the
+// endpoint is unknown.
+func healDriveKey(drive madmin.HealDriveInfo) string {
+	if drive.Endpoint != "" {
+		return drive.Endpoint
+	}
+	return drive.UUID
+}
+
+// updateDriveStats - aggregate the drive states of a heal result item.
+func (ui *uiData) updateDriveStats(i madmin.HealResultItem) {
+	before := make(map[string]string, len(i.Before.Drives))
+	for _, drive := range i.Before.Drives {
+		before[healDriveKey(drive)] = drive.State
+	}
+	for _, drive := range i.After.Drives {
+		key := healDriveKey(drive)
+		stats, ok := ui.Drives[key]
+		if !ok {
+			stats = &healDriveStats{Endpoint: key}
+			ui.Drives[key] = stats
+		}
+		if stats.State != "" && (stats.State == madmin.DriveStateOffline) != (drive.State == madmin.DriveStateOffline) {
+			stats.OfflineTransitions++
+		}
+		stats.State = drive.State
+		stats.Items++
+
+		switch before[key] {
+		case madmin.DriveStateMissing:
+			stats.Missing++
+		case madmin.DriveStateCorrupt:
+			stats.Corrupted++
+		}
+		if (before[key] == madmin.DriveStateMissing || before[key] == madmin.DriveStateCorrupt) && drive.State == madmin.DriveStateOk {
+			stats.Healed++
+		}
+		if drive.State == madmin.DriveStateOffline {
+			stats.Offline++
+		}
+	}
+}
+
+// sortedDrives - drive statistics sorted by endpoint.
+func (ui *uiData) sortedDrives() []healDriveStats {
+	drives := make([]healDriveStats, 0, len(ui.Drives))
+	for _, stats := range ui.Drives {
+		drives = append(drives, *stats)
+	}
+	sort.Slice(drives, func(i, j int) bool {
+		return drives[i].Endpoint < drives[j].Endpoint
+	})
+	return drives
+}
+
+// driveCol - offline drives are red, drives being healed yellow and
+// healthy drives green.
+func driveCol(d healDriveStats) col {
+	switch {
+	case d.State == madmin.DriveStateOffline:
+		return colRed
+	case d.Missing+d.Corrupted > d.Healed:
+		return colYellow
+	}
+	return colGreen
+}
+
+// printDrivesTable - print the drive statistics as a table, returns the
+// number of printed lines.
+func (ui *uiData) printDrivesTable() int {
+	drives := ui.sortedDrives()
+	rowColors := []*color.Color{color.New(color.Bold)}
+	rows := [][]string{{"Drive", "State", "Items", "Healed", "Missing", "Corrupted", "Offline", "Transitions", "Progress"}}
+	for _, d := range drives {
+		rowColors = append(rowColors, getPrintCol(driveCol(d)))
+		rows = append(rows, []string{
+			d.Endpoint,
+			d.State,
+			humanize.Comma(d.Items),
+			humanize.Comma(d.Healed),
+			humanize.Comma(d.Missing),
+			humanize.Comma(d.Corrupted),
+			humanize.Comma(d.Offline),
+			humanize.Comma(d.OfflineTransitions),
+			fmt.Sprintf("%5.1f%%", d.progress()),
+		})
+	}
+	t := console.NewTable(rowColors, []bool{false, false, true, true, true, true, true, true, true}, 4)
+	t.DisplayTable(rows)
+	// Rows and the borders of the table.
+	return len(rows) + 2
+}
+
+// updateDrivesUI - show the progress of the heal sequence per drive.
+func (ui *uiData) updateDrivesUI(s *madmin.HealTaskStatus) {
+	totalObjects, totalSize, totalTime := ui.getProgress()
+	healedStr := fmt.Sprintf("%s/%s objects; %s in %s",
+		humanize.Comma(ui.ObjectsHealed), totalObjects,
+		totalSize, totalTime)
+
+	console.Print(console.Colorize("HealUpdateUI", fmt.Sprintf(" %s", <-ui.CurChan)))
+	console.PrintC(fmt.Sprintf("  %d drives\n", len(ui.Drives)))
+	console.PrintC(fmt.Sprintf("    %s\n", healedStr))
+	console.PrintC(fmt.Sprintf("    %s\n", ui.getRateAndETAStr()))
+	ui.PrintedLines = 3 + ui.printDrivesTable()
+}
+
+// printDrivesJSON - print the drive statistics as JSON records.
+func (ui *uiData) printDrivesJSON() {
+	type driveRec struct {
+		Status string `json:"status"`
+		Type   string `json:"type"`
+		healDriveStats
+		Progress float64 `json:"progress"`
+	}
+	for _, d := range ui.sortedDrives() {
+		jsonBytes, e := json.MarshalIndent(driveRec{
+			Status:         "success",
+			Type:           "drive",
+			healDriveStats: d,
+			Progress:       d.progress(),
+		}, "", " ")
+		fatalIf(probe.NewError(e), "Unable to marshal to JSON.")
+		console.Println(string(jsonBytes))
+	}
+}
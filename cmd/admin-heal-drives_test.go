@@ -0,0 +1,69 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestUpdateDriveStats(t *testing.T) {
+	drives := func(states ...string) []madmin.HealDriveInfo {
+		var infos []madmin.HealDriveInfo
+		for i, state := range states {
+			infos = append(infos, madmin.HealDriveInfo{Endpoint: []string{"/d1", "/d2", "/d3"}[i], State: state})
+		}
+		return infos
+	}
+	item := func(before, after []madmin.HealDriveInfo) madmin.HealResultItem {
+		var i madmin.HealResultItem
+		i.Before.Drives, i.After.Drives = before, after
+		return i
+	}
+	ok, missing, corrupt, offline := madmin.DriveStateOk, madmin.DriveStateMissing, madmin.DriveStateCorrupt, madmin.DriveStateOffline
+
+	ui := uiData{Drives: make(map[string]*healDriveStats)}
+	for _, i := range []madmin.HealResultItem{
+		item(drives(ok, missing, ok), drives(ok, ok, ok)),
+		item(drives(ok, missing, offline), drives(ok, missing, offline)),
+		item(drives(corrupt, missing, ok), drives(ok, ok, ok)),
+	} {
+		ui.updateDriveStats(i)
+	}
+
+	expected := []healDriveStats{
+		{Endpoint: "/d1", State: ok, Items: 3, Healed: 1, Corrupted: 1},
+		{Endpoint: "/d2", State: ok, Items: 3, Healed: 2, Missing: 3},
+		{Endpoint: "/d3", State: ok, Items: 3, Offline: 1, OfflineTransitions: 2},
+	}
+	if got := ui.sortedDrives(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	progress := []float64{100, 200.0 / 3, 100}
+	cols := []col{colGreen, colYellow, colGreen}
+	for i, d := range expected {
+		if d.progress() != progress[i] {
+			t.Errorf("Test %d: expected progress %v, got %v", i+1, progress[i], d.progress())
+		}
+		if driveCol(d) != cols[i] {
+			t.Errorf("Test %d: expected color %v, got %v", i+1, cols[i], driveCol(d))
+		}
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// healTarget - a heal sequence started one after another to heal the
+// target without its excluded prefixes, of a single object or of all
+// objects below a prefix.
+type healTarget struct {
+	Bucket, Prefix string
+	SingleObject   bool
+}
+
+// healExclusions - prefixes excluded from healing, relative to the
+// alias as BUCKET/PREFIX.
+type healExclusions []string
+
+// newHealExclusions - exclusions relative to the heal target, such as
+// `tmp/` of `myminio/mybucket` excluding `mybucket/tmp/`.
+func newHealExclusions(bucket, prefix string, excludes []string) (healExclusions, *probe.Error) {
+	targetPath := ""
+	if bucket != "" {
+		targetPath = bucket + "/" + prefix
+	}
+	var exclusions healExclusions
+	for _, exclude := range excludes {
+		if exclude == "" || strings.HasPrefix(exclude, "/") {
+			return nil, errInvalidArgument().Trace(exclude)
+		}
+		exclusions = append(exclusions, targetPath+exclude)
+	}
+	return exclusions, nil
+}
+
+// excludes - whether a path is below an excluded prefix.
+func (h healExclusions) excludes(path string) bool {
+	for _, exclusion := range h {
+		if strings.HasPrefix(path, exclusion) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps - whether an excluded prefix is below a path, its objects
+// cannot be healed by one sequence.
+func (h healExclusions) overlaps(path string) bool {
+	for _, exclusion := range h {
+		if strings.HasPrefix(exclusion, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// planHealTargets - the heal sequences covering the objects below the
+// prefix of a bucket, or of all buckets, except the excluded prefixes.
+// Prefixes without exclusions are healed recursively by one sequence,
+// those holding exclusions are listed level by level and their objects
+// healed one by one.
+func planHealTargets(aliasedURL, bucket, prefix string, exclusions healExclusions) ([]healTarget, *probe.Error) {
+	alias, _ := url2Alias(aliasedURL)
+	var targets []healTarget
+	var plan func(bucket, prefix string) *probe.Error
+	plan = func(bucket, prefix string) *probe.Error {
+		path := bucket + "/" + prefix
+		switch {
+		case exclusions.excludes(path):
+			return nil
+		case !exclusions.overlaps(path):
+			targets = append(targets, healTarget{Bucket: bucket, Prefix: prefix})
+			return nil
+		}
+		clnt, err := newClient(alias + "/" + path)
+		if err != nil {
+			return err.Trace(alias, path)
+		}
+		s3Clnt, ok := clnt.(*s3Client)
+		if !ok {
+			return errInvalidTarget(alias + "/" + path).Trace()
+		}
+		for content := range s3Clnt.ListDelimited("/") {
+			if content.Err != nil {
+				return content.Err.Trace(alias, path)
+			}
+			key := strings.TrimPrefix(strings.TrimPrefix(content.URL.Path, "/"), bucket+"/")
+			if content.Type.IsDir() {
+				if err = plan(bucket, key); err != nil {
+					return err
+				}
+			} else if !exclusions.excludes(bucket + "/" + key) {
+				targets = append(targets, healTarget{Bucket: bucket, Prefix: key, SingleObject: true})
+			}
+		}
+		return nil
+	}
+
+	if bucket != "" {
+		return targets, plan(bucket, prefix)
+	}
+	buckets, err := listBucketsFrom(aliasedURL, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		if err = plan(bucket, ""); err != nil {
+			return nil, err
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,103 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHealExclusions(t *testing.T) {
+	exclusions, err := newHealExclusions("bucket", "logs/", []string{"tmp/", "2019"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(exclusions, healExclusions{"bucket/logs/tmp/", "bucket/logs/2019"}) {
+		t.Fatalf("unexpected exclusions %v", exclusions)
+	}
+	if !exclusions.excludes("bucket/logs/2019-10/app.log") || exclusions.excludes("bucket/logs/app.log") {
+		t.Error("expected keys below the prefixes to be excluded only")
+	}
+	if !exclusions.overlaps("bucket/logs/") || exclusions.overlaps("bucket/data/") {
+		t.Error("expected prefixes holding exclusions to overlap only")
+	}
+	if exclusions, _ = newHealExclusions("", "", []string{"bucket/tmp/"}); !reflect.DeepEqual(exclusions, healExclusions{"bucket/tmp/"}) {
+		t.Errorf("expected exclusions relative to the alias, got %v", exclusions)
+	}
+	for _, exclude := range []string{"", "/tmp"} {
+		if _, err = newHealExclusions("bucket", "", []string{exclude}); err == nil {
+			t.Errorf("expected an error for %q", exclude)
+		}
+	}
+}
+
+func TestPlanHealTargets(t *testing.T) {
+	server := httptest.NewServer(keysHandler{
+		keys: []string{
+			"data/a", "data/b", "logs/2019/app.log", "logs/2020/app.log", "logs/app.log",
+			"logs/tmp/x", "tmp/y", "z",
+		},
+		pageSize: 2,
+	})
+	defer server.Close()
+	os.Setenv("MC_HOST_healtest", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+	defer os.Unsetenv("MC_HOST_healtest")
+
+	testCases := []struct {
+		prefix   string
+		excludes []string
+		targets  []healTarget
+	}{
+		{"logs/", []string{"2019/"}, []healTarget{
+			{Bucket: "bucket", Prefix: "logs/2020/"},
+			{Bucket: "bucket", Prefix: "logs/app.log", SingleObject: true},
+			{Bucket: "bucket", Prefix: "logs/tmp/"},
+		}},
+		{"", []string{"tmp/", "logs/tmp/", "logs/2019/"}, []healTarget{
+			{Bucket: "bucket", Prefix: "data/"},
+			{Bucket: "bucket", Prefix: "logs/2020/"},
+			{Bucket: "bucket", Prefix: "logs/app.log", SingleObject: true},
+			{Bucket: "bucket", Prefix: "z", SingleObject: true},
+		}},
+		{"logs/", []string{"2"}, []healTarget{
+			{Bucket: "bucket", Prefix: "logs/app.log", SingleObject: true},
+			{Bucket: "bucket", Prefix: "logs/tmp/"},
+		}},
+		{"logs/", []string{"tmp"}, []healTarget{
+			{Bucket: "bucket", Prefix: "logs/2019/"},
+			{Bucket: "bucket", Prefix: "logs/2020/"},
+			{Bucket: "bucket", Prefix: "logs/app.log", SingleObject: true},
+		}},
+		{"data/", []string{"a", "b"}, nil},
+	}
+	for i, testCase := range testCases {
+		exclusions, err := newHealExclusions("bucket", testCase.prefix, testCase.excludes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targets, err := planHealTargets("healtest/bucket/"+testCase.prefix, "bucket", testCase.prefix, exclusions)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if !reflect.DeepEqual(targets, testCase.targets) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.targets, targets)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Interval between checks of a paused heal, and between fetches of
+	// the load of the servers.
+	healScheduleInterval = 10 * time.Second
+)
+
+// healWindow - a daily window of local time, in time since midnight.
+// Windows ending before they start end on the next day.
+type healWindow struct {
+	start, end time.Duration
+}
+
+// parseHealWindow - parse a 'HH:MM-HH:MM' window.
+func parseHealWindow(window string) (*healWindow, *probe.Error) {
+	parseClock := func(clock string) (time.Duration, bool) {
+		t, e := time.Parse("15:04", strings.TrimSpace(clock))
+		if e != nil {
+			return 0, false
+		}
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+	}
+	clocks := strings.Split(window, "-")
+	if len(clocks) != 2 {
+		return nil, errInvalidArgument().Trace(window)
+	}
+	start, ok := parseClock(clocks[0])
+	if !ok {
+		return nil, errInvalidArgument().Trace(window)
+	}
+	end, ok := parseClock(clocks[1])
+	if !ok || start == end {
+		return nil, errInvalidArgument().Trace(window)
+	}
+	return &healWindow{start: start, end: end}, nil
+}
+
+// contains - whether the local time of t is in the window.
+func (w healWindow) contains(t time.Time) bool {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.start < w.end {
+		return w.start <= clock && clock < w.end
+	}
+	return clock >= w.start || clock < w.end
+}
+
+func (w healWindow) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(w.start) + "-" + clock(w.end)
+}
+
+// healSchedule - when a heal sequence may run, it is paused outside of
+// its window and while the servers are loaded.
+type healSchedule struct {
+	spec string
+
+	window *healWindow
+	// Highest CPU load of the servers in percent, 0 for no limit.
+	maxLoad float64
+
+	// Returns the current CPU load of the servers in percent.
+	serverLoad func() float64
+	load       float64
+	loadTime   time.Time
+}
+
+// parseHealSchedule - parse the comma separated options of --schedule,
+// 'window=HH:MM-HH:MM' and 'max-load=PERCENT'.
+func parseHealSchedule(spec string) (*healSchedule, *probe.Error) {
+	s := &healSchedule{spec: spec}
+	for _, option := range strings.Split(spec, ",") {
+		kv := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(kv) != 2 {
+			return nil, errInvalidArgument().Trace(option)
+		}
+		switch kv[0] {
+		case "window":
+			window, err := parseHealWindow(kv[1])
+			if err != nil {
+				return nil, err.Trace(option)
+			}
+			s.window = window
+		case "max-load":
+			maxLoad, e := strconv.ParseFloat(strings.TrimSuffix(kv[1], "%"), 64)
+			if e != nil || maxLoad <= 0 || maxLoad > 100 {
+				return nil, errInvalidArgument().Trace(option)
+			}
+			s.maxLoad = maxLoad
+		default:
+			return nil, errInvalidArgument().Trace(option)
+		}
+	}
+	return s, nil
+}
+
+// pauseReason - why healing is paused at now, "" when it may run.
+func (s *healSchedule) pauseReason(now time.Time) string {
+	if s.window != nil && !s.window.contains(now.Local()) {
+		return "outside of the heal window " + s.window.String()
+	}
+	if s.maxLoad > 0 && s.serverLoad != nil {
+		if now.Sub(s.loadTime) >= healScheduleInterval {
+			s.load, s.loadTime = s.serverLoad(), now
+		}
+		if s.load > s.maxLoad {
+			return fmt.Sprintf("server CPU load %.1f%% above %.1f%%", s.load, s.maxLoad)
+		}
+	}
+	return ""
+}
+
+// serversCPULoad - the highest current CPU load of the servers in
+// percent, servers not reporting their load are left out.
+func serversCPULoad(client *madmin.AdminClient) float64 {
+	cpuLoads, e := client.ServerCPULoadInfo()
+	if e != nil {
+		return 0
+	}
+	var load float64
+	for _, cpuLoad := range cpuLoads {
+		if cpuLoad.Error != "" {
+			continue
+		}
+		for _, l := range cpuLoad.Load {
+			if l.Error == "" && l.Avg > load {
+				load = l.Avg
+			}
+		}
+	}
+	return load
+}
+
+// healScheduleMessage container for pauses and resumes of a scheduled heal.
+type healScheduleMessage struct {
+	Status string `json:"status"`
+	Event  string `json:"event"`
+	Target string `json:"target"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (h healScheduleMessage) JSON() string {
+	h.Status = "success"
+	healScheduleJSONBytes, e := json.MarshalIndent(h, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(healScheduleJSONBytes)
+}
+
+func (h healScheduleMessage) String() string {
+	if h.Event == "paused" {
+		return console.Colorize("HealSchedule", "Heal of `"+h.Target+"` paused, "+h.Reason+".")
+	}
+	return console.Colorize("HealSchedule", "Heal of `"+h.Target+"` resumed.")
+}
+
+// healTarget - the bucket and prefix of the current heal sequence.
+func (ui *uiData) healTarget() string {
+	if ui.Prefix != "" {
+		return ui.Bucket + "/" + ui.Prefix
+	}
+	return ui.Bucket
+}
+
+// listBucketsFrom - the buckets of the server of aliasedURL sorting at or
+// after a bucket.
+func listBucketsFrom(aliasedURL, from string) ([]string, *probe.Error) {
+	alias, _ := url2Alias(aliasedURL)
+	clnt, err := newClient(alias)
+	if err != nil {
+		return nil, err.Trace(alias)
+	}
+	var buckets []string
+	for content := range clnt.List(globalContext, false, false, DirNone) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(alias)
+		}
+		bucket := strings.Trim(content.URL.Path, string(content.URL.Separator))
+		if bucket >= from {
+			buckets = append(buckets, bucket)
+		}
+	}
+	sort.Strings(buckets)
+	return buckets, nil
+}
+
+// startHeal - start a heal sequence of the current target.
+func (ui *uiData) startHeal() error {
+	opts := *ui.HealOpts
+	if ui.SingleObject {
+		opts.Recursive = false
+	}
+	healStart, _, e := ui.Client.Heal(ui.Bucket, ui.Prefix, opts, "", ui.ForceStart, false)
+	if e != nil {
+		return e
+	}
+	ui.ClientToken = healStart.ClientToken
+	return nil
+}
+
+// startNextTarget - start the next of the heal sequences left.
+func (ui *uiData) startNextTarget() error {
+	ui.PrevDuration = ui.HealDuration
+	ui.setTarget(ui.Pending[0])
+	ui.Pending = ui.Pending[1:]
+	return ui.startHeal()
+}
+
+// setTarget - set the target of the next heal sequence.
+func (ui *uiData) setTarget(target healTarget) {
+	ui.Bucket, ui.Prefix, ui.SingleObject = target.Bucket, target.Prefix, target.SingleObject
+}
+
+// resumeHeal - start the heal sequence again after a pause. Heal
+// sequences cannot be paused on the server, they are stopped and
+// started again. Healing all buckets goes on from the bucket scanned
+// last, healing the following buckets one by one.
+func (ui *uiData) resumeHeal(aliasedURL string) error {
+	if ui.HealAll && ui.LastItem != nil && ui.LastItem.Bucket != "" {
+		buckets, err := listBucketsFrom(aliasedURL, ui.LastItem.Bucket)
+		if err != nil {
+			return err.ToGoError()
+		}
+		if len(buckets) > 0 {
+			ui.Pending = nil
+			for _, bucket := range buckets {
+				ui.Pending = append(ui.Pending, healTarget{Bucket: bucket})
+			}
+			ui.setTarget(ui.Pending[0])
+			ui.Pending = ui.Pending[1:]
+		}
+	}
+	if err := ui.startHeal(); err != nil {
+		return err
+	}
+	if ui.Paused {
+		ui.Paused = false
+		printMsg(healScheduleMessage{Event: "resumed", Target: aliasedURL})
+		ui.PrintedLines = 0
+	}
+	return nil
+}
+
+// followSchedule - pause the heal sequence while the schedule does not
+// allow it to run, and start it once it does.
+func (ui *uiData) followSchedule(aliasedURL string, trapCh <-chan bool) error {
+	reason := ui.Schedule.pauseReason(UTCNow())
+	if reason == "" {
+		if ui.ClientToken == "" {
+			return ui.resumeHeal(aliasedURL)
+		}
+		return nil
+	}
+
+	if ui.ClientToken != "" {
+		if _, _, e := ui.Client.Heal(ui.Bucket, ui.Prefix, *ui.HealOpts, "", false, true); e != nil {
+			return e
+		}
+		ui.ClientToken = ""
+		ui.PrevDuration = ui.HealDuration
+	}
+	ui.Paused = true
+	printMsg(healScheduleMessage{Event: "paused", Target: aliasedURL, Reason: reason})
+	ui.PrintedLines = 0
+	for {
+		select {
+		case <-trapCh:
+			return errors.New("Heal of `" + ui.healTarget() + "` stopped while paused")
+		case <-time.After(healScheduleInterval):
+		}
+		if ui.Schedule.pauseReason(UTCNow()) == "" {
+			return ui.resumeHeal(aliasedURL)
+		}
+	}
+}
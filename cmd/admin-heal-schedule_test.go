@@ -0,0 +1,91 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHealSchedule(t *testing.T) {
+	testCases := []struct {
+		spec    string
+		window  string
+		maxLoad float64
+		success bool
+	}{
+		{"window=22:00-06:00", "22:00-06:00", 0, true},
+		{"max-load=70", "", 70, true},
+		{"window=9:30-17:00, max-load=85.5%", "09:30-17:00", 85.5, true},
+		{"window=22:00", "", 0, false},
+		{"window=10:00-10:00", "", 0, false},
+		{"window=25:00-06:00", "", 0, false},
+		{"max-load=0", "", 0, false},
+		{"max-load=101", "", 0, false},
+		{"paused", "", 0, false},
+		{"start=now", "", 0, false},
+	}
+	for i, testCase := range testCases {
+		s, err := parseHealSchedule(testCase.spec)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+		if err != nil {
+			continue
+		}
+		window := ""
+		if s.window != nil {
+			window = s.window.String()
+		}
+		if window != testCase.window || s.maxLoad != testCase.maxLoad {
+			t.Errorf("Test %d: expected window %q and max load %v, got %q and %v", i+1, testCase.window, testCase.maxLoad, window, s.maxLoad)
+		}
+	}
+}
+
+func TestHealSchedulePauseReason(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2019, 7, 1, hour, minute, 0, 0, time.Local)
+	}
+	testCases := []struct {
+		spec   string
+		load   float64
+		now    time.Time
+		paused bool
+	}{
+		{"window=22:00-06:00", 0, at(23, 0), false},
+		{"window=22:00-06:00", 0, at(2, 0), false},
+		{"window=22:00-06:00", 0, at(6, 0), true},
+		{"window=22:00-06:00", 0, at(12, 0), true},
+		{"window=09:00-17:00", 0, at(9, 0), false},
+		{"window=09:00-17:00", 0, at(17, 30), true},
+		{"max-load=70", 50, at(12, 0), false},
+		{"max-load=70", 90, at(12, 0), true},
+		{"window=22:00-06:00,max-load=70", 90, at(23, 0), true},
+	}
+	for i, testCase := range testCases {
+		s, err := parseHealSchedule(testCase.spec)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		load := testCase.load
+		s.serverLoad = func() float64 { return load }
+		if reason := s.pauseReason(testCase.now); (reason != "") != testCase.paused {
+			t.Errorf("Test %d: expected paused %v, got reason %q", i+1, testCase.paused, reason)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"math"
 	"os"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -77,6 +78,23 @@ type uiData struct {
 
 	// Total time since heal start
 	HealDuration time.Duration
+	// Time spent by the heal sequences before the current one
+	PrevDuration time.Duration
+
+	// Schedule of the heal when not nil, it is paused outside of the
+	// schedule.
+	Schedule *healSchedule
+	Paused   bool
+	// Whether all buckets are healed, and the heal sequences left to
+	// heal one by one after resuming or to skip excluded prefixes.
+	HealAll bool
+	Pending []healTarget
+	// Whether the current heal sequence heals a single object.
+	SingleObject bool
+	// Prefixes excluded from healing as given, relative to the target,
+	// and relative to the alias.
+	Excludes   []string
+	Exclusions healExclusions
 
 	// Accumulated statistics of heal result records
 	BytesScanned int64
@@ -95,6 +113,29 @@ type uiData struct {
 	// health color code.
 	HealthCols map[col]int64
 
+	// Map from drives to their heal statistics, aggregated per drive
+	// instead of per health color when not nil.
+	Drives map[string]*healDriveStats
+
+	// Map from erasure sets or drives, as set by BreakdownBy, to the
+	// number of objects per health color code, when not nil.
+	Breakdown   map[string]map[col]int64
+	BreakdownBy string
+
+	// Lower case health colors of the items shown, all items are
+	// shown when nil.
+	Filter map[string]bool
+
+	// Number of lines printed by the last update of the display.
+	PrintedLines int
+
+	// Objects scanned over the last seconds, for the scan rate.
+	ScanRate *speedHistory
+
+	// Number of objects of the heal target, counted in the background
+	// while healing. Negative until counted.
+	TotalObjects int64
+
 	// channel to receive a prompt string to indicate activity on
 	// the terminal
 	CurChan (<-chan string)
@@ -111,6 +152,9 @@ func (ui *uiData) updateStats(i madmin.HealResultItem) error {
 		ui.ObjectsScanned++
 	}
 	ui.ItemsScanned++
+	if ui.Drives != nil {
+		ui.updateDriveStats(i)
+	}
 
 	beforeUp, afterUp := i.GetOnlineCounts()
 	if afterUp > beforeUp {
@@ -138,11 +182,58 @@ func (ui *uiData) updateStats(i madmin.HealResultItem) error {
 	}
 
 	ui.HealthCols[afterCol]++
+	if ui.Breakdown != nil {
+		ui.updateBreakdown(i, afterCol)
+	}
 	return nil
 }
 
 func (ui *uiData) updateDuration(s *madmin.HealTaskStatus) {
-	ui.HealDuration = UTCNow().Sub(s.StartTime)
+	ui.HealDuration = ui.PrevDuration + UTCNow().Sub(s.StartTime)
+}
+
+// countObjects - count the objects of the heal target in the
+// background, the count is left negative if listing fails.
+func (ui *uiData) countObjects(aliasedURL string) {
+	go func() {
+		clnt, err := newClient(aliasedURL)
+		if err != nil {
+			return
+		}
+		var count int64
+		for content := range clnt.List(globalContext, true, false, DirNone) {
+			if content.Err != nil {
+				return
+			}
+			if !content.Type.IsDir() && !ui.Exclusions.excludes(strings.TrimPrefix(content.URL.Path, "/")) {
+				count++
+			}
+		}
+		atomic.StoreInt64(&ui.TotalObjects, count)
+	}()
+}
+
+// getRateAndETA - objects scanned per second over the last seconds, and
+// the estimated time until all objects are scanned, negative if unknown.
+func (ui *uiData) getRateAndETA() (rate float64, eta time.Duration) {
+	eta = -1
+	rate = ui.ScanRate.speed()
+	total := atomic.LoadInt64(&ui.TotalObjects)
+	if remaining := total - ui.ObjectsScanned; total >= 0 && remaining >= 0 && rate > 0 {
+		eta = time.Duration(float64(remaining) / rate * float64(time.Second)).Round(time.Second)
+	}
+	return rate, eta
+}
+
+// getRateAndETAStr - scan rate and estimated time remaining as shown by
+// the heal status.
+func (ui *uiData) getRateAndETAStr() string {
+	rate, eta := ui.getRateAndETA()
+	etaStr := "--"
+	if eta >= 0 {
+		etaStr = eta.String()
+	}
+	return fmt.Sprintf("ETA: %s  Rate: %s objects/s", etaStr, humanize.Commaf(math.Round(rate*10)/10))
 }
 
 func (ui *uiData) getProgress() (oCount, objSize, duration string) {
@@ -215,6 +306,9 @@ func (ui *uiData) printItemsQuietly(s *madmin.HealTaskStatus) (err error) {
 		if err != nil {
 			return err
 		}
+		if !ui.showItem(strings.ToLower(string(a))) {
+			continue
+		}
 		printColStr(b, a)
 		hrStr := h.getHealResultStr()
 		switch h.Type {
@@ -235,6 +329,15 @@ func (ui *uiData) printStatsQuietly(s *madmin.HealTaskStatus) {
 		totalSize, totalTime)
 
 	console.PrintC(healedStr)
+	console.PrintC(ui.getExcludedStr())
+}
+
+// getExcludedStr - the prefixes excluded from healing, empty without.
+func (ui *uiData) getExcludedStr() string {
+	if len(ui.Excludes) == 0 {
+		return ""
+	}
+	return "Excluded:\t`" + strings.Join(ui.Excludes, "`, `") + "`\n"
 }
 
 func (ui *uiData) printItemsJSON(s *madmin.HealTaskStatus) (err error) {
@@ -295,6 +398,9 @@ func (ui *uiData) printItemsJSON(s *madmin.HealTaskStatus) (err error) {
 		if err != nil {
 			return err
 		}
+		if !ui.showItem(r.After.Color) {
+			continue
+		}
 		jsonBytes, err := json.MarshalIndent(r, "", " ")
 		fatalIf(probe.NewError(err), "Unable to marshal to JSON.")
 		console.Println(string(jsonBytes))
@@ -313,6 +419,14 @@ func (ui *uiData) printStatsJSON(s *madmin.HealTaskStatus) {
 		ItemsHealed    int64  `json:"items_healed"`
 		Size           int64  `json:"size"`
 		ElapsedTime    int64  `json:"duration"`
+		// Average number of objects scanned per second.
+		ObjectsPerSecond float64 `json:"objects_per_second"`
+		// Objects per health color by erasure set or by drive.
+		BreakdownBy string                      `json:"breakdown_by,omitempty"`
+		Breakdown   map[string]map[string]int64 `json:"breakdown,omitempty"`
+
+		// Prefixes excluded from healing.
+		Excluded []string `json:"excluded,omitempty"`
 	}
 
 	summary.Status = "success"
@@ -324,13 +438,55 @@ func (ui *uiData) printStatsJSON(s *madmin.HealTaskStatus) {
 	summary.ItemsHealed = ui.ItemsHealed
 	summary.Size = ui.BytesScanned
 	summary.ElapsedTime = int64(ui.HealDuration.Round(time.Second).Seconds())
+	if seconds := ui.HealDuration.Seconds(); seconds > 0 {
+		summary.ObjectsPerSecond = float64(ui.ObjectsScanned) / seconds
+	}
+	if ui.Breakdown != nil {
+		summary.BreakdownBy = ui.BreakdownBy
+		summary.Breakdown = ui.breakdownJSON()
+	}
+	summary.Excluded = ui.Excludes
 
 	jBytes, err := json.MarshalIndent(summary, "", " ")
 	fatalIf(probe.NewError(err), "Unable to marshal to JSON.")
 	console.Println(string(jBytes))
 }
 
+// printProgressJSON - print the scan rate and the estimated time
+// remaining, in seconds, as a progress record.
+func (ui *uiData) printProgressJSON() {
+	var progress struct {
+		Status           string  `json:"status"`
+		Type             string  `json:"type"`
+		ObjectsScanned   int64   `json:"objects_scanned"`
+		TotalObjects     int64   `json:"total_objects,omitempty"`
+		ObjectsPerSecond float64 `json:"objects_per_second"`
+		ETA              int64   `json:"eta,omitempty"`
+	}
+	progress.Status = "success"
+	progress.Type = "progress"
+	progress.ObjectsScanned = ui.ObjectsScanned
+	if total := atomic.LoadInt64(&ui.TotalObjects); total > 0 {
+		progress.TotalObjects = total
+	}
+	var eta time.Duration
+	progress.ObjectsPerSecond, eta = ui.getRateAndETA()
+	if eta > 0 {
+		progress.ETA = int64(eta.Seconds())
+	}
+
+	jBytes, err := json.MarshalIndent(progress, "", " ")
+	fatalIf(probe.NewError(err), "Unable to marshal to JSON.")
+	console.Println(string(jBytes))
+}
+
 func (ui *uiData) updateUI(s *madmin.HealTaskStatus) (err error) {
+	if ui.Filter != nil {
+		if err = ui.printFilteredItems(s); err != nil {
+			return err
+		}
+	}
+
 	itemCount := len(s.Items)
 	h := ui.LastItem
 	if itemCount > 0 {
@@ -351,6 +507,7 @@ func (ui *uiData) updateUI(s *madmin.HealTaskStatus) (err error) {
 	console.Print(console.Colorize("HealUpdateUI", fmt.Sprintf(" %s", <-ui.CurChan)))
 	console.PrintC(fmt.Sprintf("  %s\n", scannedStr))
 	console.PrintC(fmt.Sprintf("    %s\n", healedStr))
+	console.PrintC(fmt.Sprintf("    %s\n", ui.getRateAndETAStr()))
 
 	dspOrder := []col{colGreen, colYellow, colRed, colGrey}
 	printColors := []*color.Color{}
@@ -370,6 +527,10 @@ func (ui *uiData) updateUI(s *madmin.HealTaskStatus) (err error) {
 	}
 
 	t.DisplayTable(cellText)
+	ui.PrintedLines = 9
+	if ui.Breakdown != nil {
+		ui.PrintedLines += ui.printBreakdownTable()
+	}
 	return nil
 }
 
@@ -379,11 +540,19 @@ func (ui *uiData) UpdateDisplay(s *madmin.HealTaskStatus) (err error) {
 	for _, i := range s.Items {
 		ui.updateStats(i)
 	}
+	ui.ScanRate.add(UTCNow(), ui.ObjectsScanned)
 
-	// Update display
+	// Update display, drive statistics are only printed once finished
+	// in JSON and quiet modes.
 	switch {
+	case ui.Drives != nil && globalJSON:
+		ui.printProgressJSON()
+	case ui.Drives != nil && globalQuiet:
+	case ui.Drives != nil:
+		ui.updateDrivesUI(s)
 	case globalJSON:
 		err = ui.printItemsJSON(s)
+		ui.printProgressJSON()
 	case globalQuiet:
 		err = ui.printItemsQuietly(s)
 	default:
@@ -400,6 +569,27 @@ func (ui *uiData) healResumeMsg(aliasedURL string) string {
 	if ui.HealOpts.DryRun {
 		flags += "--dry-run "
 	}
+	if ui.Drives != nil {
+		flags += "--drives "
+	}
+	if ui.Breakdown != nil {
+		flags += "--breakdown " + ui.BreakdownBy + " "
+	}
+	if ui.Schedule != nil {
+		flags += "--schedule " + ui.Schedule.spec + " "
+	}
+	for _, exclude := range ui.Excludes {
+		flags += "--exclude " + exclude + " "
+	}
+	if ui.Filter != nil {
+		var cols []string
+		for _, c := range healCols {
+			if ui.Filter[strings.ToLower(string(c))] {
+				cols = append(cols, strings.ToLower(string(c)))
+			}
+		}
+		flags += "--filter " + strings.Join(cols, ",") + " "
+	}
 	return fmt.Sprintf("Healing is backgrounded, to resume watching use `mc admin heal %s %s`", flags, aliasedURL)
 }
 
@@ -413,6 +603,11 @@ func (ui *uiData) DisplayAndFollowHealStatus(aliasedURL string) (res madmin.Heal
 		case <-trapCh:
 			return res, errors.New(trapMsg)
 		default:
+			if ui.Schedule != nil {
+				if err = ui.followSchedule(aliasedURL, trapCh); err != nil {
+					return res, err
+				}
+			}
 			_, res, err = ui.Client.Heal(ui.Bucket, ui.Prefix, *ui.HealOpts,
 				ui.ClientToken, ui.ForceStart, false)
 			if err != nil {
@@ -422,7 +617,7 @@ func (ui *uiData) DisplayAndFollowHealStatus(aliasedURL string) (res madmin.Heal
 				firstIter = false
 			} else {
 				if !globalQuiet && !globalJSON {
-					console.RewindLines(8)
+					console.RewindLines(ui.PrintedLines)
 				}
 			}
 			err = ui.UpdateDisplay(&res)
@@ -430,11 +625,28 @@ func (ui *uiData) DisplayAndFollowHealStatus(aliasedURL string) (res madmin.Heal
 				return res, err
 			}
 
+			if res.Summary == "finished" && len(ui.Pending) > 0 {
+				if err = ui.startNextTarget(); err != nil {
+					return res, err
+				}
+				continue
+			}
 			if res.Summary == "finished" {
 				if globalJSON {
 					ui.printStatsJSON(&res)
+					if ui.Drives != nil {
+						ui.printDrivesJSON()
+					}
 				} else if globalQuiet {
 					ui.printStatsQuietly(&res)
+					if ui.Drives != nil {
+						ui.printDrivesTable()
+					}
+					if ui.Breakdown != nil {
+						ui.printBreakdownTable()
+					}
+				} else {
+					console.PrintC(ui.getExcludedStr())
 				}
 				return res, nil
 			}
@@ -0,0 +1,52 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealRateAndETA(t *testing.T) {
+	start := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		total   int64
+		scanned int64
+		rate    float64
+		eta     time.Duration
+		etaStr  string
+	}{
+		{-1, 100, 10, -1, "ETA: --  Rate: 10 objects/s"},
+		{1000, 100, 10, 90 * time.Second, "ETA: 1m30s  Rate: 10 objects/s"},
+		{100, 100, 10, 0, "ETA: 0s  Rate: 10 objects/s"},
+		{50, 100, 10, -1, "ETA: --  Rate: 10 objects/s"},
+		{1000, 0, 0, -1, "ETA: --  Rate: 0 objects/s"},
+	}
+	for i, testCase := range testCases {
+		ui := uiData{ScanRate: &speedHistory{}, TotalObjects: testCase.total}
+		ui.ScanRate.add(start, 0)
+		ui.ScanRate.add(start.Add(10*time.Second), testCase.scanned)
+		ui.ObjectsScanned = testCase.scanned
+		rate, eta := ui.getRateAndETA()
+		if rate != testCase.rate || eta != testCase.eta {
+			t.Errorf("Test %d: expected %v objects/s and %v, got %v and %v", i+1, testCase.rate, testCase.eta, rate, eta)
+		}
+		if etaStr := ui.getRateAndETAStr(); etaStr != testCase.etaStr {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.etaStr, etaStr)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Admin API path of the background heal status of newer servers, which
+// also report the erasure sets of each pool and their healing drives.
+const backgroundHealStatusPath = "/minio/admin/v3/background-heal/status"
+
+// Refresh interval of the dashboard of `mc admin heal --watch`.
+const defaultHealWatchInterval = "2s"
+
+// bgHealDrive - drive of an erasure set as reported by newer servers.
+type bgHealDrive struct {
+	Endpoint string `json:"endpoint"`
+	Healing  bool   `json:"healing"`
+	HealInfo *struct {
+		ObjectsTotalCount uint64 `json:"objects_total_count"`
+		ObjectsHealed     uint64 `json:"objects_healed"`
+		ObjectsFailed     uint64 `json:"objects_failed"`
+		ItemsHealed       uint64 `json:"items_healed"`
+		ItemsFailed       uint64 `json:"items_failed"`
+	} `json:"heal_info"`
+}
+
+// queued - objects left to heal on the drive, servers count them as
+// either objects or items depending on their release.
+func (d bgHealDrive) queued() uint64 {
+	if d.HealInfo == nil {
+		return 0
+	}
+	done := d.HealInfo.ItemsHealed + d.HealInfo.ItemsFailed
+	if objects := d.HealInfo.ObjectsHealed + d.HealInfo.ObjectsFailed; objects > done {
+		done = objects
+	}
+	if done >= d.HealInfo.ObjectsTotalCount {
+		return 0
+	}
+	return d.HealInfo.ObjectsTotalCount - done
+}
+
+// bgHealState - background heal status of the deployment, the sets and
+// the MRF queue, objects to heal after failed writes, are only sent by
+// newer servers.
+type bgHealState struct {
+	ScannedItemsCount int64
+	LastHealActivity  time.Time
+	HealDisks         []string
+	Sets              []struct {
+		PoolIndex int           `json:"pool_index"`
+		SetIndex  int           `json:"set_index"`
+		Disks     []bgHealDrive `json:"disks"`
+	} `json:"sets"`
+	MRF map[string]struct {
+		TotalItems  uint64 `json:"total_items"`
+		ItemsHealed uint64 `json:"items_healed"`
+	} `json:"mrf"`
+}
+
+// healWatchPool - healing state of a pool of a deployment.
+type healWatchPool struct {
+	Pool          int    `json:"pool"`
+	Sets          int    `json:"sets"`
+	Drives        int    `json:"drives"`
+	HealingDrives int    `json:"healingDrives"`
+	Queued        uint64 `json:"queued"`
+}
+
+// healWatchMessage - one refresh of the dashboard of the background heal.
+type healWatchMessage struct {
+	Status        string          `json:"status"`
+	Time          time.Time       `json:"time"`
+	Interval      time.Duration   `json:"-"`
+	ScannedItems  int64           `json:"scannedItems"`
+	ScanRate      float64         `json:"scanRate"`
+	LastActivity  *time.Time      `json:"lastActivity,omitempty"`
+	HealingDrives []string        `json:"healingDrives,omitempty"`
+	MRFQueued     *uint64         `json:"mrfQueued,omitempty"`
+	Pools         []healWatchPool `json:"pools,omitempty"`
+}
+
+// String colorized dashboard of the background heal, without a trailing
+// newline so that its lines can be rewound.
+func (h healWatchMessage) String() string {
+	var b strings.Builder
+	b.WriteString(console.Colorize("HealBackgroundTitle", fmt.Sprintf("Background healing status, refreshed every %s (press Ctrl-C to stop):", h.Interval)))
+	fmt.Fprintf(&b, "\n  %-16s %s", "Objects scanned:",
+		console.Colorize("HealBackground", fmt.Sprintf("%s (%s objects/s)", humanize.Comma(h.ScannedItems), humanize.Comma(int64(h.ScanRate+0.5)))))
+	if h.LastActivity != nil {
+		fmt.Fprintf(&b, "\n  %-16s %s", "Last activity:",
+			console.Colorize("HealBackground", timeDurationToHumanizedDuration(h.Time.Sub(*h.LastActivity)).String()+" ago"))
+	}
+	fmt.Fprintf(&b, "\n  %-16s %s", "Drives healing:", console.Colorize("HealBackground", fmt.Sprint(len(h.HealingDrives))))
+	if h.MRFQueued != nil {
+		fmt.Fprintf(&b, "\n  %-16s %s", "Failed writes:",
+			console.Colorize("HealBackground", humanize.Comma(int64(*h.MRFQueued))+" objects queued"))
+	}
+	if len(h.Pools) == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n  %-6s %6s %8s %8s %12s", "Pool", "Sets", "Drives", "Healing", "Queued")
+	for _, pool := range h.Pools {
+		fmt.Fprintf(&b, "\n  %-6d %6d %8d %8d %12s", pool.Pool+1, pool.Sets, pool.Drives, pool.HealingDrives, humanize.Comma(int64(pool.Queued)))
+	}
+	return b.String()
+}
+
+// JSON jsonified dashboard of the background heal.
+func (h healWatchMessage) JSON() string {
+	h.Status = "success"
+	healWatchJSONBytes, e := json.MarshalIndent(h, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(healWatchJSONBytes)
+}
+
+// parseHealWatchInterval - parse the refresh interval of --watch.
+func parseHealWatchInterval(interval string) (time.Duration, *probe.Error) {
+	duration, e := time.ParseDuration(interval)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(interval)
+	}
+	if duration <= 0 {
+		return 0, errInvalidArgument().Trace(interval)
+	}
+	return duration, nil
+}
+
+// healWatch - state of `mc admin heal --watch` between refreshes.
+type healWatch struct {
+	apiClient *adminAPIClient
+	client    *madmin.AdminClient
+	interval  time.Duration
+
+	// Servers without the newer status API are asked through madmin.
+	legacy bool
+
+	scanRate     *speedHistory
+	printedLines int
+}
+
+// getState - background heal status of the deployment, from the newer
+// status API and, if the server does not have it, from madmin.
+func (w *healWatch) getState() (bgHealState, *probe.Error) {
+	var state bgHealState
+	var err *probe.Error
+	if !w.legacy {
+		if err = w.apiClient.do(http.MethodPost, backgroundHealStatusPath, nil, nil, &state); err == nil {
+			return state, nil
+		}
+	}
+	bgHealStatus, e := w.client.BackgroundHealStatus()
+	if e != nil {
+		if err != nil {
+			// Neither API answered, the newer one is asked again.
+			return state, err
+		}
+		return state, probe.NewError(e)
+	}
+	w.legacy = true
+	state.ScannedItemsCount, state.LastHealActivity = bgHealStatus.ScannedItemsCount, bgHealStatus.LastHealActivity
+	return state, nil
+}
+
+// newHealWatchMessage - summarize a status per pool, the scan rate is the
+// rolling average of the objects scanned over the last refreshes.
+func (w *healWatch) newHealWatchMessage(state bgHealState, now time.Time) healWatchMessage {
+	w.scanRate.add(now, state.ScannedItemsCount)
+	msg := healWatchMessage{
+		Time:          now,
+		Interval:      w.interval,
+		ScannedItems:  state.ScannedItemsCount,
+		ScanRate:      w.scanRate.speed(),
+		HealingDrives: state.HealDisks,
+	}
+	// Newer servers heal while scanning and send no last activity.
+	if !state.LastHealActivity.IsZero() {
+		msg.LastActivity = &state.LastHealActivity
+	}
+	if state.MRF != nil {
+		var queued uint64
+		for _, mrf := range state.MRF {
+			if mrf.TotalItems > mrf.ItemsHealed {
+				queued += mrf.TotalItems - mrf.ItemsHealed
+			}
+		}
+		msg.MRFQueued = &queued
+	}
+
+	pools := map[int]*healWatchPool{}
+	var healing []string
+	for _, set := range state.Sets {
+		pool, ok := pools[set.PoolIndex]
+		if !ok {
+			pool = &healWatchPool{Pool: set.PoolIndex}
+			pools[set.PoolIndex] = pool
+		}
+		pool.Sets++
+		for _, drive := range set.Disks {
+			pool.Drives++
+			if drive.Healing {
+				pool.HealingDrives++
+				healing = append(healing, drive.Endpoint)
+			}
+			pool.Queued += drive.queued()
+		}
+	}
+	for _, pool := range pools {
+		msg.Pools = append(msg.Pools, *pool)
+	}
+	sort.Slice(msg.Pools, func(i, j int) bool { return msg.Pools[i].Pool < msg.Pools[j].Pool })
+	if len(msg.HealingDrives) == 0 {
+		msg.HealingDrives = healing
+	}
+	return msg
+}
+
+// refresh - print the dashboard over the previous one, JSON and quiet
+// output print every refresh below the previous ones.
+func (w *healWatch) refresh(msg healWatchMessage) {
+	if w.printedLines > 0 && !globalJSON && !globalQuiet {
+		console.RewindLines(w.printedLines)
+	}
+	printMsg(msg)
+	w.printedLines = strings.Count(msg.String(), "\n") + 1
+}
+
+// watchBackgroundHeal - refresh the dashboard of the background heal of
+// the deployment of an alias until interrupted. Failed refreshes are
+// reported and watching goes on.
+func watchBackgroundHeal(aliasedURL string, client *madmin.AdminClient, interval time.Duration) *probe.Error {
+	apiClient, err := newAdminAPIClient(aliasedURL)
+	if err != nil {
+		return err.Trace(aliasedURL)
+	}
+	w := &healWatch{
+		apiClient: apiClient,
+		client:    client,
+		interval:  interval,
+		scanRate:  &speedHistory{},
+	}
+
+	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM, syscall.SIGKILL)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		state, err := w.getState()
+		if err != nil {
+			errorIf(err.Trace(aliasedURL), "Failed to get the status of the background heal.")
+			// The error is kept on screen below the last dashboard.
+			w.printedLines = 0
+		} else {
+			w.refresh(w.newHealWatchMessage(state, UTCNow()))
+		}
+		select {
+		case <-trapCh:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
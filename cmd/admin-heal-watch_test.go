@@ -0,0 +1,133 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// bgHealHandler - fake MinIO server answering the background heal status
+// of newer servers, or of older ones only.
+type bgHealHandler struct {
+	legacy bool
+}
+
+func (h bgHealHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "POST" && r.URL.Path == backgroundHealStatusPath && !h.legacy:
+		w.Write([]byte(`{"ScannedItemsCount":1200,"HealDisks":["http://node2/disk1"],"sets":[
+			{"pool_index":0,"set_index":0,"disks":[
+				{"endpoint":"http://node1/disk1"},
+				{"endpoint":"http://node2/disk1","healing":true,"heal_info":{"objects_total_count":100,"items_healed":30,"items_failed":5}}]},
+			{"pool_index":0,"set_index":1,"disks":[{"endpoint":"http://node1/disk2"},{"endpoint":"http://node2/disk2"}]},
+			{"pool_index":1,"set_index":0,"disks":[
+				{"endpoint":"http://node3/disk1","healing":true,"heal_info":{"objects_total_count":50,"objects_healed":60}}]}],
+			"mrf":{"node1":{"total_items":10,"items_healed":4},"node2":{"total_items":3,"items_healed":3}}}`))
+	case r.Method == "POST" && r.URL.Path == "/minio/admin/v1/background-heal/status":
+		w.Write([]byte(`{"ScannedItemsCount":1200,"LastHealActivity":"2019-06-01T10:00:00Z"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"Code":"XMinioAdminAPINotFound","Message":"Admin API not found"}`))
+	}
+}
+
+func TestHealWatch(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		server := httptest.NewServer(bgHealHandler{legacy: legacy})
+		os.Setenv("MC_HOST_healwatch", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+		client, err := newAdminClient("healwatch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		apiClient, err := newAdminAPIClient("healwatch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := &healWatch{apiClient: apiClient, client: client, interval: time.Second, scanRate: &speedHistory{}}
+		state, err := w.getState()
+		if err != nil {
+			t.Fatalf("legacy %v: %s", legacy, err)
+		}
+		if w.legacy != legacy {
+			t.Errorf("expected legacy %v, got %v", legacy, w.legacy)
+		}
+
+		now := time.Date(2019, 6, 1, 10, 0, 30, 0, time.UTC)
+		w.newHealWatchMessage(bgHealState{ScannedItemsCount: 1000}, now.Add(-2*time.Second))
+		msg := w.newHealWatchMessage(state, now)
+		if msg.ScannedItems != 1200 || msg.ScanRate != 100 {
+			t.Errorf("legacy %v: expected 1200 objects scanned at 100/s, got %d at %v/s", legacy, msg.ScannedItems, msg.ScanRate)
+		}
+		if legacy {
+			if msg.LastActivity == nil || now.Sub(*msg.LastActivity) != 30*time.Second || msg.Pools != nil || msg.MRFQueued != nil {
+				t.Errorf("expected the last activity only, got %+v", msg)
+			}
+		} else {
+			pools := []healWatchPool{
+				{Pool: 0, Sets: 2, Drives: 4, HealingDrives: 1, Queued: 65},
+				{Pool: 1, Sets: 1, Drives: 1, HealingDrives: 1, Queued: 0},
+			}
+			if !reflect.DeepEqual(msg.Pools, pools) {
+				t.Errorf("expected pools %+v, got %+v", pools, msg.Pools)
+			}
+			if msg.MRFQueued == nil || *msg.MRFQueued != 6 || msg.LastActivity != nil {
+				t.Errorf("expected 6 objects queued after failed writes and no last activity, got %+v", msg)
+			}
+			if !reflect.DeepEqual(msg.HealingDrives, []string{"http://node2/disk1"}) {
+				t.Errorf("expected the healing drives of the server, got %v", msg.HealingDrives)
+			}
+		}
+		// The dashboard is rewound by its number of lines.
+		lines := 4
+		if !legacy {
+			lines = 7
+		}
+		if printed := strings.Count(msg.String(), "\n") + 1; printed != lines {
+			t.Errorf("legacy %v: expected %d lines, got %d:\n%s", legacy, lines, printed, msg)
+		}
+
+		os.Unsetenv("MC_HOST_healwatch")
+		server.Close()
+	}
+}
+
+func TestParseHealWatchInterval(t *testing.T) {
+	testCases := []struct {
+		interval string
+		duration time.Duration
+		success  bool
+	}{
+		{"2s", 2 * time.Second, true},
+		{"1m30s", 90 * time.Second, true},
+		{"0s", 0, false},
+		{"-5s", 0, false},
+		{"10", 0, false},
+	}
+	for i, testCase := range testCases {
+		duration, err := parseHealWatchInterval(testCase.interval)
+		if (err == nil) != testCase.success || duration != testCase.duration {
+			t.Errorf("Test %d: expected %v and success %v, got %v and %v", i+1, testCase.duration, testCase.success, duration, err)
+		}
+	}
+}
@@ -61,13 +61,43 @@ var adminHealFlags = []cli.Flag{
 		Name:  "remove",
 		Usage: "remove dangling objects in heal sequence",
 	},
+	cli.BoolFlag{
+		Name:  "drives",
+		Usage: "show healed, missing, corrupted and offline items per drive instead of per health color",
+	},
+	cli.StringFlag{
+		Name:  "breakdown",
+		Usage: "break down the health colors of objects by erasure 'set' or by 'drive'",
+	},
+	cli.StringFlag{
+		Name:  "schedule",
+		Usage: "only heal during 'window=HH:MM-HH:MM' of local time and while the server CPU load is under 'max-load=PERCENT', comma separated",
+	},
+	cli.StringFlag{
+		Name:  "filter",
+		Usage: "only show the items of comma separated health colors (green/yellow/red/grey)",
+	},
+	cli.StringSliceFlag{
+		Name:  "exclude",
+		Usage: "skip objects below PREFIX of the target, may be repeated",
+	},
+	cli.BoolFlag{
+		Name:  "watch, w",
+		Usage: "refresh the status of the background heal until interrupted",
+	},
+	cli.StringFlag{
+		Name:  "interval",
+		Usage: "refresh --watch every DURATION, e.g. 10s",
+		Value: defaultHealWatchInterval,
+	},
+	yesFlag,
 }
 
 var adminHealCmd = cli.Command{
 	Name:            "heal",
 	Usage:           "heal disks, buckets and objects on MinIO server",
 	Action:          mainAdminHeal,
-	Before:          setGlobalsFromContext,
+	Before:          setGlobalsFromMutatingContext,
 	Flags:           append(adminHealFlags, globalFlags...),
 	HideHelpCommand: true,
 	CustomHelpTemplate: `NAME:
@@ -108,6 +138,24 @@ EXAMPLES:
 		
     8. Issue a dry-run heal operation to inspect objects health under 'dir' prefix
        $ {{.HelpName}} --dry-run myminio/testbucket/dir/
+
+    9. Heal all objects of 'testbucket' removing dangling objects, without asking for confirmation
+       $ {{.HelpName}} --recursive --remove --yes myminio/testbucket/
+
+   10. Heal all buckets of 'myminio' after replacing a drive, following the rebuild of each drive
+       $ {{.HelpName}} --recursive --drives myminio
+
+   11. Heal all buckets of 'myminio' showing the objects which are not healthy after healing, and their health per erasure set
+       $ {{.HelpName}} --recursive --filter red,grey --breakdown set myminio
+
+   12. Heal all buckets of 'myminio' only at night, pausing while the servers are busier than 70% CPU
+       $ {{.HelpName}} --recursive --schedule window=22:00-06:00,max-load=70 myminio
+
+   13. Heal all objects of 'testbucket' except those below the prefixes 'tmp/' and 'logs/2019/'
+       $ {{.HelpName}} --recursive --exclude tmp/ --exclude logs/2019/ myminio/testbucket/
+
+   14. Monitor the background healing of 'myminio', refreshed every 10 seconds until Ctrl-C
+       $ {{.HelpName}} --watch --interval 10s myminio
 `,
 }
 
@@ -122,6 +170,26 @@ func checkAdminHealSyntax(ctx *cli.Context) {
 	if scanArg != scanNormalMode && scanArg != scanDeepMode {
 		cli.ShowCommandHelpAndExit(ctx, "heal", 1) // last argument is exit code
 	}
+
+	switch ctx.String("breakdown") {
+	case "", healBreakdownSet, healBreakdownDrive:
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("breakdown")), "Invalid breakdown, expected 'set' or 'drive'.")
+	}
+	if ctx.String("breakdown") != "" && ctx.Bool("drives") {
+		fatalIf(errInvalidArgument().Trace(), "--breakdown cannot be used with --drives.")
+	}
+	if len(ctx.StringSlice("exclude")) > 0 && !ctx.Bool("recursive") {
+		fatalIf(errInvalidArgument().Trace(), "--exclude needs --recursive.")
+	}
+	if ctx.Bool("watch") {
+		if ctx.Bool("recursive") || splitStr(filepath.ToSlash(ctx.Args().Get(0)), "/", 3)[1] != "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args().Get(0)), "--watch shows the background heal of a deployment, it cannot be used with a bucket or --recursive.")
+		}
+		if _, err := parseHealWatchInterval(ctx.String("interval")); err != nil {
+			fatalIf(err, "Invalid --interval, expected a positive duration like 10s or 1m.")
+		}
+	}
 }
 
 // stopHealMessage is container for stop heal success and failure messages.
@@ -190,6 +258,7 @@ func mainAdminHeal(ctx *cli.Context) error {
 	console.SetColor("HealBackground", color.New(color.Bold))
 	console.SetColor("HealUpdateUI", color.New(color.FgYellow, color.Bold))
 	console.SetColor("HealStopped", color.New(color.FgGreen, color.Bold))
+	console.SetColor("HealSchedule", color.New(color.FgYellow))
 
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
@@ -206,6 +275,11 @@ func mainAdminHeal(ctx *cli.Context) error {
 	// Return the background heal status when the user
 	// doesn't pass a bucket or --recursive flag.
 	if bucket == "" && !ctx.Bool("recursive") {
+		if ctx.Bool("watch") {
+			interval, _ := parseHealWatchInterval(ctx.String("interval"))
+			fatalIf(watchBackgroundHeal(aliasedURL, client, interval), "Unable to watch the background heal.")
+			return nil
+		}
 		bgHealStatus, berr := client.BackgroundHealStatus()
 		fatalIf(probe.NewError(berr), "Failed to get the status of the background heal.")
 		printMsg(backgroundHealStatusMessage{Status: "success", HealInfo: bgHealStatus})
@@ -228,19 +302,64 @@ func mainAdminHeal(ctx *cli.Context) error {
 		return nil
 	}
 
-	healStart, _, herr := client.Heal(bucket, prefix, opts, "", forceStart, false)
-	fatalIf(probe.NewError(herr), "Failed to start heal sequence.")
+	if opts.Remove && !opts.DryRun && isConfirmationNeeded(ctx) {
+		clnt, err := newClient(aliasedURL)
+		fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+		confirmOrExit(fmt.Sprintf("Heal `%s` and remove dangling objects among %s? This operation is IRREVERSIBLE.",
+			aliasedURL, describeObjectCount(countObjects(clnt, false))))
+	}
 
 	ui := uiData{
 		Bucket:                bucket,
 		Prefix:                prefix,
 		Client:                client,
-		ClientToken:           healStart.ClientToken,
 		ForceStart:            forceStart,
+		HealAll:               bucket == "",
 		HealOpts:              &opts,
 		ObjectsByOnlineDrives: make(map[int]int64),
 		HealthCols:            make(map[col]int64),
 		CurChan:               cursorAnimate(),
+		ScanRate:              &speedHistory{},
+		TotalObjects:          -1,
+	}
+	if excludes := ctx.StringSlice("exclude"); len(excludes) > 0 {
+		exclusions, err := newHealExclusions(bucket, prefix, excludes)
+		fatalIf(err, "Invalid exclusion, expected a prefix relative to the target.")
+		targets, err := planHealTargets(aliasedURL, bucket, prefix, exclusions)
+		fatalIf(err.Trace(aliasedURL), "Unable to list the objects around the excluded prefixes.")
+		if len(targets) == 0 {
+			fatalIf(errInvalidArgument().Trace(excludes...), "Nothing is left to heal, the target is excluded.")
+		}
+		// Excluded prefixes are skipped by healing the rest one by one.
+		ui.Excludes, ui.Exclusions = excludes, exclusions
+		ui.HealAll = false
+		ui.setTarget(targets[0])
+		ui.Pending = targets[1:]
+	}
+	if ctx.Bool("drives") {
+		ui.Drives = make(map[string]*healDriveStats)
+	}
+	if by := ctx.String("breakdown"); by != "" {
+		ui.Breakdown = make(map[string]map[col]int64)
+		ui.BreakdownBy = by
+	}
+	if ctx.IsSet("filter") {
+		filter, err := parseHealFilter(ctx.String("filter"))
+		fatalIf(err, "Invalid filter, expected comma separated health colors among green, yellow, red and grey.")
+		ui.Filter = filter
+	}
+	if spec := ctx.String("schedule"); spec != "" {
+		schedule, err := parseHealSchedule(spec)
+		fatalIf(err, "Invalid schedule, expected comma separated 'window=HH:MM-HH:MM' and 'max-load=PERCENT'.")
+		schedule.serverLoad = func() float64 { return serversCPULoad(client) }
+		ui.Schedule = schedule
+	} else {
+		// Scheduled heals are started once the schedule allows it.
+		fatalIf(probe.NewError(ui.startHeal()), "Failed to start heal sequence.")
+	}
+	if opts.Recursive {
+		// Objects are counted while healing for the ETA.
+		ui.countObjects(aliasedURL)
 	}
 
 	res, e := ui.DisplayAndFollowHealStatus(aliasedURL)
@@ -33,7 +33,9 @@ import (
 )
 
 var (
-	adminInfoFlags = []cli.Flag{}
+	adminInfoFlags = []cli.Flag{
+		formatFlag,
+	}
 )
 
 var adminInfoCmd = cli.Command{
@@ -55,6 +57,9 @@ EXAMPLES:
   1. Get server information of the 'play' MinIO server.
        $ {{.HelpName}} play/
 
+  2. Print the version and uptime of the 'play' MinIO server.
+       $ {{.HelpName}} --format '{{"{{"}}.Addr{{"}}"}} {{"{{"}}.Properties.Version{{"}}"}} {{"{{"}}.Properties.Uptime{{"}}"}}' play/
+
 `,
 }
 
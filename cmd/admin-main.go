@@ -40,6 +40,8 @@ var adminCmd = cli.Command{
 		adminTopCmd,
 		adminMonitorCmd,
 		adminTraceCmd,
+		adminBucketCmd,
+		adminReplicateCmd,
 	},
 }
 
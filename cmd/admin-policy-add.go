@@ -30,7 +30,7 @@ var adminPolicyAddCmd = cli.Command{
 	Name:   "add",
 	Usage:  "add new policy",
 	Action: mainAdminPolicyAdd,
-	Before: setGlobalsFromContext,
+	Before: setGlobalsFromMutatingContext,
 	Flags:  globalFlags,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -0,0 +1,154 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var adminReplicateAddCmd = cli.Command{
+	Name:   "add",
+	Usage:  "replicate buckets, IAM and configuration between deployments",
+	Action: mainAdminReplicateAdd,
+	Before: setGlobalsFromMutatingContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS1 ALIAS2 [ALIAS3...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Each ALIAS is a MinIO deployment, named as the alias in the replication,
+  with the endpoint and the admin credentials of its alias. The request is
+  sent to ALIAS1, which configures the other deployments, all but ALIAS1
+  must not have buckets yet. Needs a server with site replication.
+
+EXAMPLES:
+  1. Replicate the deployments 'minio1' and 'minio2'.
+     $ {{.HelpName}} minio1 minio2
+
+  2. Replicate three deployments.
+     $ {{.HelpName}} minio1 minio2 minio3
+`,
+}
+
+// adminReplicateAddMessage - the sites replicated.
+type adminReplicateAddMessage struct {
+	Status           string   `json:"status"`
+	Sites            []string `json:"sites"`
+	Message          string   `json:"message,omitempty"`
+	InitialSyncError string   `json:"initialSyncError,omitempty"`
+}
+
+// String colorized replicate add message.
+func (m adminReplicateAddMessage) String() string {
+	msg := console.Colorize("SiteReplicated", fmt.Sprintf("Sites %s are replicated.", strings.Join(m.Sites, ", ")))
+	if m.Message != "" {
+		msg += " " + m.Message
+	}
+	if m.InitialSyncError != "" {
+		msg += "\n" + console.Colorize("SiteSyncError", "Initial sync failed: "+m.InitialSyncError)
+	}
+	return msg
+}
+
+// JSON jsonified replicate add message.
+func (m adminReplicateAddMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// checkAdminReplicateAddSyntax - validate all the passed arguments.
+func checkAdminReplicateAddSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "add", 1) // last argument is exit code
+	}
+}
+
+// getSitePeers - the deployments of aliases, by alias name.
+func getSitePeers(aliases []string) ([]sitePeer, *probe.Error) {
+	peers := make([]sitePeer, 0, len(aliases))
+	seen := map[string]bool{}
+	for _, alias := range aliases {
+		alias = strings.TrimSuffix(alias, "/")
+		if seen[alias] {
+			return nil, errInvalidArgument().Trace(alias)
+		}
+		seen[alias] = true
+		hostCfg := mustGetHostConfig(alias)
+		if hostCfg == nil {
+			return nil, errInvalidAliasedURL(alias).Trace(alias)
+		}
+		if u, e := url.Parse(hostCfg.URL); e != nil || strings.Trim(u.Path, "/") != "" {
+			return nil, errInvalidArgument().Trace(alias, hostCfg.URL)
+		}
+		peers = append(peers, sitePeer{
+			Name:      alias,
+			Endpoint:  hostCfg.URL,
+			AccessKey: hostCfg.AccessKey,
+			SecretKey: hostCfg.SecretKey,
+		})
+	}
+	return peers, nil
+}
+
+// mainAdminReplicateAdd is the handle for "mc admin replicate add" command.
+func mainAdminReplicateAdd(ctx *cli.Context) error {
+	checkAdminReplicateAddSyntax(ctx)
+
+	console.SetColor("SiteReplicated", color.New(color.FgGreen))
+	console.SetColor("SiteSyncError", color.New(color.FgYellow))
+
+	args := ctx.Args()
+	peers, err := getSitePeers(args)
+	fatalIf(err.Trace(args...), "Each site must be a different alias of a deployment, without a bucket.")
+
+	clnt, err := newAdminAPIClient(args.Get(0))
+	fatalIf(err.Trace(args.Get(0)), "Unable to initialize admin connection.")
+
+	status, err := addSiteReplication(clnt, peers)
+	fatalIf(err.Trace(args...), "Unable to replicate the sites.")
+	if !status.Success {
+		fatalIf(probe.NewError(fmt.Errorf("%s %s", status.Status, status.ErrDetail)).Trace(args...), "Unable to replicate the sites.")
+	}
+
+	sites := make([]string, len(peers))
+	for i, peer := range peers {
+		sites[i] = peer.Name
+	}
+	printMsg(adminReplicateAddMessage{
+		Sites:            sites,
+		Message:          status.Status,
+		InitialSyncError: status.InitialSyncError,
+	})
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var adminReplicateRemoveFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "all",
+		Usage: "end the replication of all sites",
+	},
+	cli.BoolFlag{
+		Name:  "force",
+		Usage: "allow removing sites, which stop replicating with the other sites",
+	},
+}
+
+var adminReplicateRemoveCmd = cli.Command{
+	Name:   "remove",
+	Usage:  "remove sites from the site replication",
+	Action: mainAdminReplicateRemove,
+	Before: setGlobalsFromMutatingContext,
+	Flags:  append(adminReplicateRemoveFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --force ALIAS SITE [SITE...]
+  {{.HelpName}} --force --all ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  SITE is the name of a site shown by 'mc admin replicate status'. Removed
+  sites keep their buckets, IAM and configuration, but stop replicating with
+  the other sites.
+
+EXAMPLES:
+  1. Remove the site 'minio3' from the replication of 'minio1'.
+     $ {{.HelpName}} --force minio1 minio3
+
+  2. End the replication of all sites of 'minio1'.
+     $ {{.HelpName}} --force --all minio1
+`,
+}
+
+// adminReplicateRemoveMessage - the sites removed.
+type adminReplicateRemoveMessage struct {
+	Status  string   `json:"status"`
+	Alias   string   `json:"alias"`
+	Sites   []string `json:"sites,omitempty"`
+	All     bool     `json:"all,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// String colorized replicate remove message.
+func (m adminReplicateRemoveMessage) String() string {
+	if m.All {
+		return console.Colorize("SiteRemoved", fmt.Sprintf("Site replication of `%s` is removed from all sites.", m.Alias))
+	}
+	if len(m.Sites) == 1 {
+		return console.Colorize("SiteRemoved", fmt.Sprintf("Site %s is removed from the replication of `%s`.", m.Sites[0], m.Alias))
+	}
+	return console.Colorize("SiteRemoved", fmt.Sprintf("Sites %s are removed from the replication of `%s`.", strings.Join(m.Sites, ", "), m.Alias))
+}
+
+// JSON jsonified replicate remove message.
+func (m adminReplicateRemoveMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// checkAdminReplicateRemoveSyntax - validate all the passed arguments.
+func checkAdminReplicateRemoveSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) == 0 || (ctx.Bool("all") == (len(args) > 1)) {
+		cli.ShowCommandHelpAndExit(ctx, "remove", 1) // last argument is exit code
+	}
+	if !ctx.Bool("force") {
+		fatalIf(errDummy().Trace(args...), "Removing sites stops their replication, retry with `--force` to remove them.")
+	}
+}
+
+// mainAdminReplicateRemove is the handle for "mc admin replicate remove" command.
+func mainAdminReplicateRemove(ctx *cli.Context) error {
+	checkAdminReplicateRemoveSyntax(ctx)
+
+	console.SetColor("SiteRemoved", color.New(color.FgGreen))
+
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+	clnt, err := newAdminAPIClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize admin connection.")
+
+	sites, all := args.Tail(), ctx.Bool("all")
+	status, err := removeSiteReplication(clnt, sites, all)
+	fatalIf(err.Trace(args...), "Unable to remove the sites.")
+	if status.ErrDetail != "" {
+		fatalIf(probe.NewError(fmt.Errorf("%s %s", status.Status, status.ErrDetail)).Trace(args...), "Unable to remove the sites.")
+	}
+
+	printMsg(adminReplicateRemoveMessage{
+		Alias:   clnt.alias,
+		Sites:   sites,
+		All:     all,
+		Message: status.Status,
+	})
+	return nil
+}
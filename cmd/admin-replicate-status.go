@@ -0,0 +1,188 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var adminReplicateStatusCmd = cli.Command{
+	Name:   "status",
+	Usage:  "show the replication state and lag of every site",
+	Action: mainAdminReplicateStatus,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Every site of the replication of ALIAS is shown with the buckets, policies,
+  users and groups it has in sync with the other sites. Sites ALIAS replicates
+  objects to are also shown online or offline, with the latency of replicating
+  an object and the objects replicated. Servers without replication metrics
+  show no latency.
+
+EXAMPLES:
+  1. Show the sites 'minio1' replicates with.
+     $ {{.HelpName}} minio1
+`,
+}
+
+// adminReplicateSiteStatus - state of a site, as seen by the deployment.
+type adminReplicateSiteStatus struct {
+	Name         string                 `json:"name"`
+	Endpoint     string                 `json:"endpoint"`
+	DeploymentID string                 `json:"deploymentID"`
+	Summary      siteReplicationSummary `json:"summary"`
+	Metrics      *siteReplicationMetric `json:"metrics,omitempty"`
+}
+
+// adminReplicateStatusMessage - state of the sites of the replication.
+type adminReplicateStatusMessage struct {
+	Status  string                     `json:"status"`
+	Alias   string                     `json:"alias"`
+	Enabled bool                       `json:"enabled"`
+	Sites   []adminReplicateSiteStatus `json:"sites,omitempty"`
+}
+
+// formatLatency - a latency rounded for display.
+func formatLatency(d time.Duration) string {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
+
+// String colorized replicate status message.
+func (m adminReplicateStatusMessage) String() string {
+	if !m.Enabled {
+		return console.Colorize("SiteOffline", fmt.Sprintf("Site replication is not enabled on `%s`.", m.Alias))
+	}
+	var b strings.Builder
+	for i, site := range m.Sites {
+		if i > 0 {
+			fmt.Fprintln(&b)
+		}
+		fmt.Fprintln(&b, console.Colorize("SiteName", fmt.Sprintf("%-11s: %s", "Site", site.Name)))
+		fmt.Fprintf(&b, "%-11s: %s\n", "Endpoint", site.Endpoint)
+		fmt.Fprintf(&b, "%-11s: %s\n", "Deployment", site.DeploymentID)
+		if metrics := site.Metrics; metrics != nil {
+			state := console.Colorize("SiteOnline", "online")
+			if !metrics.Online {
+				state = console.Colorize("SiteOffline", "offline")
+				if !metrics.LastOnline.IsZero() {
+					state += console.Colorize("SiteOffline", ", last online "+formatDate(metrics.LastOnline))
+				}
+			}
+			if metrics.TotalDowntime > 0 {
+				state += fmt.Sprintf(", down for %s in total", metrics.TotalDowntime.Round(time.Second))
+			}
+			fmt.Fprintf(&b, "%-11s: %s\n", "State", state)
+			fmt.Fprintf(&b, "%-11s: %s current, %s average, %s maximum\n", "Lag", formatLatency(metrics.Latency.Curr),
+				formatLatency(metrics.Latency.Avg), formatLatency(metrics.Latency.Max))
+			fmt.Fprintf(&b, "%-11s: %d objects, %s\n", "Replicated", metrics.ReplicatedCount, formatSize(metrics.ReplicatedSize))
+		}
+		summary := site.Summary
+		for _, entity := range []struct {
+			name              string
+			replicated, total int
+		}{
+			{"Buckets", summary.ReplicatedBuckets, summary.TotalBucketsCount},
+			{"Policies", summary.ReplicatedIAMPolicies, summary.TotalIAMPoliciesCount},
+			{"Users", summary.ReplicatedUsers, summary.TotalUsersCount},
+			{"Groups", summary.ReplicatedGroups, summary.TotalGroupsCount},
+		} {
+			count := fmt.Sprintf("%d/%d in sync", entity.replicated, entity.total)
+			if entity.replicated < entity.total {
+				count = console.Colorize("SiteOffline", count)
+			}
+			fmt.Fprintf(&b, "%-11s: %s\n", entity.name, count)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON jsonified replicate status message.
+func (m adminReplicateStatusMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// newAdminReplicateStatusMessage - the sites of a status, by name.
+func newAdminReplicateStatusMessage(alias string, status siteReplicationStatus) adminReplicateStatusMessage {
+	msg := adminReplicateStatusMessage{Alias: alias, Enabled: status.Enabled}
+	for id, peer := range status.Sites {
+		site := adminReplicateSiteStatus{
+			Name:         peer.Name,
+			Endpoint:     peer.Endpoint,
+			DeploymentID: id,
+			Summary:      status.StatsSummary[id],
+		}
+		if metrics, ok := status.Metrics.Metrics[id]; ok {
+			site.Metrics = &metrics
+		}
+		msg.Sites = append(msg.Sites, site)
+	}
+	sort.Slice(msg.Sites, func(i, j int) bool {
+		return msg.Sites[i].Name < msg.Sites[j].Name
+	})
+	return msg
+}
+
+// checkAdminReplicateStatusSyntax - validate all the passed arguments.
+func checkAdminReplicateStatusSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "status", 1) // last argument is exit code
+	}
+}
+
+// mainAdminReplicateStatus is the handle for "mc admin replicate status" command.
+func mainAdminReplicateStatus(ctx *cli.Context) error {
+	checkAdminReplicateStatusSyntax(ctx)
+
+	console.SetColor("SiteName", color.New(color.Bold))
+	console.SetColor("SiteOnline", color.New(color.FgGreen))
+	console.SetColor("SiteOffline", color.New(color.FgYellow))
+
+	aliasedURL := ctx.Args().Get(0)
+	clnt, err := newAdminAPIClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize admin connection.")
+
+	status, err := getSiteReplicationStatus(clnt)
+	fatalIf(err.Trace(aliasedURL), "Unable to get the site replication status.")
+
+	printMsg(newAdminReplicateStatusMessage(clnt.alias, status))
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/secure-io/sio-go"
+	"golang.org/x/crypto/argon2"
+)
+
+// Admin API path of the site replication of a deployment.
+const siteReplicationPath = "/minio/admin/v3/site-replication/"
+
+var adminReplicateCmd = cli.Command{
+	Name:   "replicate",
+	Usage:  "manage site replication between MinIO deployments",
+	Action: mainAdminReplicate,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	Subcommands: []cli.Command{
+		adminReplicateAddCmd,
+		adminReplicateStatusCmd,
+		adminReplicateRemoveCmd,
+	},
+	HideHelpCommand: true,
+}
+
+// mainAdminReplicate is the handle for "mc admin replicate" command.
+func mainAdminReplicate(ctx *cli.Context) error {
+	cli.ShowCommandHelp(ctx, ctx.Args().First())
+	return nil
+	// Sub-commands like "add", "status" have their own main.
+}
+
+const (
+	// Parameters of the argon2id key the site replication API
+	// decrypts request bodies with, derived from the secret key.
+	argon2idTime    = 1
+	argon2idMemory  = 64 * 1024
+	argon2idThreads = 4
+
+	// Identifier of AES-256-GCM with an argon2id key.
+	argon2idAESGCM = 0x00
+)
+
+// encryptAdminData - encrypt data for the admin API with a key derived
+// from the secret key, in the format of madmin.EncryptData of servers
+// with site replication: salt, algorithm, nonce and sealed data.
+func encryptAdminData(secretKey string, data []byte) ([]byte, *probe.Error) {
+	salt := make([]byte, 32)
+	if _, e := io.ReadFull(rand.Reader, salt); e != nil {
+		return nil, probe.NewError(e)
+	}
+	key := argon2.IDKey([]byte(secretKey), salt, argon2idTime, argon2idMemory, argon2idThreads, 32)
+	stream, e := sio.AES_256_GCM.Stream(key)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	nonce := make([]byte, stream.NonceSize())
+	if _, e = io.ReadFull(rand.Reader, nonce); e != nil {
+		return nil, probe.NewError(e)
+	}
+
+	var b bytes.Buffer
+	b.Write(salt)
+	b.WriteByte(argon2idAESGCM)
+	b.Write(nonce)
+	w := stream.EncryptWriter(&b, nonce, nil)
+	if _, e = w.Write(data); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if e = w.Close(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return b.Bytes(), nil
+}
+
+// sitePeer - a deployment to replicate, as sent to the server.
+type sitePeer struct {
+	Name      string `json:"name"`
+	Endpoint  string `json:"endpoints"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// siteReplicationAddStatus - answer of the server to replicate sites.
+type siteReplicationAddStatus struct {
+	Success          bool   `json:"success"`
+	Status           string `json:"status,omitempty"`
+	ErrDetail        string `json:"errorDetail,omitempty"`
+	InitialSyncError string `json:"initialSyncErrorMessage,omitempty"`
+}
+
+// addSiteReplication - replicate the peers, the credentials of the peers
+// are encrypted with the secret key of the client.
+func addSiteReplication(clnt *adminAPIClient, peers []sitePeer) (siteReplicationAddStatus, *probe.Error) {
+	var status siteReplicationAddStatus
+	data, e := json.Marshal(peers)
+	if e != nil {
+		return status, probe.NewError(e)
+	}
+	body, err := encryptAdminData(clnt.secretKey, data)
+	if err != nil {
+		return status, err.Trace(clnt.alias)
+	}
+	query := url.Values{}
+	query.Set("api-version", "1")
+	if err = clnt.do(http.MethodPut, siteReplicationPath+"add", query, body, &status); err != nil {
+		return status, err.Trace(clnt.alias)
+	}
+	return status, nil
+}
+
+// sitePeerInfo - a replicated deployment.
+type sitePeerInfo struct {
+	Name         string `json:"name"`
+	Endpoint     string `json:"endpoint"`
+	DeploymentID string `json:"deploymentID"`
+}
+
+// siteReplicationSummary - entities of the deployments a site has in sync.
+type siteReplicationSummary struct {
+	ReplicatedBuckets     int `json:"replicatedBuckets"`
+	ReplicatedIAMPolicies int `json:"replicatedIAMPolicies"`
+	ReplicatedUsers       int `json:"replicatedUsers"`
+	ReplicatedGroups      int `json:"replicatedGroups"`
+	TotalBucketsCount     int `json:"totalBucketsCount"`
+	TotalIAMPoliciesCount int `json:"totalIAMPoliciesCount"`
+	TotalUsersCount       int `json:"totalUsersCount"`
+	TotalGroupsCount      int `json:"totalGroupsCount"`
+}
+
+// siteReplicationLatency - latency of replicating to a site.
+type siteReplicationLatency struct {
+	Curr time.Duration `json:"curr"`
+	Avg  time.Duration `json:"avg"`
+	Max  time.Duration `json:"max"`
+}
+
+// siteReplicationMetric - replication of objects to a site.
+type siteReplicationMetric struct {
+	Endpoint        string                 `json:"endpoint"`
+	Online          bool                   `json:"isOnline"`
+	LastOnline      time.Time              `json:"lastOnline"`
+	TotalDowntime   time.Duration          `json:"totalDowntime"`
+	Latency         siteReplicationLatency `json:"latency"`
+	ReplicatedSize  int64                  `json:"replicatedSize"`
+	ReplicatedCount int64                  `json:"replicatedCount"`
+}
+
+// siteReplicationStatus - status of the site replication of a deployment,
+// sites, summaries and metrics are by deployment ID. Servers send the
+// status with the Go names of its fields, matched regardless of case,
+// servers without replication metrics send no metrics.
+type siteReplicationStatus struct {
+	Enabled      bool                              `json:"enabled"`
+	Sites        map[string]sitePeerInfo           `json:"sites"`
+	StatsSummary map[string]siteReplicationSummary `json:"statsSummary"`
+	Metrics      struct {
+		Metrics map[string]siteReplicationMetric `json:"replMetrics"`
+	} `json:"metrics"`
+}
+
+// getSiteReplicationStatus - status of the site replication of the
+// deployment and of its peers.
+func getSiteReplicationStatus(clnt *adminAPIClient) (siteReplicationStatus, *probe.Error) {
+	var status siteReplicationStatus
+	query := url.Values{}
+	for _, entity := range []string{"buckets", "policies", "users", "groups", "metrics"} {
+		query.Set(entity, "true")
+	}
+	if err := clnt.do(http.MethodGet, siteReplicationPath+"status", query, nil, &status); err != nil {
+		return status, err.Trace(clnt.alias)
+	}
+	return status, nil
+}
+
+// siteReplicationRemoveStatus - answer of the server to remove sites.
+type siteReplicationRemoveStatus struct {
+	Status    string `json:"status"`
+	ErrDetail string `json:"errorDetail,omitempty"`
+}
+
+// removeSiteReplication - remove sites by name from the replication, or
+// end the replication of all sites.
+func removeSiteReplication(clnt *adminAPIClient, sites []string, all bool) (siteReplicationRemoveStatus, *probe.Error) {
+	var status siteReplicationRemoveStatus
+	data, e := json.Marshal(struct {
+		SiteNames []string `json:"sites"`
+		RemoveAll bool     `json:"all"`
+	}{sites, all})
+	if e != nil {
+		return status, probe.NewError(e)
+	}
+	query := url.Values{}
+	query.Set("api-version", "1")
+	if err := clnt.do(http.MethodPut, siteReplicationPath+"remove", query, data, &status); err != nil {
+		return status, err.Trace(clnt.alias)
+	}
+	return status, nil
+}
@@ -0,0 +1,187 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/secure-io/sio-go"
+	"golang.org/x/crypto/argon2"
+)
+
+// siteReplicationHandler - fake site replication admin API.
+type siteReplicationHandler struct {
+	secretKey string
+	peers     []sitePeer
+	removed   []string
+}
+
+// decryptAdminData - decrypt data of encryptAdminData, as servers do.
+func decryptAdminData(secretKey string, data []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	salt, id, nonce := make([]byte, 32), make([]byte, 1), make([]byte, 8)
+	for _, b := range [][]byte{salt, id, nonce} {
+		if _, e := io.ReadFull(r, b); e != nil {
+			return nil, e
+		}
+	}
+	if id[0] != argon2idAESGCM {
+		return nil, errInvalidArgument().ToGoError()
+	}
+	key := argon2.IDKey([]byte(secretKey), salt, argon2idTime, argon2idMemory, argon2idThreads, 32)
+	stream, e := sio.AES_256_GCM.Stream(key)
+	if e != nil {
+		return nil, e
+	}
+	return ioutil.ReadAll(stream.DecryptReader(r, nonce, nil))
+}
+
+func (h *siteReplicationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Authorization"), "Credential=minio/") {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"Code":"AccessDenied","Message":"Access Denied."}`))
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	switch r.Method + " " + strings.TrimPrefix(r.URL.Path, siteReplicationPath) {
+	case "PUT add":
+		data, e := decryptAdminData(h.secretKey, body)
+		if e == nil {
+			e = json.Unmarshal(data, &h.peers)
+		}
+		if e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"success":true,"status":"Requested sites were configured for replication successfully."}`))
+	case "GET status":
+		w.Write([]byte(`{"Enabled":true,"MaxBuckets":2,
+		"Sites":{"d2":{"name":"site2","endpoint":"https://site2","deploymentID":"d2"},"d1":{"name":"site1","endpoint":"https://site1","deploymentID":"d1"}},
+		"StatsSummary":{"d1":{"ReplicatedBuckets":2,"TotalBucketsCount":2,"ReplicatedIAMPolicies":1,"TotalIAMPoliciesCount":1},"d2":{"ReplicatedBuckets":1,"TotalBucketsCount":2}},
+		"Metrics":{"replMetrics":{"d2":{"endpoint":"https://site2","isOnline":true,"latency":{"curr":2000000,"avg":1500000,"max":9000000},"replicatedCount":3}}}}`))
+	case "PUT remove":
+		var req struct {
+			Sites []string `json:"sites"`
+		}
+		json.Unmarshal(body, &req)
+		h.removed = req.Sites
+		w.Write([]byte(`{"status":"success"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSiteReplication(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		return newConfigV9(), nil
+	}
+
+	handler := &siteReplicationHandler{secretKey: "minio123"}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	for alias, credentials := range map[string]string{"site1": "minio:minio123", "site2": "minio:secret2", "site3": "other:secret3"} {
+		os.Setenv("MC_HOST_"+alias, strings.Replace(server.URL, "http://", "http://"+credentials+"@", 1))
+		defer os.Unsetenv("MC_HOST_" + alias)
+	}
+	os.Setenv("MC_HOST_sitepath", server.URL+"/path")
+	defer os.Unsetenv("MC_HOST_sitepath")
+
+	for _, aliases := range [][]string{{"site1", "site1/"}, {"site1", "sitepath"}, {"site1", "nosuchsite"}} {
+		if _, err := getSitePeers(aliases); err == nil {
+			t.Errorf("Expected sites %v to fail", aliases)
+		}
+	}
+	peers, err := getSitePeers([]string{"site1", "site2/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = newAdminAPIClient("site1/bucket"); err == nil {
+		t.Error("Expected a bucket to fail")
+	}
+	clnt, err := newAdminAPIClient("site1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := addSiteReplication(clnt, peers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Success {
+		t.Errorf("Expected success, got %+v", status)
+	}
+	expectedPeers := []sitePeer{
+		{Name: "site1", Endpoint: server.URL, AccessKey: "minio", SecretKey: "minio123"},
+		{Name: "site2", Endpoint: server.URL, AccessKey: "minio", SecretKey: "secret2"},
+	}
+	if !reflect.DeepEqual(handler.peers, expectedPeers) {
+		t.Errorf("Expected peers %+v, got %+v", expectedPeers, handler.peers)
+	}
+
+	replStatus, err := getSiteReplicationStatus(clnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := newAdminReplicateStatusMessage("site1", replStatus)
+	if len(msg.Sites) != 2 || msg.Sites[0].Name != "site1" || msg.Sites[1].Name != "site2" {
+		t.Fatalf("Expected sites site1 and site2, got %+v", msg.Sites)
+	}
+	if msg.Sites[0].Metrics != nil || msg.Sites[1].Metrics == nil {
+		t.Fatalf("Expected metrics of site2 only, got %+v", msg.Sites)
+	}
+	if latency := msg.Sites[1].Metrics.Latency.Avg; latency != 1500*time.Microsecond {
+		t.Errorf("Expected a latency of 1.5ms, got %s", latency)
+	}
+	if summary := msg.Sites[1].Summary; summary.ReplicatedBuckets != 1 || summary.TotalBucketsCount != 2 {
+		t.Errorf("Expected 1/2 buckets, got %+v", summary)
+	}
+	if summary := msg.Sites[0].Summary; summary.ReplicatedIAMPolicies != 1 || summary.TotalIAMPoliciesCount != 1 {
+		t.Errorf("Expected 1/1 policies, got %+v", summary)
+	}
+	if str := msg.String(); !strings.Contains(str, "1.5ms average") || !strings.Contains(str, "1/2 in sync") {
+		t.Errorf("Unexpected status %q", str)
+	}
+
+	if _, err = removeSiteReplication(clnt, []string{"site2"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(handler.removed, []string{"site2"}) {
+		t.Errorf("Expected site2 removed, got %v", handler.removed)
+	}
+
+	clnt, err = newAdminAPIClient("site3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = getSiteReplicationStatus(clnt)
+	if err == nil || !strings.Contains(err.ToGoError().Error(), "Access Denied.") {
+		t.Errorf("Expected access denied, got %v", err)
+	}
+}
@@ -31,7 +31,7 @@ var adminServiceRestartCmd = cli.Command{
 	Name:   "restart",
 	Usage:  "restart MinIO server",
 	Action: mainAdminServiceRestart,
-	Before: setGlobalsFromContext,
+	Before: setGlobalsFromMutatingContext,
 	Flags:  globalFlags,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -29,7 +29,7 @@ var adminServiceStopCmd = cli.Command{
 	Name:   "stop",
 	Usage:  "stop MinIO server",
 	Action: mainAdminServiceStop,
-	Before: setGlobalsFromContext,
+	Before: setGlobalsFromMutatingContext,
 	Flags:  globalFlags,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -27,7 +27,7 @@ var adminUserPolicyCmd = cli.Command{
 	Name:   "set-policy",
 	Usage:  "set policy for user",
 	Action: mainAdminUserPolicy,
-	Before: setGlobalsFromContext,
+	Before: setGlobalsFromMutatingContext,
 	Flags:  globalFlags,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
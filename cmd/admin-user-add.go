@@ -28,7 +28,7 @@ var adminUserAddCmd = cli.Command{
 	Name:   "add",
 	Usage:  "add a new user",
 	Action: mainAdminUserAdd,
-	Before: setGlobalsFromContext,
+	Before: setGlobalsFromMutatingContext,
 	Flags:  globalFlags,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -27,7 +27,7 @@ var adminUserRemoveCmd = cli.Command{
 	Name:   "remove",
 	Usage:  "remove user",
 	Action: mainAdminUserRemove,
-	Before: setGlobalsFromContext,
+	Before: setGlobalsFromMutatingContext,
 	Flags:  globalFlags,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
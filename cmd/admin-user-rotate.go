@@ -0,0 +1,258 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Attempts, a second apart, to sign in with rotated credentials before
+// they are given up on, servers of a cluster pick up new users a moment
+// after they are set.
+const rotateVerifyAttempts = 3
+
+var adminUserRotateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "secret-key",
+		Usage: "new secret key, a random secret key is generated by default",
+	},
+	cli.StringFlag{
+		Name:  "update-alias",
+		Usage: "alias signing in as the user, its secret key is replaced once the new one is verified",
+	},
+	yesFlag,
+}
+
+var adminUserRotateCmd = cli.Command{
+	Name:   "rotate",
+	Usage:  "replace the secret key of a user",
+	Action: mainAdminUserRotate,
+	Before: setGlobalsFromMutatingContext,
+	Flags:  append(adminUserRotateFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET ACCESSKEY
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  A user has a single secret key, applications still using the previous one lose access as
+  soon as it is replaced. The new secret key is verified by signing in with it, when that
+  fails the previous secret key is restored if it is known from the alias of the user.
+
+  With '--update-alias' the alias is only updated once the new secret key is verified, the
+  configuration file is replaced as a whole so it is never left half written. Service
+  accounts are not supported by the admin API of this MinIO server version.
+
+EXAMPLES:
+  1. Replace the secret key of user 'foobar' on MinIO server with a random one.
+     $ {{.HelpName}} myminio foobar
+
+  2. Replace the secret key of user 'foobar' and update the alias 'foobar-minio' signing in as 'foobar'.
+     $ {{.HelpName}} --update-alias foobar-minio myminio foobar
+
+  3. Replace the secret key of user 'foobar' with a chosen one without asking for confirmation.
+     $ set +o history
+     $ {{.HelpName}} --yes --secret-key foo67890 myminio foobar
+     $ set -o history
+`,
+}
+
+// userRotateMessage container for a rotated secret key.
+type userRotateMessage struct {
+	Status    string `json:"status"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Alias     string `json:"alias,omitempty"`
+	Verified  bool   `json:"verified"`
+}
+
+func (u userRotateMessage) String() string {
+	msg := "Replaced the secret key of user `" + u.AccessKey + "`, the new secret key is `" + u.SecretKey + "`."
+	if !u.Verified {
+		msg += " The user is disabled, the new secret key was not verified."
+	}
+	if u.Alias != "" {
+		msg += " Updated alias `" + u.Alias + "`."
+	}
+	return console.Colorize("UserMessage", msg)
+}
+
+func (u userRotateMessage) JSON() string {
+	u.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// generateSecretKey - random secret key of 40 characters, the length
+// of the secret keys generated by MinIO server.
+func generateSecretKey() (string, *probe.Error) {
+	keyBytes := make([]byte, 30)
+	if _, e := rand.Read(keyBytes); e != nil {
+		return "", probe.NewError(e)
+	}
+	return strings.Replace(base64.StdEncoding.EncodeToString(keyBytes), "/", "+", -1), nil
+}
+
+// isCredentialAccepted - an error signing in is only a rejection of the
+// credentials when the server does not know them, a user without
+// permission to list buckets is still signed in.
+func isCredentialAccepted(e error) bool {
+	if e == nil {
+		return true
+	}
+	return minio.ToErrorResponse(e).Code == "AccessDenied"
+}
+
+// verifyCredentials - sign in to the host with the given credentials.
+func verifyCredentials(urlStr string, hostCfg hostConfigV9) *probe.Error {
+	clnt, err := s3New(newS3Config(urlStr, &hostCfg))
+	if err != nil {
+		return err.Trace(urlStr)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return errInvalidArgument().Trace(urlStr)
+	}
+	var e error
+	for attempt := 1; attempt <= rotateVerifyAttempts; attempt++ {
+		if _, e = s3Clnt.api.ListBuckets(); isCredentialAccepted(e) {
+			return nil
+		}
+		if attempt < rotateVerifyAttempts {
+			time.Sleep(time.Second)
+		}
+	}
+	return probe.NewError(e).Trace(urlStr)
+}
+
+// checkAdminUserRotateSyntax - validate all the passed arguments
+func checkAdminUserRotateSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "rotate", 1) // last argument is exit code
+	}
+	alias := ctx.String("update-alias")
+	if alias == "" {
+		return
+	}
+	if hostCfg, _ := getEnvHostConfig(alias); hostCfg != nil {
+		fatalIf(errInvalidArgument().Trace(alias), "Unable to update alias `"+alias+"` defined by the `"+mcEnvHostPrefix+alias+"` environment variable.")
+	}
+	hostCfg, err := getHostConfig(alias)
+	fatalIf(err.Trace(alias), "Unable to find alias `"+alias+"`.")
+	if hostCfg.AccessKey != ctx.Args().Get(1) {
+		fatalIf(errInvalidArgument().Trace(alias), "Unable to update alias `"+alias+"`, it signs in as `"+hostCfg.AccessKey+"`.")
+	}
+}
+
+// mainAdminUserRotate is the handle for "mc admin user rotate" command.
+func mainAdminUserRotate(ctx *cli.Context) error {
+	checkAdminUserRotateSyntax(ctx)
+
+	console.SetColor("UserMessage", color.New(color.FgGreen))
+
+	args := ctx.Args()
+	aliasedURL, accessKey := args.Get(0), args.Get(1)
+	updateAlias := ctx.String("update-alias")
+
+	_, urlStr, targetHostCfg, err := expandAlias(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to find alias of `"+aliasedURL+"`.")
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Cannot get a configured admin connection.")
+
+	users, e := client.ListUsers()
+	fatalIf(probe.NewError(e).Trace(args...), "Cannot list users")
+	user, ok := users[accessKey]
+	if !ok {
+		fatalIf(errNoSuchUser(accessKey).Trace(args...), "Cannot rotate the secret key")
+	}
+
+	secretKey := ctx.String("secret-key")
+	if secretKey == "" {
+		secretKey, err = generateSecretKey()
+		fatalIf(err, "Unable to generate a secret key.")
+	}
+
+	// The previous secret key can only be restored when an alias
+	// signing in as the user knows it.
+	var previousSecretKey string
+	if updateAlias != "" {
+		previousSecretKey = mustGetHostConfig(updateAlias).SecretKey
+	} else if targetHostCfg != nil && targetHostCfg.AccessKey == accessKey {
+		previousSecretKey = targetHostCfg.SecretKey
+	}
+
+	if isConfirmationNeeded(ctx) {
+		confirmOrExit("Replace the secret key of user `" + accessKey + "`? Applications using the current one lose access.")
+	}
+
+	fatalIf(probe.NewError(client.SetUser(accessKey, secretKey, user.Status)).Trace(args...), "Cannot replace the secret key")
+
+	rotatedHostCfg := *targetHostCfg
+	rotatedHostCfg.AccessKey, rotatedHostCfg.SecretKey = accessKey, secretKey
+	verified := user.Status == madmin.AccountEnabled
+	if verified {
+		if verifyErr := verifyCredentials(urlStr, rotatedHostCfg); verifyErr != nil {
+			if previousSecretKey == "" {
+				fatalIf(verifyErr, "Unable to sign in with the new secret key `"+secretKey+"` of user `"+accessKey+"`, the previous one is not known and cannot be restored.")
+			}
+			// Rotating the secret key of the admin alias itself
+			// leaves it signing in with the new secret key.
+			if targetHostCfg.AccessKey == accessKey {
+				client, err = s3AdminNew(newS3Config(urlStr, &rotatedHostCfg))
+				fatalIf(err, "Unable to sign in with the new secret key `"+secretKey+"` of user `"+accessKey+"` to restore the previous one.")
+			}
+			e = client.SetUser(accessKey, previousSecretKey, user.Status)
+			fatalIf(probe.NewError(e).Trace(args...), "Unable to restore the previous secret key of user `"+accessKey+"`, the new secret key is `"+secretKey+"`.")
+			fatalIf(verifyErr, "Unable to sign in with the new secret key of user `"+accessKey+"`, the previous secret key was restored.")
+		}
+	}
+
+	if updateAlias != "" {
+		err := updateMcConfig(func(mcCfg *configV9) {
+			hostCfg := mcCfg.Hosts[updateAlias]
+			hostCfg.SecretKey = secretKey
+			mcCfg.Hosts[updateAlias] = hostCfg
+		})
+		fatalIf(err.Trace(updateAlias), "Unable to update alias `"+updateAlias+"`, the new secret key of user `"+accessKey+"` is `"+secretKey+"`.")
+	}
+
+	printMsg(userRotateMessage{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Alias:     updateAlias,
+		Verified:  verified,
+	})
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	minio "github.com/minio/minio-go/v6"
+)
+
+func TestGenerateSecretKey(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		secretKey, err := generateSecretKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secretKey) != 40 {
+			t.Errorf("expected a secret key of 40 characters, got %q", secretKey)
+		}
+		if seen[secretKey] {
+			t.Errorf("secret key %q generated twice", secretKey)
+		}
+		seen[secretKey] = true
+	}
+}
+
+func TestIsCredentialAccepted(t *testing.T) {
+	testCases := []struct {
+		err      error
+		accepted bool
+	}{
+		{nil, true},
+		{minio.ErrorResponse{Code: "AccessDenied"}, true},
+		{minio.ErrorResponse{Code: "InvalidAccessKeyId"}, false},
+		{minio.ErrorResponse{Code: "SignatureDoesNotMatch"}, false},
+		{errors.New("connection refused"), false},
+	}
+	for i, testCase := range testCases {
+		if accepted := isCredentialAccepted(testCase.err); accepted != testCase.accepted {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.accepted, accepted)
+		}
+	}
+}
@@ -31,6 +31,7 @@ var adminUserCmd = cli.Command{
 		adminUserEnableCmd,
 		adminUserRemoveCmd,
 		adminUserListCmd,
+		adminUserRotateCmd,
 	},
 	HideHelpCommand: true,
 }
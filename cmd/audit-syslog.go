@@ -0,0 +1,43 @@
+// +build !windows,!plan9,!nacl
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"log/syslog"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// writeAuditSyslog - send an audit entry to the local syslog daemon,
+// failed commands are logged as warnings.
+func writeAuditSyslog(success bool, entry string) *probe.Error {
+	priority := syslog.LOG_AUTHPRIV | syslog.LOG_INFO
+	if !success {
+		priority = syslog.LOG_AUTHPRIV | syslog.LOG_WARNING
+	}
+	w, e := syslog.New(priority, "mc")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer w.Close()
+	if _, e = w.Write([]byte(entry)); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Audit log modes, set in the config file or by MC_AUDIT.
+const (
+	auditOff    = "off"
+	auditFile   = "file"
+	auditSyslog = "syslog"
+)
+
+// Name of the audit log in the config folder.
+const auditLogFile = "audit.log"
+
+// auditedCommands - commands modifying servers, buckets, objects or the
+// local configuration, with the positions of secret arguments.
+var auditedCommands = map[string][]int{
+	"cp":                    nil,
+	"mv":                    nil,
+	"rm":                    nil,
+	"rb":                    nil,
+	"mb":                    nil,
+	"pipe":                  nil,
+	"mirror":                nil,
+	"policy":                nil,
+	"clean":                 nil,
+	"login":                 nil,
+	"update":                nil,
+	"event/add":             nil,
+	"event/remove":          nil,
+	"session/clear":         nil,
+	"session/resume":        nil,
+	"share/delete":          nil,
+	"share/rm":              nil,
+	"config/host/add":       {3},
+	"config/host/remove":    nil,
+	"config/import":         nil,
+	"config/audit":          nil,
+	"idp/openid/set":        nil,
+	"idp/openid/remove":     nil,
+	"admin/heal":            nil,
+	"admin/config/set":      nil,
+	"admin/service/restart": nil,
+	"admin/service/stop":    nil,
+	"admin/profile/start":   nil,
+	"admin/policy/add":      nil,
+	"admin/policy/remove":   nil,
+	"admin/user/add":        {2},
+	"admin/user/policy":     nil,
+	"admin/user/disable":    nil,
+	"admin/user/enable":     nil,
+	"admin/user/remove":     nil,
+	"admin/user/rotate":     nil,
+}
+
+// Flags whose values are never written to the audit log.
+var auditRedactedFlags = []string{"secret-key", "encrypt-key", "passphrase"}
+
+// auditEntry - record of one invocation of a mutating command.
+type auditEntry struct {
+	Time     time.Time     `json:"time"`
+	User     string        `json:"user,omitempty"`
+	Host     string        `json:"host,omitempty"`
+	PID      int           `json:"pid"`
+	Version  string        `json:"version"`
+	Command  string        `json:"command"`
+	Args     []string      `json:"args"`
+	Targets  []string      `json:"targets,omitempty"`
+	Status   string        `json:"status"`
+	ExitCode int           `json:"exitCode"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+
+	mode string
+}
+
+var (
+	// Audit entry of the running command, nil if it is not audited.
+	globalAudit     *auditEntry
+	globalAuditOnce sync.Once
+)
+
+// getAuditMode - the audit log mode, MC_AUDIT overrides the config file.
+func getAuditMode() string {
+	mode := os.Getenv("MC_AUDIT")
+	if mode == "" {
+		if config, err := loadMcConfig(); err == nil {
+			mode = config.Audit
+		}
+	}
+	switch mode = strings.ToLower(mode); mode {
+	case auditFile, auditSyslog:
+		return mode
+	}
+	return auditOff
+}
+
+// isValidAuditMode - returns true if the audit log mode is known.
+func isValidAuditMode(mode string) bool {
+	switch mode {
+	case auditOff, auditFile, auditSyslog:
+		return true
+	}
+	return false
+}
+
+// auditCommandPath - path of the command of a context, like 'admin/user/add'.
+func auditCommandPath(ctx *cli.Context) string {
+	if ctx.Command.Name == "" {
+		return ""
+	}
+	names := append(strings.Fields(ctx.App.Name)[1:], ctx.Command.Name)
+	return strings.Join(names, "/")
+}
+
+// isAuditedCommand - returns true if the command modifies anything,
+// 'policy' only does when it sets a policy and 'config audit' when it
+// sets the mode.
+func isAuditedCommand(path string, args []string) bool {
+	if _, ok := auditedCommands[path]; !ok {
+		return false
+	}
+	if path == "config/audit" {
+		return len(args) > 0
+	}
+	if path == "policy" {
+		switch {
+		case len(args) > 0 && args[0] == "set-json":
+			return true
+		case len(args) < 2:
+			return false
+		}
+		switch args[0] {
+		case "list", "links", "get-json":
+			return false
+		}
+	}
+	return true
+}
+
+// redactAuditArgs - the command line without secrets, the values of
+// secret flags and arguments and the passwords of URLs are redacted.
+func redactAuditArgs(cmdArgs []string, secrets []string) []string {
+	isSecret := func(arg string) bool {
+		for _, secret := range secrets {
+			if secret != "" && arg == secret {
+				return true
+			}
+		}
+		return false
+	}
+	redactURL := func(arg string) string {
+		if u, e := url.Parse(arg); e == nil && u.User != nil {
+			return redactProxyURL(u)
+		}
+		return arg
+	}
+	redacted := make([]string, 0, len(cmdArgs))
+	redactNext := false
+	for _, arg := range cmdArgs {
+		switch {
+		case redactNext || isSecret(arg):
+			arg = traceRedacted
+			redactNext = false
+		case strings.HasPrefix(arg, "-"):
+			name := strings.TrimLeft(arg, "-")
+			value := ""
+			if i := strings.Index(name, "="); i >= 0 {
+				name, value = name[:i], name[i+1:]
+			}
+			isRedacted := false
+			for _, flag := range auditRedactedFlags {
+				if name != flag {
+					continue
+				}
+				isRedacted = true
+				if value != "" {
+					arg = arg[:len(arg)-len(value)] + traceRedacted
+				} else {
+					redactNext = true
+				}
+			}
+			if !isRedacted && value != "" {
+				// Passwords of URLs given as --flag=URL, i.e. --proxy.
+				arg = arg[:len(arg)-len(value)] + redactURL(value)
+			}
+		default:
+			arg = redactURL(arg)
+		}
+		redacted = append(redacted, arg)
+	}
+	return redacted
+}
+
+// commandSecrets - the secret arguments and flag values of the command
+// of a context, which are redacted from its command line.
+func commandSecrets(ctx *cli.Context, path string) []string {
+	var secrets []string
+	for _, i := range auditedCommands[path] {
+		secrets = append(secrets, ctx.Args().Get(i))
+	}
+	for _, flag := range auditRedactedFlags {
+		secrets = append(secrets, ctx.String(flag))
+	}
+	return secrets
+}
+
+// auditTargets - URLs of the aliases among the arguments.
+func auditTargets(args []string) []string {
+	var targets []string
+	for _, arg := range args {
+		alias, path := url2Alias(arg)
+		if hostCfg := mustGetHostConfig(alias); hostCfg != nil {
+			targets = append(targets, urlJoinPath(hostCfg.URL, path))
+		}
+	}
+	return targets
+}
+
+// startAudit - start the audit entry of the command of a context when
+// it is audited and the audit log is enabled.
+func startAudit(ctx *cli.Context) {
+	path := auditCommandPath(ctx)
+	args := ctx.Args()
+	if !isAuditedCommand(path, args) {
+		return
+	}
+	mode := getAuditMode()
+	if mode == auditOff {
+		return
+	}
+	entry := &auditEntry{
+		Time:    UTCNow(),
+		PID:     os.Getpid(),
+		Version: Version,
+		Command: strings.Replace(path, "/", " ", -1),
+		Args:    redactAuditArgs(os.Args[1:], commandSecrets(ctx, path)),
+		Targets: auditTargets(args),
+		mode:    mode,
+	}
+	if u, e := user.Current(); e == nil {
+		entry.User = u.Username
+	}
+	entry.Host, _ = os.Hostname()
+	globalAudit = entry
+}
+
+// finishAudit - record the result of an audited command, only once as
+// mc may exit from several places.
+func finishAudit(exitCode int, errMsg string) {
+	if globalAudit == nil {
+		return
+	}
+	globalAuditOnce.Do(func() {
+		entry := globalAudit
+		entry.Duration = time.Since(entry.Time)
+		entry.ExitCode = exitCode
+		entry.Error = errMsg
+		entry.Status = "success"
+		if exitCode != 0 {
+			entry.Status = "error"
+		}
+		errorIf(writeAuditEntry(entry), "Unable to write to the audit log.")
+	})
+}
+
+// writeAuditEntry - append an audit entry to the audit log of its mode.
+func writeAuditEntry(entry *auditEntry) *probe.Error {
+	entryBytes, e := json.Marshal(entry)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if entry.mode == auditSyslog {
+		return writeAuditSyslog(entry.Status == "success", string(entryBytes))
+	}
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return err.Trace()
+	}
+	if err = createMcConfigDir(); err != nil {
+		return err.Trace(configDir)
+	}
+	logFile := filepath.Join(configDir, auditLogFile)
+	f, e := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if e != nil {
+		return probe.NewError(e).Trace(logFile)
+	}
+	defer f.Close()
+	if _, e = f.Write(append(entryBytes, '\n')); e != nil {
+		return probe.NewError(e).Trace(logFile)
+	}
+	return nil
+}
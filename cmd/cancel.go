@@ -0,0 +1,208 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Time given to a cancelled command to stop cleanly, such as to abort
+// its incomplete uploads, before mc exits.
+const cancelGracePeriod = 10 * time.Second
+
+var (
+	// globalContext is cancelled on interrupt or once '--timeout' expires,
+	// all requests and transfers are bound to it.
+	globalContext, globalCancel = context.WithCancel(context.Background())
+
+	// Timer of '--timeout', nil if not set.
+	globalTimeoutTimer *time.Timer
+	globalTimeout      time.Duration
+	globalTimedOut     bool
+	globalTimeoutMutex sync.Mutex
+)
+
+// setGlobalTimeout - cancel globalContext once the timeout expires, a
+// timeout of 0 keeps the current timeout.
+func setGlobalTimeout(timeout time.Duration) {
+	globalTimeoutMutex.Lock()
+	defer globalTimeoutMutex.Unlock()
+	if timeout <= 0 || timeout == globalTimeout {
+		return
+	}
+	if globalTimeoutTimer != nil {
+		globalTimeoutTimer.Stop()
+	}
+	globalTimeout = timeout
+	globalTimeoutTimer = time.AfterFunc(timeout, func() {
+		globalTimeoutMutex.Lock()
+		globalTimedOut = true
+		globalTimeoutMutex.Unlock()
+		globalCancel()
+	})
+}
+
+// isTimedOut - returns true if globalContext was cancelled by '--timeout'.
+func isTimedOut() bool {
+	globalTimeoutMutex.Lock()
+	defer globalTimeoutMutex.Unlock()
+	return globalTimedOut
+}
+
+// isInterrupted - returns true if globalContext was cancelled by SIGINT
+// or SIGTERM.
+func isInterrupted() bool {
+	return globalContext.Err() != nil && !isTimedOut()
+}
+
+// trapCancellation - cancel globalContext on interrupt, commands are
+// given cancelGracePeriod to stop before mc exits. Commands trapping
+// signals themselves stop on their own. A second interrupt exits right
+// away.
+func trapCancellation() {
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigCh:
+			globalCancel()
+			if atomic.LoadInt32(&signalTraps) > 0 {
+				<-sigCh
+				finishCommand(globalInterruptExitStatus, "interrupted")
+				os.Exit(globalInterruptExitStatus)
+			}
+		case <-globalContext.Done():
+		}
+		select {
+		case <-sigCh:
+		case <-time.After(cancelGracePeriod):
+		}
+		if isTimedOut() {
+			fatalIf(errDummy().Trace(), fmt.Sprintf("Command timed out after %s.", globalTimeout))
+		}
+		finishCommand(globalInterruptExitStatus, "interrupted")
+		os.Exit(globalInterruptExitStatus)
+	}()
+}
+
+// cancelledError - the error of an operation stopped by globalContext.
+func cancelledError() error {
+	if isTimedOut() {
+		return fmt.Errorf("timed out after %s", globalTimeout)
+	}
+	return context.Canceled
+}
+
+// errCancelled - the error of an operation stopped by globalContext.
+func errCancelled() *probe.Error {
+	return probe.NewError(cancelledError())
+}
+
+// contextReader - a reader failing once its context is cancelled.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if e := r.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return r.reader.Read(p)
+}
+
+// newContextReader - stop reading from reader once ctx is cancelled.
+func newContextReader(ctx context.Context, reader io.Reader) io.Reader {
+	return contextReader{ctx: ctx, reader: reader}
+}
+
+// contextTransport - a round tripper binding all requests to ctx, so
+// requests sent without a context are cancelled too. Requests cleaning
+// up incomplete uploads are not cancelled.
+type contextTransport struct {
+	ctx       context.Context
+	transport http.RoundTripper
+}
+
+// contextBody - a response body releasing the request context once
+// closed.
+type contextBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b contextBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// RoundTrip - send the request with a context cancelled along with the
+// request's own context or the context of the transport.
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isCleanupRequest(req) {
+		return t.transport.RoundTrip(req)
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		select {
+		case <-t.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	resp, e := t.transport.RoundTrip(req.WithContext(ctx))
+	if e != nil {
+		cancel()
+		if t.ctx.Err() != nil {
+			return nil, cancelledError()
+		}
+		return nil, e
+	}
+	resp.Body = contextBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// isCleanupRequest - returns true for the requests listing and aborting
+// incomplete uploads, which still run once globalContext is cancelled.
+func isCleanupRequest(req *http.Request) bool {
+	query := req.URL.Query()
+	switch req.Method {
+	case http.MethodGet:
+		_, isListUploads := query["uploads"]
+		return isListUploads
+	case http.MethodDelete:
+		_, isAbortUpload := query["uploadId"]
+		return isAbortUpload
+	}
+	return false
+}
+
+// newContextTransport - bind all requests of transport to globalContext.
+func newContextTransport(transport http.RoundTripper) http.RoundTripper {
+	return contextTransport{ctx: globalContext, transport: transport}
+}
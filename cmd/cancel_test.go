@@ -0,0 +1,107 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContextReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := newContextReader(ctx, bytes.NewReader([]byte("hello world")))
+	p := make([]byte, 5)
+	if n, e := reader.Read(p); e != nil || n != 5 {
+		t.Fatalf("expected 5 bytes read, got %d, %v", n, e)
+	}
+	cancel()
+	if _, e := reader.Read(p); e != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, e)
+	}
+}
+
+func TestIsCleanupRequest(t *testing.T) {
+	testCases := []struct {
+		method    string
+		url       string
+		isCleanup bool
+	}{
+		{http.MethodGet, "http://localhost:9000/bucket?uploads&prefix=object", true},
+		{http.MethodDelete, "http://localhost:9000/bucket/object?uploadId=abc", true},
+		{http.MethodGet, "http://localhost:9000/bucket/object", false},
+		{http.MethodPut, "http://localhost:9000/bucket/object?partNumber=1&uploadId=abc", false},
+		{http.MethodDelete, "http://localhost:9000/bucket/object", false},
+	}
+	for i, testCase := range testCases {
+		req, e := http.NewRequest(testCase.method, testCase.url, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if isCleanup := isCleanupRequest(req); isCleanup != testCase.isCleanup {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.isCleanup, isCleanup)
+		}
+	}
+}
+
+func TestContextTransport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hang" {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: contextTransport{ctx: ctx, transport: http.DefaultTransport}}
+
+	resp, e := client.Get(server.URL + "/ok")
+	if e != nil {
+		t.Fatal(e)
+	}
+	body, e := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if e != nil || string(body) != "ok" {
+		t.Fatalf("expected ok, got %q, %v", body, e)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		resp, e := client.Get(server.URL + "/hang")
+		if e == nil {
+			resp.Body.Close()
+		}
+		errCh <- e
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case e := <-errCh:
+		if e == nil {
+			t.Fatal("expected the request to be cancelled")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not cancelled")
+	}
+}
@@ -0,0 +1,202 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+var catConcatFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "concat, recursive, r",
+		Usage: "concatenate all objects under each SOURCE prefix in lexical order",
+	},
+	cli.StringFlag{
+		Name:  "separator",
+		Usage: "write STRING between the objects concatenated by --concat, '\\n', '\\t' and '\\0' are a newline, a tab and a NUL",
+	},
+	cli.IntFlag{
+		Name:  "prefetch",
+		Usage: "number of objects read ahead while concatenating, up to 4MiB each",
+		Value: 2,
+	},
+}
+
+const (
+	// Objects are read ahead in catPrefetchChunks chunks of
+	// catPrefetchChunk bytes at most, reading an object stops until
+	// its chunks are written.
+	catPrefetchChunk  = 1024 * 1024
+	catPrefetchChunks = 4
+)
+
+// catSeparatorEscaper unescapes the escape sequences of --separator.
+var catSeparatorEscaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t", `\0`, "\x00")
+
+// prefetchedObject - an object read in the background.
+type prefetchedObject struct {
+	chunks  chan []byte
+	current []byte
+
+	// error of the object, set before chunks is closed
+	err *probe.Error
+}
+
+// prefetchObject - start reading an object, until doneCh is closed.
+func prefetchObject(alias, urlStr string, sse encrypt.ServerSide, doneCh <-chan struct{}) *prefetchedObject {
+	o := &prefetchedObject{chunks: make(chan []byte, catPrefetchChunks)}
+	go func() {
+		defer close(o.chunks)
+		reader, _, err := getSourceStream(globalContext, alias, urlStr, false, sse)
+		if err != nil {
+			o.err = err.Trace(urlStr)
+			return
+		}
+		defer reader.Close()
+		for {
+			chunk := make([]byte, catPrefetchChunk)
+			n, e := io.ReadFull(reader, chunk)
+			if n > 0 {
+				select {
+				case o.chunks <- chunk[:n]:
+				case <-doneCh:
+					return
+				}
+			}
+			switch e {
+			case nil:
+			case io.EOF, io.ErrUnexpectedEOF:
+				return
+			default:
+				o.err = probe.NewError(e).Trace(urlStr)
+				return
+			}
+		}
+	}()
+	return o
+}
+
+// Read implements io.Reader, waits for the chunks read ahead.
+func (o *prefetchedObject) Read(p []byte) (int, error) {
+	for len(o.current) == 0 {
+		chunk, ok := <-o.chunks
+		if !ok {
+			if o.err != nil {
+				return 0, o.err.ToGoError()
+			}
+			return 0, io.EOF
+		}
+		o.current = chunk
+	}
+	n := copy(p, o.current)
+	o.current = o.current[n:]
+	return n, nil
+}
+
+// catContent - an object to concatenate.
+type catContent struct {
+	alias   string
+	content *clientContent
+	isS3    bool
+}
+
+// listCatContents - all objects under a prefix, in lexical order.
+func listCatContents(sourceURL string) ([]catContent, *probe.Error) {
+	alias, expandedURL, _ := mustExpandAlias(sourceURL)
+	clnt, err := newClientFromAlias(alias, expandedURL)
+	if err != nil {
+		return nil, err.Trace(sourceURL)
+	}
+	isS3 := clnt.GetURL().Type == objectStorage
+	var contents []catContent
+	for content := range clnt.List(globalContext, true, false, DirNone) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(sourceURL)
+		}
+		if !content.Type.IsDir() {
+			contents = append(contents, catContent{alias: alias, content: content, isS3: isS3})
+		}
+	}
+	// Folders of local listings come before names sorting lower.
+	sort.SliceStable(contents, func(i, j int) bool {
+		return filepath.ToSlash(contents[i].content.URL.Path) < filepath.ToSlash(contents[j].content.URL.Path)
+	})
+	return contents, nil
+}
+
+// catConcat - write all objects under the prefixes of sourceURLs to
+// stdout, each prefix in lexical order, while the next prefetch objects
+// are read ahead.
+func catConcat(sourceURLs []string, encKeyDB map[string][]prefixSSEPair, transforms []string, transformExec, separator string, prefetch int) *probe.Error {
+	var contents []catContent
+	for _, sourceURL := range sourceURLs {
+		prefixContents, err := listCatContents(sourceURL)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, prefixContents...)
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	var window []*prefetchedObject
+	next := 0
+	for i, c := range contents {
+		for ; next < len(contents) && next <= i+prefetch; next++ {
+			n := contents[next]
+			sse := getSSE(filepath.ToSlash(filepath.Join(n.alias, n.content.URL.Path)), encKeyDB[n.alias])
+			window = append(window, prefetchObject(n.alias, n.content.URL.String(), sse, doneCh))
+		}
+		var reader io.Reader = window[0]
+		window = window[1:]
+		urlStr := c.content.URL.String()
+
+		if i > 0 && separator != "" {
+			if err := catOut(strings.NewReader(separator), -1); err != nil {
+				return err.Trace(urlStr)
+			}
+		}
+		// Like catURL, only sizes of objects are checked.
+		size := int64(-1)
+		if c.isS3 {
+			size = c.content.Size
+		}
+		if transformExec != "" || len(transforms) > 0 {
+			transform, err := newTransformsReader(transforms, transformExec, reader, urlStr, "-")
+			if err != nil {
+				return err.Trace(urlStr)
+			}
+			err = catOut(transform, -1)
+			transform.Close()
+			if err != nil {
+				return err.Trace(urlStr)
+			}
+			continue
+		}
+		if err := catOut(reader, size); err != nil {
+			return err.Trace(urlStr)
+		}
+	}
+	return nil
+}
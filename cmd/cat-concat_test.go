@@ -0,0 +1,110 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestCatConcatContents(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-cat-concat-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(root, "config"))
+	mcCfg := newMcConfig()
+	loadMcConfig = func() (*configV9, *probe.Error) { return mcCfg, nil }
+
+	logs := filepath.Join(root, "logs")
+	big := bytes.Repeat([]byte("0123456789"), catPrefetchChunk/4)
+	files := map[string][]byte{
+		"b":         []byte("b"),
+		"a/2":       []byte("a2"),
+		"a.txt":     []byte("a.txt"),
+		"a/1":       []byte("a1"),
+		"c/big.bin": big,
+	}
+	for name, data := range files {
+		path := filepath.Join(logs, filepath.FromSlash(name))
+		if e = os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, data, 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	contents, err := listCatContents(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Names are in lexical order, of the whole key and not folder by folder.
+	expected := []string{"a.txt", "a/1", "a/2", "b", "c/big.bin"}
+	if len(contents) != len(expected) {
+		t.Fatalf("expected %d objects, found %d", len(expected), len(contents))
+	}
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	for i, c := range contents {
+		name := strings.TrimPrefix(filepath.ToSlash(c.content.URL.Path), filepath.ToSlash(logs)+"/")
+		if name != expected[i] {
+			t.Fatalf("Test %d: expected `%s`, found `%s`", i+1, expected[i], name)
+		}
+		data, e := ioutil.ReadAll(prefetchObject(c.alias, c.content.URL.String(), nil, doneCh))
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if !bytes.Equal(data, files[name]) {
+			t.Fatalf("Test %d: expected %d bytes of `%s`, found %d", i+1, len(files[name]), name, len(data))
+		}
+	}
+
+	// A missing object is an error of its reader.
+	if _, e = ioutil.ReadAll(prefetchObject("", filepath.Join(logs, "missing"), nil, doneCh)); e == nil {
+		t.Fatal("expected an error reading a missing object")
+	}
+}
+
+func TestCatSeparatorEscaper(t *testing.T) {
+	testCases := []struct {
+		separator string
+		expected  string
+	}{
+		{"", ""},
+		{"---", "---"},
+		{`\n`, "\n"},
+		{`\t|\0`, "\t|\x00"},
+		{`\\n`, `\n`},
+		{`\x`, `\x`},
+	}
+	for i, testCase := range testCases {
+		if s := catSeparatorEscaper.Replace(testCase.separator); s != testCase.expected {
+			t.Fatalf("Test %d: expected %q, found %q", i+1, testCase.expected, s)
+		}
+	}
+}
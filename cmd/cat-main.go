@@ -34,10 +34,10 @@ import (
 )
 
 var (
-	// This is kept dummy for future purposes
-	// and also to add ioFlags and globalFlags
-	// in CLI registration.
-	catFlags = []cli.Flag{}
+	catFlags = []cli.Flag{
+		transformsFlag,
+		transformFlag,
+	}
 )
 
 // Display contents of a file.
@@ -46,7 +46,7 @@ var catCmd = cli.Command{
 	Usage:  "display object contents",
 	Action: mainCat,
 	Before: setGlobalsFromContext,
-	Flags:  append(append(catFlags, ioFlags...), globalFlags...),
+	Flags:  append(append(append(catFlags, catConcatFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -71,6 +71,15 @@ EXAMPLES:
 
    4. Save an encrypted object from Amazon S3 cloud storage to a local file.
       $ {{.HelpName}} --encrypt-key 's3/mysql-backups=32byteslongsecretkeymustbegiven1' s3/mysql-backups/backups-201810.gz > /mnt/data/recent.gz
+
+   5. Display a compressed object from Amazon S3 cloud storage, decompressing it on the fly.
+      $ {{.HelpName}} --transform-exec 'gunzip' s3/mysql-backups/backups-201810.sql.gz
+
+   6. Display the names of the items of a compressed JSON object, without a local jq or gunzip.
+      $ {{.HelpName}} --transform gunzip --transform 'jq-raw:.items[].name' s3/inventory/items.json.gz
+
+   7. Stream the chunks of a log, split across the objects of a prefix, in the order of their names.
+      $ {{.HelpName}} --concat s3/logs/2019-10-01/app.log.
 `,
 }
 
@@ -134,10 +143,23 @@ func checkCatSyntax(ctx *cli.Context) {
 			fatalIf(probe.NewError(errors.New("")), fmt.Sprintf("Unknown flag `%s` passed.", arg))
 		}
 	}
+	if ctx.Bool("concat") {
+		if !ctx.Args().Present() {
+			fatalIf(errInvalidArgument().Trace(), "--concat needs at least one SOURCE prefix.")
+		}
+		for _, arg := range args {
+			if arg == "-" {
+				fatalIf(errInvalidArgument().Trace(arg), "--concat cannot read the standard input.")
+			}
+		}
+		if ctx.Int("prefetch") < 0 {
+			fatalIf(errInvalidArgument().Trace(ctx.String("prefetch")), "--prefetch cannot be negative.")
+		}
+	}
 }
 
 // catURL displays contents of a URL to stdout.
-func catURL(sourceURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+func catURL(sourceURL string, encKeyDB map[string][]prefixSSEPair, transforms []string, transformExec string) *probe.Error {
 	var reader io.ReadCloser
 	size := int64(-1)
 	switch sourceURL {
@@ -150,7 +172,7 @@ func catURL(sourceURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error
 		// downloaded object is equal to the original one. FS files
 		// are ignored since some of them have zero size though they
 		// have contents like files under /proc.
-		client, content, err := url2Stat(sourceURL, false, encKeyDB)
+		client, content, err := url2Stat(globalContext, sourceURL, false, encKeyDB)
 		if err == nil && client.GetURL().Type == objectStorage {
 			size = content.Size
 		}
@@ -159,6 +181,15 @@ func catURL(sourceURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error
 		}
 		defer reader.Close()
 	}
+	if transformExec != "" || len(transforms) > 0 {
+		var err *probe.Error
+		if reader, err = newTransformsReader(transforms, transformExec, reader, sourceURL, "-"); err != nil {
+			return err.Trace(sourceURL)
+		}
+		defer reader.Close()
+		// Size of the output is only known to the transforms.
+		size = -1
+	}
 	return catOut(reader, size).Trace(sourceURL)
 }
 
@@ -215,6 +246,14 @@ func mainCat(ctx *cli.Context) error {
 
 	// check 'cat' cli arguments.
 	checkCatSyntax(ctx)
+	checkTransforms(ctx.StringSlice("transform"))
+
+	if ctx.Bool("concat") {
+		separator := catSeparatorEscaper.Replace(ctx.String("separator"))
+		fatalIf(catConcat(ctx.Args(), encKeyDB, ctx.StringSlice("transform"), ctx.String("transform-exec"), separator, ctx.Int("prefetch")).Trace(ctx.Args()...),
+			"Unable to concatenate `"+strings.Join(ctx.Args(), "`, `")+"`.")
+		return nil
+	}
 
 	// Set command flags from context.
 	stdinMode := false
@@ -224,7 +263,7 @@ func mainCat(ctx *cli.Context) error {
 
 	// handle std input data.
 	if stdinMode {
-		fatalIf(catOut(os.Stdin, -1).Trace(), "Unable to read from standard input.")
+		fatalIf(catURL("-", encKeyDB, ctx.StringSlice("transform"), ctx.String("transform-exec")).Trace(), "Unable to read from standard input.")
 		return nil
 	}
 
@@ -242,7 +281,7 @@ func mainCat(ctx *cli.Context) error {
 
 	// Convert arguments to URLs: expand alias, fix format.
 	for _, url := range args {
-		fatalIf(catURL(url, encKeyDB).Trace(url), "Unable to read from `"+url+"`.")
+		fatalIf(catURL(url, encKeyDB, ctx.StringSlice("transform"), ctx.String("transform-exec")).Trace(url), "Unable to read from `"+url+"`.")
 	}
 
 	return nil
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"io"
 	"io/ioutil"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -62,3 +64,41 @@ func TestPrettyStdout(t *testing.T) {
 		}
 	}
 }
+
+func TestTransformReader(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("filter programs are run by /bin/sh")
+	}
+	testCases := []struct {
+		command  string
+		input    string
+		output   string
+		success  bool
+		errorMsg string
+	}{
+		{"tr a-z A-Z", "hello world", "HELLO WORLD", true, ""},
+		{"cat; echo \"$MC_TRANSFORM_SOURCE\"", "data\n", "data\nplay/bucket/object\n", true, ""},
+		{"head -c 2", "partially read", "pa", true, ""},
+		{"echo broken >&2; exit 3", "data", "", false, "broken"},
+	}
+	for i, testCase := range testCases {
+		reader, err := newTransformReader(testCase.command, strings.NewReader(testCase.input), "play/bucket/object", "-")
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		output, e := ioutil.ReadAll(reader)
+		reader.Close()
+		if e != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %v", i+1, e)
+		}
+		if e == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if e != nil && !strings.Contains(e.Error(), testCase.errorMsg) {
+			t.Fatalf("Test %d: Expected error to contain `%s`, got `%v`", i+1, testCase.errorMsg, e)
+		}
+		if testCase.success && string(output) != testCase.output {
+			t.Fatalf("Test %d: Expected `%s`, got `%s`", i+1, testCase.output, output)
+		}
+	}
+}
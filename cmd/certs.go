@@ -17,10 +17,17 @@
 package cmd
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/minio/mc/pkg/probe"
 )
@@ -115,6 +122,18 @@ func mustGetSystemCertPool() *x509.CertPool {
 	return pool
 }
 
+// appendCAFiles adds the PEM encoded certificates of all caFiles to the pool.
+func appendCAFiles(pool *x509.CertPool, caFiles ...string) *probe.Error {
+	for _, caFile := range caFiles {
+		caCert, e := ioutil.ReadFile(caFile)
+		if e != nil {
+			return probe.NewError(e).Trace(caFile)
+		}
+		pool.AppendCertsFromPEM(caCert)
+	}
+	return nil
+}
+
 // loadRootCAs fetches CA files provided in MinIO config and adds them to globalRootCAs
 // Currently under Windows, there is no way to load system + user CAs at the same time
 func loadRootCAs() {
@@ -125,11 +144,84 @@ func loadRootCAs() {
 	// Get system cert pool, and empty cert pool under Windows because it is not supported
 	globalRootCAs = mustGetSystemCertPool()
 	// Load custom root CAs for client requests
-	for _, caFile := range caFiles {
-		caCert, err := ioutil.ReadFile(caFile)
+	fatalIf(appendCAFiles(globalRootCAs, caFiles...), "Unable to load a CA file.")
+}
+
+// loadHostRootCAs returns a CA pool for a single host, made of the
+// system CAs, the CAs of the MinIO config dir and the host CA bundle.
+func loadHostRootCAs(caFile string) (*x509.CertPool, *probe.Error) {
+	pool := mustGetSystemCertPool()
+	if err := appendCAFiles(pool, mustGetCAFiles()...); err != nil {
+		return nil, err.Trace(caFile)
+	}
+	if err := appendCAFiles(pool, caFile); err != nil {
+		return nil, err.Trace(caFile)
+	}
+	return pool, nil
+}
+
+// certFingerprint returns the SHA-256 fingerprint of a certificate.
+func certFingerprint(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.Raw)
+	return sum[:]
+}
+
+// formatFingerprint formats a fingerprint as colon separated hex, like openssl does.
+func formatFingerprint(fingerprint []byte) string {
+	s := make([]string, len(fingerprint))
+	for i, b := range fingerprint {
+		s[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(s, ":")
+}
+
+// parseFingerprint parses a SHA-256 fingerprint with or without colons.
+func parseFingerprint(fingerprint string) ([]byte, *probe.Error) {
+	b, e := hex.DecodeString(strings.Replace(fingerprint, ":", "", -1))
+	if e != nil {
+		return nil, probe.NewError(e).Trace(fingerprint)
+	}
+	if len(b) != sha256.Size {
+		return nil, errInvalidArgument().Trace(fingerprint)
+	}
+	return b, nil
+}
+
+// setHostTLSConfig applies the host specific CA bundle, client
+// certificate and pinned fingerprint, if any, to tlsConfig.
+func setHostTLSConfig(tlsConfig *tls.Config, config *Config) *probe.Error {
+	if config.CACert != "" {
+		rootCAs, err := loadHostRootCAs(config.CACert)
 		if err != nil {
-			fatalIf(probe.NewError(err), "Unable to load a CA file.")
+			return err.Trace(config.CACert)
 		}
-		globalRootCAs.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = rootCAs
 	}
+	if config.ClientCert != "" {
+		cert, e := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if e != nil {
+			return probe.NewError(e).Trace(config.ClientCert, config.ClientKey)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.Fingerprint != "" {
+		fingerprint, err := parseFingerprint(config.Fingerprint)
+		if err != nil {
+			return err.Trace(config.Fingerprint)
+		}
+		// A pinned certificate replaces the CA based verification.
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("server did not present any certificate")
+			}
+			sum := sha256.Sum256(rawCerts[0])
+			if !bytes.Equal(sum[:], fingerprint) {
+				return fmt.Errorf("server certificate fingerprint %s does not match pinned fingerprint %s",
+					formatFingerprint(sum[:]), formatFingerprint(fingerprint))
+			}
+			return nil
+		}
+	}
+	return nil
 }
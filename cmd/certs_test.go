@@ -0,0 +1,162 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCertificate - a self-signed CA certificate for 127.0.0.1, also
+// written PEM encoded to path.
+func newTestCertificate(t *testing.T, name, path string) tls.Certificate {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, e := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); e != nil {
+		t.Fatal(e)
+	}
+	cert, e := x509.ParseCertificate(der)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}
+}
+
+func TestSetHostTLSConfig(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-certs-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(root, "config"))
+
+	serverCA, otherCA := filepath.Join(root, "server.pem"), filepath.Join(root, "other.pem")
+	serverCert := newTestCertificate(t, "server", serverCA)
+	otherCert := newTestCertificate(t, "other", otherCA)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert}}
+	// Rejected handshakes are expected.
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	serverFingerprint := formatFingerprint(certFingerprint(serverCert.Leaf))
+	testCases := []struct {
+		config  Config
+		success bool
+	}{
+		// The server is unknown to the system CAs.
+		{Config{}, false},
+		{Config{CACert: serverCA}, true},
+		{Config{CACert: otherCA}, false},
+		// A pinned fingerprint accepts the matching certificate only.
+		{Config{Fingerprint: serverFingerprint}, true},
+		{Config{Fingerprint: strings.ToLower(strings.Replace(serverFingerprint, ":", "", -1))}, true},
+		{Config{Fingerprint: formatFingerprint(certFingerprint(otherCert.Leaf))}, false},
+		{Config{CACert: serverCA, Fingerprint: formatFingerprint(certFingerprint(otherCert.Leaf))}, false},
+	}
+	for i, testCase := range testCases {
+		tlsConfig := &tls.Config{}
+		if err := setHostTLSConfig(tlsConfig, &testCase.config); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		resp, e := client.Get(server.URL)
+		if e == nil {
+			resp.Body.Close()
+		}
+		if success := e == nil; success != testCase.success {
+			t.Errorf("Test %d: expected success %t, got %v", i+1, testCase.success, e)
+		}
+	}
+
+	// Fingerprints which are not SHA-256 sums are rejected.
+	for _, fingerprint := range []string{"not hex", "AB:CD", serverFingerprint + ":00"} {
+		if err := setHostTLSConfig(&tls.Config{}, &Config{Fingerprint: fingerprint}); err == nil {
+			t.Errorf("expected an error for fingerprint %q", fingerprint)
+		}
+	}
+}
+
+func TestLoadHostRootCAs(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-certs-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(root, "config"))
+	if e = os.MkdirAll(mustGetCAsDir(), 0700); e != nil {
+		t.Fatal(e)
+	}
+
+	hostCA := filepath.Join(root, "host.pem")
+	hostCert := newTestCertificate(t, "host", hostCA)
+	configCert := newTestCertificate(t, "config", filepath.Join(mustGetCAsDir(), "config.pem"))
+	pool, err := loadHostRootCAs(hostCA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The host CA is added to the CAs of the config dir.
+	for _, cert := range []tls.Certificate{hostCert, configCert} {
+		if _, e = cert.Leaf.Verify(x509.VerifyOptions{Roots: pool}); e != nil {
+			t.Errorf("expected %s to be trusted, got %v", cert.Leaf.Subject.CommonName, e)
+		}
+	}
+	// And to the system CAs, which are kept.
+	if expected, got := len(mustGetSystemCertPool().Subjects())+2, len(pool.Subjects()); got != expected {
+		t.Errorf("expected %d CAs, got %d", expected, got)
+	}
+
+	if _, err = loadHostRootCAs(filepath.Join(root, "missing.pem")); err == nil {
+		t.Error("expected an error for a missing CA file")
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"path/filepath"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+	"lukechampine.com/blake3"
+)
+
+var hashFlag = cli.StringFlag{
+	Name:  "hash",
+	Value: string(checksumSHA256),
+	Usage: "checksum algorithm: 'xxh64', 'blake3' or 'sha256', xxh64 and blake3 are faster on fast drives",
+}
+
+// checksumAlgorithm - a hash the content of objects is compared by.
+type checksumAlgorithm string
+
+const (
+	checksumSHA256 checksumAlgorithm = "sha256"
+	checksumBLAKE3 checksumAlgorithm = "blake3"
+	// Not cryptographic, fastest to detect changes and corruption but
+	// not tampering.
+	checksumXXH64 checksumAlgorithm = "xxh64"
+)
+
+// checksumAlgorithms - all algorithms, in the order preferred to verify
+// manifest entries with several sums.
+var checksumAlgorithms = []checksumAlgorithm{checksumSHA256, checksumBLAKE3, checksumXXH64}
+
+// parseChecksumAlgorithm - the algorithm of a --hash value.
+func parseChecksumAlgorithm(name string) (checksumAlgorithm, *probe.Error) {
+	for _, algorithm := range checksumAlgorithms {
+		if checksumAlgorithm(name) == algorithm {
+			return algorithm, nil
+		}
+	}
+	return "", errInvalidArgument().Trace(name)
+}
+
+// getChecksumAlgorithm - the algorithm of --hash.
+func getChecksumAlgorithm(ctx *cli.Context) checksumAlgorithm {
+	algorithm, err := parseChecksumAlgorithm(ctx.String("hash"))
+	fatalIf(err, "Unknown checksum algorithm `"+ctx.String("hash")+"`, use 'xxh64', 'blake3' or 'sha256'.")
+	return algorithm
+}
+
+// New - a new hash of the algorithm. xxh64 sums are big endian, like
+// those printed by 'xxhsum'.
+func (a checksumAlgorithm) New() hash.Hash {
+	switch a {
+	case checksumBLAKE3:
+		return blake3.New(32, nil)
+	case checksumXXH64:
+		return xxhash.New()
+	}
+	return sha256.New()
+}
+
+// sumLength - the length of the hex sums of the algorithm.
+func (a checksumAlgorithm) sumLength() int {
+	if a == checksumXXH64 {
+		return 16
+	}
+	return 64
+}
+
+// contentChecksums - sums of the content at URL by each of algorithms,
+// read in full once.
+func contentChecksums(alias, urlStr string, encKeyDB map[string][]prefixSSEPair, algorithms ...checksumAlgorithm) ([]string, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	var sse encrypt.ServerSide
+	if clnt.GetURL().Type == objectStorage {
+		sse = getSSE(filepath.ToSlash(filepath.Join(alias, clnt.GetURL().Path)), encKeyDB[alias])
+	}
+	reader, err := clnt.Get(sse)
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	defer reader.Close()
+	hashes := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, len(algorithms))
+	for i, algorithm := range algorithms {
+		hashes[i] = algorithm.New()
+		writers[i] = hashes[i]
+	}
+	if _, e := io.Copy(io.MultiWriter(writers...), reader); e != nil {
+		return nil, probe.NewError(e).Trace(urlStr)
+	}
+	sums := make([]string, len(hashes))
+	for i, h := range hashes {
+		sums[i] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
+
+// contentChecksum - sum of the content at URL by algorithm, read in full.
+func contentChecksum(alias, urlStr string, encKeyDB map[string][]prefixSSEPair, algorithm checksumAlgorithm) (string, *probe.Error) {
+	sums, err := contentChecksums(alias, urlStr, encKeyDB, algorithm)
+	if err != nil {
+		return "", err
+	}
+	return sums[0], nil
+}
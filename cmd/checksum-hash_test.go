@@ -0,0 +1,83 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestChecksumAlgorithm(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		return newConfigV9(), nil
+	}
+
+	testCases := []struct {
+		name    string
+		content string
+		sum     string
+		success bool
+	}{
+		{"sha256", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", true},
+		{"blake3", "", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262", true},
+		{"xxh64", "", "ef46db3751d8e999", true},
+		{"md5", "", "", false},
+		{"SHA256", "", "", false},
+	}
+	root, e := ioutil.TempDir("", "mc-checksum-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+
+	for i, testCase := range testCases {
+		algorithm, err := parseChecksumAlgorithm(testCase.name)
+		if err != nil {
+			if testCase.success {
+				t.Errorf("Test %d: Unexpected error %s", i+1, err)
+			}
+			continue
+		}
+		if !testCase.success {
+			t.Errorf("Test %d: Expected to fail", i+1)
+			continue
+		}
+		if len(testCase.sum) != algorithm.sumLength() {
+			t.Errorf("Test %d: Expected a sum of %d, got %d", i+1, len(testCase.sum), algorithm.sumLength())
+		}
+		path := filepath.Join(root, testCase.name)
+		if e = ioutil.WriteFile(path, []byte(testCase.content), 0600); e != nil {
+			t.Fatal(e)
+		}
+		sums, err := contentChecksums("", path, nil, algorithm, checksumSHA256)
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error %s", i+1, err)
+		}
+		if sums[0] != testCase.sum {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.sum, sums[0])
+		}
+		if sum, _ := contentChecksum("", path, nil, checksumSHA256); sums[1] != sum {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, sum, sums[1])
+		}
+	}
+}
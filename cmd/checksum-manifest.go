@@ -0,0 +1,242 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var writeManifestFlag = cli.StringFlag{
+	Name:  "write-manifest",
+	Usage: "write the name, size and checksum of the objects copied to FILE as JSON lines, to check them with 'mc verify --manifest FILE'",
+}
+
+// checksumManifestEntry - an object of a checksum manifest, its name is
+// relative to the folder of the manifest and separated by slashes. An
+// entry has the sum of one algorithm or more.
+type checksumManifestEntry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+	BLAKE3 string `json:"blake3,omitempty"`
+	XXH64  string `json:"xxh64,omitempty"`
+}
+
+// newChecksumManifestEntry - an entry with the sum of algorithm.
+func newChecksumManifestEntry(name string, size int64, algorithm checksumAlgorithm, sum string) checksumManifestEntry {
+	entry := checksumManifestEntry{Name: name, Size: size}
+	*entry.sumOf(algorithm) = sum
+	return entry
+}
+
+// sumOf - the field of the sum of algorithm.
+func (m *checksumManifestEntry) sumOf(algorithm checksumAlgorithm) *string {
+	switch algorithm {
+	case checksumBLAKE3:
+		return &m.BLAKE3
+	case checksumXXH64:
+		return &m.XXH64
+	}
+	return &m.SHA256
+}
+
+// checksum - the sum of algorithm, or without an algorithm the sum of the
+// first algorithm the entry has a sum of. The sum is empty if missing.
+func (m checksumManifestEntry) checksum(algorithm checksumAlgorithm) (checksumAlgorithm, string) {
+	if algorithm != "" {
+		return algorithm, *m.sumOf(algorithm)
+	}
+	for _, algorithm := range checksumAlgorithms {
+		if sum := *m.sumOf(algorithm); sum != "" {
+			return algorithm, sum
+		}
+	}
+	return checksumSHA256, ""
+}
+
+// String one JSON line, an output which is a manifest.
+func (m checksumManifestEntry) String() string {
+	entryBytes, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(entryBytes)
+}
+
+// JSON is the same JSON line, with or without --json.
+func (m checksumManifestEntry) JSON() string {
+	return m.String()
+}
+
+// relativeKey - the key of u below root, separated by slashes.
+func relativeKey(root, u clientURL) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(u.Path, root.Path), string(root.Separator))
+	return strings.Replace(rel, string(root.Separator), "/", -1)
+}
+
+// checksumManifest - writes the objects copied below root, one JSON
+// line each.
+type checksumManifest struct {
+	mutex     sync.Mutex
+	file      *os.File
+	root      clientURL
+	algorithm checksumAlgorithm
+}
+
+// newChecksumManifest - create a manifest of the objects copied below
+// root, with their sums by algorithm. A nil manifest is returned for an
+// empty path.
+func newChecksumManifest(path string, root clientURL, algorithm checksumAlgorithm) (*checksumManifest, *probe.Error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, e := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	return &checksumManifest{file: file, root: root, algorithm: algorithm}, nil
+}
+
+// add - write an object copied to u.
+func (m *checksumManifest) add(u clientURL, size int64, sum string) {
+	if m == nil {
+		return
+	}
+	line := newChecksumManifestEntry(relativeKey(m.root, u), size, m.algorithm, sum).String()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, e := m.file.WriteString(line + "\n")
+	errorIf(probe.NewError(e).Trace(m.file.Name()), "Unable to write to the manifest.")
+}
+
+// Close - close the manifest.
+func (m *checksumManifest) Close() error {
+	if m == nil {
+		return nil
+	}
+	return m.file.Close()
+}
+
+// parseChecksumManifest - the entries of a manifest, JSON lines written
+// by mc, or the lines of 'sha256sum', 'b3sum' or 'xxhsum' which have no
+// size and sums of algorithm.
+func parseChecksumManifest(reader io.Reader, algorithm checksumAlgorithm) ([]checksumManifestEntry, *probe.Error) {
+	var entries []checksumManifestEntry
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry checksumManifestEntry
+		if strings.HasPrefix(line, "{") {
+			if e := json.Unmarshal([]byte(line), &entry); e != nil {
+				return nil, probe.NewError(e).Trace(line)
+			}
+		} else {
+			// '<sum>  <name>', with a '*' before names read in
+			// binary mode.
+			fields := strings.SplitN(line, " ", 2)
+			if len(fields) != 2 {
+				return nil, errInvalidArgument().Trace(line)
+			}
+			name := strings.TrimPrefix(strings.TrimPrefix(fields[1], " "), "*")
+			entry = newChecksumManifestEntry(name, -1, algorithm, fields[0])
+		}
+		if _, sum := entry.checksum(""); entry.Name == "" || sum == "" {
+			return nil, errInvalidArgument().Trace(line)
+		}
+		for _, algorithm := range checksumAlgorithms {
+			sum := entry.sumOf(algorithm)
+			if *sum != "" && len(*sum) != algorithm.sumLength() {
+				return nil, errInvalidArgument().Trace(line)
+			}
+			*sum = strings.ToLower(*sum)
+		}
+		entries = append(entries, entry)
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return entries, nil
+}
+
+// readChecksumManifest - the entries of the manifest at path, '-' reads
+// the standard input.
+func readChecksumManifest(path string, algorithm checksumAlgorithm) ([]checksumManifestEntry, *probe.Error) {
+	if path == "-" {
+		return parseChecksumManifest(os.Stdin, algorithm)
+	}
+	file, e := os.Open(path)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	defer file.Close()
+	entries, err := parseChecksumManifest(file, algorithm)
+	if err != nil {
+		return nil, err.Trace(path)
+	}
+	return entries, nil
+}
+
+// doChecksumManifest - print the manifest of the objects listed by clnt,
+// with their sums by algorithm. Like doList names are relative to the
+// listed folder.
+func doChecksumManifest(clnt Client, alias string, isRecursive bool, olderThan, newerThan string, algorithm checksumAlgorithm) error {
+	prefixPath := clnt.GetURL().Path
+	separator := string(clnt.GetURL().Separator)
+	if !strings.HasSuffix(prefixPath, separator) {
+		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
+	}
+	prefixPath = strings.TrimPrefix(filepath.ToSlash(prefixPath), "."+separator)
+	fs := &failureStatus{}
+	for content := range clnt.List(globalContext, isRecursive, false, DirNone) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			fs.fail(content.Err)
+			continue
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		if olderThan != "" && isOlder(content.Time, olderThan) {
+			continue
+		}
+		if newerThan != "" && isNewer(content.Time, newerThan) {
+			continue
+		}
+		sum, err := contentChecksum(alias, content.URL.String(), nil, algorithm)
+		if err != nil {
+			errorIf(err.Trace(content.URL.String()), "Unable to read `%s`.", content.URL.String())
+			fs.fail(err)
+			continue
+		}
+		name := strings.TrimPrefix(filepath.ToSlash(content.URL.Path), prefixPath)
+		printMsg(newChecksumManifestEntry(name, content.Size, algorithm, sum))
+		fs.success()
+	}
+	return fs.exitError()
+}
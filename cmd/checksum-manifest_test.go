@@ -0,0 +1,80 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksumManifest(t *testing.T) {
+	sumA := strings.Repeat("a", 64)
+	sumB := strings.Repeat("b", 64)
+	sumC := strings.Repeat("c", 16)
+	testCases := []struct {
+		manifest  string
+		algorithm checksumAlgorithm
+		entries   []checksumManifestEntry
+		success   bool
+	}{
+		{"", checksumSHA256, nil, true},
+		{`{"name":"a.txt","size":2,"sha256":"` + sumA + `"}` + "\n\n" + `{"name":"dir/b c.txt","size":0,"sha256":"` + sumB + `"}` + "\n", checksumSHA256,
+			[]checksumManifestEntry{{Name: "a.txt", Size: 2, SHA256: sumA}, {Name: "dir/b c.txt", Size: 0, SHA256: sumB}}, true},
+		// Lines of sha256sum, in text and binary mode.
+		{sumA + "  a.txt\r\n" + strings.ToUpper(sumB) + " *dir/b c.txt \n", checksumSHA256,
+			[]checksumManifestEntry{{Name: "a.txt", Size: -1, SHA256: sumA}, {Name: "dir/b c.txt ", Size: -1, SHA256: sumB}}, true},
+		// JSON lines with other sums, whatever the algorithm of lines.
+		{`{"name":"a.txt","size":2,"xxh64":"` + sumC + `","blake3":"` + sumB + `"}`, checksumSHA256,
+			[]checksumManifestEntry{{Name: "a.txt", Size: 2, BLAKE3: sumB, XXH64: sumC}}, true},
+		// Lines of b3sum and xxhsum.
+		{sumA + "  a.txt", checksumBLAKE3, []checksumManifestEntry{{Name: "a.txt", Size: -1, BLAKE3: sumA}}, true},
+		{sumC + "  a.txt", checksumXXH64, []checksumManifestEntry{{Name: "a.txt", Size: -1, XXH64: sumC}}, true},
+		{sumC + "  a.txt", checksumSHA256, nil, false},
+		{`{"name":"a.txt","size":2,"xxh64":"` + sumA + `"}`, checksumSHA256, nil, false},
+		{`{"name":"a.txt","size":2}`, checksumSHA256, nil, false},
+		{`{"name":"a.txt","size":2,"sha256":"abc"}`, checksumSHA256, nil, false},
+		{`{"name":"","size":2,"sha256":"` + sumA + `"}`, checksumSHA256, nil, false},
+		{`{"name":"a.txt",`, checksumSHA256, nil, false},
+		{sumA, checksumSHA256, nil, false},
+		{"abc  a.txt", checksumSHA256, nil, false},
+	}
+	for i, testCase := range testCases {
+		entries, err := parseChecksumManifest(strings.NewReader(testCase.manifest), testCase.algorithm)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+		if testCase.success && !reflect.DeepEqual(entries, testCase.entries) {
+			t.Fatalf("Test %d: expected %+v, got %+v", i+1, testCase.entries, entries)
+		}
+	}
+}
+
+func TestRelativeKey(t *testing.T) {
+	testCases := []struct {
+		root, url, key string
+	}{
+		{"play/bucket/prefix", "play/bucket/prefix/dir/a.txt", "dir/a.txt"},
+		{"play/bucket/prefix/", "play/bucket/prefix/a.txt", "a.txt"},
+		{"/tmp/target", "/tmp/target/dir/a.txt", "dir/a.txt"},
+	}
+	for i, testCase := range testCases {
+		if key := relativeKey(*newClientURL(testCase.root), *newClientURL(testCase.url)); key != testCase.key {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.key, key)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/ioutils"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/quick"
+)
+
+var (
+	cleanFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "remove local state older than L days, M hours and N minutes",
+			Value: "30d",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only report what would be removed",
+		},
+	}
+)
+
+var cleanCmd = cli.Command{
+	Name:   "clean",
+	Usage:  "remove stale sessions, expired shares and caches",
+	Action: mainClean,
+	Before: setGlobalsFromContext,
+	Flags:  append(cleanFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   Removes saved sessions which were not resumed, session data left behind
+   without a session, profiling output, the listings cached by 'mirror'
+   and the cache of bucket regions, and expired entries of the share
+   database. Only local state under the configuration folder is touched,
+   except for the unfinished multipart uploads of removed sessions which
+   are aborted like 'mc session clear' does.
+
+   --older-than accepts the string for days, hours and minutes
+   i.e. 1d2h30m states 1 day, 2 hours and 30 minutes.
+
+EXAMPLES:
+   1. Remove local state older than 30 days.
+      $ {{.HelpName}}
+
+   2. Show what would be removed if local state older than a week is cleaned.
+      $ {{.HelpName}} --dry-run --older-than 7d
+
+   3. Remove all sessions, orphaned session data, profiles and caches regardless of age.
+      $ {{.HelpName}} --older-than 0d
+`,
+}
+
+// Kinds of local state removed by clean.
+const (
+	cleanSession        = "session"
+	cleanSessionData    = "orphaned-session-data"
+	cleanSessionUploads = "orphaned-session-uploads"
+	cleanShare          = "expired-share"
+	cleanProfile        = "profile"
+	cleanCache          = "cache"
+	cleanStatusRemoved  = "removed"
+	cleanStatusDryRun   = "dry-run"
+)
+
+// cleanMessage container for each removed piece of local state.
+type cleanMessage struct {
+	Status string `json:"status"`
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size,omitempty"`
+}
+
+// String colorized clean message.
+func (c cleanMessage) String() string {
+	verb := "Removed"
+	if c.Status == cleanStatusDryRun {
+		verb = "Would remove"
+	}
+	return console.Colorize("Clean", fmt.Sprintf("%s %s `%s`.", verb, strings.Replace(c.Type, "-", " ", -1), c.Path))
+}
+
+// JSON jsonified clean message.
+func (c cleanMessage) JSON() string {
+	cleanJSONBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(cleanJSONBytes)
+}
+
+// cleanSummaryMessage container for the clean totals.
+type cleanSummaryMessage struct {
+	Status  string `json:"status"`
+	DryRun  bool   `json:"dryRun"`
+	Removed int    `json:"removed"`
+	Size    int64  `json:"size"`
+}
+
+// String colorized clean summary message.
+func (c cleanSummaryMessage) String() string {
+	if c.Removed == 0 {
+		return console.Colorize("CleanSummary", "Nothing to clean.")
+	}
+	if c.DryRun {
+		return console.Colorize("CleanSummary", fmt.Sprintf("Would remove %d item(s), freeing %s.", c.Removed, humanize.IBytes(uint64(c.Size))))
+	}
+	return console.Colorize("CleanSummary", fmt.Sprintf("Removed %d item(s), freed %s.", c.Removed, humanize.IBytes(uint64(c.Size))))
+}
+
+// JSON jsonified clean summary message.
+func (c cleanSummaryMessage) JSON() string {
+	c.Status = "success"
+	cleanJSONBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(cleanJSONBytes)
+}
+
+// staleFile - local state file eligible for removal.
+type staleFile struct {
+	kind string
+	path string
+	size int64
+}
+
+// findStaleSessions returns session files and orphaned session data and
+// uploads files last modified before olderThan. A data or uploads file
+// without its session file can never be resumed.
+func findStaleSessions(sessionDir string, olderThan time.Time) (stale []staleFile, err *probe.Error) {
+	entries, e := filepath.Glob(filepath.Join(sessionDir, "*"))
+	if e != nil {
+		return nil, probe.NewError(e).Trace(sessionDir)
+	}
+	for _, path := range entries {
+		st, e := os.Stat(path)
+		if e != nil || !st.Mode().IsRegular() || !st.ModTime().Before(olderThan) {
+			continue
+		}
+		switch filepath.Ext(path) {
+		case ".json":
+			stale = append(stale, staleFile{cleanSession, path, st.Size()})
+			for _, ext := range []string{".data", ".uploads"} {
+				if extSt, e := os.Stat(strings.TrimSuffix(path, ".json") + ext); e == nil {
+					stale[len(stale)-1].size += extSt.Size()
+				}
+			}
+		case ".data":
+			if _, e := os.Stat(strings.TrimSuffix(path, ".data") + ".json"); os.IsNotExist(e) {
+				stale = append(stale, staleFile{cleanSessionData, path, st.Size()})
+			}
+		case ".uploads":
+			if _, e := os.Stat(strings.TrimSuffix(path, ".uploads") + ".json"); os.IsNotExist(e) {
+				stale = append(stale, staleFile{cleanSessionUploads, path, st.Size()})
+			}
+		}
+	}
+	return stale, nil
+}
+
+// removeStaleFile removes a stale file. Sessions go the way of 'mc
+// session clear', their data and uploads along with them, and the
+// multipart uploads left in orphaned uploads files are aborted.
+func removeStaleFile(f staleFile) *probe.Error {
+	sid := strings.TrimSuffix(filepath.Base(f.path), filepath.Ext(f.path))
+	switch f.kind {
+	case cleanSession:
+		session, _ := loadSessionV8(sid)
+		removeSession(sid, session)
+		// Left behind only if it cannot be removed.
+		if e := os.Remove(f.path); e != nil && !os.IsNotExist(e) {
+			return probe.NewError(e)
+		}
+		return nil
+	case cleanSessionUploads:
+		return removeSessionUploads(sid)
+	}
+	if e := os.Remove(f.path); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// findStaleProfiles returns profiling output last modified before olderThan.
+func findStaleProfiles(profileDir string, olderThan time.Time) (stale []staleFile) {
+	filepath.Walk(profileDir, func(path string, info os.FileInfo, e error) error {
+		if e != nil {
+			return nil
+		}
+		if info.Mode().IsRegular() && info.ModTime().Before(olderThan) {
+			stale = append(stale, staleFile{cleanProfile, path, info.Size()})
+		}
+		return nil
+	})
+	return stale
+}
+
+// findStaleCaches returns mirror listing caches and the bucket region
+// cache last modified before olderThan, a region cache still in use is
+// kept whole.
+func findStaleCaches(configDir string, olderThan time.Time) (stale []staleFile) {
+	filepath.Walk(filepath.Join(configDir, globalMirrorCacheDir), func(path string, info os.FileInfo, e error) error {
+		if e != nil {
+			return nil
+		}
+		if info.Mode().IsRegular() && info.ModTime().Before(olderThan) {
+			stale = append(stale, staleFile{cleanCache, path, info.Size()})
+		}
+		return nil
+	})
+	path := filepath.Join(configDir, regionCacheFile)
+	if st, e := os.Stat(path); e == nil && st.Mode().IsRegular() && st.ModTime().Before(olderThan) {
+		stale = append(stale, staleFile{cleanCache, path, st.Size()})
+	}
+	return stale
+}
+
+// cleanShareDB removes expired entries from a share database and returns
+// the share URLs removed.
+func cleanShareDB(filename string, isDryRun bool) ([]string, *probe.Error) {
+	if _, e := os.Stat(filename); e != nil {
+		if os.IsNotExist(e) {
+			return nil, nil
+		}
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	qs, e := quick.NewConfig(newShareDBV1(), nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Load(filename); e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	db := qs.Data().(*shareDBV1)
+
+	var expired []string
+	for shareURL, share := range db.Shares {
+		if (share.Expiry - time.Since(share.Date)) <= 0 {
+			expired = append(expired, shareURL)
+		}
+	}
+	if len(expired) == 0 || isDryRun {
+		return expired, nil
+	}
+	db.deleteAllExpired()
+	return expired, db.Save(filename)
+}
+
+// checkCleanSyntax - validate all the passed arguments
+func checkCleanSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "clean", 1) // last argument is exit code
+	}
+	if _, e := ioutils.ParseDurationTime(ctx.String("older-than")); e != nil {
+		fatalIf(probe.NewError(e), "Unable to parse older-than=`"+ctx.String("older-than")+"`.")
+	}
+}
+
+// mainClean is the handle for "mc clean" command.
+func mainClean(ctx *cli.Context) error {
+	checkCleanSyntax(ctx)
+
+	console.SetColor("Clean", color.New(color.FgGreen))
+	console.SetColor("CleanSummary", color.New(color.FgGreen, color.Bold))
+
+	isDryRun := ctx.Bool("dry-run")
+	olderThanDuration, _ := ioutils.ParseDurationTime(ctx.String("older-than"))
+	olderThan := time.Now().Add(-olderThanDuration)
+
+	status := cleanStatusRemoved
+	if isDryRun {
+		status = cleanStatusDryRun
+	}
+	summary := cleanSummaryMessage{DryRun: isDryRun}
+
+	var stale []staleFile
+	if isSessionDirExists() {
+		sessionDir, err := getSessionDir()
+		fatalIf(err.Trace(), "Unable to access session folder.")
+		sessions, err := findStaleSessions(sessionDir, olderThan)
+		fatalIf(err.Trace(sessionDir), "Unable to access session folder `"+sessionDir+"`.")
+		stale = append(stale, sessions...)
+	}
+	stale = append(stale, findStaleProfiles(mustGetProfileDir(), olderThan)...)
+	stale = append(stale, findStaleCaches(mustGetMcConfigDir(), olderThan)...)
+
+	for _, f := range stale {
+		if !isDryRun {
+			if err := removeStaleFile(f); err != nil {
+				errorIf(err.Trace(f.path), "Unable to remove `"+f.path+"`.")
+				continue
+			}
+		}
+		printMsg(cleanMessage{Status: status, Type: f.kind, Path: f.path, Size: f.size})
+		summary.Removed++
+		summary.Size += f.size
+	}
+
+	if isShareDirExists() {
+		for _, shareFile := range []string{getShareUploadsFile(), getShareDownloadsFile()} {
+			expired, err := cleanShareDB(shareFile, isDryRun)
+			if err != nil {
+				errorIf(err.Trace(shareFile), "Unable to clean share database `"+shareFile+"`.")
+				continue
+			}
+			for _, shareURL := range expired {
+				printMsg(cleanMessage{Status: status, Type: cleanShare, Path: shareURL})
+				summary.Removed++
+			}
+		}
+	}
+
+	printSummaryMsg(summary)
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFindStaleSessions(t *testing.T) {
+	sessionDir, e := ioutil.TempDir("", "mc-clean-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(sessionDir)
+
+	old := time.Now().Add(-48 * time.Hour)
+	files := []struct {
+		name    string
+		modTime time.Time
+	}{
+		{"stale.json", old},
+		{"stale.data", old},
+		{"stale.uploads", old},
+		{"orphan.data", old},
+		{"orphan.uploads", old},
+		{"recent.json", time.Now()},
+		{"recent.data", time.Now()},
+		{"recent-orphan.data", time.Now()},
+	}
+	for _, f := range files {
+		path := filepath.Join(sessionDir, f.name)
+		if e = ioutil.WriteFile(path, []byte("{}"), 0600); e != nil {
+			t.Fatal(e)
+		}
+		if e = os.Chtimes(path, f.modTime, f.modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	stale, err := findStaleSessions(sessionDir, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]staleFile{
+		filepath.Join(sessionDir, "stale.json"):     {cleanSession, filepath.Join(sessionDir, "stale.json"), 6},
+		filepath.Join(sessionDir, "orphan.data"):    {cleanSessionData, filepath.Join(sessionDir, "orphan.data"), 2},
+		filepath.Join(sessionDir, "orphan.uploads"): {cleanSessionUploads, filepath.Join(sessionDir, "orphan.uploads"), 2},
+	}
+	if len(stale) != len(expected) {
+		t.Fatalf("Expected %d stale files, got %d: %v", len(expected), len(stale), stale)
+	}
+	for _, f := range stale {
+		if expected[f.path] != f {
+			t.Errorf("Unexpected stale file %v", f)
+		}
+	}
+}
+
+func TestRemoveStaleFile(t *testing.T) {
+	configDir, e := ioutil.TempDir("", "mc-clean-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(configDir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(configDir)
+	if err := createSessionDir(); err != nil {
+		t.Fatal(err)
+	}
+	sessionDir, err := getSessionDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploads := []byte(`{"version":"1","uploads":{}}`)
+	files := map[string][]byte{
+		"stale.json":     []byte("{}"),
+		"stale.data":     []byte("{}"),
+		"stale.uploads":  uploads,
+		"orphan.uploads": uploads,
+	}
+	for name, data := range files {
+		if e = ioutil.WriteFile(filepath.Join(sessionDir, name), data, 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	stale, err := findStaleSessions(sessionDir, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 2 {
+		t.Fatalf("Expected 2 stale files, got %d: %v", len(stale), stale)
+	}
+	for _, f := range stale {
+		if err = removeStaleFile(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name := range files {
+		if _, e = os.Stat(filepath.Join(sessionDir, name)); !os.IsNotExist(e) {
+			t.Errorf("Expected `%s` to be removed, got %v", name, e)
+		}
+	}
+}
+
+func TestFindStaleCaches(t *testing.T) {
+	configDir, e := ioutil.TempDir("", "mc-clean-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(configDir)
+	if e = os.MkdirAll(filepath.Join(configDir, globalMirrorCacheDir), 0700); e != nil {
+		t.Fatal(e)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	files := []struct {
+		name    string
+		modTime time.Time
+	}{
+		{filepath.Join(globalMirrorCacheDir, "stale.json"), old},
+		{filepath.Join(globalMirrorCacheDir, "recent.json"), time.Now()},
+		{regionCacheFile, old},
+	}
+	for _, f := range files {
+		path := filepath.Join(configDir, f.name)
+		if e = ioutil.WriteFile(path, []byte("{}"), 0600); e != nil {
+			t.Fatal(e)
+		}
+		if e = os.Chtimes(path, f.modTime, f.modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	stale := findStaleCaches(configDir, time.Now().Add(-24*time.Hour))
+	expected := []staleFile{
+		{cleanCache, filepath.Join(configDir, globalMirrorCacheDir, "stale.json"), 2},
+		{cleanCache, filepath.Join(configDir, regionCacheFile), 2},
+	}
+	if !reflect.DeepEqual(stale, expected) {
+		t.Errorf("Expected %v, got %v", expected, stale)
+	}
+}
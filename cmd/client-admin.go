@@ -54,7 +54,8 @@ func newAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
+		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey +
+			config.CACert + config.ClientCert + config.ClientKey + config.Fingerprint + config.Proxy))
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -70,14 +71,22 @@ func newAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 				return nil, probe.NewError(e)
 			}
 
+			proxyFunc, err := getProxyFunc(config)
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
+			}
+
 			// Keep TLS config.
 			tlsConfig := &tls.Config{RootCAs: globalRootCAs}
 			if config.Insecure {
 				tlsConfig.InsecureSkipVerify = true
 			}
+			if err := setHostTLSConfig(tlsConfig, config); err != nil {
+				return nil, err.Trace(config.HostURL)
+			}
 
-			var transport http.RoundTripper = &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
+			transport := newContextTransport(&http.Transport{
+				Proxy: proxyFunc,
 				DialContext: (&net.Dialer{
 					Timeout:   30 * time.Second,
 					KeepAlive: 30 * time.Second,
@@ -87,10 +96,14 @@ func newAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 				TLSHandshakeTimeout:   10 * time.Second,
 				ExpectContinueTimeout: 1 * time.Second,
 				TLSClientConfig:       tlsConfig,
-			}
-
-			if config.Debug {
-				transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
+			})
+
+			if config.Debug || globalTraceFile != "" {
+				tracer, err := newHTTPTrace("S3v4", config.Debug)
+				if err != nil {
+					return nil, err.Trace(config.HostURL)
+				}
+				transport = httptracer.GetNewTraceTransport(tracer, transport)
 			}
 
 			// Set custom transport.
@@ -92,6 +92,16 @@ func (e ObjectOnGlacier) Error() string {
 	return "Object `" + e.Object + "` is on Glacier storage."
 }
 
+// UnsafeObjectName - object name cannot be used as a local path.
+type UnsafeObjectName struct {
+	Object string
+	Reason string
+}
+
+func (e UnsafeObjectName) Error() string {
+	return "Object `" + e.Object + "` " + e.Reason + ", it is not safe to use as a local path."
+}
+
 // BucketNameTopLevel - generic error
 type BucketNameTopLevel struct{}
 
@@ -191,3 +201,12 @@ type SameFile struct {
 func (e SameFile) Error() string {
 	return fmt.Sprintf("'%s' and '%s' are the same file", e.Source, e.Destination)
 }
+
+// ReadOnlyMode - modifying operations are refused in read-only mode.
+type ReadOnlyMode struct {
+	URL string
+}
+
+func (e ReadOnlyMode) Error() string {
+	return "`" + e.URL + "` cannot be modified in read-only mode."
+}
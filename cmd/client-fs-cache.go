@@ -0,0 +1,104 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"os"
+)
+
+const (
+	// Default size of the copy buffer for local files.
+	defaultFSBufferSize = 32 * 1024
+
+	// Sizes accepted by '--fs-buffer-size'.
+	minFSBufferSize = 4 * 1024
+	maxFSBufferSize = 1024 * 1024 * 1024
+
+	// Pages of local files read or written with '--fs-direct' are
+	// dropped from the page cache after every this many bytes.
+	fsDropCacheInterval = 16 * 1024 * 1024
+)
+
+// uncachedWriter drops the pages of a file written from offset on from
+// the page cache while writing.
+type uncachedWriter struct {
+	writer  io.Writer
+	file    *os.File
+	offset  int64
+	pending int64
+}
+
+func newUncachedWriter(writer io.Writer, file *os.File, offset int64) *uncachedWriter {
+	return &uncachedWriter{writer: writer, file: file, offset: offset}
+}
+
+// Write implements io.Writer.
+func (w *uncachedWriter) Write(p []byte) (int, error) {
+	n, e := w.writer.Write(p)
+	w.pending += int64(n)
+	if w.pending >= fsDropCacheInterval {
+		// Only an advice, caching the pages is not an error.
+		dropPageCache(w.file, w.offset, w.pending, true)
+		w.offset += w.pending
+		w.pending = 0
+	}
+	return n, e
+}
+
+// uncachedReader drops the pages of a file read from the page cache
+// while reading, and all its pages when closed.
+type uncachedReader struct {
+	*os.File
+	offset  int64
+	pending int64
+}
+
+func newUncachedReader(file *os.File) *uncachedReader {
+	return &uncachedReader{File: file}
+}
+
+// Read implements io.Reader.
+func (r *uncachedReader) Read(p []byte) (int, error) {
+	n, e := r.File.Read(p)
+	r.pending += int64(n)
+	if r.pending >= fsDropCacheInterval {
+		dropPageCache(r.File, r.offset, r.pending, false)
+		r.offset += r.pending
+		r.pending = 0
+	}
+	return n, e
+}
+
+// Close implements io.Closer.
+func (r *uncachedReader) Close() error {
+	dropPageCache(r.File, 0, 0, false)
+	return r.File.Close()
+}
+
+// openLocalFile - open a local file for reading, with '--fs-direct'
+// its pages are not kept in the page cache.
+func openLocalFile(fpath string) (io.ReadCloser, error) {
+	fileData, e := os.Open(toFSPath(fpath))
+	if e != nil {
+		return nil, e
+	}
+	if globalFSDirect {
+		return newUncachedReader(fileData), nil
+	}
+	return fileData, nil
+}
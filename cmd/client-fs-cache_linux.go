@@ -0,0 +1,37 @@
+// +build linux
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// dropPageCache - advise the kernel to drop cached pages of a range of
+// the file, a length of 0 drops everything from offset on. Written pages
+// are flushed first, dirty pages cannot be dropped.
+func dropPageCache(file *os.File, offset, length int64, isWritten bool) error {
+	if isWritten {
+		if e := unix.Fdatasync(int(file.Fd())); e != nil {
+			return e
+		}
+	}
+	return unix.Fadvise(int(file.Fd()), offset, length, unix.FADV_DONTNEED)
+}
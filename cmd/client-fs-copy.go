@@ -0,0 +1,85 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"syscall"
+
+	"github.com/pkg/xattr"
+)
+
+// localSourceFile - the file of a reader of a local regular file, the
+// data of which can be copied by the filesystem, nil otherwise.
+func localSourceFile(reader io.Reader) *os.File {
+	if uncached, ok := reader.(*uncachedReader); ok {
+		reader = uncached.File
+	}
+	file, ok := reader.(*os.File)
+	if !ok || isStdIO(reader) {
+		return nil
+	}
+	st, e := file.Stat()
+	if e != nil || !st.Mode().IsRegular() {
+		return nil
+	}
+	return file
+}
+
+// reportProgress - report n bytes copied without reading them.
+func reportProgress(progress io.Reader, n int64) error {
+	if progress == nil || n <= 0 {
+		return nil
+	}
+	_, e := io.CopyN(ioutil.Discard, progress, n)
+	return e
+}
+
+// isXattrIgnored - errors of extended attributes which are not copied,
+// on filesystems without extended attributes or of namespaces, like
+// trusted, which need privileges.
+func isXattrIgnored(e error) bool {
+	xe, ok := e.(*xattr.Error)
+	if !ok {
+		return false
+	}
+	return xe.Err == syscall.ENOTSUP || xe.Err == syscall.EOPNOTSUPP ||
+		xe.Err == syscall.EPERM || xe.Err == syscall.EACCES
+}
+
+// copyXattrs - copy the extended attributes of a file to another.
+func copyXattrs(dst, src *os.File) error {
+	names, e := xattr.FList(src)
+	if e != nil {
+		if isXattrIgnored(e) {
+			return nil
+		}
+		return e
+	}
+	for _, name := range names {
+		value, e := xattr.FGet(src, name)
+		if e == nil {
+			e = xattr.FSet(dst, name, value)
+		}
+		if e != nil && !isXattrIgnored(e) {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// FICLONE of linux/fs.h, clones a whole file on filesystems sharing
+// data between files, like btrfs and XFS.
+const ficlone = 0x40049409
+
+// Data is copied in the kernel in chunks of this size, so copies can
+// be cancelled and their progress reported.
+const copyFileRangeChunk = 64 * 1024 * 1024
+
+// isCopyFileRangeUnsupported - errors of copy_file_range on kernels or
+// filesystems which do not support it, like copies across filesystems
+// before Linux 5.3.
+func isCopyFileRangeUnsupported(e error) bool {
+	switch e {
+	case unix.ENOSYS, unix.EXDEV, unix.EINVAL, unix.EOPNOTSUPP, unix.EBADF:
+		return true
+	}
+	return false
+}
+
+// copyFileData - copy size bytes of src from its offset to the start of
+// dst in the filesystem, cloning whole files or copying them with
+// copy_file_range. Returns false when the filesystem can not copy them,
+// before anything is written.
+func copyFileData(ctx context.Context, dst, src *os.File, size int64, progress io.Reader) (int64, bool, error) {
+	offset, e := src.Seek(0, io.SeekCurrent)
+	if e != nil || size <= 0 {
+		return 0, false, nil
+	}
+	if st, e := src.Stat(); e == nil && offset == 0 && st.Size() == size {
+		if _, _, errno := unix.Syscall(unix.SYS_IOCTL, dst.Fd(), ficlone, src.Fd()); errno == 0 {
+			return size, true, reportProgress(progress, size)
+		}
+	}
+	var n int64
+	for n < size {
+		if e = ctx.Err(); e != nil {
+			return n, true, e
+		}
+		readOffset, writeOffset := offset+n, n
+		chunk := size - n
+		if chunk > copyFileRangeChunk {
+			chunk = copyFileRangeChunk
+		}
+		written, e := unix.CopyFileRange(int(src.Fd()), &readOffset, int(dst.Fd()), &writeOffset, int(chunk), 0)
+		if e != nil {
+			if n == 0 && isCopyFileRangeUnsupported(e) {
+				return 0, false, nil
+			}
+			return n, true, e
+		}
+		if written == 0 {
+			// The source is shorter than expected.
+			break
+		}
+		n += int64(written)
+		if e = reportProgress(progress, int64(written)); e != nil {
+			return n, true, e
+		}
+	}
+	return n, true, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/xattr"
+)
+
+func TestFSCopyLocalFile(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-fs-copy-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	source := filepath.Join(root, "source.bin")
+	data := bytes.Repeat([]byte("0123456789"), 100000)
+	if e = ioutil.WriteFile(source, data, 0600); e != nil {
+		t.Fatal(e)
+	}
+	// Extended attributes are checked where the filesystem has them.
+	hasXattrs := xattr.Set(source, "user.mc-test", []byte("blue")) == nil
+
+	testCases := []struct {
+		target string
+		direct bool
+	}{
+		{filepath.Join(root, "target.bin"), false},
+		{filepath.Join(root, "dir", "direct.bin"), true},
+	}
+	savedDirect := globalFSDirect
+	defer func() { globalFSDirect = savedDirect }()
+	for i, testCase := range testCases {
+		globalFSDirect = testCase.direct
+		clnt, err := fsNew(testCase.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var progress int64
+		if err = clnt.Copy(source, int64(len(data)), progressFunc(func(n int64) { progress += n }), nil, nil, nil); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		copied, e := ioutil.ReadFile(testCase.target)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !bytes.Equal(copied, data) || progress != int64(len(data)) {
+			t.Errorf("Test %d: expected %d bytes copied and reported, got %d and %d", i+1, len(data), len(copied), progress)
+		}
+		if !hasXattrs {
+			continue
+		}
+		if value, e := xattr.Get(testCase.target, "user.mc-test"); e != nil || string(value) != "blue" {
+			t.Errorf("Test %d: expected the extended attribute to be copied, got %q, %v", i+1, value, e)
+		}
+	}
+}
+
+// progressFunc reports the bytes read through a hook reader.
+type progressFunc func(n int64)
+
+func (f progressFunc) Read(p []byte) (int, error) {
+	f(int64(len(p)))
+	return len(p), nil
+}
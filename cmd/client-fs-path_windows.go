@@ -0,0 +1,61 @@
+// +build windows
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Paths this long are beyond MAX_PATH once a file name is added.
+const windowsMaxPath = 248
+
+// toFSPath - the path to open a local file with. Long paths and paths
+// with reserved device names or trailing dots are only reachable with
+// the '\\?\' prefix, which requires an absolute path.
+func toFSPath(path string) string {
+	if path == "" || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	if len(path) < windowsMaxPath && !hasWindowsReservedName(path) {
+		return path
+	}
+	absPath, e := filepath.Abs(path)
+	if e != nil {
+		return path
+	}
+	if strings.HasPrefix(absPath, `\\`) {
+		return `\\?\UNC\` + absPath[2:]
+	}
+	return `\\?\` + absPath
+}
+
+// hasWindowsReservedName - returns true if a folder or file name of a
+// path is a reserved device name or ends with a dot or a space.
+func hasWindowsReservedName(path string) bool {
+	for _, name := range strings.FieldsFunc(path, func(r rune) bool { return r == '\\' || r == '/' }) {
+		if name == "." || name == ".." {
+			continue
+		}
+		if isWindowsReservedName(name) || strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+			return true
+		}
+	}
+	return false
+}
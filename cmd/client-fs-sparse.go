@@ -0,0 +1,73 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+)
+
+// Blocks of zeros at least this large are left as holes in the file.
+const sparseBlockSize = 4 * 1024
+
+var zeroBlock = make([]byte, sparseBlockSize)
+
+// sparseWriter writes to a file from an offset on, whole blocks of
+// zeros are skipped so the file system leaves holes in their place.
+type sparseWriter struct {
+	file   *os.File
+	offset int64
+	// Offset of the end of the last written block.
+	written int64
+}
+
+func newSparseWriter(file *os.File, offset int64) *sparseWriter {
+	return &sparseWriter{file: file, offset: offset, written: offset}
+}
+
+// Write implements io.Writer.
+func (w *sparseWriter) Write(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		block := p
+		if len(block) > sparseBlockSize {
+			block = block[:sparseBlockSize]
+		}
+		if len(block) < sparseBlockSize || !bytes.Equal(block, zeroBlock) {
+			n, e := w.file.WriteAt(block, w.offset)
+			total += n
+			w.offset += int64(n)
+			w.written = w.offset
+			if e != nil {
+				return total, e
+			}
+		} else {
+			w.offset += int64(len(block))
+			total += len(block)
+		}
+		p = p[len(block):]
+	}
+	return total, nil
+}
+
+// Close extends the file over a hole at its end, the file is not closed.
+func (w *sparseWriter) Close() error {
+	if w.offset > w.written {
+		return w.file.Truncate(w.offset)
+	}
+	return nil
+}
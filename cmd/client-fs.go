@@ -20,11 +20,11 @@ import (
 	"context"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
-	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -45,6 +45,10 @@ type fsClient struct {
 
 const (
 	partSuffix = ".part.minio"
+
+	// Part files not written to for longer are left behind by an
+	// abandoned copy, they are discarded instead of resumed.
+	partExpiry = 7 * 24 * time.Hour
 )
 
 var ( // GOOS specific ignore list.
@@ -228,16 +232,19 @@ func isStreamFile(objectPath string) bool {
 
 /// Object operations.
 
-func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]string, progress io.Reader) (int64, *probe.Error) {
+func (f *fsClient) put(ctx context.Context, reader io.Reader, size int64, metadata map[string][]string, progress io.Reader) (int64, *probe.Error) {
 	// ContentType is not handled on purpose.
 	// For filesystem this is a redundant information.
 
+	// Local files are copied by the filesystem if possible.
+	srcFile := localSourceFile(reader)
+
 	// Extract dir name.
 	objectDir, objectName := filepath.Split(f.PathURL.Path)
 
 	if objectDir != "" {
 		// Create any missing top level directories.
-		if e := os.MkdirAll(objectDir, 0777); e != nil {
+		if e := os.MkdirAll(toFSPath(objectDir), 0777); e != nil {
 			err := f.toClientError(e, f.PathURL.Path)
 			return 0, err.Trace(f.PathURL.Path)
 		}
@@ -256,15 +263,20 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 		objectPartPath = objectPath
 	}
 
-	// If exists, open in append mode. If not create it the part file.
-	partFile, e := os.OpenFile(objectPartPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	// If exists, the part file is resumed at its end. If not create it.
+	// Streams are written in append mode, part files are written sparse.
+	flag := os.O_CREATE | os.O_WRONLY
+	if avoidResumeUpload {
+		flag |= os.O_APPEND
+	}
+	partFile, e := os.OpenFile(toFSPath(objectPartPath), flag, 0666)
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return 0, err.Trace(f.PathURL.Path)
 	}
 
 	// Get stat to get the current size.
-	partSt, e := os.Stat(objectPartPath)
+	partSt, e := partFile.Stat()
 	if e != nil {
 		err := f.toClientError(e, objectPartPath)
 		return 0, err.Trace(objectPartPath)
@@ -274,6 +286,17 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 	// Current file offset.
 	var currentOffset = partSt.Size()
 
+	// A part file larger than the object or not written to for a long
+	// time belongs to another copy, start over instead of resuming it.
+	if !avoidResumeUpload && isStalePartFile(partSt, size) {
+		if e = partFile.Truncate(0); e != nil {
+			partFile.Close()
+			err := f.toClientError(e, objectPartPath)
+			return 0, err.Trace(objectPartPath)
+		}
+		currentOffset = 0
+	}
+
 	if !isStdIO(reader) && size > 0 {
 		reader = hookreader.NewHook(reader, progress)
 		if seeker, ok := reader.(io.Seeker); ok {
@@ -293,8 +316,34 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 		}
 	}
 
-	n, e := io.Copy(partFile, reader)
+	// Stop writing once the copy is cancelled.
+	copyReader := newContextReader(ctx, reader)
+
+	var n int64
+	var copied bool
+	if srcFile != nil && !avoidResumeUpload && currentOffset == 0 && !globalFSDirect {
+		n, copied, e = copyFileData(ctx, partFile, srcFile, size, progress)
+	}
+	buf := make([]byte, globalFSBufferSize)
+	switch {
+	case copied || e != nil:
+	case avoidResumeUpload:
+		n, e = io.CopyBuffer(partFile, copyReader, buf)
+	default:
+		sparseFile := newSparseWriter(partFile, currentOffset)
+		var writer io.Writer = sparseFile
+		if globalFSDirect {
+			writer = newUncachedWriter(sparseFile, partFile, currentOffset)
+		}
+		if n, e = io.CopyBuffer(writer, copyReader, buf); e == nil {
+			e = sparseFile.Close()
+		}
+	}
+	if e == nil && srcFile != nil {
+		e = copyXattrs(partFile, srcFile)
+	}
 	if e != nil {
+		partFile.Close()
 		return 0, probe.NewError(e)
 	}
 
@@ -309,6 +358,18 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 		}
 	}
 
+	// Flush the data to disk before rename, a crash after the rename
+	// must not leave a truncated file behind.
+	if !avoidResumeUpload {
+		if e = partFile.Sync(); e != nil {
+			partFile.Close()
+			return totalWritten, probe.NewError(e)
+		}
+		if globalFSDirect {
+			dropPageCache(partFile, 0, 0, false)
+		}
+	}
+
 	// Close the file before rename.
 	if e = partFile.Close(); e != nil {
 		return totalWritten, probe.NewError(e)
@@ -333,7 +394,7 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 	}
 	if !avoidResumeUpload {
 		// Safely completed put. Now commit by renaming to actual filename.
-		if e = os.Rename(objectPartPath, objectPath); e != nil {
+		if e = os.Rename(toFSPath(objectPartPath), toFSPath(objectPath)); e != nil {
 			err := f.toClientError(e, objectPath)
 			return totalWritten, err.Trace(objectPartPath, objectPath)
 		}
@@ -341,21 +402,41 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 	return totalWritten, nil
 }
 
+// isStalePartFile - a part file is only resumed when it is smaller than
+// the object and was written to recently.
+func isStalePartFile(partSt os.FileInfo, size int64) bool {
+	if partSt.Size() == 0 {
+		return false
+	}
+	if size > 0 && partSt.Size() > size {
+		return true
+	}
+	return time.Since(partSt.ModTime()) > partExpiry
+}
+
 // Put - create a new file with metadata.
 func (f *fsClient) Put(ctx context.Context, reader io.Reader, size int64, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide) (int64, *probe.Error) {
-	return f.put(reader, size, nil, progress)
+	return f.put(ctx, reader, size, nil, progress)
 }
 
 // ShareDownload - share download not implemented for filesystem.
-func (f *fsClient) ShareDownload(expires time.Duration) (string, *probe.Error) {
+func (f *fsClient) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
 	return "", probe.NewError(APINotImplemented{
 		API:     "ShareDownload",
 		APIType: "filesystem",
 	})
 }
 
+// ShareRequest - share requests not implemented for filesystem.
+func (f *fsClient) ShareRequest(method string, expires time.Duration, reqParams url.Values) (string, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "ShareRequest",
+		APIType: "filesystem",
+	})
+}
+
 // ShareUpload - share upload not implemented for filesystem.
-func (f *fsClient) ShareUpload(startsWith bool, expires time.Duration, contentType string) (string, map[string]string, *probe.Error) {
+func (f *fsClient) ShareUpload(startsWith bool, expires time.Duration, contentType string, maxSize int64) (string, map[string]string, *probe.Error) {
 	return "", nil, probe.NewError(APINotImplemented{
 		API:     "ShareUpload",
 		APIType: "filesystem",
@@ -370,15 +451,11 @@ func readFile(fpath string) (io.ReadCloser, error) {
 	if strings.HasSuffix(fpath, "/") {
 		fpath = fpath + "."
 	}
-	fpath, e := filepath.EvalSymlinks(fpath)
+	fpath, e := filepath.EvalSymlinks(toFSPath(fpath))
 	if e != nil {
 		return nil, e
 	}
-	fileData, e := os.Open(fpath)
-	if e != nil {
-		return nil, e
-	}
-	return fileData, nil
+	return openLocalFile(fpath)
 }
 
 // Copy - copy data from source to destination
@@ -391,7 +468,7 @@ func (f *fsClient) Copy(source string, size int64, progress io.Reader, srcSSE, t
 	}
 	defer rc.Close()
 
-	_, err := f.put(rc, size, map[string][]string{}, progress)
+	_, err := f.put(globalContext, rc, size, map[string][]string{}, progress)
 	if err != nil {
 		return err.Trace(destination, source)
 	}
@@ -408,12 +485,12 @@ func (f *fsClient) get() (io.ReadCloser, *probe.Error) {
 	}
 
 	// Resolve symlinks.
-	_, e := filepath.EvalSymlinks(tmppath)
+	_, e := filepath.EvalSymlinks(toFSPath(tmppath))
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return nil, err.Trace(f.PathURL.Path)
 	}
-	fileData, e := os.Open(f.PathURL.Path)
+	fileData, e := openLocalFile(f.PathURL.Path)
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return nil, err.Trace(f.PathURL.Path)
@@ -440,7 +517,7 @@ func (f *fsClient) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 			if isIncomplete {
 				name += partSuffix
 			}
-			if err := os.Remove(name); err != nil {
+			if err := os.Remove(toFSPath(name)); err != nil {
 				if os.IsPermission(err) {
 					// Ignore permission error.
 					errorCh <- probe.NewError(PathInsufficientPermission{Path: content.URL.Path})
@@ -455,8 +532,41 @@ func (f *fsClient) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 	return errorCh
 }
 
+// Trash - move files to the trash of the operating system instead of
+// removing them, directories emptied this way are removed.
+func (f *fsClient) Trash(contentCh <-chan *clientContent) <-chan *probe.Error {
+	errorCh := make(chan *probe.Error)
+
+	go func() {
+		defer close(errorCh)
+
+		for content := range contentCh {
+			name := content.URL.Path
+			st, e := os.Lstat(name)
+			if e == nil {
+				if st.IsDir() {
+					e = os.Remove(name)
+				} else {
+					e = moveToTrash(name)
+				}
+			}
+			if e != nil {
+				if os.IsPermission(e) {
+					// Ignore permission error.
+					errorCh <- probe.NewError(PathInsufficientPermission{Path: content.URL.Path})
+				} else {
+					errorCh <- probe.NewError(e)
+					return
+				}
+			}
+		}
+	}()
+
+	return errorCh
+}
+
 // List - list files and folders.
-func (f *fsClient) List(isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent {
+func (f *fsClient) List(ctx context.Context, isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent {
 	contentCh := make(chan *clientContent)
 	filteredCh := make(chan *clientContent)
 
@@ -472,9 +582,12 @@ func (f *fsClient) List(isRecursive, isIncomplete bool, showDir DirOpt) <-chan *
 
 	// This function filters entries from any  listing go routine
 	// created previously. If isIncomplete is activated, we will
-	// only show partly uploaded files,
+	// only show partly uploaded files, until ctx is cancelled.
 	go func() {
 		for c := range contentCh {
+			if ctx.Err() != nil {
+				continue
+			}
 			if isIncomplete {
 				if !strings.HasSuffix(c.URL.Path, partSuffix) {
 					continue
@@ -495,59 +608,14 @@ func (f *fsClient) List(isRecursive, isIncomplete bool, showDir DirOpt) <-chan *
 	return filteredCh
 }
 
-// byDirName implements sort.Interface.
-type byDirName []os.FileInfo
-
-func (f byDirName) Len() int { return len(f) }
-func (f byDirName) Less(i, j int) bool {
-	// For directory add an ending separator fortrue lexical
-	// order.
-	if f[i].Mode().IsDir() {
-		return f[i].Name()+string(filepath.Separator) < f[j].Name()
-	}
-	// For directory add an ending separator for true lexical
-	// order.
-	if f[j].Mode().IsDir() {
-		return f[i].Name() < f[j].Name()+string(filepath.Separator)
-	}
-	return f[i].Name() < f[j].Name()
-}
-func (f byDirName) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
-
-// readDir reads the directory named by dirname and returns
-// a list of sorted directory entries.
-func readDir(dirname string) ([]os.FileInfo, error) {
-	f, e := os.Open(dirname)
-	if e != nil {
-		return nil, e
-	}
-	list, e := f.Readdir(-1)
-	if e != nil {
-		return nil, e
-	}
-	if e = f.Close(); e != nil {
-		return nil, e
-	}
-	sort.Sort(byDirName(list))
-	return list, nil
-}
-
 // listPrefixes - list all files for any given prefix.
 func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent) {
 	dirName := filepath.Dir(prefix)
-	files, e := readDir(dirName)
-	if e != nil {
-		err := f.toClientError(e, dirName)
-		contentCh <- &clientContent{
-			Err: err.Trace(dirName),
-		}
-		return
-	}
 	pathURL := *f.PathURL
-	for _, fi := range files {
+	e := ioutils.ReadDirFunc(dirName, func(fi os.FileInfo) error {
 		// Skip ignored files.
 		if isIgnoredFile(fi.Name()) {
-			continue
+			return nil
 		}
 
 		file := filepath.Join(dirName, fi.Name())
@@ -560,7 +628,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent)
 							Path: pathURL.Path,
 						}),
 					}
-					continue
+					return nil
 				}
 				if os.IsNotExist(e) {
 					contentCh <- &clientContent{
@@ -568,13 +636,13 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent)
 							Path: pathURL.Path,
 						}),
 					}
-					continue
+					return nil
 				}
 				if e != nil {
 					contentCh <- &clientContent{
 						Err: probe.NewError(e),
 					}
-					continue
+					return nil
 				}
 			}
 			if strings.HasPrefix(file, prefix) {
@@ -585,7 +653,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent)
 					Type: st.Mode(),
 					Err:  nil,
 				}
-				continue
+				return nil
 			}
 		}
 		if strings.HasPrefix(file, prefix) {
@@ -597,6 +665,13 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent)
 				Err:  nil,
 			}
 		}
+		return nil
+	})
+	if e != nil {
+		err := f.toClientError(e, dirName)
+		contentCh <- &clientContent{
+			Err: err.Trace(dirName),
+		}
 	}
 }
 
@@ -631,21 +706,16 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent) {
 	// If we really see the directory.
 	switch fst.Mode().IsDir() {
 	case true:
-		files, e := readDir(fpath)
-		if err != nil {
-			contentCh <- &clientContent{Err: probe.NewError(e)}
-			return
-		}
-		for _, file := range files {
-			fi := file
+		e := ioutils.ReadDirFunc(fpath, func(fi os.FileInfo) error {
 			if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
 				fp := filepath.Join(fpath, fi.Name())
+				var e error
 				fi, e = os.Stat(fp)
 				if os.IsPermission(e) {
 					contentCh <- &clientContent{
 						Err: probe.NewError(PathInsufficientPermission{Path: pathURL.Path}),
 					}
-					continue
+					return nil
 				}
 				if os.IsNotExist(e) {
 					// Lstat makes no attempt to follow the broken link.
@@ -655,13 +725,13 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent) {
 						Size: -1,
 						Err:  probe.NewError(e),
 					}
-					continue
+					return nil
 				}
 				if e != nil {
 					contentCh <- &clientContent{
 						Err: probe.NewError(e),
 					}
-					continue
+					return nil
 				}
 			}
 			if fi.Mode().IsRegular() || fi.Mode().IsDir() {
@@ -670,7 +740,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent) {
 
 				// Skip ignored files.
 				if isIgnoredFile(fi.Name()) {
-					continue
+					return nil
 				}
 
 				contentCh <- &clientContent{
@@ -681,6 +751,10 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent) {
 					Err:  nil,
 				}
 			}
+			return nil
+		})
+		if e != nil {
+			contentCh <- &clientContent{Err: probe.NewError(e)}
 		}
 	default:
 		contentCh <- &clientContent{
@@ -707,18 +781,7 @@ func (f *fsClient) listDirOpt(contentCh chan *clientContent, isIncomplete bool,
 	// Closure function reads currentPath and sends to contentCh. If a directory is found, it lists the directory content recursively.
 	var listDir func(currentPath string) bool
 	listDir = func(currentPath string) (isStop bool) {
-		files, err := readDir(currentPath)
-		if err != nil {
-			if os.IsPermission(err) {
-				contentCh <- &clientContent{Err: probe.NewError(PathInsufficientPermission{Path: currentPath})}
-				return false
-			}
-
-			contentCh <- &clientContent{Err: probe.NewError(err)}
-			return true
-		}
-
-		for _, file := range files {
+		err := ioutils.ReadDirFunc(currentPath, func(file os.FileInfo) error {
 			name := filepath.Join(currentPath, file.Name())
 			content := clientContent{
 				URL:  *newClientURL(name),
@@ -732,19 +795,30 @@ func (f *fsClient) listDirOpt(contentCh chan *clientContent, isIncomplete bool,
 					contentCh <- &content
 				}
 				if listDir(filepath.Join(name)) {
-					return true
+					isStop = true
+					return ioutils.ErrSkipDir
 				}
 				if dirOpt == DirLast && !isIncomplete {
 					contentCh <- &content
 				}
 
-				continue
+				return nil
 			}
 
 			contentCh <- &content
+			return nil
+		})
+		if err != nil && !isStop {
+			if os.IsPermission(err) {
+				contentCh <- &clientContent{Err: probe.NewError(PathInsufficientPermission{Path: currentPath})}
+				return false
+			}
+
+			contentCh <- &clientContent{Err: probe.NewError(err)}
+			return true
 		}
 
-		return false
+		return isStop
 	}
 
 	// listDir() does not send currentPath to contentCh.  We send it here depending on dirOpt.
@@ -891,7 +965,7 @@ func (f *fsClient) MakeBucket(region string, ignoreExisting bool) *probe.Error {
 	// TODO: ignoreExisting has no effect currently. In the future, we want
 	// to call os.Mkdir() when ignoredExisting is disabled and os.MkdirAll()
 	// otherwise.
-	e := os.MkdirAll(f.PathURL.Path, 0777)
+	e := os.MkdirAll(toFSPath(f.PathURL.Path), 0777)
 	if e != nil {
 		return probe.NewError(e)
 	}
@@ -964,7 +1038,10 @@ func (f *fsClient) SetAccess(access string, isJSON bool) *probe.Error {
 }
 
 // Stat - get metadata from path.
-func (f *fsClient) Stat(isIncomplete, isFetchMeta bool, sse encrypt.ServerSide) (content *clientContent, err *probe.Error) {
+func (f *fsClient) Stat(ctx context.Context, isIncomplete, isFetchMeta bool, sse encrypt.ServerSide) (content *clientContent, err *probe.Error) {
+	if e := ctx.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
 	st, err := f.fsStat(isIncomplete)
 	if err != nil {
 		return nil, err.Trace(f.PathURL.String())
@@ -1008,7 +1085,7 @@ func (f *fsClient) toClientError(e error, fpath string) *probe.Error {
 
 // fsStat - wrapper function to get file stat.
 func (f *fsClient) fsStat(isIncomplete bool) (os.FileInfo, *probe.Error) {
-	fpath := f.PathURL.Path
+	fpath := toFSPath(f.PathURL.Path)
 
 	// Check if the path corresponds to a directory and returns
 	// the successful result whether isIncomplete is specified or not.
@@ -24,6 +24,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	. "gopkg.in/check.v1"
 )
@@ -65,7 +66,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	// Verify previously create files and list them.
 	var contents []*clientContent
-	for content := range fsClient.List(false, false, DirNone) {
+	for content := range fsClient.List(globalContext, false, false, DirNone) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -93,7 +94,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	contents = nil
 	// List non recursive to list only top level files.
-	for content := range fsClient.List(false, false, DirNone) {
+	for content := range fsClient.List(globalContext, false, false, DirNone) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -109,7 +110,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	contents = nil
 	// List recursively all files and verify.
-	for content := range fsClient.List(true, false, DirNone) {
+	for content := range fsClient.List(globalContext, true, false, DirNone) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -153,7 +154,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	contents = nil
 	// List recursively all files and verify.
-	for content := range fsClient.List(true, false, DirNone) {
+	for content := range fsClient.List(globalContext, true, false, DirNone) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -210,7 +211,7 @@ func (s *TestSuite) TestStatBucket(c *C) {
 	c.Assert(err, IsNil)
 	err = fsClient.MakeBucket("us-east-1", true)
 	c.Assert(err, IsNil)
-	_, err = fsClient.Stat(false, false, nil)
+	_, err = fsClient.Stat(globalContext, false, false, nil)
 	c.Assert(err, IsNil)
 }
 
@@ -256,6 +257,112 @@ func (s *TestSuite) TestPut(c *C) {
 	c.Assert(n, Equals, int64(len(data)))
 }
 
+// Test put through a part file, resumed or discarded when stale.
+func (s *TestSuite) TestPutPartFile(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	data := "hello world"
+	progress := func() io.Reader { return newAccounter(int64(len(data))) }
+
+	// An interrupted put leaves only the part file behind.
+	reader := bytes.NewReader([]byte(data[:5]))
+	_, err = fsClient.Put(context.Background(), reader, int64(len(data)), nil, progress(), nil)
+	c.Assert(err, NotNil)
+	_, e = os.Stat(objectPath)
+	c.Assert(os.IsNotExist(e), Equals, true)
+	partData, e := ioutil.ReadFile(objectPath + partSuffix)
+	c.Assert(e, IsNil)
+	c.Assert(string(partData), Equals, data[:5])
+
+	// The next put resumes the part file.
+	n, err := fsClient.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)), nil, progress(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	objectData, e := ioutil.ReadFile(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(string(objectData), Equals, data)
+	_, e = os.Stat(objectPath + partSuffix)
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	// Part files larger than the object or abandoned are not resumed.
+	for _, stale := range []string{"larger than the object", "hel"} {
+		e = ioutil.WriteFile(objectPath+partSuffix, []byte(stale), 0600)
+		c.Assert(e, IsNil)
+		if len(stale) < len(data) {
+			expired := time.Now().Add(-partExpiry - time.Hour)
+			c.Assert(os.Chtimes(objectPath+partSuffix, expired, expired), IsNil)
+		}
+		newData := "HELLO WORLD"
+		n, err = fsClient.Put(context.Background(), bytes.NewReader([]byte(newData)), int64(len(newData)), nil, progress(), nil)
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, int64(len(newData)))
+		objectData, e = ioutil.ReadFile(objectPath)
+		c.Assert(e, IsNil)
+		c.Assert(string(objectData), Equals, newData)
+	}
+}
+
+// Test put of a file with blocks of zeros, also at its end.
+func (s *TestSuite) TestPutSparse(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "disk.img")
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	var data []byte
+	data = append(data, "boot"...)
+	data = append(data, make([]byte, 3*sparseBlockSize)...)
+	data = append(data, "data"...)
+	data = append(data, make([]byte, 2*sparseBlockSize)...)
+	n, err := fsClient.Put(context.Background(), bytes.NewReader(data), int64(len(data)), nil, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+
+	objectData, e := ioutil.ReadFile(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(objectData, data), Equals, true)
+}
+
+// Test put and get bypassing the page cache with a small buffer.
+func (s *TestSuite) TestPutGetUncached(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	savedBufferSize, savedDirect := globalFSBufferSize, globalFSDirect
+	defer func() { globalFSBufferSize, globalFSDirect = savedBufferSize, savedDirect }()
+	setFSGlobals(minFSBufferSize, true, false)
+
+	objectPath := filepath.Join(root, "backup.tar")
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	// Larger than the interval pages are dropped at.
+	data := bytes.Repeat([]byte("backup"), fsDropCacheInterval/5)
+	reader := struct{ io.Reader }{bytes.NewReader(data)}
+	n, err := fsClient.Put(context.Background(), reader, int64(len(data)), nil, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+
+	rc, err := fsClient.Get(nil)
+	c.Assert(err, IsNil)
+	_, ok := rc.(*uncachedReader)
+	c.Assert(ok, Equals, true)
+	objectData, e := ioutil.ReadAll(rc)
+	c.Assert(e, IsNil)
+	c.Assert(rc.Close(), IsNil)
+	c.Assert(bytes.Equal(objectData, data), Equals, true)
+}
+
 // Test read a file.
 func (s *TestSuite) TestGet(c *C) {
 	root, e := ioutil.TempDir(os.TempDir(), "fs-")
@@ -334,7 +441,7 @@ func (s *TestSuite) TestStatObject(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
-	content, err := fsClient.Stat(false, false, nil)
+	content, err := fsClient.Stat(globalContext, false, false, nil)
 	c.Assert(err, IsNil)
 	c.Assert(content.Size, Equals, int64(dataLen))
 }
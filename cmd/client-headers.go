@@ -0,0 +1,177 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Headers set by the client or signed with the request, which cannot be
+// set with --header. All 'X-Amz-' headers have to be signed as well.
+var reservedHeaders = []string{
+	"Authorization",
+	"Host",
+	"Content-Length",
+	"Content-Md5",
+	"Content-Type",
+	"Date",
+	"Expect",
+	"Transfer-Encoding",
+}
+
+// checkHTTPHeader - verify a header can be added to all requests.
+func checkHTTPHeader(name, value string) *probe.Error {
+	if name == "" || strings.ContainsAny(name, " \t:\r\n") || strings.ContainsAny(value, "\r\n") {
+		return errInvalidArgument().Trace(name, value)
+	}
+	name = http.CanonicalHeaderKey(name)
+	if strings.HasPrefix(name, "X-Amz-") {
+		return errInvalidArgument().Trace(name)
+	}
+	for _, reserved := range reservedHeaders {
+		if name == reserved {
+			return errInvalidArgument().Trace(name)
+		}
+	}
+	return nil
+}
+
+// parseHTTPHeaders - parse 'Name: Value' headers, a header given again
+// replaces the former value.
+func parseHTTPHeaders(values []string) (http.Header, *probe.Error) {
+	headers := make(http.Header, len(values))
+	for _, value := range values {
+		kv := strings.SplitN(value, ":", 2)
+		if len(kv) != 2 {
+			return nil, errInvalidArgument().Trace(value)
+		}
+		name, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if err := checkHTTPHeader(name, v); err != nil {
+			return nil, err.Trace(value)
+		}
+		headers.Set(name, v)
+	}
+	return headers, nil
+}
+
+// formatHTTPHeaders - 'Name: Value' headers sorted by name, the format
+// parsed by parseHTTPHeaders.
+func formatHTTPHeaders(headers http.Header) []string {
+	var values []string
+	for name, v := range headers {
+		for _, value := range v {
+			values = append(values, name+": "+value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// hostHeaders - headers added to all requests to a host, the headers of
+// its alias overridden by --header.
+func hostHeaders(hostCfg *hostConfigV9) http.Header {
+	headers := make(http.Header)
+	if hostCfg != nil {
+		for name, value := range hostCfg.Headers {
+			headers.Set(name, value)
+		}
+	}
+	for name, value := range globalHeaders {
+		headers[name] = value
+	}
+	return headers
+}
+
+// headerTransport - adds headers to all requests, after signing them.
+type headerTransport struct {
+	headers   http.Header
+	transport http.RoundTripper
+}
+
+// newHeaderTransport - a transport adding headers to all requests sent
+// with transport, transport itself without headers.
+func newHeaderTransport(headers http.Header, transport http.RoundTripper) http.RoundTripper {
+	if len(headers) == 0 {
+		return transport
+	}
+	return headerTransport{headers: headers, transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper, the request is copied as round
+// trippers may not modify it.
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+len(t.headers))
+	for name, value := range req.Header {
+		r.Header[name] = value
+	}
+	for name, value := range t.headers {
+		r.Header[name] = value
+	}
+	return t.transport.RoundTrip(r)
+}
+
+// objectHeadersKey - context key of the headers of an uploaded object.
+type objectHeadersKey struct{}
+
+// withObjectHeaders - a context uploading an object with headers which
+// minio-go has no option for, such as Expires.
+func withObjectHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, objectHeadersKey{}, headers)
+}
+
+// objectHeadersTransport - adds the object headers of the context of a
+// request to the request which creates the object, a single part upload
+// or the start of a multipart upload, after signing it.
+type objectHeadersTransport struct {
+	transport http.RoundTripper
+}
+
+func newObjectHeadersTransport(transport http.RoundTripper) http.RoundTripper {
+	return objectHeadersTransport{transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper, requests without object
+// headers and parts of multipart uploads are sent as is.
+func (t objectHeadersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers, _ := req.Context().Value(objectHeadersKey{}).(http.Header)
+	query := req.URL.Query()
+	_, isPart := query["uploadId"]
+	_, isStart := query["uploads"]
+	if len(headers) == 0 || !(req.Method == http.MethodPut && !isPart || req.Method == http.MethodPost && isStart) {
+		return t.transport.RoundTrip(req)
+	}
+	return headerTransport{headers: headers, transport: t.transport}.RoundTrip(req)
+}
+
+// hostHeadersConfig - headers as saved in the config of an alias.
+func hostHeadersConfig(headers http.Header) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	config := make(map[string]string, len(headers))
+	for name := range headers {
+		config[name] = headers.Get(name)
+	}
+	return config
+}
@@ -0,0 +1,83 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseHTTPHeaders(t *testing.T) {
+	testCases := []struct {
+		values   []string
+		expected http.Header
+		success  bool
+	}{
+		{[]string{"X-Gateway-Token: abc"}, http.Header{"X-Gateway-Token": {"abc"}}, true},
+		{[]string{"x-request-tag:  nightly ", "X-Request-Tag: weekly"}, http.Header{"X-Request-Tag": {"weekly"}}, true},
+		{[]string{"X-Empty:"}, http.Header{"X-Empty": {""}}, true},
+		{[]string{"X-Gateway-Token=abc"}, nil, false},
+		{[]string{"X Gateway: abc"}, nil, false},
+		{[]string{": abc"}, nil, false},
+		// Headers which are signed or set by the client.
+		{[]string{"Authorization: Bearer abc"}, nil, false},
+		{[]string{"host: example.com"}, nil, false},
+		{[]string{"X-Amz-Meta-Tag: abc"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		headers, err := parseHTTPHeaders(testCase.values)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+		if err == nil && !reflect.DeepEqual(headers, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, headers)
+		}
+	}
+}
+
+func TestHeaderTransport(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	transport := newHeaderTransport(http.Header{"X-Request-Tag": {"nightly"}, "User-Agent": {"gateway"}}, http.DefaultTransport)
+	req, e := http.NewRequest(http.MethodGet, server.URL, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	req.Header.Set("User-Agent", "mc")
+	resp, e := (&http.Client{Transport: transport}).Do(req)
+	if e != nil {
+		t.Fatal(e)
+	}
+	resp.Body.Close()
+
+	if received.Get("X-Request-Tag") != "nightly" || received.Get("User-Agent") != "gateway" {
+		t.Errorf("expected the added headers, got %v", received)
+	}
+	// Round trippers may not modify requests.
+	if req.Header.Get("X-Request-Tag") != "" || req.Header.Get("User-Agent") != "mc" {
+		t.Errorf("expected the request to be left unmodified, got %v", req.Header)
+	}
+	if newHeaderTransport(nil, http.DefaultTransport) != http.DefaultTransport {
+		t.Errorf("expected no transport without headers")
+	}
+}
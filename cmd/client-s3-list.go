@@ -0,0 +1,115 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	minio "github.com/minio/minio-go/v6"
+)
+
+const (
+	// Prefixes of a recursive listing listed at once, unless set by
+	// MC_LIST_CONCURRENCY.
+	defaultListConcurrency = 8
+
+	// Objects buffered ahead by every prefix listed concurrently.
+	listPrefixBuffer = 1000
+)
+
+// listConcurrency - prefixes listed at once by a recursive listing, 1
+// lists sequentially.
+func listConcurrency() int {
+	if n, e := strconv.Atoi(os.Getenv("MC_LIST_CONCURRENCY")); e == nil && n > 0 {
+		return n
+	}
+	return defaultListConcurrency
+}
+
+// listRecursive - list all objects under a prefix in key order. Wide
+// buckets are listed faster by listing every delimited prefix on its own,
+// concurrently, until doneCh is closed.
+func (c *s3Client) listRecursive(bucket, prefix string, doneCh <-chan struct{}) <-chan minio.ObjectInfo {
+	workers := listConcurrency()
+	if workers < 2 {
+		return c.listObjectWrapper(bucket, prefix, true, doneCh)
+	}
+
+	// Objects right under the prefix.
+	objectCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectCh)
+		for object := range c.listObjectWrapper(bucket, prefix, false, doneCh) {
+			if object.Err != nil || !strings.HasSuffix(object.Key, "/") {
+				objectCh <- object
+			}
+		}
+	}()
+
+	// Listings of the delimited prefixes in key order. One is merged, one
+	// waits to be queued, at most `workers` are running.
+	prefixCh := make(chan (<-chan minio.ObjectInfo), workers-2)
+	go func() {
+		defer close(prefixCh)
+		for object := range c.listObjectWrapper(bucket, prefix, false, doneCh) {
+			// Errors are sent by the listing of objects.
+			if object.Err == nil && strings.HasSuffix(object.Key, "/") {
+				prefixCh <- bufferListing(c.listObjectWrapper(bucket, object.Key, true, doneCh))
+			}
+		}
+	}()
+
+	return mergeListings(objectCh, prefixCh)
+}
+
+// bufferListing - keep a listing going while it waits to be merged.
+func bufferListing(listCh <-chan minio.ObjectInfo) <-chan minio.ObjectInfo {
+	bufferCh := make(chan minio.ObjectInfo, listPrefixBuffer)
+	go func() {
+		defer close(bufferCh)
+		for object := range listCh {
+			bufferCh <- object
+		}
+	}()
+	return bufferCh
+}
+
+// mergeListings - merge the objects of a sorted listing with sorted
+// listings of prefixes which do not overlap, in key order. Errors are
+// sent as they come.
+func mergeListings(objectCh <-chan minio.ObjectInfo, prefixCh <-chan (<-chan minio.ObjectInfo)) <-chan minio.ObjectInfo {
+	mergedCh := make(chan minio.ObjectInfo, 1)
+	go func() {
+		defer close(mergedCh)
+		object, ok := <-objectCh
+		for listCh := range prefixCh {
+			for entry := range listCh {
+				for ok && entry.Err == nil && (object.Err != nil || object.Key < entry.Key) {
+					mergedCh <- object
+					object, ok = <-objectCh
+				}
+				mergedCh <- entry
+			}
+		}
+		for ; ok; object, ok = <-objectCh {
+			mergedCh <- object
+		}
+	}()
+	return mergedCh
+}
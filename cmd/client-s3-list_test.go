@@ -0,0 +1,73 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	minio "github.com/minio/minio-go/v6"
+)
+
+func TestMergeListings(t *testing.T) {
+	listing := func(keys ...string) <-chan minio.ObjectInfo {
+		listCh := make(chan minio.ObjectInfo, len(keys))
+		for _, key := range keys {
+			object := minio.ObjectInfo{Key: key}
+			if key == "error" {
+				object = minio.ObjectInfo{Err: errors.New("listing failed")}
+			}
+			listCh <- object
+		}
+		close(listCh)
+		return listCh
+	}
+
+	testCases := []struct {
+		objects  []string
+		prefixes [][]string
+		expected []string
+	}{
+		{nil, nil, nil},
+		{[]string{"a", "b"}, nil, []string{"a", "b"}},
+		{nil, [][]string{{"a/1", "a/2"}, {}, {"c/1"}}, []string{"a/1", "a/2", "c/1"}},
+		// Objects fall in between prefixes, 'a-b' sorts before 'a/'.
+		{[]string{"a-b", "b", "d"}, [][]string{{"a/1", "a/2"}, {"c/1"}}, []string{"a-b", "a/1", "a/2", "b", "c/1", "d"}},
+		// Errors are not held back.
+		{[]string{"error", "b"}, [][]string{{"a/1", "error", "a/2"}}, []string{"error", "a/1", "error", "a/2", "b"}},
+	}
+	for i, testCase := range testCases {
+		prefixCh := make(chan (<-chan minio.ObjectInfo), len(testCase.prefixes))
+		for _, keys := range testCase.prefixes {
+			prefixCh <- bufferListing(listing(keys...))
+		}
+		close(prefixCh)
+
+		var keys []string
+		for object := range mergeListings(listing(testCase.objects...), prefixCh) {
+			if object.Err != nil {
+				keys = append(keys, "error")
+				continue
+			}
+			keys = append(keys, object.Key)
+		}
+		if !reflect.DeepEqual(keys, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, keys)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+)
+
+const (
+	regionCacheVersion = "1"
+	regionCacheFile    = "regions.json"
+)
+
+// regionCacheV1 - regions of AWS S3 buckets by host and bucket, saved in
+// the config folder so that later commands sign requests for the region
+// of a bucket instead of looking up its location first, which AWS may
+// answer with a redirect to the region.
+type regionCacheV1 struct {
+	Version string            `json:"version"`
+	Regions map[string]string `json:"regions"`
+}
+
+var (
+	regionCache      *regionCacheV1
+	regionCacheMutex sync.Mutex
+
+	// Buckets looked up by this process, failed lookups are not retried.
+	regionLookups = map[string]bool{}
+)
+
+// getRegionCacheFile - get the path of the bucket region cache.
+func getRegionCacheFile() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, regionCacheFile), nil
+}
+
+func regionCacheKey(host, bucket string) string {
+	return host + "/" + bucket
+}
+
+// loadRegionCache - load the cache once per process, an unreadable cache
+// is empty. Must be called with regionCacheMutex held.
+func loadRegionCache() *regionCacheV1 {
+	if regionCache != nil {
+		return regionCache
+	}
+	regionCache = &regionCacheV1{Version: regionCacheVersion, Regions: map[string]string{}}
+	path, err := getRegionCacheFile()
+	if err != nil {
+		return regionCache
+	}
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return regionCache
+	}
+	cache := &regionCacheV1{}
+	if e = json.Unmarshal(data, cache); e == nil && cache.Version == regionCacheVersion && cache.Regions != nil {
+		regionCache = cache
+	}
+	return regionCache
+}
+
+// saveRegionCache - replace the cache file, errors are ignored as a
+// missing cache only costs lookups. Must be called with regionCacheMutex
+// held.
+func saveRegionCache() {
+	path, err := getRegionCacheFile()
+	if err != nil {
+		return
+	}
+	data, e := json.MarshalIndent(regionCache, "", " ")
+	if e != nil {
+		return
+	}
+	tmpFile, e := ioutil.TempFile(filepath.Dir(path), ".regions-")
+	if e != nil {
+		return
+	}
+	_, e = tmpFile.Write(data)
+	if ce := tmpFile.Close(); e == nil {
+		e = ce
+	}
+	if e == nil {
+		e = os.Rename(tmpFile.Name(), path)
+	}
+	if e != nil {
+		os.Remove(tmpFile.Name())
+	}
+}
+
+// getBucketRegion - the cached region of a bucket, empty if unknown.
+func getBucketRegion(host, bucket string) string {
+	regionCacheMutex.Lock()
+	defer regionCacheMutex.Unlock()
+	return loadRegionCache().Regions[regionCacheKey(host, bucket)]
+}
+
+// setBucketRegion - cache the region of a bucket, an empty region
+// removes the bucket from the cache.
+func setBucketRegion(host, bucket, region string) {
+	regionCacheMutex.Lock()
+	defer regionCacheMutex.Unlock()
+	cache := loadRegionCache()
+	key := regionCacheKey(host, bucket)
+	if cache.Regions[key] == region {
+		return
+	}
+	if region == "" {
+		delete(cache.Regions, key)
+	} else {
+		cache.Regions[key] = region
+	}
+	saveRegionCache()
+}
+
+// lookupBucketRegion - look up the location of a bucket once per process
+// and cache it. minio-go answers 'us-east-1' when the lookup is denied,
+// so 'us-east-1' is never cached, requests to buckets in that region are
+// never redirected.
+func lookupBucketRegion(api *minio.Client, host, bucket string) {
+	regionCacheMutex.Lock()
+	key := regionCacheKey(host, bucket)
+	looked := regionLookups[key]
+	regionLookups[key] = true
+	regionCacheMutex.Unlock()
+	if looked {
+		return
+	}
+	if region, e := api.GetBucketLocation(bucket); e == nil && region != "" && region != "us-east-1" {
+		setBucketRegion(host, bucket, region)
+	}
+}
+
+// bucketRegionTransport - drops the cached region of a bucket when AWS S3
+// redirects or refuses a request, the bucket was likely recreated in
+// another region and is looked up again by the next command.
+type bucketRegionTransport struct {
+	host, bucket string
+	transport    http.RoundTripper
+}
+
+func newBucketRegionTransport(host, bucket string, transport http.RoundTripper) http.RoundTripper {
+	return &bucketRegionTransport{host: host, bucket: bucket, transport: transport}
+}
+
+func (t *bucketRegionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, e := t.transport.RoundTrip(req)
+	if e == nil && (resp.StatusCode == http.StatusMovedPermanently || resp.StatusCode == http.StatusBadRequest) {
+		setBucketRegion(t.host, t.bucket, resp.Header.Get("X-Amz-Bucket-Region"))
+	}
+	return resp, e
+}
+
+// cacheBucketRegion - cache the region of a bucket created or removed by
+// mc, 'us-east-1' and an empty region remove the bucket from the cache.
+func (c *s3Client) cacheBucketRegion(bucket, region string) {
+	if !isAmazon(c.targetURL.Host) {
+		return
+	}
+	if region == "us-east-1" {
+		region = ""
+	}
+	setBucketRegion(c.targetURL.Host, bucket, region)
+}
@@ -0,0 +1,109 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestBucketRegionCache(t *testing.T) {
+	configDir, e := ioutil.TempDir("", "mc-regions-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(configDir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(configDir)
+	defer func() { regionCache = nil }()
+	regionCache = nil
+
+	host := "s3.amazonaws.com"
+	if region := getBucketRegion(host, "bucket"); region != "" {
+		t.Fatalf("expected no cached region, got %s", region)
+	}
+	setBucketRegion(host, "bucket", "eu-west-1")
+
+	// The region is saved for the next commands.
+	regionCache = nil
+	if region := getBucketRegion(host, "bucket"); region != "eu-west-1" {
+		t.Fatalf("expected eu-west-1, got %s", region)
+	}
+
+	// Buckets created and removed by mc outside of AWS are not cached,
+	// 'us-east-1' is the default region.
+	clnt := &s3Client{targetURL: newClientURL("https://s3.amazonaws.com/other")}
+	clnt.cacheBucketRegion("other", "ap-south-1")
+	clnt.cacheBucketRegion("bucket", "us-east-1")
+	(&s3Client{targetURL: newClientURL("https://play.min.io/third")}).cacheBucketRegion("third", "eu-central-1")
+	regionCache = nil
+	for bucket, expected := range map[string]string{"bucket": "", "other": "ap-south-1", "third": ""} {
+		if region := getBucketRegion(host, bucket); region != expected {
+			t.Errorf("bucket %s: expected region %q, got %q", bucket, expected, region)
+		}
+	}
+}
+
+func TestBucketRegionTransport(t *testing.T) {
+	configDir, e := ioutil.TempDir("", "mc-regions-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(configDir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(configDir)
+	defer func() { regionCache = nil }()
+	regionCache = nil
+
+	var status int
+	var bucketRegion string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bucketRegion != "" {
+			w.Header().Set("X-Amz-Bucket-Region", bucketRegion)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	host := "s3.amazonaws.com"
+	setBucketRegion(host, "bucket", "eu-west-1")
+	client := &http.Client{Transport: newBucketRegionTransport(host, "bucket", http.DefaultTransport)}
+	testCases := []struct {
+		status       int
+		bucketRegion string
+		expected     string
+	}{
+		{http.StatusOK, "", "eu-west-1"},
+		{http.StatusNotFound, "", "eu-west-1"},
+		{http.StatusMovedPermanently, "ap-south-1", "ap-south-1"},
+		{http.StatusBadRequest, "", ""},
+	}
+	for i, testCase := range testCases {
+		status, bucketRegion = testCase.status, testCase.bucketRegion
+		resp, e := client.Get(server.URL + "/bucket")
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp.Body.Close()
+		if region := getBucketRegion(host, "bucket"); region != testCase.expected {
+			t.Errorf("Test %d: expected region %q, got %q", i+1, testCase.expected, region)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+)
+
+const (
+	requestPayerHeader = "X-Amz-Request-Payer"
+
+	// Prefix of the authorization of requests signed with signature V4,
+	// and the payload hash of requests with a streaming signature.
+	signV4Algorithm  = "AWS4-HMAC-SHA256"
+	streamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+)
+
+// requestPayerTransport - accepts the charges of requests to requester
+// pays buckets on all requests. The header has to be signed, so signed
+// requests are signed again with the header.
+type requestPayerTransport struct {
+	accessKey, secretKey, sessionToken string
+	transport                          http.RoundTripper
+}
+
+// newRequestPayerTransport - a transport of requests paid by the
+// requester, sent with transport.
+func newRequestPayerTransport(config *Config, transport http.RoundTripper) http.RoundTripper {
+	return requestPayerTransport{
+		accessKey:    config.AccessKey,
+		secretKey:    config.SecretKey,
+		sessionToken: config.SessionToken,
+		transport:    transport,
+	}
+}
+
+// requesterPaysParams - presigned requests accept the charges of
+// requester pays buckets in their query, which is signed.
+func (c *s3Client) requesterPaysParams(reqParams url.Values) url.Values {
+	if !c.requesterPays {
+		return reqParams
+	}
+	params := make(url.Values, len(reqParams)+1)
+	for key, value := range reqParams {
+		params[key] = value
+	}
+	params.Set(requestPayerHeader, "requester")
+	return params
+}
+
+// signatureRegion - the region of a signature V4 authorization, in its
+// credential scope 'Credential=KEY/DATE/REGION/s3/aws4_request'.
+func signatureRegion(authorization string) string {
+	i := strings.Index(authorization, "Credential=")
+	if i < 0 {
+		return ""
+	}
+	scope := strings.Split(strings.SplitN(authorization[i+len("Credential="):], ",", 2)[0], "/")
+	if len(scope) != 5 {
+		return ""
+	}
+	return scope[2]
+}
+
+// RoundTrip implements http.RoundTripper. Only requests signed with
+// signature V4 in their headers are signed again. Requests with a
+// streaming signature cannot be, their chunks are signed with the
+// signature of the request, they are sent as is like presigned and
+// anonymous requests.
+func (t requestPayerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authorization := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, signV4Algorithm) || req.Header.Get("X-Amz-Content-Sha256") == streamingPayload {
+		return t.transport.RoundTrip(req)
+	}
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for name, value := range req.Header {
+		r.Header[name] = value
+	}
+	r.Header.Set(requestPayerHeader, "requester")
+	return t.transport.RoundTrip(s3signer.SignV4(*r, t.accessKey, t.secretKey, t.sessionToken, signatureRegion(authorization)))
+}
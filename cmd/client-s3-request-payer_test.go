@@ -0,0 +1,118 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+)
+
+func TestSignatureRegion(t *testing.T) {
+	testCases := []struct {
+		authorization string
+		region        string
+	}{
+		{"AWS4-HMAC-SHA256 Credential=AKIA/20190701/eu-west-1/s3/aws4_request, SignedHeaders=host, Signature=abc", "eu-west-1"},
+		{"AWS4-HMAC-SHA256 Credential=AKIA/20190701/us-east-1/s3/aws4_request,SignedHeaders=host,Signature=abc", "us-east-1"},
+		{"AWS AKIA:abc", ""},
+		{"", ""},
+	}
+	for i, testCase := range testCases {
+		if region := signatureRegion(testCase.authorization); region != testCase.region {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.region, region)
+		}
+	}
+}
+
+func TestRequestPayerTransport(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+	}))
+	defer server.Close()
+
+	transport := newRequestPayerTransport(&Config{AccessKey: "access", SecretKey: "secret"}, http.DefaultTransport)
+	send := func(req *http.Request) {
+		received = nil
+		resp, e := (&http.Client{Transport: transport}).Do(req)
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp.Body.Close()
+	}
+
+	// Requests signed with signature V4 are signed again with the header.
+	req, e := http.NewRequest(http.MethodGet, server.URL+"/bucket/object", nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	req = s3signer.SignV4(*req, "access", "secret", "", "eu-west-1")
+	authorization := req.Header.Get("Authorization")
+	send(req)
+	if received.Header.Get(requestPayerHeader) != "requester" {
+		t.Errorf("expected the request payer header, got %v", received.Header)
+	}
+	signed := received.Header.Get("Authorization")
+	if signed == authorization || !strings.Contains(signed, "x-amz-request-payer") || signatureRegion(signed) != "eu-west-1" {
+		t.Errorf("expected a signature of the request payer header in eu-west-1, got %q", signed)
+	}
+	if req.Header.Get(requestPayerHeader) != "" {
+		t.Errorf("expected the request to be left unmodified, got %v", req.Header)
+	}
+
+	// Streaming signatures, signature V2 and anonymous requests are sent as is.
+	for _, header := range []http.Header{
+		{"Authorization": {"AWS4-HMAC-SHA256 Credential=access/20190701/us-east-1/s3/aws4_request"}, "X-Amz-Content-Sha256": {streamingPayload}},
+		{"Authorization": {"AWS access:abc"}},
+		{},
+	} {
+		req, e := http.NewRequest(http.MethodGet, server.URL+"/bucket/object", nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		req.Header = header
+		send(req)
+		if received.Header.Get(requestPayerHeader) != "" || received.Header.Get("Authorization") != header.Get("Authorization") {
+			t.Errorf("expected %v to be sent as is, got %v", header, received.Header)
+		}
+	}
+}
+
+func TestRequesterPaysParams(t *testing.T) {
+	params := url.Values{"response-content-type": {"text/plain"}}
+	c := &s3Client{}
+	if got := c.requesterPaysParams(params); got.Get(requestPayerHeader) != "" {
+		t.Errorf("expected no request payer, got %v", got)
+	}
+	c.requesterPays = true
+	got := c.requesterPaysParams(params)
+	if got.Get(requestPayerHeader) != "requester" || got.Get("response-content-type") != "text/plain" {
+		t.Errorf("expected the request payer with the parameters, got %v", got)
+	}
+	if params.Get(requestPayerHeader) != "" {
+		t.Errorf("expected the parameters to be left unmodified, got %v", params)
+	}
+	if got := c.requesterPaysParams(nil); got.Get(requestPayerHeader) != "requester" {
+		t.Errorf("expected the request payer without parameters, got %v", got)
+	}
+}
@@ -19,8 +19,14 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"hash/fnv"
 	"io"
 	"net"
@@ -30,16 +36,21 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/hookreader"
 	"github.com/minio/mc/pkg/httptracer"
 	"github.com/minio/mc/pkg/probe"
 	minio "github.com/minio/minio-go/v6"
 	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/minio/minio-go/v6/pkg/encrypt"
 	"github.com/minio/minio-go/v6/pkg/policy"
+	"github.com/minio/minio-go/v6/pkg/s3signer"
 	"github.com/minio/minio-go/v6/pkg/s3utils"
 	"github.com/minio/minio/pkg/mimedb"
 )
@@ -49,11 +60,25 @@ type s3Client struct {
 	mutex        *sync.Mutex
 	targetURL    *clientURL
 	api          *minio.Client
+	transport    http.RoundTripper
 	virtualStyle bool
+
+	// Credentials to sign requests minio-go can not make.
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	signature    string
+
+	// Whether requests accept the charges of requester pays buckets.
+	requesterPays bool
+
+	// Multipart strategy of uploads.
+	multipart multipartConfig
 }
 
 const (
-	amazonHostNameAccelerated = "s3-accelerate.amazonaws.com"
+	amazonHostNameAccelerated          = "s3-accelerate.amazonaws.com"
+	amazonHostNameAcceleratedDualStack = "s3-accelerate.dualstack.amazonaws.com"
 
 	googleHostName            = "storage.googleapis.com"
 	serverEncryptionKeyPrefix = "x-amz-server-side-encryption"
@@ -83,6 +108,7 @@ var cseHeaders = []string{
 // newFactory encloses New function with client cache.
 func newFactory() func(config *Config) (Client, *probe.Error) {
 	clientCache := make(map[uint32]*minio.Client)
+	transportCache := make(map[uint32]http.RoundTripper)
 	mutex := &sync.Mutex{}
 
 	// Return New function.
@@ -101,6 +127,15 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 		s3Clnt.mutex = new(sync.Mutex)
 		// Save the target URL.
 		s3Clnt.targetURL = targetURL
+		s3Clnt.accessKey = config.AccessKey
+		s3Clnt.secretKey = config.SecretKey
+		s3Clnt.sessionToken = config.SessionToken
+		s3Clnt.signature = config.Signature
+		s3Clnt.multipart = config.Multipart
+		s3Clnt.requesterPays = config.RequesterPays
+		if config.RequesterPays && strings.ToUpper(config.Signature) == "S3V2" {
+			return nil, errRequesterPaysV2(config.HostURL)
+		}
 
 		// Save if target supports virtual host style.
 		hostName := targetURL.Host
@@ -113,9 +148,27 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				hostName = googleHostName
 			}
 		}
+		// Requests to a bucket are signed for the region of the alias or
+		// the region cached by a previous lookup, without a new lookup.
+		// Clients with a cached region serve a single bucket.
+		bucket, _ := s3Clnt.url2BucketAndObject()
+		region, cachedRegion := config.Region, false
+		if region == "" && bucket != "" && isAmazon(hostName) {
+			region = getBucketRegion(hostName, bucket)
+			cachedRegion = region != ""
+		}
+		regionKey := region
+		if cachedRegion {
+			regionKey = region + "/" + bucket
+		}
+
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
+		confHash.Write([]byte(hostName + regionKey + config.AccessKey + config.SecretKey + config.SessionToken +
+			config.CACert + config.ClientCert + config.ClientKey + config.Fingerprint + config.Proxy +
+			strings.Join(formatHTTPHeaders(config.Headers), "\n") + strconv.FormatBool(config.RequesterPays) +
+			strconv.FormatBool(config.Accelerate) + strconv.FormatBool(config.DualStack) +
+			strings.Join(config.Endpoints, ",")))
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -125,10 +178,10 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 		var found bool
 		if api, found = clientCache[confSum]; !found {
 			// if Signature version '4' use NewV4 directly.
-			creds := credentials.NewStaticV4(config.AccessKey, config.SecretKey, "")
+			creds := credentials.NewStaticV4(config.AccessKey, config.SecretKey, config.SessionToken)
 			// if Signature version '2' use NewV2 directly.
 			if strings.ToUpper(config.Signature) == "S3V2" {
-				creds = credentials.NewStaticV2(config.AccessKey, config.SecretKey, "")
+				creds = credentials.NewStaticV2(config.AccessKey, config.SecretKey, config.SessionToken)
 			}
 			// Not found. Instantiate a new MinIO
 			var e error
@@ -136,7 +189,7 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 			options := minio.Options{
 				Creds:        creds,
 				Secure:       useTLS,
-				Region:       "",
+				Region:       region,
 				BucketLookup: config.Lookup,
 			}
 
@@ -145,8 +198,13 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				return nil, probe.NewError(e)
 			}
 
+			proxyFunc, err := getProxyFunc(config)
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
+			}
+
 			tr := &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
+				Proxy: proxyFunc,
 				DialContext: (&net.Dialer{
 					Timeout:   30 * time.Second,
 					KeepAlive: 30 * time.Second,
@@ -177,6 +235,10 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				if config.Insecure {
 					tlsConfig.InsecureSkipVerify = true
 				}
+				// Apply host specific CA bundle and client certificate.
+				if err := setHostTLSConfig(tlsConfig, config); err != nil {
+					return nil, err.Trace(config.HostURL)
+				}
 				tr.TLSClientConfig = tlsConfig
 
 				// Because we create a custom TLSClientConfig, we have to opt-in to HTTP/2.
@@ -189,14 +251,25 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				// }
 			}
 
-			var transport http.RoundTripper = tr
-			if config.Debug {
-				if strings.EqualFold(config.Signature, "S3v4") {
-					transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
-				} else if strings.EqualFold(config.Signature, "S3v2") {
-					transport = httptracer.GetNewTraceTransport(newTraceV2(), transport)
+			// Requests are cancelled on interrupt and once --timeout expires.
+			transport := newContextTransport(tr)
+			// Requests fail over between the endpoints of the deployment.
+			transport = newEndpointsTransport(newEndpointPool(targetURL.Scheme, hostName, config.Endpoints, transport), transport)
+			if cachedRegion {
+				transport = newBucketRegionTransport(hostName, bucket, transport)
+			}
+			if config.Debug || globalTraceFile != "" {
+				tracer, err := newHTTPTrace(config.Signature, config.Debug)
+				if err != nil {
+					return nil, err.Trace(config.HostURL)
 				}
+				transport = httptracer.GetNewTraceTransport(tracer, transport)
 			}
+			// Headers are added outside of the trace to be traced.
+			if config.RequesterPays {
+				transport = newRequestPayerTransport(config, transport)
+			}
+			transport = newHeaderTransport(config.Headers, newObjectHeadersTransport(transport))
 
 			// Set the new transport.
 			api.SetCustomTransport(transport)
@@ -204,6 +277,10 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 			// If Amazon Accelerated URL is requested enable it.
 			if isS3AcceleratedEndpoint {
 				api.SetS3TransferAccelerate(amazonHostNameAccelerated)
+			} else if config.Accelerate && canAccelerate(hostName) {
+				// Requests to buckets are sent to the accelerate
+				// endpoint instead of the endpoint of the region.
+				api.SetS3TransferAccelerate(accelerateEndpoint(config.DualStack))
 			}
 
 			// Set app info.
@@ -211,10 +288,17 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 
 			// Cache the new MinIO Client with hash of config as key.
 			clientCache[confSum] = api
+			transportCache[confSum] = transport
+		}
+
+		// Cache the region of a bucket for the next commands.
+		if region == "" && bucket != "" && isAmazon(hostName) {
+			lookupBucketRegion(api, hostName, bucket)
 		}
 
 		// Store the new api object.
 		s3Clnt.api = api
+		s3Clnt.transport = transportCache[confSum]
 
 		return s3Clnt, nil
 	}
@@ -231,6 +315,9 @@ func (c *s3Client) GetURL() clientURL {
 
 // Add bucket notification
 func (c *s3Client) AddNotificationConfig(arn string, events []string, prefix, suffix string) *probe.Error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	// Validate total fields in ARN.
 	fields := strings.Split(arn, ":")
@@ -293,6 +380,9 @@ func (c *s3Client) AddNotificationConfig(arn string, events []string, prefix, su
 
 // Remove bucket notification
 func (c *s3Client) RemoveNotificationConfig(arn string) *probe.Error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	// Remove all notification configs if arn is empty
 	if arn == "" {
@@ -559,7 +649,7 @@ func (c *s3Client) Select(expression string, sse encrypt.ServerSide, selOpts Sel
 
 	opts.InputSerialization = selectObjectInputOpts(selOpts, object)
 	opts.OutputSerialization = selectObjectOutputOpts(selOpts, opts.InputSerialization)
-	reader, e := c.api.SelectObjectContent(context.Background(), bucket, object, opts)
+	reader, e := c.api.SelectObjectContent(globalContext, bucket, object, opts)
 	if e != nil {
 		return nil, probe.NewError(e)
 	}
@@ -691,6 +781,9 @@ func (c *s3Client) Watch(params watchParams) (*watchObject, *probe.Error) {
 
 // Get - get object with metadata.
 func (c *s3Client) Get(sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
+	if at := rewindTime(c.targetURL.String()); !at.IsZero() {
+		return c.getRewind(at, sse)
+	}
 	bucket, object := c.url2BucketAndObject()
 	opts := minio.GetObjectOptions{}
 	opts.ServerSideEncryption = sse
@@ -719,6 +812,9 @@ func (c *s3Client) Get(sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
 // such that large file sizes will be copied in multipart manner on server
 // side.
 func (c *s3Client) Copy(source string, size int64, progress io.Reader, srcSSE, tgtSSE encrypt.ServerSide, metadata map[string]string) *probe.Error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	dstBucket, dstObject := c.url2BucketAndObject()
 	if dstBucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -762,6 +858,9 @@ func (c *s3Client) Copy(source string, size int64, progress io.Reader, srcSSE, t
 
 // Put - upload an object with custom metadata.
 func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide) (int64, *probe.Error) {
+	if err := c.checkWritable(); err != nil {
+		return 0, err
+	}
 	bucket, object := c.url2BucketAndObject()
 	contentType, ok := metadata["Content-Type"]
 	if ok {
@@ -791,6 +890,13 @@ func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metada
 		delete(metadata, "Content-Language")
 	}
 
+	// minio-go refuses Expires as user metadata and has no option for
+	// it, it is added to the request creating the object.
+	if expires, ok := metadata["Expires"]; ok {
+		delete(metadata, "Expires")
+		ctx = withObjectHeaders(ctx, http.Header{"Expires": {expires}})
+	}
+
 	storageClass, ok := metadata["X-Amz-Storage-Class"]
 	if ok {
 		delete(metadata, "X-Amz-Storage-Class")
@@ -798,6 +904,10 @@ func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metada
 	if bucket == "" {
 		return 0, probe.NewError(BucketNameEmpty{})
 	}
+	partSize, err := c.multipart.uploadPartSize(size)
+	if err != nil {
+		return 0, err.Trace(c.targetURL.String())
+	}
 	opts := minio.PutObjectOptions{
 		UserMetadata:         metadata,
 		Progress:             progress,
@@ -809,9 +919,16 @@ func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metada
 		ContentLanguage:      contentLanguage,
 		StorageClass:         strings.ToUpper(storageClass),
 		ServerSideEncryption: sse,
+		PartSize:             partSize,
 	}
 	n, e := c.api.PutObjectWithContext(ctx, bucket, object, reader, size, opts)
 	if e != nil {
+		if ctx.Err() != nil || globalContext.Err() != nil {
+			// A cancelled multipart upload is not aborted by minio-go,
+			// its parts would be kept and billed.
+			c.api.RemoveIncompleteUpload(bucket, object)
+			return n, errCancelled().Trace(c.targetURL.String())
+		}
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "UnexpectedEOF" || e == io.EOF {
 			return n, probe.NewError(UnexpectedEOF{
@@ -874,6 +991,17 @@ func (c *s3Client) removeIncompleteObjects(bucket string, objectsCh <-chan strin
 func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *clientContent) <-chan *probe.Error {
 	errorCh := make(chan *probe.Error)
 
+	if err := c.checkWritable(); err != nil {
+		go func() {
+			defer close(errorCh)
+			errorCh <- err
+			// Drain contentCh, so that senders are not blocked.
+			for range contentCh {
+			}
+		}()
+		return errorCh
+	}
+
 	prevBucket := ""
 	// Maintain objectsCh, statusCh for each bucket
 	var objectsCh chan string
@@ -913,6 +1041,8 @@ func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 				if isRemoveBucket && !isIncomplete {
 					if err := c.api.RemoveBucket(prevBucket); err != nil {
 						errorCh <- probe.NewError(err)
+					} else {
+						c.cacheBucketRegion(prevBucket, "")
 					}
 				}
 				// Re-init objectsCh for next bucket
@@ -925,7 +1055,14 @@ func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 				prevBucket = bucket
 			}
 
-			if objectName != "" {
+			if objectName != "" && isIncomplete && content.UploadID != "" {
+				// Abort only the listed upload, other uploads of the
+				// same object may not match the caller's filters.
+				core := minio.Core{Client: c.api}
+				if e := core.AbortMultipartUpload(bucket, objectName, content.UploadID); e != nil {
+					errorCh <- probe.NewError(e)
+				}
+			} else if objectName != "" {
 				// Send object name once but continuously checks for pending
 				// errors in parallel, the reason is that minio-go RemoveObjects
 				// can block if there is any pending error not received yet.
@@ -960,14 +1097,37 @@ func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 		if isRemoveBucket && prevBucket != "" && !isIncomplete {
 			if err := c.api.RemoveBucket(prevBucket); err != nil {
 				errorCh <- probe.NewError(err)
+			} else {
+				c.cacheBucketRegion(prevBucket, "")
 			}
 		}
 	}()
 	return errorCh
 }
 
+// checkWritable - refuse operations modifying the target in read-only mode.
+func (c *s3Client) checkWritable() *probe.Error {
+	if globalReadOnly {
+		return probe.NewError(ReadOnlyMode{URL: c.targetURL.String()})
+	}
+	return nil
+}
+
 // MakeBucket - make a new bucket.
 func (c *s3Client) MakeBucket(region string, ignoreExisting bool) *probe.Error {
+	return c.makeBucket(region, ignoreExisting, false)
+}
+
+// MakeBucketWithLock - make a new bucket with object lock enabled, which
+// can only be enabled when the bucket is created.
+func (c *s3Client) MakeBucketWithLock(region string, ignoreExisting bool) *probe.Error {
+	return c.makeBucket(region, ignoreExisting, true)
+}
+
+func (c *s3Client) makeBucket(region string, ignoreExisting, withLock bool) *probe.Error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -978,7 +1138,7 @@ func (c *s3Client) MakeBucket(region string, ignoreExisting bool) *probe.Error {
 			if _, e := c.api.PutObject(bucket, object, bytes.NewReader([]byte("")), 0, minio.PutObjectOptions{}); e != nil {
 				switch minio.ToErrorResponse(e).Code {
 				case "NoSuchBucket":
-					e = c.api.MakeBucket(bucket, region)
+					e = c.createBucket(bucket, region, withLock)
 					if e != nil {
 						return probe.NewError(e)
 					}
@@ -991,7 +1151,7 @@ func (c *s3Client) MakeBucket(region string, ignoreExisting bool) *probe.Error {
 		return probe.NewError(BucketNameTopLevel{})
 	}
 
-	e := c.api.MakeBucket(bucket, region)
+	e := c.createBucket(bucket, region, withLock)
 	if e != nil {
 		// Ignore bucket already existing error when ignoreExisting flag is enabled
 		if ignoreExisting {
@@ -1004,6 +1164,35 @@ func (c *s3Client) MakeBucket(region string, ignoreExisting bool) *probe.Error {
 		}
 		return probe.NewError(e)
 	}
+	c.cacheBucketRegion(bucket, region)
+	return nil
+}
+
+// createBucket - create a bucket, minio-go does not support creating
+// buckets with object lock enabled so these are created by hand.
+func (c *s3Client) createBucket(bucket, region string, withLock bool) error {
+	if !withLock {
+		return c.api.MakeBucket(bucket, region)
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	var body []byte
+	if region != "us-east-1" {
+		var e error
+		body, e = xml.Marshal(struct {
+			XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CreateBucketConfiguration"`
+			Location string   `xml:"LocationConstraint"`
+		}{Location: region})
+		if e != nil {
+			return e
+		}
+	}
+	header := make(http.Header)
+	header.Set("X-Amz-Bucket-Object-Lock-Enabled", "true")
+	if err := c.putBucketConfig(bucket, "", region, header, body); err != nil {
+		return err.ToGoError()
+	}
 	return nil
 }
 
@@ -1059,6 +1248,9 @@ func (c *s3Client) GetAccess() (string, string, *probe.Error) {
 
 // SetAccess set access policy permissions.
 func (c *s3Client) SetAccess(bucketPolicy string, isJSON bool) *probe.Error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -1097,17 +1289,87 @@ func (c *s3Client) SetAccess(bucketPolicy string, isJSON bool) *probe.Error {
 }
 
 // listObjectWrapper - select ObjectList version depending on the target hostname
-func (c *s3Client) listObjectWrapper(bucket, object string, isRecursive bool, doneCh chan struct{}) <-chan minio.ObjectInfo {
+func (c *s3Client) listObjectWrapper(bucket, object string, isRecursive bool, doneCh <-chan struct{}) <-chan minio.ObjectInfo {
 	if isAmazon(c.targetURL.Host) || isAmazonAccelerated(c.targetURL.Host) {
 		return c.api.ListObjectsV2(bucket, object, isRecursive, doneCh)
 	}
 	return c.api.ListObjects(bucket, object, isRecursive, doneCh)
 }
 
+// ListDelimited - list the keys starting with the object of the target,
+// grouped by delimiter. Prefixes are listed as folders ending with the
+// delimiter, an empty delimiter lists all keys.
+func (c *s3Client) ListDelimited(delimiter string) <-chan *clientContent {
+	contentCh := make(chan *clientContent)
+	go func() {
+		defer close(contentCh)
+		bucket, prefix := c.url2BucketAndObject()
+		if bucket == "" {
+			contentCh <- &clientContent{Err: probe.NewError(BucketNameEmpty{})}
+			return
+		}
+		core := minio.Core{Client: c.api}
+		isV2 := isAmazon(c.targetURL.Host) || isAmazonAccelerated(c.targetURL.Host)
+		marker := ""
+		for {
+			var prefixes []minio.CommonPrefix
+			var objects []minio.ObjectInfo
+			var truncated bool
+			if isV2 {
+				result, e := core.ListObjectsV2(bucket, prefix, marker, false, delimiter, 1000, "")
+				if e != nil {
+					contentCh <- &clientContent{Err: probe.NewError(e)}
+					return
+				}
+				prefixes, objects, truncated = result.CommonPrefixes, result.Contents, result.IsTruncated
+				marker = result.NextContinuationToken
+			} else {
+				result, e := core.ListObjects(bucket, prefix, marker, delimiter, 1000)
+				if e != nil {
+					contentCh <- &clientContent{Err: probe.NewError(e)}
+					return
+				}
+				prefixes, objects, truncated = result.CommonPrefixes, result.Contents, result.IsTruncated
+				// Servers only return the next marker with a delimiter,
+				// continue after the last key or prefix otherwise.
+				marker = result.NextMarker
+				if marker == "" {
+					if len(objects) > 0 {
+						marker = objects[len(objects)-1].Key
+					}
+					if len(prefixes) > 0 && prefixes[len(prefixes)-1].Prefix > marker {
+						marker = prefixes[len(prefixes)-1].Prefix
+					}
+				}
+			}
+			// Both lists are sorted, merge them to list in key order.
+			for len(prefixes) > 0 || len(objects) > 0 {
+				if len(objects) == 0 || (len(prefixes) > 0 && prefixes[0].Prefix < objects[0].Key) {
+					url := *c.targetURL
+					url.Path = c.joinPath(bucket, prefixes[0].Prefix)
+					contentCh <- &clientContent{URL: url, Type: os.ModeDir}
+					prefixes = prefixes[1:]
+					continue
+				}
+				content := c.objectInfo2ClientContent(bucket, objects[0])
+				contentCh <- &content
+				objects = objects[1:]
+			}
+			if !truncated || marker == "" {
+				return
+			}
+		}
+	}()
+	return contentCh
+}
+
 // Stat - send a 'HEAD' on a bucket or object to fetch its metadata.
-func (c *s3Client) Stat(isIncomplete, isFetchMeta bool, sse encrypt.ServerSide) (*clientContent, *probe.Error) {
+func (c *s3Client) Stat(ctx context.Context, isIncomplete, isFetchMeta bool, sse encrypt.ServerSide) (*clientContent, *probe.Error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	if at := rewindTime(c.targetURL.String()); !at.IsZero() && !isIncomplete {
+		return c.statRewind(at, isFetchMeta, sse)
+	}
 	bucket, object := c.url2BucketAndObject()
 	// Bucket name cannot be empty, stat on URL has no meaning.
 	if bucket == "" {
@@ -1138,7 +1400,7 @@ func (c *s3Client) Stat(isIncomplete, isFetchMeta bool, sse encrypt.ServerSide)
 
 	// If the request is for incomplete upload stat, handle it here.
 	if isIncomplete {
-		for objectMultipartInfo := range c.api.ListIncompleteUploads(bucket, prefix, nonRecursive, nil) {
+		for objectMultipartInfo := range c.api.ListIncompleteUploads(bucket, prefix, nonRecursive, ctx.Done()) {
 			if objectMultipartInfo.Err != nil {
 				return nil, probe.NewError(objectMultipartInfo.Err)
 			}
@@ -1147,6 +1409,7 @@ func (c *s3Client) Stat(isIncomplete, isFetchMeta bool, sse encrypt.ServerSide)
 				objectMetadata.URL = *c.targetURL
 				objectMetadata.Time = objectMultipartInfo.Initiated
 				objectMetadata.Size = objectMultipartInfo.Size
+				objectMetadata.UploadID = objectMultipartInfo.UploadID
 				objectMetadata.Type = os.FileMode(0664)
 				objectMetadata.Metadata = map[string]string{}
 				objectMetadata.EncryptionHeaders = map[string]string{}
@@ -1167,8 +1430,12 @@ func (c *s3Client) Stat(isIncomplete, isFetchMeta bool, sse encrypt.ServerSide)
 
 	opts := minio.StatObjectOptions{}
 	opts.ServerSideEncryption = sse
+	if isFetchMeta {
+		// Checksums stored with objects are only returned on request.
+		opts.Set(amzChecksumMode, "ENABLED")
+	}
 
-	for objectStat := range c.listObjectWrapper(bucket, prefix, nonRecursive, nil) {
+	for objectStat := range c.listObjectWrapper(bucket, prefix, nonRecursive, ctx.Done()) {
 		if objectStat.Err != nil {
 			return nil, probe.NewError(objectStat.Err)
 		}
@@ -1204,11 +1471,94 @@ func (c *s3Client) Stat(isIncomplete, isFetchMeta bool, sse encrypt.ServerSide)
 				objectMetadata.Metadata = stat.Metadata
 				objectMetadata.EncryptionHeaders = stat.EncryptionHeaders
 				objectMetadata.Expires = stat.Expires
+				objectMetadata.Tags = c.statObjectTags(bucket, object, stat.Metadata)
 			}
 			return objectMetadata, nil
 		}
 	}
-	return c.getObjectStat(bucket, object, opts)
+	objectMetadata, err := c.getObjectStat(bucket, object, opts)
+	if err != nil {
+		return nil, err
+	}
+	if isFetchMeta {
+		objectMetadata.Tags = c.statObjectTags(bucket, object, objectMetadata.Metadata)
+	}
+	return objectMetadata, nil
+}
+
+// Headers of object tags and checksums.
+const (
+	amzTaggingCount = "X-Amz-Tagging-Count"
+	amzChecksumMode = "X-Amz-Checksum-Mode"
+)
+
+// getObjectTags - get the tags of an object.
+func (c *s3Client) getObjectTags(bucket, object string) (map[string]string, *probe.Error) {
+	reqParams := make(url.Values)
+	reqParams.Set("tagging", "")
+	resp, err := c.presignedDo(http.MethodGet, bucket, object, reqParams)
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	defer resp.Body.Close()
+	var tagging struct {
+		TagSet []struct {
+			Key   string `xml:"Key"`
+			Value string `xml:"Value"`
+		} `xml:"TagSet>Tag"`
+	}
+	if e := xml.NewDecoder(resp.Body).Decode(&tagging); e != nil {
+		return nil, probe.NewError(e).Trace(bucket, object)
+	}
+	tags := make(map[string]string)
+	for _, tag := range tagging.TagSet {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+// setObjectTags - replace the tags of an object.
+func (c *s3Client) setObjectTags(bucket, object string, tags map[string]string) *probe.Error {
+	type tag struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	}
+	var tagging struct {
+		XMLName xml.Name `xml:"Tagging"`
+		TagSet  []tag    `xml:"TagSet>Tag"`
+	}
+	for key, value := range tags {
+		tagging.TagSet = append(tagging.TagSet, tag{key, value})
+	}
+	sort.Slice(tagging.TagSet, func(i, j int) bool { return tagging.TagSet[i].Key < tagging.TagSet[j].Key })
+	body, e := xml.Marshal(tagging)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	md5Sum := md5.Sum(body)
+	header := make(http.Header)
+	header.Set("Content-Md5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+	reqParams := make(url.Values)
+	reqParams.Set("tagging", "")
+	resp, err := c.presignedDoWithBody(http.MethodPut, bucket, object, reqParams, header, body)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// statObjectTags - tags of an object the server counted tags for. Tags
+// need their own permission, they are left out when they cannot be read.
+func (c *s3Client) statObjectTags(bucket, object string, metadata map[string]string) map[string]string {
+	if count, _ := strconv.Atoi(metadata[amzTaggingCount]); count == 0 {
+		return nil
+	}
+	tags, err := c.getObjectTags(bucket, object)
+	if err != nil {
+		return nil
+	}
+	return tags
 }
 
 // getObjectStat returns the metadata of an object from a HEAD call.
@@ -1280,6 +1630,23 @@ func isAmazonAccelerated(host string) bool {
 	return host == "s3-accelerate.amazonaws.com"
 }
 
+// canAccelerate - whether requests to an AWS S3 endpoint can use
+// transfer acceleration, which is not available in China, GovCloud and
+// on FIPS endpoints.
+func canAccelerate(host string) bool {
+	u := url.URL{Host: host}
+	return isAmazon(host) && !isAmazonChina(host) && !isAmazonAccelerated(host) &&
+		!s3utils.IsAmazonGovCloudEndpoint(u) && !s3utils.IsAmazonFIPSEndpoint(u)
+}
+
+// accelerateEndpoint - the host of accelerated requests.
+func accelerateEndpoint(dualStack bool) string {
+	if dualStack {
+		return amazonHostNameAcceleratedDualStack
+	}
+	return amazonHostNameAccelerated
+}
+
 func isGoogle(host string) bool {
 	return s3utils.IsGoogleEndpoint(url.URL{Host: host})
 }
@@ -1352,37 +1719,41 @@ func (c *s3Client) splitPath(path string) (bucketName, objectName string) {
 /// Bucket API operations.
 
 // List - list at delimited path, if not recursive.
-func (c *s3Client) List(isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent {
+func (c *s3Client) List(ctx context.Context, isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
 	contentCh := make(chan *clientContent)
+	if at := rewindTime(c.targetURL.String()); !at.IsZero() && !isIncomplete {
+		go c.listRewindInRoutine(contentCh, at, isRecursive)
+		return contentCh
+	}
 	if isIncomplete {
 		if isRecursive {
 			if showDir == DirNone {
-				go c.listIncompleteRecursiveInRoutine(contentCh)
+				go c.listIncompleteRecursiveInRoutine(ctx, contentCh)
 			} else {
-				go c.listIncompleteRecursiveInRoutineDirOpt(contentCh, showDir)
+				go c.listIncompleteRecursiveInRoutineDirOpt(ctx, contentCh, showDir)
 			}
 		} else {
-			go c.listIncompleteInRoutine(contentCh)
+			go c.listIncompleteInRoutine(ctx, contentCh)
 		}
 	} else {
 		if isRecursive {
 			if showDir == DirNone {
-				go c.listRecursiveInRoutine(contentCh)
+				go c.listRecursiveInRoutine(ctx, contentCh)
 			} else {
-				go c.listRecursiveInRoutineDirOpt(contentCh, showDir)
+				go c.listRecursiveInRoutineDirOpt(ctx, contentCh, showDir)
 			}
 		} else {
-			go c.listInRoutine(contentCh)
+			go c.listInRoutine(ctx, contentCh)
 		}
 	}
 
 	return contentCh
 }
 
-func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
+func (c *s3Client) listIncompleteInRoutine(ctx context.Context, contentCh chan *clientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
@@ -1397,7 +1768,7 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 		}
 		isRecursive := false
 		for _, bucket := range buckets {
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
+			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, ctx.Done()) {
 				if object.Err != nil {
 					contentCh <- &clientContent{
 						Err: probe.NewError(object.Err),
@@ -1419,13 +1790,14 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 					content.Size = object.Size
 					content.Time = object.Initiated
 					content.Type = os.ModeTemporary
+					content.UploadID = object.UploadID
 				}
 				contentCh <- content
 			}
 		}
 	default:
 		isRecursive := false
-		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, nil) {
+		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, ctx.Done()) {
 			if object.Err != nil {
 				contentCh <- &clientContent{
 					Err: probe.NewError(object.Err),
@@ -1447,13 +1819,14 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 				content.Size = object.Size
 				content.Time = object.Initiated
 				content.Type = os.ModeTemporary
+				content.UploadID = object.UploadID
 			}
 			contentCh <- content
 		}
 	}
 }
 
-func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientContent) {
+func (c *s3Client) listIncompleteRecursiveInRoutine(ctx context.Context, contentCh chan *clientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
@@ -1468,7 +1841,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 		}
 		isRecursive := true
 		for _, bucket := range buckets {
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
+			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, ctx.Done()) {
 				if object.Err != nil {
 					contentCh <- &clientContent{
 						Err: probe.NewError(object.Err),
@@ -1482,12 +1855,13 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 				content.Size = object.Size
 				content.Time = object.Initiated
 				content.Type = os.ModeTemporary
+				content.UploadID = object.UploadID
 				contentCh <- content
 			}
 		}
 	default:
 		isRecursive := true
-		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, nil) {
+		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, ctx.Done()) {
 			if object.Err != nil {
 				contentCh <- &clientContent{
 					Err: probe.NewError(object.Err),
@@ -1502,6 +1876,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 			content.Size = object.Size
 			content.Time = object.Initiated
 			content.Type = os.ModeTemporary
+			content.UploadID = object.UploadID
 			contentCh <- content
 		}
 	}
@@ -1517,6 +1892,7 @@ func (c *s3Client) objectMultipartInfo2ClientContent(bucket string, entry minio.
 	content.URL = url
 	content.Size = entry.Size
 	content.Time = entry.Initiated
+	content.UploadID = entry.UploadID
 
 	if strings.HasSuffix(entry.Key, "/") {
 		content.Type = os.ModeDir
@@ -1528,7 +1904,7 @@ func (c *s3Client) objectMultipartInfo2ClientContent(bucket string, entry minio.
 }
 
 // Recursively lists incomplete uploads.
-func (c *s3Client) listIncompleteRecursiveInRoutineDirOpt(contentCh chan *clientContent, dirOpt DirOpt) {
+func (c *s3Client) listIncompleteRecursiveInRoutineDirOpt(ctx context.Context, contentCh chan *clientContent, dirOpt DirOpt) {
 	defer close(contentCh)
 
 	// Closure function reads list of incomplete uploads and sends to contentCh. If a directory is found, it lists
@@ -1536,7 +1912,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutineDirOpt(contentCh chan *client
 	var listDir func(bucket, object string) bool
 	listDir = func(bucket, object string) (isStop bool) {
 		isRecursive := false
-		for entry := range c.api.ListIncompleteUploads(bucket, object, isRecursive, nil) {
+		for entry := range c.api.ListIncompleteUploads(bucket, object, isRecursive, ctx.Done()) {
 			if entry.Err != nil {
 				url := *c.targetURL
 				url.Path = c.joinPath(bucket, object)
@@ -1595,7 +1971,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutineDirOpt(contentCh chan *client
 	} else if strings.HasSuffix(object, string(c.targetURL.Separator)) {
 		// Get stat of given object is a directory.
 		isIncomplete := true
-		content, perr := c.Stat(isIncomplete, false, nil)
+		content, perr := c.Stat(ctx, isIncomplete, false, nil)
 		cContent = content
 		if perr != nil {
 			contentCh <- &clientContent{Err: perr.Trace(bucket)}
@@ -1645,6 +2021,7 @@ func (c *s3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 	content.Size = entry.Size
 	content.ETag = entry.ETag
 	content.Time = entry.LastModified
+	content.StorageClass = entry.StorageClass
 
 	if strings.HasSuffix(entry.Key, "/") && entry.Size == 0 && entry.LastModified.IsZero() {
 		content.Type = os.ModeDir
@@ -1668,14 +2045,14 @@ func (c *s3Client) bucketStat(bucket string) (*clientContent, *probe.Error) {
 }
 
 // Recursively lists objects.
-func (c *s3Client) listRecursiveInRoutineDirOpt(contentCh chan *clientContent, dirOpt DirOpt) {
+func (c *s3Client) listRecursiveInRoutineDirOpt(ctx context.Context, contentCh chan *clientContent, dirOpt DirOpt) {
 	defer close(contentCh)
 	// Closure function reads list objects and sends to contentCh. If a directory is found, it lists
 	// objects of the directory content recursively.
 	var listDir func(bucket, object string) bool
 	listDir = func(bucket, object string) (isStop bool) {
 		isRecursive := false
-		for entry := range c.listObjectWrapper(bucket, object, isRecursive, nil) {
+		for entry := range c.listObjectWrapper(bucket, object, isRecursive, ctx.Done()) {
 			if entry.Err != nil {
 				url := *c.targetURL
 				url.Path = c.joinPath(bucket, object)
@@ -1734,7 +2111,7 @@ func (c *s3Client) listRecursiveInRoutineDirOpt(contentCh chan *clientContent, d
 		// Get stat of given object is a directory.
 		isIncomplete := false
 		isFetchMeta := false
-		content, perr := c.Stat(isIncomplete, isFetchMeta, nil)
+		content, perr := c.Stat(ctx, isIncomplete, isFetchMeta, nil)
 		cContent = content
 		if perr != nil {
 			contentCh <- &clientContent{Err: perr.Trace(bucket)}
@@ -1765,7 +2142,7 @@ func (c *s3Client) listRecursiveInRoutineDirOpt(contentCh chan *clientContent, d
 	}
 }
 
-func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
+func (c *s3Client) listInRoutine(ctx context.Context, contentCh chan *clientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
@@ -1797,7 +2174,7 @@ func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
 		contentCh <- content
 	default:
 		isRecursive := false
-		for object := range c.listObjectWrapper(b, o, isRecursive, nil) {
+		for object := range c.listObjectWrapper(b, o, isRecursive, ctx.Done()) {
 			if object.Err != nil {
 				contentCh <- &clientContent{
 					Err: probe.NewError(object.Err),
@@ -1824,6 +2201,7 @@ func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
 				content.URL = url
 				content.Size = object.Size
 				content.ETag = object.ETag
+				content.StorageClass = object.StorageClass
 				content.Time = object.LastModified
 				content.Type = os.FileMode(0664)
 			}
@@ -1845,7 +2223,7 @@ const (
 	s3StorageClassGlacier = "GLACIER"
 )
 
-func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
+func (c *s3Client) listRecursiveInRoutine(ctx context.Context, contentCh chan *clientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
@@ -1859,8 +2237,7 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
 			return
 		}
 		for _, bucket := range buckets {
-			isRecursive := true
-			for object := range c.listObjectWrapper(bucket.Name, o, isRecursive, nil) {
+			for object := range c.listRecursive(bucket.Name, o, ctx.Done()) {
 				// Return error if we encountered glacier object and continue.
 				if object.StorageClass == s3StorageClassGlacier {
 					contentCh <- &clientContent{
@@ -1880,14 +2257,14 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
 				content.URL = objectURL
 				content.Size = object.Size
 				content.ETag = object.ETag
+				content.StorageClass = object.StorageClass
 				content.Time = object.LastModified
 				content.Type = os.FileMode(0664)
 				contentCh <- content
 			}
 		}
 	default:
-		isRecursive := true
-		for object := range c.listObjectWrapper(b, o, isRecursive, nil) {
+		for object := range c.listRecursive(b, o, ctx.Done()) {
 			if object.Err != nil {
 				contentCh <- &clientContent{
 					Err: probe.NewError(object.Err),
@@ -1901,6 +2278,7 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
 			content.URL = url
 			content.Size = object.Size
 			content.ETag = object.ETag
+			content.StorageClass = object.StorageClass
 			content.Time = object.LastModified
 			content.Type = os.FileMode(0664)
 			contentCh <- content
@@ -1908,20 +2286,48 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
 	}
 }
 
-// ShareDownload - get a usable presigned object url to share.
-func (c *s3Client) ShareDownload(expires time.Duration) (string, *probe.Error) {
+// ShareDownload - get a usable presigned object url to share, reqParams
+// may override response headers like `response-content-disposition`.
+func (c *s3Client) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
-	// No additional request parameters are set for the time being.
-	reqParams := make(url.Values)
-	presignedURL, e := c.api.PresignedGetObject(bucket, object, expires, reqParams)
+	if reqParams == nil {
+		reqParams = make(url.Values)
+	}
+	presignedURL, e := c.api.PresignedGetObject(bucket, object, expires, c.requesterPaysParams(reqParams))
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	return presignedURL.String(), nil
+}
+
+// ShareRequest - get a presigned object url for another request method
+// than GET, such as HEAD or DELETE.
+func (c *s3Client) ShareRequest(method string, expires time.Duration, reqParams url.Values) (string, *probe.Error) {
+	// Presigned DELETE requests remove data, they are not shared in
+	// read-only mode.
+	if method != http.MethodGet && method != http.MethodHead {
+		if err := c.checkWritable(); err != nil {
+			return "", err
+		}
+	}
+	bucket, object := c.url2BucketAndObject()
+	if reqParams == nil {
+		reqParams = make(url.Values)
+	}
+	presignedURL, e := c.api.Presign(method, bucket, object, expires, c.requesterPaysParams(reqParams))
 	if e != nil {
 		return "", probe.NewError(e)
 	}
 	return presignedURL.String(), nil
 }
 
-// ShareUpload - get data for presigned post http form upload.
-func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentType string) (string, map[string]string, *probe.Error) {
+// ShareUpload - get data for presigned post http form upload, uploads
+// larger than a non-zero maxSize are rejected.
+func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentType string, maxSize int64) (string, map[string]string, *probe.Error) {
+	// Upload URLs grant write access, they are not shared in read-only mode.
+	if err := c.checkWritable(); err != nil {
+		return "", nil, err
+	}
 	bucket, object := c.url2BucketAndObject()
 	p := minio.NewPostPolicy()
 	if e := p.SetExpires(UTCNow().Add(expires)); e != nil {
@@ -1931,6 +2337,11 @@ func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentT
 		// No need to verify for error here, since we have stripped out spaces.
 		p.SetContentType(contentType)
 	}
+	if maxSize > 0 {
+		if e := p.SetContentLengthRange(0, maxSize); e != nil {
+			return "", nil, probe.NewError(e)
+		}
+	}
 	if e := p.SetBucket(bucket); e != nil {
 		return "", nil, probe.NewError(e)
 	}
@@ -1949,3 +2360,656 @@ func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentT
 	}
 	return u.String(), m, nil
 }
+
+// getBucketConfig - fetch a bucket sub-resource such as `versioning` and
+// decode its XML into v. minio-go does not expose these calls, so the
+// request is presigned and sent through the same transport.
+func (c *s3Client) getBucketConfig(resource string, v interface{}) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	reqParams := make(url.Values)
+	reqParams.Set(resource, "")
+	resp, err := c.presignedDo(http.MethodGet, bucket, "", reqParams)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if e := xml.NewDecoder(resp.Body).Decode(v); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// presignedDo - presign a request and send it through the same transport
+// as minio-go, failed responses are returned as errors.
+func (c *s3Client) presignedDo(method, bucket, object string, reqParams url.Values) (*http.Response, *probe.Error) {
+	return c.presignedDoWithHeader(method, bucket, object, reqParams, nil)
+}
+
+// presignedDoWithHeader - like presignedDo, sending additional headers
+// such as the keys of SSE-C encrypted objects, which presigned URLs do
+// not sign.
+func (c *s3Client) presignedDoWithHeader(method, bucket, object string, reqParams url.Values, header http.Header) (*http.Response, *probe.Error) {
+	return c.presignedDoWithBody(method, bucket, object, reqParams, header, nil)
+}
+
+// presignedDoWithBody - like presignedDoWithHeader, sending a body such
+// as the XML of a sub-resource, the payload is not signed.
+func (c *s3Client) presignedDoWithBody(method, bucket, object string, reqParams url.Values, header http.Header, body []byte) (*http.Response, *probe.Error) {
+	if method != http.MethodGet && method != http.MethodHead {
+		if err := c.checkWritable(); err != nil {
+			return nil, err
+		}
+	}
+	u, e := c.api.Presign(method, bucket, object, 5*time.Minute, c.requesterPaysParams(reqParams))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	req, e := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+	transport := c.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, e := (&http.Client{Transport: transport}).Do(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		defer resp.Body.Close()
+		return nil, probe.NewError(httpRespToError(resp, bucket))
+	}
+	return resp, nil
+}
+
+// putBucketConfig - send a signed PUT of a bucket sub-resource such as
+// `versioning`, or of the bucket itself for an empty resource, for calls
+// minio-go does not expose or needing headers it can not set.
+func (c *s3Client) putBucketConfig(bucket, resource, region string, header http.Header, body []byte) *probe.Error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	host, urlPath := c.targetURL.Host, "/"+bucket+"/"
+	if c.virtualStyle {
+		host, urlPath = bucket+"."+host, "/"
+	}
+	reqURL := c.targetURL.Scheme + "://" + host + urlPath
+	if resource != "" {
+		reqURL += "?" + resource
+	}
+	req, e := http.NewRequest(http.MethodPut, reqURL, bytes.NewReader(body))
+	if e != nil {
+		return probe.NewError(e)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+	if len(body) > 0 {
+		md5Sum := md5.Sum(body)
+		req.Header.Set("Content-Md5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+	}
+	sha256Sum := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum[:]))
+	if strings.ToUpper(c.signature) == "S3V2" {
+		if c.sessionToken != "" {
+			req.Header.Set("X-Amz-Security-Token", c.sessionToken)
+		}
+		req = s3signer.SignV2(*req, c.accessKey, c.secretKey, c.virtualStyle)
+	} else {
+		req = s3signer.SignV4(*req, c.accessKey, c.secretKey, c.sessionToken, region)
+	}
+
+	transport := c.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, e := (&http.Client{Transport: transport}).Do(req)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return probe.NewError(httpRespToError(resp, bucket))
+	}
+	return nil
+}
+
+// httpRespToError - decode the S3 error of a failed response.
+func httpRespToError(resp *http.Response, bucket string) error {
+	errResp := minio.ErrorResponse{StatusCode: resp.StatusCode, BucketName: bucket}
+	if e := xml.NewDecoder(resp.Body).Decode(&errResp); e != nil || errResp.Code == "" {
+		errResp.Code = resp.Status
+		errResp.Message = http.StatusText(resp.StatusCode)
+	}
+	return errResp
+}
+
+// GetVersioning - get the versioning status of the bucket, empty when
+// versioning was never enabled.
+func (c *s3Client) GetVersioning() (string, *probe.Error) {
+	var versioning struct {
+		Status string `xml:"Status"`
+	}
+	if err := c.getBucketConfig("versioning", &versioning); err != nil {
+		return "", err.Trace(c.targetURL.String())
+	}
+	return versioning.Status, nil
+}
+
+// SetVersioning - enable or suspend versioning of the bucket.
+func (c *s3Client) SetVersioning(status string) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	region, e := c.api.GetBucketLocation(bucket)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	body, e := xml.Marshal(struct {
+		XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration"`
+		Status  string   `xml:"Status"`
+	}{Status: status})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if err := c.putBucketConfig(bucket, "versioning", region, nil, body); err != nil {
+		return err.Trace(c.targetURL.String())
+	}
+	return nil
+}
+
+// objectVersion - a version or delete marker of an object.
+type objectVersion struct {
+	Key          string    `xml:"Key"`
+	VersionID    string    `xml:"VersionId"`
+	IsLatest     bool      `xml:"IsLatest"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+	Size         int64     `xml:"Size"`
+	StorageClass string    `xml:"StorageClass"`
+
+	isDeleteMarker bool
+}
+
+// listVersionsResult - a page of the ListObjectVersions response.
+type listVersionsResult struct {
+	IsTruncated         bool            `xml:"IsTruncated"`
+	NextKeyMarker       string          `xml:"NextKeyMarker"`
+	NextVersionIDMarker string          `xml:"NextVersionIdMarker"`
+	Versions            []objectVersion `xml:"Version"`
+	DeleteMarkers       []objectVersion `xml:"DeleteMarker"`
+}
+
+// RemoveAllVersions - remove all object versions and delete markers of
+// the bucket, progress is called with the number of versions removed so
+// far after every page of the listing.
+func (c *s3Client) RemoveAllVersions(progress func(removed int)) (int, *probe.Error) {
+	if err := c.checkWritable(); err != nil {
+		return 0, err
+	}
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	var removed int
+	var keyMarker, versionIDMarker string
+	for {
+		result, err := c.listVersionsPage(bucket, "", keyMarker, versionIDMarker)
+		if err != nil {
+			return removed, err.Trace(bucket)
+		}
+
+		for _, version := range append(result.Versions, result.DeleteMarkers...) {
+			versionParams := make(url.Values)
+			versionParams.Set("versionId", version.VersionID)
+			resp, err := c.presignedDo(http.MethodDelete, bucket, version.Key, versionParams)
+			if err != nil {
+				return removed, err.Trace(bucket, version.Key, version.VersionID)
+			}
+			resp.Body.Close()
+			removed++
+		}
+		if progress != nil {
+			progress(removed)
+		}
+		if !result.IsTruncated {
+			return removed, nil
+		}
+		keyMarker, versionIDMarker = result.NextKeyMarker, result.NextVersionIDMarker
+	}
+}
+
+// listVersionsPage - list a page of the versions and delete markers of
+// the objects below prefix, starting after the given markers.
+func (c *s3Client) listVersionsPage(bucket, prefix, keyMarker, versionIDMarker string) (listVersionsResult, *probe.Error) {
+	var result listVersionsResult
+	reqParams := make(url.Values)
+	reqParams.Set("versions", "")
+	if prefix != "" {
+		reqParams.Set("prefix", prefix)
+	}
+	if keyMarker != "" {
+		reqParams.Set("key-marker", keyMarker)
+		reqParams.Set("version-id-marker", versionIDMarker)
+	}
+	resp, err := c.presignedDo(http.MethodGet, bucket, "", reqParams)
+	if err != nil {
+		return result, err.Trace(bucket, prefix)
+	}
+	defer resp.Body.Close()
+	if e := xml.NewDecoder(resp.Body).Decode(&result); e != nil {
+		return result, probe.NewError(e).Trace(bucket, prefix)
+	}
+	return result, nil
+}
+
+// countVersions - count the object versions and delete markers of the
+// bucket.
+func (c *s3Client) countVersions(bucket string) (int64, *probe.Error) {
+	var count int64
+	var keyMarker, versionIDMarker string
+	for {
+		result, err := c.listVersionsPage(bucket, "", keyMarker, versionIDMarker)
+		if err != nil {
+			return count, err.Trace(bucket)
+		}
+		count += int64(len(result.Versions) + len(result.DeleteMarkers))
+		if !result.IsTruncated {
+			return count, nil
+		}
+		keyMarker, versionIDMarker = result.NextKeyMarker, result.NextVersionIDMarker
+	}
+}
+
+// sortedVersions - versions and delete markers of a page are decoded
+// apart, sort them back by key and newest first.
+func sortedVersions(result listVersionsResult) []objectVersion {
+	versions := result.Versions
+	for _, marker := range result.DeleteMarkers {
+		marker.isDeleteMarker = true
+		versions = append(versions, marker)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].Key != versions[j].Key {
+			return versions[i].Key < versions[j].Key
+		}
+		if !versions[i].LastModified.Equal(versions[j].LastModified) {
+			return versions[i].LastModified.After(versions[j].LastModified)
+		}
+		return versions[i].IsLatest && !versions[j].IsLatest
+	})
+	return versions
+}
+
+// listVersionsSince - call fn with every version and delete marker of
+// the objects below prefix created after the given time. Listing stops
+// when fn returns false.
+func (c *s3Client) listVersionsSince(bucket, prefix string, since time.Time, fn func(version objectVersion) bool) *probe.Error {
+	var keyMarker, versionIDMarker string
+	for {
+		result, err := c.listVersionsPage(bucket, prefix, keyMarker, versionIDMarker)
+		if err != nil {
+			return err
+		}
+		for _, version := range sortedVersions(result) {
+			if !version.LastModified.After(since) {
+				continue
+			}
+			if !fn(version) {
+				return nil
+			}
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		keyMarker, versionIDMarker = result.NextKeyMarker, result.NextVersionIDMarker
+	}
+}
+
+// listVersionsAt - call fn with the version of every object below prefix
+// which was the latest one at the given time, objects created later or
+// deleted by then are skipped. Listing stops when fn returns false.
+func (c *s3Client) listVersionsAt(bucket, prefix string, at time.Time, fn func(version objectVersion) bool) *probe.Error {
+	// Key the version was already found for, versions of a key can
+	// continue on the next page.
+	var found string
+	var keyMarker, versionIDMarker string
+	for {
+		result, err := c.listVersionsPage(bucket, prefix, keyMarker, versionIDMarker)
+		if err != nil {
+			return err
+		}
+		for _, version := range sortedVersions(result) {
+			if version.Key == found || version.LastModified.After(at) {
+				continue
+			}
+			found = version.Key
+			if version.isDeleteMarker {
+				continue
+			}
+			if !fn(version) {
+				return nil
+			}
+		}
+		if !result.IsTruncated {
+			return nil
+		}
+		keyMarker, versionIDMarker = result.NextKeyMarker, result.NextVersionIDMarker
+	}
+}
+
+// version2ClientContent - convert a version of an object to clientContent.
+func (c *s3Client) version2ClientContent(bucket string, version objectVersion) *clientContent {
+	url := *c.targetURL
+	url.Path = c.joinPath(bucket, version.Key)
+	return &clientContent{
+		URL:          url,
+		Size:         version.Size,
+		ETag:         version.ETag,
+		Time:         version.LastModified,
+		Type:         os.FileMode(0664),
+		VersionID:    version.VersionID,
+		StorageClass: version.StorageClass,
+	}
+}
+
+// listRewindInRoutine - list objects as they were at the given time,
+// folders of a non-recursive listing are derived from the object keys.
+func (c *s3Client) listRewindInRoutine(contentCh chan *clientContent, at time.Time, isRecursive bool) {
+	defer close(contentCh)
+	sep := string(c.targetURL.Separator)
+	b, o := c.url2BucketAndObject()
+	switch {
+	case b == "":
+		contentCh <- &clientContent{Err: probe.NewError(BucketNameEmpty{})}
+		return
+	case !isRecursive && !strings.HasSuffix(c.targetURL.Path, sep) && o == "":
+		content, err := c.bucketStat(b)
+		if err != nil {
+			contentCh <- &clientContent{Err: err.Trace(b)}
+			return
+		}
+		contentCh <- content
+		return
+	}
+
+	var lastDir string
+	err := c.listVersionsAt(b, o, at, func(version objectVersion) bool {
+		// Avoid sending an empty directory when we are specifically listing it
+		if strings.HasSuffix(version.Key, sep) && o == version.Key {
+			return true
+		}
+		if !isRecursive {
+			if i := strings.Index(version.Key[len(o):], sep); i >= 0 {
+				// Keys of a folder are listed in a row.
+				if dir := version.Key[:len(o)+i+1]; dir != lastDir {
+					lastDir = dir
+					url := *c.targetURL
+					url.Path = c.joinPath(b, dir)
+					contentCh <- &clientContent{URL: url, Time: at, Type: os.ModeDir}
+				}
+				return true
+			}
+		}
+		contentCh <- c.version2ClientContent(b, version)
+		return true
+	})
+	if err != nil {
+		contentCh <- &clientContent{Err: err}
+	}
+}
+
+// statRewind - stat an object or folder as it was at the given time.
+func (c *s3Client) statRewind(at time.Time, isFetchMeta bool, sse encrypt.ServerSide) (*clientContent, *probe.Error) {
+	sep := string(c.targetURL.Separator)
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return nil, probe.NewError(BucketNameEmpty{})
+	}
+	if object == "" {
+		return c.bucketStat(bucket)
+	}
+
+	prefix := strings.TrimRight(object, sep)
+	var content *clientContent
+	err := c.listVersionsAt(bucket, prefix, at, func(version objectVersion) bool {
+		switch {
+		case version.Key == object:
+			content = c.version2ClientContent(bucket, version)
+			content.URL = *c.targetURL
+		case strings.HasPrefix(version.Key, prefix+sep):
+			content = &clientContent{URL: *c.targetURL, Time: at, Type: os.ModeDir}
+		default:
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	if content == nil {
+		return nil, probe.NewError(ObjectMissing{})
+	}
+	content.Metadata = map[string]string{}
+	content.EncryptionHeaders = map[string]string{}
+	if !isFetchMeta || content.Type.IsDir() {
+		return content, nil
+	}
+
+	header := make(http.Header)
+	if sse != nil {
+		sse.Marshal(header)
+	}
+	reqParams := make(url.Values)
+	reqParams.Set("versionId", content.VersionID)
+	resp, err := c.presignedDoWithHeader(http.MethodHead, bucket, object, reqParams, header)
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	content.Metadata["Content-Type"] = resp.Header.Get("Content-Type")
+	for k, v := range resp.Header {
+		switch {
+		case strings.HasPrefix(strings.ToLower(k), serverEncryptionKeyPrefix):
+			content.EncryptionHeaders[k] = v[0]
+		case strings.HasPrefix(strings.ToLower(k), "x-amz-meta-"):
+			content.Metadata[k] = v[0]
+		}
+	}
+	return content, nil
+}
+
+// getRewind - get an object as it was at the given time.
+func (c *s3Client) getRewind(at time.Time, sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
+	content, err := c.statRewind(at, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	if content.Type.IsDir() {
+		return nil, probe.NewError(ObjectMissing{})
+	}
+	bucket, object := c.url2BucketAndObject()
+	header := make(http.Header)
+	if sse != nil {
+		sse.Marshal(header)
+	}
+	reqParams := make(url.Values)
+	reqParams.Set("versionId", content.VersionID)
+	resp, err := c.presignedDoWithHeader(http.MethodGet, bucket, object, reqParams, header)
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	return resp.Body, nil
+}
+
+// AbortIncompleteUploads - abort all incomplete multipart uploads in the
+// bucket, returns the number of objects with aborted uploads.
+func (c *s3Client) AbortIncompleteUploads() (int, *probe.Error) {
+	if err := c.checkWritable(); err != nil {
+		return 0, err
+	}
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Uploads are listed before any is aborted, RemoveIncompleteUpload
+	// aborts all uploads of an object.
+	var objects []string
+	seen := make(map[string]bool)
+	for upload := range c.api.ListIncompleteUploads(bucket, "", true, doneCh) {
+		if upload.Err != nil {
+			return 0, probe.NewError(upload.Err).Trace(bucket)
+		}
+		if !seen[upload.Key] {
+			seen[upload.Key] = true
+			objects = append(objects, upload.Key)
+		}
+	}
+	for i, object := range objects {
+		if e := c.api.RemoveIncompleteUpload(bucket, object); e != nil {
+			return i, probe.NewError(e).Trace(bucket, object)
+		}
+	}
+	return len(objects), nil
+}
+
+// UploadParts - count the parts uploaded so far by an incomplete upload.
+func (c *s3Client) UploadParts(content *clientContent) (int, *probe.Error) {
+	bucket, object := c.splitPath(content.URL.Path)
+	core := minio.Core{Client: c.api}
+	parts, partNumberMarker := 0, 0
+	for {
+		result, e := core.ListObjectParts(bucket, object, content.UploadID, partNumberMarker, 1000)
+		if e != nil {
+			return 0, probe.NewError(e).Trace(bucket, object)
+		}
+		parts += len(result.ObjectParts)
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+}
+
+// PutStream - upload a stream of unknown length in parts, the next part
+// is read while a part is uploaded and only two parts are kept in memory.
+// Streams smaller than a part are uploaded with a single PUT. The size
+// hint is the expected size of the stream or -1, it sets the part size
+// when none is configured.
+func (c *s3Client) PutStream(ctx context.Context, reader io.Reader, sizeHint int64, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide) (int64, *probe.Error) {
+	if err := c.checkWritable(); err != nil {
+		return 0, err
+	}
+	partSize, err := c.multipart.streamPartSize(sizeHint)
+	if err != nil {
+		return 0, err.Trace(c.targetURL.String())
+	}
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+
+	buffers := [2][]byte{make([]byte, partSize), nil}
+	length, e := io.ReadFull(reader, buffers[0])
+	switch e {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		return c.Put(ctx, bytes.NewReader(buffers[0][:length]), int64(length), metadata, progress, sse)
+	default:
+		return 0, probe.NewError(e).Trace(c.targetURL.String())
+	}
+
+	opts := minio.PutObjectOptions{
+		UserMetadata:         make(map[string]string),
+		ServerSideEncryption: sse,
+	}
+	for k, v := range metadata {
+		if k == "Content-Type" {
+			opts.ContentType = v
+		} else {
+			opts.UserMetadata[k] = v
+		}
+	}
+	core := minio.Core{Client: c.api}
+	uploadID, e := core.NewMultipartUpload(bucket, object, opts)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(c.targetURL.String())
+	}
+
+	var parts []minio.CompletePart
+	var total int64
+	for partNumber := 1; length > 0; partNumber++ {
+		if partNumber > maxUploadParts {
+			e = fmt.Errorf("streams larger than %d parts of %s cannot be uploaded, use a larger --part-size or a --size-hint",
+				maxUploadParts, humanize.IBytes(uint64(partSize)))
+			break
+		}
+		if e = ctx.Err(); e != nil {
+			break
+		}
+		part := buffers[(partNumber-1)%2][:length]
+		uploadedCh := make(chan error, 1)
+		go func(partNumber int) {
+			objectPart, e := core.PutObjectPart(bucket, object, uploadID, partNumber,
+				hookreader.NewHook(bytes.NewReader(part), progress), int64(len(part)), "", "", sse)
+			parts = append(parts, minio.CompletePart{PartNumber: partNumber, ETag: objectPart.ETag})
+			uploadedCh <- e
+		}(partNumber)
+
+		next := buffers[partNumber%2]
+		if next == nil {
+			next = make([]byte, partSize)
+			buffers[partNumber%2] = next
+		}
+		var readErr error
+		length, readErr = io.ReadFull(reader, next)
+		if e = <-uploadedCh; e != nil {
+			break
+		}
+		total += int64(len(part))
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			e = readErr
+			break
+		}
+	}
+	if e == nil {
+		_, e = core.CompleteMultipartUpload(bucket, object, uploadID, parts)
+	}
+	if e != nil {
+		// Parts of a failed upload would be kept and billed.
+		core.AbortMultipartUpload(bucket, object, uploadID)
+		if ctx.Err() != nil || globalContext.Err() != nil {
+			return total, errCancelled().Trace(c.targetURL.String())
+		}
+		return total, probe.NewError(e).Trace(c.targetURL.String())
+	}
+	return total, nil
+}
+
+// GetObjectLockEnabled - check if object locking is enabled on the bucket.
+func (c *s3Client) GetObjectLockEnabled() (bool, *probe.Error) {
+	var lock struct {
+		ObjectLockEnabled string `xml:"ObjectLockEnabled"`
+	}
+	if err := c.getBucketConfig("object-lock", &lock); err != nil {
+		switch minio.ToErrorResponse(err.ToGoError()).Code {
+		case "ObjectLockConfigurationNotFoundError", "NotImplemented":
+			return false, nil
+		}
+		return false, err.Trace(c.targetURL.String())
+	}
+	return lock.ObjectLockEnabled == "Enabled", nil
+}
@@ -23,7 +23,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"time"
 
 	minio "github.com/minio/minio-go/v6"
 	. "gopkg.in/check.v1"
@@ -178,7 +180,7 @@ func (s *TestSuite) TestBucketOperations(c *C) {
 	s3c, err = s3New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false, DirNone) {
+	for content := range s3c.List(globalContext, false, false, DirNone) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsDir(), Equals, true)
 	}
@@ -187,7 +189,7 @@ func (s *TestSuite) TestBucketOperations(c *C) {
 	s3c, err = s3New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false, DirNone) {
+	for content := range s3c.List(globalContext, false, false, DirNone) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsDir(), Equals, true)
 	}
@@ -196,7 +198,7 @@ func (s *TestSuite) TestBucketOperations(c *C) {
 	s3c, err = s3New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false, DirNone) {
+	for content := range s3c.List(globalContext, false, false, DirNone) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsRegular(), Equals, true)
 	}
@@ -237,6 +239,35 @@ func (s *TestSuite) TestObjectOperations(c *C) {
 	}
 }
 
+// Test streams of unknown length, uploaded part by part.
+func (s *TestSuite) TestPutStream(c *C) {
+	object := objectHandler(objectHandler{
+		resource: "/bucket/object",
+	})
+	server := httptest.NewServer(object)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + object.resource
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	conf.Multipart = multipartConfig{PartSize: minUploadPartSize}
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3c := clnt.(*s3Client)
+
+	// A single PUT, and three parts.
+	for _, size := range []int{12, 2*minUploadPartSize + 1} {
+		data := bytes.Repeat([]byte("a"), size)
+		n, err := s3c.PutStream(context.Background(), bytes.NewReader(data), -1, map[string]string{
+			"Content-Type": "application/octet-stream",
+		}, nil, nil)
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, int64(size))
+	}
+}
+
 var testSelectCompressionTypeCases = []struct {
 	opts            SelectObjectOpts
 	object          string
@@ -261,3 +292,86 @@ func (s *TestSuite) TestSelectCompressionType(c *C) {
 		c.Assert(cType, DeepEquals, test.compressionType)
 	}
 }
+
+// Test that nothing is modified in read-only mode.
+func (s *TestSuite) TestReadOnlyMode(c *C) {
+	object := objectHandler(objectHandler{
+		resource: "/bucket/object",
+		data:     []byte("Hello, World"),
+	})
+	server := httptest.NewServer(object)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + object.resource
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	globalReadOnly = true
+	defer func() { globalReadOnly = false }()
+
+	_, err = s3c.Put(context.Background(), bytes.NewReader(object.data), int64(len(object.data)), nil, nil, nil)
+	c.Assert(err, NotNil)
+	c.Assert(err.ToGoError(), FitsTypeOf, ReadOnlyMode{})
+	c.Assert(errorExitStatus(err), Equals, globalAccessDeniedExitStatus)
+	c.Assert(s3c.MakeBucket("us-east-1", false), NotNil)
+	c.Assert(s3c.SetAccess("readonly", false), NotNil)
+
+	contentCh := make(chan *clientContent, 1)
+	contentCh <- &clientContent{URL: *newClientURL(conf.HostURL)}
+	close(contentCh)
+	for err = range s3c.Remove(false, false, contentCh) {
+		c.Assert(err.ToGoError(), FitsTypeOf, ReadOnlyMode{})
+	}
+
+	// Reading is still allowed.
+	reader, err := s3c.Get(nil)
+	c.Assert(err, IsNil)
+	var buffer bytes.Buffer
+	_, e := io.Copy(&buffer, reader)
+	c.Assert(e, IsNil)
+	c.Assert(buffer.Bytes(), DeepEquals, object.data)
+}
+
+// Test presigned URLs of other methods than GET.
+func (s *TestSuite) TestShareRequest(c *C) {
+	object := objectHandler(objectHandler{
+		resource: "/bucket/object",
+		data:     []byte("Hello, World"),
+	})
+	server := httptest.NewServer(object)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + object.resource
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	shareURL, err := s3c.ShareRequest(http.MethodHead, time.Hour, url.Values{"versionId": {"null"}})
+	c.Assert(err, IsNil)
+	u, e := url.Parse(shareURL)
+	c.Assert(e, IsNil)
+	c.Assert(u.Path, Equals, object.resource)
+	c.Assert(u.Query().Get("versionId"), Equals, "null")
+	c.Assert(u.Query().Get("X-Amz-Signature"), Not(Equals), "")
+
+	resp, e := http.Head(shareURL)
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	// Removals are not shared in read-only mode.
+	globalReadOnly = true
+	defer func() { globalReadOnly = false }()
+	_, err = s3c.ShareRequest(http.MethodDelete, time.Hour, nil)
+	c.Assert(err, NotNil)
+	c.Assert(err.ToGoError(), FitsTypeOf, ReadOnlyMode{})
+	_, err = s3c.ShareRequest(http.MethodHead, time.Hour, nil)
+	c.Assert(err, IsNil)
+}
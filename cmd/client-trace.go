@@ -0,0 +1,252 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/httptracer"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Redacted value of secrets in traces.
+const traceRedacted = "**REDACTED**"
+
+// Headers whose values are never written to a trace.
+var traceRedactedHeaders = []string{
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Amz-Security-Token",
+	"X-Amz-Server-Side-Encryption-Customer-Key",
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key",
+}
+
+// Query parameters of presigned URLs whose values are never written to a trace.
+var traceRedactedQueries = []string{
+	"X-Amz-Credential",
+	"X-Amz-Signature",
+	"X-Amz-Security-Token",
+	"AWSAccessKeyId",
+	"Signature",
+}
+
+var (
+	traceCredentialRegex = regexp.MustCompile("Credential=([^/]+)/")
+	traceSignatureRegex  = regexp.MustCompile("Signature=([0-9a-f]+)")
+)
+
+// traceEntry - structured record of one HTTP round trip.
+type traceEntry struct {
+	Time           time.Time         `json:"time"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Status         int               `json:"status,omitempty"`
+	Duration       time.Duration     `json:"duration"`
+	Retry          int               `json:"retry,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	RequestHeader  map[string]string `json:"requestHeader,omitempty"`
+	ResponseHeader map[string]string `json:"responseHeader,omitempty"`
+}
+
+// String one line summary of a round trip.
+func (t traceEntry) String() string {
+	msg := t.Method + " " + t.URL
+	if t.Error != "" {
+		msg += " failed: " + t.Error
+	} else {
+		msg += " " + fmt.Sprintf("%d %s", t.Status, http.StatusText(t.Status))
+	}
+	msg += " in " + t.Duration.String()
+	if t.Retry > 0 {
+		msg += fmt.Sprintf(" (retry %d)", t.Retry)
+	}
+	return msg
+}
+
+// redactAuthorization hides the access key and signature of both
+// signature v2 and v4 Authorization headers.
+func redactAuthorization(auth string) string {
+	// Signature v2 format: AWS <access-key-id>:<signature>
+	if strings.HasPrefix(auth, "AWS ") {
+		return "AWS " + traceRedacted + ":" + traceRedacted
+	}
+	auth = traceCredentialRegex.ReplaceAllString(auth, "Credential="+traceRedacted+"/")
+	return traceSignatureRegex.ReplaceAllString(auth, "Signature="+traceRedacted)
+}
+
+// redactURL hides credentials carried by presigned URLs.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	for _, key := range traceRedactedQueries {
+		if query.Get(key) != "" {
+			query.Set(key, traceRedacted)
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// redactHeader flattens a header, hiding all secrets.
+func redactHeader(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	flat := make(map[string]string, len(h))
+	for k, v := range h {
+		flat[k] = strings.Join(v, ",")
+	}
+	if auth, ok := flat["Authorization"]; ok {
+		flat["Authorization"] = redactAuthorization(auth)
+	}
+	for _, k := range traceRedactedHeaders {
+		if _, ok := flat[k]; ok {
+			flat[k] = traceRedacted
+		}
+	}
+	return flat
+}
+
+// Trace file shared by all clients, opened on first use.
+var (
+	traceFileOnce  sync.Once
+	traceFileMutex sync.Mutex
+	traceFile      *os.File
+	traceFileErr   *probe.Error
+)
+
+// openTraceFile opens the file set by --trace-file for appending.
+func openTraceFile() (*os.File, *probe.Error) {
+	traceFileOnce.Do(func() {
+		f, e := os.OpenFile(globalTraceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if e != nil {
+			traceFileErr = probe.NewError(e).Trace(globalTraceFile)
+			return
+		}
+		traceFile = f
+	})
+	return traceFile, traceFileErr
+}
+
+// httpTrace - structured tracer for all HTTP round trips. Summaries and
+// the redacted request and response headers are dumped on the console
+// with --debug and appended as JSON lines to the --trace-file.
+type httpTrace struct {
+	headers httptracer.HTTPTracer
+	debug   bool
+	file    *os.File
+
+	mutex *sync.Mutex
+	// Failed attempts per request, used to count retries.
+	attempts map[string]int
+}
+
+// newHTTPTrace - initialize a tracer dumping headers with the tracer of
+// the given signature.
+func newHTTPTrace(signature string, debug bool) (httptracer.HTTPTracer, *probe.Error) {
+	t := httpTrace{
+		headers:  newTraceV4(),
+		debug:    debug,
+		mutex:    &sync.Mutex{},
+		attempts: make(map[string]int),
+	}
+	if strings.EqualFold(signature, "S3v2") {
+		t.headers = newTraceV2()
+	}
+	if globalTraceFile != "" {
+		f, err := openTraceFile()
+		if err != nil {
+			return nil, err.Trace(globalTraceFile)
+		}
+		t.file = f
+	}
+	return t, nil
+}
+
+// Request - Trace HTTP Request
+func (t httpTrace) Request(req *http.Request) error {
+	if !t.debug {
+		return nil
+	}
+	return t.headers.Request(req)
+}
+
+// Response - Trace HTTP Response
+func (t httpTrace) Response(resp *http.Response) error {
+	if !t.debug {
+		return nil
+	}
+	return t.headers.Response(resp)
+}
+
+// retry returns the number of previous failed attempts of the same
+// request. Retries are signed again, so requests are matched by
+// method and URL only.
+func (t httpTrace) retry(req *http.Request, resp *http.Response, err error) int {
+	key := req.Method + " " + req.URL.String()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	retry := t.attempts[key]
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		t.attempts[key] = retry + 1
+	} else {
+		delete(t.attempts, key)
+	}
+	return retry
+}
+
+// RoundTripDone - Trace summary of the HTTP round trip
+func (t httpTrace) RoundTripDone(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	entry := traceEntry{
+		Time:          UTCNow(),
+		Method:        req.Method,
+		URL:           redactURL(req.URL),
+		Duration:      duration,
+		Retry:         t.retry(req, resp, err),
+		RequestHeader: redactHeader(req.Header),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+		entry.ResponseHeader = redactHeader(resp.Header)
+	}
+
+	if t.debug {
+		console.Debugln(entry.String() + "\n")
+	}
+	if t.file == nil {
+		return
+	}
+	entryBytes, e := json.Marshal(entry)
+	if e != nil {
+		return
+	}
+	traceFileMutex.Lock()
+	defer traceFileMutex.Unlock()
+	t.file.Write(append(entryBytes, '\n'))
+}
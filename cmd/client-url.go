@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -171,7 +172,7 @@ func urlJoinPath(url1, url2 string) string {
 }
 
 // url2Stat returns stat info for URL.
-func url2Stat(urlStr string, isFetchMeta bool, encKeyDB map[string][]prefixSSEPair) (client Client, content *clientContent, err *probe.Error) {
+func url2Stat(ctx context.Context, urlStr string, isFetchMeta bool, encKeyDB map[string][]prefixSSEPair) (client Client, content *clientContent, err *probe.Error) {
 	client, err = newClient(urlStr)
 	if err != nil {
 		return nil, nil, err.Trace(urlStr)
@@ -179,7 +180,7 @@ func url2Stat(urlStr string, isFetchMeta bool, encKeyDB map[string][]prefixSSEPa
 	alias, _ := url2Alias(urlStr)
 	sse := getSSE(urlStr, encKeyDB[alias])
 
-	content, err = client.Stat(false, isFetchMeta, sse)
+	content, err = client.Stat(ctx, false, isFetchMeta, sse)
 	if err != nil {
 		return nil, nil, err.Trace(urlStr)
 	}
@@ -218,7 +219,7 @@ func isURLPrefixExists(urlPrefix string, incomplete bool) bool {
 	}
 	isRecursive := false
 	isIncomplete := incomplete
-	for entry := range clnt.List(isRecursive, isIncomplete, DirNone) {
+	for entry := range clnt.List(globalContext, isRecursive, isIncomplete, DirNone) {
 		return entry.Err == nil
 	}
 	return false
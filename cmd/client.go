@@ -19,6 +19,8 @@ package cmd
 import (
 	"context"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -45,8 +47,8 @@ const defaultMultipartThreadsNum = 4
 // Client - client interface
 type Client interface {
 	// Common operations
-	Stat(isIncomplete, isFetchMeta bool, sse encrypt.ServerSide) (content *clientContent, err *probe.Error)
-	List(isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent
+	Stat(ctx context.Context, isIncomplete, isFetchMeta bool, sse encrypt.ServerSide) (content *clientContent, err *probe.Error)
+	List(ctx context.Context, isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent
 
 	// Bucket operations
 	MakeBucket(region string, ignoreExisting bool) *probe.Error
@@ -67,8 +69,9 @@ type Client interface {
 	Put(ctx context.Context, reader io.Reader, size int64, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide) (n int64, err *probe.Error)
 
 	// I/O operations with expiration
-	ShareDownload(expires time.Duration) (string, *probe.Error)
-	ShareUpload(bool, time.Duration, string) (string, map[string]string, *probe.Error)
+	ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error)
+	ShareRequest(method string, expires time.Duration, reqParams url.Values) (string, *probe.Error)
+	ShareUpload(bool, time.Duration, string, int64) (string, map[string]string, *probe.Error)
 
 	// Watch events
 	Watch(params watchParams) (*watchObject, *probe.Error)
@@ -89,23 +92,51 @@ type clientContent struct {
 	Metadata          map[string]string
 	UserMetadata      map[string]string
 	ETag              string
+	UploadID          string
+	VersionID         string
+	StorageClass      string
 	Expires           time.Time
 	EncryptionHeaders map[string]string
+	Tags              map[string]string
+	ACL               string
 	Err               *probe.Error
 }
 
 // Config - see http://docs.amazonwebservices.com/AmazonS3/latest/dev/index.html?RESTAuthentication.html
 type Config struct {
-	AccessKey   string
-	SecretKey   string
-	Signature   string
-	HostURL     string
-	AppName     string
-	AppVersion  string
-	AppComments []string
-	Debug       bool
-	Insecure    bool
-	Lookup      minio.BucketLookupType
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Signature    string
+	HostURL      string
+	AppName      string
+	AppVersion   string
+	AppComments  []string
+	Debug        bool
+	Insecure     bool
+	Lookup       minio.BucketLookupType
+	CACert       string
+	ClientCert   string
+	ClientKey    string
+	Fingerprint  string
+	Proxy        string
+	Headers      http.Header
+	Multipart    multipartConfig
+
+	// Requests accept the charges of requester pays buckets.
+	RequesterPays bool
+
+	// Other endpoints of the deployment of HostURL.
+	Endpoints []string
+
+	// Requests are signed for Region instead of the looked up region
+	// of buckets.
+	Region string
+
+	// Requests to buckets of AWS S3 use the accelerate endpoint, its
+	// dual-stack variant with DualStack.
+	Accelerate bool
+	DualStack  bool
 }
 
 // SelectObjectOpts - opts entered for select API
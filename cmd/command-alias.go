@@ -0,0 +1,217 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/minio/cli"
+)
+
+// splitCommandAlias - split the command line of an alias into words at
+// spaces, words may be quoted with single or double quotes.
+func splitCommandAlias(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// isAppCommand - true for the name of an mc command.
+func isAppCommand(name string) bool {
+	for _, cmd := range appCmds {
+		if cmd.HasName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCommandAlias - the words of the command line of an alias, which
+// starts with an mc command. Aliases cannot hide mc commands nor expand
+// to other aliases.
+func parseCommandAlias(name, line string) ([]string, error) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		return nil, errors.New("names are single words")
+	}
+	if isAppCommand(name) {
+		return nil, fmt.Errorf("it hides the mc command %s", name)
+	}
+	words, e := splitCommandAlias(line)
+	if e != nil {
+		return nil, e
+	}
+	if len(words) == 0 {
+		return nil, errors.New("no command")
+	}
+	if !isAppCommand(words[0]) {
+		return nil, fmt.Errorf("%s is not an mc command", words[0])
+	}
+	return words, nil
+}
+
+// validateConfigCommandAliases - errors of the command aliases of the
+// config.
+func validateConfigCommandAliases(aliases map[string]string) []string {
+	var errors []string
+	for name, line := range aliases {
+		if _, e := parseCommandAlias(name, line); e != nil {
+			errors = append(errors, fmt.Sprintf("Invalid command alias %s: %s", name, e))
+		}
+	}
+	sort.Strings(errors)
+	return errors
+}
+
+// configDirFromArgs - the folder of --config-dir on the command line,
+// empty if not given.
+func configDirFromArgs(args []string) string {
+	for i := 1; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		if name == args[i] {
+			continue
+		}
+		if strings.HasPrefix(name, "config-dir=") || strings.HasPrefix(name, "C=") {
+			return name[strings.Index(name, "=")+1:]
+		}
+		if (name == "config-dir" || name == "C") && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// loadCommandAliases - the valid command aliases of the config file of
+// the command line, read before the command line is parsed. Invalid
+// aliases are skipped here and reported by the config check.
+func loadCommandAliases(args []string) map[string][]string {
+	if configDir := configDirFromArgs(args); configDir != "" {
+		savedConfigDir := mcCustomConfigDir
+		setMcConfigDir(configDir)
+		defer setMcConfigDir(savedConfigDir)
+	}
+	// The config is read apart from its cache, the config folder is set
+	// once the command line is parsed.
+	config, err := readConfigV9()
+	if err != nil || len(config.CommandAliases) == 0 {
+		return nil
+	}
+	aliases := make(map[string][]string)
+	for name, line := range config.CommandAliases {
+		if words, e := parseCommandAlias(name, line); e == nil {
+			aliases[name] = words
+		}
+	}
+	return aliases
+}
+
+// commandArgIndex - index of the command in the arguments of mc, after
+// the global flags and their values.
+func commandArgIndex(args []string) int {
+	valueFlags := make(map[string]bool)
+	for _, flag := range append(mcFlags, globalFlags...) {
+		_, isBool := flag.(cli.BoolFlag)
+		for _, name := range strings.Split(flag.GetName(), ",") {
+			valueFlags[strings.TrimSpace(name)] = !isBool
+		}
+	}
+	for i := 1; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		if name == args[i] || name == "" {
+			return i
+		}
+		if !strings.Contains(name, "=") && valueFlags[name] {
+			i++
+		}
+	}
+	return len(args)
+}
+
+// expandCommandAlias - replace a command alias by its command line, the
+// arguments after the alias follow it.
+func expandCommandAlias(args []string, aliases map[string][]string) []string {
+	i := commandArgIndex(args)
+	if i >= len(args) {
+		return args
+	}
+	words, ok := aliases[args[i]]
+	if !ok {
+		return args
+	}
+	expanded := make([]string, 0, len(args)+len(words))
+	expanded = append(expanded, args[:i]...)
+	expanded = append(expanded, words...)
+	return append(expanded, args[i+1:]...)
+}
+
+// commandAliasCmds - commands listing the aliases in the help and in the
+// suggestions for mistyped commands. Aliases are expanded before the
+// command line is parsed, their commands only run for command lines
+// which could not be expanded.
+func commandAliasCmds(aliases map[string][]string) []cli.Command {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var cmds []cli.Command
+	for _, name := range names {
+		line := strings.Join(aliases[name], " ")
+		cmds = append(cmds, cli.Command{
+			Name:            name,
+			Usage:           "alias of '" + line + "'",
+			SkipFlagParsing: true,
+			HideHelpCommand: true,
+			Action: func(ctx *cli.Context) error {
+				fatalIf(errInvalidArgument().Trace(ctx.Command.Name), "Unable to expand the command alias `"+ctx.Command.Name+
+					"`, give global flags after the command `"+line+"`.")
+				return nil
+			},
+		})
+	}
+	return cmds
+}
@@ -0,0 +1,103 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCommandAlias(t *testing.T) {
+	testCases := []struct {
+		name, line string
+		words      []string
+		success    bool
+	}{
+		{"backup", "mirror --remove --overwrite --parallel 8", []string{"mirror", "--remove", "--overwrite", "--parallel", "8"}, true},
+		{"site", `cp  --attr "Cache-Control=max-age=60"   --exclude '*.tmp'`, []string{"cp", "--attr", "Cache-Control=max-age=60", "--exclude", "*.tmp"}, true},
+		{"empty", `mirror --exclude ""`, []string{"mirror", "--exclude", ""}, true},
+		{"heal", "admin heal --watch", []string{"admin", "heal", "--watch"}, true},
+		{"cp", "mirror", nil, false},
+		{"two words", "mirror", nil, false},
+		{"--backup", "mirror", nil, false},
+		{"nothing", "  ", nil, false},
+		{"chained", "backup --fake", nil, false},
+		{"unterminated", `cp --attr "a=b`, nil, false},
+	}
+	for i, testCase := range testCases {
+		words, err := parseCommandAlias(testCase.name, testCase.line)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+		if !reflect.DeepEqual(words, testCase.words) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.words, words)
+		}
+	}
+}
+
+func TestExpandCommandAlias(t *testing.T) {
+	aliases := map[string][]string{"backup": {"mirror", "--remove", "--overwrite"}}
+	testCases := []struct {
+		args     []string
+		expanded []string
+	}{
+		{[]string{"mc", "backup", "src", "dst"}, []string{"mc", "mirror", "--remove", "--overwrite", "src", "dst"}},
+		// Global flags before the alias and their values are kept.
+		{[]string{"mc", "--json", "-C", "/tmp/mc", "backup", "src"}, []string{"mc", "--json", "-C", "/tmp/mc", "mirror", "--remove", "--overwrite", "src"}},
+		{[]string{"mc", "--config-dir=/tmp/mc", "backup"}, []string{"mc", "--config-dir=/tmp/mc", "mirror", "--remove", "--overwrite"}},
+		// Values of flags and arguments are not aliases.
+		{[]string{"mc", "-C", "backup", "ls"}, []string{"mc", "-C", "backup", "ls"}},
+		{[]string{"mc", "ls", "backup"}, []string{"mc", "ls", "backup"}},
+		{[]string{"mc", "--json"}, []string{"mc", "--json"}},
+	}
+	for i, testCase := range testCases {
+		if expanded := expandCommandAlias(testCase.args, aliases); !reflect.DeepEqual(expanded, testCase.expanded) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expanded, expanded)
+		}
+	}
+}
+
+func TestLoadCommandAliases(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-command-alias-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	config := `{"version": "9", "hosts": {}, "commandAliases": {"backup": "mirror --remove", "cp": "mirror"}}`
+	if e = ioutil.WriteFile(filepath.Join(root, "config.json"), []byte(config), 0600); e != nil {
+		t.Fatal(e)
+	}
+
+	// The config folder of the command line is read, and left unset.
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir("")
+	aliases := loadCommandAliases([]string{"mc", "-C", root, "backup"})
+	if !reflect.DeepEqual(aliases, map[string][]string{"backup": {"mirror", "--remove"}}) {
+		t.Errorf("expected the valid aliases only, got %q", aliases)
+	}
+	if mcCustomConfigDir != "" {
+		t.Errorf("expected the config folder to be restored, got %s", mcCustomConfigDir)
+	}
+
+	cmds := commandAliasCmds(aliases)
+	if len(cmds) != 1 || cmds[0].Name != "backup" || cmds[0].Usage != "alias of 'mirror --remove'" {
+		t.Errorf("unexpected alias commands %+v", cmds)
+	}
+}
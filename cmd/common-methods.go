@@ -19,6 +19,7 @@ package cmd
 import (
 	"context"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -28,6 +29,7 @@ import (
 	"gopkg.in/h2non/filetype.v1"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/hookreader"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v6/pkg/encrypt"
 )
@@ -61,7 +63,7 @@ func getEncKeys(ctx *cli.Context) (map[string][]prefixSSEPair, *probe.Error) {
 func isAliasURLDir(aliasURL string, keys map[string][]prefixSSEPair) bool {
 	// If the target url exists, check if it is a directory
 	// and return immediately.
-	_, targetContent, err := url2Stat(aliasURL, false, keys)
+	_, targetContent, err := url2Stat(globalContext, aliasURL, false, keys)
 	if err == nil {
 		return targetContent.Type.IsDir()
 	}
@@ -102,7 +104,7 @@ func getSourceStreamMetadataFromURL(urlStr string, encKeyDB map[string][]prefixS
 		return nil, nil, err.Trace(urlStr)
 	}
 	sseKey := getSSE(urlStr, encKeyDB[alias])
-	return getSourceStream(alias, urlStrFull, true, sseKey)
+	return getSourceStream(globalContext, alias, urlStrFull, true, sseKey)
 }
 
 // getSourceStreamFromURL gets a reader from URL.
@@ -112,12 +114,12 @@ func getSourceStreamFromURL(urlStr string, encKeyDB map[string][]prefixSSEPair)
 		return nil, err.Trace(urlStr)
 	}
 	sse := getSSE(urlStr, encKeyDB[alias])
-	reader, _, err = getSourceStream(alias, urlStrFull, false, sse)
+	reader, _, err = getSourceStream(globalContext, alias, urlStrFull, false, sse)
 	return reader, err
 }
 
 // getSourceStream gets a reader from URL.
-func getSourceStream(alias string, urlStr string, fetchStat bool, sse encrypt.ServerSide) (reader io.ReadCloser, metadata map[string]string, err *probe.Error) {
+func getSourceStream(ctx context.Context, alias string, urlStr string, fetchStat bool, sse encrypt.ServerSide) (reader io.ReadCloser, metadata map[string]string, err *probe.Error) {
 	sourceClnt, err := newClientFromAlias(alias, urlStr)
 	if err != nil {
 		return nil, nil, err.Trace(alias, urlStr)
@@ -128,7 +130,7 @@ func getSourceStream(alias string, urlStr string, fetchStat bool, sse encrypt.Se
 	}
 	metadata = make(map[string]string)
 	if fetchStat {
-		st, err := sourceClnt.Stat(false, true, sse)
+		st, err := sourceClnt.Stat(ctx, false, true, sse)
 		if err != nil {
 			return nil, nil, err.Trace(alias, urlStr)
 		}
@@ -138,6 +140,10 @@ func getSourceStream(alias string, urlStr string, fetchStat bool, sse encrypt.Se
 				metadata[k] = v
 			}
 		}
+		// Expires is parsed apart from the other headers.
+		if !st.Expires.IsZero() {
+			metadata["Expires"] = st.Expires.UTC().Format(http.TimeFormat)
+		}
 		// If our reader is a seeker try to detect content-type further.
 		if s, ok := reader.(io.ReadSeeker); ok {
 			// All unrecognized files have `application/octet-stream`
@@ -180,19 +186,6 @@ func putTargetStream(ctx context.Context, alias string, urlStr string, reader io
 	return n, nil
 }
 
-// putTargetStreamWithURL writes to URL from reader. If length=-1, read until EOF.
-func putTargetStreamWithURL(urlStr string, reader io.Reader, size int64, sse encrypt.ServerSide) (int64, *probe.Error) {
-	alias, urlStrFull, _, err := expandAlias(urlStr)
-	if err != nil {
-		return 0, err.Trace(alias, urlStr)
-	}
-	contentType := guessURLContentType(urlStr)
-	metadata := map[string]string{
-		"Content-Type": contentType,
-	}
-	return putTargetStream(context.Background(), alias, urlStrFull, reader, size, metadata, nil, sse)
-}
-
 // copySourceToTargetURL copies to targetURL from source.
 func copySourceToTargetURL(alias string, urlStr string, source string, size int64, progress io.Reader, srcSSE, tgtSSE encrypt.ServerSide, metadata map[string]string) *probe.Error {
 	targetClnt, err := newClientFromAlias(alias, urlStr)
@@ -207,14 +200,16 @@ func copySourceToTargetURL(alias string, urlStr string, source string, size int6
 }
 
 // createUserMetadata - returns a map of user defined function
-// by combining the usermetadata of object and  values passed by attr keyword
-func createUserMetadata(sourceAlias, sourceURLStr string, srcSSE encrypt.ServerSide, urls URLs) (map[string]string, *probe.Error) {
+// by combining the usermetadata of object and  values passed by attr keyword,
+// along with the storage class and ACL of the target. Server side copies
+// with metadata replace the content headers, which are copied as well.
+func createUserMetadata(ctx context.Context, sourceAlias, sourceURLStr string, srcSSE encrypt.ServerSide, urls URLs) (map[string]string, *probe.Error) {
 	metadata := make(map[string]string)
 	sourceClnt, err := newClientFromAlias(sourceAlias, sourceURLStr)
 	if err != nil {
 		return nil, err.Trace(sourceAlias, sourceURLStr)
 	}
-	st, err := sourceClnt.Stat(false, true, srcSSE)
+	st, err := sourceClnt.Stat(ctx, false, true, srcSSE)
 	if err != nil {
 		return nil, err.Trace(sourceAlias, sourceURLStr)
 	}
@@ -228,6 +223,19 @@ func createUserMetadata(sourceAlias, sourceURLStr string, srcSSE encrypt.ServerS
 	for k, v := range urls.TargetContent.UserMetadata {
 		metadata[k] = v
 	}
+	for k, v := range urls.TargetContent.Metadata {
+		metadata[k] = v
+	}
+	if len(metadata) > 0 {
+		for _, k := range copyContentHeaders {
+			if _, ok := metadata[k]; !ok && st.Metadata[k] != "" && httpguts.ValidHeaderFieldValue(st.Metadata[k]) {
+				metadata[k] = st.Metadata[k]
+			}
+		}
+		if _, ok := metadata["Expires"]; !ok && !st.Expires.IsZero() {
+			metadata["Expires"] = st.Expires.UTC().Format(http.TimeFormat)
+		}
+	}
 	return metadata, nil
 }
 
@@ -247,10 +255,23 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	srcSSE := getSSE(sourcePath, encKeyDB[sourceAlias])
 	tgtSSE := getSSE(targetPath, encKeyDB[targetAlias])
 
-	// Optimize for server side copy if the host is same.
-	if sourceAlias == targetAlias {
+	// Tags are not copied by multipart and stream copies, they are set
+	// on the target once copied.
+	var tags map[string]string
+	if urls.PreserveMetadata {
+		var err *probe.Error
+		if tags, err = getSourceTags(sourceAlias, sourceURL.String()); err != nil && !isNotImplemented(err) {
+			return urls.WithError(err.Trace(sourceURL.String()))
+		}
+	}
+
+	// Optimize for server side copy if the host is same, unless the
+	// object has to go through transforms or a local filter program,
+	// or a previous version is read with --rewind which server side
+	// copy can not.
+	if sourceAlias == targetAlias && urls.TransformExec == "" && len(urls.Transforms) == 0 && rewindTime(sourceURL.String()).IsZero() {
 
-		metadata, err := createUserMetadata(sourceAlias, sourceURL.String(), srcSSE, urls)
+		metadata, err := createUserMetadata(ctx, sourceAlias, sourceURL.String(), srcSSE, urls)
 		if err != nil {
 			return urls.WithError(err.Trace(sourceURL.String()))
 		}
@@ -263,7 +284,7 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	} else {
 
 		// Proceed with regular stream copy.
-		reader, metadata, err := getSourceStream(sourceAlias, sourceURL.String(), true, srcSSE)
+		reader, metadata, err := getSourceStream(ctx, sourceAlias, sourceURL.String(), true, srcSSE)
 		if err != nil {
 			return urls.WithError(err.Trace(sourceURL.String()))
 		}
@@ -284,11 +305,35 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			delete(metadata, "X-Amz-Server-Side-Encryption-Customer-Algorithm")
 			delete(metadata, "X-Amz-Server-Side-Encryption-Customer-Key-Md5")
 		}
-		_, err = putTargetStream(ctx, targetAlias, targetURL.String(), reader, length, metadata, progress, tgtSSE)
+		var source io.Reader = reader
+		if urls.TransformExec != "" || len(urls.Transforms) > 0 {
+			// Progress follows the source object, the size of the
+			// filtered stream is not known in advance.
+			transform, err := newTransformsReader(urls.Transforms, urls.TransformExec,
+				hookreader.NewHook(reader, progress), sourcePath, targetPath)
+			if err != nil {
+				return urls.WithError(err.Trace(sourceURL.String()))
+			}
+			defer transform.Close()
+			source, length, progress = transform, -1, nil
+		}
+		if readerAt, ok := reader.(io.ReaderAt); ok && source == io.Reader(reader) && urls.Uploads != nil {
+			// Uploads of a session resume from the parts uploaded
+			// before a restart.
+			_, err = putTargetResumable(ctx, targetAlias, targetURL.String(), targetPath, readerAt, length,
+				urls.SourceContent.Time, metadata, progress, tgtSSE, urls.Uploads)
+		} else {
+			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), source, length, metadata, progress, tgtSSE)
+		}
 		if err != nil {
 			return urls.WithError(err.Trace(targetURL.String()))
 		}
 	}
+	if len(tags) > 0 {
+		if err := setTargetTags(targetAlias, targetURL.String(), tags); err != nil {
+			return urls.WithError(err.Trace(targetURL.String()))
+		}
+	}
 	return urls.WithError(nil)
 }
 
@@ -336,3 +381,21 @@ func newClient(aliasedURL string) (Client, *probe.Error) {
 	}
 	return newClientFromAlias(alias, urlStrFull)
 }
+
+// checkWritableTargets - fail before any transfer starts when a remote
+// target cannot be modified in read-only mode, local targets are
+// always writable.
+func checkWritableTargets(targetURLs ...string) {
+	if !globalReadOnly {
+		return
+	}
+	for _, targetURL := range targetURLs {
+		clnt, err := newClient(targetURL)
+		if err != nil {
+			continue
+		}
+		if s3Clnt, ok := clnt.(*s3Client); ok {
+			fatalIf(s3Clnt.checkWritable().Trace(targetURL), "Refusing to modify `"+targetURL+"`.")
+		}
+	}
+}
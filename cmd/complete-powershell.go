@@ -0,0 +1,112 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/mitchellh/go-homedir"
+)
+
+// Marks the completion script in a PowerShell profile.
+const powerShellCompletionMarker = "# mc auto-completion"
+
+// PowerShell has no completion API of its own like bash, the script
+// passes the command line to mc in COMP_LINE and COMP_POINT as bash does.
+const powerShellCompletionScript = powerShellCompletionMarker + `
+Register-ArgumentCompleter -Native -CommandName mc, mc.exe -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $env:COMP_LINE = $commandAst.ToString().PadRight($cursorPosition - $commandAst.Extent.StartOffset)
+    $env:COMP_POINT = $env:COMP_LINE.Length
+    & '%s' mc | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+    Remove-Item Env:COMP_LINE, Env:COMP_POINT
+}
+`
+
+// powerShellCompletion - the completion script for the mc binary at bin.
+func powerShellCompletion(bin string) string {
+	// Single quotes are escaped by doubling them in PowerShell strings.
+	return fmt.Sprintf(powerShellCompletionScript, strings.Replace(bin, "'", "''", -1))
+}
+
+// powerShellProfiles - the profiles of Windows PowerShell and of
+// PowerShell 6 and later of the current user.
+func powerShellProfiles() ([]string, *probe.Error) {
+	homeDir, e := homedir.Dir()
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	documents := filepath.Join(homeDir, "Documents")
+	return []string{
+		filepath.Join(documents, "WindowsPowerShell", "Microsoft.PowerShell_profile.ps1"),
+		filepath.Join(documents, "PowerShell", "Microsoft.PowerShell_profile.ps1"),
+	}, nil
+}
+
+// isPowerShellCompletionInstalled - returns true if a profile of the
+// current user loads the completion script.
+func isPowerShellCompletionInstalled() bool {
+	profiles, err := powerShellProfiles()
+	if err != nil {
+		return false
+	}
+	for _, profile := range profiles {
+		if data, e := ioutil.ReadFile(profile); e == nil && strings.Contains(string(data), powerShellCompletionMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// installPowerShellCompletion - append the completion script to the
+// profiles of the current user, creating the Windows PowerShell profile
+// if there is none.
+func installPowerShellCompletion() *probe.Error {
+	bin, e := os.Executable()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	profiles, err := powerShellProfiles()
+	if err != nil {
+		return err.Trace()
+	}
+	for i, profile := range profiles {
+		if _, e = os.Stat(profile); os.IsNotExist(e) && i > 0 {
+			continue
+		}
+		if e = os.MkdirAll(filepath.Dir(profile), 0700); e != nil {
+			return probe.NewError(e).Trace(profile)
+		}
+		f, e := os.OpenFile(profile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if e != nil {
+			return probe.NewError(e).Trace(profile)
+		}
+		_, e = f.WriteString("\r\n" + strings.Replace(powerShellCompletion(bin), "\n", "\r\n", -1))
+		f.Close()
+		if e != nil {
+			return probe.NewError(e).Trace(profile)
+		}
+	}
+	return nil
+}
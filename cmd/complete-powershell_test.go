@@ -0,0 +1,61 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func TestInstallPowerShellCompletion(t *testing.T) {
+	script := powerShellCompletion(`C:\Users\O'Brien\mc.exe`)
+	if !strings.Contains(script, `& 'C:\Users\O''Brien\mc.exe' mc`) {
+		t.Errorf("binary path is not quoted in script:\n%s", script)
+	}
+
+	homeDir, e := ioutil.TempDir("", "mc-powershell-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(homeDir)
+	savedHome, savedCache := os.Getenv("HOME"), homedir.DisableCache
+	defer func() {
+		os.Setenv("HOME", savedHome)
+		homedir.DisableCache = savedCache
+	}()
+	os.Setenv("HOME", homeDir)
+	homedir.DisableCache = true
+
+	if isPowerShellCompletionInstalled() {
+		t.Fatal("completion is not installed yet")
+	}
+	if err := installPowerShellCompletion(); err != nil {
+		t.Fatal(err)
+	}
+	if !isPowerShellCompletionInstalled() {
+		t.Fatal("completion is installed")
+	}
+	// Only existing profiles of PowerShell 6 and later are changed.
+	if _, e = os.Stat(filepath.Join(homeDir, "Documents", "PowerShell")); !os.IsNotExist(e) {
+		t.Errorf("expected no PowerShell 6 profile, got %v", e)
+	}
+}
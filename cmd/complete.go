@@ -49,7 +49,7 @@ func completeS3Path(s3Path string) (prediction []string) {
 
 	// List dirPath content and only pick elements that corresponds
 	// to the path that we want to complete
-	for content := range clnt.List(false, false, DirFirst) {
+	for content := range clnt.List(globalContext, false, false, DirFirst) {
 		completeS3Path := alias + getKey(content)
 		if content.Type.IsDir() {
 			if !strings.HasSuffix(completeS3Path, "/") {
@@ -142,19 +142,22 @@ var fsCompleter = fsComplete{}
 // The list of all commands supported by mc with their mapping
 // with their bash completer function
 var completeCmds = map[string]complete.Predictor{
-	"/ls":     complete.PredictOr(s3Completer, fsCompleter),
-	"/cp":     complete.PredictOr(s3Completer, fsCompleter),
-	"/rm":     complete.PredictOr(s3Completer, fsCompleter),
-	"/rb":     complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
-	"/cat":    complete.PredictOr(s3Completer, fsCompleter),
-	"/head":   complete.PredictOr(s3Completer, fsCompleter),
-	"/diff":   complete.PredictOr(s3Completer, fsCompleter),
-	"/find":   complete.PredictOr(s3Completer, fsCompleter),
-	"/mirror": complete.PredictOr(s3Completer, fsCompleter),
-	"/pipe":   complete.PredictOr(s3Completer, fsCompleter),
-	"/stat":   complete.PredictOr(s3Completer, fsCompleter),
-	"/watch":  complete.PredictOr(s3Completer, fsCompleter),
-	"/policy": complete.PredictOr(s3Completer, fsCompleter),
+	"/ls":      complete.PredictOr(s3Completer, fsCompleter),
+	"/cp":      complete.PredictOr(s3Completer, fsCompleter),
+	"/mv":      complete.PredictOr(s3Completer, fsCompleter),
+	"/rm":      complete.PredictOr(s3Completer, fsCompleter),
+	"/rb":      complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
+	"/cat":     complete.PredictOr(s3Completer, fsCompleter),
+	"/head":    complete.PredictOr(s3Completer, fsCompleter),
+	"/diff":    complete.PredictOr(s3Completer, fsCompleter),
+	"/verify":  complete.PredictOr(s3Completer, fsCompleter),
+	"/find":    complete.PredictOr(s3Completer, fsCompleter),
+	"/mirror":  complete.PredictOr(s3Completer, fsCompleter),
+	"/pipe":    complete.PredictOr(s3Completer, fsCompleter),
+	"/publish": complete.PredictOr(fsCompleter, s3Completer),
+	"/stat":    complete.PredictOr(s3Completer, fsCompleter),
+	"/watch":   complete.PredictOr(s3Completer, fsCompleter),
+	"/policy":  complete.PredictOr(s3Completer, fsCompleter),
 
 	"/mb":  aliasCompleter,
 	"/sql": s3Completer,
@@ -185,23 +188,54 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/user/enable":  aliasCompleter,
 	"/admin/user/list":    aliasCompleter,
 	"/admin/user/remove":  aliasCompleter,
+	"/admin/user/rotate":  aliasCompleter,
 
-	"/event/add":    aliasCompleter,
-	"/event/list":   aliasCompleter,
-	"/event/remove": aliasCompleter,
+	"/admin/bucket/info": s3Completer,
+
+	"/admin/replicate/add":    aliasCompleter,
+	"/admin/replicate/status": aliasCompleter,
+	"/admin/replicate/remove": aliasCompleter,
+
+	"/retention/report": complete.PredictOr(s3Completer, fsCompleter),
+
+	"/support/tls":     aliasCompleter,
+	"/support/diag":    aliasCompleter,
+	"/support/inspect": s3Completer,
+
+	"/docs/man":        nil,
+	"/docs/completion": complete.PredictSet(completionShells...),
+
+	"/idp/openid/set":    aliasCompleter,
+	"/idp/openid/info":   aliasCompleter,
+	"/idp/openid/test":   aliasCompleter,
+	"/idp/openid/remove": aliasCompleter,
+
+	"/event/add":     aliasCompleter,
+	"/event/list":    aliasCompleter,
+	"/event/listen":  aliasCompleter,
+	"/event/remove":  aliasCompleter,
+	"/event/targets": aliasCompleter,
 
 	"/session/clear":  nil,
 	"/session/list":   nil,
 	"/session/resume": nil,
 
+	"/share/delete":   nil,
 	"/share/download": nil,
+	"/share/head":     nil,
 	"/share/list":     nil,
+	"/share/rm":       nil,
 	"/share/upload":   nil,
 
 	"/config/host/add":    nil,
 	"/config/host/list":   aliasCompleter,
 	"/config/host/remove": aliasCompleter,
+	"/config/export":      aliasCompleter,
+	"/config/import":      fsCompleter,
+	"/config/audit":       nil,
 
+	"/clean":   nil,
+	"/login":   aliasCompleter,
 	"/update":  nil,
 	"/version": nil,
 }
@@ -0,0 +1,129 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var configAuditCmd = cli.Command{
+	Name:            "audit",
+	Usage:           "show or set the audit log of mutating commands",
+	Action:          mainConfigAudit,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [off | file | syslog]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+   MC_AUDIT: audit log mode, overrides the configuration file
+
+DESCRIPTION:
+  Every invocation of a command modifying servers, buckets, objects or the configuration is
+  recorded with its arguments, target URLs and result. Secret keys, encryption keys,
+  passphrases and passwords of URLs are redacted. The 'file' mode appends JSON lines to
+  'audit.log' in the configuration folder, the 'syslog' mode sends them to the local syslog
+  daemon. Commands refused for invalid usage are not recorded.
+
+EXAMPLES:
+  1. Show the audit log mode.
+     $ {{.HelpName}}
+
+  2. Append audit entries to the audit log file in the configuration folder.
+     $ {{.HelpName}} file
+
+  3. Send audit entries to syslog.
+     $ {{.HelpName}} syslog
+
+  4. Disable the audit log.
+     $ {{.HelpName}} off
+
+`,
+}
+
+// configAuditMessage container for the audit log mode.
+type configAuditMessage struct {
+	Status string `json:"status"`
+	Mode   string `json:"mode"`
+	Path   string `json:"path,omitempty"`
+}
+
+func (c configAuditMessage) String() string {
+	switch c.Mode {
+	case auditFile:
+		return console.Colorize("ConfigAudit", "Audit log is written to `"+c.Path+"`.")
+	case auditSyslog:
+		return console.Colorize("ConfigAudit", "Audit log is sent to syslog.")
+	}
+	return console.Colorize("ConfigAudit", "Audit log is disabled.")
+}
+
+func (c configAuditMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// checkConfigAuditSyntax - validate all the passed arguments
+func checkConfigAuditSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) > 1 {
+		cli.ShowCommandHelpAndExit(ctx, "audit", 1) // last argument is exit code
+	}
+	if mode := ctx.Args().First(); mode != "" && !isValidAuditMode(strings.ToLower(mode)) {
+		fatalIf(errInvalidArgument().Trace(mode), "Unknown audit log mode `"+mode+"`, `off`, `file` or `syslog` is expected.")
+	}
+}
+
+// mainConfigAudit is the handle for "mc config audit" command.
+func mainConfigAudit(ctx *cli.Context) error {
+	checkConfigAuditSyntax(ctx)
+
+	console.SetColor("ConfigAudit", color.New(color.FgGreen, color.Bold))
+
+	if mode := ctx.Args().First(); mode != "" {
+		err := updateMcConfig(func(mcCfg *configV9) {
+			mcCfg.Audit = strings.ToLower(mode)
+			if mcCfg.Audit == auditOff {
+				mcCfg.Audit = ""
+			}
+		})
+		fatalIf(err.Trace(mode), "Unable to save the audit log mode.")
+	}
+
+	msg := configAuditMessage{Mode: getAuditMode()}
+	if msg.Mode == auditFile {
+		msg.Path = filepath.Join(mustGetMcConfigDir(), auditLogFile)
+	}
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,334 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// Version of the format written by 'config export'.
+	configExportVersion = "1"
+
+	// Key derivation used for encrypted secrets.
+	configExportKDF = "scrypt"
+
+	// Environment variable holding the passphrase of encrypted secrets.
+	configPassphraseEnv = "MC_CONFIG_PASSPHRASE"
+)
+
+var configExportFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "no-secrets",
+		Usage: "leave secrets out of the export",
+	},
+	cli.BoolFlag{
+		Name:  "encrypt",
+		Usage: "encrypt secrets with a passphrase",
+	},
+}
+
+var configExportCmd = cli.Command{
+	Name:            "export",
+	Usage:           "export hosts from configuration file",
+	Action:          mainConfigExport,
+	Before:          setGlobalsFromContext,
+	Flags:           append(configExportFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [ALIAS ...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+   MC_CONFIG_PASSPHRASE: passphrase of encrypted secrets, asked for when not set
+
+DESCRIPTION:
+  Hosts are written to the standard output, all hosts are exported when no alias is given.
+  Paths to TLS certificates and keys are exported as they are, the files must exist on the
+  machine importing them. Secret keys, session tokens, proxy passwords and the values of
+  saved headers are secrets, they are left out with --no-secrets and encrypted with --encrypt.
+
+EXAMPLES:
+  1. Export all hosts to a file.
+     $ {{.HelpName}} > mc-hosts.json
+
+  2. Export the hosts "myminio" and "mys3" without their secrets.
+     $ {{.HelpName}} --no-secrets myminio mys3 > mc-hosts.json
+
+  3. Export all hosts with their secrets encrypted with a passphrase.
+     $ {{.HelpName}} --encrypt > mc-hosts.json
+
+`,
+}
+
+// configExport is the document written by 'config export' and read
+// by 'config import'.
+type configExport struct {
+	Version    string                  `json:"version"`
+	Encryption *configExportEncryption `json:"encryption,omitempty"`
+	Hosts      map[string]hostConfigV9 `json:"hosts"`
+}
+
+// configExportEncryption describes how secrets are encrypted, every
+// secret is sealed with AES-256-GCM under a key derived
+// from the passphrase.
+type configExportEncryption struct {
+	KDF  string `json:"kdf"`
+	Salt string `json:"salt"`
+}
+
+// deriveConfigExportKey - derive the key encrypting secrets.
+func deriveConfigExportKey(passphrase, salt []byte) (cipher.AEAD, *probe.Error) {
+	key, e := scrypt.Key(passphrase, salt, 32768, 8, 1, 32)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	aead, e := cipher.NewGCM(block)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return aead, nil
+}
+
+// sealSecret - encrypt a secret, the alias and field it belongs to are
+// authenticated so secrets cannot be swapped between hosts or fields.
+func sealSecret(aead cipher.AEAD, field, secret string) (string, *probe.Error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, e := rand.Read(nonce); e != nil {
+		return "", probe.NewError(e)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(secret), []byte(field))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// mapHostSecrets - replace every secret of a host by the result of fn:
+// the secret key, the session token, the password of the proxy URL and
+// the values of saved headers. Each secret is passed along with the
+// alias and the field it belongs to, secrets replaced by an empty string
+// are left out.
+func mapHostSecrets(alias string, hostCfg hostConfigV9, fn func(field, secret string) (string, *probe.Error)) (hostConfigV9, *probe.Error) {
+	var err *probe.Error
+	if hostCfg.SecretKey != "" {
+		// The secret key is sealed with the alias alone, as by earlier exports.
+		if hostCfg.SecretKey, err = fn(alias, hostCfg.SecretKey); err != nil {
+			return hostCfg, err.Trace(alias)
+		}
+	}
+	if hostCfg.SessionToken != "" {
+		if hostCfg.SessionToken, err = fn(alias+"/sessionToken", hostCfg.SessionToken); err != nil {
+			return hostCfg, err.Trace(alias)
+		}
+	}
+	if u, e := url.Parse(hostCfg.Proxy); e == nil && u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			if password, err = fn(alias+"/proxy", password); err != nil {
+				return hostCfg, err.Trace(alias)
+			}
+			if password != "" {
+				u.User = url.UserPassword(u.User.Username(), password)
+			} else {
+				u.User = url.User(u.User.Username())
+			}
+			hostCfg.Proxy = u.String()
+		}
+	}
+	if len(hostCfg.Headers) > 0 {
+		headers := make(map[string]string, len(hostCfg.Headers))
+		for name, value := range hostCfg.Headers {
+			if value, err = fn(alias+"/headers/"+name, value); err != nil {
+				return hostCfg, err.Trace(alias, name)
+			}
+			if value != "" {
+				headers[name] = value
+			}
+		}
+		hostCfg.Headers = nil
+		if len(headers) > 0 {
+			hostCfg.Headers = headers
+		}
+	}
+	return hostCfg, nil
+}
+
+// openSecret - decrypt a secret sealed by sealSecret.
+func openSecret(aead cipher.AEAD, field, sealed string) (string, *probe.Error) {
+	data, e := base64.StdEncoding.DecodeString(sealed)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	if len(data) < aead.NonceSize() {
+		return "", probe.NewError(errors.New("encrypted secret is too short"))
+	}
+	secret, e := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(field))
+	if e != nil {
+		return "", probe.NewError(errors.New("wrong passphrase or corrupted secret"))
+	}
+	return string(secret), nil
+}
+
+// exportHosts - build the export of the given aliases, all hosts are
+// exported when aliases is empty. Secrets are left out when noSecrets
+// is set and are encrypted when a passphrase is given.
+func exportHosts(hosts map[string]hostConfigV9, aliases []string, noSecrets bool, passphrase []byte) (*configExport, *probe.Error) {
+	if len(aliases) == 0 {
+		for alias := range hosts {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+	}
+
+	export := &configExport{
+		Version: configExportVersion,
+		Hosts:   make(map[string]hostConfigV9),
+	}
+
+	var aead cipher.AEAD
+	if passphrase != nil && !noSecrets {
+		salt := make([]byte, 32)
+		if _, e := rand.Read(salt); e != nil {
+			return nil, probe.NewError(e)
+		}
+		var err *probe.Error
+		if aead, err = deriveConfigExportKey(passphrase, salt); err != nil {
+			return nil, err.Trace()
+		}
+		export.Encryption = &configExportEncryption{
+			KDF:  configExportKDF,
+			Salt: base64.StdEncoding.EncodeToString(salt),
+		}
+	}
+
+	for _, alias := range aliases {
+		hostCfg, ok := hosts[alias]
+		if !ok {
+			return nil, errInvalidAliasedURL(alias).Trace(alias)
+		}
+		// Temporary credentials are bound to this machine, imported
+		// aliases sign in again with 'mc login'.
+		if hostCfg.Login != nil {
+			hostCfg = hostCfg.withoutLoginSession()
+		}
+		var err *probe.Error
+		switch {
+		case noSecrets:
+			hostCfg, err = mapHostSecrets(alias, hostCfg, func(field, secret string) (string, *probe.Error) {
+				return "", nil
+			})
+		case aead != nil:
+			hostCfg, err = mapHostSecrets(alias, hostCfg, func(field, secret string) (string, *probe.Error) {
+				return sealSecret(aead, field, secret)
+			})
+		}
+		if err != nil {
+			return nil, err.Trace(alias)
+		}
+		export.Hosts[alias] = hostCfg
+	}
+	return export, nil
+}
+
+// getConfigPassphrase - read the passphrase of encrypted secrets
+// from MC_CONFIG_PASSPHRASE, or ask for it on the terminal.
+func getConfigPassphrase(confirm bool) ([]byte, *probe.Error) {
+	if passphrase := os.Getenv(configPassphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return nil, probe.NewError(fmt.Errorf("%s is not set and no terminal is available to ask for a passphrase", configPassphraseEnv))
+	}
+	fmt.Fprint(os.Stderr, "Enter passphrase: ")
+	passphrase, e := terminal.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if len(passphrase) == 0 {
+		return nil, probe.NewError(errors.New("passphrase cannot be empty"))
+	}
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+		confirmation, e := terminal.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		if !bytes.Equal(passphrase, confirmation) {
+			return nil, probe.NewError(errors.New("passphrases do not match"))
+		}
+	}
+	return passphrase, nil
+}
+
+// checkConfigExportSyntax - verifies input arguments to 'config export'.
+func checkConfigExportSyntax(ctx *cli.Context) {
+	for _, alias := range ctx.Args() {
+		if !isValidAlias(alias) {
+			fatalIf(errInvalidAlias(alias), "Invalid alias `"+alias+"`.")
+		}
+	}
+	if ctx.Bool("no-secrets") && ctx.Bool("encrypt") {
+		fatalIf(errInvalidArgument(), "Only one of `--no-secrets` and `--encrypt` may be given.")
+	}
+}
+
+func mainConfigExport(ctx *cli.Context) error {
+	checkConfigExportSyntax(ctx)
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
+
+	var passphrase []byte
+	if ctx.Bool("encrypt") {
+		passphrase, err = getConfigPassphrase(true)
+		fatalIf(err, "Unable to read passphrase.")
+	}
+
+	export, err := exportHosts(conf.Hosts, ctx.Args(), ctx.Bool("no-secrets"), passphrase)
+	fatalIf(err, "Unable to export hosts.")
+
+	exportBytes, e := json.MarshalIndent(export, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	_, e = fmt.Fprintln(os.Stdout, string(exportBytes))
+	fatalIf(probe.NewError(e), "Unable to write hosts.")
+	return nil
+}
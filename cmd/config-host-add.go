@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"math/rand"
+	"os"
 	"time"
 
 	"github.com/fatih/color"
@@ -36,6 +37,54 @@ var hostAddFlags = []cli.Flag{
 		Name:  "api",
 		Usage: "API signature. Valid options are '[S3v4, S3v2]'",
 	},
+	cli.StringFlag{
+		Name:  "region",
+		Usage: "region of all buckets of this host, their location is not looked up",
+	},
+	cli.StringFlag{
+		Name:  "ca-cert",
+		Usage: "path to a PEM encoded CA bundle trusted for this host",
+	},
+	cli.StringFlag{
+		Name:  "client-cert",
+		Usage: "path to a PEM encoded client certificate presented to this host",
+	},
+	cli.StringFlag{
+		Name:  "client-key",
+		Usage: "path to the PEM encoded private key of the client certificate",
+	},
+	cli.StringFlag{
+		Name:  "fingerprint",
+		Usage: "pin the SHA-256 fingerprint of the server certificate instead of verifying it against CAs",
+	},
+	cli.StringFlag{
+		Name:  "share-expire",
+		Usage: "default expiry in NN[h|m|s] of URLs shared by 'share download'",
+	},
+	cli.StringFlag{
+		Name:  "part-size",
+		Usage: "default size of the parts of multipart uploads to this host, e.g. 16MiB",
+	},
+	cli.StringFlag{
+		Name:  "multipart-threshold",
+		Usage: "default size from which objects are uploaded in parts to this host, e.g. 64MiB",
+	},
+	cli.BoolFlag{
+		Name:  "disable-multipart",
+		Usage: "upload every object with a single PUT to this host, for gateways without multipart support",
+	},
+	cli.StringSliceFlag{
+		Name:  "endpoint",
+		Usage: "other endpoint of the same deployment, requests fail over to the fastest endpoint up, can be repeated",
+	},
+	cli.BoolFlag{
+		Name:  "accelerate",
+		Usage: "send requests to buckets of AWS S3 to the transfer acceleration endpoint",
+	},
+	cli.BoolFlag{
+		Name:  "dual-stack",
+		Usage: "use the dual-stack (IPv4 and IPv6) transfer acceleration endpoint, with --accelerate",
+	},
 }
 var configHostAddCmd = cli.Command{
 	Name:            "add",
@@ -80,6 +129,45 @@ EXAMPLES:
 								minio minio123 --api "s3v4" --lookup "dns"
 		 $ set -o history
 
+  5. Add a private MinIO cluster under "mycluster" alias, trusting its own CA and authenticating with a client certificate.
+     $ {{.HelpName}} mycluster https://minio.internal:9000 minio minio123 \
+                 --ca-cert ~/certs/internal-ca.pem --client-cert ~/certs/mc.crt --client-key ~/certs/mc.key
+
+  6. Add a MinIO server with a self-signed certificate under "mylab" alias, pinning its certificate fingerprint
+     as reported by 'mc support tls'.
+     $ {{.HelpName}} mylab https://192.168.1.10:9000 minio minio123 \
+                 --fingerprint 3F:2A:...:9C
+
+  7. Add a host reachable only through a corporate proxy under "corp" alias, the proxy is saved along with the alias.
+     $ {{.HelpName}} corp https://s3.amazonaws.com \
+                 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --proxy http://proxy.corp.example.com:3128
+
+  8. Add a host under "team" alias, URLs shared by 'mc share download' expire after a day unless '--expire' is given.
+     $ {{.HelpName}} team https://minio.team.example.com:9000 minio minio123 --share-expire 24h
+
+  9. Add a gateway requiring parts of exactly 16MiB under "gw" alias, objects of 64MiB or more are uploaded in parts.
+     $ {{.HelpName}} gw https://gateway.example.com minio minio123 --part-size 16MiB --multipart-threshold 64MiB
+
+  10. Add a gateway authenticating requests with a token header under "gw" alias, the header is sent with all its requests.
+     $ {{.HelpName}} gw https://gateway.example.com minio minio123 --header "X-Gateway-Token: 6f1c..."
+
+  11. Add Amazon S3 under "datasets" alias to read requester pays buckets, accepting their charges.
+     $ {{.HelpName}} datasets https://s3.amazonaws.com \
+                 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --api S3v4 --requester-pays
+
+  12. Add Amazon S3 under "s3fast" alias, uploading to buckets with transfer acceleration over IPv4 and IPv6.
+     $ {{.HelpName}} s3fast https://s3.amazonaws.com \
+                 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --accelerate --dual-stack
+
+  13. Add Amazon S3 under "s3eu" alias for buckets in the eu-west-1 region, their location is not looked up.
+     $ {{.HelpName}} s3eu https://s3.amazonaws.com \
+                 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --region eu-west-1
+
+  14. Add a distributed MinIO deployment under "cluster" alias, requests fail over between its four nodes.
+     $ {{.HelpName}} cluster https://node1.example.com:9000 minio minio123 \
+                 --endpoint https://node2.example.com:9000 --endpoint https://node3.example.com:9000 \
+                 --endpoint https://node4.example.com:9000
+
 `,
 }
 
@@ -125,17 +213,72 @@ func checkConfigHostAddSyntax(ctx *cli.Context) {
 		fatalIf(errInvalidArgument().Trace(bucketLookup),
 			"Unrecognized bucket lookup. Valid options are `[dns,auto, path]`.")
 	}
+
+	clientCert := ctx.String("client-cert")
+	clientKey := ctx.String("client-key")
+	if (clientCert == "") != (clientKey == "") {
+		fatalIf(errInvalidArgument().Trace(clientCert, clientKey),
+			"Both `--client-cert` and `--client-key` should be provided.")
+	}
+
+	if fingerprint := ctx.String("fingerprint"); fingerprint != "" {
+		_, err := parseFingerprint(fingerprint)
+		fatalIf(err.Trace(fingerprint), "Invalid SHA-256 fingerprint `"+fingerprint+"`.")
+	}
+
+	if proxy := ctx.String("proxy"); proxy != "" {
+		_, err := parseProxyURL(proxy)
+		fatalIf(err.Trace(proxy), "Invalid proxy `"+proxy+"`.")
+	}
+
+	if shareExpire := ctx.String("share-expire"); shareExpire != "" {
+		_, err := parseShareExpiry(shareExpire)
+		fatalIf(err, "Invalid share expiry `"+shareExpire+"`.")
+	}
+
+	if err := checkEndpoints(args.Get(1), ctx.StringSlice("endpoint")); err != nil {
+		fatalIf(err, "Invalid `--endpoint`, endpoints should be URLs of the same scheme as `"+args.Get(1)+"`.")
+	}
+
+	if err := checkAccelerate(args.Get(1), ctx.Bool("accelerate"), ctx.Bool("dual-stack")); err != nil {
+		fatalIf(err, "Transfer acceleration is only available on AWS S3 outside of China and GovCloud, and `--dual-stack` needs `--accelerate`.")
+	}
+
+	for _, flag := range []string{"part-size", "multipart-threshold"} {
+		if size := ctx.String(flag); size != "" {
+			_, err := parseUploadSize(size)
+			fatalIf(err, "Invalid `--"+flag+" "+size+"`.")
+		}
+	}
+
+	for _, file := range []string{ctx.String("ca-cert"), clientCert, clientKey} {
+		if file == "" {
+			continue
+		}
+		if _, e := os.Stat(file); e != nil {
+			fatalIf(probe.NewError(e).Trace(file), "Unable to access `"+file+"`.")
+		}
+	}
+}
+
+// checkAccelerate - transfer acceleration is only available on AWS S3,
+// other requests to AWS S3 always use dual-stack endpoints.
+func checkAccelerate(hostURL string, accelerate, dualStack bool) *probe.Error {
+	if dualStack && !accelerate {
+		return errInvalidArgument().Trace(hostURL)
+	}
+	if accelerate && !canAccelerate(newClientURL(hostURL).Host) {
+		return errInvalidArgument().Trace(hostURL)
+	}
+	return nil
 }
 
 // addHost - add a host config.
 func addHost(alias string, hostCfgV9 hostConfigV9) {
-	mcCfgV9, err := loadMcConfig()
-	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
-
 	// Add new host.
-	mcCfgV9.Hosts[alias] = hostCfgV9
-
-	err = saveMcConfig(mcCfgV9)
+	err := updateMcConfig(func(mcCfgV9 *configV9) {
+		mcCfgV9.Hosts[alias] = hostCfgV9
+	})
 	fatalIf(err.Trace(alias), "Unable to update hosts in config version `"+mustGetMcConfigPath()+"`.")
 
 	printMsg(hostMessage{
@@ -151,16 +294,26 @@ func addHost(alias string, hostCfgV9 hostConfigV9) {
 
 // probeS3Signature - auto probe S3 server signature: issue a Stat call
 // using v4 signature then v2 in case of failure.
-func probeS3Signature(accessKey, secretKey, url string) (string, *probe.Error) {
+func probeS3Signature(hostCfg hostConfigV9) (string, *probe.Error) {
 	probeBucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "probe-bucket-sign-")
 	// Test s3 connection for API auto probe
 	s3Config := &Config{
 		// S3 connection parameters
-		Insecure:  globalInsecure,
-		AccessKey: accessKey,
-		SecretKey: secretKey,
-		Signature: "s3v4",
-		HostURL:   urlJoinPath(url, probeBucketName),
+		Insecure:    globalInsecure,
+		AccessKey:   hostCfg.AccessKey,
+		SecretKey:   hostCfg.SecretKey,
+		Signature:   "s3v4",
+		HostURL:     urlJoinPath(hostCfg.URL, probeBucketName),
+		CACert:      hostCfg.CACert,
+		ClientCert:  hostCfg.ClientCert,
+		ClientKey:   hostCfg.ClientKey,
+		Fingerprint: hostCfg.Fingerprint,
+		Proxy:       hostCfg.Proxy,
+		Region:      hostCfg.Region,
+		Headers:     hostHeaders(&hostCfg),
+		Endpoints:   hostCfg.Endpoints,
+
+		RequesterPays: hostCfg.RequesterPays || globalRequesterPays,
 	}
 
 	s3Client, err := s3New(s3Config)
@@ -168,7 +321,7 @@ func probeS3Signature(accessKey, secretKey, url string) (string, *probe.Error) {
 		return "", err
 	}
 
-	if _, err = s3Client.Stat(false, false, nil); err != nil {
+	if _, err = s3Client.Stat(globalContext, false, false, nil); err != nil {
 		switch err.ToGoError().(type) {
 		case BucketDoesNotExist:
 			// Bucket doesn't exist, means signature probing worked V4.
@@ -179,7 +332,7 @@ func probeS3Signature(accessKey, secretKey, url string) (string, *probe.Error) {
 			if err != nil {
 				return "", err
 			}
-			if _, err = s3Client.Stat(false, false, nil); err != nil {
+			if _, err = s3Client.Stat(globalContext, false, false, nil); err != nil {
 				switch err.ToGoError().(type) {
 				case BucketDoesNotExist:
 					// Bucket doesn't exist, means signature probing worked with V2.
@@ -195,25 +348,20 @@ func probeS3Signature(accessKey, secretKey, url string) (string, *probe.Error) {
 
 // buildS3Config constructs an S3 Config and does
 // signature auto-probe when needed.
-func buildS3Config(url, accessKey, secretKey, api, lookup string) (*Config, *probe.Error) {
+func buildS3Config(hostCfg hostConfigV9) (*Config, *probe.Error) {
 
-	s3Config := newS3Config(url, &hostConfigV9{
-		AccessKey: accessKey,
-		SecretKey: secretKey,
-		URL:       url,
-		Lookup:    lookup,
-	})
+	s3Config := newS3Config(hostCfg.URL, &hostCfg)
 
 	// If api is provided we do not auto probe signature, this is
 	// required in situations when signature type is provided by the user.
-	if api != "" {
-		s3Config.Signature = api
+	if hostCfg.API != "" {
+		s3Config.Signature = hostCfg.API
 		return s3Config, nil
 	}
 	// Probe S3 signature version
-	api, err := probeS3Signature(accessKey, secretKey, url)
+	api, err := probeS3Signature(hostCfg)
 	if err != nil {
-		return nil, err.Trace(url, accessKey, secretKey, api, lookup)
+		return nil, err.Trace(hostCfg.URL, hostCfg.AccessKey, hostCfg.SecretKey, hostCfg.API, hostCfg.Lookup)
 	}
 
 	s3Config.Signature = api
@@ -233,16 +381,51 @@ func mainConfigHostAdd(ctx *cli.Context) error {
 		api       = ctx.String("api")
 		lookup    = ctx.String("lookup")
 	)
+	var endpoints []string
+	for _, endpoint := range ctx.StringSlice("endpoint") {
+		endpoints = append(endpoints, trimTrailingSeparator(endpoint))
+	}
 
-	s3Config, err := buildS3Config(url, accessKey, secretKey, api, lookup)
+	s3Config, err := buildS3Config(hostConfigV9{
+		URL:         url,
+		AccessKey:   accessKey,
+		SecretKey:   secretKey,
+		API:         api,
+		Lookup:      lookup,
+		CACert:      ctx.String("ca-cert"),
+		ClientCert:  ctx.String("client-cert"),
+		ClientKey:   ctx.String("client-key"),
+		Fingerprint: ctx.String("fingerprint"),
+		Proxy:       ctx.String("proxy"),
+		Region:      ctx.String("region"),
+		Endpoints:   endpoints,
+	})
 	fatalIf(err.Trace(ctx.Args()...), "Unable to initialize new config from the provided credentials.")
 
 	addHost(ctx.Args().Get(0), hostConfigV9{
-		URL:       s3Config.HostURL,
-		AccessKey: s3Config.AccessKey,
-		SecretKey: s3Config.SecretKey,
-		API:       s3Config.Signature,
-		Lookup:    lookup,
+		URL:         s3Config.HostURL,
+		AccessKey:   s3Config.AccessKey,
+		SecretKey:   s3Config.SecretKey,
+		API:         s3Config.Signature,
+		Lookup:      lookup,
+		CACert:      s3Config.CACert,
+		ClientCert:  s3Config.ClientCert,
+		ClientKey:   s3Config.ClientKey,
+		Fingerprint: s3Config.Fingerprint,
+		Proxy:       ctx.String("proxy"),
+		Region:      ctx.String("region"),
+		ShareExpiry: ctx.String("share-expire"),
+		Headers:     hostHeadersConfig(globalHeaders),
+		Endpoints:   endpoints,
+
+		RequesterPays: globalRequesterPays,
+
+		PartSize:           ctx.String("part-size"),
+		MultipartThreshold: ctx.String("multipart-threshold"),
+		DisableMultipart:   ctx.Bool("disable-multipart"),
+
+		Accelerate: ctx.Bool("accelerate"),
+		DualStack:  ctx.Bool("dual-stack"),
 	}) // Add a host with specified credentials.
 	return nil
 }
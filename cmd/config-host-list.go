@@ -78,6 +78,8 @@ func mainConfigHostList(ctx *cli.Context) error {
 	console.SetColor("SecretKey", color.New(color.FgCyan))
 	console.SetColor("API", color.New(color.FgBlue))
 	console.SetColor("Lookup", color.New(color.FgCyan))
+	console.SetColor("TLS", color.New(color.FgMagenta))
+	console.SetColor("Proxy", color.New(color.FgBlue))
 
 	args := ctx.Args()
 	listHosts(args.Get(0)) // List all configured hosts.
@@ -130,6 +132,12 @@ func listHosts(alias string) {
 				SecretKey:   v.SecretKey,
 				API:         v.API,
 				Lookup:      v.Lookup,
+				CACert:      v.CACert,
+				ClientCert:  v.ClientCert,
+				ClientKey:   v.ClientKey,
+				Fingerprint: v.Fingerprint,
+				Proxy:       v.Proxy,
+				Endpoints:   v.Endpoints,
 			})
 			return
 		}
@@ -147,6 +155,12 @@ func listHosts(alias string) {
 			SecretKey:   v.SecretKey,
 			API:         v.API,
 			Lookup:      v.Lookup,
+			CACert:      v.CACert,
+			ClientCert:  v.ClientCert,
+			ClientKey:   v.ClientKey,
+			Fingerprint: v.Fingerprint,
+			Proxy:       v.Proxy,
+			Endpoints:   v.Endpoints,
 		})
 	}
 
@@ -75,13 +75,10 @@ func mainConfigHostRemove(ctx *cli.Context) error {
 
 // removeHost - removes a host.
 func removeHost(alias string) {
-	conf, err := loadMcConfig()
-	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
-
 	// Remove host.
-	delete(conf.Hosts, alias)
-
-	err = saveMcConfig(conf)
+	err := updateMcConfig(func(conf *configV9) {
+		delete(conf.Hosts, alias)
+	})
 	fatalIf(err.Trace(alias), "Unable to save deleted hosts in config version `"+globalMCConfigVersion+"`.")
 
 	printMsg(hostMessage{op: "remove", Alias: alias})
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/console"
@@ -55,6 +57,14 @@ type hostMessage struct {
 	SecretKey   string `json:"secretKey,omitempty"`
 	API         string `json:"api,omitempty"`
 	Lookup      string `json:"lookup,omitempty"`
+	CACert      string `json:"caCert,omitempty"`
+	ClientCert  string `json:"clientCert,omitempty"`
+	ClientKey   string `json:"clientKey,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Proxy       string `json:"proxy,omitempty"`
+
+	// Other endpoints of the deployment.
+	Endpoints []string `json:"endpoints,omitempty"`
 }
 
 // Print the config information of one alias, when prettyPrint flag
@@ -63,16 +73,38 @@ type hostMessage struct {
 func (h hostMessage) String() string {
 	switch h.op {
 	case "list":
+		rows := []Row{
+			{"Alias", "Alias"},
+			{"URL", "URL"},
+			{"AccessKey", "AccessKey"},
+			{"SecretKey", "SecretKey"},
+			{"API", "API"},
+			{"Lookup", "Lookup"},
+		}
+		contents := []string{h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, h.Lookup}
+		// Show TLS settings only when configured.
+		for _, tlsRow := range []struct{ desc, value string }{
+			{"CACert", h.CACert},
+			{"ClientCert", h.ClientCert},
+			{"ClientKey", h.ClientKey},
+			{"Fingerprint", h.Fingerprint},
+		} {
+			if tlsRow.value != "" {
+				rows = append(rows, Row{tlsRow.desc, "TLS"})
+				contents = append(contents, tlsRow.value)
+			}
+		}
+		if h.Proxy != "" {
+			rows = append(rows, Row{"Proxy", "Proxy"})
+			contents = append(contents, h.Proxy)
+		}
+		if len(h.Endpoints) > 0 {
+			rows = append(rows, Row{"Endpoints", "URL"})
+			contents = append(contents, strings.Join(h.Endpoints, ", "))
+		}
 		// Create a new pretty table with cols configuration
-		t := newPrettyRecord(2,
-			Row{"Alias", "Alias"},
-			Row{"URL", "URL"},
-			Row{"AccessKey", "AccessKey"},
-			Row{"SecretKey", "SecretKey"},
-			Row{"API", "API"},
-			Row{"Lookup", "Lookup"},
-		)
-		return t.buildRecord(h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, h.Lookup)
+		t := newPrettyRecord(2, rows...)
+		return t.buildRecord(contents...)
 	case "remove":
 		return console.Colorize("HostMessage", "Removed `"+h.Alias+"` successfully.")
 	case "add":
@@ -0,0 +1,222 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var configImportFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "overwrite",
+		Usage: "replace hosts already in configuration file",
+	},
+}
+
+var configImportCmd = cli.Command{
+	Name:            "import",
+	Usage:           "import hosts into configuration file",
+	Action:          mainConfigImport,
+	Before:          setGlobalsFromContext,
+	Flags:           append(configImportFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FILE
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+   MC_CONFIG_PASSPHRASE: passphrase of encrypted secrets, asked for when not set
+
+DESCRIPTION:
+  Hosts exported by 'config export' are read from FILE, or from the standard input when FILE is '-'.
+  Hosts already in the configuration file are kept unless '--overwrite' is given.
+
+EXAMPLES:
+  1. Import hosts from a file.
+     $ {{.HelpName}} mc-hosts.json
+
+  2. Import hosts on a CI runner, replacing existing ones and decrypting secrets with a passphrase.
+     $ export MC_CONFIG_PASSPHRASE=$(cat /run/secrets/mc-passphrase)
+     $ {{.HelpName}} --overwrite mc-hosts.json
+
+  3. Copy all hosts to another machine.
+     $ mc config export --encrypt | ssh backup.example.com mc config import -
+
+`,
+}
+
+// configImportMessage container for imported host message structure.
+type configImportMessage struct {
+	Status  string `json:"status"`
+	Alias   string `json:"alias"`
+	URL     string `json:"URL"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// String colorized imported host message.
+func (c configImportMessage) String() string {
+	if c.Skipped {
+		return console.Colorize("HostSkipped", "Skipped `"+c.Alias+"`, it already exists.")
+	}
+	return console.Colorize("HostMessage", "Imported `"+c.Alias+"` successfully.")
+}
+
+// JSON jsonified imported host message.
+func (c configImportMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// readConfigExport - read and validate hosts exported by 'config export'.
+func readConfigExport(reader io.Reader) (*configExport, *probe.Error) {
+	export := &configExport{}
+	if e := json.NewDecoder(reader).Decode(export); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if export.Version != configExportVersion {
+		return nil, probe.NewError(fmt.Errorf("unsupported export version `%s`", export.Version))
+	}
+	if export.Encryption != nil && export.Encryption.KDF != configExportKDF {
+		return nil, probe.NewError(fmt.Errorf("unsupported key derivation `%s`", export.Encryption.KDF))
+	}
+	for alias, hostCfg := range export.Hosts {
+		if !isValidAlias(alias) {
+			return nil, errInvalidAlias(alias).Trace(alias)
+		}
+		if !isValidHostURL(hostCfg.URL) {
+			return nil, errInvalidURL(hostCfg.URL).Trace(alias)
+		}
+		if hostCfg.API != "" && !isValidAPI(hostCfg.API) {
+			return nil, errInvalidArgument().Trace(alias, hostCfg.API)
+		}
+		if hostCfg.Lookup != "" && !isValidLookup(hostCfg.Lookup) {
+			return nil, errInvalidArgument().Trace(alias, hostCfg.Lookup)
+		}
+	}
+	return export, nil
+}
+
+// decryptConfigExport - decrypt the secrets of exported hosts in place.
+func decryptConfigExport(export *configExport, passphrase []byte) *probe.Error {
+	if export.Encryption == nil {
+		return nil
+	}
+	salt, e := base64.StdEncoding.DecodeString(export.Encryption.Salt)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	aead, err := deriveConfigExportKey(passphrase, salt)
+	if err != nil {
+		return err.Trace()
+	}
+	for alias, hostCfg := range export.Hosts {
+		hostCfg, err = mapHostSecrets(alias, hostCfg, func(field, sealed string) (string, *probe.Error) {
+			return openSecret(aead, field, sealed)
+		})
+		if err != nil {
+			return err.Trace(alias)
+		}
+		export.Hosts[alias] = hostCfg
+	}
+	export.Encryption = nil
+	return nil
+}
+
+// importHosts - merge exported hosts into the hosts of the config, hosts
+// already configured are only replaced when overwrite is set.
+func importHosts(hosts map[string]hostConfigV9, export *configExport, overwrite bool) []configImportMessage {
+	var aliases []string
+	for alias := range export.Hosts {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	var msgs []configImportMessage
+	for _, alias := range aliases {
+		hostCfg := export.Hosts[alias]
+		msg := configImportMessage{Alias: alias, URL: hostCfg.URL}
+		if _, ok := hosts[alias]; ok && !overwrite {
+			msg.Skipped = true
+		} else {
+			hosts[alias] = hostCfg
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// checkConfigImportSyntax - verifies input arguments to 'config import'.
+func checkConfigImportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "import", 1) // last argument is exit code
+	}
+}
+
+func mainConfigImport(ctx *cli.Context) error {
+	checkConfigImportSyntax(ctx)
+
+	console.SetColor("HostMessage", color.New(color.FgGreen))
+	console.SetColor("HostSkipped", color.New(color.FgYellow))
+
+	filename := ctx.Args().Get(0)
+	reader := os.Stdin
+	if filename != "-" {
+		file, e := os.Open(filename)
+		fatalIf(probe.NewError(e).Trace(filename), "Unable to open `"+filename+"`.")
+		defer file.Close()
+		reader = file
+	}
+
+	export, err := readConfigExport(reader)
+	fatalIf(err.Trace(filename), "Unable to read hosts from `"+filename+"`.")
+
+	if export.Encryption != nil {
+		passphrase, err := getConfigPassphrase(false)
+		fatalIf(err, "Unable to read passphrase.")
+		err = decryptConfigExport(export, passphrase)
+		fatalIf(err, "Unable to decrypt secrets.")
+	}
+
+	var msgs []configImportMessage
+	err = updateMcConfig(func(conf *configV9) {
+		msgs = importHosts(conf.Hosts, export, ctx.Bool("overwrite"))
+	})
+	fatalIf(err.Trace(filename), "Unable to update hosts in config `"+mustGetMcConfigPath()+"`.")
+
+	for _, msg := range msgs {
+		printMsg(msg)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	// How long to wait for the lock held by another mc process.
+	mcConfigLockTimeout = 30 * time.Second
+	// How often to try taking the lock while waiting.
+	mcConfigLockRetry = 10 * time.Millisecond
+)
+
+// Serializes the goroutines of this process, the lock file only
+// serializes processes.
+var mcConfigLockMutex sync.Mutex
+
+// mcConfigLock - an advisory lock of the config folder, held by a
+// process while it writes the config, session or share files, so that
+// concurrent mc processes do not lose each other's changes. It is held
+// briefly, never while waiting on the network, and must not be taken
+// while already held.
+type mcConfigLock struct {
+	file *os.File
+}
+
+// getMcConfigLockPath - the lock file of the config folder, next to the
+// lock files of mirrors.
+func getMcConfigLockPath() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, "locks", "config.lock"), nil
+}
+
+// lockMcConfig - lock the config folder, waiting for other processes
+// holding it. A config folder in which the lock cannot be created is
+// not locked, nothing can be written to it either.
+func lockMcConfig() (*mcConfigLock, *probe.Error) {
+	path, err := getMcConfigLockPath()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	mcConfigLockMutex.Lock()
+	if e := os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+		return &mcConfigLock{}, nil
+	}
+	deadline := time.Now().Add(mcConfigLockTimeout)
+	for {
+		file, locked, e := tryLockFile(path)
+		switch {
+		case locked:
+			return &mcConfigLock{file: file}, nil
+		case e != nil:
+			return &mcConfigLock{}, nil
+		case time.Now().After(deadline):
+			mcConfigLockMutex.Unlock()
+			return nil, errConfigLocked(path).Trace(path)
+		}
+		time.Sleep(mcConfigLockRetry)
+	}
+}
+
+// Release - release the lock.
+func (l *mcConfigLock) Release() {
+	if l == nil {
+		return
+	}
+	if l.file != nil {
+		l.file.Close()
+	}
+	mcConfigLockMutex.Unlock()
+}
@@ -0,0 +1,167 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio/pkg/quick"
+)
+
+func TestLockMcConfig(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-config-lock-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(root)
+
+	lock, err := lockMcConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := getMcConfigLockPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another process cannot take the lock while it is held.
+	if file, locked, e := tryLockFile(path); e != nil || locked {
+		if locked {
+			file.Close()
+		}
+		t.Fatalf("expected the lock to be held: %v", e)
+	}
+	lock.Release()
+	file, locked, e := tryLockFile(path)
+	if e != nil || !locked {
+		t.Fatalf("expected the released lock: %v", e)
+	}
+	file.Close()
+}
+
+func TestUpdateMcConfig(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-config-lock-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	savedLoadMcConfig, savedCacheCfgV9 := loadMcConfig, cacheCfgV9
+	defer func() { loadMcConfig, cacheCfgV9 = savedLoadMcConfig, savedCacheCfgV9 }()
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(root, "config"))
+	cacheCfgV9 = nil
+
+	if created, err := createMcConfig(); err != nil || !created {
+		t.Fatalf("expected the config to be created: %v", err)
+	}
+	if created, err := createMcConfig(); err != nil || created {
+		t.Fatalf("expected the existing config to be kept: %v", err)
+	}
+	loadMcConfig = loadMcConfigFactory()
+
+	// Another process adds a host after this one loaded the config.
+	other := newMcConfig()
+	other.Hosts["other"] = hostConfigV9{URL: "https://other.example.com", API: "S3v4", Lookup: "auto"}
+	qs, e := quick.NewConfig(other, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = qs.Save(mustGetMcConfigPath()); e != nil {
+		t.Fatal(e)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			alias := fmt.Sprintf("host%d", i)
+			err := updateMcConfig(func(config *configV9) {
+				config.Hosts[alias] = hostConfigV9{URL: "https://" + alias + ".example.com", API: "S3v4", Lookup: "auto"}
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	config, err := readConfigV9()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.Hosts["other"]; !ok {
+		t.Error("the host added by another process was lost")
+	}
+	for i := 0; i < 10; i++ {
+		if _, ok := config.Hosts[fmt.Sprintf("host%d", i)]; !ok {
+			t.Errorf("host%d was lost", i)
+		}
+	}
+}
+
+func TestLoadMcConfigWhileSaved(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-config-lock-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(root)
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	if _, err := createMcConfig(); err != nil {
+		t.Fatal(err)
+	}
+	loadMcConfig = loadMcConfigFactory()
+
+	// Hosts saved while other workers read the config, as refreshed logins do.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		alias := fmt.Sprintf("host%d", i)
+		go func() {
+			defer wg.Done()
+			if err := saveHostConfig(alias, hostConfigV9{URL: "http://localhost:9000", API: "S3v4", Lookup: "auto"}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := loadMcConfig(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	config, err := loadMcConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if _, ok := config.Hosts[fmt.Sprintf("host%d", i)]; !ok {
+			t.Errorf("expected host%d to be loaded after it was saved", i)
+		}
+	}
+}
@@ -42,6 +42,9 @@ var configCmd = cli.Command{
 	Flags:           append(configFlags, globalFlags...),
 	Subcommands: []cli.Command{
 		configHostCmd,
+		configExportCmd,
+		configImportCmd,
+		configAuditCmd,
 	},
 }
 
@@ -76,3 +76,31 @@ func TestValidAccessKeys(t *testing.T) {
 	equalAssert(isValidAccessKey("EXOb76bfeb1234562iu679f11588"), true, t)
 	equalAssert(isValidAccessKey("BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"), true, t)
 }
+
+func TestCheckAccelerate(t *testing.T) {
+	testCases := []struct {
+		hostURL    string
+		accelerate bool
+		dualStack  bool
+		success    bool
+	}{
+		{"https://s3.amazonaws.com", true, false, true},
+		{"https://s3.eu-west-1.amazonaws.com", true, true, true},
+		{"https://s3.amazonaws.com", false, false, true},
+		{"https://localhost:9000", false, false, true},
+		// Regional endpoints are always dual-stack.
+		{"https://s3.amazonaws.com", false, true, false},
+		{"https://localhost:9000", true, false, false},
+		{"https://s3.cn-north-1.amazonaws.com.cn", true, false, false},
+		{"https://s3-us-gov-west-1.amazonaws.com", true, false, false},
+		{"https://s3-fips-us-gov-west-1.amazonaws.com", true, false, false},
+	}
+	for i, testCase := range testCases {
+		err := checkAccelerate(testCase.hostURL, testCase.accelerate, testCase.dualStack)
+		if (err == nil) != testCase.success {
+			t.Errorf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+	}
+	equalAssert(accelerateEndpoint(false) == "s3-accelerate.amazonaws.com", true, t)
+	equalAssert(accelerateEndpoint(true) == "s3-accelerate.dualstack.amazonaws.com", true, t)
+}
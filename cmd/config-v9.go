@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"sync"
+	"time"
 
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio/pkg/quick"
@@ -42,12 +43,112 @@ type hostConfigV9 struct {
 	SecretKey string `json:"secretKey"`
 	API       string `json:"api"`
 	Lookup    string `json:"lookup"`
+
+	// Optional TLS settings, paths to PEM encoded files.
+	CACert     string `json:"caCert,omitempty"`
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+
+	// Optional SHA-256 fingerprint of the server certificate, when set
+	// the certificate is pinned instead of verified against CAs.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Optional HTTP, HTTPS or SOCKS5 proxy URL used for this host.
+	Proxy string `json:"proxy,omitempty"`
+
+	// Optional HTTP headers added to all requests to this host, by name.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Optional, requests to this host accept the charges of requester
+	// pays buckets.
+	RequesterPays bool `json:"requesterPays,omitempty"`
+
+	// Optional, other endpoints of the same deployment, requests fail
+	// over to them when the URL is unreachable.
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Optional region of all buckets of this host, their location is not
+	// looked up.
+	Region string `json:"region,omitempty"`
+
+	// Optional, requests to buckets of AWS S3 use transfer acceleration,
+	// over IPv4 and IPv6 with dual-stack.
+	Accelerate bool `json:"accelerate,omitempty"`
+	DualStack  bool `json:"dualStack,omitempty"`
+
+	// Optional default expiry of URLs shared by 'share download'.
+	ShareExpiry string `json:"shareExpiry,omitempty"`
+
+	// Optional multipart strategy of uploads to this host, overridden by
+	// '--part-size', '--multipart-threshold' and '--disable-multipart'.
+	PartSize           string `json:"partSize,omitempty"`
+	MultipartThreshold string `json:"multipartThreshold,omitempty"`
+	DisableMultipart   bool   `json:"disableMultipart,omitempty"`
+
+	// Optional session token of temporary credentials, set by 'mc login'.
+	SessionToken string `json:"sessionToken,omitempty"`
+
+	// Optional OpenID Connect login the temporary credentials are
+	// refreshed with.
+	Login *hostLoginV9 `json:"login,omitempty"`
+}
+
+// hostLoginV9 OpenID Connect login of a host.
+type hostLoginV9 struct {
+	Issuer        string    `json:"issuer"`
+	ClientID      string    `json:"clientID"`
+	Scope         string    `json:"scope,omitempty"`
+	TokenEndpoint string    `json:"tokenEndpoint,omitempty"`
+	RefreshToken  string    `json:"refreshToken,omitempty"`
+	Expiration    time.Time `json:"expiration"`
 }
 
 // configV8 config version.
 type configV9 struct {
 	Version string                  `json:"version"`
 	Hosts   map[string]hostConfigV9 `json:"hosts"`
+
+	// Optional, disables the check for updates on startup.
+	NoUpdateCheck bool `json:"noUpdateCheck,omitempty"`
+
+	// Optional, answer to installing auto-completion: "install" or "skip".
+	AutoCompletion string `json:"autoCompletion,omitempty"`
+
+	// Optional, audit log of mutating commands: "off", "file" or "syslog".
+	Audit string `json:"audit,omitempty"`
+
+	// Optional, scripts run before and after commands, by command name
+	// like "mirror" or "admin heal".
+	Hooks map[string]hookConfigV9 `json:"hooks,omitempty"`
+
+	// Optional, colors of the output.
+	Theme *themeConfigV9 `json:"theme,omitempty"`
+
+	// Optional, defaults of flags selected with --profile, by profile
+	// name.
+	Profiles map[string]profileConfigV9 `json:"profiles,omitempty"`
+
+	// Optional, command lines run in place of a name, like "backup" for
+	// "mirror --remove --overwrite".
+	CommandAliases map[string]string `json:"commandAliases,omitempty"`
+}
+
+// themeConfigV9 - a built-in theme, "default", "high-contrast" or
+// "monochrome", and colors of tags replacing those of the theme.
+type themeConfigV9 struct {
+	Name   string            `json:"name,omitempty"`
+	Colors map[string]string `json:"colors,omitempty"`
+}
+
+// profileConfigV9 - values of flags by flag name, like "storage-class"
+// or "exclude". Values are strings, numbers, booleans, or lists of
+// strings for flags given more than once.
+type profileConfigV9 map[string]interface{}
+
+// hookConfigV9 - shell commands run before and after a command.
+type hookConfigV9 struct {
+	Pre  string `json:"pre,omitempty"`
+	Post string `json:"post,omitempty"`
 }
 
 // newConfigV9 - new config version.
@@ -114,6 +215,20 @@ func loadConfigV9() (*configV9, *probe.Error) {
 		return cacheCfgV9, nil
 	}
 
+	cfgV9, err := readConfigV9()
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache config.
+	cacheCfgV9 = cfgV9
+
+	// Success.
+	return cfgV9, nil
+}
+
+// readConfigV9 - reads the config file, bypassing the cache.
+func readConfigV9() (*configV9, *probe.Error) {
 	if !isMcConfigExists() {
 		return nil, errInvalidArgument().Trace()
 	}
@@ -130,13 +245,16 @@ func loadConfigV9() (*configV9, *probe.Error) {
 		return nil, probe.NewError(e)
 	}
 
-	cfgV9 := qc.Data().(*configV9)
+	return qc.Data().(*configV9), nil
+}
 
-	// Cache config.
-	cacheCfgV9 = cfgV9
+// cacheConfigV9 - use a config without loading it, for config folders
+// which cannot be written to.
+func cacheConfigV9(cfgV9 *configV9) {
+	cfgMutex.Lock()
+	defer cfgMutex.Unlock()
 
-	// Success.
-	return cfgV9, nil
+	cacheCfgV9 = cfgV9
 }
 
 // saveConfigV8 - saves an updated config.
@@ -47,6 +47,16 @@ func validateConfigFile(config *configV9) (bool, []string) {
 			errors = append(errors, hostErrors...)
 		}
 	}
+	for name, profile := range config.Profiles {
+		if profileErrors := validateConfigProfile(name, profile); len(profileErrors) > 0 {
+			validationSuccessful = false
+			errors = append(errors, profileErrors...)
+		}
+	}
+	if aliasErrors := validateConfigCommandAliases(config.CommandAliases); len(aliasErrors) > 0 {
+		validationSuccessful = false
+		errors = append(errors, aliasErrors...)
+	}
 	return validationSuccessful, errors
 }
 
@@ -61,5 +71,35 @@ func validateConfigHost(host hostConfigV9) (bool, []string) {
 		validationSuccessful = false
 		hostErrors = append(hostErrors, errInvalidURL(host.URL).ToGoError().Error())
 	}
+	if (host.ClientCert == "") != (host.ClientKey == "") {
+		validationSuccessful = false
+		hostErrors = append(hostErrors, fmt.Sprintf("Both clientCert and clientKey should be set for host %s", host.URL))
+	}
+	if host.Fingerprint != "" {
+		if _, err := parseFingerprint(host.Fingerprint); err != nil {
+			validationSuccessful = false
+			hostErrors = append(hostErrors, fmt.Sprintf("Invalid SHA-256 fingerprint %s for host %s", host.Fingerprint, host.URL))
+		}
+	}
+	if host.Proxy != "" {
+		if _, err := parseProxyURL(host.Proxy); err != nil {
+			validationSuccessful = false
+			hostErrors = append(hostErrors, fmt.Sprintf("Invalid proxy %s for host %s", host.Proxy, host.URL))
+		}
+	}
+	if err := checkEndpoints(host.URL, host.Endpoints); err != nil {
+		validationSuccessful = false
+		hostErrors = append(hostErrors, fmt.Sprintf("Invalid endpoints for host %s", host.URL))
+	}
+	if err := checkAccelerate(host.URL, host.Accelerate, host.DualStack); err != nil {
+		validationSuccessful = false
+		hostErrors = append(hostErrors, fmt.Sprintf("Invalid transfer acceleration settings for host %s", host.URL))
+	}
+	for name, value := range host.Headers {
+		if err := checkHTTPHeader(name, value); err != nil {
+			validationSuccessful = false
+			hostErrors = append(hostErrors, fmt.Sprintf("Invalid header %s for host %s", name, host.URL))
+		}
+	}
 	return validationSuccessful, hostErrors
 }
@@ -18,11 +18,14 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/minio/mc/pkg/probe"
 
@@ -103,13 +106,28 @@ func newMcConfig() *configV9 {
 	return cfg
 }
 
+// mcConfigGeneration - bumped whenever the config file is saved, a
+// cached config of an older generation is loaded again.
+var mcConfigGeneration int64
+
 // loadMcConfigCached - returns loadMcConfig with a closure for config cache.
 func loadMcConfigFactory() func() (*configV9, *probe.Error) {
-	// Load once and cache in a closure.
-	cfgCache, err := loadConfigV9()
+	var (
+		mutex      sync.Mutex
+		cfgCache   *configV9
+		err        *probe.Error
+		generation int64 = -1
+	)
 
 	// loadMcConfig - reads configuration file and returns config.
 	return func() (*configV9, *probe.Error) {
+		latest := atomic.LoadInt64(&mcConfigGeneration)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if generation != latest {
+			cfgCache, err = loadConfigV9()
+			generation = latest
+		}
 		return cfgCache, err
 	}
 }
@@ -117,12 +135,47 @@ func loadMcConfigFactory() func() (*configV9, *probe.Error) {
 // loadMcConfig - returns configuration, initialized later.
 var loadMcConfig func() (*configV9, *probe.Error)
 
-// saveMcConfig - saves configuration file and returns error if any.
-func saveMcConfig(config *configV9) *probe.Error {
-	if config == nil {
-		return errInvalidArgument().Trace()
+// createMcConfig - saves a new configuration file, unless another mc
+// process created it meanwhile. Returns true if it was saved.
+func createMcConfig() (bool, *probe.Error) {
+	lock, err := lockMcConfig()
+	if err != nil {
+		return false, err.Trace()
+	}
+	defer lock.Release()
+
+	if isMcConfigExists() {
+		return false, nil
+	}
+	return true, writeMcConfig(newMcConfig())
+}
+
+// updateMcConfig - reads the config file again while the config folder
+// is locked, so that changes saved by other mc processes since it was
+// loaded are kept, and saves it updated by fn.
+func updateMcConfig(fn func(config *configV9)) *probe.Error {
+	lock, err := lockMcConfig()
+	if err != nil {
+		return err.Trace()
+	}
+	defer lock.Release()
+
+	// A config which was never saved is updated as loaded.
+	readConfig := loadMcConfig
+	if isMcConfigExists() {
+		readConfig = readConfigV9
 	}
+	config, err := readConfig()
+	if err != nil {
+		return err.Trace(mustGetMcConfigPath())
+	}
+	fn(config)
+	return writeMcConfig(config)
+}
 
+// writeMcConfig - saves the configuration file, the config folder must
+// be locked.
+func writeMcConfig(config *configV9) *probe.Error {
 	err := createMcConfigDir()
 	if err != nil {
 		return err.Trace(mustGetMcConfigDir())
@@ -133,8 +186,8 @@ func saveMcConfig(config *configV9) *probe.Error {
 		return err.Trace(mustGetMcConfigPath())
 	}
 
-	// Refresh the config cache.
-	loadMcConfig = loadMcConfigFactory()
+	// Refresh the config cache, the config is loaded again when next read.
+	atomic.AddInt64(&mcConfigGeneration, 1)
 	return nil
 }
 
@@ -150,13 +203,33 @@ func isMcConfigExists() bool {
 	return true
 }
 
+// isMcConfigDirWritable - returns false if files cannot be created in
+// the config folder, e.g. in a read-only container.
+func isMcConfigDirWritable() bool {
+	if err := createMcConfigDir(); err != nil {
+		return false
+	}
+	f, e := ioutil.TempFile(mustGetMcConfigDir(), ".writable-")
+	if e != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
 // isValidAlias - Check if alias valid.
 func isValidAlias(alias string) bool {
 	return regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9-_]+$").MatchString(alias)
 }
 
 // getHostConfig retrieves host specific configuration such as access keys, signature type.
+// Hosts defined by MC_HOST_<alias> environment variables take precedence over the config file.
 func getHostConfig(alias string) (*hostConfigV9, *probe.Error) {
+	if hostCfg, err := getEnvHostConfig(alias); hostCfg != nil || err != nil {
+		return hostCfg, err
+	}
+
 	mcCfg, err := loadMcConfig()
 	if err != nil {
 		return nil, err.Trace(alias)
@@ -172,22 +245,27 @@ func getHostConfig(alias string) (*hostConfigV9, *probe.Error) {
 	return nil, errNoMatchingHost(alias).Trace(alias)
 }
 
-// mustGetHostConfig retrieves host specific configuration such as access keys, signature type.
-func mustGetHostConfig(alias string) *hostConfigV9 {
-	hostCfg, _ := getHostConfig(alias)
-	// If alias is not found,
-	// look for it in the environment variable.
-	if hostCfg == nil {
-		if envConfig, ok := os.LookupEnv(mcEnvHostPrefix + alias); ok {
-			hostCfg, _ = expandAliasFromEnv(envConfig)
+// getEnvHostConfig retrieves host specific configuration from the
+// MC_HOST_<alias> environment variable, nil if it is not set.
+func getEnvHostConfig(alias string) (*hostConfigV9, *probe.Error) {
+	envConfig, ok := os.LookupEnv(mcEnvHostPrefix + alias)
+	if !ok {
+		envConfig, ok = os.LookupEnv(mcEnvHostsDeprecatedPrefix + alias)
+		if !ok {
+			return nil, nil
 		}
+		errorIf(errInvalidArgument().Trace(mcEnvHostsDeprecatedPrefix+alias), "`MC_HOSTS_<alias>` environment variable is deprecated. Please use `MC_HOST_<alias>` instead for the same functionality.")
 	}
-	if hostCfg == nil {
-		if envConfig, ok := os.LookupEnv(mcEnvHostsDeprecatedPrefix + alias); ok {
-			errorIf(errInvalidArgument().Trace(mcEnvHostsDeprecatedPrefix+alias), "`MC_HOSTS_<alias>` environment variable is deprecated. Please use `MC_HOST_<alias>` instead for the same functionality.")
-			hostCfg, _ = expandAliasFromEnv(envConfig)
-		}
+	hostCfg, err := expandAliasFromEnv(envConfig)
+	if err != nil {
+		return nil, err.Trace(alias)
 	}
+	return hostCfg, nil
+}
+
+// mustGetHostConfig retrieves host specific configuration such as access keys, signature type.
+func mustGetHostConfig(alias string) *hostConfigV9 {
+	hostCfg, _ := getHostConfig(alias)
 	return hostCfg
 }
 
@@ -282,26 +360,23 @@ func expandAlias(aliasedURL string) (alias string, urlStr string, hostCfg *hostC
 	// Extract alias from the URL.
 	alias, path := url2Alias(aliasedURL)
 
-	var envConfig string
-	var ok bool
-
-	if envConfig, ok = os.LookupEnv(mcEnvHostPrefix + alias); !ok {
-		envConfig, ok = os.LookupEnv(mcEnvHostsDeprecatedPrefix + alias)
-		if ok {
-			errorIf(errInvalidArgument().Trace(mcEnvHostsDeprecatedPrefix+alias), "`MC_HOSTS_<alias>` environment variable is deprecated. Please use `MC_HOST_<alias>` instead for the same functionality.")
-		}
+	// Hosts from the environment take precedence, a malformed
+	// MC_HOST_<alias> is reported instead of being ignored.
+	if hostCfg, err = getEnvHostConfig(alias); err != nil {
+		return "", "", nil, err.Trace(aliasedURL)
 	}
 
-	if ok {
-		hostCfg, err = expandAliasFromEnv(envConfig)
-		if err != nil {
-			return "", "", nil, err.Trace(aliasedURL)
+	// Find the matching alias entry and expand the URL, refreshing
+	// the temporary credentials of 'mc login' if they expire.
+	if hostCfg == nil {
+		hostCfg = mustGetHostConfig(alias)
+		if hostCfg != nil && hostCfg.Login.isExpiring() {
+			if hostCfg, err = refreshLogin(alias); err != nil {
+				return "", "", nil, err.Trace(aliasedURL)
+			}
 		}
-		return alias, urlJoinPath(hostCfg.URL, path), hostCfg, nil
 	}
-
-	// Find the matching alias entry and expand the URL.
-	if hostCfg = mustGetHostConfig(alias); hostCfg != nil {
+	if hostCfg != nil {
 		return alias, urlJoinPath(hostCfg.URL, path), hostCfg, nil
 	}
 	return "", aliasedURL, nil, nil // No matching entry found. Return original URL as is.
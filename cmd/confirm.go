@@ -0,0 +1,100 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minio/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Objects counted at most for the estimate of a confirmation question.
+const confirmCountLimit = 10000
+
+// yesFlag skips the confirmation of irreversible operations.
+var yesFlag = cli.BoolFlag{
+	Name:  "yes, y",
+	Usage: "do not ask for confirmation",
+}
+
+// countObjects - estimate the number of objects listed by the client,
+// counting stops after confirmCountLimit objects.
+func countObjects(clnt Client, isIncomplete bool) int {
+	ctx, cancelList := context.WithCancel(globalContext)
+	defer cancelList()
+	contentCh := clnt.List(ctx, true, isIncomplete, DirNone)
+	// The listing is cancelled and drained when counting stops early,
+	// so that it does not block.
+	defer func() { go drainContents(contentCh) }()
+	count := 0
+	for content := range contentCh {
+		if content.Err != nil || content.Type.IsDir() {
+			continue
+		}
+		count++
+		if count > confirmCountLimit {
+			break
+		}
+	}
+	return count
+}
+
+// describeObjectCount - human readable object count of countObjects.
+func describeObjectCount(count int) string {
+	switch {
+	case count > confirmCountLimit:
+		return fmt.Sprintf("more than %d objects", confirmCountLimit)
+	case count == 1:
+		return "1 object"
+	}
+	return fmt.Sprintf("%d objects", count)
+}
+
+// askConfirmation - ask a yes or no question, any answer other than
+// yes is a no.
+func askConfirmation(reader io.Reader, writer io.Writer, question string) bool {
+	fmt.Fprintf(writer, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(reader).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// isConfirmationNeeded - irreversible operations are confirmed unless
+// '--yes' is given, or no terminal is available to answer so scripts
+// keep working.
+func isConfirmationNeeded(ctx *cli.Context) bool {
+	if ctx.Bool("yes") {
+		return false
+	}
+	return terminal.IsTerminal(int(os.Stdin.Fd())) && terminal.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// confirmOrExit - exit unless the user confirms an irreversible operation.
+func confirmOrExit(question string) {
+	if !askConfirmation(os.Stdin, os.Stderr, question) {
+		fatalIf(errDummy().Trace(), "Operation aborted, nothing was changed.")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAskConfirmation(t *testing.T) {
+	testCases := []struct {
+		answer    string
+		confirmed bool
+	}{
+		{"y\n", true},
+		{"yes\n", true},
+		{" YES \n", true},
+		{"Y", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"yep\n", false},
+	}
+	for i, testCase := range testCases {
+		var prompt bytes.Buffer
+		confirmed := askConfirmation(strings.NewReader(testCase.answer), &prompt, "Remove `s3/bucket`?")
+		if confirmed != testCase.confirmed {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.confirmed, confirmed)
+		}
+		if prompt.String() != "Remove `s3/bucket`? [y/N]: " {
+			t.Errorf("Test %d: unexpected prompt %q", i+1, prompt.String())
+		}
+	}
+}
+
+func TestCountObjects(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-confirm-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	for _, name := range []string{"a", "dir/b", "dir/sub/c"} {
+		path := filepath.Join(root, name)
+		if e = os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte(name), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	clnt, err := fsNew(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := countObjects(clnt, false); count != 3 {
+		t.Errorf("expected 3 objects, got %d", count)
+	}
+
+	testCases := []struct {
+		count    int
+		expected string
+	}{
+		{0, "0 objects"},
+		{1, "1 object"},
+		{42, "42 objects"},
+		{confirmCountLimit, "10000 objects"},
+		{confirmCountLimit + 1, "more than 10000 objects"},
+	}
+	for i, testCase := range testCases {
+		if description := describeObjectCount(testCase.count); description != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, description)
+		}
+	}
+}
+
+// endlessListClient - a client listing objects until its listing is
+// cancelled, sending them unconditionally like the other clients.
+type endlessListClient struct {
+	Client
+	doneCh chan struct{}
+}
+
+func (c *endlessListClient) List(ctx context.Context, isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent {
+	contentCh := make(chan *clientContent)
+	go func() {
+		defer close(c.doneCh)
+		defer close(contentCh)
+		for ctx.Err() == nil {
+			contentCh <- &clientContent{Type: os.FileMode(0664)}
+		}
+	}()
+	return contentCh
+}
+
+func TestCountObjectsStopsListing(t *testing.T) {
+	clnt := &endlessListClient{doneCh: make(chan struct{})}
+	if count := countObjects(clnt, false); count != confirmCountLimit+1 {
+		t.Errorf("expected %d objects, got %d", confirmCountLimit+1, count)
+	}
+	select {
+	case <-clnt.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the listing to stop once counted")
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/ioutils"
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/net/http/httpguts"
+)
+
+// contentHeaderFlags set the content headers of uploaded objects, which
+// are error-prone with --attr as their values often have commas and '='
+// like 'public, max-age=3600'.
+var contentHeaderFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "cache-control",
+		Usage: "set the Cache-Control header of uploaded objects, e.g. 'public, max-age=3600'",
+	},
+	cli.StringFlag{
+		Name:  "expires",
+		Usage: "set the Expires header of uploaded objects to DURATION from now or to TIMESTAMP",
+	},
+	cli.StringFlag{
+		Name:  "content-encoding",
+		Usage: "set the Content-Encoding header of uploaded objects, e.g. 'gzip'",
+	},
+}
+
+// parseExpires - parse the point in time of --expires, either a duration
+// like `30d` from now, a timestamp like `2020-01-01T00:00` or an HTTP
+// date, as an HTTP date.
+func parseExpires(value string) (string, *probe.Error) {
+	if at, e := http.ParseTime(value); e == nil {
+		return at.UTC().Format(http.TimeFormat), nil
+	}
+	for _, layout := range rewindLayouts {
+		if at, e := time.ParseInLocation(layout, value, time.Local); e == nil {
+			return at.UTC().Format(http.TimeFormat), nil
+		}
+	}
+	d, e := ioutils.ParseDurationTime(value)
+	if e != nil || d < 0 {
+		return "", errInvalidArgument().Trace(value)
+	}
+	return UTCNow().Add(d).Format(http.TimeFormat), nil
+}
+
+// parseContentHeaders - the content headers set by the flags of ctx, a
+// duration of --expires is from the time the command starts.
+func parseContentHeaders(ctx *cli.Context) map[string]string {
+	headers := make(map[string]string)
+	for _, header := range []struct{ flag, name string }{
+		{"cache-control", "Cache-Control"},
+		{"content-encoding", "Content-Encoding"},
+	} {
+		value := ctx.String(header.flag)
+		if value == "" {
+			continue
+		}
+		if !httpguts.ValidHeaderFieldValue(value) {
+			fatalIf(errInvalidArgument().Trace(value), "Unable to parse --"+header.flag+"=`"+value+"`.")
+		}
+		headers[header.name] = value
+	}
+	if value := ctx.String("expires"); value != "" {
+		expires, err := parseExpires(value)
+		fatalIf(err, "Unable to parse --expires=`"+value+"`, expected a duration like `30d` or a timestamp.")
+		headers["Expires"] = expires
+	}
+	return headers
+}
@@ -0,0 +1,130 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestParseExpires(t *testing.T) {
+	now := UTCNow()
+	testCases := []struct {
+		value    string
+		expected time.Time
+		success  bool
+	}{
+		{"Tue, 31 Dec 2019 23:59:00 GMT", time.Date(2019, 12, 31, 23, 59, 0, 0, time.UTC), true},
+		{"2019-12-31T23:59:00Z", time.Date(2019, 12, 31, 23, 59, 0, 0, time.UTC), true},
+		{"2019-12-31T23:59:00+02:00", time.Date(2019, 12, 31, 21, 59, 0, 0, time.UTC), true},
+		{"2019-12-31", time.Date(2019, 12, 31, 0, 0, 0, 0, time.Local), true},
+		{"30d", now.Add(30 * 24 * time.Hour), true},
+		{"1d12h", now.Add(36 * time.Hour), true},
+		{"tomorrow", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for i, testCase := range testCases {
+		expires, err := parseExpires(testCase.value)
+		if !testCase.success {
+			if err == nil {
+				t.Errorf("Test %d: expected `%s` to fail, got `%s`", i+1, testCase.value, expires)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %s", i+1, err)
+		}
+		at, e := http.ParseTime(expires)
+		if e != nil {
+			t.Fatalf("Test %d: expected an HTTP date, got `%s`", i+1, expires)
+		}
+		// Durations are from the time parsed, within seconds of now.
+		if d := at.Sub(testCase.expected); d < -time.Second || d > 5*time.Second {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected.UTC().Format(http.TimeFormat), expires)
+		}
+	}
+}
+
+// putHeadersHandler - fake S3 server recording the headers of uploads.
+type putHeadersHandler struct {
+	mutex  sync.Mutex
+	header http.Header
+}
+
+func (h *putHeadersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	switch {
+	case r.Method == "GET" && len(r.URL.Query()["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "PUT" && r.URL.Path == "/bucket/index.html.gz":
+		h.header = r.Header
+		w.Header().Set("ETag", "\"d41d8cd98f00b204e9800998ecf8427e\"")
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func TestPutContentHeaders(t *testing.T) {
+	h := &putHeadersHandler{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		config := newConfigV9()
+		config.Hosts["fake"] = hostConfigV9{
+			URL: server.URL, AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", API: "S3v4", Lookup: "path",
+		}
+		return config, nil
+	}
+
+	headers := map[string]string{
+		"Cache-Control":    "public, max-age=3600",
+		"Content-Encoding": "gzip",
+		"Expires":          "Tue, 31 Dec 2019 23:59:00 GMT",
+	}
+	metadata := map[string]string{"Content-Type": "text/html"}
+	for k, v := range headers {
+		metadata[k] = v
+	}
+	content := "<html></html>"
+	if _, err := putTargetStream(context.Background(), "fake", server.URL+"/bucket/index.html.gz", strings.NewReader(content),
+		int64(len(content)), metadata, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range headers {
+		if found := h.header.Get(k); found != v {
+			t.Errorf("expected %s `%s` on the upload, found `%s`", k, v, found)
+		}
+	}
+	// Content headers are not user metadata.
+	for k := range h.header {
+		if strings.HasPrefix(k, "X-Amz-Meta-") {
+			t.Errorf("expected no user metadata, found %s", k)
+		}
+	}
+}
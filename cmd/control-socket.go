@@ -0,0 +1,302 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// controlSocketFlag is shared by cp and mirror.
+var controlSocketFlag = cli.StringFlag{
+	Name:  "control-socket",
+	Usage: "serve JSON-RPC progress, pause, resume and cancel requests on a Unix socket",
+}
+
+// Maximum number of errors kept for the Errors call.
+const controlMaxErrors = 100
+
+// ControlArgs - arguments of all control calls, none so far.
+type ControlArgs struct{}
+
+// ControlProgress - reply of the Progress call.
+type ControlProgress struct {
+	Status      string        `json:"status"`
+	Paused      bool          `json:"paused"`
+	Transferred int64         `json:"transferred"`
+	Total       int64         `json:"total"`
+	Objects     int64         `json:"objects"`
+	Failed      int64         `json:"failed"`
+	Elapsed     time.Duration `json:"elapsed"`
+	Speed       float64       `json:"speed"`
+}
+
+// ControlError - a failed transfer reported by the Errors call.
+type ControlError struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source,omitempty"`
+	Target  string    `json:"target,omitempty"`
+	Message string    `json:"message"`
+}
+
+// transferControl - state of a running cp or mirror, served to GUIs and
+// wrappers on the --control-socket. All methods are no-ops on a nil
+// control, so callers do not need to check if the socket was requested.
+type transferControl struct {
+	listener  net.Listener
+	startTime time.Time
+
+	transferred int64
+	total       int64
+	objects     int64
+	failed      int64
+
+	mutex   sync.Mutex
+	pauseCh chan struct{}
+	doneCh  chan struct{}
+	errors  []ControlError
+}
+
+// newTransferControl listens on the Unix socket at path, nil is returned
+// for an empty path.
+func newTransferControl(path string) (*transferControl, *probe.Error) {
+	if path == "" {
+		return nil, nil
+	}
+	// Remove a socket left behind by a previous run.
+	if fi, e := os.Lstat(path); e == nil && fi.Mode()&os.ModeSocket != 0 {
+		if conn, e := net.Dial("unix", path); e == nil {
+			conn.Close()
+			return nil, probe.NewError(os.ErrExist).Trace(path)
+		}
+		os.Remove(path)
+	}
+	listener, e := net.Listen("unix", path)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	// Only the user running mc may control it.
+	if e = os.Chmod(path, 0600); e != nil {
+		listener.Close()
+		return nil, probe.NewError(e).Trace(path)
+	}
+
+	c := &transferControl{
+		listener:  listener,
+		startTime: time.Now(),
+		doneCh:    make(chan struct{}),
+	}
+	server := rpc.NewServer()
+	if e = server.RegisterName("mc", &ControlService{control: c}); e != nil {
+		listener.Close()
+		return nil, probe.NewError(e)
+	}
+	go func() {
+		for {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+			go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+		}
+	}()
+	return c, nil
+}
+
+// Close stops serving and removes the socket.
+func (c *transferControl) Close() {
+	if c == nil {
+		return
+	}
+	c.setPaused(false)
+	c.mutex.Lock()
+	select {
+	case <-c.doneCh:
+	default:
+		close(c.doneCh)
+	}
+	c.mutex.Unlock()
+	c.listener.Close()
+}
+
+// controlReader - counts transferred bytes, blocks while paused.
+type controlReader struct {
+	io.Reader
+	control *transferControl
+}
+
+func (r controlReader) Read(p []byte) (n int, err error) {
+	r.control.wait()
+	n, err = r.Reader.Read(p)
+	atomic.AddInt64(&r.control.transferred, int64(n))
+	return n, err
+}
+
+// hook wraps the progress reader of transfers.
+func (c *transferControl) hook(progress io.Reader) io.Reader {
+	if c == nil {
+		return progress
+	}
+	return controlReader{Reader: progress, control: c}
+}
+
+// wait blocks while transfers are paused.
+func (c *transferControl) wait() {
+	c.mutex.Lock()
+	pauseCh := c.pauseCh
+	c.mutex.Unlock()
+	if pauseCh != nil {
+		select {
+		case <-pauseCh:
+		case <-c.doneCh:
+		}
+	}
+}
+
+// setPaused pauses or resumes all transfers.
+func (c *transferControl) setPaused(pause bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if pause && c.pauseCh == nil {
+		c.pauseCh = make(chan struct{})
+	} else if !pause && c.pauseCh != nil {
+		close(c.pauseCh)
+		c.pauseCh = nil
+	}
+}
+
+// setTotal sets the total number of bytes to transfer.
+func (c *transferControl) setTotal(total int64) {
+	if c == nil {
+		return
+	}
+	atomic.StoreInt64(&c.total, total)
+}
+
+// addTotal adds bytes to transfer, like objects found by watching.
+func (c *transferControl) addTotal(n int64) {
+	if c == nil {
+		return
+	}
+	atomic.AddInt64(&c.total, n)
+}
+
+// add accounts bytes which were not transferred through hook, like
+// objects already copied by a resumed session.
+func (c *transferControl) add(n int64) {
+	if c == nil {
+		return
+	}
+	atomic.AddInt64(&c.transferred, n)
+}
+
+// done records the result of a copy or removal.
+func (c *transferControl) done(urls URLs) {
+	if c == nil {
+		return
+	}
+	if urls.Error == nil {
+		atomic.AddInt64(&c.objects, 1)
+		return
+	}
+	atomic.AddInt64(&c.failed, 1)
+
+	ctrlErr := ControlError{Time: UTCNow(), Message: urls.Error.ToGoError().Error()}
+	if urls.SourceContent != nil {
+		ctrlErr.Source = urls.SourceContent.URL.String()
+	}
+	if urls.TargetContent != nil {
+		ctrlErr.Target = urls.TargetContent.URL.String()
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.errors = append(c.errors, ctrlErr)
+	if len(c.errors) > controlMaxErrors {
+		c.errors = c.errors[len(c.errors)-controlMaxErrors:]
+	}
+}
+
+// ControlService - JSON-RPC methods served on the control socket as
+// `mc.Progress`, `mc.Pause`, `mc.Resume`, `mc.Cancel` and `mc.Errors`.
+type ControlService struct {
+	control *transferControl
+}
+
+// Progress - report transferred bytes and objects.
+func (s *ControlService) Progress(args ControlArgs, reply *ControlProgress) error {
+	c := s.control
+	c.mutex.Lock()
+	paused := c.pauseCh != nil
+	c.mutex.Unlock()
+
+	elapsed := time.Since(c.startTime)
+	*reply = ControlProgress{
+		Status:      "success",
+		Paused:      paused,
+		Transferred: atomic.LoadInt64(&c.transferred),
+		Total:       atomic.LoadInt64(&c.total),
+		Objects:     atomic.LoadInt64(&c.objects),
+		Failed:      atomic.LoadInt64(&c.failed),
+		Elapsed:     elapsed,
+	}
+	if elapsed > 0 {
+		reply.Speed = float64(reply.Transferred) / elapsed.Seconds()
+	}
+	return nil
+}
+
+// Pause - pause all transfers, in-flight transfers stop reading.
+func (s *ControlService) Pause(args ControlArgs, reply *ControlProgress) error {
+	s.control.setPaused(true)
+	return s.Progress(args, reply)
+}
+
+// Resume - resume paused transfers.
+func (s *ControlService) Resume(args ControlArgs, reply *ControlProgress) error {
+	s.control.setPaused(false)
+	return s.Progress(args, reply)
+}
+
+// Cancel - stop like on an interrupt, a cp session is saved for resuming.
+func (s *ControlService) Cancel(args ControlArgs, reply *ControlProgress) error {
+	s.control.setPaused(false)
+	if e := s.Progress(args, reply); e != nil {
+		return e
+	}
+	p, e := os.FindProcess(os.Getpid())
+	if e != nil {
+		return e
+	}
+	return p.Signal(os.Interrupt)
+}
+
+// Errors - list the most recent failed transfers.
+func (s *ControlService) Errors(args ControlArgs, reply *[]ControlError) error {
+	s.control.mutex.Lock()
+	defer s.control.mutex.Unlock()
+	*reply = append([]ControlError{}, s.control.errors...)
+	return nil
+}
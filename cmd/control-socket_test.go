@@ -0,0 +1,93 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestTransferControl(c *C) {
+	dir, e := ioutil.TempDir("", "mc-control-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mc.sock")
+
+	// No socket requested.
+	control, err := newTransferControl("")
+	c.Assert(err, IsNil)
+	c.Assert(control.hook(strings.NewReader("")), NotNil)
+	control.done(URLs{})
+	control.Close()
+
+	control, err = newTransferControl(path)
+	c.Assert(err, IsNil)
+	defer control.Close()
+
+	// A second mc must not take over a live socket.
+	_, err = newTransferControl(path)
+	c.Assert(err, NotNil)
+
+	client, e := jsonrpc.Dial("unix", path)
+	c.Assert(e, IsNil)
+	defer client.Close()
+
+	control.setTotal(10)
+	var progress ControlProgress
+	c.Assert(client.Call("mc.Pause", ControlArgs{}, &progress), IsNil)
+	c.Assert(progress.Paused, Equals, true)
+	c.Assert(progress.Total, Equals, int64(10))
+
+	readDone := make(chan struct{})
+	go func() {
+		ioutil.ReadAll(control.hook(strings.NewReader("0123456789")))
+		close(readDone)
+	}()
+	select {
+	case <-readDone:
+		c.Fatal("transfer was not paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	c.Assert(client.Call("mc.Resume", ControlArgs{}, &progress), IsNil)
+	c.Assert(progress.Paused, Equals, false)
+	<-readDone
+
+	control.done(URLs{})
+	control.done(URLs{
+		SourceContent: &clientContent{URL: *newClientURL("/tmp/a")},
+		Error:         probe.NewError(errors.New("disk full")),
+	})
+	c.Assert(client.Call("mc.Progress", ControlArgs{}, &progress), IsNil)
+	c.Assert(progress.Transferred, Equals, int64(10))
+	c.Assert(progress.Objects, Equals, int64(1))
+	c.Assert(progress.Failed, Equals, int64(1))
+
+	var ctrlErrors []ControlError
+	c.Assert(client.Call("mc.Errors", ControlArgs{}, &ctrlErrors), IsNil)
+	c.Assert(len(ctrlErrors), Equals, 1)
+	c.Assert(ctrlErrors[0].Source, Equals, "/tmp/a")
+	c.Assert(ctrlErrors[0].Message, Equals, "disk full")
+}
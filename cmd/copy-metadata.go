@@ -0,0 +1,72 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// preserveMetadataFlag copies the tags of source objects along with
+// their metadata.
+var preserveMetadataFlag = cli.BoolFlag{
+	Name:  "preserve-metadata",
+	Usage: "preserve the content headers, metadata and tags of source objects on the target",
+}
+
+// Headers of source objects which are reset when a server side copy
+// replaces the metadata of the target, they are copied along with the
+// user metadata.
+var copyContentHeaders = []string{
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Type",
+	"Expires",
+	"X-Amz-Website-Redirect-Location",
+}
+
+// getSourceTags - the tags of a source object, nil for objects on other
+// storage than object storage.
+func getSourceTags(alias, urlStr string) (map[string]string, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return nil, nil
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	return s3Clnt.getObjectTags(bucket, object)
+}
+
+// setTargetTags - set the tags of a copied object, tags are dropped on
+// other storage than object storage.
+func setTargetTags(alias, urlStr string, tags map[string]string) *probe.Error {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err.Trace(alias, urlStr)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return nil
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	return s3Clnt.setObjectTags(bucket, object, tags)
+}
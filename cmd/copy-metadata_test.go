@@ -0,0 +1,134 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// copyMetadataHandler - fake S3 server of a tagged object with metadata,
+// recording the headers of server side copies and the tags set.
+type copyMetadataHandler struct {
+	mutex      sync.Mutex
+	copyHeader http.Header
+	tagging    string
+}
+
+func (h *copyMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && r.URL.Path == "/bucket/":
+		w.Write([]byte("<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated></ListBucketResult>"))
+	case r.Method == "HEAD" && r.URL.Path == "/bucket/report.csv":
+		w.Header().Set("Content-Length", "12")
+		w.Header().Set("Last-Modified", UTCNow().Format(http.TimeFormat))
+		w.Header().Set("ETag", "9af2f8218b150c351ad802c6f3d66abe")
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Expires", "Tue, 31 Dec 2019 23:59:00 GMT")
+		w.Header().Set("X-Amz-Meta-Owner", "finance")
+		w.Header().Set("X-Amz-Tagging-Count", "1")
+	case r.Method == "GET" && len(query["tagging"]) > 0 && r.URL.Path == "/bucket/report.csv":
+		w.Write([]byte("<Tagging><TagSet><Tag><Key>project</Key><Value>apollo</Value></Tag></TagSet></Tagging>"))
+	case r.Method == "PUT" && len(query["tagging"]) > 0 && r.URL.Path == "/bucket/copy.csv":
+		body, _ := ioutil.ReadAll(r.Body)
+		h.tagging = string(body)
+	case r.Method == "PUT" && r.URL.Path == "/bucket/copy.csv" && r.Header.Get("X-Amz-Copy-Source") != "":
+		h.copyHeader = r.Header
+		w.Write([]byte("<CopyObjectResult><ETag>9af2f8218b150c351ad802c6f3d66abe</ETag></CopyObjectResult>"))
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func TestCopyPreserveMetadata(t *testing.T) {
+	h := &copyMetadataHandler{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	savedLoadMcConfig, savedCacheCfgV9 := loadMcConfig, cacheCfgV9
+	defer func() { loadMcConfig, cacheCfgV9 = savedLoadMcConfig, savedCacheCfgV9 }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		config := newConfigV9()
+		config.Hosts["fake"] = hostConfigV9{
+			URL: server.URL, AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", API: "S3v4", Lookup: "path",
+		}
+		return config, nil
+	}
+	configDir, e := ioutil.TempDir("", "mc-copy-metadata-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(configDir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(configDir)
+
+	urls := URLs{
+		SourceAlias:   "fake",
+		SourceContent: &clientContent{URL: *newClientURL(server.URL + "/bucket/report.csv"), Size: 12},
+		TargetAlias:   "fake",
+		TargetContent: &clientContent{
+			URL:      *newClientURL(server.URL + "/bucket/copy.csv"),
+			Metadata: map[string]string{amzACL: "bucket-owner-full-control"},
+		},
+		PreserveMetadata: true,
+	}
+	if urls = uploadSourceToTargetURL(context.Background(), urls, nil, nil); urls.Error != nil {
+		t.Fatal(urls.Error)
+	}
+
+	// Metadata replaced by the ACL keeps the content headers.
+	for header, value := range map[string]string{
+		"X-Amz-Acl":                "bucket-owner-full-control",
+		"X-Amz-Metadata-Directive": "REPLACE",
+		"X-Amz-Meta-Owner":         "finance",
+		"Content-Type":             "text/csv",
+		"Cache-Control":            "no-cache",
+		"Expires":                  "Tue, 31 Dec 2019 23:59:00 GMT",
+	} {
+		if found := h.copyHeader.Get(header); found != value {
+			t.Errorf("expected %s `%s` on the copy, found `%s`", header, value, found)
+		}
+	}
+	if !strings.Contains(h.tagging, "<Tag><Key>project</Key><Value>apollo</Value></Tag>") {
+		t.Errorf("expected the tags of the source on the copy, found `%s`", h.tagging)
+	}
+
+	// Tags are only copied with their metadata.
+	h.tagging = ""
+	urls.PreserveMetadata = false
+	if urls = uploadSourceToTargetURL(context.Background(), urls, nil, nil); urls.Error != nil {
+		t.Fatal(urls.Error)
+	}
+	if h.tagging != "" {
+		t.Errorf("expected no tags set, found `%s`", h.tagging)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// cp flags skipping objects depending on the target.
+var copyConditionFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "no-clobber, if-not-exists",
+		Usage: "skip objects which already exist on the target",
+	},
+	cli.BoolFlag{
+		Name:  "if-size-differ",
+		Usage: "skip objects which exist on the target with the same size",
+	},
+	cli.BoolFlag{
+		Name:  "if-newer",
+		Usage: "skip objects which exist on the target and are not older than the source",
+	},
+}
+
+// copyCondition - conditions on the target for an object to be copied,
+// the zero value copies all objects. With both --if-size-differ and
+// --if-newer an object is copied when either condition holds, like
+// mirror compares objects.
+type copyCondition struct {
+	noClobber    bool
+	ifSizeDiffer bool
+	ifNewer      bool
+}
+
+// newCopyCondition - the conditions of the flags of a session.
+func newCopyCondition(boolFlags map[string]bool) copyCondition {
+	return copyCondition{
+		noClobber:    boolFlags["no-clobber"],
+		ifSizeDiffer: boolFlags["if-size-differ"],
+		ifNewer:      boolFlags["if-newer"],
+	}
+}
+
+// checkCopyCondition - --no-clobber never overwrites, it contradicts the
+// conditions to overwrite.
+func checkCopyCondition(ctx *cli.Context) {
+	if ctx.Bool("no-clobber") && (ctx.Bool("if-size-differ") || ctx.Bool("if-newer")) {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "`--no-clobber` cannot be combined with `--if-size-differ` or `--if-newer`.")
+	}
+}
+
+// skip - whether the copy of cpURLs is skipped, the target is only
+// stat'ed when a condition is set.
+func (c copyCondition) skip(cpURLs URLs, encKeyDB map[string][]prefixSSEPair) (bool, *probe.Error) {
+	if !c.noClobber && !c.ifSizeDiffer && !c.ifNewer {
+		return false, nil
+	}
+	targetPath := filepath.ToSlash(filepath.Join(cpURLs.TargetAlias, cpURLs.TargetContent.URL.Path))
+	clnt, err := newClientFromAlias(cpURLs.TargetAlias, cpURLs.TargetContent.URL.String())
+	if err != nil {
+		return false, err.Trace(targetPath)
+	}
+	st, err := clnt.Stat(globalContext, false, false, getSSE(targetPath, encKeyDB[cpURLs.TargetAlias]))
+	if err != nil {
+		if errorExitStatus(err) == globalNotFoundExitStatus {
+			return false, nil
+		}
+		return false, err.Trace(targetPath)
+	}
+	// A folder in the way fails the copy as without conditions.
+	if st.Type.IsDir() {
+		return false, nil
+	}
+	switch {
+	case c.noClobber:
+		return true, nil
+	case c.ifSizeDiffer && st.Size != cpURLs.SourceContent.Size:
+		return false, nil
+	case c.ifNewer && cpURLs.SourceContent.Time.After(st.Time):
+		return false, nil
+	}
+	return true, nil
+}
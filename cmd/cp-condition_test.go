@@ -0,0 +1,85 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestCopyConditionSkip(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-cp-condition-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(root, "config"))
+	mcCfg := newMcConfig()
+	loadMcConfig = func() (*configV9, *probe.Error) { return mcCfg, nil }
+
+	target := filepath.Join(root, "target.txt")
+	if e = ioutil.WriteFile(target, []byte("target"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	targetTime := time.Now().Add(-time.Hour)
+	if e = os.Chtimes(target, targetTime, targetTime); e != nil {
+		t.Fatal(e)
+	}
+
+	testCases := []struct {
+		condition copyCondition
+		target    string
+		size      int64
+		modTime   time.Time
+		skip      bool
+	}{
+		// Without conditions all objects are copied.
+		{copyCondition{}, target, 6, targetTime, false},
+		{copyCondition{noClobber: true}, target, 10, targetTime.Add(time.Minute), true},
+		{copyCondition{noClobber: true}, filepath.Join(root, "missing.txt"), 6, targetTime, false},
+		{copyCondition{ifSizeDiffer: true}, target, 6, targetTime.Add(time.Minute), true},
+		{copyCondition{ifSizeDiffer: true}, target, 7, targetTime, false},
+		{copyCondition{ifNewer: true}, target, 7, targetTime, true},
+		{copyCondition{ifNewer: true}, target, 6, targetTime.Add(time.Minute), false},
+		{copyCondition{ifSizeDiffer: true, ifNewer: true}, target, 6, targetTime.Add(-time.Minute), true},
+		{copyCondition{ifSizeDiffer: true, ifNewer: true}, target, 7, targetTime.Add(-time.Minute), false},
+		{copyCondition{ifSizeDiffer: true, ifNewer: true}, target, 6, targetTime.Add(time.Minute), false},
+		// Folders in the way are left to the copy to fail.
+		{copyCondition{noClobber: true}, root, 6, targetTime, false},
+	}
+	for i, testCase := range testCases {
+		cpURLs := URLs{
+			SourceContent: &clientContent{Size: testCase.size, Time: testCase.modTime},
+			TargetContent: &clientContent{URL: *newClientURL(testCase.target)},
+		}
+		skip, err := testCase.condition.skip(cpURLs, nil)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if skip != testCase.skip {
+			t.Errorf("Test %d: expected skip %v, got %v", i+1, testCase.skip, skip)
+		}
+	}
+}
@@ -18,16 +18,20 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/console"
@@ -41,6 +45,14 @@ var (
 			Name:  "recursive, r",
 			Usage: "copy recursively",
 		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print the planned copies with their size, without copying",
+		},
+		cli.StringFlag{
+			Name:  "files-from",
+			Usage: "copy the sources listed in FILE, or on stdin with '-', one per line or NUL delimited",
+		},
 		cli.StringFlag{
 			Name:  "older-than",
 			Usage: "copy objects older than L days, M hours and N minutes",
@@ -61,6 +73,20 @@ var (
 			Name:  "attr",
 			Usage: "add custom metadata for the object",
 		},
+		preserveMetadataFlag,
+		aclFlag,
+		transformsFlag,
+		transformFlag,
+		rewindFlag,
+		errorLogFlag,
+		maxErrorsFlag,
+		sanitizeFlag,
+		renameFlag,
+		interactiveFlag,
+		parallelFlag,
+		adaptiveFlag,
+		profileFlag,
+		controlSocketFlag,
 	}
 )
 
@@ -72,8 +98,8 @@ var cpCmd = cli.Command{
 	Name:   "cp",
 	Usage:  "copy objects",
 	Action: mainCopy,
-	Before: setGlobalsFromContext,
-	Flags:  append(append(cpFlags, ioFlags...), globalFlags...),
+	Before: setGlobalsFromProfile,
+	Flags:  append(append(append(append(append(cpFlags, contentHeaderFlags...), copyConditionFlags...), ioFlags...), uploadFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -123,6 +149,61 @@ EXAMPLES:
 
 	12. Copy a text file to an object storage and assign REDUCED_REDUNDANCY storage-class to the uploaded object.
 			$ {{.HelpName}} --storage-class REDUCED_REDUNDANCY myobject.txt play/mybucket
+
+  13. Restore GPG encrypted backups from Amazon S3 cloud storage, decrypting each object on the fly.
+      $ {{.HelpName}} --recursive --transform-exec 'gpg --batch --decrypt' s3/backups/2019/ /mnt/restore/
+
+  14. Copy a folder recursively while a supervisor queries progress, pauses or cancels it over a socket.
+      $ {{.HelpName}} --recursive --control-socket /tmp/mc-cp.sock backup/ play/archive/
+
+  15. Restore a folder of a versioned bucket as it was on the first of June 2019 at 10:00.
+      $ {{.HelpName}} --recursive --rewind 2019-06-01T10:00 play/mybucket/docs/ /mnt/restore/docs/
+
+  16. Print the objects a recursive copy would transfer, and where to, without copying anything.
+      $ {{.HelpName}} --recursive --dry-run --newer-than 7d play/mybucket/ s3/backup/
+
+  17. Copy the objects found by 'mc find', names with spaces or newlines included, to a folder.
+      $ mc find s3/logs --name "*.gz" --print0 | {{.HelpName}} --files-from - /mnt/archive/
+
+  18. Copy a folder recursively logging the objects which failed, then retry only those to their targets.
+      $ {{.HelpName}} --recursive --error-log errors.log backup/ play/archive/
+      $ {{.HelpName}} --files-from errors.log --error-log errors-retry.log
+
+  19. Download a bucket written by untrusted clients, escaping names such as '..' instead of skipping them.
+      $ {{.HelpName}} --recursive --sanitize escape s3/uploads/ /srv/uploads/
+
+  20. Upload a backup to a gateway accepting only parts of 64MiB, objects of 64MiB or more are uploaded in parts.
+      $ {{.HelpName}} --part-size 64MiB --multipart-threshold 64MiB backup.tar gw/backups/
+
+  21. Copy a folder recursively over a network of unknown capacity, tuning the number of parallel copies while copying.
+      $ {{.HelpName}} --recursive --adaptive backup/ play/archive/
+
+  22. Download compressed logs recursively, decompressing each object on the fly without a local gunzip.
+      $ {{.HelpName}} --recursive --transform gunzip s3/logs/2019/ /mnt/logs/
+
+  23. Copy a folder recursively, skipping the objects which already exist on the target.
+      $ {{.HelpName}} --recursive --no-clobber backup/ play/archive/
+
+  24. Copy a folder recursively, overwriting only the objects of another size or older than the source.
+      $ {{.HelpName}} --recursive --if-size-differ --if-newer backup/ play/archive/
+
+  25. Copy a folder recursively with the parallelism, storage class and encryption key of the profile 'backup' of the config.
+      $ {{.HelpName}} --recursive --profile backup backup/ s3/backups/
+
+  26. Copy a folder recursively to another bucket, keeping the tags of the objects.
+      $ {{.HelpName}} --recursive --preserve-metadata s3/mybucket/reports/ s3/archive/reports/
+
+  27. Copy a folder recursively to a bucket of another account, granting its owner full control of the objects.
+      $ {{.HelpName}} --recursive --acl bucket-owner-full-control s3/mybucket/exports/ s3/partner-bucket/
+
+  28. Copy the folder 'raw' recursively to another bucket as the folder 'processed'.
+      $ {{.HelpName}} --recursive --rename 's/^raw\//processed\//' s3/mybucket/raw s3/archive/
+
+  29. Pick the objects of a folder to copy in a fuzzy finder.
+      $ {{.HelpName}} --recursive --interactive s3/mybucket/reports/ /tmp/reports/
+
+  30. Publish a static website, letting browsers and CDNs cache its gzipped assets for an hour.
+      $ {{.HelpName}} --recursive --cache-control 'public, max-age=3600' --content-encoding gzip site/assets/ s3/www/assets/
  `,
 }
 
@@ -150,6 +231,51 @@ func (c copyMessage) JSON() string {
 	return string(copyMessageBytes)
 }
 
+// copyPlanMessage container for a copy planned by --dry-run
+type copyPlanMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+}
+
+// String colorized copy plan message
+func (c copyPlanMessage) String() string {
+	return console.Colorize("Copy", fmt.Sprintf("`%s` -> `%s`", c.Source, c.Target)) +
+		console.Colorize("Size", fmt.Sprintf(" (%s)", humanize.IBytes(uint64(c.Size))))
+}
+
+// JSON jsonified copy plan message
+func (c copyPlanMessage) JSON() string {
+	c.Status = "success"
+	copyMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(copyMessageBytes)
+}
+
+// copyPlanSummaryMessage container for the totals of --dry-run
+type copyPlanSummaryMessage struct {
+	Status     string `json:"status"`
+	TotalCount int64  `json:"totalCount"`
+	TotalSize  int64  `json:"totalSize"`
+}
+
+// String colorized copy plan summary message
+func (c copyPlanSummaryMessage) String() string {
+	return fmt.Sprintf("Dry run, %d object(s) of %s would be copied, nothing was transferred.",
+		c.TotalCount, humanize.IBytes(uint64(c.TotalSize)))
+}
+
+// JSON jsonified copy plan summary message
+func (c copyPlanSummaryMessage) JSON() string {
+	c.Status = "success"
+	summaryMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryMessageBytes)
+}
+
 // Progress - an interface which describes current amount
 // of data written.
 type Progress interface {
@@ -164,7 +290,7 @@ type ProgressReader interface {
 }
 
 // doCopy - Copy a singe file from source to destination
-func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[string][]prefixSSEPair) URLs {
+func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, control *transferControl, encKeyDB map[string][]prefixSSEPair) URLs {
 	if cpURLs.Error != nil {
 		cpURLs.Error = cpURLs.Error.Trace()
 		return cpURLs
@@ -176,9 +302,19 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 	targetURL := cpURLs.TargetContent.URL
 	length := cpURLs.SourceContent.Size
 
+	// Objects skipped by --no-clobber, --if-size-differ or --if-newer
+	// count as copied.
+	skip, err := cpURLs.Condition.skip(cpURLs, encKeyDB)
+	if err != nil {
+		return cpURLs.WithError(err.Trace(sourceURL.String()))
+	}
+	if skip {
+		return doCopyFake(cpURLs, pg, control)
+	}
+
 	if progressReader, ok := pg.(*progressBar); ok {
 		progressReader.SetCaption(cpURLs.SourceContent.URL.String() + ": ")
-	} else {
+	} else if !globalNoProgress {
 		sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 		targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
 		printMsg(copyMessage{
@@ -189,18 +325,54 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 			TotalSize:  cpURLs.TotalSize,
 		})
 	}
-	return uploadSourceToTargetURL(ctx, cpURLs, pg, encKeyDB)
+	return uploadSourceToTargetURL(ctx, cpURLs, control.hook(pg), encKeyDB)
 }
 
 // doCopyFake - Perform a fake copy to update the progress bar appropriately.
-func doCopyFake(cpURLs URLs, pg Progress) URLs {
+func doCopyFake(cpURLs URLs, pg Progress, control *transferControl) URLs {
 	if progressReader, ok := pg.(*progressBar); ok {
 		progressReader.ProgressBar.Add64(cpURLs.SourceContent.Size)
 	}
+	control.add(cpURLs.SourceContent.Size)
+	cpURLs.Skipped = true
 	return cpURLs
 }
 
 // doPrepareCopyURLs scans the source URL and prepares a list of objects for copying.
+// pickCopyURLs - let the user pick the sources to copy once all URLs are
+// prepared, URLs which failed to be prepared are passed on.
+func pickCopyURLs(session *sessionV8, URLsCh <-chan URLs, olderThan, newerThan string) <-chan URLs {
+	pickedCh := make(chan URLs)
+	go func() {
+		defer close(pickedCh)
+		var candidates []URLs
+		var items []string
+		for cpURLs := range URLsCh {
+			if cpURLs.Error != nil {
+				pickedCh <- cpURLs
+				continue
+			}
+			if olderThan != "" && isOlder(cpURLs.SourceContent.Time, olderThan) {
+				continue
+			}
+			if newerThan != "" && isNewer(cpURLs.SourceContent.Time, newerThan) {
+				continue
+			}
+			candidates = append(candidates, cpURLs)
+			items = append(items, filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, cpURLs.SourceContent.URL.Path)))
+		}
+		picked, ok := pickObjects("Select objects to copy", items)
+		if !ok {
+			session.Delete()
+			fatalIf(errDummy().Trace(), "Operation aborted, nothing was changed.")
+		}
+		for _, i := range picked {
+			pickedCh <- candidates[i]
+		}
+	}()
+	return pickedCh
+}
+
 func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool, cancelCopy context.CancelFunc) {
 	// Separate source and target. 'cp' can take only one target,
 	// but any number of sources.
@@ -227,7 +399,15 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool, cancelCopy contex
 	if !globalQuiet && !globalJSON { // set up progress bar
 		scanBar = scanBarFactory()
 	}
-	URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive, encKeyDB)
+	var URLsCh <-chan URLs
+	if session.Header.CommandBoolFlags["retry"] {
+		URLsCh = prepareRetryURLs(session.Header.CommandArgs, encKeyDB)
+	} else {
+		URLsCh = prepareCopyURLs(globalContext, sourceURLs, targetURL, isRecursive, encKeyDB)
+	}
+	if session.Header.CommandBoolFlags["interactive"] {
+		URLsCh = pickCopyURLs(session, URLsCh, olderThan, newerThan)
+	}
 	done := false
 	for !done {
 		select {
@@ -243,6 +423,8 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool, cancelCopy contex
 				}
 				if strings.Contains(cpURLs.Error.ToGoError().Error(), " is a folder.") {
 					errorIf(cpURLs.Error.Trace(), "Folder cannot be copied. Please use `...` suffix.")
+				} else if _, ok := cpURLs.Error.ToGoError().(UnsafeObjectName); ok {
+					errorIf(cpURLs.Error.Trace(), "Skipping object with an unsafe name.")
 				} else {
 					errorIf(cpURLs.Error.Trace(), "Unable to prepare URL for copying.")
 				}
@@ -279,7 +461,7 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool, cancelCopy contex
 				console.Eraseline()
 			}
 			session.Delete() // If we are interrupted during the URL scanning, we drop the session.
-			os.Exit(0)
+			os.Exit(globalInterruptExitStatus)
 		}
 	}
 	session.Header.TotalBytes = totalBytes
@@ -287,14 +469,88 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool, cancelCopy contex
 	session.Save()
 }
 
-func doCopySession(session *sessionV8, encKeyDB map[string][]prefixSSEPair) error {
+// doCopyDryRun prints the copies prepared in the session, nothing is
+// copied.
+func doCopyDryRun(session *sessionV8) error {
+	if !globalQuiet && !globalJSON {
+		console.Eraseline()
+	}
+	encKeyDB, err := parseAndValidateEncryptionKeys(session.Header.CommandStringFlags["encrypt-key"], session.Header.CommandStringFlags["encrypt"])
+	fatalIf(err, "Unable to parse encryption keys.")
+	condition := newCopyCondition(session.Header.CommandBoolFlags)
+	summary := copyPlanSummaryMessage{}
+	urlScanner := bufio.NewScanner(session.NewDataReader())
+	for urlScanner.Scan() {
+		var cpURLs URLs
+		if e := json.Unmarshal([]byte(urlScanner.Text()), &cpURLs); e != nil {
+			errorIf(probe.NewError(e), "Unable to unmarshal %s", urlScanner.Text())
+			continue
+		}
+		// Objects the conditions skip are not planned.
+		skip, err := condition.skip(cpURLs, encKeyDB)
+		if err != nil {
+			errorIf(err, "Unable to check the target of `%s`.", cpURLs.SourceContent.URL.String())
+			continue
+		}
+		if skip {
+			continue
+		}
+		summary.TotalCount++
+		summary.TotalSize += cpURLs.SourceContent.Size
+		printMsg(copyPlanMessage{
+			Source: filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, cpURLs.SourceContent.URL.Path)),
+			Target: filepath.ToSlash(filepath.Join(cpURLs.TargetAlias, cpURLs.TargetContent.URL.Path)),
+			Size:   cpURLs.SourceContent.Size,
+		})
+	}
+	if e := urlScanner.Err(); e != nil {
+		errorIf(probe.NewError(e), "Unable to read the planned copies.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	printMsg(summary)
+	return nil
+}
+
+// waitCancelledCopies - stop queueing copies and wait for the cancelled
+// copies in progress to return, so their incomplete uploads are aborted.
+func waitCancelledCopies(quitCh chan<- struct{}, statusCh <-chan URLs, done func(URLs)) {
+	go func() { quitCh <- struct{}{} }()
+	timeout := time.After(cancelGracePeriod)
+	for {
+		select {
+		case cpURLs, ok := <-statusCh:
+			if !ok {
+				return
+			}
+			done(cpURLs)
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// doCopySession copies all objects of the session, sources are removed
+// once copied when isMvCmd is set.
+func doCopySession(session *sessionV8, encKeyDB map[string][]prefixSSEPair, isMvCmd bool) error {
 	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM, syscall.SIGKILL)
 
-	ctx, cancelCopy := context.WithCancel(context.Background())
+	ctx, cancelCopy := context.WithCancel(globalContext)
 	defer cancelCopy()
-	if !session.HasData() {
+	isResumed := session.HasData()
+	if !isResumed {
 		doPrepareCopyURLs(session, trapCh, cancelCopy)
 	}
+	if session.Header.CommandBoolFlags["dry-run"] {
+		return doCopyDryRun(session)
+	}
+
+	// A resumed session appends to its error log.
+	errLog, err := newErrorLog(session.Header.CommandStringFlags["error-log"], isResumed)
+	if err != nil {
+		session.Delete()
+		fatalIf(err, "Unable to create the error log.")
+	}
+	defer errLog.Close()
 
 	// Prepare URL scanner from session data file.
 	urlScanner := bufio.NewScanner(session.NewDataReader())
@@ -302,6 +558,13 @@ func doCopySession(session *sessionV8, encKeyDB map[string][]prefixSSEPair) erro
 	// or not. This is useful when we resume from a session.
 	isCopied := isLastFactory(session.Header.LastCopied)
 
+	// Multipart uploads interrupted by a restart are resumed.
+	uploads, err := loadSessionUploads(session.SessionID)
+	if err != nil {
+		session.Delete()
+		fatalIf(err, "Unable to load the multipart uploads of the session.")
+	}
+
 	// Store a progress bar or an accounter
 	var pg ProgressReader
 
@@ -312,10 +575,27 @@ func doCopySession(session *sessionV8, encKeyDB map[string][]prefixSSEPair) erro
 		pg = newAccounter(session.Header.TotalBytes)
 	}
 
+	control, err := newTransferControl(session.Header.CommandStringFlags["control-socket"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err, "Unable to listen on the control socket.")
+	}
+	defer control.Close()
+	control.setTotal(session.Header.TotalBytes)
+
 	var quitCh = make(chan struct{})
 	var statusCh = make(chan URLs)
 
-	parallel, queueCh := newParallelManager(statusCh)
+	var parallel *ParallelManager
+	var queueCh chan func() URLs
+	if session.Header.CommandBoolFlags["adaptive"] {
+		parallel, queueCh = newAdaptiveParallelManager(statusCh, pg)
+	} else {
+		parallel, queueCh = newParallelManagerWithWorkers(statusCh, session.Header.CommandIntFlags["parallel"])
+	}
+	if progressReader, ok := pg.(*progressBar); ok {
+		progressReader.SetWorkers(parallel.workers)
+	}
 
 	go func() {
 		gracefulStop := func() {
@@ -356,6 +636,16 @@ func doCopySession(session *sessionV8, encKeyDB map[string][]prefixSSEPair) erro
 				// Save totalSize.
 				cpURLs.TotalSize = session.Header.TotalBytes
 
+				// Transforms and filter program each object is piped
+				// through, if any.
+				if transforms := session.Header.CommandStringFlags["transform"]; transforms != "" {
+					cpURLs.Transforms = strings.Split(transforms, "\n")
+				}
+				cpURLs.TransformExec = session.Header.CommandStringFlags["transform-exec"]
+
+				// Conditions on the target for the object to be copied.
+				cpURLs.Condition = newCopyCondition(session.Header.CommandBoolFlags)
+
 				// Check and handle storage class if passed in command line args
 				if _, ok := session.Header.CommandStringFlags["storage-class"]; ok {
 					if cpURLs.TargetContent.Metadata == nil {
@@ -364,6 +654,16 @@ func doCopySession(session *sessionV8, encKeyDB map[string][]prefixSSEPair) erro
 					cpURLs.TargetContent.Metadata["X-Amz-Storage-Class"] = session.Header.CommandStringFlags["storage-class"]
 				}
 
+				// Canned ACL of the target and tags of the source.
+				if acl := session.Header.CommandStringFlags["acl"]; acl != "" {
+					if cpURLs.TargetContent.Metadata == nil {
+						cpURLs.TargetContent.Metadata = make(map[string]string)
+					}
+					cpURLs.TargetContent.Metadata[amzACL] = acl
+				}
+				cpURLs.PreserveMetadata = session.Header.CommandBoolFlags["preserve-metadata"]
+				cpURLs.Uploads = uploads
+
 				//	metaMap, metaSet := session.Header.UserMetaData
 
 				// Check and handle metadata if passed in command line args
@@ -380,51 +680,92 @@ func doCopySession(session *sessionV8, encKeyDB map[string][]prefixSSEPair) erro
 				// Verify if previously copied, notify progress bar.
 				if isCopied(cpURLs.SourceContent.URL.String()) {
 					queueCh <- func() URLs {
-						return doCopyFake(cpURLs, pg)
+						return doCopyFake(cpURLs, pg, control)
 					}
 				} else {
 					queueCh <- func() URLs {
-						return doCopy(ctx, cpURLs, pg, encKeyDB)
+						if isMvCmd {
+							return doMove(ctx, cpURLs, pg, control, encKeyDB)
+						}
+						return doCopy(ctx, cpURLs, pg, control, encKeyDB)
 					}
 				}
 			}
 		}
 	}()
 
-	var retErr error
+	fs := &failureStatus{}
+	if maxErrors, ok := session.Header.CommandIntFlags["max-errors"]; ok {
+		fs.setMaxErrors(maxErrors)
+	}
+	summary := newTransferSummary()
+	// Local folders of moved files, which may be left empty.
+	movedDirs := make(map[string]bool)
 
 loop:
 	for {
 		select {
 		case <-trapCh:
-			quitCh <- struct{}{}
 			cancelCopy()
 			// Receive interrupt notification.
-			if !globalQuiet && !globalJSON {
-				console.Eraseline()
+			if progressReader, ok := pg.(*progressBar); ok {
+				progressReader.Erase()
 			}
-			session.CloseAndDie()
+			// Copies completed while stopping are saved as well.
+			waitCancelledCopies(quitCh, statusCh, func(cpURLs URLs) {
+				if cpURLs.Error != nil {
+					return
+				}
+				control.done(cpURLs)
+				summary.done(cpURLs)
+				session.Header.LastCopied = cpURLs.SourceContent.URL.String()
+				session.Save()
+			})
+			msg := summary.message(session.Header.TotalBytes)
+			msg.Interrupted = true
+			printSummaryMsg(msg)
+			session.CloseAndInterrupt()
 		case cpURLs, ok := <-statusCh:
 			// Status channel is closed, we should return.
 			if !ok {
 				break loop
 			}
+			// Copies cancelled by an interrupt are not failures, the
+			// interrupt is handled once trapped.
+			if cpURLs.Error != nil && isInterrupted() {
+				continue loop
+			}
+			control.done(cpURLs)
+			summary.done(cpURLs)
 			if cpURLs.Error == nil {
 				session.Header.LastCopied = cpURLs.SourceContent.URL.String()
 				session.Save()
+				fs.success()
+				if isMvCmd && cpURLs.SourceContent.URL.Type == fileSystem {
+					movedDirs[filepath.Dir(cpURLs.SourceContent.URL.Path)] = true
+				}
 			} else {
 
 				// Set exit status for any copy error
-				retErr = exitStatus(globalErrorExitStatus)
+				fs.fail(cpURLs.Error)
+				errLog.log(cpURLs)
 
 				// Print in new line and adjust to top so that we
 				// don't print over the ongoing progress bar.
-				if !globalQuiet && !globalJSON {
-					console.Eraseline()
+				if progressReader, ok := pg.(*progressBar); ok {
+					progressReader.Erase()
+				}
+				errMsg := "Failed to copy `%s`."
+				if isMvCmd {
+					errMsg = "Failed to move `%s`."
 				}
 				errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
-					fmt.Sprintf("Failed to copy `%s`.", cpURLs.SourceContent.URL.String()))
-				if isErrIgnored(cpURLs.Error) {
+					fmt.Sprintf(errMsg, cpURLs.SourceContent.URL.String()))
+				if err := fs.exceeded(); err != nil {
+					errorIf(err.Trace(), "Too many failed objects.")
+					session.CloseAndDie()
+				}
+				if isErrIgnored(cpURLs.Error) || fs.tolerated() {
 					continue loop
 				}
 				// For critical errors we should exit. Session
@@ -435,17 +776,21 @@ loop:
 		}
 	}
 
+	if isMvCmd && session.Header.CommandBoolFlags["recursive"] {
+		sourceURLs := session.Header.CommandArgs[:len(session.Header.CommandArgs)-1]
+		removeEmptySourceDirs(sourceURLs, movedDirs)
+	}
+
 	if progressReader, ok := pg.(*progressBar); ok {
 		if progressReader.ProgressBar.Get() > 0 {
-			progressReader.ProgressBar.Finish()
-		}
-	} else {
-		if accntReader, ok := pg.(*accounter); ok {
-			printMsg(accntReader.Stat())
+			progressReader.Finish()
 		}
+	} else if accntReader, ok := pg.(*accounter); ok {
+		accntReader.Stat()
 	}
+	printSummaryMsg(summary.message(session.Header.TotalBytes))
 
-	return retErr
+	return fs.exitError()
 }
 
 // validate the passed metadataString and populate the map
@@ -462,6 +807,48 @@ func getMetaDataEntry(metadataString string) (map[string]string, *probe.Error) {
 	return metaDataMap, nil
 }
 
+// parseFileList - split a list of paths delimited by newlines, or by NUL
+// characters when the list has any, empty entries are skipped.
+func parseFileList(reader io.Reader) ([]string, error) {
+	data, e := ioutil.ReadAll(reader)
+	if e != nil {
+		return nil, e
+	}
+	delimiter := "\n"
+	if bytes.IndexByte(data, 0) >= 0 {
+		delimiter = "\x00"
+	}
+	var paths []string
+	for _, path := range strings.Split(string(data), delimiter) {
+		if delimiter == "\n" {
+			path = strings.TrimSuffix(path, "\r")
+		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// readFilesFrom - read the sources of --files-from, '-' reads them from
+// stdin.
+func readFilesFrom(name string) ([]string, *probe.Error) {
+	reader := io.Reader(os.Stdin)
+	if name != "-" {
+		f, e := os.Open(name)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		defer f.Close()
+		reader = f
+	}
+	paths, e := parseFileList(reader)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return paths, nil
+}
+
 // mainCopy is the entry point for cp command.
 func mainCopy(ctx *cli.Context) error {
 	// Parse encryption keys per command.
@@ -474,9 +861,65 @@ func mainCopy(ctx *cli.Context) error {
 		userMetaMap, err = getMetaDataEntry(ctx.String("attr"))
 		fatalIf(err, "Unable to parse attribute %v", ctx.String("attr"))
 	}
+	// Content headers of their flags override those of --attr.
+	for k, v := range parseContentHeaders(ctx) {
+		userMetaMap[k] = v
+	}
 
-	// check 'copy' cli arguments.
-	checkCopySyntax(ctx, encKeyDB)
+	// Check the built-in transforms before anything is copied.
+	checkTransforms(ctx.StringSlice("transform"))
+	checkCopyCondition(ctx)
+
+	// Sources listed by --files-from are copied along with the sources
+	// given as arguments. Without arguments, the objects of an error log
+	// are retried, each to the target it failed to be copied to, and
+	// URLs holds pairs of a source and its target.
+	URLs := []string(ctx.Args())
+	isRetry := false
+	if filesFrom := ctx.String("files-from"); filesFrom != "" {
+		sourceURLs, err := readFilesFrom(filesFrom)
+		fatalIf(err.Trace(filesFrom), "Unable to read the sources listed in `"+filesFrom+"`.")
+		switch {
+		case len(sourceURLs) == 0 && len(URLs) <= 1:
+			fatalIf(errInvalidArgument().Trace(filesFrom), "No sources listed in `"+filesFrom+"`.")
+		case len(URLs) == 0:
+			entries, ok := parseErrorLog(sourceURLs)
+			if !ok {
+				fatalIf(errInvalidArgument().Trace(filesFrom), "No target given and `"+filesFrom+"` is not an error log to retry.")
+			}
+			for _, entry := range entries {
+				URLs = append(URLs, entry.Source, entry.Target)
+			}
+			isRetry = true
+		default:
+			URLs = append(append(sourceURLs, URLs[:len(URLs)-1]...), URLs[len(URLs)-1])
+		}
+	}
+
+	var rewindAt time.Time
+	var targetURLs []string
+	if isRetry {
+		if ctx.String("rewind") != "" {
+			fatalIf(errInvalidArgument().Trace(ctx.String("rewind")), "Unable to rewind the objects of an error log, they are retried as they are now.")
+		}
+		for i := 0; i < len(URLs); i += 2 {
+			checkCopySyntax(ctx, URLs[i:i+2], encKeyDB)
+			targetURLs = append(targetURLs, URLs[i+1])
+		}
+	} else {
+		// Sources are stat'ed as they were at the point in time of --rewind.
+		if len(URLs) >= 2 {
+			rewindAt = rewindSources(ctx.String("rewind"), URLs[:len(URLs)-1]...)
+		}
+
+		// check 'copy' cli arguments.
+		checkCopySyntax(ctx, URLs, encKeyDB)
+		targetURLs = URLs[len(URLs)-1:]
+	}
+	if !ctx.Bool("dry-run") {
+		checkWritableTargets(targetURLs...)
+	}
+	maxErrors := checkMaxErrors(ctx)
 
 	// Additional command speific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
@@ -494,11 +937,32 @@ func mainCopy(ctx *cli.Context) error {
 	session := newSessionV8()
 	session.Header.CommandType = "cp"
 	session.Header.CommandBoolFlags["recursive"] = recursive
+	session.Header.CommandBoolFlags["dry-run"] = ctx.Bool("dry-run")
+	session.Header.CommandBoolFlags["retry"] = isRetry
+	session.Header.CommandBoolFlags["adaptive"] = ctx.Bool("adaptive")
+	session.Header.CommandBoolFlags["no-clobber"] = ctx.Bool("no-clobber")
+	session.Header.CommandBoolFlags["if-size-differ"] = ctx.Bool("if-size-differ")
+	session.Header.CommandBoolFlags["if-newer"] = ctx.Bool("if-newer")
+	session.Header.CommandBoolFlags["preserve-metadata"] = ctx.Bool("preserve-metadata")
+	session.Header.CommandBoolFlags["interactive"] = ctx.Bool("interactive")
+	session.Header.CommandIntFlags["parallel"] = ctx.Int("parallel")
 	session.Header.CommandStringFlags["older-than"] = olderThan
 	session.Header.CommandStringFlags["newer-than"] = newerThan
 	session.Header.CommandStringFlags["storage-class"] = storageClass
+	session.Header.CommandStringFlags["acl"] = ctx.String("acl")
 	session.Header.CommandStringFlags["encrypt-key"] = sseKeys
 	session.Header.CommandStringFlags["encrypt"] = sse
+	session.Header.CommandStringFlags["transform"] = strings.Join(ctx.StringSlice("transform"), "\n")
+	session.Header.CommandStringFlags["transform-exec"] = ctx.String("transform-exec")
+	session.Header.CommandStringFlags["control-socket"] = ctx.String("control-socket")
+	session.Header.CommandStringFlags["error-log"] = ctx.String("error-log")
+	if maxErrors >= 0 {
+		session.Header.CommandIntFlags["max-errors"] = maxErrors
+	}
+	if !rewindAt.IsZero() {
+		// A resumed session is rewound to the same point in time.
+		session.Header.CommandStringFlags["rewind"] = rewindAt.Format(time.RFC3339Nano)
+	}
 	session.Header.UserMetaData = userMetaMap
 
 	var e error
@@ -508,8 +972,8 @@ func mainCopy(ctx *cli.Context) error {
 	}
 
 	// extract URLs.
-	session.Header.CommandArgs = ctx.Args()
-	e = doCopySession(session, encKeyDB)
+	session.Header.CommandArgs = URLs
+	e = doCopySession(session, encKeyDB, false)
 	session.Delete()
 
 	return e
@@ -17,7 +17,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -59,3 +61,41 @@ func TestParseMetaData(t *testing.T) {
 		}
 	}
 }
+
+func TestCopyPlanMessage(t *testing.T) {
+	var plan copyPlanMessage
+	msg := copyPlanMessage{Source: "play/bucket/a.txt", Target: "s3/backup/a.txt", Size: 2048}
+	if e := json.Unmarshal([]byte(msg.JSON()), &plan); e != nil {
+		t.Fatal(e)
+	}
+	msg.Status = "success"
+	if plan != msg {
+		t.Errorf("expected %v, got %v", msg, plan)
+	}
+
+	summary := copyPlanSummaryMessage{TotalCount: 3, TotalSize: 3 << 20}
+	if s := summary.String(); s != "Dry run, 3 object(s) of 3.0 MiB would be copied, nothing was transferred." {
+		t.Errorf("unexpected summary %q", s)
+	}
+}
+
+func TestParseFileList(t *testing.T) {
+	testCases := []struct {
+		list     string
+		expected []string
+	}{
+		{"", nil},
+		{"a.txt\n", []string{"a.txt"}},
+		{"a.txt\r\ndir/b c.txt\n\n", []string{"a.txt", "dir/b c.txt"}},
+		{"s3/bucket/a.txt\x00s3/bucket/new\nline.txt\x00", []string{"s3/bucket/a.txt", "s3/bucket/new\nline.txt"}},
+	}
+	for i, testCase := range testCases {
+		paths, e := parseFileList(strings.NewReader(testCase.list))
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %s", i+1, e)
+		}
+		if !reflect.DeepEqual(paths, testCase.expected) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, paths)
+		}
+	}
+}
@@ -23,24 +23,23 @@ import (
 	"github.com/minio/mc/pkg/console"
 )
 
-func checkCopySyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
-	if len(ctx.Args()) < 2 {
-		cli.ShowCommandHelpAndExit(ctx, "cp", 1) // last argument is exit code.
-	}
-
-	// extract URLs.
-	URLs := ctx.Args()
+func checkCopySyntax(ctx *cli.Context, URLs []string, encKeyDB map[string][]prefixSSEPair) {
 	if len(URLs) < 2 {
-		fatalIf(errDummy().Trace(ctx.Args()...), fmt.Sprintf("Unable to parse source and target arguments."))
+		cli.ShowCommandHelpAndExit(ctx, "cp", 1) // last argument is exit code.
 	}
 
 	srcURLs := URLs[:len(URLs)-1]
 	tgtURL := URLs[len(URLs)-1]
 	isRecursive := ctx.Bool("recursive")
+	setSanitizeFromContext(ctx)
+	setRenameFromContext(ctx)
+	checkParallelFlag(ctx)
+	checkACLFlag(ctx)
+	checkInteractiveFlag(ctx)
 
 	// Verify if source(s) exists.
 	for _, srcURL := range srcURLs {
-		_, _, err := url2Stat(srcURL, false, encKeyDB)
+		_, _, err := url2Stat(globalContext, srcURL, false, encKeyDB)
 		if err != nil {
 			console.Fatalf("Unable to validate source %s\n", srcURL)
 		}
@@ -55,7 +54,7 @@ func checkCopySyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	}
 
 	// Guess CopyURLsType based on source and target URLs.
-	copyURLsType, err := guessCopyURLType(srcURLs, tgtURL, isRecursive, encKeyDB)
+	copyURLsType, err := guessCopyURLType(globalContext, srcURLs, tgtURL, isRecursive, encKeyDB)
 	if err != nil {
 		fatalIf(errInvalidArgument().Trace(), "Unable to guess the type of copy operation.")
 	}
@@ -81,7 +80,7 @@ func checkCopySyntaxTypeA(srcURLs []string, tgtURL string, keys map[string][]pre
 		fatalIf(errInvalidArgument().Trace(), "Invalid number of source arguments.")
 	}
 	srcURL := srcURLs[0]
-	_, srcContent, err := url2Stat(srcURL, false, keys)
+	_, srcContent, err := url2Stat(globalContext, srcURL, false, keys)
 	fatalIf(err.Trace(srcURL), "Unable to stat source `"+srcURL+"`.")
 
 	if !srcContent.Type.IsRegular() {
@@ -96,7 +95,7 @@ func checkCopySyntaxTypeB(srcURLs []string, tgtURL string, keys map[string][]pre
 		fatalIf(errInvalidArgument().Trace(), "Invalid number of source arguments.")
 	}
 	srcURL := srcURLs[0]
-	_, srcContent, err := url2Stat(srcURL, false, keys)
+	_, srcContent, err := url2Stat(globalContext, srcURL, false, keys)
 	fatalIf(err.Trace(srcURL), "Unable to stat source `"+srcURL+"`.")
 
 	if !srcContent.Type.IsRegular() {
@@ -104,7 +103,7 @@ func checkCopySyntaxTypeB(srcURLs []string, tgtURL string, keys map[string][]pre
 	}
 
 	// Check target.
-	if _, tgtContent, err := url2Stat(tgtURL, false, keys); err == nil {
+	if _, tgtContent, err := url2Stat(globalContext, tgtURL, false, keys); err == nil {
 		if !tgtContent.Type.IsDir() {
 			fatalIf(errInvalidArgument().Trace(tgtURL), "Target `"+tgtURL+"` is not a folder.")
 		}
@@ -119,14 +118,14 @@ func checkCopySyntaxTypeC(srcURLs []string, tgtURL string, isRecursive bool, key
 	}
 
 	// Check target.
-	if _, tgtContent, err := url2Stat(tgtURL, false, keys); err == nil {
+	if _, tgtContent, err := url2Stat(globalContext, tgtURL, false, keys); err == nil {
 		if !tgtContent.Type.IsDir() {
 			fatalIf(errInvalidArgument().Trace(tgtURL), "Target `"+tgtURL+"` is not a folder.")
 		}
 	}
 
 	for _, srcURL := range srcURLs {
-		c, srcContent, err := url2Stat(srcURL, false, keys)
+		c, srcContent, err := url2Stat(globalContext, srcURL, false, keys)
 		// incomplete uploads are not necessary for copy operation, no need to verify for them.
 		isIncomplete := false
 		if err != nil {
@@ -156,7 +155,7 @@ func checkCopySyntaxTypeC(srcURLs []string, tgtURL string, isRecursive bool, key
 func checkCopySyntaxTypeD(srcURLs []string, tgtURL string, keys map[string][]prefixSSEPair) {
 	// Source can be anything: file, dir, dir...
 	// Check target if it is a dir
-	if _, tgtContent, err := url2Stat(tgtURL, false, keys); err == nil {
+	if _, tgtContent, err := url2Stat(globalContext, tgtURL, false, keys); err == nil {
 		if !tgtContent.Type.IsDir() {
 			fatalIf(errInvalidArgument().Trace(tgtURL), "Target `"+tgtURL+"` is not a folder.")
 		}
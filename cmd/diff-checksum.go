@@ -0,0 +1,199 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Parts of multipart uploads are usually a multiple of 1 MiB.
+const checksumPartAlign = 1024 * 1024
+
+// parseETag - returns the MD5 sum of a single part upload, or the MD5 of
+// the part MD5 sums and the number of parts of a multipart upload.
+func parseETag(etag string) (sum string, parts int) {
+	etag = strings.Trim(etag, "\"")
+	if i := strings.LastIndex(etag, "-"); i >= 0 {
+		if n, e := strconv.Atoi(etag[i+1:]); e == nil && n > 0 {
+			return etag[:i], n
+		}
+	}
+	return etag, 0
+}
+
+// multipartPartSize - guess the part size of an object of size bytes
+// uploaded in parts, all but the last part are of the same size.
+func multipartPartSize(size int64, parts int) int64 {
+	partSize := (size + int64(parts) - 1) / int64(parts)
+	aligned := (partSize + checksumPartAlign - 1) / checksumPartAlign * checksumPartAlign
+	if (size+aligned-1)/aligned == int64(parts) {
+		return aligned
+	}
+	return partSize
+}
+
+// computeETag - ETag of the content of reader as if uploaded in parts of
+// partSize bytes, or in a single part if partSize is 0.
+func computeETag(reader io.Reader, partSize int64) (string, error) {
+	if partSize <= 0 {
+		h := md5.New()
+		if _, e := io.Copy(h, reader); e != nil {
+			return "", e
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	var sums []byte
+	var parts int
+	for {
+		h := md5.New()
+		n, e := io.CopyN(h, reader, partSize)
+		if n > 0 {
+			sums = append(sums, h.Sum(nil)...)
+			parts++
+		}
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return "", e
+		}
+	}
+	sum := md5.Sum(sums)
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), parts), nil
+}
+
+// checksumDiffer - compares the content of objects which match in name,
+// size and time. ETags are compared as is when both are known and of the
+// same kind, otherwise the content is read to compute an ETag like the
+// one of the other object. Without any ETag both are read to compare
+// their sums by hash, SHA-256 if not set.
+type checksumDiffer struct {
+	firstAlias  string
+	secondAlias string
+	hash        checksumAlgorithm
+}
+
+// contentETag - ETag of the content at URL, computed like the given
+// ETag of another object, which may be empty.
+func (c checksumDiffer) contentETag(alias string, content *clientContent, like string) (string, *probe.Error) {
+	urlStr := content.URL.String()
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return "", err.Trace(alias, urlStr)
+	}
+	reader, err := clnt.Get(nil)
+	if err != nil {
+		return "", err.Trace(urlStr)
+	}
+	defer reader.Close()
+
+	var partSize int64
+	if _, parts := parseETag(like); parts > 0 {
+		partSize = multipartPartSize(content.Size, parts)
+	}
+	etag, e := computeETag(reader, partSize)
+	if e != nil {
+		return "", probe.NewError(e).Trace(urlStr)
+	}
+	return etag, nil
+}
+
+// differ - true if the content of the objects of diff differs.
+func (c checksumDiffer) differ(d diffMessage) (bool, *probe.Error) {
+	first, second := d.firstContent, d.secondContent
+	firstSum, firstParts := parseETag(first.ETag)
+	secondSum, secondParts := parseETag(second.ETag)
+
+	if firstSum != "" && secondSum != "" {
+		if firstSum == secondSum && firstParts == secondParts {
+			return false, nil
+		}
+		if firstParts == 0 && secondParts == 0 {
+			// Both are plain MD5 sums.
+			return true, nil
+		}
+	}
+
+	var err *probe.Error
+	switch {
+	case secondSum != "":
+		// Compute the first like the second.
+		firstSum, err = c.contentETag(c.firstAlias, first, second.ETag)
+		secondSum = strings.Trim(second.ETag, "\"")
+	case firstSum != "":
+		secondSum, err = c.contentETag(c.secondAlias, second, first.ETag)
+		firstSum = strings.Trim(first.ETag, "\"")
+	default:
+		// No ETags, like on a filesystem.
+		if firstSum, err = contentChecksum(c.firstAlias, first.URL.String(), nil, c.hash); err == nil {
+			secondSum, err = contentChecksum(c.secondAlias, second.URL.String(), nil, c.hash)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return firstSum != secondSum, nil
+}
+
+// differences - pass on the differences of diffCh, comparing the
+// checksums of the objects which do not differ otherwise by parallel
+// workers. Objects differing in checksum are passed on as differInChecksum
+// once compared, the other objects which do not differ are dropped.
+func (c checksumDiffer) differences(diffCh chan diffMessage, parallel int) chan diffMessage {
+	resultCh := make(chan diffMessage, diffBufferSize)
+	similarCh := make(chan diffMessage, parallel)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for diffMsg := range similarCh {
+				differ, err := c.differ(diffMsg)
+				if err != nil {
+					resultCh <- diffMessage{Error: err.Trace(diffMsg.FirstURL, diffMsg.SecondURL)}
+					continue
+				}
+				if differ {
+					diffMsg.Diff = differInChecksum
+					resultCh <- diffMsg
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(resultCh)
+		for diffMsg := range diffCh {
+			if diffMsg.Error == nil && diffMsg.Diff == differInNone {
+				similarCh <- diffMsg
+				continue
+			}
+			resultCh <- diffMsg
+		}
+		close(similarCh)
+		wg.Wait()
+	}()
+	return resultCh
+}
@@ -0,0 +1,121 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestComputeETag(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 5*checksumPartAlign+10)
+	sum := md5.Sum(data)
+
+	first := md5.Sum(data[:3*checksumPartAlign])
+	second := md5.Sum(data[3*checksumPartAlign:])
+	sums := md5.Sum(append(first[:], second[:]...))
+	multipart := fmt.Sprintf("%s-2", hex.EncodeToString(sums[:]))
+
+	testCases := []struct {
+		partSize int64
+		expected string
+	}{
+		{0, hex.EncodeToString(sum[:])},
+		{3 * checksumPartAlign, multipart},
+	}
+	for i, testCase := range testCases {
+		etag, e := computeETag(bytes.NewReader(data), testCase.partSize)
+		if e != nil {
+			t.Fatalf("Test %d: %s", i+1, e)
+		}
+		if etag != testCase.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expected, etag)
+		}
+	}
+
+	// The guessed part size of the upload gives the same ETag.
+	sum2, parts := parseETag(`"` + multipart + `"`)
+	if sum2 != hex.EncodeToString(sums[:]) || parts != 2 {
+		t.Errorf("Unable to parse %s, got %s and %d", multipart, sum2, parts)
+	}
+	if partSize := multipartPartSize(int64(len(data)), parts); partSize != 3*checksumPartAlign {
+		t.Errorf("Expected part size %d, got %d", 3*checksumPartAlign, partSize)
+	}
+}
+
+func TestChecksumDiffer(t *testing.T) {
+	content := func(etag string) *clientContent {
+		return &clientContent{ETag: etag, Size: 10}
+	}
+	testCases := []struct {
+		firstETag  string
+		secondETag string
+		differ     bool
+	}{
+		{`"d41d8cd98f00b204e9800998ecf8427e"`, "d41d8cd98f00b204e9800998ecf8427e", false},
+		{"d41d8cd98f00b204e9800998ecf8427e", "9e107d9d372bb6826bd81d3542a419d6", true},
+		{"9e107d9d372bb6826bd81d3542a419d6-2", "9e107d9d372bb6826bd81d3542a419d6-2", false},
+	}
+	for i, testCase := range testCases {
+		differ, err := checksumDiffer{}.differ(diffMessage{
+			firstContent:  content(testCase.firstETag),
+			secondContent: content(testCase.secondETag),
+		})
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if differ != testCase.differ {
+			t.Errorf("Test %d: Expected differ %t, got %t", i+1, testCase.differ, differ)
+		}
+	}
+}
+
+func TestChecksumDifferences(t *testing.T) {
+	similar := func(name, firstETag, secondETag string) diffMessage {
+		return diffMessage{
+			FirstURL:      name,
+			Diff:          differInNone,
+			firstContent:  &clientContent{ETag: firstETag, Size: 10},
+			secondContent: &clientContent{ETag: secondETag, Size: 10},
+		}
+	}
+	diffCh := make(chan diffMessage)
+	go func() {
+		defer close(diffCh)
+		for i := 0; i < 100; i++ {
+			diffCh <- similar("same", "d41d8cd98f00b204e9800998ecf8427e", "d41d8cd98f00b204e9800998ecf8427e")
+			diffCh <- similar("changed", "d41d8cd98f00b204e9800998ecf8427e", "9e107d9d372bb6826bd81d3542a419d6")
+			diffCh <- diffMessage{FirstURL: "missing", Diff: differInFirst}
+		}
+	}()
+
+	counts := make(map[string]int)
+	for diffMsg := range (checksumDiffer{}).differences(diffCh, 8) {
+		if diffMsg.Error != nil {
+			t.Fatal(diffMsg.Error)
+		}
+		counts[diffMsg.FirstURL+" "+diffMsg.Diff.String()]++
+	}
+	expected := map[string]int{"changed checksum": 100, "missing only-in-first": 100}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
@@ -27,9 +28,32 @@ import (
 	"github.com/minio/mc/pkg/probe"
 )
 
+// Objects compared by checksum in parallel by default.
+const defaultDiffParallel = 4
+
 // diff specific flags.
 var (
-	diffFlags = []cli.Flag{}
+	diffFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "only",
+			Usage: "only list differences of comma separated kinds: missing, extra, newer, size, checksum",
+		},
+		cli.BoolFlag{
+			Name:  "exit-code",
+			Usage: "exit with status 1 if any difference is found",
+		},
+		cli.BoolFlag{
+			Name:  "checksum",
+			Usage: "compare content checksums of objects matching in name, size and time",
+		},
+		cli.IntFlag{
+			Name:  "parallel",
+			Value: defaultDiffParallel,
+			Usage: "number of objects compared by checksum in parallel, with --checksum",
+		},
+		hashFlag,
+		normalizeFlag,
+	}
 )
 
 // Compute differences in object name, size, and date between two buckets.
@@ -50,19 +74,62 @@ FLAGS:
   {{end}}
 DESCRIPTION:
   Diff only calculates differences in object name, size and time.
-  It *DOES NOT* compare objects' contents.
+  It *DOES NOT* compare objects' contents, unless --checksum is set.
+
+  With --checksum objects matching in name, size and time are compared by
+  their ETags, the MD5 sum of their content or of the parts of multipart
+  uploads. Content without a comparable ETag, like local files, is read
+  to compute one, by --parallel objects at a time. When neither has an
+  ETag, like two folders of local filesystems, both are read to compare
+  the checksums of --hash, SHA-256 sums by default.
+
+  Both listings are read concurrently and compared in key order as they
+  arrive. Large local folders are sorted through temporary files, and
+  listings sorted again for --rename or --windows-compat are held in
+  memory whole, otherwise memory does not grow with the number of
+  objects. Differences are printed as they are found, those in checksum
+  as their comparisons complete.
 
 LEGEND:
     < - object is only in source.
     > - object is only in destination.
     ! - newer object is in source.
 
+KINDS:
+    missing  - object is only in source, missing in destination.
+    extra    - object is only in destination.
+    newer    - newer object is in source.
+    size     - object differs in size or type, the size delta is shown.
+    checksum - object differs in content, only with --checksum.
+
+  A summary of the listed differences and their total size delta, source
+  minus destination, is printed at the end unless --quiet is set.
+
+  Keys are compared in Unicode normalization form NFC, 'é' composed on
+  Linux equals 'é' decomposed on macOS. --normalize nfd compares in NFD,
+  --normalize off compares the bytes of keys.
+
 EXAMPLES:
   1. Compare a local folder with a folder on Amazon S3 cloud storage.
      $ {{.HelpName}} ~/Photos s3/mybucket/Photos
 
   2. Compare two folders on a local filesystem.
      $ {{.HelpName}} ~/Photos /Media/Backup/Photos
+
+  3. List only objects missing in the backup or differing in size.
+     $ {{.HelpName}} --only missing,size ~/Photos s3/mybucket/Photos
+
+  4. Verify a backup in a script, failing if it differs in any way.
+     $ {{.HelpName}} --quiet --exit-code ~/Photos s3/mybucket/Photos
+
+  5. Detect objects of two mirrored buckets which diverged in content.
+     $ {{.HelpName}} --checksum --only checksum site1/mybucket site2/mybucket
+
+  6. Find keys of a bucket differing from a local folder only in their Unicode normalization.
+     $ {{.HelpName}} --normalize off --only missing ~/Music s3/mybucket/Music
+
+  7. Compare the content of two local copies of a dataset on NVMe drives by their xxh64 sums.
+     $ {{.HelpName}} --checksum --hash xxh64 /mnt/nvme0/dataset /mnt/nvme1/dataset
 `,
 }
 
@@ -72,6 +139,7 @@ type diffMessage struct {
 	FirstURL      string       `json:"first"`
 	SecondURL     string       `json:"second"`
 	Diff          differType   `json:"diff"`
+	SizeDelta     int64        `json:"sizeDelta"`
 	Error         *probe.Error `json:"error,omitempty"`
 	firstContent  *clientContent
 	secondContent *clientContent
@@ -88,9 +156,11 @@ func (d diffMessage) String() string {
 	case differInType:
 		msg = console.Colorize("DiffType", "! "+d.SecondURL)
 	case differInSize:
-		msg = console.Colorize("DiffSize", "! "+d.SecondURL)
+		msg = console.Colorize("DiffSize", "! "+d.SecondURL+" ("+formatSizeDelta(d.SizeDelta)+")")
 	case differInTime:
 		msg = console.Colorize("DiffTime", "! "+d.SecondURL)
+	case differInChecksum:
+		msg = console.Colorize("DiffChecksum", "! "+d.SecondURL+" (checksum)")
 	default:
 		fatalIf(errDummy().Trace(d.FirstURL, d.SecondURL),
 			"Unhandled difference between `"+d.FirstURL+"` and `"+d.SecondURL+"`.")
@@ -104,10 +174,106 @@ func (d diffMessage) JSON() string {
 	d.Status = "success"
 	diffJSONBytes, e := json.MarshalIndent(d, "", " ")
 	fatalIf(probe.NewError(e),
-		"Unable to marshal diff message `"+d.FirstURL+"`, `"+d.SecondURL+"` and `"+string(d.Diff)+"`.")
+		"Unable to marshal diff message `"+d.FirstURL+"`, `"+d.SecondURL+"` and `"+d.Diff.String()+"`.")
 	return string(diffJSONBytes)
 }
 
+// sizeDelta - size of the first object minus the size of the second,
+// missing objects count as empty.
+func (d diffMessage) sizeDelta() int64 {
+	var delta int64
+	if d.firstContent != nil && !d.firstContent.Type.IsDir() {
+		delta += d.firstContent.Size
+	}
+	if d.secondContent != nil && !d.secondContent.Type.IsDir() {
+		delta -= d.secondContent.Size
+	}
+	return delta
+}
+
+// formatSizeDelta - human readable size delta with its sign.
+func formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + humanize.IBytes(uint64(-delta))
+	}
+	return "+" + humanize.IBytes(uint64(delta))
+}
+
+// diffSummaryMessage container for the summary printed after all
+// differences.
+type diffSummaryMessage struct {
+	Status       string `json:"status"`
+	OnlyInFirst  int64  `json:"onlyInFirst"`
+	OnlyInSecond int64  `json:"onlyInSecond"`
+	Differing    int64  `json:"differing"`
+	SizeDelta    int64  `json:"sizeDelta"`
+}
+
+// add counts a listed difference.
+func (s *diffSummaryMessage) add(d diffMessage) {
+	switch d.Diff {
+	case differInFirst:
+		s.OnlyInFirst++
+	case differInSecond:
+		s.OnlyInSecond++
+	default:
+		s.Differing++
+	}
+	s.SizeDelta += d.SizeDelta
+}
+
+// found - true if any difference was listed.
+func (s diffSummaryMessage) found() bool {
+	return s.OnlyInFirst+s.OnlyInSecond+s.Differing > 0
+}
+
+// String colorized diff summary message
+func (s diffSummaryMessage) String() string {
+	return console.Colorize("DiffMessage", fmt.Sprintf("Total: %d only in source, %d only in destination, %d differing, size delta %s",
+		s.OnlyInFirst, s.OnlyInSecond, s.Differing, formatSizeDelta(s.SizeDelta)))
+}
+
+// JSON jsonified diff summary message
+func (s diffSummaryMessage) JSON() string {
+	s.Status = "success"
+	summaryJSONBytes, e := json.MarshalIndent(struct {
+		Status  string             `json:"status"`
+		Summary diffSummaryMessage `json:"summary"`
+	}{
+		Status:  s.Status,
+		Summary: s,
+	}, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal diff summary.")
+	return string(summaryJSONBytes)
+}
+
+// parseDiffKinds - parse the kinds of differences given to --only, nil
+// is returned to list all differences.
+func parseDiffKinds(only string) (map[differType]bool, *probe.Error) {
+	if only == "" {
+		return nil, nil
+	}
+	kinds := make(map[differType]bool)
+	for _, kind := range strings.Split(only, ",") {
+		switch strings.TrimSpace(kind) {
+		case "missing":
+			kinds[differInFirst] = true
+		case "extra":
+			kinds[differInSecond] = true
+		case "newer":
+			kinds[differInTime] = true
+		case "size":
+			kinds[differInSize] = true
+			kinds[differInType] = true
+		case "checksum":
+			kinds[differInChecksum] = true
+		default:
+			return nil, errInvalidArgument().Trace(kind)
+		}
+	}
+	return kinds, nil
+}
+
 func checkDiffSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	if len(ctx.Args()) != 2 {
 		cli.ShowCommandHelpAndExit(ctx, "diff", 1) // last argument is exit code
@@ -117,6 +283,17 @@ func checkDiffSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
 		}
 	}
+	setNormalizeFromContext(ctx)
+	if _, err := parseDiffKinds(ctx.String("only")); err != nil {
+		fatalIf(err, "Unable to parse `--only`, expected a list of missing, extra, newer, size and checksum.")
+	}
+	if ctx.Int("parallel") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("parallel")), "Unable to use `--parallel`, at least one object must be compared at a time.")
+	}
+	if ctx.IsSet("hash") && !ctx.Bool("checksum") {
+		fatalIf(errInvalidArgument().Trace(ctx.String("hash")), "`--hash` needs `--checksum`.")
+	}
+	getChecksumAlgorithm(ctx)
 	URLs := ctx.Args()
 	firstURL := URLs[0]
 	secondURL := URLs[1]
@@ -124,7 +301,7 @@ func checkDiffSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	// Diff only works between two directories, verify them below.
 
 	// Verify if firstURL is accessible.
-	_, firstContent, err := url2Stat(firstURL, false, encKeyDB)
+	_, firstContent, err := url2Stat(globalContext, firstURL, false, encKeyDB)
 	if err != nil {
 		fatalIf(err.Trace(firstURL), fmt.Sprintf("Unable to stat '%s'.", firstURL))
 	}
@@ -135,7 +312,7 @@ func checkDiffSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	}
 
 	// Verify if secondURL is accessible.
-	_, secondContent, err := url2Stat(secondURL, false, encKeyDB)
+	_, secondContent, err := url2Stat(globalContext, secondURL, false, encKeyDB)
 	if err != nil {
 		fatalIf(err.Trace(secondURL), fmt.Sprintf("Unable to stat '%s'.", secondURL))
 	}
@@ -146,8 +323,22 @@ func checkDiffSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	}
 }
 
+// diffOpts - options of a diff.
+type diffOpts struct {
+	// Only list these kinds of differences, all if nil.
+	kinds map[differType]bool
+	// Return an error if a difference is found.
+	exitCode bool
+	// Compare content checksums of similar objects.
+	checksum bool
+	// Number of objects compared by checksum in parallel.
+	parallel int
+	// Algorithm comparing content without ETags.
+	hash checksumAlgorithm
+}
+
 // doDiffMain runs the diff.
-func doDiffMain(firstURL, secondURL string) error {
+func doDiffMain(firstURL, secondURL string, opts diffOpts) error {
 	// Source and targets are always directories
 	sourceSeparator := string(newClientURL(firstURL).Separator)
 	if !strings.HasSuffix(firstURL, sourceSeparator) {
@@ -174,16 +365,34 @@ func doDiffMain(firstURL, secondURL string) error {
 			fmt.Sprintf("Failed to diff '%s' and '%s'", firstURL, secondURL))
 	}
 
+	diffCh := objectDifference(globalContext, firstClient, secondClient, firstURL, secondURL)
+	if opts.checksum {
+		checksum := checksumDiffer{firstAlias: firstAlias, secondAlias: secondAlias, hash: opts.hash}
+		diffCh = checksum.differences(similarObjectDifference(globalContext, firstClient, secondClient, firstURL, secondURL), opts.parallel)
+	}
+
 	// Diff first and second urls.
-	for diffMsg := range objectDifference(firstClient, secondClient, firstURL, secondURL) {
+	var summary diffSummaryMessage
+	for diffMsg := range diffCh {
 		if diffMsg.Error != nil {
 			errorIf(diffMsg.Error, "Unable to calculate objects difference.")
 			// Ignore error and proceed to next object.
 			continue
 		}
+		if opts.kinds != nil && !opts.kinds[diffMsg.Diff] {
+			continue
+		}
+		diffMsg.SizeDelta = diffMsg.sizeDelta()
+		summary.add(diffMsg)
 		printMsg(diffMsg)
 	}
 
+	if !globalQuiet {
+		printMsg(summary)
+	}
+	if opts.exitCode && summary.found() {
+		return exitStatus(globalErrorExitStatus)
+	}
 	return nil
 }
 
@@ -203,10 +412,18 @@ func mainDiff(ctx *cli.Context) error {
 	console.SetColor("DiffType", color.New(color.FgMagenta))
 	console.SetColor("DiffSize", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffTime", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DiffChecksum", color.New(color.FgRed, color.Bold))
 
 	URLs := ctx.Args()
 	firstURL := URLs.Get(0)
 	secondURL := URLs.Get(1)
 
-	return doDiffMain(firstURL, secondURL)
+	kinds, _ := parseDiffKinds(ctx.String("only"))
+	return doDiffMain(firstURL, secondURL, diffOpts{
+		kinds:    kinds,
+		exitCode: ctx.Bool("exit-code"),
+		checksum: ctx.Bool("checksum"),
+		parallel: ctx.Int("parallel"),
+		hash:     getChecksumAlgorithm(ctx),
+	})
 }
@@ -0,0 +1,78 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseDiffKinds(t *testing.T) {
+	testCases := []struct {
+		only       string
+		kinds      map[differType]bool
+		shouldPass bool
+	}{
+		{"", nil, true},
+		{"missing", map[differType]bool{differInFirst: true}, true},
+		{"extra, newer", map[differType]bool{differInSecond: true, differInTime: true}, true},
+		{"size", map[differType]bool{differInSize: true, differInType: true}, true},
+		{"checksum", map[differType]bool{differInChecksum: true}, true},
+		{"missing,bigger", nil, false},
+	}
+	for i, testCase := range testCases {
+		kinds, err := parseDiffKinds(testCase.only)
+		if testCase.shouldPass != (err == nil) {
+			t.Fatalf("Test %d: Expected success %t, got error %v", i+1, testCase.shouldPass, err)
+		}
+		if !reflect.DeepEqual(kinds, testCase.kinds) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.kinds, kinds)
+		}
+	}
+}
+
+func TestDiffSummary(t *testing.T) {
+	file := func(size int64) *clientContent {
+		return &clientContent{Size: size}
+	}
+	diffs := []diffMessage{
+		{Diff: differInFirst, firstContent: file(100)},
+		{Diff: differInSecond, secondContent: file(30)},
+		{Diff: differInSize, firstContent: file(10), secondContent: file(15)},
+		{Diff: differInTime, firstContent: file(10), secondContent: file(10)},
+		{Diff: differInType, firstContent: file(10), secondContent: &clientContent{Type: os.ModeDir}},
+	}
+	var summary diffSummaryMessage
+	if summary.found() {
+		t.Fatal("Expected no difference")
+	}
+	for _, d := range diffs {
+		d.SizeDelta = d.sizeDelta()
+		summary.add(d)
+	}
+	expected := diffSummaryMessage{OnlyInFirst: 1, OnlyInSecond: 1, Differing: 3, SizeDelta: 100 - 30 - 5 + 10}
+	if summary != expected {
+		t.Errorf("Expected %+v, got %+v", expected, summary)
+	}
+	if !summary.found() {
+		t.Error("Expected differences")
+	}
+	if got := formatSizeDelta(-2048); got != "-2.0 KiB" {
+		t.Errorf("Expected -2.0 KiB, got %s", got)
+	}
+}
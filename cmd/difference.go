@@ -17,24 +17,72 @@
 package cmd
 
 import (
+	"context"
 	"strings"
 	"unicode/utf8"
 
 	// golang does not support flat keys for path matching, find does
 
+	"github.com/minio/cli"
 	"golang.org/x/text/unicode/norm"
 )
 
+// Unicode normalization forms of '--normalize'.
+const (
+	normalizeNFC = "nfc"
+	normalizeNFD = "nfd"
+	normalizeOff = "off"
+)
+
+// normalizeFlag sets the Unicode normalization of compared and uploaded keys.
+var normalizeFlag = cli.StringFlag{
+	Name:  "normalize",
+	Usage: "Unicode normalization of keys for comparison and upload. Valid options are '[nfc, nfd, off]'",
+}
+
+// setNormalizeFromContext - validate and set the normalization form of
+// '--normalize'.
+func setNormalizeFromContext(ctx *cli.Context) {
+	form := strings.ToLower(ctx.String("normalize"))
+	switch form {
+	case "", normalizeNFC, normalizeNFD, normalizeOff:
+		globalNormalize = form
+	default:
+		fatalIf(errInvalidArgument().Trace(form), "Unrecognized normalization `"+form+"`. Valid options are `[nfc, nfd, off]`.")
+	}
+}
+
+// normalizeKey - the form keys are compared in, NFC unless set otherwise.
+func normalizeKey(key string) string {
+	switch globalNormalize {
+	case normalizeOff:
+		return key
+	case normalizeNFD:
+		return norm.NFD.String(key)
+	}
+	return norm.NFC.String(key)
+}
+
+// normalizeUploadKey - the key an object is copied to, normalized only
+// when '--normalize' sets a form.
+func normalizeUploadKey(key string) string {
+	if globalNormalize == normalizeNFC || globalNormalize == normalizeNFD {
+		return normalizeKey(key)
+	}
+	return key
+}
+
 // differType difference in type.
 type differType int
 
 const (
-	differInNone   differType = iota // does not differ
-	differInSize                     // differs in size
-	differInTime                     // differs in time
-	differInType                     // differs in type, exfile/directory
-	differInFirst                    // only in source (FIRST)
-	differInSecond                   // only in target (SECOND)
+	differInNone     differType = iota // does not differ
+	differInSize                       // differs in size
+	differInTime                       // differs in time
+	differInType                       // differs in type, exfile/directory
+	differInFirst                      // only in source (FIRST)
+	differInSecond                     // only in target (SECOND)
+	differInChecksum                   // differs in content checksum
 )
 
 func (d differType) String() string {
@@ -51,21 +99,54 @@ func (d differType) String() string {
 		return "only-in-first"
 	case differInSecond:
 		return "only-in-second"
+	case differInChecksum:
+		return "checksum"
 	}
 	return "unknown"
 }
 
-func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string) (diffCh chan diffMessage) {
-	return difference(sourceClnt, targetClnt, sourceURL, targetURL, true, false, DirNone)
+func objectDifference(ctx context.Context, sourceClnt, targetClnt Client, sourceURL, targetURL string) (diffCh chan diffMessage) {
+	return difference(ctx, sourceClnt, targetClnt, sourceURL, targetURL, true, false, DirNone)
+}
+
+// similarObjectDifference - same as objectDifference, also returning
+// objects which do not differ for further comparison.
+func similarObjectDifference(ctx context.Context, sourceClnt, targetClnt Client, sourceURL, targetURL string) (diffCh chan diffMessage) {
+	return difference(ctx, sourceClnt, targetClnt, sourceURL, targetURL, true, true, DirNone)
+}
+
+func dirDifference(ctx context.Context, sourceClnt, targetClnt Client, sourceURL, targetURL string) (diffCh chan diffMessage) {
+	return difference(ctx, sourceClnt, targetClnt, sourceURL, targetURL, false, true, DirFirst)
 }
 
-func dirDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string) (diffCh chan diffMessage) {
-	return difference(sourceClnt, targetClnt, sourceURL, targetURL, false, true, DirFirst)
+// Differences buffered ahead of the consumer of a comparison.
+const diffBufferSize = 1000
+
+// Entries of each listing buffered ahead of the comparison, about ten
+// pages of S3 listings.
+const diffListBufferSize = 10000
+
+// prefetchListing - relay a listing through a bounded buffer, so that
+// both sides of a comparison are listed concurrently, each running ahead
+// of the comparison instead of waiting for the other side to catch up.
+func prefetchListing(ch <-chan *clientContent) <-chan *clientContent {
+	prefetchCh := make(chan *clientContent, diffListBufferSize)
+	go func() {
+		defer close(prefetchCh)
+		for content := range ch {
+			prefetchCh <- content
+		}
+	}()
+	return prefetchCh
 }
 
 // objectDifference function finds the difference between all objects
-// recursively in sorted order from source and target.
-func difference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isRecursive, returnSimilar bool, dirOpt DirOpt) (diffCh chan diffMessage) {
+// recursively in sorted order from source and target. Both listings are
+// sorted, they are listed concurrently and merge-joined through bounded
+// buffers. Large local folders are sorted in runs spilled to temporary
+// files, only listings sorted again for --rename or --windows-compat are
+// held whole.
+func difference(ctx context.Context, sourceClnt, targetClnt Client, sourceURL, targetURL string, isRecursive, returnSimilar bool, dirOpt DirOpt) (diffCh chan diffMessage) {
 	var (
 		srcEOF, tgtEOF       bool
 		srcOk, tgtOk         bool
@@ -75,10 +156,27 @@ func difference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isRe
 
 	// Set default values for listing.
 	isIncomplete := false // we will not compare any incomplete objects.
-	srcCh := sourceClnt.List(isRecursive, isIncomplete, dirOpt)
-	tgtCh := targetClnt.List(isRecursive, isIncomplete, dirOpt)
+	srcCh := prefetchListing(sourceClnt.List(ctx, isRecursive, isIncomplete, dirOpt))
+	tgtCh := prefetchListing(targetClnt.List(ctx, isRecursive, isIncomplete, dirOpt))
 
-	diffCh = make(chan diffMessage, 1000)
+	// Local names escaped by '--windows-compat' are compared by their
+	// object keys, which are not listed in order.
+	isSrcLocal, isTgtLocal := sourceClnt.GetURL().Type == fileSystem, targetClnt.GetURL().Type == fileSystem
+	isSrcEscaped := globalWindowsCompat && isSrcLocal && !isTgtLocal
+	isTgtEscaped := globalWindowsCompat && isTgtLocal && !isSrcLocal
+	// Keys renamed by '--rename' are compared by their renamed keys.
+	isSrcRenamed := len(globalRename) > 0
+	switch {
+	case isSrcRenamed:
+		srcCh = sortByRenamedKey(srcCh, sourceURL, targetURL, isSrcEscaped)
+	case isSrcEscaped:
+		srcCh = sortByWindowsKey(srcCh, sourceURL, targetURL)
+	}
+	if isTgtEscaped {
+		tgtCh = sortByWindowsKey(tgtCh, targetURL, targetURL)
+	}
+
+	diffCh = make(chan diffMessage, diffBufferSize)
 
 	go func() {
 
@@ -130,6 +228,15 @@ func difference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isRe
 
 			srcSuffix = strings.TrimPrefix(srcCtnt.URL.String(), sourceURL)
 			tgtSuffix = strings.TrimPrefix(tgtCtnt.URL.String(), targetURL)
+			if isSrcEscaped {
+				srcSuffix = decodeWindowsName(srcSuffix)
+			}
+			if isSrcRenamed {
+				srcSuffix = renameKey(srcSuffix)
+			}
+			if isTgtEscaped {
+				tgtSuffix = decodeWindowsName(tgtSuffix)
+			}
 
 			current := urlJoinPath(targetURL, srcSuffix)
 			expected := urlJoinPath(targetURL, tgtSuffix)
@@ -150,8 +257,8 @@ func difference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isRe
 			// Normalize to avoid situations where multiple byte representations are possible.
 			// e.g. 'ä' can be represented as precomposed U+00E4 (UTF-8 0xc3a4) or decomposed
 			// U+0061 U+0308 (UTF-8 0x61cc88).
-			normalizedCurrent := norm.NFC.String(current)
-			normalizedExpected := norm.NFC.String(expected)
+			normalizedCurrent := normalizeKey(current)
+			normalizedExpected := normalizeKey(expected)
 
 			if normalizedExpected > normalizedCurrent {
 				diffCh <- diffMessage{
@@ -176,6 +283,8 @@ func difference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isRe
 						firstContent:  srcCtnt,
 						secondContent: tgtCtnt,
 					}
+					srcCtnt, srcOk = <-srcCh
+					tgtCtnt, tgtOk = <-tgtCh
 					continue
 				}
 				if (srcType.IsRegular() && tgtType.IsRegular()) && srcSize != tgtSize {
@@ -196,9 +305,8 @@ func difference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isRe
 						firstContent:  srcCtnt,
 						secondContent: tgtCtnt,
 					}
-				}
-				// No differ
-				if returnSimilar {
+				} else if returnSimilar {
+					// No differ
 					diffCh <- diffMessage{
 						FirstURL:      srcCtnt.URL.String(),
 						SecondURL:     tgtCtnt.URL.String(),
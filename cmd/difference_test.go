@@ -17,7 +17,13 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/minio/mc/pkg/probe"
 )
 
 var testCases = []struct {
@@ -46,3 +52,64 @@ func TestExcludeOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestDifferenceNormalize(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-difference-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+
+	// The same name, decomposed as written on macOS and composed.
+	sourceDir, targetDir := filepath.Join(root, "source"), filepath.Join(root, "target")
+	for _, path := range []string{filepath.Join(sourceDir, "café.txt"), filepath.Join(targetDir, "café.txt")} {
+		if e = os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte("menu"), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	sourceURL, targetURL := sourceDir+string(filepath.Separator), targetDir+string(filepath.Separator)
+
+	savedNormalize := globalNormalize
+	defer func() { globalNormalize = savedNormalize }()
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) { return newConfigV9(), nil }
+
+	testCases := []struct {
+		normalize string
+		diffs     []differType
+		uploadKey string
+	}{
+		{"", []differType{differInNone}, "café.txt"},
+		{normalizeNFC, []differType{differInNone}, "café.txt"},
+		{normalizeNFD, []differType{differInNone}, "café.txt"},
+		{normalizeOff, []differType{differInFirst, differInSecond}, "café.txt"},
+	}
+	for i, testCase := range testCases {
+		globalNormalize = testCase.normalize
+		sourceClnt, err := newClient(sourceURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetClnt, err := newClient(targetURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var diffs []differType
+		for diffMsg := range similarObjectDifference(globalContext, sourceClnt, targetClnt, sourceURL, targetURL) {
+			if diffMsg.Error != nil {
+				t.Fatalf("Test %d: unexpected error %s", i+1, diffMsg.Error)
+			}
+			diffs = append(diffs, diffMsg.Diff)
+		}
+		if !reflect.DeepEqual(diffs, testCase.diffs) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.diffs, diffs)
+		}
+		if key := normalizeUploadKey("café.txt"); key != testCase.uploadKey {
+			t.Errorf("Test %d: expected upload key %q, got %q", i+1, testCase.uploadKey, key)
+		}
+	}
+}
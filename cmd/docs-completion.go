@@ -0,0 +1,98 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/cli"
+)
+
+var docsCompletionCmd = cli.Command{
+	Name:   "completion",
+	Usage:  "generate the completion script of a shell",
+	Action: mainDocsCompletion,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} SHELL
+
+SHELL:
+  One of bash, zsh, fish or powershell.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  The completion script is written to the standard output, it completes the commands,
+  flags and aliases of the mc found in PATH.
+
+EXAMPLES:
+  1. Install the completion script of bash.
+     $ {{.HelpName}} bash > /usr/share/bash-completion/completions/mc
+
+  2. Install the completion script of zsh.
+     $ {{.HelpName}} zsh > /usr/share/zsh/site-functions/_mc
+
+  3. Install the completion script of fish.
+     $ {{.HelpName}} fish > /usr/share/fish/vendor_completions.d/mc.fish
+
+  4. Load completion in the current PowerShell session.
+     PS> {{.HelpName}} powershell | Out-String | Invoke-Expression
+
+`,
+}
+
+// Completion is asked from mc by passing the command line in COMP_LINE,
+// 'mc mc' is what makes mc answer instead of running a command.
+var completionScripts = map[string]string{
+	"bash": `complete -C mc mc
+`,
+	"zsh": `autoload -U +X bashcompinit && bashcompinit
+complete -o nospace -C mc mc
+`,
+	"fish": `function __complete_mc
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    mc mc
+end
+complete -f -c mc -a "(__complete_mc)"
+`,
+	"powershell": powerShellCompletion("mc"),
+}
+
+// completionShells - the shells with a completion script in a stable order.
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// mainDocsCompletion is the handle for "mc docs completion" command.
+func mainDocsCompletion(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "completion", 1) // last argument is exit code
+	}
+	shell := strings.ToLower(ctx.Args().First())
+	script, ok := completionScripts[shell]
+	if !ok {
+		fatalIf(errInvalidArgument().Trace(shell), "Unknown shell `"+shell+"`, expected one of "+strings.Join(completionShells, ", ")+".")
+	}
+	fmt.Print(script)
+	return nil
+}
@@ -0,0 +1,39 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "github.com/minio/cli"
+
+var docsCmd = cli.Command{
+	Name:            "docs",
+	Usage:           "generate manual pages and shell completion scripts",
+	HideHelpCommand: true,
+	Action:          mainDocs,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands: []cli.Command{
+		docsManCmd,
+		docsCompletionCmd,
+	},
+}
+
+// mainDocs is the handle for "mc docs" command.
+func mainDocs(ctx *cli.Context) error {
+	cli.ShowCommandHelp(ctx, ctx.Args().First())
+	return nil
+	// Sub-commands like "man" have their own main.
+}
@@ -0,0 +1,178 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/mitchellh/go-homedir"
+)
+
+var docsManCmd = cli.Command{
+	Name:   "man",
+	Usage:  "generate the manual page of mc or of a command",
+	Action: mainDocsMan,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [COMMAND ...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  The manual page is written to the standard output in roff format, with the help of every
+  command. Without a command the page covers all of mc.
+
+EXAMPLES:
+  1. Install the manual page of mc.
+     $ {{.HelpName}} > /usr/share/man/man1/mc.1
+
+  2. Read the manual page of the admin user commands.
+     $ {{.HelpName}} admin user | man -l -
+
+`,
+}
+
+// roffEscaper escapes backslashes and dashes of roff text.
+var roffEscaper = strings.NewReplacer(`\`, `\\`, "-", `\-`)
+
+// roffEscape - escape text for roff, lines starting with a control
+// character are protected by a zero width character.
+func roffEscape(text string) string {
+	lines := strings.Split(roffEscaper.Replace(text), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commandHelp - the help of a command as printed by 'mc COMMAND --help'.
+func commandHelp(cmd cli.Command, helpName string) string {
+	cmd.HelpName = helpName
+	template := cmd.CustomHelpTemplate
+	if template == "" {
+		template = cli.CommandHelpTemplate
+	}
+	var buf bytes.Buffer
+	cli.HelpPrinterCustom(&buf, template, cmd, nil)
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// writeManCommand - write the section of a command and its subcommands.
+func writeManCommand(w io.Writer, cmd cli.Command, parent string) {
+	if cmd.Hidden {
+		return
+	}
+	helpName := parent + " " + cmd.Name
+	fmt.Fprintf(w, ".SS \"%s\"\n%s\n", roffEscape(helpName), roffEscape(cmd.Usage))
+	if len(cmd.Subcommands) > 0 {
+		fmt.Fprintln(w, ".PP\nCommands:")
+		for _, sub := range cmd.Subcommands {
+			if !sub.Hidden {
+				fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roffEscape(sub.Name), roffEscape(sub.Usage))
+			}
+		}
+		for _, sub := range cmd.Subcommands {
+			writeManCommand(w, sub, helpName)
+		}
+		return
+	}
+	if help := commandHelp(cmd, helpName); help != "" {
+		fmt.Fprintf(w, ".PP\n.RS\n.nf\n%s\n.fi\n.RE\n", roffEscape(help))
+	}
+}
+
+// writeManPage - write the manual page of the commands below the path
+// of command names given, all of the app when it is empty.
+func writeManPage(w io.Writer, app *cli.App, path []string) error {
+	cmds, helpName := app.Commands, app.Name
+	title, usage := app.Name, app.Usage
+	for i, name := range path {
+		var found *cli.Command
+		for j := range cmds {
+			if cmds[j].HasName(name) {
+				found = &cmds[j]
+				break
+			}
+		}
+		if found == nil {
+			return fmt.Errorf("`%s` is not a command of `%s`", name, helpName)
+		}
+		if len(found.Subcommands) == 0 {
+			if i < len(path)-1 {
+				return fmt.Errorf("`%s %s` has no subcommands", helpName, found.Name)
+			}
+			cmds = []cli.Command{*found}
+		} else {
+			cmds, helpName = found.Subcommands, helpName+" "+found.Name
+		}
+		title, usage = title+"-"+found.Name, found.Usage
+	}
+
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"%s %s\" \"MinIO Client\"\n", strings.ToUpper(roffEscape(title)), app.Name, roffEscape(ReleaseTag))
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", roffEscape(title), roffEscape(strings.TrimSuffix(usage, ".")))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B %s\n[FLAGS] COMMAND [COMMAND FLAGS | \\-h] [ARGUMENTS...]\n", roffEscape(app.Name))
+	fmt.Fprintln(w, ".SH GLOBAL FLAGS")
+	for _, flag := range app.VisibleFlags() {
+		names := strings.SplitN(flag.String(), "\t", 2)
+		if len(names) != 2 {
+			continue
+		}
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roffEscape(strings.TrimSpace(names[0])), roffEscape(strings.TrimSpace(names[1])))
+	}
+	fmt.Fprintln(w, ".SH COMMANDS")
+	for _, cmd := range cmds {
+		writeManCommand(w, cmd, helpName)
+	}
+	fmt.Fprintf(w, ".SH SEE ALSO\nhttps://docs.min.io/docs/minio\\-client\\-complete\\-guide\n")
+	return nil
+}
+
+// mainDocsMan is the handle for "mc docs man" command.
+func mainDocsMan(ctx *cli.Context) error {
+	// The context of a subcommand has an app of its own, the
+	// manual page is generated from the commands of the root app.
+	app := ctx.App
+	for parent := ctx.Parent(); parent != nil; parent = parent.Parent() {
+		app = parent.App
+	}
+	var buf bytes.Buffer
+	if e := writeManPage(&buf, app, ctx.Args()); e != nil {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to generate the manual page, "+e.Error()+".")
+	}
+	page := buf.String()
+	// Defaults like the config folder are in the home folder of whoever
+	// generates the page, show them relative to the home of the reader.
+	if homeDir, e := homedir.Dir(); e == nil && homeDir != "" {
+		page = strings.Replace(page, roffEscape(homeDir), "~", -1)
+	}
+	_, e := io.WriteString(os.Stdout, page)
+	fatalIf(probe.NewError(e), "Unable to write the manual page.")
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestRoffEscape(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"list buckets", "list buckets"},
+		{"--recursive, -r", `\-\-recursive, \-r`},
+		{`C:\Users\`, `C:\\Users\\`},
+		{".mc folder\n'off' prints", "\\&.mc folder\n\\&'off' prints"},
+	}
+	for i, testCase := range testCases {
+		if got := roffEscape(testCase.text); got != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestWriteManPage(t *testing.T) {
+	app := cli.NewApp()
+	app.Name = "mc"
+	app.Usage = "MinIO Client for cloud storage and filesystems."
+	app.Commands = []cli.Command{
+		{Name: "ls", Usage: "list buckets and objects", CustomHelpTemplate: "NAME:\n  {{.HelpName}} - {{.Usage}}\n"},
+		{Name: "admin", Usage: "manage MinIO servers", Subcommands: []cli.Command{
+			{Name: "info", Usage: "display MinIO server information"},
+			{Name: "secret", Usage: "not documented", Hidden: true},
+		}},
+	}
+
+	testCases := []struct {
+		path     []string
+		contains []string
+		excludes []string
+		success  bool
+	}{
+		{nil, []string{".TH MC 1", "\n.SS \"mc ls\"\n", "  mc ls \\- list buckets and objects\n", ".SS \"mc admin info\""}, []string{"secret"}, true},
+		{[]string{"admin"}, []string{".TH MC\\-ADMIN 1", "mc\\-admin \\- manage MinIO servers", ".SS \"mc admin info\""}, []string{".SS \"mc ls\""}, true},
+		{[]string{"ls"}, []string{".TH MC\\-LS 1", ".SS \"mc ls\""}, []string{"admin"}, true},
+		{[]string{"rm"}, nil, nil, false},
+		{[]string{"ls", "info"}, nil, nil, false},
+	}
+	for i, testCase := range testCases {
+		var buf bytes.Buffer
+		err := writeManPage(&buf, app, testCase.path)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		page := buf.String()
+		for _, s := range testCase.contains {
+			if !strings.Contains(page, s) {
+				t.Errorf("Test %d: expected %q in page:\n%s", i+1, s, page)
+			}
+		}
+		for _, s := range testCase.excludes {
+			if strings.Contains(page, s) {
+				t.Errorf("Test %d: unexpected %q in page:\n%s", i+1, s, page)
+			}
+		}
+	}
+}
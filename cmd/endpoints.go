@@ -0,0 +1,246 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	// Health checks of endpoints time out after endpointCheckTimeout,
+	// unreachable endpoints are checked again after endpointRecheckInterval.
+	endpointCheckTimeout    = 5 * time.Second
+	endpointRecheckInterval = 30 * time.Second
+
+	// Liveness probe of MinIO servers.
+	endpointHealthPath = "/minio/health/live"
+)
+
+// endpointState - health of an endpoint of a deployment.
+type endpointState struct {
+	host      string
+	latency   time.Duration
+	isDown    bool
+	downSince time.Time
+	checking  bool
+}
+
+// endpointPool - endpoints of a deployment, requests are sent to the
+// fastest endpoint up and fail over to the next when it is unreachable.
+type endpointPool struct {
+	scheme    string
+	endpoints []*endpointState
+	transport http.RoundTripper
+
+	once  sync.Once
+	mutex sync.Mutex
+}
+
+var (
+	// Pools by endpoints, shared by the clients of a deployment.
+	endpointPools      = map[string]*endpointPool{}
+	endpointPoolsMutex sync.Mutex
+)
+
+// checkEndpoints - endpoints of an alias are URLs of the same scheme as
+// the URL of the alias.
+func checkEndpoints(hostURL string, endpoints []string) *probe.Error {
+	scheme := newClientURL(hostURL).Scheme
+	for _, endpoint := range endpoints {
+		if !isValidHostURL(endpoint) {
+			return errInvalidURL(endpoint).Trace(hostURL)
+		}
+		if newClientURL(endpoint).Scheme != scheme {
+			return errInvalidArgument().Trace(hostURL, endpoint)
+		}
+	}
+	return nil
+}
+
+// newEndpointPool - the pool of the endpoints of a host, the host comes
+// first. Hosts without endpoints have no pool.
+func newEndpointPool(scheme, host string, endpoints []string, transport http.RoundTripper) *endpointPool {
+	hosts := []string{host}
+	for _, endpoint := range endpoints {
+		if u, e := url.Parse(endpoint); e == nil && u.Host != host {
+			hosts = append(hosts, u.Host)
+		}
+	}
+	if len(hosts) == 1 {
+		return nil
+	}
+
+	key := scheme + "://" + strings.Join(hosts, ",")
+	endpointPoolsMutex.Lock()
+	defer endpointPoolsMutex.Unlock()
+	if pool, ok := endpointPools[key]; ok {
+		return pool
+	}
+	pool := &endpointPool{scheme: scheme, transport: transport}
+	for _, host := range hosts {
+		pool.endpoints = append(pool.endpoints, &endpointState{host: host})
+	}
+	endpointPools[key] = pool
+	return pool
+}
+
+// check - probe the liveness of an endpoint and measure its latency.
+func (p *endpointPool) check(endpoint *endpointState) {
+	ctx, cancel := context.WithTimeout(context.Background(), endpointCheckTimeout)
+	defer cancel()
+	start := time.Now()
+	isUp := false
+	req, e := http.NewRequest(http.MethodGet, p.scheme+"://"+endpoint.host+endpointHealthPath, nil)
+	if e == nil {
+		var resp *http.Response
+		if resp, e = p.transport.RoundTrip(req.WithContext(ctx)); e == nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			// Servers without the probe only have to answer.
+			isUp = resp.StatusCode < http.StatusInternalServerError
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	endpoint.checking = false
+	if isUp {
+		endpoint.isDown = false
+		endpoint.latency = time.Since(start)
+	} else {
+		p.markDown(endpoint)
+	}
+}
+
+// checkAll - check all endpoints once before the first request.
+func (p *endpointPool) checkAll() {
+	var wg sync.WaitGroup
+	for _, endpoint := range p.endpoints {
+		wg.Add(1)
+		go func(endpoint *endpointState) {
+			defer wg.Done()
+			p.check(endpoint)
+		}(endpoint)
+	}
+	wg.Wait()
+}
+
+// markDown - skip an unreachable endpoint. Must be called with the
+// mutex held.
+func (p *endpointPool) markDown(endpoint *endpointState) {
+	if !endpoint.isDown {
+		endpoint.isDown = true
+		endpoint.downSince = time.Now()
+	}
+}
+
+// pick - the fastest endpoint up which was not tried yet. Endpoints down
+// for longer than the recheck interval are checked in the background.
+// When all are down, the endpoint down for the longest is tried again.
+func (p *endpointPool) pick(tried map[*endpointState]bool) *endpointState {
+	p.once.Do(p.checkAll)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var fastest, oldest *endpointState
+	for _, endpoint := range p.endpoints {
+		if tried[endpoint] {
+			continue
+		}
+		if endpoint.isDown {
+			if !endpoint.checking && time.Since(endpoint.downSince) > endpointRecheckInterval {
+				endpoint.checking = true
+				endpoint.downSince = time.Now()
+				go p.check(endpoint)
+			}
+			if oldest == nil || endpoint.downSince.Before(oldest.downSince) {
+				oldest = endpoint
+			}
+			continue
+		}
+		if fastest == nil || endpoint.latency < fastest.latency {
+			fastest = endpoint
+		}
+	}
+	if fastest != nil {
+		return fastest
+	}
+	return oldest
+}
+
+// endpointsTransport - sends requests to the endpoint picked from the pool.
+// The Host header is kept so that signatures stay valid, requests failing
+// to reach an endpoint are sent to the next one when their body can be
+// sent again, others are retried by minio-go and sent to the next one.
+type endpointsTransport struct {
+	pool      *endpointPool
+	transport http.RoundTripper
+}
+
+func newEndpointsTransport(pool *endpointPool, transport http.RoundTripper) http.RoundTripper {
+	if pool == nil {
+		return transport
+	}
+	return &endpointsTransport{pool: pool, transport: transport}
+}
+
+func (t *endpointsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tried := map[*endpointState]bool{}
+	body := req.Body
+	for {
+		endpoint := t.pool.pick(tried)
+		tried[endpoint] = true
+
+		r := new(http.Request)
+		*r = *req
+		u := *req.URL
+		u.Host = endpoint.host
+		r.URL = &u
+		r.Body = body
+		if r.Host == "" {
+			r.Host = req.URL.Host
+		}
+		resp, e := t.transport.RoundTrip(r)
+		if e == nil || req.Context().Err() != nil || globalContext.Err() != nil {
+			return resp, e
+		}
+
+		t.pool.mutex.Lock()
+		t.pool.markDown(endpoint)
+		t.pool.mutex.Unlock()
+		if len(tried) == len(t.pool.endpoints) {
+			return resp, e
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, e
+			}
+			if body, e = req.GetBody(); e != nil {
+				return nil, e
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckEndpoints(t *testing.T) {
+	testCases := []struct {
+		hostURL   string
+		endpoints []string
+		success   bool
+	}{
+		{"https://node1:9000", nil, true},
+		{"https://node1:9000", []string{"https://node2:9000", "https://node3:9000"}, true},
+		{"http://node1:9000", []string{"https://node2:9000"}, false},
+		{"https://node1:9000", []string{"node2:9000"}, false},
+	}
+	for i, testCase := range testCases {
+		if err := checkEndpoints(testCase.hostURL, testCase.endpoints); (err == nil) != testCase.success {
+			t.Errorf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+	}
+}
+
+func TestEndpointsTransport(t *testing.T) {
+	var mutex sync.Mutex
+	served := map[string]int{}
+	var hosts []string
+	newNode := func(name string, delay time.Duration) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == endpointHealthPath {
+				time.Sleep(delay)
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			served[name]++
+			hosts = append(hosts, r.Host)
+		}))
+	}
+	down := newNode("down", 0)
+	down.Close()
+	fast := newNode("fast", 0)
+	defer fast.Close()
+	slow := newNode("slow", 200*time.Millisecond)
+	defer slow.Close()
+
+	downURL, _ := url.Parse(down.URL)
+	pool := newEndpointPool("http", downURL.Host, []string{slow.URL, fast.URL}, http.DefaultTransport)
+	if newEndpointPool("http", downURL.Host, []string{slow.URL, fast.URL}, http.DefaultTransport) != pool {
+		t.Fatal("expected the pool to be shared by the clients of the deployment")
+	}
+	client := &http.Client{Transport: newEndpointsTransport(pool, http.DefaultTransport)}
+	send := func(method string) {
+		t.Helper()
+		req, e := http.NewRequest(method, down.URL+"/bucket/object", strings.NewReader("data"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp, e := client.Do(req)
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp.Body.Close()
+	}
+
+	// The fastest endpoint up is preferred, requests keep their host.
+	send(http.MethodGet)
+	if served["fast"] != 1 || hosts[0] != downURL.Host {
+		t.Fatalf("expected the request to be served by the fast endpoint for %s, got %v from %v", downURL.Host, served, hosts)
+	}
+
+	// Requests fail over once the endpoint becomes unreachable.
+	fast.Close()
+	send(http.MethodPut)
+	send(http.MethodGet)
+	if served["slow"] != 2 {
+		t.Errorf("expected the requests to fail over to the slow endpoint, got %v", served)
+	}
+
+	if newEndpointPool("http", downURL.Host, nil, http.DefaultTransport) != nil {
+		t.Error("expected no pool without endpoints")
+	}
+}
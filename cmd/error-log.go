@@ -0,0 +1,128 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// errorLogFlag is shared by cp and mirror.
+var errorLogFlag = cli.StringFlag{
+	Name:  "error-log",
+	Usage: "write the objects failed to copy to FILE as JSON lines, to retry them with 'mc cp --files-from FILE'",
+}
+
+// errorLogEntry - a failed object of the error log.
+type errorLogEntry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Target string    `json:"target"`
+	Error  string    `json:"error"`
+}
+
+// errorLog - writes the objects failed to copy, one JSON line each.
+type errorLog struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// newErrorLog - create an error log, an existing log is appended to when
+// a session is resumed. A nil log is returned for an empty path.
+func newErrorLog(path string, isAppend bool) (*errorLog, *probe.Error) {
+	if path == "" {
+		return nil, nil
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if isAppend {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, e := os.OpenFile(path, flags, 0600)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	return &errorLog{file: file}, nil
+}
+
+// log - write a failed copy, removals are not logged.
+func (l *errorLog) log(sURLs URLs) {
+	if l == nil || sURLs.Error == nil || sURLs.SourceContent == nil || sURLs.TargetContent == nil {
+		return
+	}
+	entry := errorLogEntry{
+		Time:   UTCNow(),
+		Source: filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path)),
+		Target: filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path)),
+		Error:  sURLs.Error.ToGoError().Error(),
+	}
+	entryBytes, e := json.Marshal(entry)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_, e = l.file.Write(append(entryBytes, '\n'))
+	errorIf(probe.NewError(e).Trace(l.file.Name()), "Unable to write to the error log.")
+}
+
+// Close - close the error log.
+func (l *errorLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// parseErrorLog - the entries of a list read by --files-from, if every
+// line of the list is an error log entry.
+func parseErrorLog(lines []string) ([]errorLogEntry, bool) {
+	if len(lines) == 0 {
+		return nil, false
+	}
+	entries := make([]errorLogEntry, 0, len(lines))
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "{") {
+			return nil, false
+		}
+		var entry errorLogEntry
+		if e := json.Unmarshal([]byte(line), &entry); e != nil || entry.Source == "" || entry.Target == "" {
+			return nil, false
+		}
+		entries = append(entries, entry)
+	}
+	return entries, true
+}
+
+// prepareRetryURLs - prepare the copies of the objects of an error log,
+// retryURLs holds pairs of a source and the target it failed to be
+// copied to.
+func prepareRetryURLs(retryURLs []string, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
+	copyURLsCh := make(chan URLs)
+	go func() {
+		defer close(copyURLsCh)
+		for i := 0; i+1 < len(retryURLs); i += 2 {
+			copyURLsCh <- prepareCopyURLsTypeA(globalContext, retryURLs[i], retryURLs[i+1], encKeyDB)
+		}
+	}()
+	return copyURLsCh
+}
@@ -0,0 +1,103 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestErrorLog(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-error-log-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	path := filepath.Join(root, "errors.log")
+
+	for _, isAppend := range []bool{false, true} {
+		errLog, err := newErrorLog(path, isAppend)
+		if err != nil {
+			t.Fatal(err)
+		}
+		errLog.log(URLs{
+			SourceAlias:   "play",
+			SourceContent: &clientContent{URL: *newClientURL("/mybucket/a b.txt")},
+			TargetContent: &clientContent{URL: *newClientURL("/mnt/backup/a b.txt")},
+			Error:         errInvalidArgument(),
+		})
+		// Successful copies and removals are not logged.
+		errLog.log(URLs{
+			SourceContent: &clientContent{URL: *newClientURL("/mnt/c.txt")},
+			TargetContent: &clientContent{URL: *newClientURL("/mnt/backup/c.txt")},
+		})
+		errLog.log(URLs{TargetContent: &clientContent{URL: *newClientURL("/mnt/backup/d.txt")}, Error: errDummy()})
+		if e = errLog.Close(); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	lines, err := readFilesFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, ok := parseErrorLog(lines)
+	if !ok || len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %v", lines)
+	}
+	for _, entry := range entries {
+		if entry.Source != "play/mybucket/a b.txt" || entry.Target != "/mnt/backup/a b.txt" || entry.Error == "" {
+			t.Errorf("unexpected entry %v", entry)
+		}
+	}
+
+	// No log is written without a path.
+	errLog, err := newErrorLog("", false)
+	if errLog != nil || err != nil {
+		t.Fatalf("expected no log, got %v, %v", errLog, err)
+	}
+	errLog.log(URLs{Error: errDummy()})
+	errLog.Close()
+}
+
+func TestParseErrorLog(t *testing.T) {
+	testCases := []struct {
+		lines   []string
+		entries []errorLogEntry
+	}{
+		{nil, nil},
+		{[]string{"play/mybucket/a.txt"}, nil},
+		{[]string{`{"source":"a.txt","target":"play/mybucket/a.txt","error":"timeout"}`},
+			[]errorLogEntry{{Source: "a.txt", Target: "play/mybucket/a.txt", Error: "timeout"}}},
+		// A list of paths is not an error log, even if a name looks like JSON.
+		{[]string{`{"source":"a.txt","target":"play/mybucket/a.txt"}`, "b.txt"}, nil},
+		{[]string{`{"source":"a.txt"}`}, nil},
+		{[]string{`{"source":`}, nil},
+	}
+	for i, testCase := range testCases {
+		entries, ok := parseErrorLog(testCase.lines)
+		if ok != (testCase.entries != nil) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.entries != nil, ok)
+		}
+		if ok && !reflect.DeepEqual(entries, testCase.entries) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.entries, entries)
+		}
+	}
+}
@@ -18,13 +18,17 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio/pkg/madmin"
 )
 
 // causeMessage container for golang error messages
@@ -38,10 +42,52 @@ type errorMessage struct {
 	Message   string             `json:"message"`
 	Cause     causeMessage       `json:"cause"`
 	Type      string             `json:"type"`
+	Code      string             `json:"code,omitempty"`
+	RequestID string             `json:"requestId,omitempty"`
 	CallTrace []probe.TracePoint `json:"trace,omitempty"`
 	SysInfo   map[string]string  `json:"sysinfo"`
 }
 
+// errorJSON - structured error printed to stderr with --json, errType
+// is either "fatal" or "error".
+func errorJSON(err *probe.Error, errType, msg string) string {
+	e := err.ToGoError()
+	errorMsg := errorMessage{
+		Message: msg,
+		Type:    errType,
+		Cause: causeMessage{
+			Message: e.Error(),
+			Error:   e,
+		},
+		SysInfo: err.SysInfo,
+	}
+	errorMsg.Code, errorMsg.RequestID = errorResponseCode(e)
+	if globalDebug {
+		errorMsg.CallTrace = err.CallTrace
+	}
+	json, e := json.MarshalIndent(struct {
+		Status string       `json:"status"`
+		Error  errorMessage `json:"error"`
+	}{
+		Status: "error",
+		Error:  errorMsg,
+	}, "", " ")
+	if e != nil {
+		console.Fatalln(probe.NewError(e))
+	}
+	return formatStructured(string(json))
+}
+
+// errorResponseCode - error code and request id of a failed S3 or admin
+// API call, empty for any other error.
+func errorResponseCode(e error) (code, requestID string) {
+	if resp := minio.ToErrorResponse(e); resp.Code != "" {
+		return resp.Code, resp.RequestID
+	}
+	resp := madmin.ToErrorResponse(e)
+	return resp.Code, resp.RequestID
+}
+
 // fatalIf wrapper function which takes error and selectively prints stack frames if available on debug
 func fatalIf(err *probe.Error, msg string, data ...interface{}) {
 	if err == nil {
@@ -51,31 +97,10 @@ func fatalIf(err *probe.Error, msg string, data ...interface{}) {
 }
 
 func fatal(err *probe.Error, msg string, data ...interface{}) {
+	finishCommand(errorExitStatus(err), strings.TrimSpace(fmt.Sprintf(msg, data...)+" "+err.ToGoError().Error()))
 	if globalJSON {
-		errorMsg := errorMessage{
-			Message: msg,
-			Type:    "fatal",
-			Cause: causeMessage{
-				Message: err.ToGoError().Error(),
-				Error:   err.ToGoError(),
-			},
-			SysInfo: err.SysInfo,
-		}
-		if globalDebug {
-			errorMsg.CallTrace = err.CallTrace
-		}
-		json, e := json.MarshalIndent(struct {
-			Status string       `json:"status"`
-			Error  errorMessage `json:"error"`
-		}{
-			Status: "error",
-			Error:  errorMsg,
-		}, "", " ")
-		if e != nil {
-			console.Fatalln(probe.NewError(e))
-		}
-		console.Println(string(json))
-		console.Fatalln()
+		console.Eprintln(errorJSON(err, "fatal", fmt.Sprintf(msg, data...)))
+		console.FatalStatusln(errorExitStatus(err))
 	}
 
 	msg = fmt.Sprintf(msg, data...)
@@ -105,7 +130,7 @@ func fatal(err *probe.Error, msg string, data ...interface{}) {
 		}
 	}
 
-	console.Fatalln(fmt.Sprintf("%s %s", msg, errmsg))
+	console.FatalStatusln(errorExitStatus(err), fmt.Sprintf("%s %s", msg, errmsg))
 }
 
 // Exit coder wraps cli new exit error with a
@@ -117,35 +142,142 @@ func exitStatus(status int) error {
 	return cli.NewExitError("", status)
 }
 
+// errorExitStatus - exit status telling scripts the class of an error,
+// either not found, access denied or any other failure.
+func errorExitStatus(err *probe.Error) int {
+	if err == nil {
+		return 0
+	}
+	e := err.ToGoError()
+	switch e.(type) {
+	case BucketDoesNotExist, PathNotFound, ObjectMissing, BrokenSymlink, targetNotFoundErr:
+		return globalNotFoundExitStatus
+	case PathInsufficientPermission, ReadOnlyMode:
+		return globalAccessDeniedExitStatus
+	}
+	if os.IsNotExist(e) {
+		return globalNotFoundExitStatus
+	}
+	if os.IsPermission(e) {
+		return globalAccessDeniedExitStatus
+	}
+
+	code, _ := errorResponseCode(e)
+	switch code {
+	case "NoSuchBucket", "NoSuchKey", "NoSuchUpload", "NoSuchBucketPolicy", "NotFound":
+		return globalNotFoundExitStatus
+	case "AccessDenied", "AllAccessDisabled", "InvalidAccessKeyId", "SignatureDoesNotMatch", "XMinioAdminNotOwner":
+		return globalAccessDeniedExitStatus
+	}
+	return globalErrorExitStatus
+}
+
+// maxErrorsFlag is shared by cp, mirror and rm.
+var maxErrorsFlag = cli.IntFlag{
+	Name:  "max-errors",
+	Usage: "keep going past failed objects, abort once more than N objects failed",
+}
+
+// checkMaxErrors - validate '--max-errors', -1 is returned when it is
+// not given.
+func checkMaxErrors(ctx *cli.Context) int {
+	if !ctx.IsSet("max-errors") {
+		return -1
+	}
+	maxErrors := ctx.Int("max-errors")
+	if maxErrors < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("max-errors")), "Unable to parse --max-errors, a number of objects is expected.")
+	}
+	return maxErrors
+}
+
+// failureStatus - exit status of a command running many operations,
+// which reports errors and keeps going. The command fails with the
+// class of its errors if they are alike, or with a partial failure if
+// any of its operations succeeded.
+type failureStatus struct {
+	mutex     sync.Mutex
+	status    int
+	succeeded bool
+	failed    int
+
+	// Failures tolerated by '--max-errors', bounded is false when the
+	// command keeps its own behavior on errors.
+	maxErrors int
+	bounded   bool
+}
+
+// setMaxErrors bounds the failures tolerated, a negative value keeps
+// the command's own behavior on errors.
+func (f *failureStatus) setMaxErrors(maxErrors int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.maxErrors = maxErrors
+	f.bounded = maxErrors >= 0
+}
+
+// tolerated - true if failures are bounded and the bound is not
+// exceeded, the command keeps going past the failed operation.
+func (f *failureStatus) tolerated() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.bounded && f.failed <= f.maxErrors
+}
+
+// exceeded - the error to abort the command with once more operations
+// failed than tolerated, nil otherwise.
+func (f *failureStatus) exceeded() *probe.Error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.bounded && f.failed > f.maxErrors {
+		return errTooManyErrors(f.maxErrors)
+	}
+	return nil
+}
+
+// fail records a failed operation.
+func (f *failureStatus) fail(err *probe.Error) {
+	status := errorExitStatus(err)
+	if status == 0 {
+		status = globalErrorExitStatus
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.status != 0 && f.status != status {
+		status = globalErrorExitStatus
+	}
+	f.status = status
+	f.failed++
+}
+
+// success records a successful operation.
+func (f *failureStatus) success() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.succeeded = true
+}
+
+// exitError returns the error to exit the command with, nil if no
+// operation failed.
+func (f *failureStatus) exitError() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	switch {
+	case f.status == 0:
+		return nil
+	case f.succeeded:
+		return exitStatus(globalPartialFailureExitStatus)
+	}
+	return exitStatus(f.status)
+}
+
 // errorIf synonymous with fatalIf but doesn't exit on error != nil
 func errorIf(err *probe.Error, msg string, data ...interface{}) {
 	if err == nil {
 		return
 	}
 	if globalJSON {
-		errorMsg := errorMessage{
-			Message: fmt.Sprintf(msg, data...),
-			Type:    "error",
-			Cause: causeMessage{
-				Message: err.ToGoError().Error(),
-				Error:   err.ToGoError(),
-			},
-			SysInfo: err.SysInfo,
-		}
-		if globalDebug {
-			errorMsg.CallTrace = err.CallTrace
-		}
-		json, e := json.MarshalIndent(struct {
-			Status string       `json:"status"`
-			Error  errorMessage `json:"error"`
-		}{
-			Status: "error",
-			Error:  errorMsg,
-		}, "", " ")
-		if e != nil {
-			console.Fatalln(probe.NewError(e))
-		}
-		console.Println(string(json))
+		console.Eprintln(errorJSON(err, "error", fmt.Sprintf(msg, data...)))
 		return
 	}
 	msg = fmt.Sprintf(msg, data...)
@@ -0,0 +1,156 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestErrorExitStatus(t *testing.T) {
+	testCases := []struct {
+		err      *probe.Error
+		expected int
+	}{
+		{nil, 0},
+		{probe.NewError(errors.New("failed")), globalErrorExitStatus},
+		{probe.NewError(BucketDoesNotExist{Bucket: "bucket"}), globalNotFoundExitStatus},
+		{probe.NewError(PathNotFound{Path: "/tmp/file"}), globalNotFoundExitStatus},
+		{errTargetNotFound("play/bucket"), globalNotFoundExitStatus},
+		{probe.NewError(os.ErrNotExist), globalNotFoundExitStatus},
+		{probe.NewError(minio.ErrorResponse{Code: "NoSuchKey"}), globalNotFoundExitStatus},
+		{probe.NewError(PathInsufficientPermission{Path: "/tmp/file"}), globalAccessDeniedExitStatus},
+		{probe.NewError(os.ErrPermission), globalAccessDeniedExitStatus},
+		{probe.NewError(minio.ErrorResponse{Code: "AccessDenied"}), globalAccessDeniedExitStatus},
+		{probe.NewError(ReadOnlyMode{URL: "play/bucket"}), globalAccessDeniedExitStatus},
+		{probe.NewError(minio.ErrorResponse{Code: "InternalError"}), globalErrorExitStatus},
+	}
+	for i, testCase := range testCases {
+		if status := errorExitStatus(testCase.err); status != testCase.expected {
+			t.Errorf("Test %d: Expected exit status %d, got %d", i+1, testCase.expected, status)
+		}
+	}
+}
+
+func TestErrorJSON(t *testing.T) {
+	testCases := []struct {
+		err       *probe.Error
+		code      string
+		requestID string
+	}{
+		{probe.NewError(errors.New("failed")), "", ""},
+		{probe.NewError(minio.ErrorResponse{Code: "NoSuchKey", Message: "The specified key does not exist.", RequestID: "15A0F3AC"}), "NoSuchKey", "15A0F3AC"},
+		{probe.NewError(madmin.ErrorResponse{Code: "XMinioAdminNotOwner", RequestID: "15A0F3AD"}), "XMinioAdminNotOwner", "15A0F3AD"},
+	}
+	for i, testCase := range testCases {
+		var msg struct {
+			Status string
+			Error  struct {
+				Message   string
+				Type      string
+				Code      string
+				RequestID string
+				Cause     struct{ Message string }
+			}
+		}
+		jsonStr := ansiEscapeRegex.ReplaceAllString(errorJSON(testCase.err, "fatal", "Unable to stat."), "")
+		if e := json.Unmarshal([]byte(jsonStr), &msg); e != nil {
+			t.Fatalf("Test %d: %s", i+1, e)
+		}
+		if msg.Status != "error" || msg.Error.Type != "fatal" || msg.Error.Message != "Unable to stat." {
+			t.Errorf("Test %d: Unexpected error message %s", i+1, jsonStr)
+		}
+		if msg.Error.Cause.Message != testCase.err.ToGoError().Error() {
+			t.Errorf("Test %d: Expected cause `%s`, got `%s`", i+1, testCase.err.ToGoError(), msg.Error.Cause.Message)
+		}
+		if msg.Error.Code != testCase.code || msg.Error.RequestID != testCase.requestID {
+			t.Errorf("Test %d: Expected code %s and request id %s, got %s and %s", i+1, testCase.code, testCase.requestID, msg.Error.Code, msg.Error.RequestID)
+		}
+	}
+}
+
+func TestFailureStatus(t *testing.T) {
+	notFound := probe.NewError(BucketDoesNotExist{Bucket: "bucket"})
+	denied := probe.NewError(minio.ErrorResponse{Code: "AccessDenied"})
+
+	exitCode := func(e error) int {
+		if e == nil {
+			return 0
+		}
+		return e.(cli.ExitCoder).ExitCode()
+	}
+
+	fs := &failureStatus{}
+	fs.success()
+	if status := exitCode(fs.exitError()); status != 0 {
+		t.Errorf("Expected exit status 0, got %d", status)
+	}
+
+	fs = &failureStatus{}
+	fs.fail(notFound)
+	fs.fail(notFound)
+	if status := exitCode(fs.exitError()); status != globalNotFoundExitStatus {
+		t.Errorf("Expected exit status %d, got %d", globalNotFoundExitStatus, status)
+	}
+
+	fs.fail(denied)
+	if status := exitCode(fs.exitError()); status != globalErrorExitStatus {
+		t.Errorf("Expected exit status %d, got %d", globalErrorExitStatus, status)
+	}
+
+	fs.success()
+	if status := exitCode(fs.exitError()); status != globalPartialFailureExitStatus {
+		t.Errorf("Expected exit status %d, got %d", globalPartialFailureExitStatus, status)
+	}
+}
+
+func TestFailureStatusMaxErrors(t *testing.T) {
+	testCases := []struct {
+		maxErrors int
+		failures  int
+		tolerated bool
+		exceeded  bool
+	}{
+		// Without --max-errors the command keeps its own behavior.
+		{-1, 0, false, false},
+		{-1, 100, false, false},
+		{0, 0, true, false},
+		{0, 1, false, true},
+		{2, 2, true, false},
+		{2, 3, false, true},
+	}
+	for i, testCase := range testCases {
+		fs := &failureStatus{}
+		fs.setMaxErrors(testCase.maxErrors)
+		for j := 0; j < testCase.failures; j++ {
+			fs.fail(errDummy())
+		}
+		if tolerated := fs.tolerated(); tolerated != testCase.tolerated {
+			t.Errorf("Test %d: expected tolerated %v, got %v", i+1, testCase.tolerated, tolerated)
+		}
+		if err := fs.exceeded(); (err != nil) != testCase.exceeded {
+			t.Errorf("Test %d: expected exceeded %v, got %v", i+1, testCase.exceeded, err)
+		}
+	}
+}
@@ -66,6 +66,10 @@ EXAMPLES:
    2. Enable bucket notification with filters parameters
      $ {{.HelpName}} s3/mybucket arn:aws:sqs:us-west-2:444455556666:your-queue --event put,delete,get --prefix photos/ --suffix .jpg
 
+   3. Enable bucket notification with a webhook target of a MinIO server, listed by 'mc event targets'.
+     $ mc event targets myminio
+     $ {{.HelpName}} myminio/mybucket arn:minio:sqs::1:webhook --event put
+
 `,
 }
 
@@ -120,6 +124,7 @@ func mainEventAdd(ctx *cli.Context) error {
 	if !ok {
 		fatalIf(errDummy().Trace(), "The provided url doesn't point to a S3 server.")
 	}
+	checkNotificationARN(path, arn)
 
 	err = s3Client.AddNotificationConfig(arn, event, prefix, suffix)
 	fatalIf(err, "Cannot enable notification on the specified bucket.")
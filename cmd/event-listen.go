@@ -0,0 +1,236 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Time layout of replayed events, the layout of S3 notification events.
+const eventTimeLayout = "2006-01-02T15:04:05.000Z"
+
+var (
+	eventListenFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "events",
+			Value: "put,delete,get",
+			Usage: "filter specific types of events; defaults to all events by default",
+		},
+		cli.StringFlag{
+			Name:  "prefix",
+			Usage: "filter events for a prefix",
+		},
+		cli.StringFlag{
+			Name:  "suffix",
+			Usage: "filter events for a suffix",
+		},
+		cli.StringFlag{
+			Name:  "resume-from",
+			Usage: "replay the events missed since DURATION ago or since TIMESTAMP before listening",
+		},
+	}
+)
+
+var eventListenCmd = cli.Command{
+	Name:   "listen",
+	Usage:  "listen for bucket notification events, replaying missed events",
+	Action: mainEventListen,
+	Before: setGlobalsFromContext,
+	Flags:  append(append(eventListenFlags, metricsFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Events are not kept by the server, '--resume-from' replays the events missed since a point
+  in time from the objects below TARGET. Versioned buckets replay every version and delete
+  marker created since then, other buckets replay the creation of every object modified since
+  then, read events are never replayed. Live events are listened for before the replay starts,
+  an event happening during the replay may be printed twice.
+
+EXAMPLES:
+   1. Listen for events on a bucket of a MinIO server.
+      $ {{.HelpName}} myminio/mybucket
+
+   2. Catch up on the events of the last 2 hours, then keep listening.
+      $ {{.HelpName}} --resume-from 2h myminio/mybucket
+
+   3. Catch up on the uploads of ".jpg" files since a timestamp, then keep listening.
+      $ {{.HelpName}} --events put --suffix ".jpg" --resume-from 2019-10-09T22:00 myminio/photos
+
+   4. Listen for events and send their counts by type to the statsd daemon on 127.0.0.1:8125.
+      $ {{.HelpName}} --metrics-statsd 127.0.0.1:8125 myminio/mybucket
+`,
+}
+
+// checkEventListenSyntax - validate all the passed arguments, returns
+// the point in time events are replayed from.
+func checkEventListenSyntax(ctx *cli.Context) time.Time {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "listen", 1) // last argument is exit code
+	}
+	value := ctx.String("resume-from")
+	if value == "" {
+		return time.Time{}
+	}
+	since, err := parseRewind(value)
+	fatalIf(err.Trace(value), "Unable to parse --resume-from=`"+value+"`.")
+	if since.After(UTCNow()) {
+		fatalIf(errInvalidRewind(value).Trace(value), "Unable to replay events from a point in time in the future.")
+	}
+	return since
+}
+
+// replayEvents - events of the objects below the client modified after
+// since, in the order they happened.
+func replayEvents(clnt Client, since time.Time, params watchParams) ([]EventInfo, *probe.Error) {
+	types := make(map[EventType]bool)
+	for _, event := range params.events {
+		switch event {
+		case "put":
+			types[EventCreate] = true
+		case "delete":
+			types[EventRemove] = true
+		case "get":
+			// Reads leave no trace to replay them from.
+		default:
+			return nil, errInvalidArgument().Trace(event)
+		}
+	}
+
+	var events []EventInfo
+	if s3Clnt, ok := clnt.(*s3Client); ok {
+		// Servers not implementing versioning are listed as unversioned.
+		if versioning, err := s3Clnt.GetVersioning(); err == nil && versioning != "" {
+			bucket, prefix := s3Clnt.url2BucketAndObject()
+			if prefix == "" {
+				prefix = params.prefix
+			}
+			err = s3Clnt.listVersionsSince(bucket, prefix, since, func(version objectVersion) bool {
+				eventType := EventCreate
+				if version.isDeleteMarker {
+					eventType = EventRemove
+				}
+				if types[eventType] && strings.HasSuffix(version.Key, params.suffix) {
+					events = append(events, EventInfo{
+						Time: version.LastModified.UTC().Format(eventTimeLayout),
+						Size: version.Size,
+						Path: s3Clnt.version2ClientContent(bucket, version).URL.String(),
+						Type: eventType,
+					})
+				}
+				return true
+			})
+			if err != nil {
+				return nil, err.Trace(clnt.GetURL().String())
+			}
+			sortEvents(events)
+			return events, nil
+		}
+	}
+
+	if !types[EventCreate] {
+		return nil, nil
+	}
+	sep := string(clnt.GetURL().Separator)
+	root := strings.TrimSuffix(clnt.GetURL().Path, sep) + sep
+	for content := range clnt.List(globalContext, true, false, DirNone) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(clnt.GetURL().String())
+		}
+		key := strings.TrimPrefix(content.URL.Path, root)
+		if content.Type.IsDir() || !content.Time.After(since) ||
+			!strings.HasPrefix(key, params.prefix) || !strings.HasSuffix(key, params.suffix) {
+			continue
+		}
+		events = append(events, EventInfo{
+			Time: content.Time.UTC().Format(eventTimeLayout),
+			Size: content.Size,
+			Path: content.URL.String(),
+			Type: EventCreate,
+		})
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+// sortEvents - sort events by time, the layout of their UTC times
+// sorts in time order.
+func sortEvents(events []EventInfo) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time < events[j].Time
+	})
+}
+
+// mainEventListen is the handle for "mc event listen" command.
+func mainEventListen(ctx *cli.Context) error {
+	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Size", color.New(color.FgYellow))
+	console.SetColor("EventType", color.New(color.FgCyan, color.Bold))
+	console.SetColor("ObjectName", color.New(color.Bold))
+	console.SetColor("Replayed", color.New(color.FgYellow))
+
+	since := checkEventListenSyntax(ctx)
+
+	path := ctx.Args().First()
+	clnt, err := newClient(path)
+	fatalIf(err.Trace(path), "Cannot parse the provided url.")
+
+	params := watchParams{
+		recursive: true,
+		events:    strings.Split(ctx.String("events"), ","),
+		prefix:    ctx.String("prefix"),
+		suffix:    ctx.String("suffix"),
+	}
+
+	metrics, err := newOperationMetrics("event_listen", ctx.String("metrics-addr"), ctx.String("metrics-statsd"))
+	fatalIf(err, "Unable to serve the metrics.")
+	defer metrics.Close()
+
+	// Listen before replaying, no event is missed in between.
+	wo, err := clnt.Watch(params)
+	fatalIf(err.Trace(path), "Unable to listen for events on `"+path+"`.")
+
+	if !since.IsZero() {
+		events, err := replayEvents(clnt, since, params)
+		if err != nil {
+			close(wo.doneChan)
+			fatalIf(err.Trace(path), "Unable to replay the events of `"+path+"`.")
+		}
+		for _, event := range events {
+			msg := newWatchMessage(event)
+			msg.Replayed = true
+			printMsg(msg)
+			metrics.event(event)
+		}
+	}
+
+	printEvents(wo, metrics)
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestReplayEvents(c *C) {
+	base := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	h := &rewindHandler{
+		versions: []rewindVersion{
+			{key: "a.jpg", versionID: "v3", modTime: at(30), body: "a3a3"},
+			{key: "a.jpg", versionID: "v1", modTime: at(10), body: "a1"},
+			{key: "b.txt", versionID: "v4", modTime: at(25), deleteMarker: true},
+			{key: "b.txt", versionID: "v2", modTime: at(15), body: "b2"},
+			{key: "dir/c.jpg", versionID: "v5", modTime: at(20), body: "c5"},
+		},
+		pageSize: 2,
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	describe := func(events []EventInfo) (descriptions []string) {
+		for _, event := range events {
+			descriptions = append(descriptions, event.Time+" "+string(event.Type)+" "+event.Path)
+		}
+		return descriptions
+	}
+
+	// Versions and delete markers are replayed in time order.
+	events, err := replayEvents(clnt, at(12), watchParams{events: []string{"put", "delete", "get"}})
+	c.Assert(err, IsNil)
+	c.Assert(describe(events), DeepEquals, []string{
+		"2019-06-01T00:15:00.000Z ObjectCreated " + server.URL + "/bucket/b.txt",
+		"2019-06-01T00:20:00.000Z ObjectCreated " + server.URL + "/bucket/dir/c.jpg",
+		"2019-06-01T00:25:00.000Z ObjectRemoved " + server.URL + "/bucket/b.txt",
+		"2019-06-01T00:30:00.000Z ObjectCreated " + server.URL + "/bucket/a.jpg",
+	})
+	c.Assert(events[3].Size, Equals, int64(4))
+
+	events, err = replayEvents(clnt, at(12), watchParams{events: []string{"put"}, suffix: ".jpg"})
+	c.Assert(err, IsNil)
+	c.Assert(describe(events), DeepEquals, []string{
+		"2019-06-01T00:20:00.000Z ObjectCreated " + server.URL + "/bucket/dir/c.jpg",
+		"2019-06-01T00:30:00.000Z ObjectCreated " + server.URL + "/bucket/a.jpg",
+	})
+
+	_, err = replayEvents(clnt, at(12), watchParams{events: []string{"post"}})
+	c.Assert(err, NotNil)
+
+	// Local folders replay the creation of modified files.
+	root, e := ioutil.TempDir("", "mc-event-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	for i, name := range []string{"old.jpg", "dir/new.jpg", "new.txt"} {
+		path := filepath.Join(root, name)
+		c.Assert(os.MkdirAll(filepath.Dir(path), 0700), IsNil)
+		c.Assert(ioutil.WriteFile(path, []byte(name), 0600), IsNil)
+		c.Assert(os.Chtimes(path, at(10*i), at(10*i)), IsNil)
+	}
+	fsClnt, err := fsNew(root)
+	c.Assert(err, IsNil)
+	events, err = replayEvents(fsClnt, at(5), watchParams{events: []string{"put", "delete"}, prefix: "dir/"})
+	c.Assert(err, IsNil)
+	c.Assert(describe(events), DeepEquals, []string{
+		"2019-06-01T00:10:00.000Z ObjectCreated " + filepath.Join(root, "dir", "new.jpg"),
+	})
+	events, err = replayEvents(fsClnt, at(5), watchParams{events: []string{"delete"}})
+	c.Assert(err, IsNil)
+	c.Assert(events, IsNil)
+}
@@ -33,6 +33,8 @@ var eventCmd = cli.Command{
 		eventAddCmd,
 		eventRemoveCmd,
 		eventListCmd,
+		eventListenCmd,
+		eventTargetsCmd,
 	},
 }
 
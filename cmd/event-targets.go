@@ -0,0 +1,174 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/words"
+)
+
+var eventTargetsCmd = cli.Command{
+	Name:   "targets",
+	Usage:  "list the notification targets of a server",
+	Action: mainEventTargets,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  The ARNs of the notification targets configured on a MinIO server, to be
+  passed to 'mc event add'. Listing them needs admin access to the server.
+
+EXAMPLES:
+   1. List the ARNs of the notification targets of a server.
+     $ {{.HelpName}} myminio
+
+`,
+}
+
+// checkEventTargetsSyntax - validate all the passed arguments
+func checkEventTargetsSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "targets", 1) // last argument is exit code
+	}
+}
+
+// eventTargetMessage container of an ARN of a notification target, like
+// 'arn:minio:sqs::1:webhook'.
+type eventTargetMessage struct {
+	Status  string `json:"status"`
+	ARN     string `json:"arn"`
+	Region  string `json:"region"`
+	ID      string `json:"id"`
+	Service string `json:"service"`
+}
+
+// newEventTargetMessage - the parts of a notification ARN.
+func newEventTargetMessage(arn string) eventTargetMessage {
+	msg := eventTargetMessage{ARN: arn}
+	if parts := strings.SplitN(arn, ":", 6); len(parts) == 6 {
+		msg.Region, msg.ID, msg.Service = parts[3], parts[4], parts[5]
+	}
+	return msg
+}
+
+func (u eventTargetMessage) JSON() string {
+	u.Status = "success"
+	eventTargetMessageJSONBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(eventTargetMessageJSONBytes)
+}
+
+func (u eventTargetMessage) String() string {
+	return console.Colorize("ARN", fmt.Sprintf("%s   ", u.ARN)) + console.Colorize("Event", u.Service)
+}
+
+// notificationARNs - the ARNs of the notification targets of all servers
+// of aliasedURL, sorted.
+func notificationARNs(aliasedURL string) ([]string, *probe.Error) {
+	client, err := newAdminClient(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	serversInfo, e := client.ServerInfo()
+	if e != nil {
+		return nil, probe.NewError(e).Trace(aliasedURL)
+	}
+	seen := make(map[string]bool)
+	arns := []string{}
+	for _, serverInfo := range serversInfo {
+		if serverInfo.Error != "" || serverInfo.Data == nil {
+			continue
+		}
+		for _, arn := range serverInfo.Data.Properties.SQSARN {
+			if !seen[arn] {
+				seen[arn] = true
+				arns = append(arns, arn)
+			}
+		}
+	}
+	sort.Strings(arns)
+	return arns, nil
+}
+
+// suggestNotificationARN - the ARN closest to a mistyped one, or "" when
+// none is close.
+func suggestNotificationARN(arn string, arns []string) string {
+	suggestion, best := "", len(arn)/3+1
+	for _, candidate := range arns {
+		if distance := words.DamerauLevenshteinDistance(arn, candidate); distance < best {
+			suggestion, best = candidate, distance
+		}
+	}
+	return suggestion
+}
+
+// checkNotificationARN - exit when arn is not a notification target of
+// the server of aliasedURL. ARNs of servers not listing their targets,
+// like AWS S3 or MinIO without admin access, are not checked.
+func checkNotificationARN(aliasedURL, arn string) {
+	arns, err := notificationARNs(aliasedURL)
+	if err != nil {
+		return
+	}
+	for _, target := range arns {
+		if target == arn {
+			return
+		}
+	}
+	alias, _ := url2Alias(aliasedURL)
+	msg := "ARN `" + arn + "` is not a notification target of `" + alias + "`."
+	switch suggestion := suggestNotificationARN(arn, arns); {
+	case suggestion != "":
+		msg += " Did you mean `" + suggestion + "`?"
+	case len(arns) > 0:
+		msg += " Its targets are `" + strings.Join(arns, "`, `") + "`."
+	default:
+		msg += " It has no notification targets."
+	}
+	fatalIf(errInvalidArgument().Trace(arn), msg)
+}
+
+func mainEventTargets(ctx *cli.Context) error {
+	console.SetColor("ARN", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Event", color.New(color.FgCyan, color.Bold))
+
+	checkEventTargetsSyntax(ctx)
+
+	aliasedURL := ctx.Args().Get(0)
+	arns, err := notificationARNs(aliasedURL)
+	fatalIf(err, "Cannot list the notification targets of `"+aliasedURL+"`.")
+	for _, arn := range arns {
+		printMsg(newEventTargetMessage(arn))
+	}
+	return nil
+}
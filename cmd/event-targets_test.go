@@ -0,0 +1,61 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+func TestNewEventTargetMessage(t *testing.T) {
+	testCases := []struct {
+		arn     string
+		region  string
+		id      string
+		service string
+	}{
+		{"arn:minio:sqs::1:webhook", "", "1", "webhook"},
+		{"arn:minio:sqs:us-east-1:primary:amqp", "us-east-1", "primary", "amqp"},
+		// Unknown formats keep only their ARN.
+		{"not-an-arn", "", "", ""},
+	}
+	for i, testCase := range testCases {
+		msg := newEventTargetMessage(testCase.arn)
+		if msg.ARN != testCase.arn || msg.Region != testCase.region || msg.ID != testCase.id || msg.Service != testCase.service {
+			t.Errorf("Test %d: unexpected message %+v", i+1, msg)
+		}
+	}
+}
+
+func TestSuggestNotificationARN(t *testing.T) {
+	arns := []string{"arn:minio:sqs::1:amqp", "arn:minio:sqs::1:webhook", "arn:minio:sqs::2:webhook"}
+	testCases := []struct {
+		arn        string
+		suggestion string
+	}{
+		{"arn:minio:sqs::1:webhok", "arn:minio:sqs::1:webhook"},
+		{"arn:minio:sqs:us-east-1:1:amqp", "arn:minio:sqs::1:amqp"},
+		{"arn:minio:sqs::2:webhok", "arn:minio:sqs::2:webhook"},
+		// Unrelated ARNs get no suggestion.
+		{"arn:aws:sqs:us-west-2:444455556666:your-queue", ""},
+	}
+	for i, testCase := range testCases {
+		if suggestion := suggestNotificationARN(testCase.arn, arns); suggestion != testCase.suggestion {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.suggestion, suggestion)
+		}
+	}
+	if suggestion := suggestNotificationARN("arn:minio:sqs::1:webhook", nil); suggestion != "" {
+		t.Errorf("expected no suggestion without targets, got %q", suggestion)
+	}
+}
@@ -0,0 +1,42 @@
+// +build !windows
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLockFile - open path and lock it exclusively, without waiting for
+// another process holding the lock. The lock is held until the file is
+// closed.
+func tryLockFile(path string) (*os.File, bool, error) {
+	file, e := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if e != nil {
+		return nil, false, e
+	}
+	if e = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); e != nil {
+		file.Close()
+		if e == syscall.EWOULDBLOCK {
+			return nil, false, nil
+		}
+		return nil, false, e
+	}
+	return file, true, nil
+}
@@ -0,0 +1,45 @@
+// +build windows
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// ERROR_SHARING_VIOLATION
+const errSharingViolation syscall.Errno = 32
+
+// tryLockFile - open path without sharing it, which locks it until the
+// file is closed. Opening a file opened by another process fails at once.
+func tryLockFile(path string) (*os.File, bool, error) {
+	name, e := syscall.UTF16PtrFromString(path)
+	if e != nil {
+		return nil, false, e
+	}
+	handle, e := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+		syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if e != nil {
+		if e == errSharingViolation {
+			return nil, false, nil
+		}
+		return nil, false, &os.PathError{Op: "open", Path: path, Err: e}
+	}
+	return os.NewFile(uintptr(handle), path), true, nil
+}
@@ -17,7 +17,9 @@
 package cmd
 
 import (
+	"regexp"
 	"strings"
+	"text/template"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
@@ -57,9 +59,13 @@ var (
 			Name:  "print",
 			Usage: "print in custom format to STDOUT (see FORMAT)",
 		},
+		cli.BoolFlag{
+			Name:  "print0",
+			Usage: "terminate each match by a NUL character instead of a newline, for xargs -0",
+		},
 		cli.StringFlag{
 			Name:  "regex",
-			Usage: "match directory and object name with PCRE regex pattern",
+			Usage: "match directory and object name with a Go regular expression (RE2 syntax)",
 		},
 		cli.StringFlag{
 			Name:  "larger",
@@ -77,6 +83,16 @@ var (
 			Name:  "watch",
 			Usage: "monitor a specified path for newly created object(s)",
 		},
+		cli.BoolFlag{
+			Name:  "watch-only",
+			Usage: "like --watch, without matching the objects which exist already",
+		},
+		cli.UintFlag{
+			Name:  "parallel",
+			Usage: "run up to N commands of --exec concurrently, reporting all failures",
+			Value: 1,
+		},
+		formatFlag,
 	}
 )
 
@@ -119,6 +135,16 @@ FORMAT
 
       {url} --> Substitutes to a shareable URL of the path.
 
+   A --print format with "{{"{{"}}" is a Go template instead, with the fields .Key,
+   .Base, .Dir, .Size, .Time, .ETag and .URL, and the functions humanize
+   and quote. --format prints each match with a Go template of the fields
+   printed by --json instead, like .Key, .Size, .Time and .ETag, and the
+   functions humanize, quote and json.
+
+   Keywords in quotes, like {"base"}, substitute to a quoted string. The --exec
+   command is split into arguments at spaces before substitution, a path with
+   spaces is passed to the command as a single argument.
+
 EXAMPLES:
    01. Find all "foo.jpg" in all buckets under "s3" account.
        $ {{.HelpName}} s3 --name "foo.jpg"
@@ -151,6 +177,21 @@ EXAMPLES:
    10. List all objects up to 3 levels sub-directory deep under "s3/bucket".
        $ {{.HelpName}} s3/bucket --maxdepth 3
 
+   11. Find all ".log" files under "/var/log" and compress them, running 4 commands at a time.
+       $ {{.HelpName}} /var/log --name "*.log" --parallel 4 --exec "gzip {}"
+
+   12. Remove all ".tmp" objects under "s3/bucket", safe for names with spaces or newlines.
+       $ {{.HelpName}} s3/bucket --name "*.tmp" --print0 | xargs -0 mc rm
+
+   13. Print the size and modification date of all objects under "s3/bucket" with a Go template.
+       $ {{.HelpName}} s3/bucket --print '{{"{{"}}humanize .Size{{"}}"}} {{"{{"}}.Time.Format "2006-01-02"{{"}}"}} {{"{{"}}.Key{{"}}"}}'
+
+   14. Print the ETag and name of all ".iso" objects under "s3/bucket", separated by a tab.
+       $ {{.HelpName}} s3/bucket --name "*.iso" --format '{{"{{"}}.ETag{{"}}"}}\t{{"{{"}}.Key{{"}}"}}'
+
+   15. Print the names of new ".csv" objects under "s3/bucket" as they are uploaded, for a pipeline.
+       $ {{.HelpName}} s3/bucket --name "*.csv" --watch-only | while read -r key; do process "$key"; done
+
 `,
 }
 
@@ -169,12 +210,34 @@ func checkFindSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		}
 	}
 
+	if ctx.String("regex") != "" {
+		_, e := regexp.Compile(ctx.String("regex"))
+		fatalIf(probe.NewError(e).Trace(ctx.String("regex")), "Unable to parse `--regex` pattern.")
+	}
+
+	if ctx.Bool("print0") && globalJSON {
+		fatalIf(errInvalidArgument().Trace(args...), "`--print0` cannot be used with `--json`.")
+	}
+
+	if ctx.String("format") != "" && (ctx.String("print") != "" || ctx.Bool("print0")) {
+		fatalIf(errInvalidArgument().Trace(args...), "`--format` cannot be used with `--print` or `--print0`.")
+	}
+
+	if ctx.IsSet("parallel") {
+		if ctx.Uint("parallel") == 0 {
+			fatalIf(errInvalidArgument().Trace(ctx.String("parallel")), "`--parallel` must be at least 1.")
+		}
+		if ctx.String("exec") == "" {
+			fatalIf(errInvalidArgument().Trace(args...), "`--parallel` requires `--exec`.")
+		}
+	}
+
 	// Extract input URLs and validate.
 	for _, url := range args {
-		_, _, err := url2Stat(url, false, encKeyDB)
+		_, _, err := url2Stat(globalContext, url, false, encKeyDB)
 		if err != nil && !isURLPrefixExists(url, false) {
 			// Bucket name empty is a valid error for 'find myminio' unless we are using watch, treat it as such.
-			if _, ok := err.ToGoError().(BucketNameEmpty); ok && !ctx.Bool("watch") {
+			if _, ok := err.ToGoError().(BucketNameEmpty); ok && !ctx.Bool("watch") && !ctx.Bool("watch-only") {
 				continue
 			}
 			fatalIf(err.Trace(url), "Unable to stat `"+url+"`.")
@@ -194,11 +257,15 @@ type findContext struct {
 	regexPattern  string
 	maxDepth      uint
 	printFmt      string
+	printTmpl     *template.Template
+	print0        bool
 	olderThan     string
 	newerThan     string
 	largerSize    uint64
 	smallerSize   uint64
 	watch         bool
+	watchOnly     bool
+	execPool      *findExecPool
 
 	// Internal values
 	targetAlias   string
@@ -261,11 +328,16 @@ func mainFind(ctx *cli.Context) error {
 		targetFullURL = hostCfg.URL
 	}
 
+	printTmpl, err := parseFindTemplate(ctx.String("print"))
+	fatalIf(err, "Unable to parse `--print` template.")
+
 	return doFind(&findContext{
 		Context:       ctx,
 		maxDepth:      ctx.Uint("maxdepth"),
 		execCmd:       ctx.String("exec"),
 		printFmt:      ctx.String("print"),
+		printTmpl:     printTmpl,
+		print0:        ctx.Bool("print0"),
 		namePattern:   ctx.String("name"),
 		pathPattern:   ctx.String("path"),
 		regexPattern:  ctx.String("regex"),
@@ -274,7 +346,9 @@ func mainFind(ctx *cli.Context) error {
 		newerThan:     newerThan,
 		largerSize:    largerSize,
 		smallerSize:   smallerSize,
-		watch:         ctx.Bool("watch"),
+		watch:         ctx.Bool("watch") || ctx.Bool("watch-only"),
+		watchOnly:     ctx.Bool("watch-only"),
+		execPool:      newFindExecPool(int(ctx.Uint("parallel"))),
 		targetAlias:   targetAlias,
 		targetURL:     args[0],
 		targetFullURL: targetFullURL,
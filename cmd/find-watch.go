@@ -0,0 +1,170 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Objects listed with a time this long before the watch started may
+// also be received as events, their times come from different clocks.
+const findWatchSkew = time.Minute
+
+// findWatch - the objects created in the target of find --watch. The
+// target is watched before it is listed, so objects created while
+// listing are not missed, and events are queued until it is listed.
+type findWatch struct {
+	watchObj *watchObject
+	started  time.Time
+	eventCh  chan EventInfo
+
+	// Matches already printed, of objects listed shortly before the
+	// watch started and of folders at --maxdepth.
+	printed map[string]time.Time
+}
+
+// startFindWatch - watch the target when --watch is given, nil otherwise.
+func startFindWatch(ctx *findContext) *findWatch {
+	if !ctx.watch {
+		return nil
+	}
+	watchObj, err := ctx.clnt.Watch(watchParams{
+		recursive: true,
+		events:    []string{"put"},
+	})
+	fatalIf(err.Trace(ctx.targetAlias), "Cannot watch with given params.")
+
+	w := &findWatch{
+		watchObj: watchObj,
+		started:  UTCNow(),
+		eventCh:  make(chan EventInfo),
+		printed:  make(map[string]time.Time),
+	}
+	go w.queueEvents(watchObj.Events())
+	return w
+}
+
+// queueEvents - forward events to eventCh without ever blocking the
+// watch, events are queued as long as they are not received.
+func (w *findWatch) queueEvents(events <-chan EventInfo) {
+	defer close(w.eventCh)
+	var queue []EventInfo
+	for events != nil || len(queue) > 0 {
+		var eventCh chan<- EventInfo
+		var next EventInfo
+		if len(queue) > 0 {
+			eventCh, next = w.eventCh, queue[0]
+		}
+		select {
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			queue = append(queue, event)
+		case eventCh <- next:
+			queue = queue[1:]
+		}
+	}
+}
+
+// isFolderKey - true for the folders matched at --maxdepth.
+func isFolderKey(ctx *findContext, key string) bool {
+	return strings.HasSuffix(key, string(ctx.clnt.GetURL().Separator))
+}
+
+// listed - remember a match of the listing which may be received as an
+// event as well.
+func (w *findWatch) listed(ctx *findContext, content contentMessage) {
+	if w == nil {
+		return
+	}
+	if isFolderKey(ctx, content.Key) || !content.Time.Before(w.started.Add(-findWatchSkew)) {
+		w.printed[content.Key] = content.Time
+	}
+}
+
+// isPrinted - true for an event of a match already printed, objects are
+// the same when modified at the same second.
+func (w *findWatch) isPrinted(ctx *findContext, content contentMessage) bool {
+	if isFolderKey(ctx, content.Key) {
+		if _, ok := w.printed[content.Key]; ok {
+			return true
+		}
+		w.printed[content.Key] = content.Time
+		return false
+	}
+	printedTime, ok := w.printed[content.Key]
+	if !ok {
+		return false
+	}
+	delete(w.printed, content.Key)
+	return printedTime.Truncate(time.Second).Equal(content.Time.Truncate(time.Second))
+}
+
+// wait - match the created objects until the user cancels find. Errors of
+// the watch are fatal, so pipelines reading the output can restart it.
+func (w *findWatch) wait(ctx *findContext) {
+	if w == nil {
+		return
+	}
+
+	// Enables users to kill using the control + c
+	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM)
+
+	// Loop until user CTRL-C the command line.
+	for {
+		select {
+		case <-trapCh:
+			console.Println()
+			w.watchObj.Close()
+			return
+		case event, ok := <-w.eventCh:
+			if !ok {
+				return
+			}
+
+			eventTime, e := time.Parse(time.RFC3339, event.Time)
+			if e != nil {
+				errorIf(probe.NewError(e).Trace(event.Time), "Unable to parse event time.")
+				continue
+			}
+
+			content := contentMessage{
+				Key:  getAliasedPath(ctx, event.Path),
+				Time: eventTime.In(globalTimeZone),
+				Size: event.Size,
+			}
+			if w.isPrinted(ctx, content) {
+				continue
+			}
+			find(ctx, content)
+
+		case err, ok := <-w.watchObj.Errors():
+			if !ok {
+				return
+			}
+			fatalIf(err.Trace(ctx.targetURL), "Unable to watch for events.")
+		}
+	}
+}
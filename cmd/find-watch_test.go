@@ -0,0 +1,73 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindWatchQueueEvents(t *testing.T) {
+	events := make(chan EventInfo)
+	w := &findWatch{eventCh: make(chan EventInfo)}
+	go w.queueEvents(events)
+	// Events are queued while nothing receives them.
+	for _, path := range []string{"a", "b", "c"} {
+		events <- EventInfo{Path: path}
+	}
+	close(events)
+	var paths []string
+	for event := range w.eventCh {
+		paths = append(paths, event.Path)
+	}
+	if len(paths) != 3 || paths[0] != "a" || paths[2] != "c" {
+		t.Errorf("expected the events in order, got %v", paths)
+	}
+}
+
+func TestFindWatchIsPrinted(t *testing.T) {
+	clnt, err := fsNew("/tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &findContext{clnt: clnt}
+	started := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	w := &findWatch{started: started, printed: make(map[string]time.Time)}
+
+	// Listed before the watch started, and shortly before it.
+	w.listed(ctx, contentMessage{Key: "old.txt", Time: started.Add(-time.Hour)})
+	w.listed(ctx, contentMessage{Key: "new.txt", Time: started.Add(-time.Second)})
+	w.listed(ctx, contentMessage{Key: "dir/", Time: started.Add(-time.Hour)})
+
+	testCases := []struct {
+		content  contentMessage
+		expected bool
+	}{
+		{contentMessage{Key: "old.txt", Time: started.Add(time.Minute)}, false},
+		{contentMessage{Key: "new.txt", Time: started.Add(-time.Second / 2)}, true},
+		// Listed objects are only skipped once, the next upload is new.
+		{contentMessage{Key: "new.txt", Time: started.Add(-time.Second / 2)}, false},
+		{contentMessage{Key: "dir/", Time: started.Add(time.Minute)}, true},
+		{contentMessage{Key: "other/", Time: started.Add(time.Minute)}, false},
+		{contentMessage{Key: "other/", Time: started.Add(2 * time.Minute)}, true},
+	}
+	for i, testCase := range testCases {
+		if got := w.isPrinted(ctx, testCase.content); got != testCase.expected {
+			t.Errorf("Test %d: %s: expected %v, got %v", i+1, testCase.content.Key, testCase.expected, got)
+		}
+	}
+}
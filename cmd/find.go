@@ -18,16 +18,18 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"text/template"
 	"time"
 
-	"github.com/dustin/go-humanize"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
 
@@ -55,19 +57,23 @@ func (f findMessage) JSON() string {
 // also proceed to look for similar strings alone and print it.
 //
 // pattern:
-// 	{ term }
+//
+//	{ term }
+//
 // term:
-// 	'*'         matches any sequence of non-Separator characters
-// 	'?'         matches any single non-Separator character
-// 	'[' [ '^' ] { character-range } ']'
-// 	            character class (must be non-empty)
-// 	c           matches character c (c != '*', '?', '\\', '[')
-// 	'\\' c      matches character c
+//
+//	'*'         matches any sequence of non-Separator characters
+//	'?'         matches any single non-Separator character
+//	'[' [ '^' ] { character-range } ']'
+//	            character class (must be non-empty)
+//	c           matches character c (c != '*', '?', '\\', '[')
+//	'\\' c      matches character c
+//
 // character-range:
-// 	c           matches character c (c != '\\', '-', ']')
-// 	'\\' c      matches character c
-// 	lo '-' hi   matches character c for lo <= c <= hi
 //
+//	c           matches character c (c != '\\', '-', ']')
+//	'\\' c      matches character c
+//	lo '-' hi   matches character c for lo <= c <= hi
 func nameMatch(pattern, path string) bool {
 	matched, e := filepath.Match(pattern, filepath.Base(path))
 	errorIf(probe.NewError(e).Trace(pattern, path), "Unable to match with input pattern.")
@@ -91,11 +97,29 @@ func pathMatch(pattern, path string) bool {
 	return wildcard.Match(pattern, path)
 }
 
+// Compiled regex patterns, find matches every object of a possibly
+// huge listing against the same pattern.
+var (
+	regexCacheMutex sync.Mutex
+	regexCache      = make(map[string]*regexp.Regexp)
+)
+
 // regexMatch reports whether path matches the regex pattern.
 func regexMatch(pattern, path string) bool {
-	matched, e := regexp.MatchString(pattern, path)
-	errorIf(probe.NewError(e).Trace(pattern), "Unable to regex match with input pattern.")
-	return matched
+	regexCacheMutex.Lock()
+	re, ok := regexCache[pattern]
+	if !ok {
+		var e error
+		re, e = regexp.Compile(pattern)
+		if e != nil {
+			regexCacheMutex.Unlock()
+			errorIf(probe.NewError(e).Trace(pattern), "Unable to regex match with input pattern.")
+			return false
+		}
+		regexCache[pattern] = re
+	}
+	regexCacheMutex.Unlock()
+	return re.MatchString(path)
 }
 
 func getExitStatus(err error) int {
@@ -110,11 +134,89 @@ func getExitStatus(err error) int {
 	return 1
 }
 
-// execFind executes the input command line, additionally formats input
-// for the command line in accordance with subsititution arguments.
-func execFind(command string) {
-	commandArgs := strings.Split(command, " ")
+// execArgs splits the --exec command line into arguments before
+// substituting them, so keys with spaces are passed as one argument.
+func execArgs(command string, fileContent contentMessage) []string {
+	commandArgs := strings.Fields(command)
+	for i := range commandArgs {
+		commandArgs[i] = stringsReplace(commandArgs[i], fileContent)
+	}
+	return commandArgs
+}
+
+// findExecPool runs the --exec commands, at most parallel at a time.
+// A single command at a time stops find at the first failure with its
+// exit status, otherwise failures are reported and find keeps going.
+type findExecPool struct {
+	parallel int
+	sem      chan struct{}
+	wg       sync.WaitGroup
+
+	mutex  sync.Mutex
+	total  int
+	failed int
+	status int
+}
+
+func newFindExecPool(parallel int) *findExecPool {
+	if parallel < 1 {
+		parallel = 1
+	}
+	return &findExecPool{
+		parallel: parallel,
+		sem:      make(chan struct{}, parallel),
+	}
+}
+
+// run executes the command, in the background if running in parallel.
+func (p *findExecPool) run(commandArgs []string) {
+	if len(commandArgs) == 0 {
+		return
+	}
+	if p.parallel == 1 {
+		if err := execFind(commandArgs); err != nil {
+			// Return exit status of the command run
+			os.Exit(getExitStatus(err))
+		}
+		return
+	}
+	p.sem <- struct{}{}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		err := execFind(commandArgs)
+		<-p.sem
+
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		p.total++
+		if err != nil {
+			errorIf(probe.NewError(err).Trace(commandArgs...), "Unable to execute `%s`.", strings.Join(commandArgs, " "))
+			p.failed++
+			p.status = getExitStatus(err)
+		}
+	}()
+}
+
+// wait for all commands, the error exits find with the status of the
+// failed command, or a partial failure if others succeeded.
+func (p *findExecPool) wait() error {
+	p.wg.Wait()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.failed == 0 {
+		return nil
+	}
+	errorIf(probe.NewError(fmt.Errorf("%d of %d commands failed", p.failed, p.total)), "Unable to execute all commands.")
+	if p.failed < p.total {
+		return exitStatus(globalPartialFailureExitStatus)
+	}
+	return exitStatus(p.status)
+}
 
+// execFind executes the input command line, substituted by execArgs,
+// and prints its output once it is done.
+func execFind(commandArgs []string) error {
 	cmd := exec.Command(commandArgs[0], commandArgs[1:]...)
 	var out bytes.Buffer
 	var stderr bytes.Buffer
@@ -122,62 +224,10 @@ func execFind(command string) {
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
 		console.Print(console.Colorize("FindExecErr", stderr.String()))
-		// Return exit status of the command run
-		os.Exit(getExitStatus(err))
+		return err
 	}
 	console.PrintC(out.String())
-}
-
-// watchFind - enables listening on the input path, listens for all file/object
-// created actions. Asynchronously executes the input command line, also allows
-// formatting for the command line in accordance with subsititution arguments.
-func watchFind(ctx *findContext) {
-	// Watch is not enabled, return quickly.
-	if !ctx.watch {
-		return
-	}
-	params := watchParams{
-		recursive: true,
-		events:    []string{"put"},
-	}
-	watchObj, err := ctx.clnt.Watch(params)
-	fatalIf(err.Trace(ctx.targetAlias), "Cannot watch with given params.")
-
-	// Enables users to kill using the control + c
-	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM)
-
-	// Loop until user CTRL-C the command line.
-	for {
-		select {
-		case <-trapCh:
-			console.Println()
-			close(watchObj.doneChan)
-			return
-		case event, ok := <-watchObj.Events():
-			if !ok {
-				return
-			}
-
-			time, e := time.Parse(time.RFC3339, event.Time)
-			if e != nil {
-				errorIf(probe.NewError(e).Trace(event.Time), "Unable to parse event time.")
-				continue
-			}
-
-			find(ctx, contentMessage{
-				Key:  getAliasedPath(ctx, event.Path),
-				Time: time,
-				Size: event.Size,
-			})
-
-		case err, ok := <-watchObj.Errors():
-			if !ok {
-				return
-			}
-			errorIf(err, "Unable to watch for events.")
-			return
-		}
-	}
+	return nil
 }
 
 // Descend at most (a non-negative integer) levels of files
@@ -230,27 +280,148 @@ func find(ctx *findContext, fileContent contentMessage) {
 
 	// proceed to either exec, format the output string.
 	if ctx.execCmd != "" {
-		execFind(stringsReplace(ctx.execCmd, fileContent))
+		ctx.execPool.run(execArgs(ctx.execCmd, fileContent))
 		return
 	}
+	printFind(ctx, fileContent)
+}
+
+// findTemplate - values available to a Go template given to --print.
+type findTemplate struct {
+	Key  string
+	Base string
+	Dir  string
+	Size int64
+	Time time.Time
+	ETag string
+}
+
+// URL - shareable URL of the object, only generated if used.
+func (f findTemplate) URL() string {
+	return getShareURL(f.Key)
+}
+
+// parseFindTemplate parses the --print format as a Go template if it
+// contains actions, nil is returned for the {} style formats.
+func parseFindTemplate(printFmt string) (*template.Template, *probe.Error) {
+	if !strings.Contains(printFmt, "{{") {
+		return nil, nil
+	}
+	tmpl, e := template.New("print").Funcs(formatTemplateFuncs).Parse(printFmt)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(printFmt)
+	}
+	return tmpl, nil
+}
+
+// formatFind formats a match as requested by --print.
+func formatFind(ctx *findContext, fileContent contentMessage) (string, *probe.Error) {
+	if ctx.printTmpl != nil {
+		var buf bytes.Buffer
+		e := ctx.printTmpl.Execute(&buf, findTemplate{
+			Key:  fileContent.Key,
+			Base: filepath.Base(fileContent.Key),
+			Dir:  filepath.Dir(fileContent.Key),
+			Size: fileContent.Size,
+			Time: fileContent.Time,
+			ETag: fileContent.ETag,
+		})
+		if e != nil {
+			return "", probe.NewError(e).Trace(fileContent.Key)
+		}
+		return buf.String(), nil
+	}
 	if ctx.printFmt != "" {
-		fileContent.Key = stringsReplace(ctx.printFmt, fileContent)
+		return stringsReplace(ctx.printFmt, fileContent), nil
 	}
+	return fileContent.Key, nil
+}
+
+// printFind prints a match, terminated by a NUL character with --print0.
+func printFind(ctx *findContext, fileContent contentMessage) {
+	str, err := formatFind(ctx, fileContent)
+	if err != nil {
+		errorIf(err, "Unable to format `%s` with the `--print` template.", fileContent.Key)
+		return
+	}
+	if ctx.print0 {
+		console.Print(str + "\x00")
+		return
+	}
+	fileContent.Key = str
 	printMsg(findMessage{fileContent})
 }
 
+// listFind lists all content under the target, only descending up to
+// --maxdepth levels of directories instead of listing everything when
+// maxDepth is set. Directories at the maximum depth are sent as is.
+func listFind(ctx *findContext) <-chan *clientContent {
+	if ctx.maxDepth == 0 {
+		return ctx.clnt.List(globalContext, true, false, DirNone)
+	}
+	contentCh := make(chan *clientContent)
+	var listDepth func(clnt Client, depth uint)
+	listDepth = func(clnt Client, depth uint) {
+		clntURL := clnt.GetURL().String()
+		for content := range clnt.List(globalContext, false, false, DirNone) {
+			if content.Err != nil || !content.Type.IsDir() {
+				contentCh <- content
+				continue
+			}
+			separator := string(content.URL.Separator)
+			if !strings.HasSuffix(content.URL.Path, separator) {
+				content.URL.Path += separator
+			}
+			dirURL := content.URL.String()
+			if dirURL == clntURL {
+				// Prefix listed by itself.
+				continue
+			}
+			if depth >= ctx.maxDepth {
+				contentCh <- content
+				continue
+			}
+			var dirClnt Client
+			var err *probe.Error
+			if ctx.targetAlias == "" {
+				dirClnt, err = fsNew(dirURL)
+			} else {
+				dirClnt, err = newClientFromAlias(ctx.targetAlias, dirURL)
+			}
+			if err != nil {
+				contentCh <- &clientContent{Err: err.Trace(dirURL)}
+				continue
+			}
+			listDepth(dirClnt, depth+1)
+		}
+	}
+	go func() {
+		defer close(contentCh)
+		listDepth(ctx.clnt, 1)
+	}()
+	return contentCh
+}
+
 // doFind - find is main function body which interprets and executes
 // all the input parameters.
 func doFind(ctx *findContext) error {
-	// If watch is enabled we will wait on the prefix perpetually
-	// for all I/O events until canceled by user, if watch is not enabled
-	// following defer is a no-op.
-	defer watchFind(ctx)
-
 	var prevKeyName string
 
+	// Objects created while listing are matched once listed.
+	watch := startFindWatch(ctx)
+
+	// With --watch-only existing content is not listed.
+	var contentCh <-chan *clientContent
+	if ctx.watchOnly {
+		emptyCh := make(chan *clientContent)
+		close(emptyCh)
+		contentCh = emptyCh
+	} else {
+		contentCh = listFind(ctx)
+	}
+
 	// iterate over all content which is within the given directory
-	for content := range ctx.clnt.List(true, false, DirNone) {
+	for content := range contentCh {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
 			// handle this specifically for filesystem related errors.
@@ -277,8 +448,9 @@ func doFind(ctx *findContext) error {
 		fileKeyName := getAliasedPath(ctx, content.URL.String())
 		fileContent := contentMessage{
 			Key:  fileKeyName,
-			Time: content.Time.Local(),
+			Time: content.Time.In(globalTimeZone),
 			Size: content.Size,
+			ETag: content.ETag,
 		}
 
 		// Match the incoming content, didn't match return.
@@ -287,22 +459,24 @@ func doFind(ctx *findContext) error {
 		} // For all matching content
 
 		prevKeyName = fileKeyName
+		watch.listed(ctx, fileContent)
 
 		// proceed to either exec, format the output string.
 		if ctx.execCmd != "" {
-			execFind(stringsReplace(ctx.execCmd, fileContent))
+			ctx.execPool.run(execArgs(ctx.execCmd, fileContent))
 			continue
 		}
-		if ctx.printFmt != "" {
-			fileContent.Key = stringsReplace(ctx.printFmt, fileContent)
-		}
 
-		printMsg(findMessage{fileContent})
+		printFind(ctx, fileContent)
 	}
 
-	// Success, notice watch will execute in defer only if enabled and this call
-	// will return after watch is canceled.
-	return nil
+	// If watch is enabled we will wait on the prefix perpetually
+	// for all I/O events until canceled by user, if watch is not enabled
+	// this is a no-op.
+	watch.wait(ctx)
+
+	// Wait for commands still running in parallel.
+	return ctx.execPool.wait()
 }
 
 // stringsReplace - formats the string to remove {} and replace each
@@ -341,22 +515,22 @@ func stringsReplace(args string, fileContent contentMessage) string {
 
 	// replace all instances of {size}
 	if strings.Contains(str, "{size}") {
-		str = strings.Replace(str, "{size}", humanize.IBytes(uint64(fileContent.Size)), -1)
+		str = strings.Replace(str, "{size}", formatSize(fileContent.Size), -1)
 	}
 
 	// replace all instances of {"size"}
 	if strings.Contains(str, `{"size"}`) {
-		str = strings.Replace(str, `{"size"}`, strconv.Quote(humanize.IBytes(uint64(fileContent.Size))), -1)
+		str = strings.Replace(str, `{"size"}`, strconv.Quote(formatSize(fileContent.Size)), -1)
 	}
 
 	// replace all instances of {time}
 	if strings.Contains(str, "{time}") {
-		str = strings.Replace(str, "{time}", fileContent.Time.Format(printDate), -1)
+		str = strings.Replace(str, "{time}", formatDate(fileContent.Time), -1)
 	}
 
 	// replace all instances of {"time"}
 	if strings.Contains(str, `{"time"}`) {
-		str = strings.Replace(str, `{"time"}`, strconv.Quote(fileContent.Time.Format(printDate)), -1)
+		str = strings.Replace(str, `{"time"}`, strconv.Quote(formatDate(fileContent.Time)), -1)
 	}
 
 	// replace all instances of {url}
@@ -423,7 +597,7 @@ func getShareURL(path string) string {
 	clnt, err := newClientFromAlias(targetAlias, targetURLFull)
 	fatalIf(err.Trace(targetAlias, targetURLFull), "Unable to initialize client instance from alias.")
 
-	content, err := clnt.Stat(false, false, nil)
+	content, err := clnt.Stat(globalContext, false, false, nil)
 	fatalIf(err.Trace(targetURLFull, targetAlias), "Unable to lookup file/object.")
 
 	// Skip if its a directory.
@@ -436,7 +610,7 @@ func getShareURL(path string) string {
 	fatalIf(err.Trace(targetAlias, objectURL), "Unable to initialize new client from alias.")
 
 	// Set default expiry for each url (point of no longer valid), to be 7 days
-	shareURL, err := newClnt.ShareDownload(defaultSevenDays)
+	shareURL, err := newClnt.ShareDownload(defaultSevenDays, nil)
 	fatalIf(err.Trace(targetAlias, objectURL), "Unable to generate share url.")
 
 	return shareURL
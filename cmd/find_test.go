@@ -17,7 +17,11 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -452,3 +456,131 @@ func TestGetExitStatus(t *testing.T) {
 		}
 	}
 }
+
+// Tests substitution of --exec arguments, execArgs() function
+func TestExecArgs(t *testing.T) {
+	content := contentMessage{Key: "play/my bucket/a b.txt"}
+	testCases := []struct {
+		command      string
+		expectedArgs []string
+	}{
+		{"ls {}", []string{"ls", "play/my bucket/a b.txt"}},
+		{"mv {}  {dir}/old-{base}", []string{"mv", "play/my bucket/a b.txt", "play/my bucket/old-a b.txt"}},
+		{"", []string{}},
+	}
+	for i, testCase := range testCases {
+		gotArgs := execArgs(testCase.command, content)
+		if strings.Join(gotArgs, "|") != strings.Join(testCase.expectedArgs, "|") {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expectedArgs, gotArgs)
+		}
+	}
+}
+
+// Tests aggregated failures of parallel --exec commands.
+func TestFindExecPool(t *testing.T) {
+	exitCode := func(e error) int {
+		if e == nil {
+			return 0
+		}
+		return e.(interface{ ExitCode() int }).ExitCode()
+	}
+	testCases := []struct {
+		commands           [][]string
+		expectedExitStatus int
+	}{
+		{[][]string{{"true"}, {"true"}}, 0},
+		{[][]string{{"true"}, {"ls", "asdf"}}, globalPartialFailureExitStatus},
+		{[][]string{{"ls", "asdf"}, {"ls", "asdf"}}, 2},
+	}
+	for i, testCase := range testCases {
+		pool := newFindExecPool(2)
+		for _, commandArgs := range testCase.commands {
+			pool.run(commandArgs)
+		}
+		if status := exitCode(pool.wait()); status != testCase.expectedExitStatus {
+			t.Errorf("Test %d: Expected exit status %d, got %d", i+1, testCase.expectedExitStatus, status)
+		}
+	}
+}
+
+// Tests --print formats, formatFind() function
+func TestFormatFind(t *testing.T) {
+	content := contentMessage{
+		Key:  "s3/bucket/dir/a.txt",
+		Size: 2048,
+		Time: time.Unix(2147483647, 0).UTC(),
+		ETag: "d41d8cd9",
+	}
+	testCases := []struct {
+		printFmt    string
+		expectedStr string
+		shouldPass  bool
+	}{
+		{"", "s3/bucket/dir/a.txt", true},
+		{"{base} {size}", "a.txt 2.0 KiB", true},
+		{"{{.Dir}} {{.Base}} {{.Size}} {{.ETag}}", "s3/bucket/dir a.txt 2048 d41d8cd9", true},
+		{`{{humanize .Size}} {{quote .Key}} {{.Time.Format "2006-01-02"}}`, `2.0 KiB "s3/bucket/dir/a.txt" 2038-01-19`, true},
+		{"{{.Missing}}", "", false},
+	}
+	for i, testCase := range testCases {
+		printTmpl, err := parseFindTemplate(testCase.printFmt)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		gotStr, err := formatFind(&findContext{printFmt: testCase.printFmt, printTmpl: printTmpl}, content)
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: Expected success %t, got error %v", i+1, testCase.shouldPass, err)
+		}
+		if gotStr != testCase.expectedStr {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expectedStr, gotStr)
+		}
+	}
+	if _, err := parseFindTemplate("{{.Key"); err == nil {
+		t.Error("Expected an invalid template to fail")
+	}
+}
+
+// Tests listing bounded by --maxdepth, listFind() function
+func TestListFind(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-find-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	for _, file := range []string{"a", "d1/b", "d1/d2/c"} {
+		path := filepath.Join(dir, file)
+		if e = os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte(file), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	clnt, err := fsNew(dir + string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		maxDepth     uint
+		expectedKeys []string
+	}{
+		{0, []string{"a", "d1/b", "d1/d2/c"}},
+		{1, []string{"a", "d1/"}},
+		{2, []string{"a", "d1/b", "d1/d2/"}},
+		{3, []string{"a", "d1/b", "d1/d2/c"}},
+	}
+	for i, testCase := range testCases {
+		var keys []string
+		for content := range listFind(&findContext{clnt: clnt, maxDepth: testCase.maxDepth}) {
+			if content.Err != nil {
+				t.Fatalf("Test %d: %s", i+1, content.Err)
+			}
+			keys = append(keys, filepath.ToSlash(strings.TrimPrefix(content.URL.Path, dir+string(filepath.Separator))))
+		}
+		sort.Strings(keys)
+		if strings.Join(keys, ",") != strings.Join(testCase.expectedKeys, ",") {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedKeys, keys)
+		}
+	}
+}
@@ -38,6 +38,10 @@ var globalFlags = []cli.Flag{
 		Name:  "quiet, q",
 		Usage: "disable progress bar display",
 	},
+	cli.BoolFlag{
+		Name:  "no-progress",
+		Usage: "print only a summary line when done, for cron logs",
+	},
 	cli.BoolFlag{
 		Name:  "no-color",
 		Usage: "disable color theme",
@@ -46,6 +50,18 @@ var globalFlags = []cli.Flag{
 		Name:  "json",
 		Usage: "enable JSON formatted output",
 	},
+	cli.StringFlag{
+		Name:  "output",
+		Usage: "output format. Valid options are '[table, json, yaml, csv]'",
+	},
+	cli.StringFlag{
+		Name:  "humanize",
+		Usage: "'off' prints sizes in bytes and dates in RFC3339. Valid options are '[on, off]'",
+	},
+	cli.BoolFlag{
+		Name:  "utc",
+		Usage: "print dates in UTC, overrides the time zone set in MC_TIME_ZONE",
+	},
 	cli.BoolFlag{
 		Name:  "debug",
 		Usage: "enable debug output",
@@ -54,10 +70,42 @@ var globalFlags = []cli.Flag{
 		Name:  "insecure",
 		Usage: "disable SSL certificate verification",
 	},
+	cli.StringFlag{
+		Name:  "proxy",
+		Usage: "HTTP, HTTPS or SOCKS5 proxy URL, overrides HTTPS_PROXY and configured proxies",
+	},
+	cli.StringSliceFlag{
+		Name:  "header",
+		Usage: "add the HTTP header 'NAME: VALUE' to all requests, can be repeated, overrides the headers of aliases",
+	},
+	cli.BoolFlag{
+		Name:  "requester-pays",
+		Usage: "accept the charges of requests to requester pays buckets",
+	},
+	cli.StringFlag{
+		Name:  "trace-file",
+		Usage: "append a JSON trace of all HTTP requests, with credentials redacted, to a file",
+	},
+	cli.BoolFlag{
+		Name:  "read-only",
+		Usage: "refuse all operations modifying remote data, also enabled by MC_READONLY",
+	},
+	cli.StringFlag{
+		Name:  "timeout",
+		Usage: "cancel the command if it does not complete within DURATION, e.g. 30s or 2h",
+	},
+	cli.BoolFlag{
+		Name:  "no-check-update",
+		Usage: "disable the check for updates, also disabled by MC_NO_UPDATE_CHECK",
+	},
 	cli.BoolFlag{
 		Name:  "no-autocompletion",
 		Usage: "disable automatic install of mc auto-completion",
 	},
+	cli.StringFlag{
+		Name:  "autocompletion",
+		Usage: "install or skip mc auto-completion without asking, remembered in the config file. Valid options are '[install, skip]'",
+	},
 }
 
 // Flags common across all I/O commands such as cp, mirror, stat, pipe etc.
@@ -66,6 +114,18 @@ var ioFlags = []cli.Flag{
 		Name:  "encrypt-key",
 		Usage: "encrypt/decrypt objects (using server-side encryption with customer provided keys)",
 	},
+	cli.StringFlag{
+		Name:  "fs-buffer-size",
+		Usage: "size of the copy buffer for local files, e.g. 1MiB (default: 32KiB)",
+	},
+	cli.BoolFlag{
+		Name:  "fs-direct",
+		Usage: "keep local files out of the page cache, for very large transfers",
+	},
+	cli.BoolFlag{
+		Name:  "windows-compat",
+		Usage: "escape object keys which are not valid Windows file names, and unescape them on upload",
+	},
 }
 
 // registerCmd registers a cli command
@@ -0,0 +1,86 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// formatFlag is shared by all commands able to print their results with
+// a Go template.
+var formatFlag = cli.StringFlag{
+	Name:  "format",
+	Usage: "print each result with a Go template, e.g. '{{.Key}}\\t{{.Size}}'",
+}
+
+// Go template given to --format, nil unless set.
+var globalFormat *template.Template
+
+// Functions available to a Go template given to --format or to the
+// --print of find.
+var formatTemplateFuncs = template.FuncMap{
+	"humanize": func(size int64) string {
+		return humanize.IBytes(uint64(size))
+	},
+	"quote": strconv.Quote,
+	"json": func(v interface{}) (string, error) {
+		b, e := json.Marshal(v)
+		return string(b), e
+	},
+}
+
+// Escapes of tabs and newlines are expanded in --format, as shells do
+// not expand them within quotes.
+var formatEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+// parseFormatTemplate parses the --format Go template.
+func parseFormatTemplate(format string) (*template.Template, *probe.Error) {
+	tmpl, e := template.New("format").Funcs(formatTemplateFuncs).Parse(formatEscapes.Replace(format))
+	if e != nil {
+		return nil, probe.NewError(e).Trace(format)
+	}
+	return tmpl, nil
+}
+
+// formatMsg executes the --format template with a message, the fields
+// of the template are the fields of the message printed as JSON.
+func formatMsg(tmpl *template.Template, msg interface{}) (string, *probe.Error) {
+	var buf bytes.Buffer
+	if e := tmpl.Execute(&buf, msg); e != nil {
+		return "", probe.NewError(e)
+	}
+	return buf.String(), nil
+}
+
+// printFormatted prints a message with the --format template.
+func printFormatted(msg interface{}) {
+	str, err := formatMsg(globalFormat, msg)
+	if err != nil {
+		errorIf(err, "Unable to format the output with the `--format` template.")
+		return
+	}
+	console.Println(str)
+}
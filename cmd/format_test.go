@@ -0,0 +1,69 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatMsg(t *testing.T) {
+	modTime := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	content := contentMessage{Key: "dir/file.txt", Size: 2048, Time: modTime, ETag: "abc"}
+	stat := statMessage{Key: "dir/file.txt", Metadata: map[string]string{"Content-Type": "text/plain"}}
+
+	testCases := []struct {
+		format   string
+		msg      interface{}
+		expected string
+		parseErr bool
+		execErr  bool
+	}{
+		{format: `{{.Key}}\t{{.Size}}`, msg: content, expected: "dir/file.txt\t2048"},
+		{format: `{{humanize .Size}}\n{{.Time.Format "2006-01-02"}}`, msg: content, expected: "2.0 KiB\n2019-07-01"},
+		{format: `{{quote .Key}} {{.ETag}}`, msg: content, expected: `"dir/file.txt" abc`},
+		{format: `{{json .Metadata}}`, msg: stat, expected: `{"Content-Type":"text/plain"}`},
+		{format: `{{index .Metadata "Content-Type"}}`, msg: stat, expected: "text/plain"},
+		{format: `{{.Key`, parseErr: true},
+		{format: `{{.Unknown}}`, msg: content, execErr: true},
+	}
+	for i, testCase := range testCases {
+		tmpl, err := parseFormatTemplate(testCase.format)
+		if testCase.parseErr {
+			if err == nil {
+				t.Errorf("Test %d: expected a parse error for %q", i+1, testCase.format)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		str, err := formatMsg(tmpl, testCase.msg)
+		if testCase.execErr {
+			if err == nil {
+				t.Errorf("Test %d: expected an error for %q", i+1, testCase.format)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if str != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, str)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// +build darwin
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// moveToTrash - move a file to the trash of the user, names already in
+// the trash are numbered like Finder does.
+func moveToTrash(path string) error {
+	absPath, e := filepath.Abs(path)
+	if e != nil {
+		return e
+	}
+	homeDir, e := homedir.Dir()
+	if e != nil {
+		return e
+	}
+	trashDir := filepath.Join(homeDir, ".Trash")
+
+	name := filepath.Base(absPath)
+	ext := filepath.Ext(name)
+	for i := 1; ; i++ {
+		trashName := name
+		if i > 1 {
+			trashName = fmt.Sprintf("%s %d%s", strings.TrimSuffix(name, ext), i, ext)
+		}
+		trashPath := filepath.Join(trashDir, trashName)
+		if _, e = os.Lstat(trashPath); e == nil {
+			continue
+		}
+		return os.Rename(absPath, trashPath)
+	}
+}
@@ -0,0 +1,95 @@
+// +build !windows,!darwin
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// getTrashDir - home trash of the XDG trash specification.
+func getTrashDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "Trash"), nil
+	}
+	homeDir, e := homedir.Dir()
+	if e != nil {
+		return "", e
+	}
+	return filepath.Join(homeDir, ".local", "share", "Trash"), nil
+}
+
+// moveToTrash - move a file to the home trash, along with the info file
+// desktop file managers need to restore it.
+func moveToTrash(path string) error {
+	absPath, e := filepath.Abs(path)
+	if e != nil {
+		return e
+	}
+	trashDir, e := getTrashDir()
+	if e != nil {
+		return e
+	}
+	filesDir := filepath.Join(trashDir, "files")
+	infoDir := filepath.Join(trashDir, "info")
+	for _, dir := range []string{filesDir, infoDir} {
+		if e = os.MkdirAll(dir, 0700); e != nil {
+			return e
+		}
+	}
+
+	name := filepath.Base(absPath)
+	for i := 1; ; i++ {
+		trashName := name
+		if i > 1 {
+			trashName = fmt.Sprintf("%s.%d", name, i)
+		}
+		// Creating the info file exclusively claims the name in the trash.
+		infoPath := filepath.Join(infoDir, trashName+".trashinfo")
+		infoFile, e := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(e) {
+			continue
+		}
+		if e != nil {
+			return e
+		}
+		_, e = fmt.Fprintf(infoFile, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+			(&url.URL{Path: absPath}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+		if ce := infoFile.Close(); e == nil {
+			e = ce
+		}
+		if e == nil {
+			e = os.Rename(absPath, filepath.Join(filesDir, trashName))
+		}
+		if e != nil {
+			os.Remove(infoPath)
+			if linkErr, ok := e.(*os.LinkError); ok && linkErr.Err == syscall.EXDEV {
+				return fmt.Errorf("`%s` is not on the filesystem of the trash `%s`", absPath, trashDir)
+			}
+			return e
+		}
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+// +build !windows,!darwin
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFSTrash(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-trash-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	defer os.Setenv("XDG_DATA_HOME", dataHome)
+	os.Setenv("XDG_DATA_HOME", filepath.Join(root, "data"))
+
+	dir := filepath.Join(root, "dir")
+	if e = os.MkdirAll(filepath.Join(dir, "sub"), 0700); e != nil {
+		t.Fatal(e)
+	}
+	files := []string{filepath.Join(dir, "a b.txt"), filepath.Join(dir, "sub", "a b.txt")}
+	for _, file := range files {
+		if e = ioutil.WriteFile(file, []byte(file), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	clnt, err := fsNew(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentCh := make(chan *clientContent, 4)
+	for _, path := range []string{files[0], files[1], filepath.Join(dir, "sub"), dir} {
+		contentCh <- &clientContent{URL: *newClientURL(path)}
+	}
+	close(contentCh)
+	for err := range clnt.(*fsClient).Trash(contentCh) {
+		t.Fatal(err)
+	}
+	if _, e = os.Stat(dir); !os.IsNotExist(e) {
+		t.Fatalf("expected %s to be removed, got %v", dir, e)
+	}
+
+	trashDir := filepath.Join(root, "data", "Trash")
+	for i, trashName := range []string{"a b.txt", "a b.txt.2"} {
+		data, e := ioutil.ReadFile(filepath.Join(trashDir, "files", trashName))
+		if e != nil {
+			t.Fatal(e)
+		}
+		if string(data) != files[i] {
+			t.Errorf("expected %s in the trash as %s, got %s", files[i], trashName, data)
+		}
+		info, e := ioutil.ReadFile(filepath.Join(trashDir, "info", trashName+".trashinfo"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		path := strings.Replace(files[i], " ", "%20", -1)
+		if !strings.HasPrefix(string(info), "[Trash Info]\nPath="+path+"\nDeletionDate=") {
+			t.Errorf("unexpected trash info for %s: %s", files[i], info)
+		}
+	}
+}
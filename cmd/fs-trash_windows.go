@@ -0,0 +1,75 @@
+// +build windows
+
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var procSHFileOperationW = syscall.NewLazyDLL("shell32.dll").NewProc("SHFileOperationW")
+
+// Constants of SHFileOperationW.
+const (
+	foDelete          = 0x0003
+	fofSilent         = 0x0004
+	fofNoConfirmation = 0x0010
+	fofAllowUndo      = 0x0040
+	fofNoErrorUI      = 0x0400
+)
+
+// shFileOpStruct - SHFILEOPSTRUCTW of the Windows shell.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+// moveToTrash - move a file to the Recycle Bin.
+func moveToTrash(path string) error {
+	absPath, e := filepath.Abs(path)
+	if e != nil {
+		return e
+	}
+	// The list of files is terminated by two null characters.
+	from, e := syscall.UTF16FromString(absPath)
+	if e != nil {
+		return e
+	}
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &append(from, 0)[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofSilent | fofNoErrorUI,
+	}
+	r, _, _ := procSHFileOperationW.Call(uintptr(unsafe.Pointer(&op)))
+	if r != 0 {
+		return fmt.Errorf("unable to move `%s` to the Recycle Bin, error code 0x%x", absPath, r)
+	}
+	if op.fAnyOperationsAborted != 0 {
+		return fmt.Errorf("moving `%s` to the Recycle Bin was aborted", absPath)
+	}
+	return nil
+}
@@ -19,9 +19,16 @@ package cmd
 
 import (
 	"crypto/x509"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
 )
 
 // mc configuration related constants.
@@ -43,19 +50,68 @@ const (
 	globalSharedURLsDataDir    = "share"
 	globalSessionConfigVersion = "8"
 
+	// Cached listings of mirror targets.
+	globalMirrorCacheDir = "cache"
+
 	// Profile directory for dumping profiler outputs.
 	globalProfileDir = "profile"
 
-	// Global error exit status.
+	// Global error exit status, also used for usage errors.
 	globalErrorExitStatus = 1
+
+	// Exit status when a bucket, object or file does not exist.
+	globalNotFoundExitStatus = 2
+
+	// Exit status when the credentials do not permit an operation.
+	globalAccessDeniedExitStatus = 3
+
+	// Exit status when only some of the operations of a command failed.
+	globalPartialFailureExitStatus = 4
+
+	// Exit status when interrupted by SIGINT or SIGTERM, as shells report
+	// commands killed by SIGINT.
+	globalInterruptExitStatus = 130
 )
 
 var (
-	globalQuiet    = false // Quiet flag set via command line
-	globalJSON     = false // Json flag set via command line
-	globalDebug    = false // Debug flag set via command line
-	globalNoColor  = false // No Color flag set via command line
-	globalInsecure = false // Insecure flag set via command line
+	globalQuiet      = false // Quiet flag set via command line
+	globalNoProgress = false // No progress flag set via command line
+	globalJSON       = false // Json flag set via command line
+	globalDebug      = false // Debug flag set via command line
+	globalNoColor    = false // No Color flag set via command line
+	globalInsecure   = false // Insecure flag set via command line
+	globalReadOnly   = false // Read-only flag set via command line or MC_READONLY
+	globalProxy      = ""    // Proxy URL set via command line
+	globalTraceFile  = ""    // HTTP trace file set via command line
+	globalOutput     = ""    // Output format set via command line
+	globalNoHumanize = false // Humanize off set via command line
+
+	// Time zone of printed dates set via command line or MC_TIME_ZONE
+	globalTimeZone = time.Local
+
+	globalFSBufferSize = defaultFSBufferSize // Copy buffer of local files set via command line
+	globalFSDirect     = false               // Bypass of the page cache set via command line
+
+	// Mapping of object keys into Windows compatible file names set via command line
+	globalWindowsCompat = false
+
+	// Unicode normalization of compared keys set via command line
+	globalNormalize = ""
+
+	// Policy for object names unsafe as local paths set via command line
+	globalSanitize = sanitizeSkip
+
+	// Substitutions of the keys of copied objects set via command line
+	globalRename []renameRule
+
+	// Multipart strategy of uploads set via command line
+	globalMultipart multipartConfig
+
+	// Extra HTTP headers of all requests set via command line
+	globalHeaders = http.Header{}
+
+	// Acceptance of the charges of requester pays buckets set via command line
+	globalRequesterPays = false
 
 	// WHEN YOU ADD NEXT GLOBAL FLAG, MAKE SURE TO ALSO UPDATE SESSION CODE AND CODE BELOW.
 )
@@ -69,12 +125,42 @@ var (
 )
 
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
-func setGlobals(quiet, debug, json, noColor, insecure bool) {
-	globalQuiet = globalQuiet || quiet
+func setGlobals(quiet, noProgress, debug, json, noColor, insecure, readOnly, noHumanize bool, proxy, traceFile, output, timeZone string) {
+	// Without progress only the summary of the quiet mode is printed.
+	globalNoProgress = globalNoProgress || noProgress
+	globalQuiet = globalQuiet || quiet || globalNoProgress
 	globalDebug = globalDebug || debug
 	globalJSON = globalJSON || json
-	globalNoColor = globalNoColor || noColor
+	if output != "" {
+		globalOutput = output
+	}
+	switch globalOutput {
+	case outputJSON, outputYAML, outputCSV:
+		// All structured formats are rendered from JSON messages.
+		globalJSON = true
+	case outputTable:
+		globalJSON = false
+	default:
+		if globalJSON {
+			globalOutput = outputJSON
+		}
+	}
+	// NO_COLOR is honored as well, see https://no-color.org
+	globalNoColor = globalNoColor || noColor || os.Getenv("NO_COLOR") != ""
 	globalInsecure = globalInsecure || insecure
+	globalReadOnly = globalReadOnly || readOnly || isReadOnlyEnv()
+	if proxy != "" {
+		globalProxy = proxy
+	}
+	if traceFile != "" {
+		globalTraceFile = traceFile
+	}
+	globalNoHumanize = globalNoHumanize || noHumanize
+	if timeZone != "" {
+		if location, e := time.LoadLocation(timeZone); e == nil {
+			globalTimeZone = location
+		}
+	}
 
 	// Enable debug messages if requested.
 	if globalDebug {
@@ -90,10 +176,140 @@ func setGlobals(quiet, debug, json, noColor, insecure bool) {
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
 func setGlobalsFromContext(ctx *cli.Context) error {
 	quiet := ctx.IsSet("quiet")
+	noProgress := ctx.IsSet("no-progress")
 	debug := ctx.IsSet("debug")
 	json := ctx.IsSet("json")
 	noColor := ctx.IsSet("no-color")
 	insecure := ctx.IsSet("insecure")
-	setGlobals(quiet, debug, json, noColor, insecure)
+	readOnly := ctx.IsSet("read-only")
+	proxy := ctx.String("proxy")
+	traceFile := ctx.String("trace-file")
+	output := strings.ToLower(ctx.String("output"))
+	if !isValidOutput(output) {
+		fatalIf(errInvalidArgument().Trace(output), "Unrecognized output format `"+output+"`. Valid options are `[table, json, yaml, csv]`.")
+	}
+	humanizeOption := strings.ToLower(ctx.String("humanize"))
+	if humanizeOption != "" && humanizeOption != "on" && humanizeOption != "off" {
+		fatalIf(errInvalidArgument().Trace(humanizeOption), "Unrecognized humanize option `"+humanizeOption+"`. Valid options are `[on, off]`.")
+	}
+	noHumanize := humanizeOption == "off"
+	timeZone := os.Getenv("MC_TIME_ZONE")
+	if ctx.IsSet("utc") || ctx.GlobalIsSet("utc") {
+		timeZone = "UTC"
+	}
+	if _, e := time.LoadLocation(timeZone); e != nil {
+		fatalIf(probe.NewError(e).Trace(timeZone), "Unrecognized time zone `"+timeZone+"` in MC_TIME_ZONE.")
+	}
+	setGlobals(quiet, noProgress, debug, json, noColor, insecure, readOnly, noHumanize, proxy, traceFile, output, timeZone)
+
+	// Go template of commands printing results with --format.
+	if format := ctx.String("format"); format != "" {
+		if globalJSON {
+			fatalIf(errInvalidArgument().Trace(format), "Unable to use `--format` with structured output, the template already selects the fields to print.")
+		}
+		var err *probe.Error
+		globalFormat, err = parseFormatTemplate(format)
+		fatalIf(err, "Unable to parse `--format` template.")
+	}
+
+	if timeout := ctx.String("timeout"); timeout != "" {
+		duration, e := time.ParseDuration(timeout)
+		fatalIf(probe.NewError(e).Trace(timeout), "Unable to parse `--timeout "+timeout+"`.")
+		if duration <= 0 {
+			fatalIf(errInvalidArgument().Trace(timeout), "Unable to use `--timeout "+timeout+"`, the timeout must be positive.")
+		}
+		setGlobalTimeout(duration)
+	}
+
+	globalRequesterPays = globalRequesterPays || ctx.IsSet("requester-pays")
+	if values := ctx.StringSlice("header"); len(values) > 0 {
+		headers, err := parseHTTPHeaders(values)
+		fatalIf(err, "Unable to use `--header`, expected 'NAME: VALUE' headers which are not signed, like 'Authorization' or 'X-Amz-*'.")
+		setHeaderGlobals(headers)
+	}
+
+	// Local file I/O flags of I/O commands.
+	var fsBufferSize uint64
+	if bufferSize := ctx.String("fs-buffer-size"); bufferSize != "" {
+		var e error
+		fsBufferSize, e = humanize.ParseBytes(bufferSize)
+		fatalIf(probe.NewError(e).Trace(bufferSize), "Unable to parse `--fs-buffer-size "+bufferSize+"`.")
+		if fsBufferSize < minFSBufferSize || fsBufferSize > maxFSBufferSize {
+			fatalIf(errInvalidArgument().Trace(bufferSize), "Unable to use `--fs-buffer-size "+bufferSize+"`, the buffer size must be between "+
+				humanize.IBytes(minFSBufferSize)+" and "+humanize.IBytes(maxFSBufferSize)+".")
+		}
+	}
+	setFSGlobals(int(fsBufferSize), ctx.IsSet("fs-direct"), ctx.IsSet("windows-compat"))
+
+	// Multipart flags of upload commands.
+	var multipart multipartConfig
+	for _, flag := range []struct {
+		name  string
+		value *uint64
+	}{{"part-size", &multipart.PartSize}, {"multipart-threshold", &multipart.Threshold}} {
+		if size := ctx.String(flag.name); size != "" {
+			var err *probe.Error
+			*flag.value, err = parseUploadSize(size)
+			fatalIf(err, "Unable to use `--"+flag.name+" "+size+"`.")
+		}
+	}
+	multipart.Disabled = ctx.IsSet("disable-multipart")
+	setMultipartGlobals(multipart)
+
+	// Record mutating commands in the audit log and run the hooks of the
+	// command, once all flags are valid.
+	startAudit(ctx)
+	startHooks(ctx)
 	return nil
 }
+
+// setMultipartGlobals - set the global multipart strategy of uploads,
+// zero values keep the current strategy.
+func setMultipartGlobals(multipart multipartConfig) {
+	globalMultipart = globalMultipart.override(multipart)
+}
+
+// setHeaderGlobals - add headers to all requests, replacing the
+// former headers of the same name.
+func setHeaderGlobals(headers http.Header) {
+	for name, value := range headers {
+		globalHeaders[name] = value
+	}
+}
+
+// setFSGlobals - set global states of local file I/O, a buffer size of
+// 0 keeps the current size.
+func setFSGlobals(bufferSize int, direct, windowsCompat bool) {
+	if bufferSize > 0 {
+		globalFSBufferSize = bufferSize
+	}
+	globalFSDirect = globalFSDirect || direct
+	globalWindowsCompat = globalWindowsCompat || windowsCompat
+}
+
+// setGlobalsFromMutatingContext - set global states of commands which
+// modify the server, refused in read-only mode before doing anything.
+func setGlobalsFromMutatingContext(ctx *cli.Context) error {
+	setGlobalsFromContext(ctx)
+	if globalReadOnly {
+		fatalIf(probe.NewError(ReadOnlyMode{URL: ctx.Args().First()}), "Refusing to modify the server.")
+	}
+	return nil
+}
+
+// isReadOnlyEnv - returns true if MC_READONLY is set, any value other
+// than a false boolean enables read-only mode.
+func isReadOnlyEnv() bool {
+	return isEnvEnabled("MC_READONLY")
+}
+
+// isEnvEnabled - returns true if the environment variable is set to any
+// value other than a false boolean.
+func isEnvEnabled(key string) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return false
+	}
+	enabled, e := strconv.ParseBool(value)
+	return e != nil || enabled
+}
@@ -0,0 +1,174 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	// Phases of a command running hooks.
+	hookPre  = "pre"
+	hookPost = "post"
+
+	// Hook scripts are stopped once they run longer than this.
+	hookTimeout = 5 * time.Minute
+
+	// Set for hook scripts, mc commands run by hooks run no hooks.
+	hookPhaseEnv = "MC_HOOK_PHASE"
+)
+
+// hookEvent - the command running a hook, written to the standard input
+// of the hook script. The result is only known by post hooks.
+type hookEvent struct {
+	Phase    string          `json:"phase"`
+	Command  string          `json:"command"`
+	Args     []string        `json:"args"`
+	Targets  []string        `json:"targets,omitempty"`
+	Status   string          `json:"status,omitempty"`
+	ExitCode *int            `json:"exitCode,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Duration time.Duration   `json:"duration,omitempty"`
+	Summary  json.RawMessage `json:"summary,omitempty"`
+}
+
+var (
+	// Post hook of the running command and its event, nil if it has none.
+	globalPostHook     string
+	globalHookEvent    *hookEvent
+	globalHookStart    time.Time
+	globalHookSummary  json.RawMessage
+	globalPostHookOnce sync.Once
+)
+
+// getCommandHook - the hook configured for a command path.
+func getCommandHook(path string) (hookConfigV9, bool) {
+	if path == "" || os.Getenv(hookPhaseEnv) != "" {
+		return hookConfigV9{}, false
+	}
+	config, err := loadMcConfig()
+	if err != nil {
+		return hookConfigV9{}, false
+	}
+	hook, ok := config.Hooks[strings.Replace(path, "/", " ", -1)]
+	return hook, ok
+}
+
+// runHook - run a hook script in a shell, with the event as standard
+// input and in MC_HOOK_* environment variables. The output of the
+// script goes to the standard error, so that JSON output stays parsable.
+func runHook(script string, event hookEvent) *probe.Error {
+	eventBytes, e := json.Marshal(event)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", script)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", script)
+	}
+	cmd.Env = append(os.Environ(),
+		hookPhaseEnv+"="+event.Phase,
+		"MC_HOOK_COMMAND="+event.Command)
+	if event.ExitCode != nil {
+		cmd.Env = append(cmd.Env,
+			"MC_HOOK_STATUS="+event.Status,
+			"MC_HOOK_EXIT_CODE="+strconv.Itoa(*event.ExitCode))
+	}
+	cmd.Stdin = bytes.NewReader(eventBytes)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if e = cmd.Run(); e != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return probe.NewError(fmt.Errorf("%s hook `%s` timed out after %s", event.Phase, script, hookTimeout))
+		}
+		return probe.NewError(fmt.Errorf("%s hook `%s` failed: %s", event.Phase, script, e))
+	}
+	return nil
+}
+
+// startHooks - run the pre hook of the command of a context, a failing
+// pre hook stops the command. The post hook runs once it is done.
+func startHooks(ctx *cli.Context) {
+	path := auditCommandPath(ctx)
+	hook, ok := getCommandHook(path)
+	if !ok {
+		return
+	}
+	event := &hookEvent{
+		Command: strings.Replace(path, "/", " ", -1),
+		Args:    redactAuditArgs(os.Args[1:], commandSecrets(ctx, path)),
+		Targets: auditTargets(ctx.Args()),
+	}
+	if hook.Pre != "" {
+		preEvent := *event
+		preEvent.Phase = hookPre
+		fatalIf(runHook(hook.Pre, preEvent), "Refused to run `"+event.Command+"` by its pre hook.")
+	}
+	if hook.Post != "" {
+		globalPostHook = hook.Post
+		globalHookEvent = event
+		globalHookStart = time.Now()
+	}
+}
+
+// printSummaryMsg - print the summary of a command, which is passed to
+// its post hook as well.
+func printSummaryMsg(msg message) {
+	if globalHookEvent != nil {
+		globalHookSummary = json.RawMessage(msg.JSON())
+	}
+	printMsg(msg)
+}
+
+// finishHooks - run the post hook of the running command with its
+// result, only once as mc may exit from several places.
+func finishHooks(exitCode int, errMsg string) {
+	if globalHookEvent == nil {
+		return
+	}
+	globalPostHookOnce.Do(func() {
+		event := *globalHookEvent
+		event.Phase = hookPost
+		event.ExitCode = &exitCode
+		event.Status = "success"
+		if exitCode != 0 {
+			event.Status = "error"
+		}
+		event.Error = errMsg
+		event.Duration = time.Since(globalHookStart)
+		event.Summary = globalHookSummary
+		errorIf(runHook(globalPostHook, event), "Unable to run the post hook of `"+event.Command+"`.")
+	})
+}
@@ -0,0 +1,89 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestRunHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts of the test need a POSIX shell")
+	}
+	dir, e := ioutil.TempDir("", "mc-hooks-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "event.json")
+
+	exitCode := 2
+	event := hookEvent{Phase: hookPost, Command: "mirror", Args: []string{"mirror", "a", "play/b"}, Status: "error", ExitCode: &exitCode}
+	script := `test "$MC_HOOK_PHASE $MC_HOOK_COMMAND $MC_HOOK_EXIT_CODE" = "post mirror 2" && cat > ` + out
+	if err := runHook(script, event); err != nil {
+		t.Fatal(err)
+	}
+	data, e := ioutil.ReadFile(out)
+	if e != nil {
+		t.Fatal(e)
+	}
+	var got hookEvent
+	if e = json.Unmarshal(data, &got); e != nil {
+		t.Fatal(e)
+	}
+	if got.Command != "mirror" || got.Status != "error" || got.ExitCode == nil || *got.ExitCode != 2 {
+		t.Errorf("unexpected hook event %s", data)
+	}
+
+	err := runHook("exit 3", hookEvent{Phase: hookPre, Command: "rm"})
+	if err == nil || !strings.Contains(err.ToGoError().Error(), "pre hook `exit 3` failed") {
+		t.Errorf("expected a failed pre hook, got %v", err)
+	}
+}
+
+func TestGetCommandHook(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	config := newConfigV9()
+	config.Hooks = map[string]hookConfigV9{
+		"mirror":     {Post: "notify"},
+		"admin heal": {Pre: "check"},
+	}
+	loadMcConfig = func() (*configV9, *probe.Error) { return config, nil }
+
+	if hook, ok := getCommandHook("admin/heal"); !ok || hook.Pre != "check" {
+		t.Errorf("expected the pre hook of admin heal, got %+v", hook)
+	}
+	if _, ok := getCommandHook("cp"); ok {
+		t.Error("cp has no hooks")
+	}
+
+	// mc run by a hook script runs no hooks.
+	os.Setenv(hookPhaseEnv, hookPost)
+	defer os.Unsetenv(hookPhaseEnv)
+	if _, ok := getCommandHook("mirror"); ok {
+		t.Error("hooks must not run within hooks")
+	}
+}
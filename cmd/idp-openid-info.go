@@ -0,0 +1,74 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var idpOpenIDInfoCmd = cli.Command{
+	Name:   "info",
+	Usage:  "show the OpenID Connect configuration",
+	Action: mainIDPOpenIDInfo,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the JWKS URL web identity tokens of MinIO server are validated with.
+     $ {{.HelpName}} myminio
+`,
+}
+
+// checkIDPOpenIDInfoSyntax - validate all the passed arguments
+func checkIDPOpenIDInfoSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "info", 1) // last argument is exit code
+	}
+}
+
+// mainIDPOpenIDInfo is the handle for "mc idp openid info" command.
+func mainIDPOpenIDInfo(ctx *cli.Context) error {
+	checkIDPOpenIDInfoSyntax(ctx)
+
+	console.SetColor("IDPMessage", color.New(color.FgGreen))
+
+	aliasedURL := ctx.Args().Get(0)
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Cannot get a configured admin connection.")
+
+	config, err := getServerConfig(client)
+	fatalIf(err.Trace(aliasedURL), "Cannot get server configuration.")
+
+	printMsg(idpOpenIDMessage{
+		op:          "info",
+		targetAlias: aliasedURL,
+		JWKSURL:     getOpenIDJWKSURL(config),
+	})
+	return nil
+}
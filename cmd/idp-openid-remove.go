@@ -0,0 +1,76 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var idpOpenIDRemoveCmd = cli.Command{
+	Name:   "remove",
+	Usage:  "remove the OpenID Connect configuration",
+	Action: mainIDPOpenIDRemove,
+	Before: setGlobalsFromMutatingContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Stop accepting web identity tokens on MinIO server once it is restarted.
+     $ {{.HelpName}} myminio
+`,
+}
+
+// checkIDPOpenIDRemoveSyntax - validate all the passed arguments
+func checkIDPOpenIDRemoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "remove", 1) // last argument is exit code
+	}
+}
+
+// mainIDPOpenIDRemove is the handle for "mc idp openid remove" command.
+func mainIDPOpenIDRemove(ctx *cli.Context) error {
+	checkIDPOpenIDRemoveSyntax(ctx)
+
+	console.SetColor("IDPMessage", color.New(color.FgGreen))
+
+	aliasedURL := ctx.Args().Get(0)
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Cannot get a configured admin connection.")
+
+	config, err := getServerConfig(client)
+	fatalIf(err.Trace(aliasedURL), "Cannot get server configuration.")
+
+	setOpenIDJWKSURL(config, "")
+	fatalIf(setServerConfig(client, config).Trace(aliasedURL), "Cannot remove the OpenID configuration.")
+
+	printMsg(idpOpenIDMessage{
+		op:          "remove",
+		targetAlias: aliasedURL,
+	})
+	return nil
+}
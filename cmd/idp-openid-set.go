@@ -0,0 +1,89 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var idpOpenIDSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "set the JWKS URL of an OpenID Connect provider",
+	Action: mainIDPOpenIDSet,
+	Before: setGlobalsFromMutatingContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET JWKS_URL
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  MinIO server validates the web identity tokens of its STS API with the keys served at the
+  JWKS URL of the OpenID Connect provider. The server downloads the keys before accepting the
+  new configuration, so the URL must be reachable from the server. The server is restarted
+  for the configuration to take effect, a JWKS URL set by the MINIO_IAM_JWKS_URL environment
+  variable of the server overrides it.
+
+  Client IDs and LDAP are not configurable on MinIO servers of this version.
+
+EXAMPLES:
+  1. Validate web identity tokens of MinIO server with the keys of a Keycloak realm.
+     $ {{.HelpName}} myminio https://keycloak.example.com/auth/realms/myrealm/protocol/openid-connect/certs
+`,
+}
+
+// checkIDPOpenIDSetSyntax - validate all the passed arguments
+func checkIDPOpenIDSetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+	jwksURL := ctx.Args().Get(1)
+	fatalIf(checkJWKSURL(jwksURL), "Invalid JWKS URL `"+jwksURL+"`, an http or https URL is expected.")
+}
+
+// mainIDPOpenIDSet is the handle for "mc idp openid set" command.
+func mainIDPOpenIDSet(ctx *cli.Context) error {
+	checkIDPOpenIDSetSyntax(ctx)
+
+	console.SetColor("IDPMessage", color.New(color.FgGreen))
+
+	args := ctx.Args()
+	aliasedURL, jwksURL := args.Get(0), args.Get(1)
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Cannot get a configured admin connection.")
+
+	config, err := getServerConfig(client)
+	fatalIf(err.Trace(aliasedURL), "Cannot get server configuration.")
+
+	setOpenIDJWKSURL(config, jwksURL)
+	fatalIf(setServerConfig(client, config).Trace(args...), "Cannot set the OpenID configuration.")
+
+	printMsg(idpOpenIDMessage{
+		op:          "set",
+		targetAlias: aliasedURL,
+		JWKSURL:     jwksURL,
+	})
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+var idpOpenIDTestFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "token-file",
+		Usage: "file with a web identity token to exchange for temporary credentials, '-' reads it from STDIN",
+	},
+}
+
+var idpOpenIDTestCmd = cli.Command{
+	Name:   "test",
+	Usage:  "test the OpenID Connect configuration",
+	Action: mainIDPOpenIDTest,
+	Before: setGlobalsFromContext,
+	Flags:  append(idpOpenIDTestFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  The keys served at the configured JWKS URL are downloaded by mc, from where the URL may
+  be reachable differently than from the server. With '--token-file' a web identity token
+  issued by the provider is exchanged for temporary credentials with the STS API of the
+  server, the way applications signing in with the provider do.
+
+EXAMPLES:
+  1. Check that the JWKS URL configured on MinIO server serves keys.
+     $ {{.HelpName}} myminio
+
+  2. Exchange a web identity token, obtained from the provider by a script, for temporary credentials.
+     $ get-id-token.sh | {{.HelpName}} --token-file - myminio
+`,
+}
+
+// stsErrorResponse - error of the STS API.
+type stsErrorResponse struct {
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// exchangeWebIdentityToken - exchange a web identity token for temporary
+// credentials with the AssumeRoleWithWebIdentity call of the STS API.
+func exchangeWebIdentityToken(stsURL string, config *Config, token string) (credentials.WebIdentityResult, *probe.Error) {
+	var result credentials.WebIdentityResult
+	client, err := newIDPHTTPClient(config)
+	if err != nil {
+		return result, err.Trace(stsURL)
+	}
+	u, e := url.Parse(stsURL)
+	if e != nil {
+		return result, probe.NewError(e).Trace(stsURL)
+	}
+	query := url.Values{}
+	query.Set("Action", "AssumeRoleWithWebIdentity")
+	query.Set("WebIdentityToken", token)
+	query.Set("Version", "2011-06-15")
+	u.RawQuery = query.Encode()
+
+	req, e := http.NewRequest(http.MethodPost, u.String(), nil)
+	if e != nil {
+		return result, probe.NewError(e).Trace(stsURL)
+	}
+	req.Header.Set("User-Agent", getUserAgent())
+	resp, e := client.Do(req)
+	if e != nil {
+		return result, probe.NewError(e).Trace(stsURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errResp stsErrorResponse
+		if xml.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Error.Code != "" {
+			return result, probe.NewError(fmt.Errorf("%s: %s", errResp.Error.Code, errResp.Error.Message))
+		}
+		return result, probe.NewError(fmt.Errorf("STS API of %s answered %s", stsURL, resp.Status))
+	}
+	var webIdentityResp credentials.AssumeRoleWithWebIdentityResponse
+	if e = xml.NewDecoder(resp.Body).Decode(&webIdentityResp); e != nil {
+		return result, probe.NewError(e).Trace(stsURL)
+	}
+	return webIdentityResp.Result, nil
+}
+
+// readWebIdentityToken - the token of '--token-file', STDIN for '-'.
+func readWebIdentityToken(tokenFile string) (string, *probe.Error) {
+	var tokenBytes []byte
+	var e error
+	if tokenFile == "-" {
+		tokenBytes, e = ioutil.ReadAll(os.Stdin)
+	} else {
+		tokenBytes, e = ioutil.ReadFile(tokenFile)
+	}
+	if e != nil {
+		return "", probe.NewError(e).Trace(tokenFile)
+	}
+	token := strings.TrimSpace(string(tokenBytes))
+	if token == "" {
+		return "", errInvalidArgument().Trace(tokenFile)
+	}
+	return token, nil
+}
+
+// checkIDPOpenIDTestSyntax - validate all the passed arguments
+func checkIDPOpenIDTestSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "test", 1) // last argument is exit code
+	}
+}
+
+// mainIDPOpenIDTest is the handle for "mc idp openid test" command.
+func mainIDPOpenIDTest(ctx *cli.Context) error {
+	checkIDPOpenIDTestSyntax(ctx)
+
+	console.SetColor("IDPMessage", color.New(color.FgGreen))
+
+	aliasedURL := ctx.Args().Get(0)
+	var token string
+	if tokenFile := ctx.String("token-file"); tokenFile != "" {
+		var err *probe.Error
+		token, err = readWebIdentityToken(tokenFile)
+		fatalIf(err, "Unable to read a web identity token from `"+tokenFile+"`.")
+	}
+
+	_, urlStr, hostCfg, err := expandAlias(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to find alias of `"+aliasedURL+"`.")
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Cannot get a configured admin connection.")
+
+	config, err := getServerConfig(client)
+	fatalIf(err.Trace(aliasedURL), "Cannot get server configuration.")
+
+	msg := idpOpenIDMessage{
+		op:          "test",
+		targetAlias: aliasedURL,
+		JWKSURL:     getOpenIDJWKSURL(config),
+	}
+	if msg.JWKSURL == "" {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "OpenID is not configured on `"+aliasedURL+"`, set it with `mc idp openid set`.")
+	}
+	msg.Keys, err = fetchJWKS(msg.JWKSURL)
+	fatalIf(err, "Unable to download the keys of JWKS URL `"+msg.JWKSURL+"`.")
+
+	if token != "" {
+		result, err := exchangeWebIdentityToken(urlStr, newS3Config(urlStr, hostCfg), token)
+		fatalIf(err, "Unable to exchange the web identity token for temporary credentials.")
+		msg.AccessKey = result.Credentials.AccessKey
+		if expiration := result.Credentials.Expiration; !expiration.IsZero() {
+			msg.Expiration = &expiration
+		}
+	}
+
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,236 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Time given to identity providers and the STS API to answer.
+const idpRequestTimeout = 30 * time.Second
+
+var idpOpenIDCmd = cli.Command{
+	Name:            "openid",
+	Usage:           "configure OpenID Connect single sign-on",
+	HideHelpCommand: true,
+	Action:          mainIDPOpenID,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands: []cli.Command{
+		idpOpenIDSetCmd,
+		idpOpenIDInfoCmd,
+		idpOpenIDTestCmd,
+		idpOpenIDRemoveCmd,
+	},
+}
+
+// mainIDPOpenID is the handle for "mc idp openid" command.
+func mainIDPOpenID(ctx *cli.Context) error {
+	cli.ShowCommandHelp(ctx, ctx.Args().First())
+	return nil
+	// Sub-commands like "set", "test" have their own main.
+}
+
+// idpOpenIDMessage container for the OpenID configuration of a server.
+type idpOpenIDMessage struct {
+	op          string
+	targetAlias string
+	Status      string     `json:"status"`
+	JWKSURL     string     `json:"jwksURL,omitempty"`
+	Keys        int        `json:"keys,omitempty"`
+	AccessKey   string     `json:"accessKey,omitempty"`
+	Expiration  *time.Time `json:"expiration,omitempty"`
+}
+
+func (i idpOpenIDMessage) String() string {
+	restart := "Please restart your server with `mc admin service restart " + i.targetAlias + "`."
+	switch i.op {
+	case "set":
+		return console.Colorize("IDPMessage", "OpenID JWKS URL of `"+i.targetAlias+"` set to `"+i.JWKSURL+"`. "+restart)
+	case "remove":
+		return console.Colorize("IDPMessage", "Removed the OpenID configuration of `"+i.targetAlias+"`. "+restart)
+	case "info":
+		if i.JWKSURL == "" {
+			return console.Colorize("IDPMessage", "OpenID is not configured on `"+i.targetAlias+"`.")
+		}
+		return console.Colorize("IDPMessage", "JWKS URL: "+i.JWKSURL)
+	case "test":
+		msg := fmt.Sprintf("JWKS URL `%s` serves %d keys.", i.JWKSURL, i.Keys)
+		if i.AccessKey != "" {
+			msg += fmt.Sprintf(" Token exchanged for temporary access key `%s`", i.AccessKey)
+			if i.Expiration != nil {
+				msg += " expiring " + formatDate(*i.Expiration)
+			}
+			msg += "."
+		}
+		return console.Colorize("IDPMessage", msg)
+	}
+	return ""
+}
+
+func (i idpOpenIDMessage) JSON() string {
+	i.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(i, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// newIDPHTTPClient - HTTP client for identity providers and the STS API,
+// with the proxy and TLS settings of the given config.
+func newIDPHTTPClient(config *Config) (*http.Client, *probe.Error) {
+	proxyFunc, err := getProxyFunc(config)
+	if err != nil {
+		return nil, err.Trace(config.Proxy)
+	}
+	tlsConfig := &tls.Config{RootCAs: globalRootCAs, InsecureSkipVerify: config.Insecure}
+	if err = setHostTLSConfig(tlsConfig, config); err != nil {
+		return nil, err.Trace(config.HostURL)
+	}
+	return &http.Client{
+		Timeout: idpRequestTimeout,
+		Transport: &http.Transport{
+			Proxy:           proxyFunc,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// getServerConfig - the configuration of a server as generic JSON, so
+// that settings unknown to mc are written back untouched.
+func getServerConfig(client *madmin.AdminClient) (map[string]interface{}, *probe.Error) {
+	configBytes, e := client.GetConfig()
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(configBytes))
+	// Keep numbers as they are, not as floats.
+	decoder.UseNumber()
+	var config map[string]interface{}
+	if e = decoder.Decode(&config); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return config, nil
+}
+
+// setServerConfig - write back a configuration read by getServerConfig.
+func setServerConfig(client *madmin.AdminClient, config map[string]interface{}) *probe.Error {
+	configBytes, e := json.Marshal(config)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return probe.NewError(client.SetConfig(bytes.NewReader(configBytes)))
+}
+
+// getOpenIDJWKSURL - the JWKS URL of the 'openid' section of a server
+// configuration, empty when OpenID is not configured.
+func getOpenIDJWKSURL(config map[string]interface{}) string {
+	openID, _ := config["openid"].(map[string]interface{})
+	jwks, _ := openID["jwks"].(map[string]interface{})
+	jwksURL, _ := jwks["url"].(string)
+	return jwksURL
+}
+
+// setOpenIDJWKSURL - set the JWKS URL of the 'openid' section of a
+// server configuration, an empty URL disables OpenID.
+func setOpenIDJWKSURL(config map[string]interface{}, jwksURL string) {
+	openID, ok := config["openid"].(map[string]interface{})
+	if !ok {
+		openID = make(map[string]interface{})
+		config["openid"] = openID
+	}
+	jwks, ok := openID["jwks"].(map[string]interface{})
+	if !ok {
+		jwks = make(map[string]interface{})
+		openID["jwks"] = jwks
+	}
+	if jwksURL == "" {
+		jwks["url"] = nil
+		return
+	}
+	jwks["url"] = jwksURL
+}
+
+// checkJWKSURL - a JWKS URL is an absolute http or https URL.
+func checkJWKSURL(jwksURL string) *probe.Error {
+	u, e := url.Parse(jwksURL)
+	if e != nil {
+		return probe.NewError(e).Trace(jwksURL)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errInvalidArgument().Trace(jwksURL)
+	}
+	return nil
+}
+
+// jsonWebKeySet - the keys of a JWKS document, only the fields needed
+// to recognise the keys MinIO server can validate tokens with.
+type jsonWebKeySet struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+	} `json:"keys"`
+}
+
+// fetchJWKS - download the JWKS document of an identity provider the way
+// MinIO server does, returning the number of keys it serves.
+func fetchJWKS(jwksURL string) (int, *probe.Error) {
+	client, err := newIDPHTTPClient(&Config{Proxy: globalProxy, Insecure: globalInsecure, HostURL: jwksURL})
+	if err != nil {
+		return 0, err.Trace(jwksURL)
+	}
+	req, e := http.NewRequest(http.MethodGet, jwksURL, nil)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(jwksURL)
+	}
+	req.Header.Set("User-Agent", getUserAgent())
+	resp, e := client.Do(req)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(jwksURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, probe.NewError(fmt.Errorf("JWKS URL %s answered %s", jwksURL, resp.Status))
+	}
+	var keySet jsonWebKeySet
+	if e = json.NewDecoder(resp.Body).Decode(&keySet); e != nil {
+		return 0, probe.NewError(fmt.Errorf("JWKS URL %s serves no JSON web key set: %s", jwksURL, e))
+	}
+	if len(keySet.Keys) == 0 {
+		return 0, probe.NewError(fmt.Errorf("JWKS URL %s serves no keys", jwksURL))
+	}
+	for _, key := range keySet.Keys {
+		switch strings.ToUpper(key.Kty) {
+		case "RSA", "EC":
+		default:
+			return 0, probe.NewError(fmt.Errorf("JWKS URL %s serves key `%s` of unsupported type `%s`", jwksURL, key.Kid, key.Kty))
+		}
+	}
+	return len(keySet.Keys), nil
+}
@@ -0,0 +1,159 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenIDJWKSURL(t *testing.T) {
+	configJSON := `{"version":"33","region":"","worm":"off","cache":{"expiry":90},"openid":{"jwks":{"url":null}}}`
+	decoder := json.NewDecoder(strings.NewReader(configJSON))
+	decoder.UseNumber()
+	var config map[string]interface{}
+	if err := decoder.Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+	if jwksURL := getOpenIDJWKSURL(config); jwksURL != "" {
+		t.Fatalf("expected no JWKS URL, got %q", jwksURL)
+	}
+
+	jwksURL := "https://idp.example.com/certs"
+	setOpenIDJWKSURL(config, jwksURL)
+	if got := getOpenIDJWKSURL(config); got != jwksURL {
+		t.Fatalf("expected %q, got %q", jwksURL, got)
+	}
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Settings unknown to mc are kept as they are.
+	for _, expected := range []string{`"version":"33"`, `"worm":"off"`, `"cache":{"expiry":90}`, `"jwks":{"url":"https://idp.example.com/certs"}`} {
+		if !bytes.Contains(configBytes, []byte(expected)) {
+			t.Errorf("expected %s in %s", expected, configBytes)
+		}
+	}
+
+	setOpenIDJWKSURL(config, "")
+	if got := getOpenIDJWKSURL(config); got != "" {
+		t.Errorf("expected no JWKS URL, got %q", got)
+	}
+
+	// Configurations without an 'openid' section get one.
+	config = map[string]interface{}{"version": "33"}
+	setOpenIDJWKSURL(config, jwksURL)
+	if got := getOpenIDJWKSURL(config); got != jwksURL {
+		t.Errorf("expected %q, got %q", jwksURL, got)
+	}
+}
+
+func TestCheckJWKSURL(t *testing.T) {
+	testCases := []struct {
+		jwksURL string
+		valid   bool
+	}{
+		{"https://idp.example.com/certs", true},
+		{"http://localhost:8080/auth/realms/minio/protocol/openid-connect/certs", true},
+		{"idp.example.com/certs", false},
+		{"ftp://idp.example.com/certs", false},
+		{"https://", false},
+	}
+	for i, testCase := range testCases {
+		if err := checkJWKSURL(testCase.jwksURL); (err == nil) != testCase.valid {
+			t.Errorf("Test %d: expected valid %v, got %v", i+1, testCase.valid, err)
+		}
+	}
+}
+
+func TestFetchJWKS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/certs":
+			fmt.Fprint(w, `{"keys":[{"kid":"a","kty":"RSA","n":"x","e":"AQAB"},{"kid":"b","kty":"EC","crv":"P-256"}]}`)
+		case "/empty":
+			fmt.Fprint(w, `{"keys":[]}`)
+		case "/symmetric":
+			fmt.Fprint(w, `{"keys":[{"kid":"c","kty":"oct","k":"secret"}]}`)
+		case "/html":
+			fmt.Fprint(w, `<html></html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		path string
+		keys int
+		err  string
+	}{
+		{"/certs", 2, ""},
+		{"/empty", 0, "serves no keys"},
+		{"/symmetric", 0, "unsupported type `oct`"},
+		{"/html", 0, "serves no JSON web key set"},
+		{"/missing", 0, "404"},
+	}
+	for i, testCase := range testCases {
+		keys, err := fetchJWKS(server.URL + testCase.path)
+		if testCase.err == "" {
+			if err != nil {
+				t.Errorf("Test %d: unexpected error %s", i+1, err)
+			}
+		} else if err == nil || !strings.Contains(err.ToGoError().Error(), testCase.err) {
+			t.Errorf("Test %d: expected error %q, got %v", i+1, testCase.err, err)
+		}
+		if keys != testCase.keys {
+			t.Errorf("Test %d: expected %d keys, got %d", i+1, testCase.keys, keys)
+		}
+	}
+}
+
+func TestExchangeWebIdentityToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Method != http.MethodPost || query.Get("Action") != "AssumeRoleWithWebIdentity" || query.Get("Version") != "2011-06-15" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if query.Get("WebIdentityToken") != "good-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><Error><Type></Type><Code>InvalidParameterValue</Code><Message>Token is expired</Message></Error></ErrorResponse>`)
+			return
+		}
+		fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleWithWebIdentityResult><Credentials><AccessKeyId>TEMPACCESSKEY</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><Expiration>2019-07-01T12:00:00Z</Expiration><SessionToken>session</SessionToken></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`)
+	}))
+	defer server.Close()
+
+	result, err := exchangeWebIdentityToken(server.URL, &Config{HostURL: server.URL}, "good-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Credentials.AccessKey != "TEMPACCESSKEY" || result.Credentials.Expiration.IsZero() {
+		t.Errorf("unexpected credentials %+v", result.Credentials)
+	}
+
+	_, err = exchangeWebIdentityToken(server.URL, &Config{HostURL: server.URL}, "expired-token")
+	if err == nil || !strings.Contains(err.ToGoError().Error(), "InvalidParameterValue: Token is expired") {
+		t.Errorf("expected the STS error, got %v", err)
+	}
+}
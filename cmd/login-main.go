@@ -0,0 +1,282 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Temporary credentials are refreshed this long before they expire.
+const loginRefreshMargin = time.Minute
+
+var loginFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "issuer",
+		Usage: "issuer URL of the OpenID Connect provider, remembered by the alias",
+	},
+	cli.StringFlag{
+		Name:  "client-id",
+		Usage: "client ID of mc registered with the provider, remembered by the alias",
+	},
+	cli.StringFlag{
+		Name:  "scope",
+		Usage: "scopes requested from the provider, space separated (default: \"openid\")",
+	},
+}
+
+var loginCmd = cli.Command{
+	Name:   "login",
+	Usage:  "sign in to an alias with an OpenID Connect provider",
+	Action: mainLogin,
+	Before: setGlobalsFromContext,
+	Flags:  append(loginFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Sign in with a code shown on a page of the provider, from a browser on any device. The web
+  identity token of the provider is exchanged for temporary credentials with the STS API of
+  the server, which are stored in the alias instead of long-lived keys. The provider must
+  support the device authorization grant, and the server must be configured for it with
+  'mc idp openid set'.
+
+  Temporary credentials are refreshed with the refresh token of the provider once they
+  expire. When the provider issues no refresh token, or the refresh token expired, sign in
+  again with the same command.
+
+EXAMPLES:
+  1. Sign in to the alias 'myminio' with a Keycloak realm, where 'mc' is the client ID registered for mc.
+     $ {{.HelpName}} --issuer https://keycloak.example.com/auth/realms/myrealm --client-id mc myminio
+
+  2. Sign in to the alias 'myminio' again, with the provider it remembers.
+     $ {{.HelpName}} myminio
+`,
+}
+
+// loginMessage container for the temporary credentials of an alias.
+type loginMessage struct {
+	Status     string    `json:"status"`
+	Alias      string    `json:"alias"`
+	AccessKey  string    `json:"accessKey"`
+	Expiration time.Time `json:"expiration"`
+	Refresh    bool      `json:"refresh"`
+}
+
+func (l loginMessage) String() string {
+	msg := fmt.Sprintf("Signed in to `%s` with temporary access key `%s`, valid until %s", l.Alias, l.AccessKey, formatDate(l.Expiration))
+	if l.Refresh {
+		return console.Colorize("Login", msg+" and refreshed once it expires.")
+	}
+	return console.Colorize("Login", msg+", the provider issued no refresh token to renew it.")
+}
+
+func (l loginMessage) JSON() string {
+	l.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(l, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// isExpiring - returns true if the temporary credentials of a login
+// expire within loginRefreshMargin.
+func (l *hostLoginV9) isExpiring() bool {
+	return l != nil && time.Now().Add(loginRefreshMargin).After(l.Expiration)
+}
+
+// withoutLoginSession - the host without the temporary credentials and
+// refresh token of its login.
+func (h hostConfigV9) withoutLoginSession() hostConfigV9 {
+	if h.Login == nil {
+		return h
+	}
+	login := *h.Login
+	login.RefreshToken = ""
+	login.Expiration = time.Time{}
+	h.Login = &login
+	h.AccessKey, h.SecretKey, h.SessionToken = "", "", ""
+	return h
+}
+
+// newLoginHTTPClient - HTTP client for the provider of a host.
+func newLoginHTTPClient(hostCfg *hostConfigV9, endpoint string) (*http.Client, *probe.Error) {
+	proxy := hostCfg.Proxy
+	if globalProxy != "" {
+		proxy = globalProxy
+	}
+	return newIDPHTTPClient(&Config{Proxy: proxy, Insecure: globalInsecure, HostURL: endpoint})
+}
+
+// storeLoginCredentials - exchange a web identity token for temporary
+// credentials and store them in the host.
+func storeLoginCredentials(hostCfg *hostConfigV9, token oidcTokenResponse) *probe.Error {
+	result, err := exchangeWebIdentityToken(hostCfg.URL, newS3Config(hostCfg.URL, hostCfg), token.webIdentityToken())
+	if err != nil {
+		return err.Trace(hostCfg.URL)
+	}
+	hostCfg.AccessKey = result.Credentials.AccessKey
+	hostCfg.SecretKey = result.Credentials.SecretKey
+	hostCfg.SessionToken = result.Credentials.SessionToken
+	hostCfg.Login.Expiration = result.Credentials.Expiration
+	// Providers rotating refresh tokens issue a new one on every refresh.
+	if token.RefreshToken != "" {
+		hostCfg.Login.RefreshToken = token.RefreshToken
+	}
+	return nil
+}
+
+// saveHostConfig - replace the host of an alias in the config file.
+func saveHostConfig(alias string, hostCfg hostConfigV9) *probe.Error {
+	return updateMcConfig(func(mcCfg *configV9) {
+		mcCfg.Hosts[alias] = hostCfg
+	}).Trace(alias)
+}
+
+// All refreshes of temporary credentials are done one at a time, so
+// that parallel requests refresh an alias only once.
+var loginMutex sync.Mutex
+
+// refreshLogin - refresh the expiring temporary credentials of an alias
+// with the refresh token of its login.
+func refreshLogin(alias string) (*hostConfigV9, *probe.Error) {
+	loginMutex.Lock()
+	defer loginMutex.Unlock()
+
+	// Another request may have refreshed the alias meanwhile.
+	hostCfg, err := getHostConfig(alias)
+	if err != nil {
+		return nil, err.Trace(alias)
+	}
+	if !hostCfg.Login.isExpiring() {
+		return hostCfg, nil
+	}
+	if hostCfg.Login.RefreshToken == "" || hostCfg.Login.TokenEndpoint == "" {
+		return nil, errLoginExpired(alias).Trace(alias)
+	}
+
+	client, err := newLoginHTTPClient(hostCfg, hostCfg.Login.TokenEndpoint)
+	if err != nil {
+		return nil, err.Trace(alias)
+	}
+	token, err := refreshOIDCToken(client, hostCfg.Login.TokenEndpoint, hostCfg.Login.ClientID, hostCfg.Login.RefreshToken)
+	if err != nil {
+		errorIf(err.Trace(alias), "Unable to refresh the login of `"+alias+"`.")
+		return nil, errLoginExpired(alias).Trace(alias)
+	}
+	login := *hostCfg.Login
+	hostCfg.Login = &login
+	if err = storeLoginCredentials(hostCfg, token); err != nil {
+		return nil, err.Trace(alias)
+	}
+	if err = saveHostConfig(alias, *hostCfg); err != nil {
+		return nil, err.Trace(alias)
+	}
+	return hostCfg, nil
+}
+
+// checkLoginSyntax - validate all the passed arguments
+func checkLoginSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "login", 1) // last argument is exit code
+	}
+	alias, _ := url2Alias(ctx.Args().Get(0))
+	if hostCfg, _ := getEnvHostConfig(alias); hostCfg != nil {
+		fatalIf(errInvalidArgument().Trace(alias), "Unable to sign in to alias `"+alias+"` defined by the `"+mcEnvHostPrefix+alias+"` environment variable.")
+	}
+	if issuer := ctx.String("issuer"); issuer != "" {
+		fatalIf(checkJWKSURL(issuer), "Invalid issuer `"+issuer+"`, an http or https URL is expected.")
+	}
+}
+
+// mainLogin is the handle for "mc login" command.
+func mainLogin(ctx *cli.Context) error {
+	checkLoginSyntax(ctx)
+
+	console.SetColor("Login", color.New(color.FgGreen, color.Bold))
+	console.SetColor("LoginCode", color.New(color.FgYellow, color.Bold))
+
+	alias, _ := url2Alias(ctx.Args().Get(0))
+	hostCfg, err := getHostConfig(alias)
+	fatalIf(err.Trace(alias), "Unable to find alias `"+alias+"`.")
+
+	login := hostLoginV9{Scope: "openid"}
+	if hostCfg.Login != nil {
+		login = *hostCfg.Login
+	}
+	if ctx.IsSet("issuer") {
+		login.Issuer = ctx.String("issuer")
+	}
+	if ctx.IsSet("client-id") {
+		login.ClientID = ctx.String("client-id")
+	}
+	if ctx.IsSet("scope") {
+		login.Scope = ctx.String("scope")
+	}
+	if login.Issuer == "" || login.ClientID == "" {
+		fatalIf(errInvalidArgument().Trace(alias), "Unable to sign in to `"+alias+"`, `--issuer` and `--client-id` of the provider are needed.")
+	}
+
+	client, err := newLoginHTTPClient(hostCfg, login.Issuer)
+	fatalIf(err.Trace(alias), "Unable to sign in to `"+alias+"`.")
+
+	provider, err := discoverOIDCProvider(client, login.Issuer)
+	fatalIf(err.Trace(login.Issuer), "Unable to find the endpoints of the provider `"+login.Issuer+"`.")
+	login.TokenEndpoint = provider.TokenEndpoint
+
+	auth, err := requestDeviceAuthorization(client, provider, login.ClientID, login.Scope)
+	fatalIf(err.Trace(login.Issuer), "Unable to sign in with the provider `"+login.Issuer+"`.")
+
+	// The instructions go to STDERR, so that JSON output stays parsable.
+	if auth.VerificationURIComplete != "" {
+		fmt.Fprintln(os.Stderr, "Open "+auth.VerificationURIComplete+" in a browser and confirm the code "+console.Colorize("LoginCode", auth.UserCode)+" to sign in to `"+alias+"`.")
+	} else {
+		fmt.Fprintln(os.Stderr, "Open "+auth.VerificationURI+" in a browser and enter the code "+console.Colorize("LoginCode", auth.UserCode)+" to sign in to `"+alias+"`.")
+	}
+
+	token, err := pollDeviceToken(client, provider, login.ClientID, auth)
+	fatalIf(err.Trace(login.Issuer), "Unable to sign in with the provider `"+login.Issuer+"`.")
+
+	login.RefreshToken = ""
+	hostCfg.Login = &login
+	err = storeLoginCredentials(hostCfg, token)
+	fatalIf(err.Trace(alias), "Unable to exchange the web identity token for temporary credentials of `"+alias+"`.")
+	fatalIf(saveHostConfig(alias, *hostCfg), "Unable to save the temporary credentials of `"+alias+"`.")
+
+	printMsg(loginMessage{
+		Alias:      alias,
+		AccessKey:  hostCfg.AccessKey,
+		Expiration: hostCfg.Login.Expiration,
+		Refresh:    hostCfg.Login.RefreshToken != "",
+	})
+	return nil
+}
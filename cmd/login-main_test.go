@@ -0,0 +1,170 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// newTestOIDCServer - an OpenID Connect provider supporting device codes
+// and refresh tokens, which is also the STS API of a MinIO server.
+func newTestOIDCServer() (*httptest.Server, *int32) {
+	var polls, exchanges int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":"%[1]s","device_authorization_endpoint":"%[1]s/device","token_endpoint":"%[1]s/token"}`, server.URL)
+		case "/device":
+			if r.PostForm.Get("client_id") != "mc" || r.PostForm.Get("scope") != "openid" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_client"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"device_code":"device-1","user_code":"ABCD-EFGH","verification_uri":"%s/verify","expires_in":60,"interval":1}`, server.URL)
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			switch r.PostForm.Get("grant_type") {
+			case oidcDeviceCodeGrantType:
+				if atomic.AddInt32(&polls, 1) == 1 {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"error":"authorization_pending"}`)
+					return
+				}
+				fmt.Fprint(w, `{"id_token":"id-1","refresh_token":"refresh-1","expires_in":300}`)
+			case "refresh_token":
+				if r.PostForm.Get("refresh_token") != "refresh-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Token is not active"}`)
+					return
+				}
+				fmt.Fprint(w, `{"id_token":"id-2","refresh_token":"refresh-2","expires_in":300}`)
+			}
+		case "/":
+			n := atomic.AddInt32(&exchanges, 1)
+			expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			fmt.Fprintf(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleWithWebIdentityResult><Credentials><AccessKeyId>ACCESS-%[1]d-%[2]s</AccessKeyId><SecretAccessKey>secret-%[1]d</SecretAccessKey><Expiration>%[3]s</Expiration><SessionToken>session-%[1]d</SessionToken></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`,
+				n, r.URL.Query().Get("WebIdentityToken"), expiration)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return server, &polls
+}
+
+func TestLoginDeviceFlow(t *testing.T) {
+	server, polls := newTestOIDCServer()
+	defer server.Close()
+
+	client := server.Client()
+	provider, err := discoverOIDCProvider(client, server.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.TokenEndpoint != server.URL+"/token" {
+		t.Fatalf("unexpected token endpoint %q", provider.TokenEndpoint)
+	}
+	if _, err = requestDeviceAuthorization(client, provider, "unknown", "openid"); err == nil {
+		t.Fatal("expected an error for an unknown client")
+	}
+	auth, err := requestDeviceAuthorization(client, provider, "mc", "openid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := pollDeviceToken(client, provider, "mc", auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.webIdentityToken() != "id-1" || token.RefreshToken != "refresh-1" || atomic.LoadInt32(polls) != 2 {
+		t.Fatalf("unexpected token %+v after %d polls", token, atomic.LoadInt32(polls))
+	}
+
+	hostCfg := &hostConfigV9{URL: server.URL, API: "S3v4", Login: &hostLoginV9{ClientID: "mc", TokenEndpoint: provider.TokenEndpoint}}
+	if err = storeLoginCredentials(hostCfg, token); err != nil {
+		t.Fatal(err)
+	}
+	if hostCfg.AccessKey != "ACCESS-1-id-1" || hostCfg.SecretKey != "secret-1" || hostCfg.SessionToken != "session-1" || hostCfg.Login.RefreshToken != "refresh-1" {
+		t.Errorf("unexpected credentials %+v", hostCfg)
+	}
+	if hostCfg.Login.isExpiring() {
+		t.Errorf("credentials until %s are not expiring", hostCfg.Login.Expiration)
+	}
+
+	if _, err = refreshOIDCToken(client, provider.TokenEndpoint, "mc", "revoked"); err == nil {
+		t.Error("expected an error for a revoked refresh token")
+	}
+
+	session := hostCfg.withoutLoginSession()
+	if session.AccessKey != "" || session.SecretKey != "" || session.SessionToken != "" || session.Login.RefreshToken != "" || session.Login.ClientID != "mc" {
+		t.Errorf("unexpected host without session %+v %+v", session, session.Login)
+	}
+	if hostCfg.Login.RefreshToken != "refresh-1" {
+		t.Error("the login of the host was changed")
+	}
+}
+
+func TestRefreshLogin(t *testing.T) {
+	server, _ := newTestOIDCServer()
+	defer server.Close()
+
+	savedLoadMcConfig, savedCacheCfgV9 := loadMcConfig, cacheCfgV9
+	defer func() { loadMcConfig, cacheCfgV9 = savedLoadMcConfig, savedCacheCfgV9 }()
+	config := newConfigV9()
+	config.Hosts["sso"] = hostConfigV9{
+		URL: server.URL, AccessKey: "ACCESS-0", SecretKey: "secret-0", SessionToken: "session-0", API: "S3v4", Lookup: "auto",
+		Login: &hostLoginV9{Issuer: server.URL, ClientID: "mc", TokenEndpoint: server.URL + "/token", RefreshToken: "refresh-1", Expiration: time.Now().Add(time.Second)},
+	}
+	config.Hosts["expired"] = hostConfigV9{
+		URL: server.URL, API: "S3v4", Lookup: "auto",
+		Login: &hostLoginV9{Issuer: server.URL, ClientID: "mc", TokenEndpoint: server.URL + "/token", Expiration: time.Now().Add(-time.Hour)},
+	}
+	loadMcConfig = func() (*configV9, *probe.Error) { return config, nil }
+
+	configDir, e := ioutil.TempDir("", "mc-login-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(configDir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(configDir)
+
+	_, urlStr, hostCfg, err := expandAlias("sso/bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if urlStr != server.URL+"/bucket" || hostCfg.AccessKey != "ACCESS-1-id-2" || hostCfg.SessionToken != "session-1" || hostCfg.Login.RefreshToken != "refresh-2" {
+		t.Fatalf("unexpected refreshed host %s %+v", urlStr, hostCfg)
+	}
+	// The refreshed credentials are saved and used from then on.
+	if _, _, hostCfg, err = expandAlias("sso/bucket"); err != nil || hostCfg.AccessKey != "ACCESS-1-id-2" {
+		t.Fatalf("unexpected saved host %+v: %v", hostCfg, err)
+	}
+
+	if _, _, _, err = expandAlias("expired/bucket"); err == nil {
+		t.Error("expected an error for a login without refresh token")
+	}
+}
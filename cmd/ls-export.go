@@ -0,0 +1,237 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+var exportFlag = cli.StringFlag{
+	Name:  "export",
+	Usage: "write an inventory of the objects listed to FILE instead of printing them, as CSV or Parquet by the extension of FILE",
+}
+
+// Rows of a Parquet inventory buffered in memory before they are written
+// as a row group.
+const inventoryRowGroupSize = 32 * 1024 * 1024
+
+// Layout of the times of CSV inventories.
+const inventoryTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// inventoryRecord - an object of an inventory, its key is relative to the
+// listed folder and separated by slashes. Times are in milliseconds since
+// the epoch, in UTC.
+type inventoryRecord struct {
+	Key          string `parquet:"name=key, type=UTF8"`
+	Size         int64  `parquet:"name=size, type=INT64"`
+	ETag         string `parquet:"name=etag, type=UTF8"`
+	LastModified int64  `parquet:"name=last_modified, type=TIMESTAMP_MILLIS"`
+	StorageClass string `parquet:"name=storage_class, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	VersionID    string `parquet:"name=version_id, type=UTF8"`
+}
+
+// Columns of CSV inventories, the names of the Parquet columns.
+var inventoryColumns = []string{"key", "size", "etag", "last_modified", "storage_class", "version_id"}
+
+// inventoryWriter - writes the records of an inventory as they are listed.
+type inventoryWriter interface {
+	Write(record inventoryRecord) error
+	Close() error
+}
+
+// csvInventoryWriter - writes an inventory as CSV with a header.
+type csvInventoryWriter struct {
+	file *os.File
+	buf  *bufio.Writer
+	csv  *csv.Writer
+}
+
+func (w *csvInventoryWriter) Write(record inventoryRecord) error {
+	return w.csv.Write([]string{
+		record.Key,
+		strconv.FormatInt(record.Size, 10),
+		record.ETag,
+		time2Millis(record.LastModified).Format(inventoryTimeFormat),
+		record.StorageClass,
+		record.VersionID,
+	})
+}
+
+func (w *csvInventoryWriter) Close() error {
+	w.csv.Flush()
+	e := w.csv.Error()
+	if e == nil {
+		e = w.buf.Flush()
+	}
+	if ce := w.file.Close(); e == nil {
+		e = ce
+	}
+	return e
+}
+
+// parquetLocalFile - a local file read and written by parquet-go.
+type parquetLocalFile struct {
+	*os.File
+}
+
+func (f parquetLocalFile) Create(name string) (source.ParquetFile, error) {
+	file, e := os.Create(name)
+	if e != nil {
+		return nil, e
+	}
+	return parquetLocalFile{file}, nil
+}
+
+func (f parquetLocalFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = f.Name()
+	}
+	file, e := os.Open(name)
+	if e != nil {
+		return nil, e
+	}
+	return parquetLocalFile{file}, nil
+}
+
+// parquetInventoryWriter - writes an inventory as Parquet, by row groups.
+type parquetInventoryWriter struct {
+	file    *os.File
+	parquet *writer.ParquetWriter
+}
+
+func (w *parquetInventoryWriter) Write(record inventoryRecord) error {
+	return w.parquet.Write(record)
+}
+
+func (w *parquetInventoryWriter) Close() error {
+	e := w.parquet.WriteStop()
+	if ce := w.file.Close(); e == nil {
+		e = ce
+	}
+	return e
+}
+
+// time2Millis - the time of milliseconds since the epoch.
+func time2Millis(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+}
+
+// newInventoryWriter - create the inventory file, as CSV or Parquet by
+// its extension.
+func newInventoryWriter(path string) (inventoryWriter, *probe.Error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".csv" && ext != ".parquet" {
+		return nil, errInvalidArgument().Trace(path)
+	}
+	file, e := os.Create(path)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if ext == ".csv" {
+		buf := bufio.NewWriter(file)
+		w := &csvInventoryWriter{file: file, buf: buf, csv: csv.NewWriter(buf)}
+		if e = w.csv.Write(inventoryColumns); e != nil {
+			file.Close()
+			return nil, probe.NewError(e)
+		}
+		return w, nil
+	}
+	pw, e := writer.NewParquetWriter(parquetLocalFile{file}, new(inventoryRecord), 1)
+	if e != nil {
+		file.Close()
+		return nil, probe.NewError(e)
+	}
+	pw.RowGroupSize = inventoryRowGroupSize
+	return &parquetInventoryWriter{file: file, parquet: pw}, nil
+}
+
+// exportMessage - the objects written to an inventory.
+type exportMessage struct {
+	Status  string `json:"status"`
+	File    string `json:"file"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+// String colorized export message.
+func (e exportMessage) String() string {
+	return fmt.Sprintf("Exported %d objects, %s, to `%s`.", e.Objects, formatSize(e.Size), e.File)
+}
+
+// JSON jsonified export message.
+func (e exportMessage) JSON() string {
+	e.Status = "success"
+	jsonMessageBytes, err := json.MarshalIndent(e, "", " ")
+	fatalIf(probe.NewError(err), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// doListExport - write the objects listed to the inventory, folders are
+// skipped. Listing errors are reported and the listing goes on like 'ls'.
+func doListExport(clnt Client, contentCh <-chan *clientContent, w inventoryWriter, olderThan, newerThan string, msg *exportMessage) error {
+	prefixPath := clnt.GetURL().Path
+	separator := string(clnt.GetURL().Separator)
+	if !strings.HasSuffix(prefixPath, separator) {
+		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
+	}
+	prefixPath = strings.TrimPrefix(filepath.ToSlash(prefixPath), "."+separator)
+	fs := &failureStatus{}
+	for content := range contentCh {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			fs.fail(content.Err)
+			continue
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		if olderThan != "" && isOlder(content.Time, olderThan) {
+			continue
+		}
+		if newerThan != "" && isNewer(content.Time, newerThan) {
+			continue
+		}
+		record := inventoryRecord{
+			Key:          strings.TrimPrefix(filepath.ToSlash(content.URL.Path), prefixPath),
+			Size:         content.Size,
+			ETag:         strings.Trim(content.ETag, "\""),
+			LastModified: content.Time.UnixNano() / int64(time.Millisecond),
+			StorageClass: content.StorageClass,
+			VersionID:    content.VersionID,
+		}
+		e := w.Write(record)
+		fatalIf(probe.NewError(e).Trace(msg.File), "Unable to write the inventory.")
+		msg.Objects++
+		msg.Size += content.Size
+		fs.success()
+	}
+	return fs.exitError()
+}
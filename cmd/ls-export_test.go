@@ -0,0 +1,167 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// readInventory - the records of a CSV or Parquet inventory.
+func readInventory(t *testing.T, path string) []inventoryRecord {
+	if strings.HasSuffix(path, ".csv") {
+		f, e := os.Open(path)
+		if e != nil {
+			t.Fatal(e)
+		}
+		defer f.Close()
+		rows, e := csv.NewReader(f).ReadAll()
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(rows) == 0 || !reflect.DeepEqual(rows[0], inventoryColumns) {
+			t.Fatalf("Expected the header %v, got %v", inventoryColumns, rows)
+		}
+		var records []inventoryRecord
+		for _, row := range rows[1:] {
+			var size int64
+			for _, c := range row[1] {
+				size = size*10 + int64(c-'0')
+			}
+			modTime, e := time.Parse(inventoryTimeFormat, row[3])
+			if e != nil {
+				t.Fatal(e)
+			}
+			records = append(records, inventoryRecord{row[0], size, row[2], modTime.UnixNano() / int64(time.Millisecond), row[4], row[5]})
+		}
+		return records
+	}
+	f, e := os.Open(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+	pr, e := reader.NewParquetReader(parquetLocalFile{f}, new(inventoryRecord), 1)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer pr.ReadStop()
+	defer f.Close()
+	records := make([]inventoryRecord, pr.GetNumRows())
+	if e = pr.Read(&records); e != nil {
+		t.Fatal(e)
+	}
+	return records
+}
+
+func TestListExport(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		return newConfigV9(), nil
+	}
+
+	root, e := ioutil.TempDir("", "mc-export-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	source := filepath.Join(root, "source")
+	modTime := time.Date(2019, 10, 1, 12, 30, 0, 0, time.UTC)
+	for name, content := range map[string]string{"a,b.txt": "a", "dir/c": "cc"} {
+		path := filepath.Join(source, name)
+		if e = os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte(content), 0600); e != nil {
+			t.Fatal(e)
+		}
+		if e = os.Chtimes(path, modTime, modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/bucket/" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>` +
+			`<Contents><Key>logs/app.log</Key><Size>10</Size><ETag>"abc"</ETag><LastModified>2019-10-01T12:30:00.000Z</LastModified><StorageClass>STANDARD_IA</StorageClass></Contents>` +
+			`<Contents><Key>logs/2019/old.log</Key><Size>20</Size><ETag>"def"</ETag><LastModified>2019-10-01T12:30:00.000Z</LastModified><StorageClass>GLACIER</StorageClass></Contents>` +
+			`</ListBucketResult>`))
+	}))
+	defer server.Close()
+	os.Setenv("MC_HOST_exporttest", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+	defer os.Unsetenv("MC_HOST_exporttest")
+
+	millis := modTime.UnixNano() / int64(time.Millisecond)
+	testCases := []struct {
+		target  string
+		records []inventoryRecord
+	}{
+		{source + string(filepath.Separator), []inventoryRecord{
+			{Key: "a,b.txt", Size: 1, LastModified: millis},
+			{Key: "dir/c", Size: 2, LastModified: millis},
+		}},
+		{"exporttest/bucket/logs/", []inventoryRecord{
+			{Key: "app.log", Size: 10, ETag: "abc", LastModified: millis, StorageClass: "STANDARD_IA"},
+			{Key: "2019/old.log", Size: 20, ETag: "def", LastModified: millis, StorageClass: "GLACIER"},
+		}},
+	}
+	for i, testCase := range testCases {
+		for _, ext := range []string{".csv", ".parquet"} {
+			path := filepath.Join(root, "inventory"+ext)
+			w, err := newInventoryWriter(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clnt, err := newClient(testCase.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := exportMessage{File: path}
+			if e = doListExport(clnt, clnt.List(globalContext, true, false, DirNone), w, "", "", &msg); e != nil {
+				t.Fatalf("Test %d: Unexpected error %s", i+1, e)
+			}
+			if e = w.Close(); e != nil {
+				t.Fatal(e)
+			}
+			records := readInventory(t, path)
+			if !reflect.DeepEqual(records, testCase.records) {
+				t.Errorf("Test %d %s: Expected %+v, got %+v", i+1, ext, testCase.records, records)
+			}
+			if msg.Objects != int64(len(testCase.records)) {
+				t.Errorf("Test %d %s: Expected %d objects, got %d", i+1, ext, len(testCase.records), msg.Objects)
+			}
+		}
+	}
+
+	if _, err := newInventoryWriter(filepath.Join(root, "inventory.txt")); err == nil {
+		t.Error("Expected an inventory without a known extension to fail")
+	}
+}
@@ -17,11 +17,14 @@
 package cmd
 
 import (
+	"path/filepath"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/ioutils"
+	"github.com/minio/mc/pkg/probe"
 )
 
 // ls specific flags.
@@ -35,6 +38,30 @@ var (
 			Name:  "incomplete, I",
 			Usage: "list incomplete uploads",
 		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "list objects older than L days, M hours and N minutes",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "list objects newer than L days, M hours and N minutes",
+		},
+		cli.StringFlag{
+			Name:  "delimiter",
+			Usage: "group keys into folders by DELIMITER instead of '/', on object storage",
+		},
+		cli.BoolFlag{
+			Name:  "no-delimiter",
+			Usage: "list all keys starting with TARGET as a key prefix, without folders, on object storage",
+		},
+		cli.BoolFlag{
+			Name:  "checksum-manifest",
+			Usage: "print the name, size and checksum of objects as JSON lines, reading every object",
+		},
+		hashFlag,
+		exportFlag,
+		rewindFlag,
+		formatFlag,
 	}
 )
 
@@ -73,6 +100,36 @@ EXAMPLES:
    6. List incomplete (previously failed) uploads of objects on Amazon S3.
       $ {{.HelpName}} --incomplete s3/mybucket
 
+   7. List incomplete uploads started more than 7 days ago, with their part counts and sizes.
+      $ {{.HelpName}} --incomplete --recursive --older-than 7d s3/mybucket
+
+   8. List objects modified in the last 2 hours.
+      $ {{.HelpName}} --recursive --newer-than 2h s3/mybucket
+
+   9. List the contents of a versioned bucket as they were 7 days ago.
+      $ {{.HelpName}} --recursive --rewind 7d s3/mybucket
+
+  10. List the names and sizes in bytes of all objects in mybucket, separated by a tab.
+      $ {{.HelpName}} --recursive --format '{{"{{"}}.Key{{"}}"}}\t{{"{{"}}.Size{{"}}"}}' s3/mybucket
+
+  11. Write the manifest of a data delivery, to check it later with 'mc verify --manifest'.
+      $ {{.HelpName}} --recursive --checksum-manifest s3/deliveries/2019-10/ > delivery.manifest
+
+  12. List the keys of mybucket starting with 'logs/2019-10', including keys such as 'logs/2019-10-01/app.log'.
+      $ {{.HelpName}} --no-delimiter s3/mybucket/logs/2019-10
+
+  13. List the keys of mybucket named with ':' as the folder separator, such as 'tenant:42:report.csv', by folder.
+      $ {{.HelpName}} --delimiter ':' s3/mybucket/tenant:
+
+  14. Write the manifest of a local dataset with xxh64 sums, faster to compute than SHA-256 on NVMe drives.
+      $ {{.HelpName}} --recursive --checksum-manifest --hash xxh64 /mnt/nvme/dataset/ > dataset.manifest
+
+  15. Write an inventory of all objects of mybucket with their sizes, ETags, times and storage classes to 'inventory.csv'.
+      $ {{.HelpName}} --recursive --export inventory.csv s3/mybucket
+
+  16. Write an inventory of the objects of a versioned bucket as they were 7 days ago, with their version ids, as Parquet.
+      $ {{.HelpName}} --recursive --rewind 7d --export inventory.parquet s3/mybucket
+
 `,
 }
 
@@ -87,12 +144,63 @@ func checkListSyntax(ctx *cli.Context) {
 			fatalIf(errInvalidArgument().Trace(args...), "Unable to validate empty argument.")
 		}
 	}
+	if ctx.Bool("checksum-manifest") && ctx.Bool("incomplete") {
+		fatalIf(errInvalidArgument().Trace(args...), "`--checksum-manifest` cannot be combined with `--incomplete`.")
+	}
+	if ctx.IsSet("hash") && !ctx.Bool("checksum-manifest") {
+		fatalIf(errInvalidArgument().Trace(args...), "`--hash` needs `--checksum-manifest`.")
+	}
+	if export := ctx.String("export"); export != "" {
+		for _, flag := range []string{"incomplete", "checksum-manifest"} {
+			if ctx.Bool(flag) {
+				fatalIf(errInvalidArgument().Trace(args...), "`--export` cannot be combined with `--"+flag+"`.")
+			}
+		}
+		if ctx.String("format") != "" {
+			fatalIf(errInvalidArgument().Trace(args...), "`--export` cannot be combined with `--format`.")
+		}
+		if ext := strings.ToLower(filepath.Ext(export)); ext != ".csv" && ext != ".parquet" {
+			fatalIf(errInvalidArgument().Trace(export), "Unable to export to `"+export+"`, the file must end with `.csv` or `.parquet`.")
+		}
+	}
+	getChecksumAlgorithm(ctx)
+	if ctx.IsSet("delimiter") || ctx.Bool("no-delimiter") {
+		if ctx.IsSet("delimiter") && ctx.Bool("no-delimiter") {
+			fatalIf(errInvalidArgument().Trace(args...), "`--delimiter` cannot be combined with `--no-delimiter`.")
+		}
+		if ctx.IsSet("delimiter") && ctx.String("delimiter") == "" {
+			fatalIf(errInvalidArgument().Trace(args...), "Unable to list with an empty delimiter, use `--no-delimiter` instead.")
+		}
+		for _, flag := range []string{"recursive", "incomplete", "checksum-manifest"} {
+			if ctx.Bool(flag) {
+				fatalIf(errInvalidArgument().Trace(args...), "`--delimiter` and `--no-delimiter` cannot be combined with `--"+flag+"`.")
+			}
+		}
+		if ctx.String("rewind") != "" {
+			fatalIf(errInvalidArgument().Trace(args...), "`--delimiter` and `--no-delimiter` cannot be combined with `--rewind`.")
+		}
+		for _, url := range args {
+			if clnt, err := newClient(url); err == nil {
+				if _, ok := clnt.(*s3Client); !ok {
+					fatalIf(errInvalidArgument().Trace(url), "Unable to list `"+url+"` by delimiter, only object storage is supported.")
+				}
+			}
+		}
+		// Targets are key prefixes, which need not exist as objects.
+		return
+	}
+	for _, flag := range []string{"older-than", "newer-than"} {
+		if value := ctx.String(flag); value != "" {
+			_, e := ioutils.ParseDurationTime(value)
+			fatalIf(probe.NewError(e).Trace(value), "Unable to parse --"+flag+"=`"+value+"`.")
+		}
+	}
 	// extract URLs.
 	URLs := ctx.Args()
 	isIncomplete := ctx.Bool("incomplete")
 
 	for _, url := range URLs {
-		_, _, err := url2Stat(url, false, nil)
+		_, _, err := url2Stat(globalContext, url, false, nil)
 		if err != nil && !isURLPrefixExists(url, isIncomplete) {
 			// Bucket name empty is a valid error for 'ls myminio',
 			// treat it as such.
@@ -111,6 +219,14 @@ func mainList(ctx *cli.Context) error {
 	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Parts", color.New(color.FgMagenta))
+
+	args := ctx.Args()
+	// mimic operating system tool behavior.
+	if !ctx.Args().Present() {
+		args = []string{"."}
+	}
+	rewindSources(ctx.String("rewind"), args...)
 
 	// check 'ls' cli arguments.
 	checkListSyntax(ctx)
@@ -118,11 +234,17 @@ func mainList(ctx *cli.Context) error {
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
 	isIncomplete := ctx.Bool("incomplete")
+	olderThan := ctx.String("older-than")
+	newerThan := ctx.String("newer-than")
+	isDelimited := ctx.IsSet("delimiter") || ctx.Bool("no-delimiter")
+	delimiter := ctx.String("delimiter")
 
-	args := ctx.Args()
-	// mimic operating system tool behavior.
-	if !ctx.Args().Present() {
-		args = []string{"."}
+	var inventory inventoryWriter
+	exportMsg := exportMessage{File: ctx.String("export")}
+	if exportMsg.File != "" {
+		var err *probe.Error
+		inventory, err = newInventoryWriter(exportMsg.File)
+		fatalIf(err.Trace(exportMsg.File), "Unable to create the inventory `"+exportMsg.File+"`.")
 	}
 
 	var cErr error
@@ -130,9 +252,24 @@ func mainList(ctx *cli.Context) error {
 		clnt, err := newClient(targetURL)
 		fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
 
+		if isDelimited {
+			// Checked to be an object storage target by checkListSyntax.
+			contentCh := clnt.(*s3Client).ListDelimited(delimiter)
+			if inventory != nil {
+				if e := doListExport(clnt, contentCh, inventory, olderThan, newerThan, &exportMsg); e != nil {
+					cErr = e
+				}
+				continue
+			}
+			if e := doList(clnt, contentCh, false, true, olderThan, newerThan); e != nil {
+				cErr = e
+			}
+			continue
+		}
+
 		if !strings.HasSuffix(targetURL, string(clnt.GetURL().Separator)) {
 			var st *clientContent
-			st, err = clnt.Stat(isIncomplete, false, nil)
+			st, err = clnt.Stat(globalContext, isIncomplete, false, nil)
 			if err == nil && st.Type.IsDir() {
 				targetURL = targetURL + string(clnt.GetURL().Separator)
 				clnt, err = newClient(targetURL)
@@ -140,9 +277,27 @@ func mainList(ctx *cli.Context) error {
 			}
 		}
 
-		if e := doList(clnt, isRecursive, isIncomplete); e != nil {
+		if ctx.Bool("checksum-manifest") {
+			alias, _, _ := mustExpandAlias(targetURL)
+			if e := doChecksumManifest(clnt, alias, isRecursive, olderThan, newerThan, getChecksumAlgorithm(ctx)); e != nil {
+				cErr = e
+			}
+			continue
+		}
+		if inventory != nil {
+			if e := doListExport(clnt, clnt.List(globalContext, isRecursive, false, DirNone), inventory, olderThan, newerThan, &exportMsg); e != nil {
+				cErr = e
+			}
+			continue
+		}
+		if e := doList(clnt, clnt.List(globalContext, isRecursive, isIncomplete, DirNone), isIncomplete, false, olderThan, newerThan); e != nil {
 			cErr = e
 		}
 	}
+	if inventory != nil {
+		e := inventory.Close()
+		fatalIf(probe.NewError(e).Trace(exportMsg.File), "Unable to write the inventory `"+exportMsg.File+"`.")
+		printMsg(exportMsg)
+	}
 	return cErr
 }
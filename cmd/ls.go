@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,20 +35,47 @@ const (
 	printDate = "2006-01-02 15:04:05 MST"
 )
 
+// formatDate - date in the time zone of printed dates, human friendly
+// unless '--humanize off' asks for RFC3339 dates.
+func formatDate(t time.Time) string {
+	if globalNoHumanize {
+		return t.In(globalTimeZone).Format(time.RFC3339)
+	}
+	return t.In(globalTimeZone).Format(printDate)
+}
+
+// formatSize - size in IEC units, in bytes with '--humanize off'.
+func formatSize(size int64) string {
+	if globalNoHumanize {
+		return strconv.FormatInt(size, 10)
+	}
+	return humanize.IBytes(uint64(size))
+}
+
 // contentMessage container for content message structure.
 type contentMessage struct {
-	Status   string    `json:"status"`
-	Filetype string    `json:"type"`
-	Time     time.Time `json:"lastModified"`
-	Size     int64     `json:"size"`
-	Key      string    `json:"key"`
-	ETag     string    `json:"etag"`
+	Status    string    `json:"status"`
+	Filetype  string    `json:"type"`
+	Time      time.Time `json:"lastModified"`
+	Size      int64     `json:"size"`
+	Key       string    `json:"key"`
+	ETag      string    `json:"etag"`
+	UploadID  string    `json:"uploadId,omitempty"`
+	VersionID string    `json:"versionId,omitempty"`
+	Parts     int       `json:"parts,omitempty"`
 }
 
 // String colorized string message.
 func (c contentMessage) String() string {
-	message := console.Colorize("Time", fmt.Sprintf("[%s] ", c.Time.Format(printDate)))
-	message = message + console.Colorize("Size", fmt.Sprintf("%7s ", strings.Join(strings.Fields(humanize.IBytes(uint64(c.Size))), "")))
+	message := console.Colorize("Time", fmt.Sprintf("[%s] ", formatDate(c.Time)))
+	message = message + console.Colorize("Size", fmt.Sprintf("%7s ", strings.Join(strings.Fields(formatSize(c.Size)), "")))
+	if c.UploadID != "" {
+		parts := fmt.Sprintf("%d parts", c.Parts)
+		if c.Parts == 1 {
+			parts = "1 part"
+		}
+		message = message + console.Colorize("Parts", fmt.Sprintf("%9s ", parts))
+	}
 	message = func() string {
 		if c.Filetype == "folder" {
 			return message + console.Colorize("Dir", c.Key)
@@ -69,7 +97,7 @@ func (c contentMessage) JSON() string {
 // parseContent parse client Content container into printer struct.
 func parseContent(c *clientContent) contentMessage {
 	content := contentMessage{}
-	content.Time = c.Time.Local()
+	content.Time = c.Time.In(globalTimeZone)
 
 	// guess file type.
 	content.Filetype = func() string {
@@ -83,6 +111,8 @@ func parseContent(c *clientContent) contentMessage {
 	md5sum := strings.TrimPrefix(c.ETag, "\"")
 	md5sum = strings.TrimSuffix(md5sum, "\"")
 	content.ETag = md5sum
+	content.UploadID = c.UploadID
+	content.VersionID = c.VersionID
 	// Convert OS Type to match console file printing style.
 	content.Key = getKey(c)
 	return content
@@ -104,15 +134,17 @@ func getKey(c *clientContent) string {
 	return c.URL.Path
 }
 
-// doList - list all entities inside a folder.
-func doList(clnt Client, isRecursive, isIncomplete bool) error {
+// doList - print the entities listed inside a folder, entries outside of
+// olderThan and newerThan are skipped. Folders of delimited listings end
+// with their delimiter and are printed as listed.
+func doList(clnt Client, contentCh <-chan *clientContent, isIncomplete, isDelimited bool, olderThan, newerThan string) error {
 	prefixPath := clnt.GetURL().Path
 	separator := string(clnt.GetURL().Separator)
 	if !strings.HasSuffix(prefixPath, separator) {
 		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
 	}
-	var cErr error
-	for content := range clnt.List(isRecursive, isIncomplete, DirNone) {
+	fs := &failureStatus{}
+	for content := range contentCh {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
 			// handle this specifically for filesystem related errors.
@@ -133,9 +165,27 @@ func doList(clnt Client, isRecursive, isIncomplete bool) error {
 				continue
 			}
 			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
-			cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
+			fs.fail(content.Err) // Set the exit status.
 			continue
 		}
+		if !content.Type.IsDir() {
+			// Skip entries newer than --older-than and older than --newer-than.
+			if olderThan != "" && isOlder(content.Time, olderThan) {
+				continue
+			}
+			if newerThan != "" && isNewer(content.Time, newerThan) {
+				continue
+			}
+		}
+		parts := 0
+		if s3Clnt, ok := clnt.(*s3Client); ok && isIncomplete && content.UploadID != "" {
+			var err *probe.Error
+			if parts, err = s3Clnt.UploadParts(content); err != nil {
+				errorIf(err.Trace(content.URL.String()), "Unable to list parts of incomplete upload.")
+				fs.fail(err)
+				continue
+			}
+		}
 		// Convert any os specific delimiters to "/".
 		contentURL := filepath.ToSlash(content.URL.Path)
 		prefixPath = filepath.ToSlash(prefixPath)
@@ -146,8 +196,13 @@ func doList(clnt Client, isRecursive, isIncomplete bool) error {
 		contentURL = strings.TrimPrefix(contentURL, prefixPath)
 		content.URL.Path = contentURL
 		parsedContent := parseContent(content)
+		if isDelimited && content.Type.IsDir() {
+			parsedContent.Key = content.URL.Path
+		}
+		parsedContent.Parts = parts
 		// Print colorized or jsonized content info.
 		printMsg(parsedContent)
+		fs.success()
 	}
-	return cErr
+	return fs.exitError()
 }
@@ -15,3 +15,257 @@
  */
 
 package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// incompleteUpload - a multipart upload on the fake S3 server.
+type incompleteUpload struct {
+	key       string
+	uploadID  string
+	initiated time.Time
+	parts     int
+}
+
+// uploadsHandler - fake S3 server of a bucket with incomplete uploads.
+type uploadsHandler struct {
+	mutex    sync.Mutex
+	uploads  []incompleteUpload // Sorted by key and upload id.
+	partSize int64
+	pageSize int
+}
+
+func (h *uploadsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && len(query["uploads"]) > 0:
+		response := "<ListMultipartUploadsResult><Bucket>bucket</Bucket>"
+		for _, u := range h.uploads {
+			if strings.HasPrefix(u.key, query.Get("prefix")) {
+				response += fmt.Sprintf("<Upload><Key>%s</Key><UploadId>%s</UploadId><Initiated>%s</Initiated></Upload>",
+					u.key, u.uploadID, u.initiated.UTC().Format(time.RFC3339))
+			}
+		}
+		w.Write([]byte(response + "<IsTruncated>false</IsTruncated></ListMultipartUploadsResult>"))
+	case r.Method == "GET" && len(query["uploadId"]) > 0:
+		for _, u := range h.uploads {
+			if u.key != object || u.uploadID != query.Get("uploadId") {
+				continue
+			}
+			marker, _ := strconv.Atoi(query.Get("part-number-marker"))
+			end := marker + h.pageSize
+			if end > u.parts {
+				end = u.parts
+			}
+			response := "<ListPartsResult><Bucket>bucket</Bucket>"
+			for i := marker + 1; i <= end; i++ {
+				response += fmt.Sprintf("<Part><PartNumber>%d</PartNumber><Size>%d</Size></Part>", i, h.partSize)
+			}
+			if end < u.parts {
+				response += fmt.Sprintf("<IsTruncated>true</IsTruncated><NextPartNumberMarker>%d</NextPartNumberMarker>", end)
+			} else {
+				response += "<IsTruncated>false</IsTruncated>"
+			}
+			w.Write([]byte(response + "</ListPartsResult>"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchUpload</Code></Error>"))
+	case r.Method == "DELETE" && len(query["uploadId"]) > 0:
+		for i, u := range h.uploads {
+			if u.key == object && u.uploadID == query.Get("uploadId") {
+				h.uploads = append(h.uploads[:i], h.uploads[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func (s *TestSuite) TestListIncompleteUploads(c *C) {
+	now := UTCNow().Truncate(time.Second)
+	h := &uploadsHandler{
+		uploads: []incompleteUpload{
+			{"big.iso", "upload1", now.Add(-30 * 24 * time.Hour), 5},
+			{"big.iso", "upload2", now.Add(-time.Hour), 1},
+		},
+		partSize: 5 * 1024 * 1024,
+		pageSize: 2,
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3c := clnt.(*s3Client)
+
+	var contents []*clientContent
+	for content := range clnt.List(globalContext, true, true, DirNone) {
+		c.Assert(content.Err, IsNil)
+		contents = append(contents, content)
+	}
+	c.Assert(len(contents), Equals, 2)
+	for i, parts := range []int{5, 1} {
+		c.Assert(contents[i].UploadID, Equals, h.uploads[i].uploadID)
+		c.Assert(contents[i].Size, Equals, int64(parts)*h.partSize)
+		c.Assert(contents[i].Time.Equal(h.uploads[i].initiated), Equals, true)
+		n, err := s3c.UploadParts(contents[i])
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, parts)
+	}
+	c.Assert(isOlder(contents[0].Time, "7d"), Equals, false)
+	c.Assert(isOlder(contents[1].Time, "7d"), Equals, true)
+
+	// Only the listed upload is aborted, not every upload of the object.
+	contentCh := make(chan *clientContent, 1)
+	contentCh <- contents[0]
+	close(contentCh)
+	for err := range clnt.Remove(true, false, contentCh) {
+		c.Assert(err, IsNil)
+	}
+	c.Assert(len(h.uploads), Equals, 1)
+	c.Assert(h.uploads[0].uploadID, Equals, "upload2")
+}
+
+func (s *TestSuite) TestFormatDateSize(c *C) {
+	savedNoHumanize, savedTimeZone := globalNoHumanize, globalTimeZone
+	defer func() { globalNoHumanize, globalTimeZone = savedNoHumanize, savedTimeZone }()
+
+	date := time.Date(2019, 10, 9, 22, 54, 57, 0, time.UTC)
+	globalTimeZone = time.FixedZone("CEST", 2*60*60)
+	c.Assert(formatDate(date), Equals, "2019-10-10 00:54:57 CEST")
+	c.Assert(formatSize(1536), Equals, "1.5 KiB")
+
+	globalNoHumanize, globalTimeZone = true, time.UTC
+	c.Assert(formatDate(date), Equals, "2019-10-09T22:54:57Z")
+	c.Assert(formatSize(1536), Equals, "1536")
+}
+
+// keysHandler - fake S3 server of a bucket listing keys by delimiter in
+// pages, the next marker is only returned with a delimiter.
+type keysHandler struct {
+	keys     []string // Sorted.
+	pageSize int
+}
+
+func (h keysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && r.URL.Path == "/bucket/":
+		prefix, delimiter, marker := query.Get("prefix"), query.Get("delimiter"), query.Get("marker")
+		response := "<ListBucketResult><Name>bucket</Name>"
+		entries, last := 0, ""
+		for _, key := range h.keys {
+			if !strings.HasPrefix(key, prefix) || key <= marker {
+				continue
+			}
+			if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+				commonPrefix := key[:len(prefix)+i+len(delimiter)]
+				if commonPrefix == last || commonPrefix <= marker {
+					continue
+				}
+				if entries == h.pageSize {
+					break
+				}
+				response += fmt.Sprintf("<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>", commonPrefix)
+				entries, last = entries+1, commonPrefix
+				continue
+			}
+			if entries == h.pageSize {
+				break
+			}
+			response += fmt.Sprintf("<Contents><Key>%s</Key><Size>1</Size><LastModified>%s</LastModified></Contents>",
+				key, UTCNow().Format(time.RFC3339))
+			entries, last = entries+1, key
+		}
+		if entries == h.pageSize {
+			response += "<IsTruncated>true</IsTruncated>"
+			if delimiter != "" {
+				response += fmt.Sprintf("<NextMarker>%s</NextMarker>", last)
+			}
+		} else {
+			response += "<IsTruncated>false</IsTruncated>"
+		}
+		w.Write([]byte(response + "</ListBucketResult>"))
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func (s *TestSuite) TestListDelimited(c *C) {
+	server := httptest.NewServer(keysHandler{
+		keys: []string{
+			"logs/2019-10-01/app.log", "logs/2019-10-01/db.log", "logs/2019-10-02/app.log",
+			"logs/2019-10.tar", "logs/2019-11-01/app.log",
+			"tenant:41:report.csv", "tenant:42:invoice.csv", "tenant:42:report.csv",
+		},
+		pageSize: 2,
+	})
+	defer server.Close()
+
+	testCases := []struct {
+		target    string
+		delimiter string
+		keys      []string
+	}{
+		// Flat listings of a key prefix.
+		{"/bucket/logs/2019-10", "", []string{
+			"/bucket/logs/2019-10-01/app.log", "/bucket/logs/2019-10-01/db.log",
+			"/bucket/logs/2019-10-02/app.log", "/bucket/logs/2019-10.tar",
+		}},
+		{"/bucket/logs/2019-1", "/", []string{
+			"/bucket/logs/2019-10-01/", "/bucket/logs/2019-10-02/",
+			"/bucket/logs/2019-10.tar", "/bucket/logs/2019-11-01/",
+		}},
+		{"/bucket/", ":", []string{"/bucket/logs/2019-10-01/app.log", "/bucket/logs/2019-10-01/db.log",
+			"/bucket/logs/2019-10-02/app.log", "/bucket/logs/2019-10.tar", "/bucket/logs/2019-11-01/app.log",
+			"/bucket/tenant:",
+		}},
+		{"/bucket/tenant:", ":", []string{"/bucket/tenant:41:", "/bucket/tenant:42:"}},
+		{"/bucket/tenant:4", "", []string{
+			"/bucket/tenant:41:report.csv", "/bucket/tenant:42:invoice.csv", "/bucket/tenant:42:report.csv",
+		}},
+	}
+	for i, testCase := range testCases {
+		conf := new(Config)
+		conf.HostURL = server.URL + testCase.target
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+
+		var keys []string
+		for content := range clnt.(*s3Client).ListDelimited(testCase.delimiter) {
+			c.Assert(content.Err, IsNil)
+			c.Assert(content.Type.IsDir(), Equals, strings.HasSuffix(content.URL.Path, testCase.delimiter) && testCase.delimiter != "")
+			keys = append(keys, content.URL.Path)
+		}
+		c.Assert(keys, DeepEquals, testCase.keys, Commentf("Test %d", i+1))
+	}
+}
@@ -100,10 +100,32 @@ func Main(args []string) {
 	// Set the mc app name.
 	appName := filepath.Base(args[0])
 
+	// Cancel requests and transfers on interrupt.
+	trapCancellation()
+
+	// Commands returning an exit status are finished before mc exits.
+	cli.OsExiter = func(code int) {
+		finishCommand(code, "")
+		os.Exit(code)
+	}
+
+	// Command aliases of the config run their command line.
+	aliases := loadCommandAliases(args)
+	args = expandCommandAlias(args, aliases)
+
 	// Run the app - exit on error.
-	if err := registerApp(appName).Run(args); err != nil {
+	if err := registerApp(appName, aliases).Run(args); err != nil {
+		finishCommand(1, err.Error())
 		os.Exit(1)
 	}
+	finishCommand(0, "")
+}
+
+// finishCommand - record the result of the running command in the audit
+// log and run its post hook.
+func finishCommand(exitCode int, errMsg string) {
+	finishAudit(exitCode, errMsg)
+	finishHooks(exitCode, errMsg)
 }
 
 // Function invoked when invalid command is passed.
@@ -149,6 +171,12 @@ func checkConfig() {
 }
 
 func migrate() {
+	// Concurrent mc processes migrate one after the other, migrations
+	// already done by another process are skipped.
+	lock, err := lockMcConfig()
+	fatalIf(err.Trace(), "Unable to lock the config folder.")
+	defer lock.Release()
+
 	// Fix broken config files if any.
 	fixConfig()
 
@@ -184,14 +212,32 @@ func getSystemData() map[string]string {
 	}
 }
 
+// isMcConfigDirInitialized - returns true if the config file and all
+// folders of the config folder exist.
+func isMcConfigDirInitialized() bool {
+	return isMcConfigExists() && isSessionDirExists() && isShareDirExists() &&
+		isCertsDirExists() && isCAsDirExists()
+}
+
 // initMC - initialize 'mc'.
 func initMC() {
+	// Nothing is written to a read-only config folder, e.g. in a container,
+	// mc then runs with the default hosts unless a config file is present,
+	// hosts may be defined by MC_HOST_<alias> environment variables.
+	if !isMcConfigDirInitialized() && !isMcConfigDirWritable() {
+		if !isMcConfigExists() {
+			cacheConfigV9(newMcConfig())
+		}
+		loadRootCAs()
+		return
+	}
+
 	// Check if mc config exists.
 	if !isMcConfigExists() {
-		err := saveMcConfig(newMcConfig())
+		created, err := createMcConfig()
 		fatalIf(err.Trace(), "Unable to save new mc config.")
 
-		if !globalQuiet && !globalJSON {
+		if created && !globalQuiet && !globalJSON {
 			console.Infoln("Configuration written to `" + mustGetMcConfigPath() + "`. Please update your access credentials.")
 		}
 	}
@@ -221,45 +267,107 @@ func initMC() {
 
 }
 
-func installAutoCompletion(ctx *cli.Context) {
-	if ctx.Bool("no-autocompletion") || ctx.GlobalBool("no-autocompletion") {
-		return
-	}
+// Answers to installing auto-completion, given by --autocompletion or
+// remembered in the config file.
+const (
+	autoCompletionInstall = "install"
+	autoCompletionSkip    = "skip"
+)
 
-	if globalQuiet || globalJSON || !terminal.IsTerminal(int(os.Stdout.Fd())) {
-		return
+// isAutoCompletionInstalled - returns true if a shell of the user
+// completes mc, PowerShell on Windows.
+func isAutoCompletionInstalled() bool {
+	if runtime.GOOS == "windows" {
+		return isPowerShellCompletionInstalled()
 	}
+	return completeinstall.IsInstalled("mc")
+}
 
+// installShellCompletion - install mc auto-completion in the shells of
+// the user, PowerShell on Windows.
+func installShellCompletion() *probe.Error {
 	if runtime.GOOS == "windows" {
-		return
+		return installPowerShellCompletion().Trace()
 	}
+	if e := completeinstall.Install("mc"); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
 
-	if completeinstall.IsInstalled("mc") {
+// saveAutoCompletionAnswer - remember the answer to installing
+// auto-completion, so that mc does not ask again.
+func saveAutoCompletionAnswer(answer string) {
+	config, err := loadMcConfig()
+	if err != nil || config.AutoCompletion == answer {
 		return
 	}
+	err = updateMcConfig(func(config *configV9) {
+		config.AutoCompletion = answer
+	})
+	errorIf(err.Trace(answer), "Unable to save the answer to installing mc auto-completion.")
+}
 
+// askAutoCompletion - ask whether to install auto-completion, false is
+// returned without asking unless both standard input and output are
+// terminals.
+func askAutoCompletion() (answer string, asked bool) {
+	if globalQuiet || globalJSON || !terminal.IsTerminal(int(os.Stdout.Fd())) || !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return "", false
+	}
+	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Printf("Install mc auto-completion in your shell ? (y/n): ")
-		reader := bufio.NewReader(os.Stdin)
 		char, _, err := reader.ReadRune()
 		if err != nil {
-			continue
+			return "", false
 		}
-
 		switch char {
 		case 'y', 'Y':
-			// Install mc completion, ignore any error for now
-			err := completeinstall.Install("mc")
-			if err != nil {
-				errorIf(probe.NewError(err), "Unable to install mc auto-completion.")
-			} else {
-				console.Infoln("Auto-completion installed! Kindly restart your shell to load it.")
-			}
-			fallthrough
+			return autoCompletionInstall, true
 		case 'n', 'N':
+			return autoCompletionSkip, true
+		}
+	}
+}
+
+func installAutoCompletion(ctx *cli.Context) {
+	if ctx.Bool("no-autocompletion") || ctx.GlobalBool("no-autocompletion") {
+		return
+	}
+
+	answer := strings.ToLower(ctx.GlobalString("autocompletion"))
+	switch answer {
+	case autoCompletionInstall, autoCompletionSkip:
+		saveAutoCompletionAnswer(answer)
+	case "":
+		if config, err := loadMcConfig(); err == nil {
+			answer = config.AutoCompletion
+		}
+	default:
+		fatalIf(errInvalidArgument().Trace(answer), "Unrecognized `--autocompletion "+answer+"`. Valid options are `[install, skip]`.")
+	}
+
+	if answer == autoCompletionSkip || isAutoCompletionInstalled() {
+		return
+	}
+
+	if answer == "" {
+		var asked bool
+		if answer, asked = askAutoCompletion(); !asked {
 			return
 		}
+		saveAutoCompletionAnswer(answer)
+		if answer == autoCompletionSkip {
+			return
+		}
+	}
+
+	if err := installShellCompletion(); err != nil {
+		errorIf(err, "Unable to install mc auto-completion.")
+		return
 	}
+	console.Infoln("Auto-completion installed! Kindly restart your shell to load it.")
 }
 
 func registerBefore(ctx *cli.Context) error {
@@ -281,6 +389,9 @@ func registerBefore(ctx *cli.Context) error {
 	// Check if config can be read.
 	checkConfig()
 
+	// Set the colors of the theme of the user.
+	setThemeFromConfig()
+
 	// Install shell completions
 	installAutoCompletion(ctx)
 
@@ -307,12 +418,25 @@ func findClosestCommands(command string) []string {
 	return closestCommands
 }
 
+// isUpdateCheckDisabled - returns true if the check for updates is
+// disabled by '--no-check-update', MC_NO_UPDATE_CHECK or the config.
+func isUpdateCheckDisabled(ctx *cli.Context) bool {
+	if ctx.Bool("no-check-update") || ctx.GlobalBool("no-check-update") || isEnvEnabled("MC_NO_UPDATE_CHECK") {
+		return true
+	}
+	config, err := loadMcConfig()
+	return err == nil && config.NoUpdateCheck
+}
+
 // Check for updates and print a notification message
 func checkUpdate(ctx *cli.Context) {
+	if isUpdateCheckDisabled(ctx) {
+		return
+	}
 	// Do not print update messages, if quiet flag is set.
 	if ctx.Bool("quiet") || ctx.GlobalBool("quiet") {
 		// Its OK to ignore any errors during doUpdate() here.
-		if updateMsg, _, currentReleaseTime, latestReleaseTime, err := getUpdateInfo(2 * time.Second); err == nil {
+		if updateMsg, _, currentReleaseTime, latestReleaseTime, err := getUpdateInfo(2*time.Second, mcReleaseChannelStable); err == nil {
 			printMsg(updateMessage{
 				Status:  "success",
 				Message: updateMsg,
@@ -335,26 +459,38 @@ var appCmds = []cli.Command{
 	pipeCmd,
 	shareCmd,
 	cpCmd,
+	mvCmd,
 	mirrorCmd,
+	publishCmd,
 	findCmd,
 	sqlCmd,
 	statCmd,
 	diffCmd,
+	verifyCmd,
 	rmCmd,
 	eventCmd,
 	watchCmd,
 	policyCmd,
+	retentionCmd,
 	adminCmd,
+	idpCmd,
+	supportCmd,
+	docsCmd,
 	sessionCmd,
 	configCmd,
+	loginCmd,
+	cleanCmd,
 	updateCmd,
 	versionCmd,
 }
 
-func registerApp(name string) *cli.App {
+func registerApp(name string, aliases map[string][]string) *cli.App {
 	for _, cmd := range appCmds {
 		registerCmd(cmd)
 	}
+	for _, cmd := range commandAliasCmds(aliases) {
+		registerCmd(cmd)
+	}
 
 	cli.HelpFlag = cli.BoolFlag{
 		Name:  "help, h",
@@ -370,6 +506,10 @@ func registerApp(name string) *cli.App {
 	app := cli.NewApp()
 	app.Name = name
 	app.Action = func(ctx *cli.Context) {
+		// Unknown commands are answered with the closest commands.
+		if ctx.Args().Present() {
+			commandNotFound(ctx, ctx.Args().First())
+		}
 		if strings.HasPrefix(ReleaseTag, "RELEASE.") {
 			// Check for new updates from dl.min.io.
 			checkUpdate(ctx)
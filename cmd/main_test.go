@@ -0,0 +1,63 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestIsUpdateCheckDisabled(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	defer os.Setenv("MC_NO_UPDATE_CHECK", os.Getenv("MC_NO_UPDATE_CHECK"))
+
+	testCases := []struct {
+		args          []string
+		env           string
+		noUpdateCheck bool
+		disabled      bool
+	}{
+		{nil, "", false, false},
+		{[]string{"--no-check-update"}, "", false, true},
+		{nil, "1", false, true},
+		{nil, "on", false, true},
+		{nil, "false", false, false},
+		{nil, "", true, true},
+	}
+	for i, testCase := range testCases {
+		set := flag.NewFlagSet("mc", flag.ContinueOnError)
+		set.Bool("no-check-update", false, "")
+		if e := set.Parse(testCase.args); e != nil {
+			t.Fatal(e)
+		}
+		os.Setenv("MC_NO_UPDATE_CHECK", testCase.env)
+		noUpdateCheck := testCase.noUpdateCheck
+		loadMcConfig = func() (*configV9, *probe.Error) {
+			config := newConfigV9()
+			config.NoUpdateCheck = noUpdateCheck
+			return config, nil
+		}
+		if disabled := isUpdateCheckDisabled(cli.NewContext(nil, set, nil)); disabled != testCase.disabled {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.disabled, disabled)
+		}
+	}
+}
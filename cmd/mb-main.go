@@ -17,6 +17,9 @@
 package cmd
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
@@ -35,6 +38,18 @@ var (
 			Name:  "ignore-existing, p",
 			Usage: "ignore if bucket/directory already exists",
 		},
+		cli.BoolFlag{
+			Name:  "with-lock, l",
+			Usage: "enable object lock, which also enables versioning",
+		},
+		cli.BoolFlag{
+			Name:  "with-versioning",
+			Usage: "enable versioning",
+		},
+		cli.StringFlag{
+			Name:  "acl",
+			Usage: "set a canned ACL on the bucket, e.g. public-read",
+		},
 	}
 )
 
@@ -73,19 +88,45 @@ EXAMPLES:
    7. Ignore if bucket/directory already exists.
       $ {{.HelpName}} --ignore-existing myminio/mynewbucket
 
+   8. Create a new bucket with object lock enabled, it can not be enabled later on.
+      $ {{.HelpName}} --with-lock myminio/mylockedbucket
+
+   9. Create a new bucket with versioning enabled in region 'eu-west-1'.
+      $ {{.HelpName}} --with-versioning --region=eu-west-1 s3/myversionedbucket
+
+  10. Create a new bucket which everyone may list and read.
+      $ {{.HelpName}} --acl public-read s3/mypublicbucket
+
 `,
 }
 
 // makeBucketMessage is container for make bucket success and failure messages.
 type makeBucketMessage struct {
-	Status string `json:"status"`
-	Bucket string `json:"bucket"`
-	Region string `json:"region"`
+	Status     string `json:"status"`
+	Bucket     string `json:"bucket"`
+	Region     string `json:"region"`
+	ObjectLock bool   `json:"objectLock"`
+	Versioning string `json:"versioning,omitempty"`
+	ACL        string `json:"acl,omitempty"`
 }
 
 // String colorized make bucket message.
 func (s makeBucketMessage) String() string {
-	return console.Colorize("MakeBucket", "Bucket created successfully `"+s.Bucket+"`.")
+	msg := "Bucket created successfully `" + s.Bucket + "`"
+	var with []string
+	switch {
+	case s.ObjectLock:
+		with = append(with, "object lock and versioning enabled")
+	case s.Versioning == "Enabled":
+		with = append(with, "versioning enabled")
+	}
+	if s.ACL != "" {
+		with = append(with, "ACL `"+s.ACL+"`")
+	}
+	if len(with) > 0 {
+		msg += " with " + strings.Join(with, " and ")
+	}
+	return console.Colorize("MakeBucket", msg+".")
 }
 
 // JSON jsonified make bucket message.
@@ -101,6 +142,33 @@ func checkMakeBucketSyntax(ctx *cli.Context) {
 	if !ctx.Args().Present() {
 		cli.ShowCommandHelpAndExit(ctx, "mb", 1) // last argument is exit code
 	}
+	if acl := ctx.String("acl"); acl != "" {
+		checkCannedACL(acl, cannedBucketACLs)
+	}
+}
+
+// makeBucketLockAndVersioning - enable versioning if asked to and verify
+// the bucket ended up as requested, as servers without object lock
+// support may create the bucket without it. An existing bucket kept by
+// --ignore-existing is verified as well.
+func makeBucketLockAndVersioning(clnt *s3Client, withLock, withVersioning bool) (bool, string, *probe.Error) {
+	locked, err := clnt.GetObjectLockEnabled()
+	if err != nil {
+		return false, "", err
+	}
+	if withLock && !locked {
+		return false, "", probe.NewError(errors.New("object lock is not enabled, it can only be enabled when the bucket is created"))
+	}
+	if withVersioning && !locked {
+		if err = clnt.SetVersioning("Enabled"); err != nil {
+			return locked, "", err
+		}
+	}
+	versioning, err := clnt.GetVersioning()
+	if err != nil {
+		return locked, "", err
+	}
+	return locked, versioning, nil
 }
 
 // mainMakeBucket is entry point for mb command.
@@ -115,19 +183,41 @@ func mainMakeBucket(ctx *cli.Context) error {
 	// Save region.
 	region := ctx.String("region")
 	ignoreExisting := ctx.Bool("p")
+	withLock := ctx.Bool("with-lock")
+	withVersioning := ctx.Bool("with-versioning")
+	acl := ctx.String("acl")
 
-	var cErr error
+	fs := &failureStatus{}
 	for _, targetURL := range ctx.Args() {
 		// Instantiate client for URL.
 		clnt, err := newClient(targetURL)
 		if err != nil {
 			errorIf(err.Trace(targetURL), "Invalid target `"+targetURL+"`.")
-			cErr = exitStatus(globalErrorExitStatus)
+			fs.fail(err)
+			continue
+		}
+
+		// Object lock and versioning are only supported by object storage.
+		s3Clnt, isS3 := clnt.(*s3Client)
+		if (withLock || withVersioning) && !isS3 {
+			err = probe.NewError(APINotImplemented{API: "MakeBucketWithLock", APIType: "filesystem"})
+			errorIf(err.Trace(targetURL), "Object lock and versioning are not supported on `"+targetURL+"`.")
+			fs.fail(err)
+			continue
+		}
+		if acl != "" && !isS3 {
+			err = probe.NewError(APINotImplemented{API: "SetBucketACL", APIType: "filesystem"})
+			errorIf(err.Trace(targetURL), "ACLs are not supported on `"+targetURL+"`.")
+			fs.fail(err)
 			continue
 		}
 
 		// Make bucket.
-		err = clnt.MakeBucket(region, ignoreExisting)
+		if withLock {
+			err = s3Clnt.MakeBucketWithLock(region, ignoreExisting)
+		} else {
+			err = clnt.MakeBucket(region, ignoreExisting)
+		}
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case BucketNameEmpty:
@@ -137,12 +227,32 @@ func mainMakeBucket(ctx *cli.Context) error {
 			default:
 				errorIf(err.Trace(targetURL), "Unable to make bucket `"+targetURL+"`.")
 			}
-			cErr = exitStatus(globalErrorExitStatus)
+			fs.fail(err)
 			continue
 		}
 
+		msg := makeBucketMessage{Status: "success", Bucket: targetURL, Region: region}
+		if withLock || withVersioning {
+			msg.ObjectLock, msg.Versioning, err = makeBucketLockAndVersioning(s3Clnt, withLock, withVersioning)
+			if err != nil {
+				errorIf(err.Trace(targetURL), "Unable to configure bucket `"+targetURL+"`.")
+				fs.fail(err)
+				continue
+			}
+		}
+
+		if acl != "" {
+			if err = s3Clnt.SetBucketACL(region, acl); err != nil {
+				errorIf(err.Trace(targetURL), "Unable to set the ACL of bucket `"+targetURL+"`.")
+				fs.fail(err)
+				continue
+			}
+			msg.ACL = acl
+		}
+
 		// Successfully created a bucket.
-		printMsg(makeBucketMessage{Status: "success", Bucket: targetURL})
+		printMsg(msg)
+		fs.success()
 	}
-	return cErr
+	return fs.exitError()
 }
@@ -0,0 +1,126 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+// lockHandler - fake S3 server for buckets with object lock and versioning.
+type lockHandler struct {
+	mutex      sync.Mutex
+	noLock     bool // Server ignoring the object lock header.
+	exists     bool
+	locked     bool
+	versioning string
+	signed     bool
+}
+
+func (h *lockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && len(query["versioning"]) > 0:
+		w.Write([]byte("<VersioningConfiguration xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"><Status>" + h.versioning + "</Status></VersioningConfiguration>"))
+	case r.Method == "GET" && len(query["object-lock"]) > 0:
+		if !h.locked {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>ObjectLockConfigurationNotFoundError</Code></Error>"))
+			return
+		}
+		w.Write([]byte("<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>"))
+	case r.Method == "PUT" && len(query["versioning"]) > 0:
+		var versioning struct {
+			Status string `xml:"Status"`
+		}
+		if r.Header.Get("Content-Md5") == "" || xml.NewDecoder(r.Body).Decode(&versioning) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.versioning = versioning.Status
+	case r.Method == "PUT" && r.URL.Path == "/bucket/":
+		if h.exists {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("<Error><Code>BucketAlreadyOwnedByYou</Code></Error>"))
+			return
+		}
+		h.exists = true
+		if r.Header.Get("X-Amz-Bucket-Object-Lock-Enabled") == "true" && !h.noLock {
+			h.locked = true
+			h.versioning = "Enabled"
+		}
+		h.signed = strings.Contains(r.Header.Get("Authorization"), "x-amz-bucket-object-lock-enabled")
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func (h *lockHandler) client(c *C, server *httptest.Server) *s3Client {
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	return clnt.(*s3Client)
+}
+
+func (s *TestSuite) TestMakeBucketWithLock(c *C) {
+	h := &lockHandler{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	clnt := h.client(c, server)
+	c.Assert(clnt.MakeBucketWithLock("us-east-1", false), IsNil)
+	c.Assert(h.locked, Equals, true)
+	c.Assert(h.signed, Equals, true)
+
+	locked, versioning, err := makeBucketLockAndVersioning(clnt, true, false)
+	c.Assert(err, IsNil)
+	c.Assert(locked, Equals, true)
+	c.Assert(versioning, Equals, "Enabled")
+
+	// An existing bucket is kept with --ignore-existing.
+	c.Assert(clnt.MakeBucketWithLock("us-east-1", true), IsNil)
+	c.Assert(clnt.MakeBucketWithLock("us-east-1", false), NotNil)
+
+	// Object lock ignored by the server is reported.
+	h = &lockHandler{noLock: true}
+	server2 := httptest.NewServer(h)
+	defer server2.Close()
+	clnt = h.client(c, server2)
+	c.Assert(clnt.MakeBucketWithLock("us-east-1", false), IsNil)
+	_, _, err = makeBucketLockAndVersioning(clnt, true, false)
+	c.Assert(err, NotNil)
+
+	// Versioning is enabled after creation.
+	locked, versioning, err = makeBucketLockAndVersioning(clnt, false, true)
+	c.Assert(err, IsNil)
+	c.Assert(locked, Equals, false)
+	c.Assert(versioning, Equals, "Enabled")
+}
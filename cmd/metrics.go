@@ -0,0 +1,281 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// metricsFlags are shared by mirror, watch and event listen.
+var metricsFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "metrics-addr",
+		Usage: "serve Prometheus metrics on ADDR at /metrics, e.g. :9464, listening on localhost without a host",
+	},
+	cli.StringFlag{
+		Name:  "metrics-statsd",
+		Usage: "send metrics to the statsd daemon at HOST:PORT every 10 seconds",
+	},
+}
+
+// Interval of the metrics sent to statsd.
+const metricsStatsdInterval = 10 * time.Second
+
+// Results of the object operations counted by the metrics.
+var metricsResults = []string{"transferred", "skipped", "removed", "failed"}
+
+// operationMetrics - counters of a long running command, served to
+// Prometheus and sent to statsd. All methods are no-ops on nil metrics,
+// so callers do not need to check if metrics were requested.
+type operationMetrics struct {
+	command   string
+	startTime time.Time
+
+	transferred int64
+	queued      int64
+
+	mutex   sync.Mutex
+	results map[string]int64
+	events  map[string]int64
+
+	listener net.Listener
+	statsd   net.Conn
+	sent     map[string]int64
+	doneCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newOperationMetrics - serve the metrics of a command on addr and send
+// them to statsdAddr, nil is returned when neither is set.
+func newOperationMetrics(command, addr, statsdAddr string) (*operationMetrics, *probe.Error) {
+	if addr == "" && statsdAddr == "" {
+		return nil, nil
+	}
+	m := &operationMetrics{
+		command:   command,
+		startTime: time.Now(),
+		results:   map[string]int64{},
+		events:    map[string]int64{},
+		sent:      map[string]int64{},
+		doneCh:    make(chan struct{}),
+	}
+	if addr != "" {
+		host, port, e := net.SplitHostPort(addr)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(addr)
+		}
+		if host == "" {
+			host = "localhost"
+		}
+		if m.listener, e = net.Listen("tcp", net.JoinHostPort(host, port)); e != nil {
+			return nil, probe.NewError(e).Trace(addr)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			m.writePrometheus(w)
+		})
+		go http.Serve(m.listener, mux)
+	}
+	if statsdAddr != "" {
+		statsd, e := net.Dial("udp", statsdAddr)
+		if e != nil {
+			m.Close()
+			return nil, probe.NewError(e).Trace(statsdAddr)
+		}
+		m.statsd = statsd
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			ticker := time.NewTicker(metricsStatsdInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					m.sendStatsd()
+				case <-m.doneCh:
+					m.sendStatsd()
+					return
+				}
+			}
+		}()
+	}
+	return m, nil
+}
+
+// Close stops serving the metrics, the last counters are sent to statsd.
+func (m *operationMetrics) Close() {
+	if m == nil {
+		return
+	}
+	close(m.doneCh)
+	m.wg.Wait()
+	if m.listener != nil {
+		m.listener.Close()
+	}
+	if m.statsd != nil {
+		m.statsd.Close()
+	}
+}
+
+// metricsReader - counts transferred bytes.
+type metricsReader struct {
+	io.Reader
+	metrics *operationMetrics
+}
+
+func (r metricsReader) Read(p []byte) (n int, err error) {
+	n, err = r.Reader.Read(p)
+	atomic.AddInt64(&r.metrics.transferred, int64(n))
+	return n, err
+}
+
+// hook wraps the progress reader of transfers.
+func (m *operationMetrics) hook(progress io.Reader) io.Reader {
+	if m == nil {
+		return progress
+	}
+	return metricsReader{Reader: progress, metrics: m}
+}
+
+// track counts a task as queued until it returns.
+func (m *operationMetrics) track(task func() URLs) func() URLs {
+	if m == nil {
+		return task
+	}
+	atomic.AddInt64(&m.queued, 1)
+	return func() URLs {
+		defer atomic.AddInt64(&m.queued, -1)
+		return task()
+	}
+}
+
+// done records the result of a copy or removal.
+func (m *operationMetrics) done(urls URLs) {
+	if m == nil {
+		return
+	}
+	result := "transferred"
+	switch {
+	case urls.Error != nil:
+		result = "failed"
+	case urls.Skipped:
+		result = "skipped"
+	case urls.SourceContent == nil:
+		result = "removed"
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.results[result]++
+}
+
+// event records an event received by a watch.
+func (m *operationMetrics) event(event EventInfo) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.events[string(event.Type)]++
+}
+
+// snapshot - copy of the counters.
+func (m *operationMetrics) snapshot() (results, events map[string]int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	results, events = map[string]int64{}, map[string]int64{}
+	for _, result := range metricsResults {
+		results[result] = m.results[result]
+	}
+	for eventType, n := range m.events {
+		events[eventType] = n
+	}
+	return results, events
+}
+
+// writePrometheus - write the metrics in the Prometheus text format.
+func (m *operationMetrics) writePrometheus(w io.Writer) {
+	results, events := m.snapshot()
+	label := fmt.Sprintf(`command="%s"`, m.command)
+
+	fmt.Fprintln(w, "# HELP mc_transferred_bytes_total Bytes transferred.")
+	fmt.Fprintln(w, "# TYPE mc_transferred_bytes_total counter")
+	fmt.Fprintf(w, "mc_transferred_bytes_total{%s} %d\n", label, atomic.LoadInt64(&m.transferred))
+	fmt.Fprintln(w, "# HELP mc_objects_total Objects transferred, skipped, removed or failed.")
+	fmt.Fprintln(w, "# TYPE mc_objects_total counter")
+	for _, result := range metricsResults {
+		fmt.Fprintf(w, "mc_objects_total{%s,result=\"%s\"} %d\n", label, result, results[result])
+	}
+	fmt.Fprintln(w, "# HELP mc_queue_depth Objects queued for transfer or removal and not finished.")
+	fmt.Fprintln(w, "# TYPE mc_queue_depth gauge")
+	fmt.Fprintf(w, "mc_queue_depth{%s} %d\n", label, atomic.LoadInt64(&m.queued))
+	if len(events) > 0 {
+		fmt.Fprintln(w, "# HELP mc_events_total Events received.")
+		fmt.Fprintln(w, "# TYPE mc_events_total counter")
+		for _, eventType := range sortedMetricNames(events) {
+			fmt.Fprintf(w, "mc_events_total{%s,type=\"%s\"} %d\n", label, eventType, events[eventType])
+		}
+	}
+	fmt.Fprintln(w, "# HELP mc_start_time_seconds Start time of the command since the epoch.")
+	fmt.Fprintln(w, "# TYPE mc_start_time_seconds gauge")
+	fmt.Fprintf(w, "mc_start_time_seconds{%s} %d\n", label, m.startTime.Unix())
+}
+
+// sendStatsd - send the counters as increments since the last packet
+// and the queue depth as a gauge.
+func (m *operationMetrics) sendStatsd() {
+	results, events := m.snapshot()
+	counters := map[string]int64{"transferred_bytes": atomic.LoadInt64(&m.transferred)}
+	for result, n := range results {
+		counters["objects."+result] = n
+	}
+	for eventType, n := range events {
+		counters["events."+eventType] = n
+	}
+
+	var packet bytes.Buffer
+	prefix := "mc." + m.command + "."
+	for _, name := range sortedMetricNames(counters) {
+		if delta := counters[name] - m.sent[name]; delta > 0 {
+			fmt.Fprintf(&packet, "%s%s:%d|c\n", prefix, name, delta)
+		}
+		m.sent[name] = counters[name]
+	}
+	fmt.Fprintf(&packet, "%squeue_depth:%d|g\n", prefix, atomic.LoadInt64(&m.queued))
+	// Metrics are lost while the daemon is unreachable.
+	m.statsd.Write(bytes.TrimSuffix(packet.Bytes(), []byte("\n")))
+}
+
+func sortedMetricNames(counters map[string]int64) []string {
+	keys := make([]string, 0, len(counters))
+	for key := range counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
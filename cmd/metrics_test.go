@@ -0,0 +1,86 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOperationMetrics(t *testing.T) {
+	if m, err := newOperationMetrics("mirror", "", ""); m != nil || err != nil {
+		t.Fatalf("expected no metrics without addresses, got %v, %v", m, err)
+	}
+
+	statsd, e := net.ListenPacket("udp", "127.0.0.1:0")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer statsd.Close()
+	m, err := newOperationMetrics("mirror", "127.0.0.1:0", statsd.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ioutil.ReadAll(m.hook(strings.NewReader("hello")))
+	m.done(URLs{SourceContent: &clientContent{}})
+	m.done(URLs{SourceContent: &clientContent{}, Error: errInvalidArgument()})
+	m.done(URLs{TargetContent: &clientContent{}})
+	m.event(EventInfo{Type: EventCreate})
+	var exposed string
+	m.track(func() URLs {
+		resp, e := http.Get("http://" + m.listener.Addr().String() + "/metrics")
+		if e != nil {
+			t.Fatal(e)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		exposed = string(body)
+		return URLs{}
+	})()
+
+	for _, line := range []string{
+		`mc_transferred_bytes_total{command="mirror"} 5`,
+		`mc_objects_total{command="mirror",result="transferred"} 1`,
+		`mc_objects_total{command="mirror",result="failed"} 1`,
+		`mc_objects_total{command="mirror",result="removed"} 1`,
+		`mc_queue_depth{command="mirror"} 1`,
+		`mc_events_total{command="mirror",type="ObjectCreated"} 1`,
+	} {
+		if !strings.Contains(exposed, line+"\n") {
+			t.Errorf("expected %q in the metrics, got\n%s", line, exposed)
+		}
+	}
+
+	// The counters are sent once closed.
+	m.Close()
+	statsd.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, e := statsd.ReadFrom(buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expected := "mc.mirror.events.ObjectCreated:1|c\nmc.mirror.objects.failed:1|c\nmc.mirror.objects.removed:1|c\n" +
+		"mc.mirror.objects.transferred:1|c\nmc.mirror.transferred_bytes:5|c\nmc.mirror.queue_depth:0|g"
+	if string(buf[:n]) != expected {
+		t.Errorf("expected statsd packet %q, got %q", expected, buf[:n])
+	}
+}
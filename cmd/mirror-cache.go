@@ -0,0 +1,256 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	mirrorCacheVersion = "1"
+
+	// A cached listing is trusted for a day, others may change the
+	// target in the meantime.
+	mirrorCacheExpiry = 24 * time.Hour
+)
+
+// noCacheFlag lists the target even when a cached listing is found.
+var noCacheFlag = cli.BoolFlag{
+	Name:  "no-cache",
+	Usage: "list the target instead of using the listing cached by a previous mirror",
+}
+
+// mirrorCacheHeader is the first line of a cache file.
+type mirrorCacheHeader struct {
+	Version string    `json:"version"`
+	Target  string    `json:"target"`
+	Time    time.Time `json:"time"`
+}
+
+// mirrorCacheEntry is an object of the target, one per line in key order.
+type mirrorCacheEntry struct {
+	Key  string    `json:"key"`
+	Size int64     `json:"size"`
+	ETag string    `json:"etag,omitempty"`
+	Time time.Time `json:"lastModified"`
+}
+
+// mirrorCache - listing of a remote mirror target, as left by the last
+// mirror which completed without errors. The listing of a run is written
+// next to the cache and only replaces it when the run succeeds, any
+// error removes the cache.
+type mirrorCache struct {
+	mutex     sync.Mutex
+	targetURL string
+	path      string
+	file      *os.File
+	writer    *bufio.Writer
+	encoder   *json.Encoder
+	complete  bool
+	invalid   bool
+}
+
+// getMirrorCacheFile - get the cache file of an expanded target URL.
+func getMirrorCacheFile(targetURL string) (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	sum := sha256.Sum256([]byte(targetURL))
+	return filepath.Join(configDir, globalMirrorCacheDir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// newMirrorCache - start caching the listing of a target. Local targets
+// are not cached, nil is returned as well when the config folder is not
+// writable, a nil cache is never used.
+func newMirrorCache(targetURL string) *mirrorCache {
+	targetAlias, expandedURL, _ := mustExpandAlias(targetURL)
+	clnt, err := newClientFromAlias(targetAlias, expandedURL)
+	if err != nil || clnt.GetURL().Type != objectStorage {
+		return nil
+	}
+	separator := string(clnt.GetURL().Separator)
+	if !strings.HasSuffix(expandedURL, separator) {
+		expandedURL = expandedURL + separator
+	}
+
+	path, err := getMirrorCacheFile(expandedURL)
+	if err != nil {
+		return nil
+	}
+	if e := os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+		return nil
+	}
+	file, e := os.OpenFile(path+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if e != nil {
+		return nil
+	}
+	mc := &mirrorCache{
+		targetURL: expandedURL,
+		path:      path,
+		file:      file,
+		writer:    bufio.NewWriter(file),
+	}
+	mc.encoder = json.NewEncoder(mc.writer)
+	mc.encoder.Encode(mirrorCacheHeader{
+		Version: mirrorCacheVersion,
+		Target:  expandedURL,
+		Time:    UTCNow(),
+	})
+	return mc
+}
+
+// listing - the target client listing from the cache, the client itself
+// when no valid cache is found.
+func (mc *mirrorCache) listing(clnt Client) Client {
+	if mc == nil {
+		return clnt
+	}
+	file, e := os.Open(mc.path)
+	if e != nil {
+		return clnt
+	}
+	reader := bufio.NewReader(file)
+	var header mirrorCacheHeader
+	line, e := reader.ReadBytes('\n')
+	if e == nil {
+		e = json.Unmarshal(line, &header)
+	}
+	if e != nil || header.Version != mirrorCacheVersion || header.Target != mc.targetURL ||
+		UTCNow().Sub(header.Time) > mirrorCacheExpiry {
+		file.Close()
+		return clnt
+	}
+	return &cachedListClient{Client: clnt, targetURL: mc.targetURL, file: file, reader: reader}
+}
+
+// add - add an object of the target after the mirror, in key order.
+func (mc *mirrorCache) add(content *clientContent) {
+	if mc == nil {
+		return
+	}
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if mc.invalid || mc.encoder == nil {
+		return
+	}
+	if e := mc.encoder.Encode(mirrorCacheEntry{
+		Key:  strings.TrimPrefix(content.URL.String(), mc.targetURL),
+		Size: content.Size,
+		ETag: content.ETag,
+		Time: content.Time,
+	}); e != nil {
+		mc.invalid = true
+	}
+}
+
+// done - the whole target was compared.
+func (mc *mirrorCache) done() {
+	if mc == nil {
+		return
+	}
+	mc.mutex.Lock()
+	mc.complete = true
+	mc.mutex.Unlock()
+}
+
+// invalidate - the target is not known after an error.
+func (mc *mirrorCache) invalidate() {
+	if mc == nil {
+		return
+	}
+	mc.mutex.Lock()
+	mc.invalid = true
+	mc.mutex.Unlock()
+}
+
+// Close - replace the cache with the listing of a complete run without
+// errors, remove it otherwise.
+func (mc *mirrorCache) Close() error {
+	if mc == nil {
+		return nil
+	}
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	e := mc.writer.Flush()
+	if ce := mc.file.Close(); e == nil {
+		e = ce
+	}
+	mc.encoder = nil
+	if e != nil || mc.invalid || !mc.complete {
+		os.Remove(mc.file.Name())
+		os.Remove(mc.path)
+		return e
+	}
+	return os.Rename(mc.file.Name(), mc.path)
+}
+
+// cachedListClient - a target client which lists its objects from a
+// mirror cache, all other operations go to the target.
+type cachedListClient struct {
+	Client
+	targetURL string
+	file      *os.File
+	reader    *bufio.Reader
+}
+
+// List - recursive listings of objects are read from the cache.
+func (c *cachedListClient) List(ctx context.Context, isRecursive, isIncomplete bool, showDir DirOpt) <-chan *clientContent {
+	if !isRecursive || isIncomplete || showDir != DirNone {
+		return c.Client.List(ctx, isRecursive, isIncomplete, showDir)
+	}
+	contentCh := make(chan *clientContent)
+	go func() {
+		defer close(contentCh)
+		defer c.file.Close()
+		for ctx.Err() == nil {
+			line, e := c.reader.ReadBytes('\n')
+			if e == io.EOF && len(line) == 0 {
+				return
+			}
+			var entry mirrorCacheEntry
+			if e == nil {
+				e = json.Unmarshal(line, &entry)
+			}
+			if e != nil {
+				contentCh <- &clientContent{Err: probe.NewError(e)}
+				return
+			}
+			contentCh <- &clientContent{
+				URL:  *newClientURL(urlJoinPath(c.targetURL, entry.Key)),
+				Size: entry.Size,
+				ETag: entry.ETag,
+				Time: entry.Time,
+				Type: os.FileMode(0664),
+			}
+		}
+	}()
+	return contentCh
+}
@@ -0,0 +1,115 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestMirrorCache(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		config := newConfigV9()
+		config.Hosts["mys3"] = hostConfigV9{URL: "https://s3.example.com", API: "S3v4", Lookup: "auto"}
+		return config, nil
+	}
+
+	configDir, e := ioutil.TempDir("", "mc-cache-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(configDir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(configDir)
+
+	// Local targets are not cached.
+	if mc := newMirrorCache(configDir); mc != nil {
+		t.Fatal("expected no cache for a local target")
+	}
+
+	targetURL := "mys3/bucket/prefix"
+	expandedURL := "https://s3.example.com/bucket/prefix"
+	modTime := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	objects := []*clientContent{
+		{URL: *newClientURL(expandedURL + "/a.txt"), Size: 1, ETag: "etag-a", Time: modTime},
+		{URL: *newClientURL(expandedURL + "/dir/b.txt"), Size: 2, Time: modTime},
+	}
+	cachedKeys := func(mc *mirrorCache) (keys []string) {
+		clnt, err := newClient(targetURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		listClnt := mc.listing(clnt)
+		if listClnt == clnt {
+			return nil
+		}
+		for content := range listClnt.List(globalContext, true, false, DirNone) {
+			if content.Err != nil {
+				t.Fatal(content.Err)
+			}
+			if content.Size == 1 && (content.ETag != "etag-a" || !content.Time.Equal(modTime)) {
+				t.Errorf("unexpected cached object %v", content)
+			}
+			keys = append(keys, content.URL.String())
+		}
+		return keys
+	}
+
+	// Nothing is cached before a mirror completes.
+	mc := newMirrorCache(targetURL)
+	if mc == nil {
+		t.Fatal("expected a cache for a remote target")
+	}
+	for _, content := range objects {
+		mc.add(content)
+	}
+	if keys := cachedKeys(mc); keys != nil {
+		t.Fatalf("unexpected cached listing %v", keys)
+	}
+	mc.done()
+	if e = mc.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	mc = newMirrorCache(targetURL)
+	expected := []string{expandedURL + "/a.txt", expandedURL + "/dir/b.txt"}
+	if keys := cachedKeys(mc); !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected %v, got %v", expected, keys)
+	}
+
+	// An error removes the cache.
+	mc.add(objects[0])
+	mc.invalidate()
+	mc.done()
+	mc.Close()
+	if keys := cachedKeys(newMirrorCache(targetURL)); keys != nil {
+		t.Fatalf("unexpected cached listing %v", keys)
+	}
+
+	// The cache of a target is not used for another one.
+	otherURL := "mys3/bucket/other"
+	if mc = newMirrorCache(otherURL); mc == nil || mc.path == newMirrorCache(targetURL).path {
+		t.Fatal("expected a cache file per target")
+	}
+}
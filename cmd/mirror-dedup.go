@@ -0,0 +1,259 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var dedupFlag = cli.BoolFlag{
+	Name:  "dedup",
+	Usage: "skip uploading files with the content of an object already under the target, copying that object on the target instead",
+}
+
+const (
+	// mirrorDedupManifest - object under the target mapping the SHA-256
+	// of the content of mirrored objects to their key.
+	mirrorDedupManifest = ".mc-dedup.json"
+
+	// mirrorDedupMetaKey - metadata having the SHA-256 of the content of
+	// objects uploaded by 'mc mirror --dedup'.
+	mirrorDedupMetaKey = "X-Amz-Meta-Mc-Sha256"
+
+	mirrorDedupManifestVersion = "1"
+)
+
+// mirrorDedupManifestV1 - the manifest object, keys are relative to the
+// target and separated by slashes.
+type mirrorDedupManifestV1 struct {
+	Version string            `json:"version"`
+	Objects map[string]string `json:"objects"`
+}
+
+// mirrorDedup - the content of the objects under the target of a mirror,
+// to copy an object on the target when a file with the same content is
+// mirrored again, under another name.
+type mirrorDedup struct {
+	mutex sync.Mutex
+
+	targetAlias string
+	manifestURL string
+	root        clientURL
+
+	// key of an object by the SHA-256 of its content, and the reverse
+	sums map[string]string
+	keys map[string]string
+
+	changed bool
+}
+
+// isDedupNotFound - errors of an object which does not exist.
+func isDedupNotFound(err *probe.Error) bool {
+	switch err.ToGoError().(type) {
+	case ObjectMissing, PathNotFound, BucketDoesNotExist:
+		return true
+	}
+	return false
+}
+
+// newMirrorDedup - load the manifest of the target, a target without a
+// manifest has no known objects yet.
+func newMirrorDedup(targetURL string) (*mirrorDedup, *probe.Error) {
+	targetAlias, expandedURL, _, err := expandAlias(targetURL)
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	clnt, err := newClientFromAlias(targetAlias, expandedURL)
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	d := &mirrorDedup{
+		targetAlias: targetAlias,
+		manifestURL: urlJoinPath(expandedURL, mirrorDedupManifest),
+		root:        clnt.GetURL(),
+		sums:        make(map[string]string),
+		keys:        make(map[string]string),
+	}
+
+	manifestClnt, err := newClientFromAlias(targetAlias, d.manifestURL)
+	if err != nil {
+		return nil, err.Trace(d.manifestURL)
+	}
+	if _, err = manifestClnt.Stat(globalContext, false, false, nil); err != nil {
+		if isDedupNotFound(err) {
+			return d, nil
+		}
+		return nil, err.Trace(d.manifestURL)
+	}
+	reader, err := manifestClnt.Get(nil)
+	if err != nil {
+		return nil, err.Trace(d.manifestURL)
+	}
+	defer reader.Close()
+	var manifest mirrorDedupManifestV1
+	if e := json.NewDecoder(reader).Decode(&manifest); e != nil {
+		return nil, probe.NewError(e).Trace(d.manifestURL)
+	}
+	if manifest.Version != mirrorDedupManifestVersion {
+		return nil, errInvalidArgument().Trace(d.manifestURL, manifest.Version)
+	}
+	for sum, key := range manifest.Objects {
+		d.sums[sum] = key
+		d.keys[key] = sum
+	}
+	return d, nil
+}
+
+// key - the key of a target URL in the manifest.
+func (d *mirrorDedup) key(u clientURL) string {
+	return relativeKey(d.root, u)
+}
+
+// objectURL - the URL of a key of the manifest.
+func (d *mirrorDedup) objectURL(key string) clientURL {
+	u := d.root
+	u.Path = strings.TrimSuffix(u.Path, string(u.Separator)) + string(u.Separator) + strings.Replace(key, "/", string(u.Separator), -1)
+	return u
+}
+
+// unlink - forget the content of key, its content is kept for another
+// key with the same content if any. The mutex must be held.
+func (d *mirrorDedup) unlink(key string) {
+	sum, ok := d.keys[key]
+	if !ok {
+		return
+	}
+	delete(d.keys, key)
+	d.changed = true
+	if d.sums[sum] != key {
+		return
+	}
+	delete(d.sums, sum)
+	for other, otherSum := range d.keys {
+		if otherSum == sum {
+			d.sums[sum] = other
+			return
+		}
+	}
+}
+
+// add - the target u has the content with SHA-256 sum.
+func (d *mirrorDedup) add(sum string, u clientURL) {
+	if d == nil {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	key := d.key(u)
+	d.unlink(key)
+	d.sums[sum], d.keys[key] = key, sum
+	d.changed = true
+}
+
+// remove - the target u was removed.
+func (d *mirrorDedup) remove(u clientURL) {
+	if d == nil {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.unlink(d.key(u))
+}
+
+// lookup - the key of an object with the content of SHA-256 sum.
+func (d *mirrorDedup) lookup(sum string) (string, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	key, ok := d.sums[sum]
+	return key, ok
+}
+
+// copyDuplicate - copy the object of the target with the content of
+// SHA-256 sum to the target of sURLs. Returns the path of the object
+// copied, or "" when there is none and the source has to be uploaded.
+func (d *mirrorDedup) copyDuplicate(sURLs URLs, sum string, progress io.Reader, encKeyDB map[string][]prefixSSEPair) (string, *probe.Error) {
+	key, ok := d.lookup(sum)
+	if !ok {
+		return "", nil
+	}
+	duplicateURL := d.objectURL(key)
+	duplicatePath := filepath.ToSlash(filepath.Join(d.targetAlias, duplicateURL.Path))
+	duplicateSSE := getSSE(duplicatePath, encKeyDB[d.targetAlias])
+
+	// The manifest is only trusted for objects which are still the
+	// same, of the same size and, if recorded, of the same SHA-256.
+	clnt, err := newClientFromAlias(d.targetAlias, duplicateURL.String())
+	if err != nil {
+		return "", err.Trace(duplicatePath)
+	}
+	st, err := clnt.Stat(globalContext, false, true, duplicateSSE)
+	if err != nil && !isDedupNotFound(err) {
+		return "", err.Trace(duplicatePath)
+	}
+	if err != nil || st.Size != sURLs.SourceContent.Size || (st.Metadata[mirrorDedupMetaKey] != "" && st.Metadata[mirrorDedupMetaKey] != sum) {
+		d.remove(duplicateURL)
+		return "", nil
+	}
+
+	targetURL := sURLs.TargetContent.URL
+	if d.key(targetURL) != key {
+		// Without metadata of its own, the copy keeps the metadata of
+		// the duplicate, its SHA-256 included.
+		targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, targetURL.Path))
+		err = copySourceToTargetURL(sURLs.TargetAlias, targetURL.String(), duplicateURL.Path, st.Size, progress,
+			duplicateSSE, getSSE(targetPath, encKeyDB[sURLs.TargetAlias]), sURLs.TargetContent.Metadata)
+		if err != nil {
+			return "", err.Trace(duplicatePath)
+		}
+	}
+	d.add(sum, targetURL)
+	return duplicatePath, nil
+}
+
+// Close - save the manifest when objects were added or removed.
+func (d *mirrorDedup) Close() *probe.Error {
+	if d == nil {
+		return nil
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if !d.changed {
+		return nil
+	}
+	manifestBytes, e := json.Marshal(mirrorDedupManifestV1{
+		Version: mirrorDedupManifestVersion,
+		Objects: d.sums,
+	})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	metadata := map[string]string{"Content-Type": "application/json"}
+	_, err := putTargetStream(globalContext, d.targetAlias, d.manifestURL, bytes.NewReader(manifestBytes), int64(len(manifestBytes)), metadata, nil, nil)
+	if err != nil {
+		return err.Trace(d.manifestURL)
+	}
+	d.changed = false
+	return nil
+}
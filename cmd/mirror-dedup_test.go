@@ -0,0 +1,103 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestMirrorDedup(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-dedup-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(root, "config"))
+	mcCfg := newMcConfig()
+	loadMcConfig = func() (*configV9, *probe.Error) { return mcCfg, nil }
+
+	target := filepath.Join(root, "target")
+	if e = os.MkdirAll(target, 0700); e != nil {
+		t.Fatal(e)
+	}
+	// A target without a manifest has no known objects.
+	d, err := newMirrorDedup(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.sums) != 0 {
+		t.Fatalf("expected no objects, got %v", d.sums)
+	}
+
+	d.add("1", d.objectURL("a.txt"))
+	d.add("1", d.objectURL("dir/b.txt"))
+	d.add("2", d.objectURL("dir/c.txt"))
+	// An overwritten object has no longer its previous content.
+	d.add("3", d.objectURL("dir/c.txt"))
+	// A removed object is replaced by another with the same content.
+	d.remove(d.objectURL("dir/b.txt"))
+
+	testCases := []struct {
+		sum   string
+		key   string
+		found bool
+	}{
+		{"1", "a.txt", true},
+		{"2", "", false},
+		{"3", "dir/c.txt", true},
+	}
+	check := func(d *mirrorDedup) {
+		for i, testCase := range testCases {
+			key, found := d.lookup(testCase.sum)
+			if key != testCase.key || found != testCase.found {
+				t.Errorf("Test %d: expected %q %v, got %q %v", i+1, testCase.key, testCase.found, key, found)
+			}
+		}
+	}
+	check(d)
+
+	// The manifest is saved as an object of the target and loaded again.
+	if err = d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, e = os.Stat(filepath.Join(target, mirrorDedupManifest)); e != nil {
+		t.Fatal(e)
+	}
+	if d, err = newMirrorDedup(target); err != nil {
+		t.Fatal(err)
+	}
+	check(d)
+	if key := d.key(d.objectURL("dir/c.txt")); key != "dir/c.txt" {
+		t.Errorf("expected key dir/c.txt, got %q", key)
+	}
+
+	// A manifest of another version is refused.
+	if e = ioutil.WriteFile(filepath.Join(target, mirrorDedupManifest), []byte(`{"version":"2"}`), 0600); e != nil {
+		t.Fatal(e)
+	}
+	if _, err = newMirrorDedup(target); err == nil {
+		t.Fatal("expected an error for an unknown manifest version")
+	}
+}
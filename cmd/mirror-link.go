@@ -0,0 +1,54 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// linkFromSnapshot - hard link the file of a previous snapshot in
+// linkDest to targetPath instead of copying the source, when the
+// snapshot file is unchanged: same size and not older than the source.
+// Returns false if the source has to be copied.
+func linkFromSnapshot(linkDest, targetRoot, targetPath string, source *clientContent) bool {
+	relPath, e := filepath.Rel(filepath.Clean(targetRoot), filepath.Clean(targetPath))
+	if e != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return false
+	}
+	snapshotPath := filepath.Join(linkDest, relPath)
+	st, e := os.Lstat(snapshotPath)
+	if e != nil || !st.Mode().IsRegular() {
+		return false
+	}
+	if st.Size() != source.Size || source.Time.After(st.ModTime()) {
+		return false
+	}
+	if targetSt, e := os.Lstat(targetPath); e == nil && os.SameFile(st, targetSt) {
+		return true
+	}
+	if e = os.MkdirAll(filepath.Dir(targetPath), 0777); e != nil {
+		return false
+	}
+	// An outdated target is replaced by --overwrite.
+	if e = os.Remove(targetPath); e != nil && !os.IsNotExist(e) {
+		return false
+	}
+	// Hard links fail across file systems, the source is copied then.
+	return os.Link(snapshotPath, targetPath) == nil
+}
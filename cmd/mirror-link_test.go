@@ -0,0 +1,85 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLinkFromSnapshot(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-link-dest-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	snapshot := filepath.Join(root, "2019-06-01")
+	target := filepath.Join(root, "2019-06-02")
+	if e = os.MkdirAll(filepath.Join(snapshot, "dir"), 0700); e != nil {
+		t.Fatal(e)
+	}
+	if e = ioutil.WriteFile(filepath.Join(snapshot, "dir", "photo.jpg"), []byte("photo"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	snapshotTime := time.Now().Add(-time.Hour)
+	if e = os.Chtimes(filepath.Join(snapshot, "dir", "photo.jpg"), snapshotTime, snapshotTime); e != nil {
+		t.Fatal(e)
+	}
+
+	testCases := []struct {
+		targetPath string
+		size       int64
+		modTime    time.Time
+		linked     bool
+	}{
+		// Unchanged since the snapshot.
+		{filepath.Join(target, "dir", "photo.jpg"), 5, snapshotTime.Add(-time.Minute), true},
+		// Linked again to the same snapshot file.
+		{filepath.Join(target, "dir", "photo.jpg"), 5, snapshotTime, true},
+		// Modified after the snapshot.
+		{filepath.Join(target, "dir", "photo.jpg"), 5, snapshotTime.Add(time.Minute), false},
+		// Size differs.
+		{filepath.Join(target, "dir", "photo.jpg"), 6, snapshotTime, false},
+		// Not in the snapshot.
+		{filepath.Join(target, "dir", "new.jpg"), 5, snapshotTime, false},
+		// Folders are never linked.
+		{filepath.Join(target, "dir"), 5, snapshotTime, false},
+		// Outside of the target.
+		{filepath.Join(root, "photo.jpg"), 5, snapshotTime, false},
+	}
+	for i, testCase := range testCases {
+		source := &clientContent{Size: testCase.size, Time: testCase.modTime}
+		if linked := linkFromSnapshot(snapshot, target, testCase.targetPath, source); linked != testCase.linked {
+			t.Errorf("Test %d: expected linked %v, got %v", i+1, testCase.linked, linked)
+		}
+	}
+
+	snapshotSt, e := os.Stat(filepath.Join(snapshot, "dir", "photo.jpg"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	targetSt, e := os.Stat(filepath.Join(target, "dir", "photo.jpg"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !os.SameFile(snapshotSt, targetSt) {
+		t.Error("expected the target to be a hard link of the snapshot")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var mirrorLockFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "lock",
+		Usage: "exit if another mirror holding the lock FILE is running, for overlapping scheduled runs",
+	},
+	cli.BoolFlag{
+		Name:  "lock-target",
+		Usage: "exit if another mirror to the same target is running, with a lock file in the config folder",
+	},
+}
+
+// mirrorLockInfo - written to a lock file by the mirror holding it.
+type mirrorLockInfo struct {
+	PID     int       `json:"pid"`
+	Started time.Time `json:"started"`
+	Target  string    `json:"target"`
+}
+
+// mirrorLockedMessage - a mirror exits as the lock is held by another.
+type mirrorLockedMessage struct {
+	Status string         `json:"status"`
+	Lock   string         `json:"lock"`
+	Holder mirrorLockInfo `json:"holder"`
+}
+
+// String colorized locked message.
+func (m mirrorLockedMessage) String() string {
+	holder := "another mirror"
+	if m.Holder.PID != 0 {
+		holder = fmt.Sprintf("the mirror to `%s` of pid %d, running since %s,", m.Holder.Target, m.Holder.PID,
+			formatDate(m.Holder.Started))
+	}
+	return console.Colorize("MirrorLocked", fmt.Sprintf("Lock `%s` is held by %s exiting.", m.Lock, holder))
+}
+
+// JSON jsonified locked message.
+func (m mirrorLockedMessage) JSON() string {
+	m.Status = "locked"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// targetLockPath - the lock file of the mirrors to a target, aliases of
+// the same host share the lock of their expanded URL.
+func targetLockPath(targetURL string) (string, *probe.Error) {
+	alias, expandedURL, _, err := expandAlias(targetURL)
+	if err != nil {
+		return "", err.Trace(targetURL)
+	}
+	if alias == "" {
+		// Relative paths of the same folder share a lock.
+		if expandedURL, e := filepath.Abs(expandedURL); e == nil {
+			targetURL = expandedURL
+		}
+	} else {
+		targetURL = strings.TrimRight(expandedURL, "/")
+	}
+	sum := sha256.Sum256([]byte(targetURL))
+	return filepath.Join(mustGetMcConfigDir(), "locks", "mirror-"+hex.EncodeToString(sum[:8])+".lock"), nil
+}
+
+// mirrorLock - an exclusive lock of a file, held until it is released or
+// the process exits.
+type mirrorLock struct {
+	file *os.File
+}
+
+// acquireMirrorLock - lock path for the mirror to targetURL. When another
+// process holds it, no lock is returned with the information written by
+// the holder, if any.
+func acquireMirrorLock(path, targetURL string) (*mirrorLock, *mirrorLockInfo, *probe.Error) {
+	if e := os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+		return nil, nil, probe.NewError(e).Trace(path)
+	}
+	file, locked, e := tryLockFile(path)
+	if e != nil {
+		return nil, nil, probe.NewError(e).Trace(path)
+	}
+	if !locked {
+		var holder mirrorLockInfo
+		if data, e := ioutil.ReadFile(path); e == nil {
+			json.Unmarshal(data, &holder)
+		}
+		return nil, &holder, nil
+	}
+
+	// The lock file is never removed, another process may be waiting
+	// for the lock of its inode, only its content is replaced.
+	data, e := json.Marshal(mirrorLockInfo{PID: os.Getpid(), Started: UTCNow(), Target: targetURL})
+	if e == nil {
+		if e = file.Truncate(0); e == nil {
+			_, e = file.WriteAt(data, 0)
+		}
+	}
+	if e != nil {
+		file.Close()
+		return nil, nil, probe.NewError(e).Trace(path)
+	}
+	return &mirrorLock{file: file}, nil, nil
+}
+
+// Release - release the lock.
+func (l *mirrorLock) Release() {
+	if l != nil {
+		l.file.Close()
+	}
+}
+
+// lockMirror - hold the lock of --lock or --lock-target for a mirror to
+// targetURL. Returns false when another mirror holds it, and the mirror
+// has to exit.
+func lockMirror(ctx *cli.Context, targetURL string) (*mirrorLock, bool) {
+	path := ctx.String("lock")
+	if ctx.Bool("lock-target") {
+		var err *probe.Error
+		path, err = targetLockPath(targetURL)
+		fatalIf(err, "Unable to find the lock of `"+targetURL+"`.")
+	}
+	if path == "" {
+		return nil, true
+	}
+	lock, holder, err := acquireMirrorLock(path, targetURL)
+	fatalIf(err, "Unable to lock `"+path+"`.")
+	if holder != nil {
+		printMsg(mirrorLockedMessage{Lock: path, Holder: *holder})
+		return nil, false
+	}
+	return lock, true
+}
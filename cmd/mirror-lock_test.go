@@ -0,0 +1,108 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestAcquireMirrorLock(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-mirror-lock-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	path := filepath.Join(root, "locks", "backup.lock")
+
+	lock, holder, err := acquireMirrorLock(path, "play/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock == nil || holder != nil {
+		t.Fatalf("expected the lock, found holder %v", holder)
+	}
+
+	// Another mirror finds the holder while the lock is held.
+	other, holder, err := acquireMirrorLock(path, "play/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other != nil || holder == nil {
+		t.Fatal("expected the lock to be held")
+	}
+	if holder.PID != os.Getpid() || holder.Target != "play/backup" {
+		t.Fatalf("unexpected holder %v", holder)
+	}
+
+	lock.Release()
+	other, holder, err = acquireMirrorLock(path, "play/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == nil || holder != nil {
+		t.Fatal("expected the released lock")
+	}
+	other.Release()
+}
+
+func TestTargetLockPath(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-mirror-lock-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(root, "config"))
+	mcCfg := newMcConfig()
+	loadMcConfig = func() (*configV9, *probe.Error) { return mcCfg, nil }
+
+	wd, e := os.Getwd()
+	if e != nil {
+		t.Fatal(e)
+	}
+	testCases := []struct {
+		first, second string
+		same          bool
+	}{
+		{"backup", "./backup/", true},
+		{"backup", filepath.Join(wd, "backup"), true},
+		{"backup", "backup2", false},
+	}
+	for i, testCase := range testCases {
+		first, err := targetLockPath(testCase.first)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		second, err := targetLockPath(testCase.second)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if (first == second) != testCase.same {
+			t.Errorf("Test %d: expected same lock %v, found `%s` and `%s`", i+1, testCase.same, first, second)
+		}
+		if filepath.Dir(first) != filepath.Join(root, "config", "locks") {
+			t.Errorf("Test %d: unexpected lock `%s`", i+1, first)
+		}
+	}
+}
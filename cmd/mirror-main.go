@@ -83,10 +83,42 @@ var (
 			Name:  "storage-class, sc",
 			Usage: "specify storage class for new object(s) on target",
 		},
+		preserveMetadataFlag,
+		aclFlag,
 		cli.StringFlag{
 			Name:  "encrypt",
 			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
 		},
+		cli.BoolFlag{
+			Name:  "tui",
+			Usage: "display a full screen transfer dashboard",
+		},
+		cli.BoolFlag{
+			Name:  "create-target",
+			Usage: "create the target bucket if it does not exist",
+		},
+		cli.BoolFlag{
+			Name:  "skip-preflight",
+			Usage: "skip checking target bucket, permissions and versioning before mirroring",
+		},
+		cli.StringFlag{
+			Name:  "link-dest, hardlink-dest",
+			Usage: "hard link files unchanged in a previous local snapshot DIR instead of copying them",
+		},
+		dedupFlag,
+		writeManifestFlag,
+		hashFlag,
+		rewindFlag,
+		errorLogFlag,
+		maxErrorsFlag,
+		noCacheFlag,
+		normalizeFlag,
+		sanitizeFlag,
+		renameFlag,
+		parallelFlag,
+		adaptiveFlag,
+		profileFlag,
+		controlSocketFlag,
 	}
 )
 
@@ -95,8 +127,8 @@ var mirrorCmd = cli.Command{
 	Name:   "mirror",
 	Usage:  "synchronize object(s) to a remote site",
 	Action: mainMirror,
-	Before: setGlobalsFromContext,
-	Flags:  append(append(mirrorFlags, ioFlags...), globalFlags...),
+	Before: setGlobalsFromProfile,
+	Flags:  append(append(append(append(append(append(mirrorFlags, contentHeaderFlags...), mirrorLockFlags...), metricsFlags...), ioFlags...), uploadFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -146,6 +178,71 @@ EXAMPLES:
 
   11. Mirror server encrypted objects from MinIO cloud storage to a bucket on Amazon S3 cloud storage
       $ {{.HelpName}} --encrypt-key "minio/photos=32byteslongsecretkeymustbegiven1,s3/archive=32byteslongsecretkeymustbegiven2" minio/photos/ s3/archive/
+
+  12. Continuously mirror a local folder to MinIO cloud storage while monitoring it on a full screen dashboard.
+      Press 'p' to pause or resume transfers and 'q' to quit.
+      $ {{.HelpName}} --tui --watch /var/lib/backups play/backups
+
+  13. Mirror a local folder to a bucket on Amazon S3 cloud storage, creating the bucket if it does not exist.
+      Mirror fails before copying anything if the bucket cannot be written to.
+      $ {{.HelpName}} --create-target backup/ s3/new-archive
+
+  14. Continuously mirror a local folder while a supervisor queries progress, pauses or cancels it over a socket.
+      $ {{.HelpName}} --watch --control-socket /run/mc-mirror.sock /var/lib/backups play/backups
+
+  15. Take a daily snapshot of a bucket to a local folder, hard linking files unchanged since yesterday's snapshot.
+      $ {{.HelpName}} --link-dest /backups/2019-06-01 play/photos /backups/2019-06-02
+
+  16. Restore a versioned bucket to another bucket as it was 2 days ago.
+      $ {{.HelpName}} --rewind 2d s3/documents s3/documents-restored
+
+  17. Mirror a folder logging the files which failed to upload, then retry only those with 'mc cp'.
+      $ {{.HelpName}} --error-log mirror-errors.log backup/ play/archive/
+      $ mc cp --files-from mirror-errors.log
+
+  18. Mirror a bucket overnight past a few unreadable objects, giving up once more than 100 objects failed.
+      $ {{.HelpName}} --max-errors 100 s3/photos play/photos
+
+  19. Mirror to a bucket changed by others since the last mirror, listing it instead of using its cached listing.
+      $ {{.HelpName}} --no-cache backup/ play/archive/
+
+  20. Mirror photos from a Mac, uploading file names in composed form so mirrors from other systems converge.
+      $ {{.HelpName}} --normalize nfc ~/Pictures play/photos
+
+  21. Mirror a bucket written by untrusted clients to a local folder, escaping names such as '..' instead of skipping them.
+      $ {{.HelpName}} --sanitize escape s3/uploads /srv/uploads
+
+  22. Mirror a folder to a gateway without multipart support, uploading every object with a single PUT.
+      $ {{.HelpName}} --disable-multipart photos/ gw/photos
+
+  23. Mirror a folder over a network of unknown capacity, tuning the number of parallel uploads while mirroring.
+      $ {{.HelpName}} --adaptive backup/ play/archive/
+
+  24. Mirror a dataset with many duplicate files, uploading each content once and copying it on the target for the others.
+      $ {{.HelpName}} --dedup datasets/ play/datasets
+
+  25. Deliver a folder with a manifest of the SHA-256 of its files, for the recipient to verify.
+      $ {{.HelpName}} --write-manifest delivery.manifest delivery/ s3/deliveries/2019-10
+      $ mc verify --manifest delivery.manifest s3/deliveries/2019-10
+      $ {{.HelpName}} --write-manifest delivery.manifest --hash blake3 delivery/ s3/deliveries/2019-11
+
+  26. Mirror a folder with the flags of the profile 'backup' of the config, a flag of the command line overrides the profile.
+      $ {{.HelpName}} --profile backup --parallel 16 backup/ s3/backups/
+
+  27. Mirror a folder every 5 minutes from cron, a run exits while the previous one is still mirroring.
+      */5 * * * * {{.HelpName}} --lock-target --quiet /var/www s3/backups/www
+
+  28. Mirror a bucket to a bucket of another account, keeping the tags of the objects and granting the owner full control.
+      $ {{.HelpName}} --preserve-metadata --acl bucket-owner-full-control s3/mybucket s3/partner-bucket
+
+  29. Watch a folder and serve Prometheus metrics of the transfers on localhost:9464, sending them to statsd as well.
+      $ {{.HelpName}} --watch --metrics-addr :9464 --metrics-statsd 127.0.0.1:8125 /var/lib/backups play/backups
+
+  30. Mirror a bucket, moving the objects of the folder 'raw' into the folder 'processed' and lowering '.JPG' extensions.
+      $ {{.HelpName}} --rename 's/^raw\//processed\//' --rename 's/\.jpg$/.jpg/i' s3/mybucket s3/archive
+
+  31. Publish a built website to a bucket served by a CDN, which may cache the pages for 5 minutes.
+      $ {{.HelpName}} --overwrite --remove --cache-control 'public, max-age=300' public/ s3/www
 `,
 }
 
@@ -171,6 +268,31 @@ type mirrorJob struct {
 	// channel for status messages
 	statusCh chan URLs
 
+	// exit status of failed copies and removals
+	failures failureStatus
+
+	// objects transferred, skipped and failed
+	summary *transferSummary
+
+	// served on --control-socket, nil if not requested
+	control *transferControl
+
+	// served on --metrics-addr and sent to --metrics-statsd, nil if
+	// not requested
+	metrics *operationMetrics
+
+	// written by --error-log, nil if not requested
+	errLog *errorLog
+
+	// listing of the target cached for the next mirror, nil if disabled
+	cache *mirrorCache
+
+	// content of the objects of the target for --dedup, nil if disabled
+	dedup *mirrorDedup
+
+	// written by --write-manifest, nil if not requested
+	manifest *checksumManifest
+
 	TotalObjects int64
 	TotalBytes   int64
 
@@ -181,6 +303,18 @@ type mirrorJob struct {
 	olderThan, newerThan                   string
 	storageClass                           string
 
+	// canned ACL and content headers of copied objects, tags are copied
+	// with preserveMetadata
+	acl              string
+	contentHeaders   map[string]string
+	preserveMetadata bool
+
+	// content headers of each copied object by its source path, if set
+	objectHeaders func(sourcePath string) map[string]string
+
+	// previous local snapshot to hard link unchanged files from
+	linkDest string
+
 	excludeOptions []string
 	encKeyDB       map[string][]prefixSSEPair
 }
@@ -193,10 +327,18 @@ type mirrorMessage struct {
 	Size       int64  `json:"size"`
 	TotalCount int64  `json:"totalCount"`
 	TotalSize  int64  `json:"totalSize"`
+	Linked     string `json:"linked,omitempty"`
+	Duplicate  string `json:"duplicate,omitempty"`
 }
 
 // String colorized mirror message
 func (m mirrorMessage) String() string {
+	if m.Linked != "" {
+		return console.Colorize("Mirror", fmt.Sprintf("`%s` -> `%s` (linked to `%s`)", m.Source, m.Target, m.Linked))
+	}
+	if m.Duplicate != "" {
+		return console.Colorize("Mirror", fmt.Sprintf("`%s` -> `%s` (copied from `%s`)", m.Source, m.Target, m.Duplicate))
+	}
 	return console.Colorize("Mirror", fmt.Sprintf("`%s` -> `%s`", m.Source, m.Target))
 }
 
@@ -237,6 +379,7 @@ func (mj *mirrorJob) doRemove(sURLs URLs) URLs {
 			return sURLs.WithError(pErr)
 		}
 	}
+	mj.dedup.remove(sURLs.TargetContent.URL)
 
 	return sURLs.WithError(nil)
 }
@@ -252,6 +395,7 @@ func (mj *mirrorJob) doMirror(ctx context.Context, cancelMirror context.CancelFu
 	// and accounting readers under relevant conditions.
 	if mj.isFake {
 		mj.status.Add(sURLs.SourceContent.Size)
+		mj.control.add(sURLs.SourceContent.Size)
 		return sURLs.WithError(nil)
 	}
 
@@ -269,9 +413,92 @@ func (mj *mirrorJob) doMirror(ctx context.Context, cancelMirror context.CancelFu
 		}
 		sURLs.TargetContent.Metadata["X-Amz-Storage-Class"] = mj.storageClass
 	}
+	if mj.acl != "" {
+		if sURLs.TargetContent.Metadata == nil {
+			sURLs.TargetContent.Metadata = make(map[string]string)
+		}
+		sURLs.TargetContent.Metadata[amzACL] = mj.acl
+	}
+	if len(mj.contentHeaders) > 0 || mj.objectHeaders != nil {
+		if sURLs.TargetContent.Metadata == nil {
+			sURLs.TargetContent.Metadata = make(map[string]string)
+		}
+		for k, v := range mj.contentHeaders {
+			sURLs.TargetContent.Metadata[k] = v
+		}
+		if mj.objectHeaders != nil {
+			for k, v := range mj.objectHeaders(sourceURL.Path) {
+				sURLs.TargetContent.Metadata[k] = v
+			}
+		}
+	}
+	sURLs.PreserveMetadata = mj.preserveMetadata
 
 	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 	targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
+
+	if mj.linkDest != "" && linkFromSnapshot(mj.linkDest, mj.targetURL, targetURL.Path, sURLs.SourceContent) {
+		mj.status.PrintMsg(mirrorMessage{
+			Source:     sourcePath,
+			Target:     targetPath,
+			Size:       length,
+			TotalCount: sURLs.TotalCount,
+			TotalSize:  sURLs.TotalSize,
+			Linked:     mj.linkDest,
+		})
+		mj.status.Add(length)
+		mj.control.add(length)
+		sURLs.Skipped = true
+		return sURLs.WithError(nil)
+	}
+
+	// --dedup compares SHA-256 sums and the manifest has the sums of
+	// --hash, both are computed reading the source once.
+	var sum, manifestSum string
+	var algorithms []checksumAlgorithm
+	if mj.dedup != nil {
+		algorithms = append(algorithms, checksumSHA256)
+	}
+	if mj.manifest != nil {
+		algorithms = append(algorithms, mj.manifest.algorithm)
+	}
+	if len(algorithms) > 0 {
+		sums, err := contentChecksums(sourceAlias, sourceURL.String(), mj.encKeyDB, algorithms...)
+		if err != nil {
+			return sURLs.WithError(err.Trace(sourceURL.String()))
+		}
+		if mj.dedup != nil {
+			sum = sums[0]
+		}
+		manifestSum = sums[len(sums)-1]
+	}
+
+	// With --dedup a file with the content of an object of the target
+	// is copied from that object instead of being uploaded.
+	if mj.dedup != nil {
+		duplicate, err := mj.dedup.copyDuplicate(sURLs, sum, mj.metrics.hook(mj.control.hook(mj.status)), mj.encKeyDB)
+		if err != nil {
+			return sURLs.WithError(err.Trace(sourceURL.String()))
+		}
+		if duplicate != "" {
+			mj.status.PrintMsg(mirrorMessage{
+				Source:     sourcePath,
+				Target:     targetPath,
+				Size:       length,
+				TotalCount: sURLs.TotalCount,
+				TotalSize:  sURLs.TotalSize,
+				Duplicate:  duplicate,
+			})
+			mj.manifest.add(targetURL, length, manifestSum)
+			sURLs.Skipped = true
+			return sURLs.WithError(nil)
+		}
+		if sURLs.TargetContent.UserMetadata == nil {
+			sURLs.TargetContent.UserMetadata = make(map[string]string)
+		}
+		sURLs.TargetContent.UserMetadata[mirrorDedupMetaKey] = sum
+	}
+
 	mj.status.PrintMsg(mirrorMessage{
 		Source:     sourcePath,
 		Target:     targetPath,
@@ -279,33 +506,61 @@ func (mj *mirrorJob) doMirror(ctx context.Context, cancelMirror context.CancelFu
 		TotalCount: sURLs.TotalCount,
 		TotalSize:  sURLs.TotalSize,
 	})
-	return uploadSourceToTargetURL(ctx, sURLs, mj.status, mj.encKeyDB)
+	sURLs = uploadSourceToTargetURL(ctx, sURLs, mj.metrics.hook(mj.control.hook(mj.status)), mj.encKeyDB)
+	if sURLs.Error == nil && len(algorithms) > 0 {
+		mj.dedup.add(sum, targetURL)
+		mj.manifest.add(targetURL, length, manifestSum)
+	}
+	return sURLs
 }
 
 // Update progress status
-func (mj *mirrorJob) monitorMirrorStatus() (errDuringMirror bool) {
+func (mj *mirrorJob) monitorMirrorStatus() error {
 	// now we want to start the progress bar
 	mj.status.Start()
-	defer mj.status.Finish()
 
 	for sURLs := range mj.statusCh {
+		// Copies cancelled by an interrupt are not failures.
+		if sURLs.Error != nil && isInterrupted() {
+			continue
+		}
+		if n, ok := mj.status.(transferDoneNotifier); ok {
+			n.transferDone(sURLs)
+		}
+		mj.control.done(sURLs)
+		mj.metrics.done(sURLs)
+		mj.summary.done(sURLs)
 		if sURLs.Error != nil {
+			// Objects with unsafe names are skipped on every run, they
+			// are only warned about.
+			if _, ok := sURLs.Error.ToGoError().(UnsafeObjectName); ok {
+				mj.status.errorIf(sURLs.Error.Trace(), "Skipping object with an unsafe name.")
+				continue
+			}
+			mj.cache.invalidate()
 			switch {
 			case sURLs.SourceContent != nil:
 				if !isErrIgnored(sURLs.Error) {
-					errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
+					mj.status.errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
 						fmt.Sprintf("Failed to copy `%s`.", sURLs.SourceContent.URL.String()))
-					errDuringMirror = true
+					mj.failures.fail(sURLs.Error)
+					mj.errLog.log(sURLs)
 				}
 			case sURLs.TargetContent != nil:
 				// When sURLs.SourceContent is nil, we know that we have an error related to removing
-				errorIf(sURLs.Error.Trace(sURLs.TargetContent.URL.String()),
+				mj.status.errorIf(sURLs.Error.Trace(sURLs.TargetContent.URL.String()),
 					fmt.Sprintf("Failed to remove `%s`.", sURLs.TargetContent.URL.String()))
-				errDuringMirror = true
+				mj.failures.fail(sURLs.Error)
 			default:
-				errorIf(sURLs.Error.Trace(), "Failed to perform mirroring action.")
-				errDuringMirror = true
+				mj.status.errorIf(sURLs.Error.Trace(), "Failed to perform mirroring action.")
+				mj.failures.fail(sURLs.Error)
+			}
+			if err := mj.failures.exceeded(); err != nil {
+				mj.status.errorIf(err.Trace(), "Too many failed objects.")
+				break
 			}
+		} else {
+			mj.failures.success()
 		}
 
 		if sURLs.SourceContent != nil {
@@ -317,7 +572,14 @@ func (mj *mirrorJob) monitorMirrorStatus() (errDuringMirror bool) {
 		}
 	}
 
-	return
+	mj.status.Finish()
+	msg := mj.summary.message(mj.TotalBytes)
+	msg.Interrupted = isInterrupted()
+	printSummaryMsg(msg)
+	if msg.Interrupted {
+		return exitStatus(globalInterruptExitStatus)
+	}
+	return mj.failures.exitError()
 }
 
 // this goroutine will watch for notifications, and add modified objects to the queue
@@ -362,10 +624,20 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 				continue
 			}
 
-			targetPath := urlJoinPath(mj.targetURL, sourceSuffix)
+			targetAlias, _, _ := mustExpandAlias(mj.targetURL)
+			targetSuffix, err := renameSuffix(sourceSuffix)
+			if err != nil {
+				mj.statusCh <- URLs{SourceAlias: sourceAlias, SourceContent: &clientContent{URL: *sourceURL}, Error: err}
+				continue
+			}
+			targetPath, err := joinTargetPath(mj.targetURL, normalizeUploadKey(targetSuffix), sourceAlias == "", targetAlias == "")
+			if err != nil {
+				mj.statusCh <- URLs{SourceAlias: sourceAlias, SourceContent: &clientContent{URL: *sourceURL}, Error: err}
+				continue
+			}
 
 			// newClient needs the unexpanded  path, newCLientURL needs the expanded path
-			targetAlias, expandedTargetPath, _ := mustExpandAlias(targetPath)
+			_, expandedTargetPath, _ := mustExpandAlias(targetPath)
 			targetURL := newClientURL(expandedTargetPath)
 			sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 			srcSSE := getSSE(sourcePath, mj.encKeyDB[sourceAlias])
@@ -388,7 +660,7 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 						mj.statusCh <- mirrorURL.WithError(err)
 						continue
 					}
-					sourceContent, err := sourceClient.Stat(false, false, srcSSE)
+					sourceContent, err := sourceClient.Stat(ctx, false, false, srcSSE)
 					if err != nil {
 						// source doesn't exist anymore
 						mj.statusCh <- mirrorURL.WithError(err)
@@ -402,7 +674,7 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 					}
 					shouldQueue := false
 					if !mj.isOverwrite {
-						_, err = targetClient.Stat(false, false, tgtSSE)
+						_, err = targetClient.Stat(ctx, false, false, tgtSSE)
 						if err == nil {
 							continue
 						} // doesn't exist
@@ -413,7 +685,8 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 						mirrorURL.TotalSize = mj.TotalBytes
 						// adjust total, because we want to show progress of the item still queued to be copied.
 						mj.status.SetTotal(mj.status.Total() + sourceContent.Size).Update()
-						mj.statusCh <- mj.doMirror(ctx, cancelMirror, mirrorURL)
+						mj.control.addTotal(sourceContent.Size)
+						mj.statusCh <- mj.metrics.track(func() URLs { return mj.doMirror(ctx, cancelMirror, mirrorURL) })()
 					}
 					continue
 				}
@@ -425,7 +698,7 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 						mj.statusCh <- mirrorURL.WithError(err)
 						return
 					}
-					_, err = targetClient.Stat(false, false, tgtSSE)
+					_, err = targetClient.Stat(ctx, false, false, tgtSSE)
 					if err == nil {
 						continue
 					} // doesn't exist
@@ -437,7 +710,8 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 					mirrorURL.TotalSize = mj.TotalBytes
 					// adjust total, because we want to show progress of the itemj stiil queued to be copied.
 					mj.status.SetTotal(mj.status.Total() + event.Size).Update()
-					mj.statusCh <- mj.doMirror(ctx, cancelMirror, mirrorURL)
+					mj.control.addTotal(event.Size)
+					mj.statusCh <- mj.metrics.track(func() URLs { return mj.doMirror(ctx, cancelMirror, mirrorURL) })()
 				}
 			} else if event.Type == EventRemove {
 				mirrorURL := URLs{
@@ -450,7 +724,7 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 				mirrorURL.TotalCount = mj.TotalObjects
 				mirrorURL.TotalSize = mj.TotalBytes
 				if mirrorURL.TargetContent != nil && mj.isRemove {
-					mj.statusCh <- mj.doRemove(mirrorURL)
+					mj.statusCh <- mj.metrics.track(func() URLs { return mj.doRemove(mirrorURL) })()
 				}
 			}
 
@@ -464,6 +738,8 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 			return
 		case <-mj.trapCh:
 			return
+		case <-ctx.Done():
+			return
 		}
 	}
 }
@@ -482,7 +758,7 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 		mj.parallel.wait()
 	}
 
-	URLsCh := prepareMirrorURLs(mj.sourceURL, mj.targetURL, mj.isFake, mj.isOverwrite, mj.isRemove, mj.excludeOptions, mj.encKeyDB)
+	URLsCh := prepareMirrorURLs(ctx, mj.sourceURL, mj.targetURL, mj.isFake, mj.isOverwrite, mj.isRemove, mj.excludeOptions, mj.cache, mj.encKeyDB)
 
 	for {
 		select {
@@ -512,6 +788,7 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 			mj.TotalBytes = totalBytes
 			mj.TotalObjects = totalObjects
 			mj.status.SetTotal(totalBytes)
+			mj.control.setTotal(totalBytes)
 
 			// Save total count.
 			sURLs.TotalCount = mj.TotalObjects
@@ -519,24 +796,32 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 			sURLs.TotalSize = mj.TotalBytes
 
 			if sURLs.SourceContent != nil {
-				mj.queueCh <- func() URLs {
+				mj.queueCh <- mj.metrics.track(func() URLs {
 					return mj.doMirror(ctx, cancelMirror, sURLs)
-				}
+				})
 			} else if sURLs.TargetContent != nil && mj.isRemove {
-				mj.queueCh <- func() URLs {
+				mj.queueCh <- mj.metrics.track(func() URLs {
 					return mj.doRemove(sURLs)
-				}
+				})
 			}
 		case <-mj.trapCh:
-			stopParallel()
+			mj.cache.invalidate()
+			// Cancel first, so incomplete uploads are aborted before
+			// the workers stop.
 			cancelMirror()
+			stopParallel()
+			return
+		case <-ctx.Done():
+			// Interrupted while watching, or timed out.
+			mj.cache.invalidate()
+			stopParallel()
 			return
 		}
 	}
 }
 
 // when using a struct for copying, we could save a lot of passing of variables
-func (mj *mirrorJob) mirror(ctx context.Context, cancelMirror context.CancelFunc) bool {
+func (mj *mirrorJob) mirror(ctx context.Context, cancelMirror context.CancelFunc) error {
 
 	var wg sync.WaitGroup
 
@@ -565,7 +850,7 @@ func (mj *mirrorJob) mirror(ctx context.Context, cancelMirror context.CancelFunc
 	return mj.monitorMirrorStatus()
 }
 
-func newMirrorJob(srcURL, dstURL string, isFake, isRemove, isOverwrite, isWatch bool, excludeOptions []string, olderThan, newerThan string, storageClass string, encKeyDB map[string][]prefixSSEPair) *mirrorJob {
+func newMirrorJob(srcURL, dstURL string, isFake, isRemove, isOverwrite, isWatch, isTUI, isAdaptive bool, parallel int, excludeOptions []string, olderThan, newerThan string, storageClass string, linkDest string, encKeyDB map[string][]prefixSSEPair) *mirrorJob {
 	mj := mirrorJob{
 		trapCh: signalTrap(os.Interrupt, syscall.SIGTERM, syscall.SIGKILL),
 		m:      new(sync.Mutex),
@@ -581,20 +866,30 @@ func newMirrorJob(srcURL, dstURL string, isFake, isRemove, isOverwrite, isWatch
 		olderThan:      olderThan,
 		newerThan:      newerThan,
 		storageClass:   storageClass,
+		linkDest:       linkDest,
 		encKeyDB:       encKeyDB,
 		statusCh:       make(chan URLs),
+		summary:        newTransferSummary(),
 		watcher:        NewWatcher(UTCNow()),
 	}
 
-	mj.parallel, mj.queueCh = newParallelManager(mj.statusCh)
+	if isAdaptive {
+		mj.parallel, mj.queueCh = newAdaptiveParallelManager(mj.statusCh, nil)
+	} else {
+		mj.parallel, mj.queueCh = newParallelManagerWithWorkers(mj.statusCh, parallel)
+	}
 
 	// we'll define the status to use here,
 	// do we want the quiet status? or the progressbar
-	var status = NewProgressStatus(mj.parallel)
+	var status Status
 	if globalQuiet {
 		status = NewQuietStatus(mj.parallel)
 	} else if globalJSON {
 		status = NewDummyStatus(mj.parallel)
+	} else if isTUI {
+		status = NewTUIStatus(fmt.Sprintf("mc mirror %s -> %s", srcURL, dstURL), mj.parallel)
+	} else {
+		status = NewProgressStatus(mj.parallel)
 	}
 	mj.status = status
 
@@ -626,7 +921,7 @@ func copyBucketPolicies(srcClt, dstClt Client, isOverwrite bool) *probe.Error {
 }
 
 // runMirror - mirrors all buckets to another S3 server
-func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) bool {
+func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) error {
 	// This is kept for backward compatibility, `--force` means
 	// --overwrite.
 	isOverwrite := ctx.Bool("force")
@@ -640,11 +935,18 @@ func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]pr
 		ctx.Bool("remove"),
 		isOverwrite,
 		ctx.Bool("watch"),
+		ctx.Bool("tui"),
+		ctx.Bool("adaptive"),
+		ctx.Int("parallel"),
 		ctx.StringSlice("exclude"),
 		ctx.String("older-than"),
 		ctx.String("newer-than"),
 		ctx.String("storage-class"),
+		ctx.String("link-dest"),
 		encKeyDB)
+	mj.acl = ctx.String("acl")
+	mj.contentHeaders = parseContentHeaders(ctx)
+	mj.preserveMetadata = ctx.Bool("preserve-metadata")
 
 	srcClt, err := newClient(srcURL)
 	fatalIf(err, "Unable to initialize `"+srcURL+"`.")
@@ -656,12 +958,25 @@ func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]pr
 		fatalIf(errDummy(), "Synchronizing bucket policies is only possible when both source & target point to S3 servers.")
 	}
 
+	if !ctx.Bool("skip-preflight") {
+		preflightFailed := runMirrorPreflight(srcClt, dstClt, mirrorPreflightOpts{
+			createTarget: ctx.Bool("create-target"),
+			region:       ctx.String("region"),
+			isFake:       ctx.Bool("fake"),
+			isRemove:     ctx.Bool("remove"),
+			isOverwrite:  isOverwrite,
+		})
+		if preflightFailed {
+			fatalIf(errDummy().Trace(dstURL), "Mirror preflight checks failed for `"+dstURL+"`.")
+		}
+	}
+
 	mirrorAllBuckets := (srcClt.GetURL().Type == objectStorage && srcClt.GetURL().Path == "/") ||
 		(dstClt.GetURL().Type == objectStorage && dstClt.GetURL().Path == "/")
 
 	if mirrorAllBuckets {
 		// Synchronize buckets using dirDifference function
-		for d := range dirDifference(srcClt, dstClt, srcURL, dstURL) {
+		for d := range dirDifference(globalContext, srcClt, dstClt, srcURL, dstURL) {
 			if d.Error != nil {
 				mj.status.fatalIf(d.Error, fmt.Sprintf("Failed to start monitoring."))
 			}
@@ -709,7 +1024,47 @@ func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]pr
 		}
 	}
 
-	ctxt, cancelMirror := context.WithCancel(context.Background())
+	control, err := newTransferControl(ctx.String("control-socket"))
+	fatalIf(err, "Unable to listen on the control socket.")
+	defer control.Close()
+	mj.control = control
+
+	metrics, err := newOperationMetrics("mirror", ctx.String("metrics-addr"), ctx.String("metrics-statsd"))
+	fatalIf(err, "Unable to serve the metrics.")
+	defer metrics.Close()
+	mj.metrics = metrics
+
+	errLog, err := newErrorLog(ctx.String("error-log"), false)
+	fatalIf(err, "Unable to create the error log.")
+	defer errLog.Close()
+	mj.errLog = errLog
+	mj.failures.setMaxErrors(checkMaxErrors(ctx))
+
+	if ctx.Bool("dedup") && !mj.isFake {
+		dedup, err := newMirrorDedup(dstURL)
+		fatalIf(err, "Unable to load the deduplication manifest of `"+dstURL+"`.")
+		mj.dedup = dedup
+		defer func() {
+			errorIf(mj.dedup.Close(), "Unable to save the deduplication manifest of `"+dstURL+"`.")
+		}()
+		// The manifest is neither mirrored nor removed.
+		mj.excludeOptions = append(mj.excludeOptions, mirrorDedupManifest)
+	}
+
+	if !mj.isFake {
+		manifest, err := newChecksumManifest(ctx.String("write-manifest"), dstClt.GetURL(), getChecksumAlgorithm(ctx))
+		fatalIf(err, "Unable to create the manifest.")
+		defer manifest.Close()
+		mj.manifest = manifest
+	}
+
+	// The target is cached for mirrors comparing all objects once.
+	if !ctx.Bool("no-cache") && !mj.isFake && !mj.isWatch && mj.olderThan == "" && mj.newerThan == "" {
+		mj.cache = newMirrorCache(dstURL)
+		defer mj.cache.Close()
+	}
+
+	ctxt, cancelMirror := context.WithCancel(globalContext)
 	defer cancelMirror()
 
 	// Start mirroring job
@@ -722,20 +1077,30 @@ func mainMirror(ctx *cli.Context) error {
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	// The source is stat'ed as it was at the point in time of --rewind.
+	if len(ctx.Args()) == 2 {
+		rewindSources(ctx.String("rewind"), ctx.Args().Get(0))
+	}
+
 	// check 'mirror' cli arguments.
 	checkMirrorSyntax(ctx, encKeyDB)
+	checkWritableTargets(ctx.Args().Get(1))
 
 	// Additional command specific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("MirrorLocked", color.New(color.FgYellow))
 
 	args := ctx.Args()
 
 	srcURL := args[0]
 	tgtURL := args[1]
 
-	if errorDetected := runMirror(srcURL, tgtURL, ctx, encKeyDB); errorDetected {
-		return exitStatus(globalErrorExitStatus)
+	// Overlapping runs leave the target to the mirror holding the lock.
+	lock, ok := lockMirror(ctx, tgtURL)
+	if !ok {
+		return nil
 	}
+	defer lock.Release()
 
-	return nil
+	return runMirror(srcURL, tgtURL, ctx, encKeyDB)
 }
@@ -0,0 +1,242 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+)
+
+// Results of a mirror preflight check.
+const (
+	preflightOK      = "ok"
+	preflightWarning = "warning"
+	preflightFailed  = "failed"
+)
+
+// Prefix of the temporary object written to check permissions.
+const preflightProbePrefix = ".mc-preflight-"
+
+// mirrorPreflightOpts - mirror options the preflight checks depend on.
+type mirrorPreflightOpts struct {
+	createTarget bool
+	region       string
+	isFake       bool
+	isRemove     bool
+	isOverwrite  bool
+}
+
+// mirrorPreflightMessage container for the result of one preflight check.
+type mirrorPreflightMessage struct {
+	Status  string `json:"status"`
+	Check   string `json:"check"`
+	Result  string `json:"result"`
+	Target  string `json:"target"`
+	Message string `json:"message"`
+
+	// Printed even if all checks passed.
+	notable bool
+}
+
+// String colorized preflight message
+func (m mirrorPreflightMessage) String() string {
+	var result string
+	switch m.Result {
+	case preflightFailed:
+		result = console.Colorize("PreflightFailed", fmt.Sprintf("%-8s", m.Result))
+	case preflightWarning:
+		result = console.Colorize("PreflightWarning", fmt.Sprintf("%-8s", m.Result))
+	default:
+		result = console.Colorize("PreflightOK", fmt.Sprintf("%-8s", m.Result))
+	}
+	return result + " " + fmt.Sprintf("%-11s", m.Check) + " " + m.Message
+}
+
+// JSON jsonified preflight message
+func (m mirrorPreflightMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// mirrorPreflight - collects the results of preflight checks.
+type mirrorPreflight struct {
+	target  string
+	results []mirrorPreflightMessage
+}
+
+func (p *mirrorPreflight) report(check, result, format string, args ...interface{}) {
+	p.results = append(p.results, mirrorPreflightMessage{
+		Check:   check,
+		Result:  result,
+		Target:  p.target,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// failed - true if any check failed.
+func (p *mirrorPreflight) failed() bool {
+	for _, r := range p.results {
+		if r.Result == preflightFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBucket - verify the target bucket exists, creating it if asked to.
+// Returns false when the bucket is not there for further checks.
+func (p *mirrorPreflight) checkBucket(dst *s3Client, bucket string, opts mirrorPreflightOpts) bool {
+	_, err := dst.bucketStat(bucket)
+	if err == nil {
+		p.report("bucket", preflightOK, "Bucket `%s` exists.", bucket)
+		return true
+	}
+	if _, ok := err.ToGoError().(BucketDoesNotExist); !ok {
+		p.report("bucket", preflightFailed, "Unable to check bucket `%s`: %s", bucket, err.ToGoError())
+		return false
+	}
+	if !opts.createTarget {
+		p.report("bucket", preflightFailed, "Bucket `%s` does not exist, use `--create-target` to create it.", bucket)
+		return false
+	}
+	if opts.isFake {
+		p.report("bucket", preflightOK, "Bucket `%s` would be created.", bucket)
+		p.results[len(p.results)-1].notable = true
+		return false
+	}
+	if e := dst.api.MakeBucket(bucket, opts.region); e != nil {
+		p.report("bucket", preflightFailed, "Unable to create bucket `%s`: %s", bucket, e)
+		return false
+	}
+	p.report("bucket", preflightOK, "Bucket `%s` created.", bucket)
+	p.results[len(p.results)-1].notable = true
+	return true
+}
+
+// checkPermissions - write and remove a temporary object under the
+// target prefix to make sure PutObject and, with --remove, DeleteObject
+// are allowed.
+func (p *mirrorPreflight) checkPermissions(dst *s3Client, bucket, prefix string, opts mirrorPreflightOpts) {
+	if opts.isFake {
+		return
+	}
+	object := path.Join(prefix, preflightProbePrefix+newRandomID(8))
+	if _, e := dst.api.PutObject(bucket, object, bytes.NewReader(nil), 0, minio.PutObjectOptions{}); e != nil {
+		p.report("write", preflightFailed, "Credentials do not permit PutObject on `%s`: %s", bucket, e)
+		return
+	}
+	p.report("write", preflightOK, "PutObject is permitted.")
+
+	if e := dst.api.RemoveObject(bucket, object); e != nil {
+		if opts.isRemove {
+			p.report("delete", preflightFailed, "Credentials do not permit DeleteObject on `%s`, required by `--remove`: %s", bucket, e)
+		} else {
+			p.report("delete", preflightWarning, "Unable to remove the temporary object `%s`: %s", object, e)
+		}
+		return
+	}
+	p.report("delete", preflightOK, "DeleteObject is permitted.")
+}
+
+// checkVersioning - warn about target versioning and object lock settings
+// which keep data around that mirror would otherwise overwrite or remove.
+func (p *mirrorPreflight) checkVersioning(src Client, dst *s3Client, opts mirrorPreflightOpts) {
+	versioning, err := dst.GetVersioning()
+	if err != nil {
+		p.report("versioning", preflightWarning, "Unable to check versioning: %s", err.ToGoError())
+	} else if versioning != "" && opts.isRemove {
+		p.report("versioning", preflightWarning, "Versioning is %s, objects removed by `--remove` are kept as noncurrent versions.", strings.ToLower(versioning))
+	} else if versioning != "" {
+		p.report("versioning", preflightOK, "Versioning is %s.", strings.ToLower(versioning))
+	} else {
+		p.report("versioning", preflightOK, "Versioning is not enabled.")
+	}
+
+	locked, err := dst.GetObjectLockEnabled()
+	if err != nil {
+		p.report("lock", preflightWarning, "Unable to check object lock: %s", err.ToGoError())
+		return
+	}
+	if locked && (opts.isRemove || opts.isOverwrite) {
+		p.report("lock", preflightWarning, "Object lock is enabled, locked versions are retained when objects are overwritten or removed.")
+		return
+	}
+
+	// Retention of locked source objects is lost on an unlocked target.
+	if s, ok := src.(*s3Client); ok && !locked {
+		if bucket, _ := s.url2BucketAndObject(); bucket != "" {
+			if srcLocked, err := s.GetObjectLockEnabled(); err == nil && srcLocked {
+				p.report("lock", preflightWarning, "Object lock is enabled on the source only, retention of mirrored objects is not preserved.")
+				return
+			}
+		}
+	}
+	if locked {
+		p.report("lock", preflightOK, "Object lock is enabled.")
+	} else {
+		p.report("lock", preflightOK, "Object lock is not enabled.")
+	}
+}
+
+// runMirrorPreflight - check the target of a mirror is usable before any
+// object is copied, every check is reported and true is returned if any
+// of them failed. Only object storage targets in a bucket are checked,
+// missing folders and buckets of a whole site mirror are created anyway.
+func runMirrorPreflight(srcClt, dstClt Client, opts mirrorPreflightOpts) bool {
+	dst, ok := dstClt.(*s3Client)
+	if !ok {
+		return false
+	}
+	bucket, prefix := dst.url2BucketAndObject()
+	if bucket == "" {
+		return false
+	}
+
+	console.SetColor("PreflightOK", color.New(color.FgGreen, color.Bold))
+	console.SetColor("PreflightWarning", color.New(color.FgYellow, color.Bold))
+	console.SetColor("PreflightFailed", color.New(color.FgRed, color.Bold))
+
+	p := &mirrorPreflight{target: dst.GetURL().String()}
+	if err := dst.checkWritable(); err != nil {
+		p.report("write", preflightFailed, "%s", err.ToGoError())
+	} else if p.checkBucket(dst, bucket, opts) {
+		p.checkPermissions(dst, bucket, prefix, opts)
+		if !p.failed() {
+			p.checkVersioning(srcClt, dst, opts)
+		}
+	}
+
+	failed := p.failed()
+	for _, r := range p.results {
+		// Only bother the user with passed checks if something is off.
+		if failed || r.Result != preflightOK || r.notable || globalJSON {
+			printMsg(r)
+		}
+	}
+	return failed
+}
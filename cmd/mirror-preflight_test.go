@@ -0,0 +1,148 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+// preflightHandler - fake S3 server for preflight checks.
+type preflightHandler struct {
+	mutex      sync.Mutex
+	exists     bool
+	denyDelete bool
+	versioning string
+	locked     bool
+	objects    map[string]bool
+}
+
+func (h *preflightHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	bucketPath := strings.TrimSuffix(r.URL.Path, "/") == "/bucket"
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && len(query["versioning"]) > 0:
+		w.Write([]byte("<VersioningConfiguration xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"><Status>" + h.versioning + "</Status></VersioningConfiguration>"))
+	case r.Method == "GET" && len(query["object-lock"]) > 0:
+		if !h.locked {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>ObjectLockConfigurationNotFoundError</Code></Error>"))
+			return
+		}
+		w.Write([]byte("<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>"))
+	case r.Method == "HEAD" && bucketPath:
+		if !h.exists {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.Method == "PUT" && bucketPath:
+		h.exists = true
+	case r.Method == "PUT" && h.exists:
+		h.objects[r.URL.Path] = true
+	case r.Method == "DELETE" && !h.denyDelete:
+		delete(h.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func (h *preflightHandler) run(c *C, server *httptest.Server, opts mirrorPreflightOpts) *mirrorPreflight {
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/prefix"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	dst := clnt.(*s3Client)
+
+	p := &mirrorPreflight{target: conf.HostURL}
+	if p.checkBucket(dst, "bucket", opts) {
+		p.checkPermissions(dst, "bucket", "prefix", opts)
+		if !p.failed() {
+			p.checkVersioning(nil, dst, opts)
+		}
+	}
+	return p
+}
+
+func preflightResults(p *mirrorPreflight) map[string]string {
+	results := make(map[string]string)
+	for _, r := range p.results {
+		results[r.Check] = r.Result
+	}
+	return results
+}
+
+func (s *TestSuite) TestMirrorPreflight(c *C) {
+	h := &preflightHandler{objects: make(map[string]bool)}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	// Missing bucket fails without --create-target.
+	p := h.run(c, server, mirrorPreflightOpts{})
+	c.Assert(p.failed(), Equals, true)
+	c.Assert(preflightResults(p)["bucket"], Equals, preflightFailed)
+
+	// A fake mirror does not create the bucket.
+	p = h.run(c, server, mirrorPreflightOpts{createTarget: true, isFake: true})
+	c.Assert(p.failed(), Equals, false)
+	c.Assert(h.exists, Equals, false)
+
+	p = h.run(c, server, mirrorPreflightOpts{createTarget: true})
+	c.Assert(p.failed(), Equals, false)
+	c.Assert(h.exists, Equals, true)
+	c.Assert(preflightResults(p), DeepEquals, map[string]string{
+		"bucket":     preflightOK,
+		"write":      preflightOK,
+		"delete":     preflightOK,
+		"versioning": preflightOK,
+		"lock":       preflightOK,
+	})
+	// Temporary object is removed.
+	c.Assert(len(h.objects), Equals, 0)
+
+	// Versioned and locked buckets keep removed objects.
+	h.versioning = "Enabled"
+	h.locked = true
+	p = h.run(c, server, mirrorPreflightOpts{isRemove: true})
+	c.Assert(p.failed(), Equals, false)
+	c.Assert(preflightResults(p)["versioning"], Equals, preflightWarning)
+	c.Assert(preflightResults(p)["lock"], Equals, preflightWarning)
+
+	// DeleteObject is only required by --remove.
+	h.denyDelete = true
+	p = h.run(c, server, mirrorPreflightOpts{})
+	c.Assert(p.failed(), Equals, false)
+	c.Assert(preflightResults(p)["delete"], Equals, preflightWarning)
+
+	p = h.run(c, server, mirrorPreflightOpts{isRemove: true})
+	c.Assert(p.failed(), Equals, true)
+	c.Assert(preflightResults(p)["delete"], Equals, preflightFailed)
+	for k := range h.objects {
+		c.Assert(strings.Contains(k, preflightProbePrefix), Equals, true)
+	}
+}
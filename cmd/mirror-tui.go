@@ -0,0 +1,458 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// Refresh rate of the dashboard.
+	tuiRefreshRate = 500 * time.Millisecond
+
+	// Maximum number of errors and events kept for display.
+	tuiMaxErrors = 5
+	tuiMaxEvents = 5
+)
+
+// transferDoneNotifier is implemented by Status objects which keep
+// track of in-flight transfers.
+type transferDoneNotifier interface {
+	transferDone(sURLs URLs)
+}
+
+// tuiTransfer - a transfer currently processed by a worker.
+type tuiTransfer struct {
+	source    string
+	size      int64
+	startTime time.Time
+}
+
+// TUIStatus renders a full screen dashboard with transfer speed
+// history, in-flight transfers and recent errors. Transfers can
+// be paused and resumed from the keyboard.
+type TUIStatus struct {
+	parallel *ParallelManager
+	title    string
+
+	current int64
+	total   int64
+
+	mutex     sync.Mutex
+	pauseCh   chan struct{}
+	active    map[string]tuiTransfer
+	errors    []string
+	events    []string
+	history   []int64
+	lastValue int64
+	startTime time.Time
+
+	oldState   *terminal.State
+	doneCh     chan struct{}
+	finishOnce sync.Once
+	wg         sync.WaitGroup
+}
+
+// NewTUIStatus returns a full screen dashboard status object.
+func NewTUIStatus(title string, parallel *ParallelManager) Status {
+	return &TUIStatus{
+		parallel: parallel,
+		title:    title,
+		active:   make(map[string]tuiTransfer),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// isTUICapable returns true if the dashboard can be displayed.
+func isTUICapable() bool {
+	return terminal.IsTerminal(int(os.Stdout.Fd())) && terminal.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Read implements the io.Reader interface, it blocks while paused.
+func (t *TUIStatus) Read(p []byte) (n int, err error) {
+	t.mutex.Lock()
+	pauseCh := t.pauseCh
+	t.mutex.Unlock()
+	if pauseCh != nil {
+		select {
+		case <-pauseCh:
+		case <-t.doneCh:
+		}
+	}
+	t.parallel.Read(p)
+	atomic.AddInt64(&t.current, int64(len(p)))
+	return len(p), nil
+}
+
+// Get returns the current number of bytes.
+func (t *TUIStatus) Get() int64 {
+	return atomic.LoadInt64(&t.current)
+}
+
+// Add bytes to current number of bytes.
+func (t *TUIStatus) Add(v int64) Status {
+	atomic.AddInt64(&t.current, v)
+	return t
+}
+
+// Total returns the total number of bytes.
+func (t *TUIStatus) Total() int64 {
+	return atomic.LoadInt64(&t.total)
+}
+
+// SetTotal sets the total number of bytes.
+func (t *TUIStatus) SetTotal(v int64) Status {
+	atomic.StoreInt64(&t.total, v)
+	return t
+}
+
+// SetCaption is ignored, in-flight transfers are displayed instead.
+func (t *TUIStatus) SetCaption(s string) {}
+
+// Update is ignored, the dashboard refreshes periodically.
+func (t *TUIStatus) Update() {}
+
+// Println records a line in the events panel.
+func (t *TUIStatus) Println(data ...interface{}) {
+	t.addEvent(strings.TrimSpace(fmt.Sprintln(data...)))
+}
+
+// PrintMsg records started transfers and other messages.
+func (t *TUIStatus) PrintMsg(msg message) {
+	if m, ok := msg.(mirrorMessage); ok {
+		t.mutex.Lock()
+		t.active[m.Source] = tuiTransfer{source: m.Source, size: m.Size, startTime: time.Now()}
+		t.mutex.Unlock()
+		return
+	}
+	t.addEvent(msg.String())
+}
+
+// transferDone removes a finished transfer from the in-flight list.
+func (t *TUIStatus) transferDone(sURLs URLs) {
+	if sURLs.SourceContent == nil {
+		return
+	}
+	source := filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+	t.mutex.Lock()
+	delete(t.active, source)
+	t.mutex.Unlock()
+}
+
+func (t *TUIStatus) addEvent(event string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.events = append(t.events, event)
+	if len(t.events) > tuiMaxEvents {
+		t.events = t.events[len(t.events)-tuiMaxEvents:]
+	}
+}
+
+func (t *TUIStatus) errorIf(err *probe.Error, msg string) {
+	if err == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.errors = append(t.errors, fmt.Sprintf("[%s] %s %s", time.Now().Format("15:04:05"), msg, err.ToGoError()))
+	if len(t.errors) > tuiMaxErrors {
+		t.errors = t.errors[len(t.errors)-tuiMaxErrors:]
+	}
+}
+
+func (t *TUIStatus) fatalIf(err *probe.Error, msg string) {
+	if err == nil {
+		return
+	}
+	t.restore()
+	fatalIf(err, msg)
+}
+
+// Start switches the terminal to the dashboard.
+func (t *TUIStatus) Start() {
+	t.startTime = time.Now()
+	oldState, e := terminal.MakeRaw(int(os.Stdin.Fd()))
+	if e == nil {
+		t.oldState = oldState
+	}
+	// Switch to alternate screen and hide cursor.
+	fmt.Fprint(color.Output, "\x1b[?1049h\x1b[?25l")
+
+	go t.readKeys()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(tuiRefreshRate)
+		defer ticker.Stop()
+		lastSample := time.Now()
+		for {
+			select {
+			case <-t.doneCh:
+				return
+			case now := <-ticker.C:
+				if now.Sub(lastSample) >= time.Second {
+					t.sample(now.Sub(lastSample))
+					lastSample = now
+				}
+				t.render()
+			}
+		}
+	}()
+}
+
+// Finish restores the terminal and displays the errors hidden by the
+// dashboard, the summary is printed by mirror.
+func (t *TUIStatus) Finish() {
+	t.restore()
+	t.mutex.Lock()
+	errors := t.errors
+	t.mutex.Unlock()
+	for _, e := range errors {
+		fmt.Fprintln(color.Output, e)
+	}
+}
+
+// restore leaves the dashboard, it is safe to call restore multiple times.
+func (t *TUIStatus) restore() {
+	t.finishOnce.Do(func() {
+		close(t.doneCh)
+		t.wg.Wait()
+		fmt.Fprint(color.Output, "\x1b[?25h\x1b[?1049l")
+		if t.oldState != nil {
+			terminal.Restore(int(os.Stdin.Fd()), t.oldState)
+		}
+	})
+}
+
+// setPaused pauses or resumes all transfers.
+func (t *TUIStatus) setPaused(pause bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if pause && t.pauseCh == nil {
+		t.pauseCh = make(chan struct{})
+	} else if !pause && t.pauseCh != nil {
+		close(t.pauseCh)
+		t.pauseCh = nil
+	}
+}
+
+func (t *TUIStatus) isPaused() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.pauseCh != nil
+}
+
+// readKeys handles key bindings of the dashboard.
+func (t *TUIStatus) readKeys() {
+	buf := make([]byte, 1)
+	for {
+		n, e := os.Stdin.Read(buf)
+		if e != nil {
+			return
+		}
+		if n == 0 {
+			continue
+		}
+		select {
+		case <-t.doneCh:
+			return
+		default:
+		}
+		switch buf[0] {
+		case 'p', 'P', ' ':
+			t.setPaused(!t.isPaused())
+		case 'r', 'R':
+			t.setPaused(false)
+		case 'q', 'Q', 0x03:
+			// Raw mode swallows Ctrl-C, deliver the interrupt ourselves
+			// so that the usual shutdown path is taken.
+			t.setPaused(false)
+			t.restore()
+			if p, e := os.FindProcess(os.Getpid()); e != nil || p.Signal(os.Interrupt) != nil {
+				os.Exit(globalErrorExitStatus)
+			}
+			return
+		}
+	}
+}
+
+// sample records the transfer speed over the last period.
+func (t *TUIStatus) sample(period time.Duration) {
+	current := t.Get()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	speed := int64(float64(current-t.lastValue) / period.Seconds())
+	t.lastValue = current
+	t.history = append(t.history, speed)
+	if limit := globalTermWidth; limit > 0 && len(t.history) > limit {
+		t.history = t.history[len(t.history)-limit:]
+	}
+}
+
+// sparkline renders the speed history in at most width columns.
+func sparkline(history []int64, width int) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	if runtime.GOOS == "windows" {
+		levels = []rune("_.-=+*#@")
+	}
+	if width <= 0 {
+		return ""
+	}
+	if len(history) > width {
+		history = history[len(history)-width:]
+	}
+	var max int64
+	for _, v := range history {
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range history {
+		i := 0
+		if max > 0 {
+			i = int(v * int64(len(levels)-1) / max)
+		}
+		b.WriteRune(levels[i])
+	}
+	return b.String()
+}
+
+// render draws the dashboard in the terminal.
+func (t *TUIStatus) render() {
+	width, height := globalTermWidth, 24
+	if w, h, e := terminal.GetSize(int(os.Stdout.Fd())); e == nil {
+		width, height = w, h
+	}
+	t.renderSize(width, height)
+}
+
+// renderSize draws the dashboard in width columns and height rows.
+func (t *TUIStatus) renderSize(width, height int) {
+	if width < 40 {
+		width = 40
+	}
+
+	current, total := t.Get(), t.Total()
+	elapsed := time.Since(t.startTime)
+
+	t.mutex.Lock()
+	paused := t.pauseCh != nil
+	var speed int64
+	if len(t.history) > 0 {
+		speed = t.history[len(t.history)-1]
+	}
+	history := append([]int64(nil), t.history...)
+	var active []tuiTransfer
+	for _, a := range t.active {
+		active = append(active, a)
+	}
+	errors := append([]string(nil), t.errors...)
+	events := append([]string(nil), t.events...)
+	t.mutex.Unlock()
+
+	sort.Slice(active, func(i, j int) bool { return active[i].startTime.Before(active[j].startTime) })
+
+	var lines []string
+	state := "RUNNING"
+	if paused {
+		state = "PAUSED"
+	}
+	lines = append(lines, color.New(color.Bold).Sprint(lineTrunc(fmt.Sprintf(" %s  [%s]", t.title, state), width)))
+
+	var avg float64
+	if elapsed > 0 {
+		avg = float64(current) / elapsed.Seconds()
+	}
+	percent := 0.0
+	if total > 0 {
+		percent = float64(current) * 100 / float64(total)
+	}
+	barWidth := width - 40
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	filled := int(percent * float64(barWidth) / 100)
+	if filled > barWidth {
+		filled = barWidth
+	}
+	bar := strings.Repeat("#", filled) + strings.Repeat(".", barWidth-filled)
+	lines = append(lines, fmt.Sprintf(" Progress : [%s] %5.1f%% %s / %s", color.GreenString(bar), percent,
+		humanize.IBytes(uint64(current)), humanize.IBytes(uint64(total))))
+
+	eta := "-"
+	if avg > 0 && total > current {
+		eta = time.Duration(float64(total-current) / avg * float64(time.Second)).Round(time.Second).String()
+	}
+	lines = append(lines, fmt.Sprintf(" Speed    : %s/s (avg %s/s)  Elapsed: %s  ETA: %s  Workers: %d",
+		humanize.IBytes(uint64(speed)), humanize.IBytes(uint64(avg)), elapsed.Round(time.Second), eta,
+		atomic.LoadUint32(&t.parallel.workersNum)))
+	lines = append(lines, " "+color.CyanString(sparkline(history, width-2)))
+	lines = append(lines, "")
+
+	lines = append(lines, color.New(color.Bold).Sprintf(" In-flight transfers (%d):", len(active)))
+	// Keep room for the other panels.
+	maxActive := height - 12 - tuiMaxErrors - tuiMaxEvents
+	if maxActive < 1 {
+		maxActive = 1
+	}
+	for i, a := range active {
+		if i >= maxActive {
+			lines = append(lines, fmt.Sprintf("   ... and %d more", len(active)-maxActive))
+			break
+		}
+		info := fmt.Sprintf(" %9s %6s", humanize.IBytes(uint64(a.size)), time.Since(a.startTime).Round(time.Second))
+		lines = append(lines, "   "+lineTrunc(a.source, width-len(info)-4)+info)
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, color.New(color.Bold).Sprint(" Recent events:"))
+	for _, e := range events {
+		lines = append(lines, "   "+lineTrunc(e, width-4))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, color.New(color.Bold).Sprint(" Recent errors:"))
+	for _, e := range errors {
+		lines = append(lines, "   "+color.RedString(lineTrunc(e, width-4)))
+	}
+	lines = append(lines, "")
+	lines = append(lines, color.YellowString(" [p] pause/resume  [r] resume  [q] quit"))
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteString("\x1b[K\r\n")
+	}
+	b.WriteString("\x1b[J")
+	fmt.Fprint(color.Output, b.String())
+}
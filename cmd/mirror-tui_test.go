@@ -0,0 +1,155 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestSparkline(t *testing.T) {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	if runtime.GOOS == "windows" {
+		levels = []rune("_.-=+*#@")
+	}
+	testCases := []struct {
+		history []int64
+		width   int
+		// Levels of the expected sparkline.
+		expected []int
+	}{
+		{nil, 10, nil},
+		{[]int64{1, 2, 3}, 0, nil},
+		{[]int64{0, 0, 0}, 3, []int{0, 0, 0}},
+		{[]int64{0, 7, 14}, 3, []int{0, 3, 7}},
+		{[]int64{1, 2, 3, 4, 5, 6, 7, 8}, 8, []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		// Only the last samples are shown, and scaled to their own maximum.
+		{[]int64{100, 1, 2}, 2, []int{3, 7}},
+	}
+	for i, testCase := range testCases {
+		var expected strings.Builder
+		for _, level := range testCase.expected {
+			expected.WriteRune(levels[level])
+		}
+		if got := sparkline(testCase.history, testCase.width); got != expected.String() {
+			t.Errorf("Test %d: expected %q, got %q", i+1, expected.String(), got)
+		}
+	}
+}
+
+func TestTUIStatusPause(t *testing.T) {
+	testCases := []struct {
+		pauses  []bool
+		blocked bool
+	}{
+		{nil, false},
+		{[]bool{true}, true},
+		{[]bool{true, true}, true},
+		{[]bool{true, false}, false},
+		{[]bool{true, false, false}, false},
+		{[]bool{false, true}, true},
+	}
+	for i, testCase := range testCases {
+		status := NewTUIStatus("mirror", &ParallelManager{}).(*TUIStatus)
+		for _, pause := range testCase.pauses {
+			status.setPaused(pause)
+		}
+		if status.isPaused() != testCase.blocked {
+			t.Errorf("Test %d: expected paused %t, got %t", i+1, testCase.blocked, status.isPaused())
+		}
+		readCh := make(chan struct{})
+		go func() {
+			status.Read(make([]byte, 10))
+			close(readCh)
+		}()
+		select {
+		case <-readCh:
+			if testCase.blocked {
+				t.Fatalf("Test %d: expected reads to block while paused", i+1)
+			}
+		case <-time.After(50 * time.Millisecond):
+			if !testCase.blocked {
+				t.Fatalf("Test %d: expected reads not to block", i+1)
+			}
+			// Resuming unblocks the read.
+			status.setPaused(false)
+			select {
+			case <-readCh:
+			case <-time.After(time.Second):
+				t.Fatalf("Test %d: expected the read to resume", i+1)
+			}
+		}
+		if status.Get() != 10 {
+			t.Errorf("Test %d: expected 10 bytes read, got %d", i+1, status.Get())
+		}
+	}
+}
+
+func TestTUIStatusRender(t *testing.T) {
+	savedOutput, savedNoColor := color.Output, color.NoColor
+	defer func() { color.Output, color.NoColor = savedOutput, savedNoColor }()
+	var output bytes.Buffer
+	color.Output, color.NoColor = &output, true
+
+	longSource := "play/mybucket/" + strings.Repeat("a", 200)
+	testCases := []struct {
+		paused   bool
+		expected []string
+	}{
+		{false, []string{" mirror  [RUNNING]", " 25.0% 250 B / 1000 B", "…", "an event", "Failed boom"}},
+		{true, []string{" mirror  [PAUSED]", " 25.0% 250 B / 1000 B", "…", "an event", "Failed boom"}},
+	}
+	for i, testCase := range testCases {
+		status := NewTUIStatus("mirror", &ParallelManager{}).(*TUIStatus)
+		status.startTime = time.Now()
+		status.SetTotal(1000).Add(250)
+		status.history = []int64{100, 200}
+		status.PrintMsg(mirrorMessage{Source: longSource, Size: 1024})
+		status.addEvent("an event")
+		status.errorIf(probe.NewError(errors.New("boom")), "Failed")
+		status.setPaused(testCase.paused)
+
+		output.Reset()
+		status.renderSize(80, 24)
+		rendered := output.String()
+		if !strings.HasPrefix(rendered, "\x1b[H") {
+			t.Errorf("Test %d: expected the dashboard to be drawn from the top, got %q", i+1, rendered)
+		}
+		lines := strings.Split(strings.TrimPrefix(rendered, "\x1b[H"), "\x1b[K\r\n")
+		for _, line := range lines {
+			if n := utf8.RuneCountInString(line); n > 80 {
+				t.Errorf("Test %d: expected lines of at most 80 columns, got %d in %q", i+1, n, line)
+			}
+		}
+		for _, expected := range testCase.expected {
+			if !strings.Contains(rendered, expected) {
+				t.Errorf("Test %d: expected %q in %q", i+1, expected, rendered)
+			}
+		}
+		if strings.Contains(rendered, longSource) {
+			t.Errorf("Test %d: expected %s to be truncated", i+1, longSource)
+		}
+	}
+}
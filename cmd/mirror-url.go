@@ -17,7 +17,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/minio/cli"
@@ -34,6 +37,8 @@ func checkMirrorSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	if len(ctx.Args()) != 2 {
 		cli.ShowCommandHelpAndExit(ctx, "mirror", 1) // last argument is exit code.
 	}
+	checkParallelFlag(ctx)
+	checkACLFlag(ctx)
 
 	// extract URLs.
 	URLs := ctx.Args()
@@ -46,6 +51,23 @@ func checkMirrorSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		errorIf(errInvalidArgument().Trace(URLs...), "`--force` is deprecated please use `--overwrite` instead for the same functionality.")
 	}
 
+	if ctx.Bool("tui") && (globalQuiet || globalJSON || !isTUICapable()) {
+		fatalIf(errInvalidArgument().Trace(URLs...), "`--tui` requires an interactive terminal and cannot be combined with `--quiet` or `--json`.")
+	}
+
+	setNormalizeFromContext(ctx)
+	setSanitizeFromContext(ctx)
+	setRenameFromContext(ctx)
+
+	if ctx.String("rewind") != "" && ctx.Bool("watch") {
+		fatalIf(errInvalidArgument().Trace(URLs...), "`--rewind` cannot be combined with `--watch`, a rewound source never changes.")
+	}
+
+	if ctx.IsSet("hash") && ctx.String("write-manifest") == "" {
+		fatalIf(errInvalidArgument().Trace(URLs...), "`--hash` needs `--write-manifest`, `--dedup` always compares SHA-256 sums.")
+	}
+	getChecksumAlgorithm(ctx)
+
 	tgtClientURL := newClientURL(tgtURL)
 	if tgtClientURL.Host != "" {
 		if tgtClientURL.Path == string(tgtClientURL.Separator) {
@@ -54,9 +76,26 @@ func checkMirrorSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		}
 	}
 
+	if linkDest := ctx.String("link-dest"); linkDest != "" {
+		tgtClnt, err := newClient(tgtURL)
+		fatalIf(err.Trace(tgtURL), "Unable to initialize target `"+tgtURL+"`.")
+		if tgtClnt.GetURL().Type != fileSystem {
+			fatalIf(errInvalidArgument().Trace(tgtURL), "`--link-dest` is only supported when mirroring to a local folder.")
+		}
+		st, e := os.Stat(linkDest)
+		if e != nil || !st.IsDir() {
+			fatalIf(errInvalidArgument().Trace(linkDest), fmt.Sprintf("Snapshot `%s` given to `--link-dest` is not a folder.", linkDest))
+		}
+		absLinkDest, _ := filepath.Abs(linkDest)
+		absTgtURL, _ := filepath.Abs(tgtURL)
+		if isURLContains(absLinkDest, absTgtURL, string(filepath.Separator)) || isURLContains(absTgtURL, absLinkDest, string(filepath.Separator)) {
+			fatalIf(errInvalidArgument().Trace(linkDest, tgtURL), "Snapshot given to `--link-dest` must be outside of the target.")
+		}
+	}
+
 	/****** Generic rules *******/
 	if !ctx.Bool("watch") {
-		c, srcContent, err := url2Stat(srcURL, false, encKeyDB)
+		c, srcContent, err := url2Stat(globalContext, srcURL, false, encKeyDB)
 		// incomplete uploads are not necessary for copy operation, no need to verify for them.
 		isIncomplete := false
 		if err != nil && !isURLPrefixExists(srcURL, isIncomplete) {
@@ -86,7 +125,7 @@ func matchExcludeOptions(excludeOptions []string, srcSuffix string) bool {
 	return false
 }
 
-func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemove bool, excludeOptions []string, URLsCh chan<- URLs, encKeyDB map[string][]prefixSSEPair) {
+func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, isFake, isOverwrite, isRemove bool, excludeOptions []string, cache *mirrorCache, URLsCh chan<- URLs, encKeyDB map[string][]prefixSSEPair) {
 	// source and targets are always directories
 	sourceSeparator := string(newClientURL(sourceURL).Separator)
 	if !strings.HasSuffix(sourceURL, sourceSeparator) {
@@ -115,8 +154,12 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 		return
 	}
 
+	isSourceLocal, isTargetLocal := sourceClnt.GetURL().Type == fileSystem, targetClnt.GetURL().Type == fileSystem
+
 	// List both source and target, compare and return values through channel.
-	for diffMsg := range objectDifference(sourceClnt, targetClnt, sourceURL, targetURL) {
+	// The target is listed from the cache of the last mirror if any, the
+	// target as it is after this mirror is cached on the way.
+	for diffMsg := range objectDifference(ctx, sourceClnt, cache.listing(targetClnt), sourceURL, targetURL) {
 		if diffMsg.Error != nil {
 			// Send all errors through the channel
 			URLsCh <- URLs{Error: diffMsg.Error}
@@ -126,18 +169,23 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 		srcSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
 		//Skip the source object if it matches the Exclude options provided
 		if matchExcludeOptions(excludeOptions, srcSuffix) {
+			if diffMsg.secondContent != nil {
+				cache.add(diffMsg.secondContent)
+			}
 			continue
 		}
 
 		tgtSuffix := strings.TrimPrefix(diffMsg.SecondURL, targetURL)
 		//Skip the target object if it matches the Exclude options provided
 		if matchExcludeOptions(excludeOptions, tgtSuffix) {
+			cache.add(diffMsg.secondContent)
 			continue
 		}
 
 		switch diffMsg.Diff {
 		case differInNone:
 			// No difference, continue.
+			cache.add(diffMsg.secondContent)
 		case differInType:
 			URLsCh <- URLs{Error: errInvalidTarget(diffMsg.SecondURL)}
 		case differInSize, differInTime:
@@ -147,11 +195,21 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 				continue
 			}
 
-			sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
+			sourceSuffix, err := renameTargetSuffix(strings.TrimPrefix(diffMsg.FirstURL, sourceURL), isSourceLocal, isTargetLocal)
+			if err != nil {
+				URLsCh <- URLs{SourceAlias: sourceAlias, SourceContent: diffMsg.firstContent, Error: err}
+				continue
+			}
+			sourceSuffix = normalizeUploadKey(sourceSuffix)
 			// Either available only in source or size differs and force is set
-			targetPath := urlJoinPath(targetURL, sourceSuffix)
 			sourceContent := diffMsg.firstContent
+			targetPath, err := joinTargetPath(targetURL, sourceSuffix, isSourceLocal, isTargetLocal)
+			if err != nil {
+				URLsCh <- URLs{SourceAlias: sourceAlias, SourceContent: sourceContent, Error: err}
+				continue
+			}
 			targetContent := &clientContent{URL: *newClientURL(targetPath)}
+			cache.add(&clientContent{URL: targetContent.URL, Size: sourceContent.Size, Time: sourceContent.Time})
 			URLsCh <- URLs{
 				SourceAlias:   sourceAlias,
 				SourceContent: sourceContent,
@@ -160,10 +218,20 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 			}
 		case differInFirst:
 			// Only in first, always copy.
-			sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
-			targetPath := urlJoinPath(targetURL, sourceSuffix)
+			sourceSuffix, err := renameTargetSuffix(strings.TrimPrefix(diffMsg.FirstURL, sourceURL), isSourceLocal, isTargetLocal)
+			if err != nil {
+				URLsCh <- URLs{SourceAlias: sourceAlias, SourceContent: diffMsg.firstContent, Error: err}
+				continue
+			}
+			sourceSuffix = normalizeUploadKey(sourceSuffix)
 			sourceContent := diffMsg.firstContent
+			targetPath, err := joinTargetPath(targetURL, sourceSuffix, isSourceLocal, isTargetLocal)
+			if err != nil {
+				URLsCh <- URLs{SourceAlias: sourceAlias, SourceContent: sourceContent, Error: err}
+				continue
+			}
 			targetContent := &clientContent{URL: *newClientURL(targetPath)}
+			cache.add(&clientContent{URL: targetContent.URL, Size: sourceContent.Size, Time: sourceContent.Time})
 			URLsCh <- URLs{
 				SourceAlias:   sourceAlias,
 				SourceContent: sourceContent,
@@ -172,6 +240,7 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 			}
 		case differInSecond:
 			if !isRemove && !isFake {
+				cache.add(diffMsg.secondContent)
 				continue
 			}
 			URLsCh <- URLs{
@@ -184,11 +253,12 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 			}
 		}
 	}
+	cache.done()
 }
 
 // Prepares urls that need to be copied or removed based on requested options.
-func prepareMirrorURLs(sourceURL string, targetURL string, isFake, isOverwrite, isRemove bool, excludeOptions []string, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
+func prepareMirrorURLs(ctx context.Context, sourceURL string, targetURL string, isFake, isOverwrite, isRemove bool, excludeOptions []string, cache *mirrorCache, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
 	URLsCh := make(chan URLs)
-	go deltaSourceTarget(sourceURL, targetURL, isFake, isOverwrite, isRemove, excludeOptions, URLsCh, encKeyDB)
+	go deltaSourceTarget(ctx, sourceURL, targetURL, isFake, isOverwrite, isRemove, excludeOptions, cache, URLsCh, encKeyDB)
 	return URLsCh
 }
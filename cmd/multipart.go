@@ -0,0 +1,226 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Limits of S3 multipart uploads.
+const (
+	minUploadPartSize         = 5 * humanize.MiByte
+	maxUploadPartSize         = 5 * humanize.GiByte
+	maxUploadParts            = 10000
+	maxSinglePutSize          = 5 * humanize.GiByte
+	maxMultipartUploadSize    = 5 * humanize.TiByte
+	defaultMultipartThreshold = 128 * humanize.MiByte
+
+	// Part size of streams of unknown length, without a configured part
+	// size or a size hint streams up to 640GiB can be uploaded.
+	defaultStreamPartSize = 64 * humanize.MiByte
+)
+
+// uploadFlags tune the multipart strategy of commands uploading objects.
+var uploadFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "part-size",
+		Usage: "size of the parts of multipart uploads, e.g. 16MiB (default: computed from the object size)",
+	},
+	cli.StringFlag{
+		Name:  "multipart-threshold",
+		Usage: "upload objects of at least this size in parts, e.g. 64MiB (default: part size or 128MiB)",
+	},
+	cli.BoolFlag{
+		Name:  "disable-multipart",
+		Usage: "upload every object with a single PUT, objects larger than 5GiB fail",
+	},
+}
+
+// multipartConfig - multipart strategy of uploads, zero values keep the
+// defaults of minio-go.
+type multipartConfig struct {
+	PartSize  uint64
+	Threshold uint64
+	Disabled  bool
+}
+
+// parseUploadSize - parse and validate a part size or a multipart
+// threshold, both are bound by the part size limits of S3.
+func parseUploadSize(size string) (uint64, *probe.Error) {
+	value, e := humanize.ParseBytes(size)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(size)
+	}
+	if value < minUploadPartSize || value > maxUploadPartSize {
+		return 0, probe.NewError(fmt.Errorf("size must be between %s and %s",
+			humanize.IBytes(minUploadPartSize), humanize.IBytes(maxUploadPartSize))).Trace(size)
+	}
+	return value, nil
+}
+
+// newMultipartConfig - multipart strategy configured for a host, sizes
+// are validated by 'config host add' and ignored when invalid.
+func newMultipartConfig(hostCfg *hostConfigV9) multipartConfig {
+	var m multipartConfig
+	if hostCfg == nil {
+		return m
+	}
+	if hostCfg.PartSize != "" {
+		m.PartSize, _ = parseUploadSize(hostCfg.PartSize)
+	}
+	if hostCfg.MultipartThreshold != "" {
+		m.Threshold, _ = parseUploadSize(hostCfg.MultipartThreshold)
+	}
+	m.Disabled = hostCfg.DisableMultipart
+	return m
+}
+
+// override - the flags of the command line override the configuration
+// of a host.
+func (m multipartConfig) override(flags multipartConfig) multipartConfig {
+	if flags.PartSize > 0 {
+		m.PartSize = flags.PartSize
+	}
+	if flags.Threshold > 0 {
+		m.Threshold = flags.Threshold
+	}
+	m.Disabled = m.Disabled || flags.Disabled
+	return m
+}
+
+// uploadPartSize - part size passed to minio-go for an upload of size
+// bytes, a size of -1 is unknown. minio-go uploads objects smaller than
+// the part size with a single PUT, so the threshold is applied by
+// raising or lowering the part size around the object size.
+func (m multipartConfig) uploadPartSize(size int64) (uint64, *probe.Error) {
+	if m == (multipartConfig{}) {
+		return 0, nil
+	}
+
+	threshold := m.Threshold
+	if threshold == 0 {
+		threshold = defaultMultipartThreshold
+		if m.PartSize > 0 {
+			threshold = m.PartSize
+		}
+	}
+
+	if m.Disabled || (size >= 0 && uint64(size) < threshold) {
+		if size < 0 {
+			return 0, probe.NewError(errors.New("size of the stream is unknown, it cannot be uploaded with multipart disabled"))
+		}
+		if uint64(size) > maxSinglePutSize {
+			return 0, probe.NewError(fmt.Errorf("objects larger than %s cannot be uploaded with multipart disabled",
+				humanize.IBytes(maxSinglePutSize)))
+		}
+		// A part larger than the object is a single PUT.
+		return uint64(size) + 1, nil
+	}
+
+	if m.PartSize == 0 {
+		if size >= 0 && uint64(size) < defaultMultipartThreshold {
+			// Below the default part size, upload a single part.
+			return uint64(size), nil
+		}
+		return 0, nil
+	}
+
+	maxSize := uint64(maxMultipartUploadSize)
+	if size >= 0 {
+		if uint64(size) < m.PartSize {
+			return uint64(size), nil
+		}
+		maxSize = uint64(size)
+	}
+	if maxSize > m.PartSize*maxUploadParts {
+		return 0, probe.NewError(fmt.Errorf("part size %s is too small, objects of %s need more than %d parts",
+			humanize.IBytes(m.PartSize), describeUploadSize(size), maxUploadParts))
+	}
+	return m.PartSize, nil
+}
+
+// resumablePartSize - part size of an upload of size bytes which is
+// resumed part by part, false when the object is below the multipart
+// threshold. Without a configured part size, parts are multiples of the
+// stream part size, as minio-go would choose.
+func (m multipartConfig) resumablePartSize(size int64) (int64, bool, *probe.Error) {
+	threshold := m.Threshold
+	if threshold == 0 {
+		threshold = defaultMultipartThreshold
+		if m.PartSize > 0 {
+			threshold = m.PartSize
+		}
+	}
+	if m.Disabled || uint64(size) < threshold {
+		return 0, false, nil
+	}
+	if size > maxMultipartUploadSize {
+		return 0, false, probe.NewError(fmt.Errorf("objects larger than %s cannot be uploaded",
+			humanize.IBytes(maxMultipartUploadSize)))
+	}
+	if m.PartSize > 0 {
+		if uint64(size) > m.PartSize*maxUploadParts {
+			return 0, false, probe.NewError(fmt.Errorf("part size %s is too small, objects of %s need more than %d parts",
+				humanize.IBytes(m.PartSize), describeUploadSize(size), maxUploadParts))
+		}
+		return int64(m.PartSize), true, nil
+	}
+	parts := (size + maxUploadParts*defaultStreamPartSize - 1) / (maxUploadParts * defaultStreamPartSize)
+	return parts * defaultStreamPartSize, true, nil
+}
+
+// streamPartSize - part size of an upload of a stream of unknown length,
+// sizeHint is its expected size or -1. Without a configured part size,
+// twice the expected size fits in the parts.
+func (m multipartConfig) streamPartSize(sizeHint int64) (int64, *probe.Error) {
+	if m.Disabled {
+		return 0, probe.NewError(errors.New("size of the stream is unknown, it cannot be uploaded with multipart disabled"))
+	}
+	if sizeHint > maxMultipartUploadSize {
+		return 0, probe.NewError(fmt.Errorf("streams larger than %s cannot be uploaded",
+			humanize.IBytes(maxMultipartUploadSize)))
+	}
+	if m.PartSize > 0 {
+		if sizeHint > 0 && uint64(sizeHint) > m.PartSize*maxUploadParts {
+			return 0, probe.NewError(fmt.Errorf("part size %s is too small, objects of %s need more than %d parts",
+				humanize.IBytes(m.PartSize), describeUploadSize(sizeHint), maxUploadParts))
+		}
+		return int64(m.PartSize), nil
+	}
+	if sizeHint < 0 {
+		return defaultStreamPartSize, nil
+	}
+	// Rounded up to MiB.
+	partSize := (2*uint64(sizeHint)/maxUploadParts + humanize.MiByte - 1) / humanize.MiByte * humanize.MiByte
+	if partSize < minUploadPartSize {
+		partSize = minUploadPartSize
+	}
+	return int64(partSize), nil
+}
+
+// describeUploadSize - human readable size of an upload, -1 is unknown.
+func describeUploadSize(size int64) string {
+	if size < 0 {
+		return "unknown size"
+	}
+	return humanize.IBytes(uint64(size))
+}
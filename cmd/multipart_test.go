@@ -0,0 +1,137 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+func TestParseUploadSize(t *testing.T) {
+	testCases := []struct {
+		size     string
+		expected uint64
+		success  bool
+	}{
+		{"5MiB", 5 * humanize.MiByte, true},
+		{"16MiB", 16 * humanize.MiByte, true},
+		{"5GiB", 5 * humanize.GiByte, true},
+		{"4MiB", 0, false},
+		{"6GiB", 0, false},
+		{"sixteen", 0, false},
+	}
+	for i, testCase := range testCases {
+		size, err := parseUploadSize(testCase.size)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if size != testCase.expected {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.expected, size)
+		}
+	}
+}
+
+func TestMultipartConfigOverride(t *testing.T) {
+	hostCfg := &hostConfigV9{PartSize: "16MiB", MultipartThreshold: "64MiB"}
+	m := newMultipartConfig(hostCfg)
+	if m != (multipartConfig{PartSize: 16 * humanize.MiByte, Threshold: 64 * humanize.MiByte}) {
+		t.Fatalf("unexpected host config %v", m)
+	}
+	m = m.override(multipartConfig{PartSize: 32 * humanize.MiByte, Disabled: true})
+	if m != (multipartConfig{PartSize: 32 * humanize.MiByte, Threshold: 64 * humanize.MiByte, Disabled: true}) {
+		t.Errorf("unexpected overridden config %v", m)
+	}
+	// Invalid sizes saved by hand are ignored.
+	if m = newMultipartConfig(&hostConfigV9{PartSize: "1KiB"}); m != (multipartConfig{}) {
+		t.Errorf("unexpected config %v", m)
+	}
+}
+
+func TestUploadPartSize(t *testing.T) {
+	const MiB = humanize.MiByte
+	testCases := []struct {
+		multipart multipartConfig
+		size      int64
+		partSize  uint64
+		success   bool
+	}{
+		// Defaults of minio-go.
+		{multipartConfig{}, 10 * MiB, 0, true},
+		{multipartConfig{}, -1, 0, true},
+		// Objects smaller than the part size are a single PUT.
+		{multipartConfig{PartSize: 16 * MiB}, 10 * MiB, 10*MiB + 1, true},
+		{multipartConfig{PartSize: 16 * MiB}, 100 * MiB, 16 * MiB, true},
+		{multipartConfig{PartSize: 16 * MiB}, 200 * 1024 * MiB, 0, false},
+		// Unknown sizes are up to 5TiB.
+		{multipartConfig{PartSize: 16 * MiB}, -1, 0, false},
+		{multipartConfig{PartSize: 1024 * MiB}, -1, 1024 * MiB, true},
+		// A threshold above the part size.
+		{multipartConfig{PartSize: 16 * MiB, Threshold: 64 * MiB}, 30 * MiB, 30*MiB + 1, true},
+		{multipartConfig{PartSize: 16 * MiB, Threshold: 64 * MiB}, 64 * MiB, 16 * MiB, true},
+		// A threshold below the part size uploads a single part.
+		{multipartConfig{PartSize: 64 * MiB, Threshold: 16 * MiB}, 30 * MiB, 30 * MiB, true},
+		{multipartConfig{Threshold: 16 * MiB}, 30 * MiB, 30 * MiB, true},
+		{multipartConfig{Threshold: 16 * MiB}, 10 * MiB, 10*MiB + 1, true},
+		{multipartConfig{Threshold: 16 * MiB}, 200 * MiB, 0, true},
+		// Multipart disabled.
+		{multipartConfig{Disabled: true}, 0, 1, true},
+		{multipartConfig{Disabled: true}, 200 * MiB, 200*MiB + 1, true},
+		{multipartConfig{Disabled: true}, 6 * 1024 * MiB, 0, false},
+		{multipartConfig{Disabled: true}, -1, 0, false},
+	}
+	for i, testCase := range testCases {
+		partSize, err := testCase.multipart.uploadPartSize(testCase.size)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if partSize != testCase.partSize {
+			t.Errorf("Test %d: expected part size %d, got %d", i+1, testCase.partSize, partSize)
+		}
+	}
+}
+
+func TestStreamPartSize(t *testing.T) {
+	const MiB = humanize.MiByte
+	testCases := []struct {
+		multipart multipartConfig
+		sizeHint  int64
+		partSize  int64
+		success   bool
+	}{
+		{multipartConfig{}, -1, 64 * MiB, true},
+		// Twice the expected size fits in the parts.
+		{multipartConfig{}, 0, 5 * MiB, true},
+		{multipartConfig{}, 10 * 1024 * MiB, 5 * MiB, true},
+		{multipartConfig{}, 1024 * 1024 * MiB, 210 * MiB, true},
+		{multipartConfig{}, 6 * 1024 * 1024 * MiB, 0, false},
+		// A configured part size is kept, if the expected size fits.
+		{multipartConfig{PartSize: 16 * MiB}, -1, 16 * MiB, true},
+		{multipartConfig{PartSize: 16 * MiB}, 100 * 1024 * MiB, 16 * MiB, true},
+		{multipartConfig{PartSize: 16 * MiB}, 200 * 1024 * MiB, 0, false},
+		{multipartConfig{Disabled: true}, -1, 0, false},
+	}
+	for i, testCase := range testCases {
+		partSize, err := testCase.multipart.streamPartSize(testCase.sizeHint)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if partSize != testCase.partSize {
+			t.Errorf("Test %d: expected part size %d, got %d", i+1, testCase.partSize, partSize)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// mv command flags.
+var (
+	mvFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "move recursively",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "move objects older than L days, M hours and N minutes",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "move objects newer than L days, M hours and N minutes",
+		},
+		cli.StringFlag{
+			Name:  "storage-class, sc",
+			Usage: "set storage class for new object(s) on target",
+		},
+		cli.StringFlag{
+			Name:  "encrypt",
+			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
+		},
+		cli.StringFlag{
+			Name:  "attr",
+			Usage: "add custom metadata for the object",
+		},
+		parallelFlag,
+		sanitizeFlag,
+		adaptiveFlag,
+		controlSocketFlag,
+	}
+)
+
+// Move command.
+var mvCmd = cli.Command{
+	Name:   "mv",
+	Usage:  "move objects",
+	Action: mainMove,
+	Before: setGlobalsFromContext,
+	Flags:  append(append(append(append(mvFlags, contentHeaderFlags...), ioFlags...), uploadFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE [SOURCE...] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+   MC_ENCRYPT:      list of comma delimited prefixes
+   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+
+DESCRIPTION:
+  Objects are copied server-side when source and target are on the same host. A source is
+  only removed once the target is found with the size of the source, an interrupted move
+  can be resumed with 'mc session resume'.
+
+  S3 has no rename, a prefix is renamed by moving it recursively with trailing slashes on
+  both source and target. Server-side copies transfer no data through mc, use '--parallel'
+  to move many small objects at once.
+
+EXAMPLES:
+   1. Move a list of objects from local file system to Amazon S3 cloud storage.
+      $ {{.HelpName}} Music/*.ogg s3/jukebox/
+
+   2. Move a folder recursively from MinIO cloud storage to Amazon S3 cloud storage.
+      $ {{.HelpName}} --recursive play/mybucket/burningman2011/ s3/mybucket/
+
+   3. Rename an object, server-side, on MinIO cloud storage.
+      $ {{.HelpName}} play/mybucket/draft.txt play/mybucket/final.txt
+
+   4. Move objects older than 90 days to an archive bucket with a cheaper storage class.
+      $ {{.HelpName}} --recursive --older-than 90d --storage-class REDUCED_REDUNDANCY s3/logs/ s3/logs-archive/
+
+   5. Rename the prefix 'drafts/' of the bucket 'mybucket' to 'published/', moving 32 objects at a time.
+      $ {{.HelpName}} --recursive --parallel 32 play/mybucket/drafts/ play/mybucket/published/
+
+   6. Move a large folder over a network of unknown capacity, tuning the number of parallel moves while moving.
+      $ {{.HelpName}} --recursive --adaptive backup/ s3/archive/
+
+   7. Move a folder with encrypted objects recursively from Amazon S3 to MinIO cloud storage.
+      $ {{.HelpName}} --recursive --encrypt-key "s3/documents/=32byteslongsecretkeymustbegiven1,myminio/documents/=32byteslongsecretkeymustbegiven2" s3/documents/ myminio/documents/
+`,
+}
+
+// doMove - copy a single object and remove its source once the target
+// is verified.
+func doMove(ctx context.Context, mvURLs URLs, pg ProgressReader, control *transferControl, encKeyDB map[string][]prefixSSEPair) URLs {
+	if mvURLs.Error != nil {
+		mvURLs.Error = mvURLs.Error.Trace()
+		return mvURLs
+	}
+	sourceURL := mvURLs.SourceContent.URL.String()
+	if mvURLs.SourceAlias == mvURLs.TargetAlias && sourceURL == mvURLs.TargetContent.URL.String() {
+		return mvURLs.WithError(errMoveOntoItself(sourceURL))
+	}
+
+	mvURLs = doCopy(ctx, mvURLs, pg, control, encKeyDB)
+	if mvURLs.Error != nil {
+		return mvURLs
+	}
+	if err := verifyMoveTarget(mvURLs, encKeyDB); err != nil {
+		return mvURLs.WithError(err.Trace(sourceURL))
+	}
+
+	clnt, err := newClientFromAlias(mvURLs.SourceAlias, sourceURL)
+	if err != nil {
+		return mvURLs.WithError(err.Trace(sourceURL))
+	}
+	contentCh := make(chan *clientContent, 1)
+	contentCh <- &clientContent{URL: mvURLs.SourceContent.URL}
+	close(contentCh)
+	isRemoveBucket := false
+	for err := range clnt.Remove(false, isRemoveBucket, contentCh) {
+		if err != nil {
+			return mvURLs.WithError(err.Trace(sourceURL))
+		}
+	}
+	return mvURLs
+}
+
+// verifyMoveTarget - verify that the target of a copied object has the
+// size of its source before the source is removed.
+func verifyMoveTarget(mvURLs URLs, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	sourcePath := filepath.ToSlash(filepath.Join(mvURLs.SourceAlias, mvURLs.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(mvURLs.TargetAlias, mvURLs.TargetContent.URL.Path))
+	clnt, err := newClientFromAlias(mvURLs.TargetAlias, mvURLs.TargetContent.URL.String())
+	if err != nil {
+		return err.Trace(targetPath)
+	}
+	content, err := clnt.Stat(globalContext, false, false, getSSE(targetPath, encKeyDB[mvURLs.TargetAlias]))
+	if err != nil {
+		return err.Trace(targetPath)
+	}
+	if content.Size != mvURLs.SourceContent.Size {
+		return errMoveVerification(sourcePath, targetPath)
+	}
+	return nil
+}
+
+// removeEmptySourceDirs - remove the folders of moved files left empty
+// by a recursive move, and their parents up to the folders of the local
+// sources. Folders which held no moved file, or still hold files, are kept.
+func removeEmptySourceDirs(sourceURLs []string, movedDirs map[string]bool) {
+	for _, sourceURL := range sourceURLs {
+		clnt, err := newClient(sourceURL)
+		if err != nil {
+			continue
+		}
+		fsClnt, ok := clnt.(*fsClient)
+		if !ok {
+			continue
+		}
+		root, e := filepath.Abs(fsClnt.PathURL.Path)
+		if e != nil {
+			continue
+		}
+		for movedDir := range movedDirs {
+			dir, e := filepath.Abs(movedDir)
+			if e != nil || (dir != root && !strings.HasPrefix(dir, root+string(filepath.Separator))) {
+				continue
+			}
+			for {
+				// Folders removed meanwhile for another moved file are skipped.
+				if e = os.Remove(dir); e != nil && !os.IsNotExist(e) {
+					break
+				}
+				if dir == root {
+					break
+				}
+				dir = filepath.Dir(dir)
+			}
+		}
+	}
+}
+
+// checkMoveTarget - a source is never moved onto itself, and a folder
+// is never moved into one of its own sub-folders.
+func checkMoveTarget(srcURL, tgtURL string, isRecursive bool) *probe.Error {
+	srcAlias, srcPath, _ := mustExpandAlias(srcURL)
+	tgtAlias, tgtPath, _ := mustExpandAlias(tgtURL)
+	if srcAlias != tgtAlias {
+		return nil
+	}
+	srcPath, tgtPath = filepath.ToSlash(filepath.Clean(srcPath)), filepath.ToSlash(filepath.Clean(tgtPath))
+	if srcPath == tgtPath {
+		return errMoveOntoItself(srcURL)
+	}
+	if isRecursive && strings.HasPrefix(tgtPath, strings.TrimSuffix(srcPath, "/")+"/") {
+		return errMoveIntoSource(srcURL, tgtURL)
+	}
+	return nil
+}
+
+// checkMoveSyntax - validate all the passed arguments.
+func checkMoveSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "mv", 1) // last argument is exit code.
+	}
+	checkCopySyntax(ctx, ctx.Args(), encKeyDB)
+
+	URLs := ctx.Args()
+	tgtURL := URLs[len(URLs)-1]
+	for _, srcURL := range URLs[:len(URLs)-1] {
+		err := checkMoveTarget(srcURL, tgtURL, ctx.Bool("recursive"))
+		fatalIf(err.Trace(srcURL, tgtURL), "Unable to move `"+srcURL+"`.")
+	}
+}
+
+// mainMove is the entry point for mv command.
+func mainMove(ctx *cli.Context) error {
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(ctx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	// Parse metadata.
+	userMetaMap := make(map[string]string)
+	if ctx.String("attr") != "" {
+		userMetaMap, err = getMetaDataEntry(ctx.String("attr"))
+		fatalIf(err, "Unable to parse attribute %v", ctx.String("attr"))
+	}
+	// Content headers of their flags override those of --attr.
+	for k, v := range parseContentHeaders(ctx) {
+		userMetaMap[k] = v
+	}
+
+	// check 'move' cli arguments.
+	checkMoveSyntax(ctx, encKeyDB)
+	// Sources are removed, so they must be writable as well.
+	checkWritableTargets(ctx.Args()...)
+
+	// Additional command specific theme customization.
+	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+
+	sseKeys := os.Getenv("MC_ENCRYPT_KEY")
+	if key := ctx.String("encrypt-key"); key != "" {
+		sseKeys = key
+	}
+
+	session := newSessionV8()
+	session.Header.CommandType = "mv"
+	session.Header.CommandBoolFlags["recursive"] = ctx.Bool("recursive")
+	session.Header.CommandBoolFlags["adaptive"] = ctx.Bool("adaptive")
+	session.Header.CommandStringFlags["older-than"] = ctx.String("older-than")
+	session.Header.CommandStringFlags["newer-than"] = ctx.String("newer-than")
+	session.Header.CommandStringFlags["storage-class"] = ctx.String("storage-class")
+	session.Header.CommandStringFlags["encrypt-key"] = sseKeys
+	session.Header.CommandStringFlags["encrypt"] = ctx.String("encrypt")
+	session.Header.CommandStringFlags["control-socket"] = ctx.String("control-socket")
+	session.Header.CommandIntFlags["parallel"] = ctx.Int("parallel")
+	session.Header.UserMetaData = userMetaMap
+
+	var e error
+	if session.Header.RootPath, e = os.Getwd(); e != nil {
+		session.Delete()
+		fatalIf(probe.NewError(e), "Unable to get current working folder.")
+	}
+
+	// extract URLs.
+	session.Header.CommandArgs = ctx.Args()
+	e = doCopySession(session, encKeyDB, true)
+	session.Delete()
+
+	return e
+}
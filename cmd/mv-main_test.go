@@ -0,0 +1,188 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestDoMove(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		return newConfigV9(), nil
+	}
+
+	root, e := ioutil.TempDir("", "mc-mv-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+
+	data := []byte("Hello, World")
+	newMoveURLs := func(source, target string) URLs {
+		if e := ioutil.WriteFile(source, data, 0600); e != nil {
+			t.Fatal(e)
+		}
+		return URLs{
+			SourceContent: &clientContent{URL: *newClientURL(source), Size: int64(len(data))},
+			TargetContent: &clientContent{URL: *newClientURL(target)},
+		}
+	}
+
+	// The source is removed once copied.
+	source, target := filepath.Join(root, "source"), filepath.Join(root, "target")
+	mvURLs := doMove(context.Background(), newMoveURLs(source, target), newAccounter(0), nil, nil)
+	if mvURLs.Error != nil {
+		t.Fatalf("unexpected error %s", mvURLs.Error)
+	}
+	if _, e = os.Stat(source); !os.IsNotExist(e) {
+		t.Errorf("expected %s to be removed, got %v", source, e)
+	}
+	if moved, e := ioutil.ReadFile(target); e != nil || string(moved) != string(data) {
+		t.Errorf("expected %s in %s, got %s, %v", data, target, moved, e)
+	}
+
+	// An object is never moved onto itself.
+	mvURLs = doMove(context.Background(), newMoveURLs(source, source), newAccounter(0), nil, nil)
+	if mvURLs.Error == nil {
+		t.Error("expected an error moving an object onto itself")
+	}
+	if _, e = os.Stat(source); e != nil {
+		t.Errorf("expected %s to be kept, got %v", source, e)
+	}
+
+	// A target not matching the source fails verification.
+	mvURLs = newMoveURLs(source, target)
+	mvURLs.SourceContent.Size++
+	if err := verifyMoveTarget(mvURLs, nil); err == nil {
+		t.Error("expected a verification error")
+	}
+}
+
+func TestRemoveEmptySourceDirs(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		return newConfigV9(), nil
+	}
+
+	root, e := ioutil.TempDir("", "mc-mv-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	// Files were moved out of 'src/c', 'src/d/e', 'empty/f' and 'other'.
+	for _, dir := range []string{"src/a/b", "src/c", "src/d/e", "empty/f", "other"} {
+		if e = os.MkdirAll(filepath.Join(root, dir), 0700); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if e = ioutil.WriteFile(filepath.Join(root, "src", "c", "kept"), nil, 0600); e != nil {
+		t.Fatal(e)
+	}
+	movedDirs := make(map[string]bool)
+	for _, dir := range []string{"src/c", "src/d/e", "empty/f", "other"} {
+		movedDirs[filepath.Join(root, filepath.FromSlash(dir))] = true
+	}
+
+	removeEmptySourceDirs([]string{filepath.Join(root, "src"), filepath.Join(root, "empty")}, movedDirs)
+	testCases := []struct {
+		path   string
+		exists bool
+	}{
+		// Folders which were empty before the move are kept.
+		{"src/a/b", true},
+		// Folders still holding files are kept.
+		{"src/c/kept", true},
+		// Folders left empty are removed with their parents, up to the source.
+		{"src/d", false},
+		{"src", true},
+		{"empty", false},
+		// Folders outside of the sources are kept.
+		{"other", true},
+	}
+	for i, testCase := range testCases {
+		_, e = os.Stat(filepath.Join(root, filepath.FromSlash(testCase.path)))
+		if exists := e == nil; exists != testCase.exists {
+			t.Errorf("Test %d: expected %s to exist %t, got %v", i+1, testCase.path, testCase.exists, e)
+		}
+	}
+}
+
+func TestCheckMoveTarget(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		config := newConfigV9()
+		config.Hosts["myminio"] = hostConfigV9{URL: "http://localhost:9000", AccessKey: "minio", SecretKey: "minio123"}
+		config.Hosts["mys3"] = hostConfigV9{URL: "https://s3.amazonaws.com", AccessKey: "minio", SecretKey: "minio123"}
+		return config, nil
+	}
+
+	testCases := []struct {
+		srcURL      string
+		tgtURL      string
+		isRecursive bool
+		success     bool
+	}{
+		{"myminio/bucket/drafts/", "myminio/bucket/published/", true, true},
+		{"myminio/bucket/drafts/", "myminio/bucket/", true, true},
+		{"myminio/bucket/drafts/", "myminio/bucket/drafts-old/", true, true},
+		{"myminio/bucket/drafts/", "mys3/bucket/drafts/", true, true},
+		{"myminio/bucket/object", "myminio/bucket/object", false, false},
+		{"myminio/bucket/drafts/", "myminio/bucket/drafts", true, false},
+		{"myminio/bucket/drafts/", "myminio/bucket/drafts/old/", true, false},
+		{"myminio/bucket/drafts", "myminio/bucket/drafts/old", false, true},
+		{"dir/", "dir/sub/", true, false},
+	}
+	for i, testCase := range testCases {
+		err := checkMoveTarget(testCase.srcURL, testCase.tgtURL, testCase.isRecursive)
+		if (err == nil) != testCase.success {
+			t.Errorf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+	}
+}
+
+func TestParallelManagerWithWorkers(t *testing.T) {
+	statusCh := make(chan URLs)
+	parallel, queueCh := newParallelManagerWithWorkers(statusCh, 8)
+	if parallel.workersNum != 8 {
+		t.Fatalf("expected 8 workers, got %d", parallel.workersNum)
+	}
+	go func() {
+		for i := 0; i < 100; i++ {
+			queueCh <- func() URLs { return URLs{} }
+		}
+		close(queueCh)
+		parallel.wait()
+		close(statusCh)
+	}()
+	count := 0
+	for range statusCh {
+		count++
+	}
+	if count != 100 {
+		t.Errorf("expected 100 results, got %d", count)
+	}
+}
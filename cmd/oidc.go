@@ -0,0 +1,218 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	// Grant type of tokens requested with a device code, RFC 8628.
+	oidcDeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// Polling interval of the token endpoint when the provider gives none.
+	oidcDefaultPollInterval = 5 * time.Second
+)
+
+// oidcProvider - endpoints of an OpenID Connect provider, as published
+// at the discovery URL of its issuer.
+type oidcProvider struct {
+	Issuer                      string `json:"issuer"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// oidcDeviceAuthorization - device code and the code shown to the user
+// who signs in.
+type oidcDeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// oidcTokenResponse - tokens of a token endpoint, or the OAuth error
+// code it answered with.
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// webIdentityToken - the token exchanged with the STS API of MinIO
+// server, the ID token unless the provider only issued an access token.
+func (t oidcTokenResponse) webIdentityToken() string {
+	if t.IDToken != "" {
+		return t.IDToken
+	}
+	return t.AccessToken
+}
+
+// oidcError - error of an OAuth error response.
+func (t oidcTokenResponse) oidcError() *probe.Error {
+	if t.ErrorDescription != "" {
+		return probe.NewError(fmt.Errorf("%s: %s", t.Error, t.ErrorDescription))
+	}
+	return probe.NewError(fmt.Errorf("%s", t.Error))
+}
+
+// doOIDCRequest - send a request to an OpenID Connect provider and
+// decode its JSON answer. OAuth error responses are decoded as well,
+// only other failures are errors.
+func doOIDCRequest(client *http.Client, req *http.Request, v interface{}) *probe.Error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", getUserAgent())
+	resp, e := client.Do(req.WithContext(globalContext))
+	if e != nil {
+		return probe.NewError(e).Trace(req.URL.String())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized {
+		return probe.NewError(fmt.Errorf("%s answered %s", req.URL, resp.Status))
+	}
+	if e = json.NewDecoder(resp.Body).Decode(v); e != nil {
+		return probe.NewError(fmt.Errorf("%s answered %s without a JSON answer: %s", req.URL, resp.Status, e))
+	}
+	return nil
+}
+
+// postOIDCForm - post a form to an endpoint of an OpenID Connect provider.
+func postOIDCForm(client *http.Client, endpoint string, form url.Values, v interface{}) *probe.Error {
+	req, e := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if e != nil {
+		return probe.NewError(e).Trace(endpoint)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doOIDCRequest(client, req, v)
+}
+
+// discoverOIDCProvider - the endpoints of the provider of an issuer.
+func discoverOIDCProvider(client *http.Client, issuer string) (oidcProvider, *probe.Error) {
+	var provider oidcProvider
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, e := http.NewRequest(http.MethodGet, discoveryURL, nil)
+	if e != nil {
+		return provider, probe.NewError(e).Trace(discoveryURL)
+	}
+	if err := doOIDCRequest(client, req, &provider); err != nil {
+		return provider, err.Trace(discoveryURL)
+	}
+	if provider.TokenEndpoint == "" {
+		return provider, probe.NewError(fmt.Errorf("%s publishes no token endpoint", discoveryURL))
+	}
+	if provider.DeviceAuthorizationEndpoint == "" {
+		return provider, probe.NewError(fmt.Errorf("%s publishes no device authorization endpoint, the provider does not support signing in with a device code", discoveryURL))
+	}
+	return provider, nil
+}
+
+// requestDeviceAuthorization - start signing in with a device code.
+func requestDeviceAuthorization(client *http.Client, provider oidcProvider, clientID, scope string) (oidcDeviceAuthorization, *probe.Error) {
+	var auth struct {
+		oidcDeviceAuthorization
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	form.Set("scope", scope)
+	if err := postOIDCForm(client, provider.DeviceAuthorizationEndpoint, form, &auth); err != nil {
+		return auth.oidcDeviceAuthorization, err.Trace(clientID)
+	}
+	if auth.Error != "" {
+		return auth.oidcDeviceAuthorization, oidcTokenResponse{Error: auth.Error, ErrorDescription: auth.ErrorDescription}.oidcError()
+	}
+	if auth.DeviceCode == "" || auth.UserCode == "" || auth.VerificationURI == "" {
+		return auth.oidcDeviceAuthorization, probe.NewError(fmt.Errorf("%s answered without a device code", provider.DeviceAuthorizationEndpoint))
+	}
+	return auth.oidcDeviceAuthorization, nil
+}
+
+// pollDeviceToken - poll the token endpoint until the user signed in,
+// refused or the device code expired.
+func pollDeviceToken(client *http.Client, provider oidcProvider, clientID string, auth oidcDeviceAuthorization) (oidcTokenResponse, *probe.Error) {
+	interval := oidcDefaultPollInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+	var deadline <-chan time.Time
+	if auth.ExpiresIn > 0 {
+		deadline = time.After(time.Duration(auth.ExpiresIn) * time.Second)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", oidcDeviceCodeGrantType)
+	form.Set("device_code", auth.DeviceCode)
+	form.Set("client_id", clientID)
+	for {
+		select {
+		case <-globalContext.Done():
+			return oidcTokenResponse{}, errCancelled()
+		case <-deadline:
+			return oidcTokenResponse{}, probe.NewError(fmt.Errorf("the code `%s` expired before signing in", auth.UserCode))
+		case <-time.After(interval):
+		}
+
+		var token oidcTokenResponse
+		if err := postOIDCForm(client, provider.TokenEndpoint, form, &token); err != nil {
+			return token, err.Trace(clientID)
+		}
+		switch token.Error {
+		case "":
+			if token.webIdentityToken() == "" {
+				return token, probe.NewError(fmt.Errorf("%s answered without a token", provider.TokenEndpoint))
+			}
+			return token, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return token, token.oidcError()
+		}
+	}
+}
+
+// refreshOIDCToken - new tokens for a refresh token.
+func refreshOIDCToken(client *http.Client, tokenEndpoint, clientID, refreshToken string) (oidcTokenResponse, *probe.Error) {
+	var token oidcTokenResponse
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", clientID)
+	if err := postOIDCForm(client, tokenEndpoint, form, &token); err != nil {
+		return token, err.Trace(clientID)
+	}
+	if token.Error != "" {
+		return token, token.oidcError()
+	}
+	if token.webIdentityToken() == "" {
+		return token, probe.NewError(fmt.Errorf("%s answered without a token", tokenEndpoint))
+	}
+	return token, nil
+}
@@ -0,0 +1,255 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// outputSchemaVersion is the version of the structured output, added as
+// `schemaVersion` to every JSON, YAML and CSV record. It is bumped
+// whenever a field is removed or changes meaning, new fields may be
+// added without a bump.
+const outputSchemaVersion = "1"
+
+// Supported values of --output.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputCSV   = "csv"
+)
+
+// isValidOutput - validate --output value.
+func isValidOutput(output string) bool {
+	switch output {
+	case "", outputTable, outputJSON, outputYAML, outputCSV:
+		return true
+	}
+	return false
+}
+
+// Color codes added by the colorized JSON encoder.
+var ansiEscapeRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// orderedField - a key and value of a JSON object.
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+// orderedObject - a decoded JSON object, keeping fields in the
+// order the message encoded them.
+type orderedObject []orderedField
+
+// MarshalYAML implements yaml.Marshaler.
+func (o orderedObject) MarshalYAML() (interface{}, error) {
+	m := make(yaml.MapSlice, len(o))
+	for i, f := range o {
+		m[i] = yaml.MapItem{Key: f.Key, Value: f.Value}
+	}
+	return m, nil
+}
+
+// decodeOrdered decodes a single JSON value, objects are decoded
+// into orderedObject and numbers into int64 or float64.
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	token, e := dec.Token()
+	if e != nil {
+		return nil, e
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			var o orderedObject
+			for dec.More() {
+				key, e := dec.Token()
+				if e != nil {
+					return nil, e
+				}
+				value, e := decodeOrdered(dec)
+				if e != nil {
+					return nil, e
+				}
+				o = append(o, orderedField{key.(string), value})
+			}
+			_, e = dec.Token() // '}'
+			return o, e
+		case '[':
+			a := []interface{}{}
+			for dec.More() {
+				value, e := decodeOrdered(dec)
+				if e != nil {
+					return nil, e
+				}
+				a = append(a, value)
+			}
+			_, e = dec.Token() // ']'
+			return a, e
+		}
+	case json.Number:
+		if i, e := t.Int64(); e == nil {
+			return i, nil
+		}
+		return t.Float64()
+	}
+	return token, nil
+}
+
+// parseMessageJSON decodes the, possibly colorized, JSON of a message.
+func parseMessageJSON(jsonStr string) (orderedObject, error) {
+	dec := json.NewDecoder(strings.NewReader(ansiEscapeRegex.ReplaceAllString(jsonStr, "")))
+	dec.UseNumber()
+	v, e := decodeOrdered(dec)
+	if e != nil {
+		return nil, e
+	}
+	o, ok := v.(orderedObject)
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object, got %T", v)
+	}
+	return append(orderedObject{{"schemaVersion", outputSchemaVersion}}, o...), nil
+}
+
+// withSchemaVersion adds the schema version as first field of the JSON
+// object of a message, keeping its colors and indentation as is.
+func withSchemaVersion(jsonStr string) string {
+	trimmed := strings.TrimLeft(jsonStr, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "{") {
+		return jsonStr
+	}
+	prefix := jsonStr[:len(jsonStr)-len(trimmed)+1]
+	rest := trimmed[1:]
+	body := strings.TrimLeft(rest, " \t\r\n")
+	space := rest[:len(rest)-len(body)]
+	field := console.Colorize("jsonBoldBlue", `"schemaVersion"`) + ":"
+	if space != "" {
+		// Indented output has a space after colons.
+		field += " "
+	}
+	field += console.Colorize("jsonGreen", `"`+outputSchemaVersion+`"`)
+	if strings.HasPrefix(body, "}") {
+		return prefix + space + field + rest
+	}
+	return prefix + space + field + "," + rest
+}
+
+// flattenRecord flattens nested objects into `parent.child` columns,
+// arrays are kept as JSON.
+func flattenRecord(prefix string, o orderedObject, keys, values *[]string) {
+	for _, f := range o {
+		key := f.Key
+		if prefix != "" {
+			key = prefix + "." + f.Key
+		}
+		switch v := f.Value.(type) {
+		case orderedObject:
+			flattenRecord(key, v, keys, values)
+			continue
+		case []interface{}:
+			b, _ := json.Marshal(toPlain(v))
+			*values = append(*values, string(b))
+		case nil:
+			*values = append(*values, "")
+		default:
+			*values = append(*values, fmt.Sprint(v))
+		}
+		*keys = append(*keys, key)
+	}
+}
+
+// toPlain converts ordered objects back to maps for JSON encoding.
+func toPlain(v interface{}) interface{} {
+	switch t := v.(type) {
+	case orderedObject:
+		m := make(map[string]interface{}, len(t))
+		for _, f := range t {
+			m[f.Key] = toPlain(f.Value)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i := range t {
+			a[i] = toPlain(t[i])
+		}
+		return a
+	}
+	return v
+}
+
+// csvOutput writes records under a header, repeated whenever the
+// columns change from one record to the next.
+type csvOutput struct {
+	mutex   sync.Mutex
+	columns []string
+}
+
+var globalCSVOutput = &csvOutput{}
+
+func (c *csvOutput) format(o orderedObject) string {
+	var keys, values []string
+	flattenRecord("", o, &keys, &values)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if strings.Join(keys, ",") != strings.Join(c.columns, ",") {
+		c.columns = keys
+		w.Write(keys)
+	}
+	w.Write(values)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// formatStructured formats the JSON of a message as selected by --output.
+func formatStructured(jsonStr string) string {
+	switch globalOutput {
+	case outputYAML, outputCSV:
+	default:
+		return withSchemaVersion(jsonStr)
+	}
+
+	o, e := parseMessageJSON(jsonStr)
+	if e != nil {
+		// Not a message object, print it as is.
+		errorIf(probe.NewError(e), "Unable to convert output to %s.", globalOutput)
+		return jsonStr
+	}
+	if globalOutput == outputCSV {
+		return globalCSVOutput.format(o)
+	}
+	b, e := yaml.Marshal(o)
+	if e != nil {
+		errorIf(probe.NewError(e), "Unable to convert output to YAML.")
+		return jsonStr
+	}
+	return "---\n" + strings.TrimSuffix(string(b), "\n")
+}
@@ -0,0 +1,72 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestWithSchemaVersion(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`{"status":"success"}`, `{"schemaVersion":"1","status":"success"}`},
+		{"{\n \"status\": \"success\"\n}", "{\n \"schemaVersion\": \"1\",\n \"status\": \"success\"\n}"},
+		{`{}`, `{"schemaVersion":"1"}`},
+		{`[1,2]`, `[1,2]`},
+	}
+	for i, testCase := range testCases {
+		got := ansiEscapeRegex.ReplaceAllString(withSchemaVersion(testCase.input), "")
+		if got != testCase.expected {
+			t.Errorf("Test %d: Expected `%s`, got `%s`", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestStructuredOutput(t *testing.T) {
+	o, e := parseMessageJSON(`{"status":"success","key":"a.txt","size":10,"meta":{"etag":"x"}}`)
+	if e != nil {
+		t.Fatal(e)
+	}
+	b, e := yaml.Marshal(o)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expectedYAML := "schemaVersion: \"1\"\nstatus: success\nkey: a.txt\nsize: 10\nmeta:\n  etag: x\n"
+	if string(b) != expectedYAML {
+		t.Errorf("Expected YAML `%s`, got `%s`", expectedYAML, string(b))
+	}
+
+	c := &csvOutput{}
+	if got, expected := c.format(o), "schemaVersion,status,key,size,meta.etag\n1,success,a.txt,10,x"; got != expected {
+		t.Errorf("Expected CSV `%s`, got `%s`", expected, got)
+	}
+	// Same columns, no header.
+	if got, expected := c.format(o), "1,success,a.txt,10,x"; got != expected {
+		t.Errorf("Expected CSV `%s`, got `%s`", expected, got)
+	}
+	o, e = parseMessageJSON(`{"status":"error","error":{"message":"failed"}}`)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if got, expected := c.format(o), "schemaVersion,status,error.message\n1,error,failed"; got != expected {
+		t.Errorf("Expected CSV `%s`, got `%s`", expected, got)
+	}
+}
@@ -17,10 +17,13 @@
 package cmd
 
 import (
+	"fmt"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/minio/cli"
 )
 
 const (
@@ -32,8 +35,41 @@ const (
 
 	// Number of workers added per bandwidth monitoring.
 	defaultWorkerFactor = 2
+
+	// Number of workers an adaptive manager starts with.
+	adaptiveStartWorkers = 2
+
+	// Percentage of failed tasks from which adaptive managers halve
+	// the number of workers.
+	adaptiveMaxErrorRate = 10
+
+	// Percentage of bandwidth change considered noise by adaptive managers.
+	adaptiveBandwidthNoise = 5
 )
 
+// adaptiveFlag tunes the number of parallel transfers while transferring.
+var adaptiveFlag = cli.BoolFlag{
+	Name:  "adaptive",
+	Usage: "start with 2 parallel transfers, then add or remove transfers following the measured throughput and errors",
+}
+
+// parallelFlag sets the number of parallel transfers.
+var parallelFlag = cli.IntFlag{
+	Name:  "parallel",
+	Usage: "number of objects transferred in parallel, follows the transfer speed by default",
+}
+
+// checkParallelFlag - validate --parallel, which excludes --adaptive.
+func checkParallelFlag(ctx *cli.Context) {
+	if parallel := ctx.Int("parallel"); parallel < 0 || parallel > maxParallelWorkers {
+		fatalIf(errInvalidArgument().Trace(ctx.String("parallel")),
+			fmt.Sprintf("Unable to transfer with `--parallel %d`, between 0 and %d objects are transferred in parallel.", parallel, maxParallelWorkers))
+	}
+	if ctx.IsSet("parallel") && ctx.Bool("adaptive") {
+		fatalIf(errInvalidArgument().Trace(ctx.String("parallel")), "Unable to transfer with both `--parallel` and `--adaptive`.")
+	}
+}
+
 // ParallelManager - helps manage parallel workers to run tasks
 type ParallelManager struct {
 	// Synchronize workers
@@ -45,6 +81,18 @@ type ParallelManager struct {
 	// Calculate sent bytes.
 	sentBytes int64
 
+	// Number of workers of adaptive managers, extra workers quit
+	// after their task. Zero for other managers.
+	targetWorkers uint32
+
+	// Finished and failed tasks.
+	doneTasks   int64
+	failedTasks int64
+
+	// Progress sampled by adaptive managers, the bytes read through
+	// the manager are sampled when nil.
+	progress Progress
+
 	// Channel to receive tasks to run
 	queueCh chan func() URLs
 	// Channel to send back results
@@ -68,6 +116,10 @@ func (p *ParallelManager) addWorker() {
 	p.wg.Add(1)
 	go func() {
 		for {
+			if p.retireWorker() {
+				p.wg.Done()
+				return
+			}
 			// Wait for jobs
 			fn, ok := <-p.queueCh
 			if !ok {
@@ -77,11 +129,40 @@ func (p *ParallelManager) addWorker() {
 			}
 			// Execute the task and send the result
 			// to result channel.
-			p.resultCh <- fn()
+			urls := fn()
+			if urls.Error != nil && !isInterrupted() {
+				atomic.AddInt64(&p.failedTasks, 1)
+			}
+			atomic.AddInt64(&p.doneTasks, 1)
+			p.resultCh <- urls
 		}
 	}()
 }
 
+// retireWorker returns true if the calling worker should quit, when
+// there are more workers than an adaptive manager wants.
+func (p *ParallelManager) retireWorker() bool {
+	for {
+		workers := atomic.LoadUint32(&p.workersNum)
+		target := atomic.LoadUint32(&p.targetWorkers)
+		if target == 0 || workers <= target {
+			return false
+		}
+		if atomic.CompareAndSwapUint32(&p.workersNum, workers, workers-1) {
+			return true
+		}
+	}
+}
+
+// setWorkers sets the number of workers of an adaptive manager, extra
+// workers quit once they finish their task.
+func (p *ParallelManager) setWorkers(workers int) {
+	atomic.StoreUint32(&p.targetWorkers, uint32(workers))
+	for current := p.workers(); current < workers; current++ {
+		p.addWorker()
+	}
+}
+
 func (p *ParallelManager) Read(b []byte) (n int, err error) {
 	atomic.AddInt64(&p.sentBytes, int64(len(b)))
 	return len(b), nil
@@ -131,6 +212,104 @@ func (p *ParallelManager) monitorProgress() {
 	}()
 }
 
+// sentBytesTotal returns the number of bytes transferred so far.
+func (p *ParallelManager) sentBytesTotal() int64 {
+	if p.progress != nil {
+		return p.progress.Get()
+	}
+	return atomic.LoadInt64(&p.sentBytes)
+}
+
+// monitorAdaptive adds and removes workers following the transfer
+// speed and the rate of failed tasks, as long as tasks are running.
+func (p *ParallelManager) monitorAdaptive() {
+	go func() {
+		ticker := time.NewTicker(monitorPeriod)
+		defer ticker.Stop()
+
+		tuner := newAdaptiveTuner(adaptiveStartWorkers)
+		var prevSentBytes, prevDone, prevFailed int64
+		for {
+			select {
+			case <-p.stopMonitorCh:
+				return
+			case <-ticker.C:
+				sentBytes := p.sentBytesTotal()
+				done := atomic.LoadInt64(&p.doneTasks)
+				failed := atomic.LoadInt64(&p.failedTasks)
+				p.setWorkers(tuner.next(adaptiveSample{
+					bandwidth: sentBytes - prevSentBytes,
+					done:      done - prevDone,
+					failed:    failed - prevFailed,
+				}))
+				prevSentBytes, prevDone, prevFailed = sentBytes, done, failed
+			}
+		}
+	}()
+}
+
+// adaptiveSample - transfers measured during a monitor period.
+type adaptiveSample struct {
+	bandwidth int64
+	done      int64
+	failed    int64
+}
+
+// adaptiveTuner - hill climbing of the number of workers, workers are
+// added as long as the bandwidth grows and removed once it drops, until
+// it drops again. Failures above adaptiveMaxErrorRate halve the workers.
+type adaptiveTuner struct {
+	workers       int
+	direction     int
+	prevBandwidth int64
+}
+
+func newAdaptiveTuner(workers int) *adaptiveTuner {
+	return &adaptiveTuner{workers: workers, direction: 1}
+}
+
+// next returns the number of workers for the next monitor period.
+func (a *adaptiveTuner) next(sample adaptiveSample) int {
+	switch {
+	case sample.failed > 0 && sample.failed*100 >= sample.done*adaptiveMaxErrorRate:
+		// Back off, then climb again from the new bandwidth.
+		a.workers /= 2
+		a.direction = 1
+		a.prevBandwidth = 0
+	case sample.bandwidth == 0 && sample.done == 0:
+		// Idle or paused, nothing to learn from.
+		return a.workers
+	case sample.bandwidth*100 > a.prevBandwidth*(100+adaptiveBandwidthNoise):
+		a.step()
+		a.prevBandwidth = sample.bandwidth
+	case sample.bandwidth*100 < a.prevBandwidth*(100-adaptiveBandwidthNoise):
+		a.direction = -a.direction
+		a.step()
+		a.prevBandwidth = sample.bandwidth
+	}
+	if a.workers < 1 {
+		a.workers = 1
+	}
+	if a.workers > maxParallelWorkers {
+		a.workers = maxParallelWorkers
+	}
+	return a.workers
+}
+
+// step adds defaultWorkerFactor workers or removes one.
+func (a *adaptiveTuner) step() {
+	if a.direction > 0 {
+		a.workers += defaultWorkerFactor
+	} else {
+		a.workers--
+	}
+}
+
+// workers returns the current number of workers.
+func (p *ParallelManager) workers() int {
+	return int(atomic.LoadUint32(&p.workersNum))
+}
+
 // Wait for all workers to finish tasks before shutting down Parallel
 func (p *ParallelManager) wait() {
 	p.wg.Wait()
@@ -157,3 +336,39 @@ func newParallelManager(resultCh chan URLs) (*ParallelManager, chan func() URLs)
 
 	return p, p.queueCh
 }
+
+// newAdaptiveParallelManager starts adaptiveStartWorkers workers, then
+// adds and removes workers following the transfer speed of progress, or
+// of the bytes read through the manager when progress is nil.
+func newAdaptiveParallelManager(resultCh chan URLs, progress Progress) (*ParallelManager, chan func() URLs) {
+	p := &ParallelManager{
+		wg:            &sync.WaitGroup{},
+		workersNum:    0,
+		stopMonitorCh: make(chan struct{}),
+		queueCh:       make(chan func() URLs),
+		resultCh:      resultCh,
+		progress:      progress,
+	}
+	p.setWorkers(adaptiveStartWorkers)
+	p.monitorAdaptive()
+	return p, p.queueCh
+}
+
+// newParallelManagerWithWorkers starts a fixed number of workers, the
+// number of workers follows the transfer speed when workers is zero.
+func newParallelManagerWithWorkers(resultCh chan URLs, workers int) (*ParallelManager, chan func() URLs) {
+	if workers <= 0 {
+		return newParallelManager(resultCh)
+	}
+	p := &ParallelManager{
+		wg:            &sync.WaitGroup{},
+		workersNum:    0,
+		stopMonitorCh: make(chan struct{}),
+		queueCh:       make(chan func() URLs),
+		resultCh:      resultCh,
+	}
+	for i := 0; i < workers; i++ {
+		p.addWorker()
+	}
+	return p, p.queueCh
+}
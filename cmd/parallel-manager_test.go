@@ -0,0 +1,97 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestAdaptiveTuner(t *testing.T) {
+	tuner := newAdaptiveTuner(adaptiveStartWorkers)
+	testCases := []struct {
+		sample  adaptiveSample
+		workers int
+	}{
+		// Climb while the bandwidth grows.
+		{adaptiveSample{bandwidth: 100, done: 10}, 4},
+		{adaptiveSample{bandwidth: 200, done: 10}, 6},
+		// Noise holds the workers.
+		{adaptiveSample{bandwidth: 204, done: 10}, 6},
+		// A drop turns around.
+		{adaptiveSample{bandwidth: 150, done: 10}, 5},
+		{adaptiveSample{bandwidth: 180, done: 10}, 4},
+		{adaptiveSample{bandwidth: 120, done: 10}, 6},
+		// Idle periods are ignored.
+		{adaptiveSample{}, 6},
+		// Failures halve the workers.
+		{adaptiveSample{bandwidth: 120, done: 10, failed: 1}, 3},
+		{adaptiveSample{bandwidth: 60, done: 10, failed: 5}, 1},
+		{adaptiveSample{bandwidth: 60, done: 10, failed: 5}, 1},
+		// Then climb again.
+		{adaptiveSample{bandwidth: 60, done: 10}, 3},
+	}
+	for i, testCase := range testCases {
+		if workers := tuner.next(testCase.sample); workers != testCase.workers {
+			t.Errorf("Test %d: expected %d workers, got %d", i+1, testCase.workers, workers)
+		}
+	}
+}
+
+func TestParallelManagerSetWorkers(t *testing.T) {
+	resultCh := make(chan URLs)
+	p := &ParallelManager{
+		wg:            &sync.WaitGroup{},
+		stopMonitorCh: make(chan struct{}),
+		queueCh:       make(chan func() URLs),
+		resultCh:      resultCh,
+	}
+	p.setWorkers(4)
+	if workers := p.workers(); workers != 4 {
+		t.Fatalf("expected 4 workers, got %d", workers)
+	}
+
+	// Extra workers quit after their task.
+	p.setWorkers(1)
+	for i := 0; i < 4; i++ {
+		i := i
+		p.queueCh <- func() URLs {
+			if i%2 == 0 {
+				return URLs{Error: probe.NewError(errors.New("failed"))}
+			}
+			return URLs{}
+		}
+		<-resultCh
+	}
+	// The last worker sent its result before quitting.
+	for deadline := time.Now().Add(time.Second); p.workers() != 1 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if workers := p.workers(); workers != 1 {
+		t.Errorf("expected 1 worker, got %d", workers)
+	}
+	if done, failed := atomic.LoadInt64(&p.doneTasks), atomic.LoadInt64(&p.failedTasks); done != 4 || failed != 2 {
+		t.Errorf("expected 4 done and 2 failed tasks, got %d and %d", done, failed)
+	}
+	close(p.queueCh)
+	p.wait()
+}
@@ -0,0 +1,377 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-colorable"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// interactiveFlag picks the objects of an operation in a fuzzy finder.
+var interactiveFlag = cli.BoolFlag{
+	Name:  "interactive",
+	Usage: "pick the objects from a fuzzy finder before the operation runs",
+}
+
+// checkInteractiveFlag - the fuzzy finder needs a terminal to draw on
+// and read keys from.
+func checkInteractiveFlag(ctx *cli.Context) {
+	if !ctx.Bool("interactive") {
+		return
+	}
+	if ctx.Bool("stdin") {
+		fatalIf(errInvalidArgument().Trace(), "‘--interactive’ and ‘--stdin’ are mutually exclusive.")
+	}
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) || !terminal.IsTerminal(int(os.Stderr.Fd())) {
+		fatalIf(errInvalidArgument().Trace(), "‘--interactive’ needs a terminal to pick the objects from.")
+	}
+}
+
+// pickerKey - an action of the fuzzy finder bound to a key.
+type pickerKey int
+
+const (
+	pickerRune pickerKey = iota
+	pickerUp
+	pickerDown
+	pickerPageUp
+	pickerPageDown
+	pickerToggle
+	pickerToggleAll
+	pickerBackspace
+	pickerClear
+	pickerAccept
+	pickerAbort
+)
+
+// pickerInput - a key read from the terminal, r is the typed character
+// of pickerRune.
+type pickerInput struct {
+	key pickerKey
+	r   rune
+}
+
+// parsePickerInput - decode the keys of a read from a raw terminal,
+// unknown control characters and escape sequences are dropped.
+func parsePickerInput(b []byte) []pickerInput {
+	var inputs []pickerInput
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			sequences := map[string]pickerKey{
+				"\x1b[A": pickerUp, "\x1bOA": pickerUp,
+				"\x1b[B": pickerDown, "\x1bOB": pickerDown,
+				"\x1b[5~": pickerPageUp, "\x1b[6~": pickerPageDown,
+			}
+			if len(b) == 1 {
+				// A lone escape.
+				return append(inputs, pickerInput{key: pickerAbort})
+			}
+			n := 2
+			if b[1] == '[' || b[1] == 'O' {
+				// Skip to the final byte of the sequence.
+				for n < len(b) && (b[n] < 0x40 || b[n] > 0x7e) {
+					n++
+				}
+				if n < len(b) {
+					n++
+				}
+			}
+			if key, ok := sequences[string(b[:n])]; ok {
+				inputs = append(inputs, pickerInput{key: key})
+			}
+			b = b[n:]
+			continue
+		case c == '\r' || c == '\n':
+			inputs = append(inputs, pickerInput{key: pickerAccept})
+		case c == 0x03 || c == 0x04:
+			inputs = append(inputs, pickerInput{key: pickerAbort})
+		case c == '\t':
+			inputs = append(inputs, pickerInput{key: pickerToggle})
+		case c == 0x01:
+			inputs = append(inputs, pickerInput{key: pickerToggleAll})
+		case c == 0x10:
+			inputs = append(inputs, pickerInput{key: pickerUp})
+		case c == 0x0e:
+			inputs = append(inputs, pickerInput{key: pickerDown})
+		case c == 0x7f || c == 0x08:
+			inputs = append(inputs, pickerInput{key: pickerBackspace})
+		case c == 0x15:
+			inputs = append(inputs, pickerInput{key: pickerClear})
+		case c >= 0x20:
+			r, n := utf8.DecodeRune(b)
+			if r != utf8.RuneError {
+				inputs = append(inputs, pickerInput{key: pickerRune, r: r})
+			}
+			b = b[n:]
+			continue
+		}
+		b = b[1:]
+	}
+	return inputs
+}
+
+// fuzzyMatch - match the characters of the query in order in the item,
+// case-insensitively unless the query has upper case characters. Matches
+// at the start of names and of consecutive characters score higher.
+func fuzzyMatch(query []rune, item string) (int, bool) {
+	if len(query) == 0 {
+		return 0, true
+	}
+	smartCase := true
+	for _, r := range query {
+		if unicode.IsUpper(r) {
+			smartCase = false
+		}
+	}
+	score, q, last := 0, 0, -2
+	previous := '/'
+	for i, r := range []rune(item) {
+		if q == len(query) {
+			break
+		}
+		candidate := r
+		if smartCase {
+			candidate = unicode.ToLower(r)
+		}
+		if candidate == query[q] {
+			score++
+			if last == i-1 {
+				score += 4
+			}
+			if strings.ContainsRune("/._- ", previous) {
+				score += 2
+			}
+			last = i
+			q++
+		}
+		previous = r
+	}
+	if q < len(query) {
+		return 0, false
+	}
+	return score, true
+}
+
+// objectPicker - state of the fuzzy finder over a list of items.
+type objectPicker struct {
+	title    string
+	items    []string
+	selected []bool
+	query    []rune
+	// Indexes of the items matching the query, best first.
+	matches []int
+	cursor  int
+	offset  int
+}
+
+func newObjectPicker(title string, items []string) *objectPicker {
+	p := &objectPicker{title: title, items: items, selected: make([]bool, len(items))}
+	p.filter()
+	return p
+}
+
+// filter - rank the items matching the query, the list order is kept
+// between items of the same score.
+func (p *objectPicker) filter() {
+	scores := make(map[int]int)
+	p.matches = p.matches[:0]
+	for i, item := range p.items {
+		if score, ok := fuzzyMatch(p.query, item); ok {
+			scores[i] = score
+			p.matches = append(p.matches, i)
+		}
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool { return scores[p.matches[i]] > scores[p.matches[j]] })
+	p.cursor, p.offset = 0, 0
+}
+
+// move - move the cursor within the matches.
+func (p *objectPicker) move(delta int) {
+	p.cursor += delta
+	if p.cursor >= len(p.matches) {
+		p.cursor = len(p.matches) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+// handle - apply a key, returns true once the picker is done.
+func (p *objectPicker) handle(input pickerInput, pageSize int) (done, aborted bool) {
+	switch input.key {
+	case pickerRune:
+		p.query = append(p.query, input.r)
+		p.filter()
+	case pickerBackspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	case pickerClear:
+		p.query = p.query[:0]
+		p.filter()
+	case pickerUp:
+		p.move(-1)
+	case pickerDown:
+		p.move(1)
+	case pickerPageUp:
+		p.move(-pageSize)
+	case pickerPageDown:
+		p.move(pageSize)
+	case pickerToggle:
+		if len(p.matches) > 0 {
+			i := p.matches[p.cursor]
+			p.selected[i] = !p.selected[i]
+			p.move(1)
+		}
+	case pickerToggleAll:
+		// Select all matches, or clear them if all are selected.
+		all := true
+		for _, i := range p.matches {
+			all = all && p.selected[i]
+		}
+		for _, i := range p.matches {
+			p.selected[i] = !all
+		}
+	case pickerAccept:
+		// Without a selection the item under the cursor is picked,
+		// nothing is picked without matches.
+		if len(p.picked()) == 0 {
+			if len(p.matches) == 0 {
+				return false, false
+			}
+			p.selected[p.matches[p.cursor]] = true
+		}
+		return true, false
+	case pickerAbort:
+		return true, true
+	}
+	return false, false
+}
+
+// picked - indexes of the selected items in list order.
+func (p *objectPicker) picked() []int {
+	var picked []int
+	for i, selected := range p.selected {
+		if selected {
+			picked = append(picked, i)
+		}
+	}
+	return picked
+}
+
+// render - draw the picker into a screen of width and height.
+func (p *objectPicker) render(width, height int) string {
+	rows := height - 4
+	if rows < 1 {
+		rows = 1
+	}
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+
+	lines := []string{
+		color.New(color.Bold).Sprint(lineTrunc(fmt.Sprintf(" %s (%d/%d matching, %d selected)",
+			p.title, len(p.matches), len(p.items), len(p.picked())), width)),
+		" > " + string(p.query),
+	}
+	for row := p.offset; row < len(p.matches) && row < p.offset+rows; row++ {
+		i := p.matches[row]
+		mark := "[ ]"
+		if p.selected[i] {
+			mark = color.GreenString("[x]")
+		}
+		line := lineTrunc(p.items[i], width-7)
+		if row == p.cursor {
+			line = color.New(color.Bold, color.FgCyan).Sprint("> ") + mark + " " + color.New(color.Bold).Sprint(line)
+		} else {
+			line = "  " + mark + " " + line
+		}
+		lines = append(lines, " "+line)
+	}
+	for len(lines) < rows+2 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, "", color.YellowString(" [tab] select  [ctrl-a] select all  [enter] confirm  [esc] abort"))
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteString("\x1b[K\r\n")
+	}
+	b.WriteString("\x1b[J")
+	return b.String()
+}
+
+// pickObjects - let the user pick items in a fuzzy finder drawn on the
+// alternate screen of stderr, false if the user aborted or picked
+// nothing.
+func pickObjects(title string, items []string) ([]int, bool) {
+	if len(items) == 0 {
+		return nil, false
+	}
+	oldState, e := terminal.MakeRaw(int(os.Stdin.Fd()))
+	if e != nil {
+		fatalIf(probe.NewError(e), "Unable to read keys from the terminal.")
+	}
+	var output io.Writer = colorable.NewColorableStderr()
+	// Switch to alternate screen, restored with the cursor on return.
+	fmt.Fprint(output, "\x1b[?1049h")
+	defer func() {
+		fmt.Fprint(output, "\x1b[?1049l")
+		terminal.Restore(int(os.Stdin.Fd()), oldState)
+	}()
+
+	p := newObjectPicker(title, items)
+	buf := make([]byte, 64)
+	for {
+		width, height := globalTermWidth, 24
+		if w, h, e := terminal.GetSize(int(os.Stderr.Fd())); e == nil {
+			width, height = w, h
+		}
+		if width < 20 {
+			width = 20
+		}
+		fmt.Fprint(output, p.render(width, height))
+
+		n, e := os.Stdin.Read(buf)
+		if e != nil {
+			return nil, false
+		}
+		for _, input := range parsePickerInput(buf[:n]) {
+			if done, aborted := p.handle(input, height-4); done {
+				return p.picked(), !aborted
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePickerInput(t *testing.T) {
+	inputs := parsePickerInput([]byte("aé\x1b[A\x1b[B\x1b[1;5C\t\x01\x7f\x15\r\x03\x1b"))
+	expected := []pickerInput{
+		{key: pickerRune, r: 'a'}, {key: pickerRune, r: 'é'}, {key: pickerUp}, {key: pickerDown},
+		{key: pickerToggle}, {key: pickerToggleAll}, {key: pickerBackspace}, {key: pickerClear},
+		{key: pickerAccept}, {key: pickerAbort}, {key: pickerAbort},
+	}
+	if !reflect.DeepEqual(inputs, expected) {
+		t.Fatalf("expected %v, got %v", expected, inputs)
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	testCases := []struct {
+		query, item string
+		match       bool
+	}{
+		{"", "logs/a.txt", true},
+		{"lat", "logs/a.txt", true},
+		{"LAT", "logs/a.txt", false},
+		{"Lat", "Logs/a.txt", true},
+		{"tal", "logs/a.txt", false},
+	}
+	for i, testCase := range testCases {
+		if _, ok := fuzzyMatch([]rune(testCase.query), testCase.item); ok != testCase.match {
+			t.Errorf("Test %d: expected %v for %q in %q", i+1, testCase.match, testCase.query, testCase.item)
+		}
+	}
+
+	// Consecutive matches rank first.
+	p := newObjectPicker("", []string{"x/l-o-g", "x/log", "y/other"})
+	for _, r := range "log" {
+		p.handle(pickerInput{key: pickerRune, r: r}, 10)
+	}
+	if !reflect.DeepEqual(p.matches, []int{1, 0}) {
+		t.Errorf("expected matches [1 0], got %v", p.matches)
+	}
+}
+
+func TestObjectPicker(t *testing.T) {
+	items := []string{"raw/a", "raw/b", "processed/a", "processed/b"}
+	p := newObjectPicker("", items)
+	for _, input := range parsePickerInput([]byte("raw\x01\x15proc\t")) {
+		if done, _ := p.handle(input, 10); done {
+			t.Fatal("unexpected end of picking")
+		}
+	}
+	done, aborted := p.handle(pickerInput{key: pickerAccept}, 10)
+	if !done || aborted {
+		t.Fatalf("expected a confirmed pick, got done %v aborted %v", done, aborted)
+	}
+	if picked := p.picked(); !reflect.DeepEqual(picked, []int{0, 1, 2}) {
+		t.Errorf("expected [0 1 2], got %v", picked)
+	}
+
+	// Without matches nothing is picked.
+	p = newObjectPicker("", items)
+	p.handle(pickerInput{key: pickerRune, r: 'z'}, 10)
+	if done, _ := p.handle(pickerInput{key: pickerAccept}, 10); done {
+		t.Error("expected no pick without matches")
+	}
+	if done, aborted := p.handle(pickerInput{key: pickerAbort}, 10); !done || !aborted {
+		t.Error("expected an aborted pick")
+	}
+}
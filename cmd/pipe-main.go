@@ -17,11 +17,20 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"syscall"
 
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
 )
 
 var (
@@ -30,6 +39,11 @@ var (
 			Name:  "encrypt",
 			Usage: "encrypt objects (using server-side encryption with server managed keys)",
 		},
+		cli.StringFlag{
+			Name:  "size-hint",
+			Usage: "expected size of the stream, e.g. 20GiB, to show the progress and to choose the part size",
+		},
+		aclFlag,
 	}
 )
 
@@ -39,7 +53,7 @@ var pipeCmd = cli.Command{
 	Usage:  "stream STDIN to an object",
 	Action: mainPipe,
 	Before: setGlobalsFromContext,
-	Flags:  append(append(pipeFlags, ioFlags...), globalFlags...),
+	Flags:  append(append(append(append(pipeFlags, contentHeaderFlags...), ioFlags...), uploadFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -65,10 +79,72 @@ EXAMPLES:
 
    4. Stream MySQL database dump to Amazon S3 directly.
       $ mysqldump -u root -p ******* accountsdb | {{.HelpName}} s3/sql-backups/backups/accountsdb-oct-9-2015.sql
+
+   5. Stream a database dump to a gateway limiting the number of parts, uploading parts of 1GiB.
+      $ pg_dump accountsdb | {{.HelpName}} --part-size 1GiB gw/sql-backups/accountsdb.sql
+
+   6. Stream a disk image of about 200GiB with its progress, the part size fits the expected size.
+      $ dd if=/dev/sdb bs=4M | {{.HelpName}} --size-hint 200GiB s3/backups/sdb.img
+
+   7. Publish the output of a command as an object everyone may read.
+      $ df -h | {{.HelpName}} --acl public-read s3/status/disks.txt
+
+   8. Stream a compressed report which browsers decompress, and which caches must not keep after the end of 2019.
+      $ gzip -c report.html | {{.HelpName}} --content-encoding gzip --expires 2019-12-31T23:59 s3/www/report.html
 `,
 }
 
-func pipe(targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+// pipeMessage container for the summary of a stream written by pipe.
+type pipeMessage struct {
+	Status string `json:"status"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// String colorized pipe message.
+func (p pipeMessage) String() string {
+	return console.Colorize("Pipe", fmt.Sprintf("`%s`: %s, SHA-256 %s", p.Target, humanize.IBytes(uint64(p.Size)), p.SHA256))
+}
+
+// JSON jsonified pipe message.
+func (p pipeMessage) JSON() string {
+	p.Status = "success"
+	pipeMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(pipeMessageBytes)
+}
+
+// pipeStream - write the stream of unknown length to the target, objects
+// are uploaded part by part with the canned ACL and content headers, if
+// any.
+func pipeStream(targetURL string, reader io.Reader, sizeHint int64, acl string, headers map[string]string, progress io.Reader, sse encrypt.ServerSide) (int64, *probe.Error) {
+	alias, urlStrFull, _, err := expandAlias(targetURL)
+	if err != nil {
+		return 0, err.Trace(targetURL)
+	}
+	clnt, err := newClientFromAlias(alias, urlStrFull)
+	if err != nil {
+		return 0, err.Trace(targetURL)
+	}
+	metadata := map[string]string{
+		"Content-Type": guessURLContentType(targetURL),
+	}
+	if acl != "" {
+		metadata[amzACL] = acl
+	}
+	for k, v := range headers {
+		metadata[k] = v
+	}
+	if s3Clnt, ok := clnt.(*s3Client); ok {
+		return s3Clnt.PutStream(globalContext, reader, sizeHint, metadata, progress, sse)
+	}
+	// Ignore size, since os.Stat() would not return proper size all the time
+	// for local filesystem for example /proc files.
+	return clnt.Put(globalContext, reader, -1, metadata, progress, sse)
+}
+
+func pipe(targetURL string, encKeyDB map[string][]prefixSSEPair, sizeHint int64, acl string, headers map[string]string) *probe.Error {
 	if targetURL == "" {
 		// When no target is specified, pipe cat's stdin to stdout.
 		return catOut(os.Stdin, -1).Trace()
@@ -76,10 +152,20 @@ func pipe(targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 	alias, _ := url2Alias(targetURL)
 	sseKey := getSSE(targetURL, encKeyDB[alias])
 
-	// Stream from stdin to multiple objects until EOF.
-	// Ignore size, since os.Stat() would not return proper size all the time
-	// for local filesystem for example /proc files.
-	_, err := putTargetStreamWithURL(targetURL, os.Stdin, -1, sseKey)
+	// The progress is only known for an expected size.
+	var pg *progressBar
+	var progress io.Reader
+	if sizeHint > 0 && !globalQuiet && !globalJSON {
+		pg = newProgressBar(sizeHint)
+		progress = pg
+	}
+
+	// Stream from stdin until EOF.
+	hash := sha256.New()
+	n, err := pipeStream(targetURL, io.TeeReader(os.Stdin, hash), sizeHint, acl, headers, progress, sseKey)
+	if pg != nil {
+		pg.Finish()
+	}
 	// TODO: See if this check is necessary.
 	switch e := err.ToGoError().(type) {
 	case *os.PathError:
@@ -88,7 +174,15 @@ func pipe(targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 			return nil
 		}
 	}
-	return err.Trace(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	printMsg(pipeMessage{
+		Target: targetURL,
+		Size:   n,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+	return nil
 }
 
 // check pipe input arguments.
@@ -96,6 +190,7 @@ func checkPipeSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) > 1 {
 		cli.ShowCommandHelpAndExit(ctx, "pipe", 1) // last argument is exit code.
 	}
+	checkACLFlag(ctx)
 }
 
 // mainPipe is the main entry point for pipe command.
@@ -106,14 +201,23 @@ func mainPipe(ctx *cli.Context) error {
 
 	// validate pipe input arguments.
 	checkPipeSyntax(ctx)
+	checkWritableTargets(ctx.Args()...)
+
+	sizeHint := int64(-1)
+	if ctx.String("size-hint") != "" {
+		size, e := humanize.ParseBytes(ctx.String("size-hint"))
+		fatalIf(probe.NewError(e).Trace(ctx.String("size-hint")), "Unable to parse --size-hint.")
+		sizeHint = int64(size)
+	}
+	console.SetColor("Pipe", color.New(color.FgGreen, color.Bold))
 
 	if len(ctx.Args()) == 0 {
-		err = pipe("", nil)
+		err = pipe("", nil, -1, "", nil)
 		fatalIf(err.Trace("stdout"), "Unable to write to one or more targets.")
 	} else {
 		// extract URLs.
 		URLs := ctx.Args()
-		err = pipe(URLs[0], encKeyDB)
+		err = pipe(URLs[0], encKeyDB, sizeHint, ctx.String("acl"), parseContentHeaders(ctx))
 		fatalIf(err.Trace(URLs[0]), "Unable to write to one or more targets.")
 	}
 
@@ -18,10 +18,12 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -29,13 +31,14 @@ import (
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
 )
 
 var (
 	policyFlags = []cli.Flag{
 		cli.BoolFlag{
 			Name:  "recursive, r",
-			Usage: "list recursively",
+			Usage: "list recursively, used by links",
 		},
 	}
 )
@@ -55,16 +58,25 @@ USAGE:
   {{.HelpName}} [FLAGS] FILE TARGET
   {{.HelpName}} [FLAGS] TARGET
   {{.HelpName}} list [FLAGS] TARGET
+  {{.HelpName}} links [FLAGS] TARGET
+  {{.HelpName}} set-json FILE TARGET
+  {{.HelpName}} get-json TARGET
 
 PERMISSION:
   Allowed policies are: [none, download, upload, public].
 {{if .VisibleFlags}}
 FILE:
-  A valid S3 policy JSON filepath.
+  A valid S3 policy JSON filepath, '-' reads the policy from the standard
+  input with set-json.
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}{{end}}
+LINKS:
+  'links' prints the URLs of all objects under TARGET which are readable
+  without credentials, according to the bucket policy, to audit unintended
+  exposure. Objects are listed recursively with '--recursive' only.
+
 EXAMPLES:
    1. Set bucket to "download" on Amazon S3 cloud storage.
       $ {{.HelpName}} download s3/burningman2011
@@ -90,6 +102,12 @@ EXAMPLES:
    8. List public object URLs recursively.
       $ {{.HelpName}} --recursive links s3/shared/
 
+   9. Save the bucket policy JSON of a bucket to a file.
+      $ {{.HelpName}} get-json s3/shared > policy.json
+
+  10. Set the bucket policy of a bucket from a JSON file, after editing it.
+      $ {{.HelpName}} set-json policy.json s3/shared
+
 `,
 }
 
@@ -148,8 +166,10 @@ func (s policyMessage) JSON() string {
 
 // policyLinksMessage is container for policy links command
 type policyLinksMessage struct {
-	Status string `json:"status"`
-	URL    string `json:"url"`
+	Status   string      `json:"status"`
+	URL      string      `json:"url"`
+	Resource string      `json:"resource"`
+	Perms    accessPerms `json:"permission"`
 }
 
 // String colorized access message.
@@ -165,9 +185,41 @@ func (s policyLinksMessage) JSON() string {
 	return string(policyJSONBytes)
 }
 
+// policyJSONMessage is container for the bucket policy JSON of get-json and set-json.
+type policyJSONMessage struct {
+	Operation string          `json:"operation"`
+	Status    string          `json:"status"`
+	Bucket    string          `json:"bucket"`
+	Policy    json.RawMessage `json:"policy,omitempty"`
+}
+
+// String prints the policy as is for get-json, to be saved and edited.
+func (s policyJSONMessage) String() string {
+	if s.Operation == "getJSON" {
+		var buf bytes.Buffer
+		if e := json.Indent(&buf, s.Policy, "", "  "); e != nil {
+			return string(s.Policy)
+		}
+		return buf.String()
+	}
+	return console.Colorize("Policy", "Bucket policy of `"+s.Bucket+"` is set.")
+}
+
+// JSON jsonified policy message.
+func (s policyJSONMessage) JSON() string {
+	policyJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(policyJSONBytes)
+}
+
 // checkPolicySyntax check for incoming syntax.
 func checkPolicySyntax(ctx *cli.Context) {
 	argsLength := len(ctx.Args())
+	// set-json is the only command taking three arguments.
+	if argsLength == 3 && ctx.Args().First() == "set-json" {
+		return
+	}
 	// Always print a help message when we have extra arguments
 	if argsLength > 2 {
 		cli.ShowCommandHelpAndExit(ctx, "policy", 1) // last argument is exit code.
@@ -191,11 +243,14 @@ func checkPolicySyntax(ctx *cli.Context) {
 		if argsLength != 2 {
 			cli.ShowCommandHelpAndExit(ctx, "policy", 1)
 		}
-	case "links":
-		// Always expect an argument after links cmd
+	case "links", "get-json":
+		// Always expect an argument after links and get-json cmd
 		if argsLength != 2 {
 			cli.ShowCommandHelpAndExit(ctx, "policy", 1)
 		}
+	case "set-json":
+		// Always expect a file and a target after set-json cmd
+		cli.ShowCommandHelpAndExit(ctx, "policy", 1)
 
 	default:
 		if argsLength == 2 && filepath.Ext(string(firstArg)) != ".json" {
@@ -236,36 +291,111 @@ func doSetAccess(targetURL string, targetPERMS accessPerms) *probe.Error {
 	return nil
 }
 
-// doSetAccessJSON do set access JSON.
-func doSetAccessJSON(targetURL string, targetPERMS accessPerms) *probe.Error {
-	clnt, err := newClient(targetURL)
-	if err != nil {
-		return err.Trace(targetURL)
-	}
-	fileReader, e := os.Open(string(targetPERMS))
-	if e != nil {
-		fatalIf(probe.NewError(e).Trace(), "Unable to set policy for `"+targetURL+"`.")
+// readPolicyJSON reads and validates a policy JSON file, or the standard
+// input for "-". Syntax errors are reported with their line number.
+func readPolicyJSON(filename string) ([]byte, *probe.Error) {
+	fileReader := io.Reader(os.Stdin)
+	if filename != "-" {
+		file, e := os.Open(filename)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(filename)
+		}
+		defer file.Close()
+		fileReader = file
 	}
-	defer fileReader.Close()
 
 	const maxJSONSize = 120 * 1024 // 120KiB
 	configBuf := make([]byte, maxJSONSize+1)
 
 	n, e := io.ReadFull(fileReader, configBuf)
 	if e == nil {
-		return probe.NewError(bytes.ErrTooLarge).Trace(targetURL)
+		return nil, probe.NewError(bytes.ErrTooLarge).Trace(filename)
 	}
-	if e != io.ErrUnexpectedEOF {
-		return probe.NewError(e).Trace(targetURL)
+	if e != io.ErrUnexpectedEOF && e != io.EOF {
+		return nil, probe.NewError(e).Trace(filename)
 	}
 
 	configBytes := configBuf[:n]
+	var policy map[string]interface{}
+	if e = json.Unmarshal(configBytes, &policy); e != nil {
+		if syntaxErr, ok := e.(*json.SyntaxError); ok {
+			line := bytes.Count(configBytes[:syntaxErr.Offset], []byte("\n")) + 1
+			e = fmt.Errorf("line %d: %s", line, syntaxErr)
+		}
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	return configBytes, nil
+}
+
+// doSetAccessJSON do set access JSON.
+func doSetAccessJSON(targetURL string, targetPERMS accessPerms) *probe.Error {
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	configBytes, err := readPolicyJSON(string(targetPERMS))
+	if err != nil {
+		return err.Trace(targetURL)
+	}
 	if err = clnt.SetAccess(string(configBytes), true); err != nil {
 		return err.Trace(targetURL, string(targetPERMS))
 	}
 	return nil
 }
 
+// isPolicyRejected - true if the server refused a policy as invalid.
+func isPolicyRejected(err *probe.Error) bool {
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "MalformedPolicy", "InvalidPolicyDocument":
+		return true
+	}
+	return false
+}
+
+// Run policy set-json command
+func runPolicySetJSONCmd(args cli.Args) {
+	filename := args.Get(0)
+	targetURL := args.Get(1)
+	if err := doSetAccessJSON(targetURL, accessPerms(filename)); err != nil {
+		switch err.ToGoError().(type) {
+		case APINotImplemented:
+			fatalIf(err.Trace(), "Unable to set policy of a non S3 url `"+targetURL+"`.")
+		}
+		if isPolicyRejected(err) {
+			fatalIf(err.Trace(targetURL), "Policy `"+filename+"` was rejected by the server:")
+		}
+		fatalIf(err.Trace(targetURL), "Unable to set policy `"+filename+"` for `"+targetURL+"`.")
+	}
+	printMsg(policyJSONMessage{
+		Status:    "success",
+		Operation: "setJSON",
+		Bucket:    targetURL,
+	})
+}
+
+// Run policy get-json command
+func runPolicyGetJSONCmd(args cli.Args) {
+	targetURL := args.First()
+	_, policyStr, err := doGetAccess(targetURL)
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case APINotImplemented:
+			fatalIf(err.Trace(), "Unable to get policy of a non S3 url `"+targetURL+"`.")
+		default:
+			fatalIf(err.Trace(targetURL), "Unable to get policy for `"+targetURL+"`.")
+		}
+	}
+	if policyStr == "" {
+		fatalIf(errDummy().Trace(targetURL), "No bucket policy is set for `"+targetURL+"`.")
+	}
+	printMsg(policyJSONMessage{
+		Status:    "success",
+		Operation: "getJSON",
+		Bucket:    targetURL,
+		Policy:    json.RawMessage(policyStr),
+	})
+}
+
 // Convert a minio-go permission to accessPerms type
 func stringToAccessPerm(perm string) accessPerms {
 	var policy accessPerms
@@ -323,13 +453,63 @@ func runPolicyListCmd(args cli.Args) {
 	}
 }
 
+// publicPrefix is a prefix under which objects are anonymously readable.
+type publicPrefix struct {
+	prefix   string
+	resource string
+	perm     accessPerms
+}
+
+// policyLinkPrefixes returns the prefixes under path, in bucket/prefix
+// form, with objects made readable by the policy rules of the bucket.
+// Rules ending with '*' cover all objects under them, other rules a
+// single object.
+func policyLinkPrefixes(rules map[string]string, path string) []publicPrefix {
+	var prefixes []publicPrefix
+	for resource, allow := range rules {
+		// Check if the found policy has read permission
+		perm := stringToAccessPerm(allow)
+		if perm != accessDownload && perm != accessPublic {
+			continue
+		}
+		// Trim the asterisk in policy rules
+		policyPath := strings.TrimSuffix(resource, "*")
+		isPrefix := policyPath != resource
+		switch {
+		case isPrefix && strings.HasPrefix(path, policyPath):
+			// The whole path is public.
+			prefixes = append(prefixes, publicPrefix{prefix: path, resource: resource, perm: perm})
+		case strings.HasPrefix(policyPath, path):
+			prefixes = append(prefixes, publicPrefix{prefix: policyPath, resource: resource, perm: perm})
+		}
+	}
+	// Longer prefixes are listed first, to report the most specific rule.
+	sort.Slice(prefixes, func(i, j int) bool {
+		if len(prefixes[i].prefix) != len(prefixes[j].prefix) {
+			return len(prefixes[i].prefix) > len(prefixes[j].prefix)
+		}
+		if prefixes[i].prefix != prefixes[j].prefix {
+			return prefixes[i].prefix < prefixes[j].prefix
+		}
+		return len(prefixes[i].resource) > len(prefixes[j].resource)
+	})
+	return prefixes
+}
+
 // Run policy links command
 func runPolicyLinksCmd(args cli.Args, recursive bool) {
 	// Get alias/bucket/prefix argument
 	targetURL := args.First()
 
-	// Fetch all policies associated to the passed url
-	policies, err := doGetAccessRules(targetURL)
+	// Extract alias from the passed argument, we'll need it to
+	// construct new pathes to list public objects
+	alias, path := url2Alias(targetURL)
+	bucket := strings.SplitN(path, "/", 2)[0]
+
+	// Fetch all policies of the bucket, a rule on a parent prefix of
+	// the passed url makes objects public as well.
+	bucketURL := alias + "/" + bucket
+	policies, err := doGetAccessRules(bucketURL)
 	if err != nil {
 		switch err.ToGoError().(type) {
 		case APINotImplemented:
@@ -339,33 +519,21 @@ func runPolicyLinksCmd(args cli.Args, recursive bool) {
 		}
 	}
 
-	// Extract alias from the passed argument, we'll need it to
-	// construct new pathes to list public objects
-	alias, path := url2Alias(targetURL)
-
 	isRecursive := recursive
 	isIncomplete := false
 
+	// Objects covered by overlapping rules are printed once.
+	seen := make(map[string]bool)
+
 	// Iterate over policy rules to fetch public urls, then search
 	// for objects under those urls
-	for k, v := range policies {
-		// Trim the asterisk in policy rules
-		policyPath := strings.TrimSuffix(k, "*")
-		// Check if current policy prefix is related to the url passed by the user
-		if !strings.HasPrefix(policyPath, path) {
-			continue
-		}
-		// Check if the found policy has read permission
-		perm := stringToAccessPerm(v)
-		if perm != accessDownload && perm != accessPublic {
-			continue
-		}
+	for _, p := range policyLinkPrefixes(policies, path) {
 		// Construct the new path to search for public objects
-		newURL := alias + "/" + policyPath
+		newURL := alias + "/" + p.prefix
 		clnt, err := newClient(newURL)
 		fatalIf(err.Trace(newURL), "Unable to initialize target `"+targetURL+"`.")
 		// Search for public objects
-		for content := range clnt.List(isRecursive, isIncomplete, DirFirst) {
+		for content := range clnt.List(globalContext, isRecursive, isIncomplete, DirFirst) {
 			if content.Err != nil {
 				errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
 				continue
@@ -379,11 +547,17 @@ func runPolicyLinksCmd(args cli.Args, recursive bool) {
 			u, e := url.Parse(content.URL.String())
 			errorIf(probe.NewError(e), "Unable to parse url `"+content.URL.String()+"`.")
 			publicURL := u.String()
+			if seen[publicURL] {
+				continue
+			}
+			seen[publicURL] = true
 
 			// Construct the message to be displayed to the user
 			msg := policyLinksMessage{
-				Status: "success",
-				URL:    publicURL,
+				Status:   "success",
+				URL:      publicURL,
+				Resource: p.resource,
+				Perms:    p.perm,
 			}
 			// Print the found object
 			printMsg(msg)
@@ -413,10 +587,12 @@ func runPolicyCmd(args cli.Args) {
 		switch probeErr.ToGoError().(type) {
 		case APINotImplemented:
 			fatalIf(probeErr.Trace(), "Unable to "+operation+" policy of a non S3 url `"+targetURL+"`.")
-		default:
-			fatalIf(probeErr.Trace(targetURL, string(perms)),
-				"Unable to "+operation+" policy `"+string(perms)+"` for `"+targetURL+"`.")
 		}
+		if operation == "setJSON" && isPolicyRejected(probeErr) {
+			fatalIf(probeErr.Trace(targetURL), "Policy `"+string(perms)+"` was rejected by the server:")
+		}
+		fatalIf(probeErr.Trace(targetURL, string(perms)),
+			"Unable to "+operation+" policy `"+string(perms)+"` for `"+targetURL+"`.")
 	}
 	policyJSON := map[string]interface{}{}
 	if policyStr != "" {
@@ -448,6 +624,12 @@ func mainPolicy(ctx *cli.Context) error {
 	case "links":
 		// policy links alias/bucket/prefix
 		runPolicyLinksCmd(ctx.Args().Tail(), ctx.Bool("recursive"))
+	case "set-json":
+		// policy set-json file alias/bucket
+		runPolicySetJSONCmd(ctx.Args().Tail())
+	case "get-json":
+		// policy get-json alias/bucket
+		runPolicyGetJSONCmd(ctx.Args().Tail())
 	default:
 		// policy [download|upload|public|] alias/bucket/prefix
 		runPolicyCmd(ctx.Args())
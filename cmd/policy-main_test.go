@@ -0,0 +1,124 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+)
+
+func TestReadPolicyJSON(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-policy-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		policy string
+		err    string
+	}{
+		{`{"Version":"2012-10-17","Statement":[]}`, ""},
+		{"{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": [\n  }\n}", "line 4: invalid character '}'"},
+		{`[]`, "cannot unmarshal array"},
+		{"", "unexpected end of JSON input"},
+		{string(bytes.Repeat([]byte(" "), 120*1024+1)), bytes.ErrTooLarge.Error()},
+	}
+	for i, testCase := range testCases {
+		filename := filepath.Join(dir, "policy.json")
+		if e = ioutil.WriteFile(filename, []byte(testCase.policy), 0600); e != nil {
+			t.Fatal(e)
+		}
+		policy, err := readPolicyJSON(filename)
+		if testCase.err == "" {
+			if err != nil {
+				t.Fatalf("Test %d: unexpected error %s", i+1, err)
+			}
+			if string(policy) != testCase.policy {
+				t.Errorf("Test %d: expected %s, got %s", i+1, testCase.policy, policy)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.ToGoError().Error(), testCase.err) {
+			t.Errorf("Test %d: expected error %q, got %v", i+1, testCase.err, err)
+		}
+	}
+
+	if _, err := readPolicyJSON(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestIsPolicyRejected(t *testing.T) {
+	testCases := []struct {
+		err      *probe.Error
+		rejected bool
+	}{
+		{probe.NewError(minio.ErrorResponse{Code: "MalformedPolicy"}), true},
+		{probe.NewError(minio.ErrorResponse{Code: "InvalidPolicyDocument"}), true},
+		{probe.NewError(minio.ErrorResponse{Code: "AccessDenied"}), false},
+		{errDummy(), false},
+	}
+	for i, testCase := range testCases {
+		if rejected := isPolicyRejected(testCase.err); rejected != testCase.rejected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.rejected, rejected)
+		}
+	}
+}
+
+func TestPolicyLinkPrefixes(t *testing.T) {
+	rules := map[string]string{
+		"shared/*":            "readonly",
+		"shared/photos/*":     "readwrite",
+		"shared/uploads/*":    "writeonly",
+		"shared/docs/faq.txt": "readonly",
+	}
+	testCases := []struct {
+		path     string
+		prefixes []publicPrefix
+	}{
+		{"shared/", []publicPrefix{
+			{"shared/docs/faq.txt", "shared/docs/faq.txt", accessDownload},
+			{"shared/photos/", "shared/photos/*", accessPublic},
+			{"shared/", "shared/*", accessDownload},
+		}},
+		// Rules on parent prefixes cover the path.
+		{"shared/photos/2019/", []publicPrefix{
+			{"shared/photos/2019/", "shared/photos/*", accessPublic},
+			{"shared/photos/2019/", "shared/*", accessDownload},
+		}},
+		{"shared/docs/", []publicPrefix{
+			{"shared/docs/faq.txt", "shared/docs/faq.txt", accessDownload},
+			{"shared/docs/", "shared/*", accessDownload},
+		}},
+		{"private/", nil},
+	}
+	for i, testCase := range testCases {
+		prefixes := policyLinkPrefixes(rules, testCase.path)
+		if !reflect.DeepEqual(prefixes, testCase.prefixes) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.prefixes, prefixes)
+		}
+	}
+}
@@ -26,9 +26,11 @@ type message interface {
 
 // printMsg prints message string or JSON structure depending on the type of output console.
 func printMsg(msg message) {
-	if !globalJSON {
+	if globalFormat != nil {
+		printFormatted(msg)
+	} else if !globalJSON {
 		console.Println(msg.String())
 	} else {
-		console.Println(msg.JSON())
+		console.Println(formatStructured(msg.JSON()))
 	}
 }
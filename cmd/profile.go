@@ -0,0 +1,158 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var profileFlag = cli.StringFlag{
+	Name:  "profile",
+	Usage: "use the flags of a profile of the config as defaults, e.g. backup",
+}
+
+// Commands taking --profile, a profile may have flags of any of them.
+var profileCommands = []string{"cp", "mirror"}
+
+// profileValues - the values of a flag of a profile, a list sets a flag
+// given more than once.
+func profileValues(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case bool:
+		return []string{strconv.FormatBool(v)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("lists have strings only, found %v", item)
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported value %v", value)
+}
+
+// flagNames - all names of the flags of a command, by each name.
+func flagNames(flags []cli.Flag) map[string][]string {
+	names := make(map[string][]string)
+	for _, flag := range flags {
+		var aliases []string
+		for _, name := range strings.Split(flag.GetName(), ",") {
+			aliases = append(aliases, strings.TrimSpace(name))
+		}
+		for _, name := range aliases {
+			names[name] = aliases
+		}
+	}
+	return names
+}
+
+// applyProfile - set the flags of the profile selected with --profile
+// which are not on the command line. Flags of the other commands taking
+// --profile are skipped, so that a profile serves all of them.
+func applyProfile(ctx *cli.Context) *probe.Error {
+	name := ctx.String("profile")
+	if name == "" {
+		return nil
+	}
+	config, err := loadMcConfig()
+	if err != nil {
+		return err.Trace()
+	}
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return probe.NewError(fmt.Errorf("no profile `%s` in %s", name, mustGetMcConfigPath()))
+	}
+
+	commandFlags := flagNames(ctx.Command.Flags)
+	otherFlags := make(map[string][]string)
+	for _, command := range profileCommands {
+		if c := ctx.App.Command(command); c != nil && c.Name != ctx.Command.Name {
+			for flag, aliases := range flagNames(c.Flags) {
+				otherFlags[flag] = aliases
+			}
+		}
+	}
+
+	flags := make([]string, 0, len(profile))
+	for flag := range profile {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	for _, flag := range flags {
+		aliases, ok := commandFlags[flag]
+		if !ok {
+			if _, ok = otherFlags[flag]; ok {
+				continue
+			}
+			return probe.NewError(fmt.Errorf("unknown flag `%s` in profile `%s`", flag, name))
+		}
+		if flag == "profile" {
+			return probe.NewError(fmt.Errorf("profile `%s` cannot select a profile", name))
+		}
+		// The command line overrides the profile.
+		isSet := false
+		for _, alias := range aliases {
+			isSet = isSet || ctx.IsSet(alias)
+		}
+		if isSet {
+			continue
+		}
+		values, e := profileValues(profile[flag])
+		if e != nil {
+			return probe.NewError(fmt.Errorf("flag `%s` of profile `%s`: %s", flag, name, e))
+		}
+		for _, value := range values {
+			if e = ctx.Set(flag, value); e != nil {
+				return probe.NewError(fmt.Errorf("flag `%s` of profile `%s`: %s", flag, name, e))
+			}
+		}
+	}
+	return nil
+}
+
+// setGlobalsFromProfile - apply the profile of the command and then set
+// the global flags, like setGlobalsFromContext for commands taking
+// --profile.
+func setGlobalsFromProfile(ctx *cli.Context) error {
+	fatalIf(applyProfile(ctx), "Unable to use the profile `"+ctx.String("profile")+"`.")
+	return setGlobalsFromContext(ctx)
+}
+
+// validateConfigProfile - errors of the values of the flags of a profile.
+func validateConfigProfile(name string, profile profileConfigV9) []string {
+	var errors []string
+	for flag, value := range profile {
+		if _, e := profileValues(value); e != nil {
+			errors = append(errors, fmt.Sprintf("Invalid value of flag %s of profile %s: %s", flag, name, e))
+		}
+	}
+	sort.Strings(errors)
+	return errors
+}
@@ -0,0 +1,107 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestApplyProfile(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		config := newConfigV9()
+		config.Profiles = map[string]profileConfigV9{
+			"backup": {
+				"parallel":      float64(8),
+				"storage-class": "STANDARD_IA",
+				"exclude":       []interface{}{"*.tmp", "*.log"},
+				"remove":        true,
+			},
+			"typo":    {"parralel": float64(8)},
+			"invalid": {"parallel": map[string]interface{}{}},
+		}
+		return config, nil
+	}
+
+	mirrorFlags := []cli.Flag{
+		cli.IntFlag{Name: "parallel"},
+		cli.StringFlag{Name: "storage-class, sc"},
+		cli.StringSliceFlag{Name: "exclude"},
+		cli.BoolFlag{Name: "remove"},
+		profileFlag,
+	}
+	cpFlags := []cli.Flag{
+		cli.IntFlag{Name: "parallel"},
+		cli.StringFlag{Name: "storage-class, sc"},
+		profileFlag,
+	}
+	app := cli.NewApp()
+	app.Commands = []cli.Command{{Name: "cp", Flags: cpFlags}, {Name: "mirror", Flags: mirrorFlags}}
+
+	testCases := []struct {
+		command  string
+		args     []string
+		success  bool
+		parallel int
+		sc       string
+		exclude  []string
+	}{
+		{"mirror", []string{"--profile", "backup"}, true, 8, "STANDARD_IA", []string{"*.tmp", "*.log"}},
+		// The command line overrides the profile, also with aliases
+		// which cli copies to the other names of a flag.
+		{"mirror", []string{"--profile", "backup", "--parallel", "2", "--storage-class", "GLACIER"}, true, 2, "GLACIER", []string{"*.tmp", "*.log"}},
+		{"mirror", []string{"--profile", "backup", "--sc", "GLACIER"}, true, 8, "", []string{"*.tmp", "*.log"}},
+		// Flags of mirror are skipped by cp.
+		{"cp", []string{"--profile", "backup"}, true, 8, "STANDARD_IA", nil},
+		{"cp", []string{}, true, 0, "", nil},
+		{"cp", []string{"--profile", "missing"}, false, 0, "", nil},
+		{"cp", []string{"--profile", "typo"}, false, 0, "", nil},
+		{"cp", []string{"--profile", "invalid"}, false, 0, "", nil},
+	}
+	for i, testCase := range testCases {
+		command := app.Command(testCase.command)
+		set := flag.NewFlagSet(testCase.command, flag.ContinueOnError)
+		for _, f := range command.Flags {
+			f.Apply(set)
+		}
+		if e := set.Parse(testCase.args); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		ctx := cli.NewContext(app, set, nil)
+		ctx.Command = *command
+
+		err := applyProfile(ctx)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if err != nil {
+			continue
+		}
+		if ctx.Int("parallel") != testCase.parallel || ctx.String("storage-class") != testCase.sc {
+			t.Errorf("Test %d: expected %d and %s, got %d and %s", i+1, testCase.parallel, testCase.sc, ctx.Int("parallel"), ctx.String("storage-class"))
+		}
+		if testCase.command == "mirror" && !reflect.DeepEqual(ctx.StringSlice("exclude"), testCase.exclude) {
+			t.Errorf("Test %d: expected excludes %v, got %v", i+1, testCase.exclude, ctx.StringSlice("exclude"))
+		}
+	}
+}
@@ -17,19 +17,118 @@
 package cmd
 
 import (
+	"fmt"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cheggaaa/pb"
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 
 	"github.com/minio/mc/pkg/console"
 )
 
+const (
+	// Window of the rolling average transfer speed.
+	speedWindow = 10 * time.Second
+
+	// Number of seconds of transfer speed history shown.
+	speedHistoryLen = 10
+)
+
 // progress extender.
 type progressBar struct {
 	*pb.ProgressBar
+
+	// Transfer speed of the last seconds, shown on the status line.
+	history *speedHistory
+
+	// Number of parallel workers, nil if not known.
+	workers func() int
+}
+
+// speedSample - bytes transferred so far at a point in time.
+type speedSample struct {
+	at    time.Time
+	bytes int64
+}
+
+// speedHistory - keeps the samples of the rolling average transfer speed
+// and the speed of each of the last seconds.
+type speedHistory struct {
+	mutex   sync.Mutex
+	samples []speedSample
+	second  speedSample
+	speeds  []float64
+}
+
+// add - add the bytes transferred so far at the given time.
+func (h *speedHistory) add(at time.Time, bytes int64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.samples = append(h.samples, speedSample{at, bytes})
+	for len(h.samples) > 2 && at.Sub(h.samples[1].at) >= speedWindow {
+		h.samples = h.samples[1:]
+	}
+
+	if h.second.at.IsZero() {
+		h.second = speedSample{at, bytes}
+	}
+	if elapsed := at.Sub(h.second.at); elapsed >= time.Second {
+		h.speeds = append(h.speeds, float64(bytes-h.second.bytes)/elapsed.Seconds())
+		if len(h.speeds) > speedHistoryLen {
+			h.speeds = h.speeds[1:]
+		}
+		h.second = speedSample{at, bytes}
+	}
+}
+
+// speed - rolling average transfer speed in bytes per second.
+func (h *speedHistory) speed() float64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.samples) < 2 {
+		return 0
+	}
+	first, last := h.samples[0], h.samples[len(h.samples)-1]
+	elapsed := last.at.Sub(first.at)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(last.bytes-first.bytes) / elapsed.Seconds()
+}
+
+// sparkline - the speed of the last seconds as a line of bars, empty
+// on terminals without unicode block characters.
+func (h *speedHistory) sparkline() string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var bars []rune
+	switch runtime.GOOS {
+	case "linux", "darwin":
+		bars = []rune("▁▂▃▄▅▆▇█")
+	default:
+		return ""
+	}
+	var max float64
+	for _, speed := range h.speeds {
+		if speed > max {
+			max = speed
+		}
+	}
+	line := make([]rune, len(h.speeds))
+	for i, speed := range h.speeds {
+		line[i] = bars[0]
+		if max > 0 {
+			line[i] = bars[int(speed/max*float64(len(bars)-1))]
+		}
+	}
+	return string(line)
 }
 
 // newProgressBar - instantiate a progress bar.
@@ -37,10 +136,9 @@ func newProgressBar(total int64) *progressBar {
 	// Progress bar speific theme customization.
 	console.SetColor("Bar", color.New(color.FgGreen, color.Bold))
 
-	pgbar := progressBar{}
-
 	// get the new original progress bar.
 	bar := pb.New64(total)
+	pgbar := progressBar{ProgressBar: bar, history: &speedHistory{}}
 
 	// Set new human friendly print units.
 	bar.SetUnits(pb.U_BYTES)
@@ -54,9 +152,11 @@ func newProgressBar(total int64) *progressBar {
 	// Show current speed is true.
 	bar.ShowSpeed = true
 
-	// Custom callback with colorized bar.
+	// Custom callback with colorized bar, followed by the status line.
+	// The cursor is moved back to the bar to redraw both lines.
 	bar.Callback = func(s string) {
-		console.Print(console.Colorize("Bar", "\r"+s))
+		pgbar.history.add(time.Now(), bar.Get())
+		console.Print(console.Colorize("Bar", "\r"+s) + "\x1b[K\n" + pgbar.statusLine() + "\x1b[K\x1b[A\r")
 	}
 
 	// Use different unicodes for Linux, OS X and Windows.
@@ -77,13 +177,47 @@ func newProgressBar(total int64) *progressBar {
 		bar.Start()
 	}
 
-	// Copy for future
-	pgbar.ProgressBar = bar
-
 	// Return new progress bar here.
 	return &pgbar
 }
 
+// statusLine - the transfer ETA and speed of the last seconds, and the
+// number of parallel workers.
+func (p *progressBar) statusLine() string {
+	speed := p.history.speed()
+	eta := "--"
+	if remaining := p.ProgressBar.Total - p.ProgressBar.Get(); remaining > 0 && speed > 0 {
+		eta = time.Duration(float64(remaining) / speed * float64(time.Second)).Round(time.Second).String()
+	}
+	line := fmt.Sprintf("ETA: %s  Speed: %s/s (%s avg)", eta, humanize.IBytes(uint64(speed)), speedWindow)
+	if sparkline := p.history.sparkline(); sparkline != "" {
+		line += " " + sparkline
+	}
+	if p.workers != nil {
+		line += fmt.Sprintf("  Workers: %d", p.workers())
+	}
+	return line
+}
+
+// SetWorkers - show the number of parallel workers on the status line.
+func (p *progressBar) SetWorkers(workers func() int) *progressBar {
+	p.workers = workers
+	return p
+}
+
+// Erase - erase the progress bar and its status line, to print a
+// message in their place.
+func (p *progressBar) Erase() {
+	console.Print("\r\x1b[2K\n\x1b[2K\x1b[A\r")
+}
+
+// Finish - finish the progress bar, the cursor is moved below the
+// status line.
+func (p *progressBar) Finish() {
+	p.ProgressBar.Finish()
+	console.Print("\n\n")
+}
+
 // Set caption.
 func (p *progressBar) SetCaption(caption string) *progressBar {
 	caption = fixateBarCaption(caption, getFixedWidth(p.ProgressBar.GetWidth(), 18))
@@ -0,0 +1,68 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSpeedHistory(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		// Bytes transferred so far, sampled every half second.
+		bytes  []int64
+		speed  float64
+		speeds int
+	}{
+		{nil, 0, 0},
+		{[]int64{0}, 0, 0},
+		{[]int64{0, 512}, 1024, 0},
+		{[]int64{0, 512, 1024, 1536, 2048}, 1024, 2},
+		// Only the last 10 seconds are averaged.
+		{[]int64{0, 10240, 10240, 10240, 10240, 10240, 10240, 10240, 10240, 10240,
+			10240, 10240, 10240, 10240, 10240, 10240, 10240, 10240, 10240, 10240,
+			10240, 10240, 10240, 10240}, 0, 10},
+	}
+	for i, testCase := range testCases {
+		history := &speedHistory{}
+		for j, bytes := range testCase.bytes {
+			history.add(start.Add(time.Duration(j)*time.Second/2), bytes)
+		}
+		if speed := history.speed(); speed != testCase.speed {
+			t.Errorf("Test %d: expected speed %v, got %v", i+1, testCase.speed, speed)
+		}
+		if len(history.speeds) != testCase.speeds {
+			t.Errorf("Test %d: expected %d seconds of history, got %d", i+1, testCase.speeds, len(history.speeds))
+		}
+	}
+}
+
+func TestSpeedHistorySparkline(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("no sparkline on " + runtime.GOOS)
+	}
+	history := &speedHistory{speeds: []float64{0, 1024, 2048, 4096, 8192}}
+	if line := history.sparkline(); line != "▁▁▂▄█" {
+		t.Errorf("unexpected sparkline %q", line)
+	}
+	history = &speedHistory{speeds: []float64{0, 0}}
+	if line := history.sparkline(); line != "▁▁" {
+		t.Errorf("unexpected sparkline %q", line)
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var publishFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "remove",
+		Usage: "remove objects of the target which are no longer in the folder",
+	},
+	cli.StringFlag{
+		Name:  "cache-control",
+		Value: "public, max-age=3600",
+		Usage: "Cache-Control header of the files other than HTML pages",
+	},
+	cli.StringFlag{
+		Name:  "html-cache-control",
+		Value: "no-cache",
+		Usage: "Cache-Control header of HTML pages, revalidated by default so that updates are served at once",
+	},
+	cli.StringFlag{
+		Name:  "index",
+		Usage: "configure the website of the bucket to serve DOCUMENT for folders, e.g. index.html",
+	},
+	cli.StringFlag{
+		Name:  "error",
+		Usage: "configure the website of the bucket to serve DOCUMENT for missing pages, e.g. 404.html",
+	},
+	cli.BoolFlag{
+		Name:  "private",
+		Usage: "keep the objects private instead of setting a download policy on the target",
+	},
+	cli.StringFlag{
+		Name:  "region",
+		Value: "us-east-1",
+		Usage: "region of the bucket if it does not exist and is created",
+	},
+}
+
+// Publish a local folder as a static website.
+var publishCmd = cli.Command{
+	Name:   "publish",
+	Usage:  "publish a folder as a static website",
+	Action: mainPublish,
+	Before: setGlobalsFromMutatingContext,
+	Flags:  append(append(publishFlags, uploadFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FOLDER TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Mirror a local folder to a bucket or a folder of a bucket, creating the
+  bucket if needed. Files are uploaded with the content type of their
+  extension and a Cache-Control header, HTML pages with their own. Anyone
+  may read the published objects, their prefix gets a download policy.
+
+  The website of the bucket is configured with --index and --error, which
+  AWS S3 serves from its website endpoint. Servers without websites, such
+  as MinIO, refuse it and are served through a proxy instead.
+
+EXAMPLES:
+  1. Publish a built site to a bucket which anyone may read.
+     $ {{.HelpName}} public/ s3/www
+
+  2. Publish a site with an index and an error page, removing the pages no longer in the folder.
+     $ {{.HelpName}} --index index.html --error 404.html --remove public/ s3/www
+
+  3. Publish documentation below a folder of a bucket, letting browsers cache assets for a day.
+     $ {{.HelpName}} --cache-control 'public, max-age=86400' docs/_build/html/ s3/www/docs/
+`,
+}
+
+// publishMessage container for the summary of a published folder.
+type publishMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	URL    string `json:"url"`
+	Policy string `json:"policy"`
+	Index  string `json:"index,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// String colorized publish message.
+func (p publishMessage) String() string {
+	msg := fmt.Sprintf("Published `%s` to `%s`", p.Source, p.Target)
+	if p.Policy == "download" {
+		msg += ", readable by anyone at " + p.URL
+	}
+	if p.Index != "" {
+		msg += fmt.Sprintf(", serving `%s` for folders", p.Index)
+	}
+	if p.Error != "" {
+		msg += fmt.Sprintf(" and `%s` for missing pages", p.Error)
+	}
+	return console.Colorize("Publish", msg+".")
+}
+
+// JSON jsonified publish message.
+func (p publishMessage) JSON() string {
+	p.Status = "success"
+	publishMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(publishMessageBytes)
+}
+
+// Extensions of the HTML pages of a site.
+var publishPageExtensions = []string{".html", ".htm"}
+
+// publishHeaders - the content type of a file by its extension, text in
+// UTF-8, and its Cache-Control header, pages have their own.
+func publishHeaders(sourcePath, cacheControl, pageCacheControl string) map[string]string {
+	contentType := guessURLContentType(sourcePath)
+	switch {
+	case strings.HasPrefix(contentType, "text/"), contentType == "application/javascript",
+		contentType == "application/json", strings.HasSuffix(contentType, "+json"), strings.HasSuffix(contentType, "+xml"):
+		contentType += "; charset=utf-8"
+	}
+	headers := map[string]string{"Content-Type": contentType}
+	if cacheControl != "" {
+		headers["Cache-Control"] = cacheControl
+	}
+	ext := strings.ToLower(filepath.Ext(sourcePath))
+	for _, pageExt := range publishPageExtensions {
+		if ext == pageExt {
+			if pageCacheControl != "" {
+				headers["Cache-Control"] = pageCacheControl
+			} else {
+				delete(headers, "Cache-Control")
+			}
+		}
+	}
+	return headers
+}
+
+// setBucketWebsite - serve the index document for folders and the error
+// document, if any, for missing keys from the website of the bucket.
+func (c *s3Client) setBucketWebsite(index, errorKey string) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	region, e := c.api.GetBucketLocation(bucket)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	type errorDocument struct {
+		Key string `xml:"Key"`
+	}
+	config := struct {
+		XMLName       xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ WebsiteConfiguration"`
+		IndexDocument struct {
+			Suffix string `xml:"Suffix"`
+		} `xml:"IndexDocument"`
+		ErrorDocument *errorDocument `xml:"ErrorDocument,omitempty"`
+	}{}
+	config.IndexDocument.Suffix = index
+	if errorKey != "" {
+		config.ErrorDocument = &errorDocument{Key: errorKey}
+	}
+	body, e := xml.Marshal(config)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if err := c.putBucketConfig(bucket, "website", region, nil, body); err != nil {
+		return err.Trace(c.targetURL.String())
+	}
+	return nil
+}
+
+// checkPublishSyntax - validate the arguments of publish, a local folder
+// and a target in a bucket.
+func checkPublishSyntax(ctx *cli.Context) (Client, *s3Client) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "publish", 1) // last argument is exit code.
+	}
+	sourceURL, targetURL := ctx.Args().Get(0), ctx.Args().Get(1)
+
+	srcClnt, err := newClient(sourceURL)
+	fatalIf(err.Trace(sourceURL), "Unable to initialize `"+sourceURL+"`.")
+	if srcClnt.GetURL().Type != fileSystem {
+		fatalIf(errInvalidArgument().Trace(sourceURL), "Unable to publish `"+sourceURL+"`, only local folders can be published.")
+	}
+	st, err := srcClnt.Stat(globalContext, false, false, nil)
+	fatalIf(err.Trace(sourceURL), "Unable to stat `"+sourceURL+"`.")
+	if !st.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(sourceURL), "Unable to publish `"+sourceURL+"`, it is not a folder.")
+	}
+
+	tgtClnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize `"+targetURL+"`.")
+	s3Clnt, ok := tgtClnt.(*s3Client)
+	if !ok {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Unable to publish to `"+targetURL+"`, the target must be on object storage.")
+	}
+	if bucket, _ := s3Clnt.url2BucketAndObject(); bucket == "" {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Target `"+targetURL+"` does not contain bucket name.")
+	}
+
+	index, errorDoc := ctx.String("index"), ctx.String("error")
+	if errorDoc != "" && index == "" {
+		fatalIf(errInvalidArgument().Trace(errorDoc), "`--error` needs `--index`, websites always have an index document.")
+	}
+	if strings.Contains(index, "/") {
+		fatalIf(errInvalidArgument().Trace(index), "Unable to serve `"+index+"` for folders, the index document is a name without `/`.")
+	}
+	return srcClnt, s3Clnt
+}
+
+// mainPublish is the entry point for publish command.
+func mainPublish(ctx *cli.Context) error {
+	srcClnt, tgtClnt := checkPublishSyntax(ctx)
+	sourceURL, targetURL := ctx.Args().Get(0), ctx.Args().Get(1)
+	checkWritableTargets(targetURL)
+
+	console.SetColor("Publish", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+
+	// The bucket is created before anything is configured on it.
+	if runMirrorPreflight(srcClnt, tgtClnt, mirrorPreflightOpts{
+		createTarget: true,
+		region:       ctx.String("region"),
+		isRemove:     ctx.Bool("remove"),
+		isOverwrite:  true,
+	}) {
+		fatalIf(errDummy().Trace(targetURL), "Unable to publish to `"+targetURL+"`.")
+	}
+
+	msg := publishMessage{
+		Source: sourceURL,
+		Target: targetURL,
+		URL:    strings.TrimSuffix(tgtClnt.GetURL().String(), "/") + "/",
+		Policy: "none",
+	}
+	if !ctx.Bool("private") {
+		err := tgtClnt.SetAccess("download", false)
+		fatalIf(err.Trace(targetURL), "Unable to set a download policy on `"+targetURL+"`.")
+		msg.Policy = "download"
+	}
+	if index := ctx.String("index"); index != "" {
+		_, prefix := tgtClnt.url2BucketAndObject()
+		if errorDoc := ctx.String("error"); errorDoc != "" {
+			msg.Error = path.Join(prefix, errorDoc)
+		}
+		err := tgtClnt.setBucketWebsite(index, msg.Error)
+		if err != nil && isNotImplemented(err) {
+			fatalIf(err, "Unable to configure the website of `"+targetURL+"`, the server does not serve websites. Serve the index and error documents with a proxy instead.")
+		}
+		fatalIf(err, "Unable to configure the website of `"+targetURL+"`.")
+		msg.Index = index
+	}
+
+	cacheControl, pageCacheControl := ctx.String("cache-control"), ctx.String("html-cache-control")
+	mj := newMirrorJob(sourceURL, targetURL, false, ctx.Bool("remove"), true, false, false, false, 0,
+		nil, "", "", "", "", nil)
+	mj.objectHeaders = func(sourcePath string) map[string]string {
+		return publishHeaders(sourcePath, cacheControl, pageCacheControl)
+	}
+	// Sites are published again and again, their target is cached.
+	mj.cache = newMirrorCache(targetURL)
+	defer mj.cache.Close()
+
+	mirrorCtx, cancelMirror := context.WithCancel(globalContext)
+	defer cancelMirror()
+	if e := mj.mirror(mirrorCtx, cancelMirror); e != nil {
+		return e
+	}
+	printMsg(msg)
+	return nil
+}
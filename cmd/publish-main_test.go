@@ -0,0 +1,142 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestPublishHeaders(t *testing.T) {
+	testCases := []struct {
+		path         string
+		contentType  string
+		cacheControl string
+	}{
+		{"/site/index.html", "text/html; charset=utf-8", "no-cache"},
+		{"/site/about/INDEX.HTM", "text/html; charset=utf-8", "no-cache"},
+		{"/site/css/main.css", "text/css; charset=utf-8", "public, max-age=3600"},
+		{"/site/js/app.js", "application/javascript; charset=utf-8", "public, max-age=3600"},
+		{"/site/data.json", "application/json; charset=utf-8", "public, max-age=3600"},
+		{"/site/logo.svg", "image/svg+xml; charset=utf-8", "public, max-age=3600"},
+		{"/site/logo.png", "image/png", "public, max-age=3600"},
+		{"/site/fonts/a.woff2", "font/woff2", "public, max-age=3600"},
+	}
+	for i, testCase := range testCases {
+		headers := publishHeaders(testCase.path, "public, max-age=3600", "no-cache")
+		if headers["Content-Type"] != testCase.contentType {
+			t.Errorf("Test %d: expected Content-Type `%s`, got `%s`", i+1, testCase.contentType, headers["Content-Type"])
+		}
+		if headers["Cache-Control"] != testCase.cacheControl {
+			t.Errorf("Test %d: expected Cache-Control `%s`, got `%s`", i+1, testCase.cacheControl, headers["Cache-Control"])
+		}
+	}
+
+	// Pages without a Cache-Control of their own are not cached by header.
+	if headers := publishHeaders("/site/index.html", "public, max-age=3600", ""); headers["Cache-Control"] != "" {
+		t.Errorf("expected no Cache-Control on pages, got `%s`", headers["Cache-Control"])
+	}
+}
+
+// websiteHandler - fake S3 server recording the website configuration.
+type websiteHandler struct {
+	mutex         sync.Mutex
+	notSupported  bool
+	indexDocument string
+	errorDocument string
+}
+
+func (h *websiteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "PUT" && len(query["website"]) > 0:
+		if h.notSupported {
+			w.WriteHeader(http.StatusNotImplemented)
+			w.Write([]byte("<Error><Code>NotImplemented</Code><Message>A header you provided implies functionality that is not implemented</Message></Error>"))
+			return
+		}
+		var config struct {
+			XMLName       xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ WebsiteConfiguration"`
+			IndexDocument struct {
+				Suffix string `xml:"Suffix"`
+			} `xml:"IndexDocument"`
+			ErrorDocument struct {
+				Key string `xml:"Key"`
+			} `xml:"ErrorDocument"`
+		}
+		if r.Header.Get("Content-Md5") == "" || xml.NewDecoder(r.Body).Decode(&config) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.indexDocument, h.errorDocument = config.IndexDocument.Suffix, config.ErrorDocument.Key
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func TestSetBucketWebsite(t *testing.T) {
+	h := &websiteHandler{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		config := newConfigV9()
+		config.Hosts["fake"] = hostConfigV9{
+			URL: server.URL, AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", API: "S3v4", Lookup: "path",
+		}
+		return config, nil
+	}
+
+	clnt, err := newClientFromAlias("fake", server.URL+"/www")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s3Clnt := clnt.(*s3Client)
+
+	testCases := []struct {
+		index, errorKey string
+	}{
+		{"index.html", "docs/404.html"},
+		{"index.htm", ""},
+	}
+	for i, testCase := range testCases {
+		if err = s3Clnt.setBucketWebsite(testCase.index, testCase.errorKey); err != nil {
+			t.Fatalf("Test %d: unexpected error %s", i+1, err)
+		}
+		if h.indexDocument != testCase.index || h.errorDocument != testCase.errorKey {
+			t.Errorf("Test %d: expected index `%s` and error `%s`, got `%s` and `%s`",
+				i+1, testCase.index, testCase.errorKey, h.indexDocument, h.errorDocument)
+		}
+	}
+
+	h.notSupported = true
+	if err = s3Clnt.setBucketWebsite("index.html", ""); err == nil || !isNotImplemented(err) {
+		t.Errorf("expected the website to be not implemented, got %v", err)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
 )
 
 var (
@@ -38,6 +39,7 @@ var (
 			Name:  "dangerous",
 			Usage: "allow site-wide removal of objects",
 		},
+		yesFlag,
 	}
 )
 
@@ -69,6 +71,14 @@ EXAMPLES:
 
    4. Remove all buckets and objects recursively from S3 host
       $ {{.HelpName}} --force --dangerous s3
+
+   5. Remove bucket 'jazz-songs' and all its contents from a script, without asking for confirmation
+      $ {{.HelpName}} --force --yes s3/jazz-songs
+
+NOTE:
+   With '--force' incomplete uploads are aborted and, on versioned buckets,
+   all object versions and delete markers are removed as well. Removal with
+   '--force' asks for confirmation on a terminal unless '--yes' is given.
 `,
 }
 
@@ -91,6 +101,30 @@ func (s removeBucketMessage) JSON() string {
 	return string(removeBucketJSONBytes)
 }
 
+// removeBucketContentsMessage reports removed incomplete uploads and versions.
+type removeBucketContentsMessage struct {
+	Status string `json:"status"`
+	Bucket string `json:"bucket"`
+	Kind   string `json:"kind"`
+	Count  int    `json:"count"`
+}
+
+// String colorized remove bucket contents message.
+func (s removeBucketContentsMessage) String() string {
+	if s.Kind == "incomplete" {
+		return console.Colorize("RemoveBucket", fmt.Sprintf("Aborted incomplete uploads of %d objects in `%s`.", s.Count, s.Bucket))
+	}
+	return console.Colorize("RemoveBucket", fmt.Sprintf("Removed %d object versions and delete markers from `%s`.", s.Count, s.Bucket))
+}
+
+// JSON jsonified remove bucket contents message.
+func (s removeBucketContentsMessage) JSON() string {
+	removeBucketJSONBytes, e := json.Marshal(s)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(removeBucketJSONBytes)
+}
+
 // Validate command line arguments.
 func checkRbSyntax(ctx *cli.Context) {
 	if !ctx.Args().Present() {
@@ -112,19 +146,95 @@ func checkRbSyntax(ctx *cli.Context) {
 	}
 }
 
+// cleanBucket aborts incomplete uploads and removes all versions of a
+// versioned bucket, which are not listed and keep the bucket from being
+// removed otherwise.
+func cleanBucket(clnt *s3Client, bucketURL string) *probe.Error {
+	n, err := clnt.AbortIncompleteUploads()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		printMsg(removeBucketContentsMessage{Status: "success", Bucket: bucketURL, Kind: "incomplete", Count: n})
+	}
+
+	versioning, err := clnt.GetVersioning()
+	if err != nil {
+		switch minio.ToErrorResponse(err.ToGoError()).Code {
+		case "NotImplemented":
+			// Versioning is not supported, nothing to remove.
+			return nil
+		}
+		return err
+	}
+	if versioning == "" {
+		return nil
+	}
+	_, err = clnt.RemoveAllVersions(func(removed int) {
+		if removed > 0 {
+			printMsg(removeBucketContentsMessage{Status: "success", Bucket: bucketURL, Kind: "versions", Count: removed})
+		}
+	})
+	return err
+}
+
+// cleanBuckets cleans the bucket of url, or all buckets of the alias for
+// a site-wide removal.
+func cleanBuckets(clnt *s3Client, url, targetAlias, targetURL string) *probe.Error {
+	bucket, object := clnt.url2BucketAndObject()
+	if object != "" {
+		// Not a bucket removal.
+		return nil
+	}
+	buckets := []string{""}
+	if bucket == "" {
+		bucketsInfo, e := clnt.api.ListBuckets()
+		if e != nil {
+			return probe.NewError(e)
+		}
+		buckets = nil
+		for _, b := range bucketsInfo {
+			buckets = append(buckets, b.Name)
+		}
+	}
+	for _, b := range buckets {
+		bucketURL, bucketTarget := url, targetURL
+		if b != "" {
+			bucketURL, bucketTarget = strings.TrimSuffix(url, "/")+"/"+b, urlJoinPath(targetURL, b)
+		}
+		bucketClnt, err := newClientFromAlias(targetAlias, bucketTarget)
+		if err != nil {
+			return err.Trace(bucketURL)
+		}
+		s3Clnt, ok := bucketClnt.(*s3Client)
+		if !ok {
+			continue
+		}
+		if err = cleanBucket(s3Clnt, bucketURL); err != nil {
+			return err.Trace(bucketURL)
+		}
+	}
+	return nil
+}
+
 // deletes a bucket and all its contents
-func deleteBucket(url string) *probe.Error {
+func deleteBucket(url string, isForce bool) *probe.Error {
 	targetAlias, targetURL, _ := mustExpandAlias(url)
 	clnt, pErr := newClientFromAlias(targetAlias, targetURL)
 	if pErr != nil {
 		return pErr
 	}
+	if s3Clnt, ok := clnt.(*s3Client); ok && isForce {
+		if pErr = cleanBuckets(s3Clnt, url, targetAlias, targetURL); pErr != nil {
+			return pErr
+		}
+	}
 	var isIncomplete bool
 	isRemoveBucket := true
 	contentCh := make(chan *clientContent)
 	errorCh := clnt.Remove(isIncomplete, isRemoveBucket, contentCh)
 
-	for content := range clnt.List(true, false, DirLast) {
+	for content := range clnt.List(globalContext, true, false, DirLast) {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
 			case PathInsufficientPermission:
@@ -201,33 +311,34 @@ func isNamespaceRemoval(url string) bool {
 func mainRemoveBucket(ctx *cli.Context) error {
 	// check 'rb' cli arguments.
 	checkRbSyntax(ctx)
+	checkWritableTargets(ctx.Args()...)
 	isForce := ctx.Bool("force")
 
 	// Additional command specific theme customization.
 	console.SetColor("RemoveBucket", color.New(color.FgGreen, color.Bold))
 
-	var cErr error
+	fs := &failureStatus{}
 	for _, targetURL := range ctx.Args() {
 		// Instantiate client for URL.
 		clnt, err := newClient(targetURL)
 		if err != nil {
 			errorIf(err.Trace(targetURL), "Invalid target `"+targetURL+"`.")
-			cErr = exitStatus(globalErrorExitStatus)
+			fs.fail(err)
 			continue
 		}
-		_, err = clnt.Stat(false, false, nil)
+		_, err = clnt.Stat(globalContext, false, false, nil)
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case BucketNameEmpty:
 			default:
 				errorIf(err.Trace(targetURL), "Unable to validate target `"+targetURL+"`.")
-				cErr = exitStatus(globalErrorExitStatus)
+				fs.fail(err)
 				continue
 
 			}
 		}
 		isEmpty := true
-		for range clnt.List(true, false, DirNone) {
+		for range clnt.List(globalContext, true, false, DirNone) {
 			isEmpty = false
 			break
 		}
@@ -235,8 +346,12 @@ func mainRemoveBucket(ctx *cli.Context) error {
 		if !isForce && !isEmpty {
 			fatalIf(errDummy().Trace(), "`"+targetURL+"` is not empty. Retry this command with ‘--force’ flag if you want to remove `"+targetURL+"` and all its contents")
 		}
+		if isForce && isConfirmationNeeded(ctx) {
+			confirmOrExit(fmt.Sprintf("Remove `%s` and %s in it? This operation is IRREVERSIBLE.",
+				targetURL, describeObjectCount(countObjects(clnt, false))))
+		}
 
-		e := deleteBucket(targetURL)
+		e := deleteBucket(targetURL, isForce)
 		fatalIf(e.Trace(targetURL), "Failed to remove `"+targetURL+"`.")
 
 		if !isNamespaceRemoval(targetURL) {
@@ -244,6 +359,7 @@ func mainRemoveBucket(ctx *cli.Context) error {
 				Bucket: targetURL, Status: "success",
 			})
 		}
+		fs.success()
 	}
-	return cErr
+	return fs.exitError()
 }
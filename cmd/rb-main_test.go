@@ -0,0 +1,141 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+// versionsHandler - fake S3 server of a versioned bucket with incomplete uploads.
+type versionsHandler struct {
+	mutex    sync.Mutex
+	versions []objectVersion // Sorted by key.
+	markers  map[string]bool // Delete markers by version id.
+	uploads  map[string]string
+	pageSize int
+}
+
+func (h *versionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && len(query["versioning"]) > 0:
+		w.Write([]byte("<VersioningConfiguration xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"><Status>Enabled</Status></VersioningConfiguration>"))
+	case r.Method == "GET" && len(query["versions"]) > 0:
+		start := 0
+		if marker := query.Get("key-marker"); marker != "" {
+			for start < len(h.versions) && h.versions[start].Key+"/"+h.versions[start].VersionID <= marker+"/"+query.Get("version-id-marker") {
+				start++
+			}
+		}
+		end := start + h.pageSize
+		if end > len(h.versions) {
+			end = len(h.versions)
+		}
+		response := "<ListVersionsResult>"
+		for _, v := range h.versions[start:end] {
+			tag := "Version"
+			if h.markers[v.VersionID] {
+				tag = "DeleteMarker"
+			}
+			response += fmt.Sprintf("<%s><Key>%s</Key><VersionId>%s</VersionId></%s>", tag, v.Key, v.VersionID, tag)
+		}
+		if end < len(h.versions) {
+			last := h.versions[end-1]
+			response += fmt.Sprintf("<IsTruncated>true</IsTruncated><NextKeyMarker>%s</NextKeyMarker><NextVersionIdMarker>%s</NextVersionIdMarker>", last.Key, last.VersionID)
+		} else {
+			response += "<IsTruncated>false</IsTruncated>"
+		}
+		w.Write([]byte(response + "</ListVersionsResult>"))
+	case r.Method == "DELETE" && len(query["versionId"]) > 0:
+		for i, v := range h.versions {
+			if v.Key == object && v.VersionID == query.Get("versionId") {
+				h.versions = append(h.versions[:i], h.versions[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "GET" && len(query["uploads"]) > 0:
+		var keys []string
+		for key := range h.uploads {
+			if strings.HasPrefix(key, query.Get("prefix")) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		response := "<ListMultipartUploadsResult><Bucket>bucket</Bucket>"
+		for _, key := range keys {
+			response += fmt.Sprintf("<Upload><Key>%s</Key><UploadId>%s</UploadId></Upload>", key, h.uploads[key])
+		}
+		w.Write([]byte(response + "<IsTruncated>false</IsTruncated></ListMultipartUploadsResult>"))
+	case r.Method == "GET" && len(query["uploadId"]) > 0:
+		w.Write([]byte("<ListPartsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated></ListPartsResult>"))
+	case r.Method == "DELETE" && len(query["uploadId"]) > 0:
+		delete(h.uploads, object)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func (s *TestSuite) TestCleanBucket(c *C) {
+	h := &versionsHandler{
+		markers:  map[string]bool{"v3": true},
+		uploads:  map[string]string{"big.iso": "upload1", "dir/huge.tar": "upload2"},
+		pageSize: 2,
+	}
+	for i, key := range []string{"a", "a", "b", "c", "c"} {
+		h.versions = append(h.versions, objectVersion{Key: key, VersionID: fmt.Sprintf("v%d", i+1)})
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3c := clnt.(*s3Client)
+
+	n, err := s3c.AbortIncompleteUploads()
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 2)
+	c.Assert(len(h.uploads), Equals, 0)
+
+	var progress []int
+	n, err = s3c.RemoveAllVersions(func(removed int) {
+		progress = append(progress, removed)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 5)
+	c.Assert(len(h.versions), Equals, 0)
+	c.Assert(progress, DeepEquals, []int{2, 4, 5})
+}
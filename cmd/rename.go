@@ -0,0 +1,201 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// renameFlag substitutes the keys of recursively copied objects.
+var renameFlag = cli.StringSliceFlag{
+	Name:  "rename",
+	Usage: "substitute the keys of copied objects below the target with a sed expression 's/REGEX/REPLACEMENT/[gi]', may be repeated",
+}
+
+// renameRule - a substitution of '--rename'.
+type renameRule struct {
+	re *regexp.Regexp
+	// replacement in the template syntax of regexp.Expand.
+	replacement string
+	global      bool
+}
+
+// splitRenameExpr - split a sed expression on the unescaped delimiter,
+// escaped delimiters are kept escaped.
+func splitRenameExpr(expr string, delimiter byte) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\' && i+1 < len(expr):
+			part.WriteByte(c)
+			part.WriteByte(expr[i+1])
+			i++
+		case c == delimiter:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	return append(parts, part.String())
+}
+
+// renamePattern - a sed regular expression as Go regular expression, an
+// escaped delimiter matches the delimiter itself.
+func renamePattern(pattern string, delimiter byte) string {
+	escaped := `\` + string(delimiter)
+	return strings.Replace(pattern, escaped, regexp.QuoteMeta(string(delimiter)), -1)
+}
+
+// renameReplacement - a sed replacement as regexp.Expand template, '\1'
+// to '\9' are groups and '&' is the match.
+func renameReplacement(replacement string) string {
+	var b strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		switch c := replacement[i]; {
+		case c == '\\' && i+1 < len(replacement):
+			i++
+			if next := replacement[i]; next >= '0' && next <= '9' {
+				b.WriteString("${" + string(next) + "}")
+			} else if next == '$' {
+				b.WriteString("$$")
+			} else {
+				b.WriteByte(next)
+			}
+		case c == '&':
+			b.WriteString("${0}")
+		case c == '$':
+			b.WriteString("$$")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// parseRenameRule - parse a sed substitution 's/REGEX/REPLACEMENT/FLAGS',
+// the delimiter is the character after 's'. Flags are 'g' to replace all
+// matches and 'i' to match case-insensitively.
+func parseRenameRule(expr string) (renameRule, *probe.Error) {
+	if len(expr) < 2 || expr[0] != 's' {
+		return renameRule{}, errInvalidArgument().Trace(expr)
+	}
+	delimiter := expr[1]
+	if delimiter == '\\' || delimiter == '\n' || delimiter >= '0' && delimiter <= '9' ||
+		delimiter >= 'a' && delimiter <= 'z' || delimiter >= 'A' && delimiter <= 'Z' {
+		return renameRule{}, errInvalidArgument().Trace(expr)
+	}
+	parts := splitRenameExpr(expr[2:], delimiter)
+	if len(parts) != 3 || parts[0] == "" {
+		return renameRule{}, errInvalidArgument().Trace(expr)
+	}
+
+	rule := renameRule{replacement: renameReplacement(parts[1])}
+	pattern := renamePattern(parts[0], delimiter)
+	for _, flag := range parts[2] {
+		switch flag {
+		case 'g':
+			rule.global = true
+		case 'i':
+			pattern = "(?i)" + pattern
+		default:
+			return renameRule{}, errInvalidArgument().Trace(expr)
+		}
+	}
+	re, e := regexp.Compile(pattern)
+	if e != nil {
+		return renameRule{}, probe.NewError(e).Trace(expr)
+	}
+	rule.re = re
+	return rule, nil
+}
+
+// apply - substitute the first or with 'g' all matches in a key.
+func (r renameRule) apply(key string) string {
+	if r.global {
+		return r.re.ReplaceAllString(key, r.replacement)
+	}
+	match := r.re.FindStringSubmatchIndex(key)
+	if match == nil {
+		return key
+	}
+	return key[:match[0]] + string(r.re.ExpandString(nil, r.replacement, key, match)) + key[match[1]:]
+}
+
+// setRenameFromContext - validate and set the substitutions of '--rename'.
+func setRenameFromContext(ctx *cli.Context) {
+	globalRename = nil
+	for _, expr := range ctx.StringSlice("rename") {
+		rule, err := parseRenameRule(expr)
+		fatalIf(err, "Unable to parse rename expression `"+expr+"`. Expected `s/REGEX/REPLACEMENT/[gi]`.")
+		globalRename = append(globalRename, rule)
+	}
+}
+
+// renameKey - apply the substitutions of '--rename' in order to a key
+// relative to the target, the leading separators of the key are kept.
+func renameKey(suffix string) string {
+	if len(globalRename) == 0 {
+		return suffix
+	}
+	key := strings.TrimLeft(suffix, "/")
+	for _, rule := range globalRename {
+		key = rule.apply(key)
+	}
+	return suffix[:len(suffix)-len(strings.TrimLeft(suffix, "/"))] + key
+}
+
+// renameSuffix - rename a key relative to the target, keys renamed to
+// nothing or to a folder are refused.
+func renameSuffix(suffix string) (string, *probe.Error) {
+	renamed := renameKey(suffix)
+	if key := strings.TrimLeft(renamed, "/"); len(globalRename) > 0 && (key == "" || strings.HasSuffix(key, "/")) {
+		return "", errInvalidTarget(renamed).Trace(suffix)
+	}
+	return renamed, nil
+}
+
+// renameTargetSuffix - rename the suffix of a source to the suffix of its
+// target. Keys are renamed after local names are unescaped and before
+// they are escaped by '--windows-compat'.
+func renameTargetSuffix(suffix string, isSourceLocal, isTargetLocal bool) (string, *probe.Error) {
+	if isSourceLocal {
+		return renameSuffix(mapWindowsCompatSuffix(suffix, isSourceLocal, isTargetLocal))
+	}
+	renamed, err := renameSuffix(suffix)
+	if err != nil {
+		return "", err
+	}
+	return mapWindowsCompatSuffix(renamed, isSourceLocal, isTargetLocal), nil
+}
+
+// sortByRenamedKey - sort a listing by the renamed keys of its contents,
+// the way difference compares them with the target.
+func sortByRenamedKey(contentCh <-chan *clientContent, listURL, targetURL string, isEscaped bool) <-chan *clientContent {
+	return sortContents(contentCh, func(content *clientContent) string {
+		suffix := strings.TrimPrefix(content.URL.String(), listURL)
+		if isEscaped {
+			suffix = decodeWindowsName(suffix)
+		}
+		return normalizeKey(urlJoinPath(targetURL, renameKey(suffix)))
+	})
+}
@@ -0,0 +1,68 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+func TestRenameRule(t *testing.T) {
+	testCases := []struct {
+		expr, key, expected string
+	}{
+		{`s/^raw\//processed\//`, "raw/a/b.txt", "processed/a/b.txt"},
+		{`s/^raw\//processed\//`, "other/raw/b.txt", "other/raw/b.txt"},
+		{`s|^raw/|processed/|`, "raw/b.txt", "processed/b.txt"},
+		{`s|a\|b|c|`, "a|b.txt", "c.txt"},
+		{`s/a/b/`, "aaa", "baa"},
+		{`s/a/b/g`, "aaa", "bbb"},
+		{`s/\.JPG$/.jpg/i`, "x.jpg", "x.jpg"},
+		{`s/\.jpg$/.png/i`, "x.JPG", "x.png"},
+		{`s/^([^\/]*)\/([^\/]*)$/\2\/\1/`, "a/b", "b/a"},
+		{`s/b/[&]/`, "abc", "a[b]c"},
+		{`s/b/\&$1/`, "abc", "a&$1c"},
+		{`s/(\d+)/n\1/g`, "1-22", "n1-n22"},
+	}
+	for i, testCase := range testCases {
+		rule, err := parseRenameRule(testCase.expr)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %s", i+1, err)
+		}
+		if renamed := rule.apply(testCase.key); renamed != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, renamed)
+		}
+	}
+
+	for i, expr := range []string{"", "s", "y/a/b/", "s/a/b", "s/a/b/c/", "s//b/", "sxaxbx", "s/a/b/x", "s/(/b/"} {
+		if _, err := parseRenameRule(expr); err == nil {
+			t.Errorf("Test %d: expected an error for %q", i+1, expr)
+		}
+	}
+}
+
+func TestRenameSuffix(t *testing.T) {
+	defer func() { globalRename = nil }()
+	rule, err := parseRenameRule(`s/^raw\/.*\.tmp$//`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalRename = []renameRule{rule}
+	if renamed, err := renameSuffix("/raw/a.txt"); err != nil || renamed != "/raw/a.txt" {
+		t.Errorf("expected /raw/a.txt, got %q %v", renamed, err)
+	}
+	if _, err := renameSuffix("/raw/a.tmp"); err == nil {
+		t.Error("expected an error for a key renamed to nothing")
+	}
+}
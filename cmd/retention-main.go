@@ -0,0 +1,42 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "github.com/minio/cli"
+
+var (
+	retentionFlags = []cli.Flag{}
+)
+
+var retentionCmd = cli.Command{
+	Name:            "retention",
+	Usage:           "inspect object retention and locking",
+	HideHelpCommand: true,
+	Action:          mainRetention,
+	Before:          setGlobalsFromContext,
+	Flags:           append(retentionFlags, globalFlags...),
+	Subcommands: []cli.Command{
+		retentionReportCmd,
+	},
+}
+
+// mainRetention is the handle for "mc retention" command.
+func mainRetention(ctx *cli.Context) error {
+	cli.ShowCommandHelp(ctx, ctx.Args().First())
+	return nil
+	// Sub-commands like "report" have their own main.
+}
@@ -0,0 +1,362 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var (
+	retentionReportFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "csv",
+			Usage: "export per object retention status as CSV to a file, use '-' for stdout",
+		},
+	}
+)
+
+var retentionReportCmd = cli.Command{
+	Name:   "report",
+	Usage:  "summarize retention status of objects under a prefix",
+	Action: mainRetentionReport,
+	Before: setGlobalsFromContext,
+	Flags:  append(append(retentionReportFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+
+EXAMPLES:
+   1. Summarize retention status of all objects in a bucket.
+      $ {{.HelpName}} myminio/compliance-bucket
+
+   2. Export retention status of objects under a prefix as CSV.
+      $ {{.HelpName}} --csv /tmp/retention.csv myminio/compliance-bucket/2019/
+
+   3. Print retention status as CSV on stdout.
+      $ {{.HelpName}} --csv - myminio/compliance-bucket
+`,
+}
+
+// Object lock headers returned by the server on HEAD requests.
+const (
+	amzObjectLockMode            = "X-Amz-Object-Lock-Mode"
+	amzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
+	amzObjectLockLegalHold       = "X-Amz-Object-Lock-Legal-Hold"
+)
+
+// retentionStatus - retention state of a single object.
+type retentionStatus string
+
+const (
+	retentionUnlocked retentionStatus = "unlocked"
+	retentionLocked   retentionStatus = "locked"
+	retentionExpired  retentionStatus = "expired"
+)
+
+// objectRetention - retention information of a single object.
+type objectRetention struct {
+	Mode        string
+	RetainUntil time.Time
+	LegalHold   bool
+}
+
+// parseObjectRetention extracts object lock information from object metadata.
+func parseObjectRetention(metadata map[string]string) objectRetention {
+	var r objectRetention
+	for k, v := range metadata {
+		switch {
+		case strings.EqualFold(k, amzObjectLockMode):
+			r.Mode = strings.ToUpper(v)
+		case strings.EqualFold(k, amzObjectLockRetainUntilDate):
+			if t, e := time.Parse(time.RFC3339, v); e == nil {
+				r.RetainUntil = t
+			}
+		case strings.EqualFold(k, amzObjectLockLegalHold):
+			r.LegalHold = strings.EqualFold(v, "ON")
+		}
+	}
+	return r
+}
+
+// status returns the retention status of an object relative to now.
+func (r objectRetention) status(now time.Time) retentionStatus {
+	if r.LegalHold {
+		return retentionLocked
+	}
+	if r.Mode == "" || r.RetainUntil.IsZero() {
+		return retentionUnlocked
+	}
+	if !r.RetainUntil.After(now) {
+		return retentionExpired
+	}
+	return retentionLocked
+}
+
+// retentionHistogramLimits - upper limits of the retention expiry histogram.
+var retentionHistogramLimits = []struct {
+	label string
+	limit time.Duration
+}{
+	{"< 1 day", 24 * time.Hour},
+	{"< 7 days", 7 * 24 * time.Hour},
+	{"< 30 days", 30 * 24 * time.Hour},
+	{"< 90 days", 90 * 24 * time.Hour},
+	{"< 1 year", 365 * 24 * time.Hour},
+}
+
+// retentionHistogramIndex returns the histogram slot for the remaining retention.
+func retentionHistogramIndex(remaining time.Duration) int {
+	for i, h := range retentionHistogramLimits {
+		if remaining < h.limit {
+			return i
+		}
+	}
+	return len(retentionHistogramLimits)
+}
+
+// retentionHistogramLabel returns the label of a histogram slot.
+func retentionHistogramLabel(i int) string {
+	if i < len(retentionHistogramLimits) {
+		return retentionHistogramLimits[i].label
+	}
+	return ">= 1 year"
+}
+
+// retentionHistogramEntry - number of objects with retention expiring in a time window.
+type retentionHistogramEntry struct {
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+	Size  int64  `json:"size"`
+}
+
+// retentionEligibleObject - object past its retention period.
+type retentionEligibleObject struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	RetainUntil time.Time `json:"retainUntil"`
+}
+
+// retentionReportMessage container for retention report.
+type retentionReportMessage struct {
+	Status      string                    `json:"status"`
+	URL         string                    `json:"url"`
+	Total       int64                     `json:"total"`
+	TotalSize   int64                     `json:"totalSize"`
+	Locked      int64                     `json:"locked"`
+	LockedSize  int64                     `json:"lockedSize"`
+	LegalHold   int64                     `json:"legalHold"`
+	Governance  int64                     `json:"governance"`
+	Compliance  int64                     `json:"compliance"`
+	Unlocked    int64                     `json:"unlocked"`
+	Expired     int64                     `json:"expired"`
+	ExpiredSize int64                     `json:"expiredSize"`
+	Histogram   []retentionHistogramEntry `json:"histogram"`
+	Eligible    []retentionEligibleObject `json:"eligible,omitempty"`
+}
+
+// newRetentionReportMessage returns a report with an initialized histogram.
+func newRetentionReportMessage(url string) retentionReportMessage {
+	r := retentionReportMessage{URL: url}
+	for i := 0; i <= len(retentionHistogramLimits); i++ {
+		r.Histogram = append(r.Histogram, retentionHistogramEntry{Label: retentionHistogramLabel(i)})
+	}
+	return r
+}
+
+// add accounts an object into the report.
+func (r *retentionReportMessage) add(key string, size int64, ret objectRetention, now time.Time) retentionStatus {
+	r.Total++
+	r.TotalSize += size
+	switch ret.Mode {
+	case "GOVERNANCE":
+		r.Governance++
+	case "COMPLIANCE":
+		r.Compliance++
+	}
+	if ret.LegalHold {
+		r.LegalHold++
+	}
+	status := ret.status(now)
+	switch status {
+	case retentionUnlocked:
+		r.Unlocked++
+	case retentionExpired:
+		r.Expired++
+		r.ExpiredSize += size
+		r.Eligible = append(r.Eligible, retentionEligibleObject{
+			Key:         key,
+			Size:        size,
+			RetainUntil: ret.RetainUntil,
+		})
+	case retentionLocked:
+		r.Locked++
+		r.LockedSize += size
+		// Objects under legal hold only, or past their retention date,
+		// have no retention expiring.
+		if ret.Mode != "" && ret.RetainUntil.After(now) {
+			i := retentionHistogramIndex(ret.RetainUntil.Sub(now))
+			r.Histogram[i].Count++
+			r.Histogram[i].Size += size
+		}
+	}
+	return status
+}
+
+// JSON jsonified retention report message.
+func (r retentionReportMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// String colorized retention report message.
+func (r retentionReportMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, console.Colorize("Retention", fmt.Sprintf("Retention report for `%s`:", r.URL)))
+	fmt.Fprintf(&b, "  %-12s: %s (%s)\n", "Objects", humanize.Comma(r.Total), humanize.IBytes(uint64(r.TotalSize)))
+	fmt.Fprintf(&b, "  %-12s: %s (%s)\n", "Locked", humanize.Comma(r.Locked), humanize.IBytes(uint64(r.LockedSize)))
+	fmt.Fprintf(&b, "  %-12s: %s\n", "Governance", humanize.Comma(r.Governance))
+	fmt.Fprintf(&b, "  %-12s: %s\n", "Compliance", humanize.Comma(r.Compliance))
+	fmt.Fprintf(&b, "  %-12s: %s\n", "Legal hold", humanize.Comma(r.LegalHold))
+	fmt.Fprintf(&b, "  %-12s: %s\n", "Unlocked", humanize.Comma(r.Unlocked))
+	fmt.Fprintln(&b, console.Colorize("Expired", fmt.Sprintf("  %-12s: %s (%s)", "Expired",
+		humanize.Comma(r.Expired), humanize.IBytes(uint64(r.ExpiredSize)))))
+	fmt.Fprintln(&b, console.Colorize("Retention", "Retention expires in:"))
+	for _, h := range r.Histogram {
+		fmt.Fprintf(&b, "  %-12s: %s (%s)\n", h.Label, humanize.Comma(h.Count), humanize.IBytes(uint64(h.Size)))
+	}
+	if len(r.Eligible) > 0 {
+		fmt.Fprintln(&b, console.Colorize("Expired", "Eligible for deletion:"))
+		for _, o := range r.Eligible {
+			fmt.Fprintf(&b, "  [%s] %7s %s\n", o.RetainUntil.Local().Format(printDate),
+				strings.Join(strings.Fields(humanize.IBytes(uint64(o.Size))), ""), o.Key)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// checkRetentionReportSyntax - validate all the passed arguments
+func checkRetentionReportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "report", 1) // last argument is exit code
+	}
+}
+
+// mainRetentionReport is the handle for "mc retention report" command.
+func mainRetentionReport(ctx *cli.Context) error {
+	console.SetColor("Retention", color.New(color.FgCyan, color.Bold))
+	console.SetColor("Expired", color.New(color.FgYellow, color.Bold))
+
+	checkRetentionReportSyntax(ctx)
+
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(ctx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	targetURL := ctx.Args().First()
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+	targetAlias, _, _ := mustExpandAlias(targetURL)
+
+	var csvWriter *csv.Writer
+	if csvPath := ctx.String("csv"); csvPath != "" {
+		var w io.Writer = os.Stdout
+		if csvPath != "-" {
+			f, e := os.Create(csvPath)
+			fatalIf(probe.NewError(e), "Unable to create `"+csvPath+"`.")
+			defer f.Close()
+			w = f
+		}
+		csvWriter = csv.NewWriter(w)
+		fatalIf(probe.NewError(csvWriter.Write([]string{"key", "size", "lastModified", "mode", "retainUntil", "legalHold", "status"})),
+			"Unable to write CSV header.")
+	}
+
+	now := UTCNow()
+	report := newRetentionReportMessage(targetURL)
+
+	fs := &failureStatus{}
+	for content := range clnt.List(globalContext, true, false, DirNone) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			fs.fail(content.Err)
+			continue
+		}
+		objectURL := targetAlias + getKey(content)
+		_, stat, err := url2Stat(globalContext, objectURL, true, encKeyDB)
+		if err != nil {
+			errorIf(err.Trace(objectURL), "Unable to stat `"+objectURL+"`.")
+			fs.fail(err)
+			continue
+		}
+		fs.success()
+
+		ret := parseObjectRetention(stat.Metadata)
+		status := report.add(objectURL, content.Size, ret, now)
+		if csvWriter == nil {
+			continue
+		}
+		var retainUntil string
+		if !ret.RetainUntil.IsZero() {
+			retainUntil = ret.RetainUntil.UTC().Format(time.RFC3339)
+		}
+		e := csvWriter.Write([]string{
+			objectURL,
+			strconv.FormatInt(content.Size, 10),
+			content.Time.UTC().Format(time.RFC3339),
+			ret.Mode,
+			retainUntil,
+			strconv.FormatBool(ret.LegalHold),
+			string(status),
+		})
+		fatalIf(probe.NewError(e), "Unable to write CSV record for `"+objectURL+"`.")
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		fatalIf(probe.NewError(csvWriter.Error()), "Unable to write CSV report.")
+		if ctx.String("csv") == "-" {
+			return fs.exitError()
+		}
+	}
+
+	printMsg(report)
+	return fs.exitError()
+}
@@ -0,0 +1,75 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionReport(t *testing.T) {
+	now := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		metadata map[string]string
+		status   retentionStatus
+	}{
+		{map[string]string{"Content-Type": "text/plain"}, retentionUnlocked},
+		{map[string]string{
+			"X-Amz-Object-Lock-Mode":              "GOVERNANCE",
+			"X-Amz-Object-Lock-Retain-Until-Date": "2019-07-03T00:00:00Z",
+		}, retentionLocked},
+		{map[string]string{
+			"X-Amz-Object-Lock-Mode":              "COMPLIANCE",
+			"X-Amz-Object-Lock-Retain-Until-Date": "2019-06-01T00:00:00Z",
+		}, retentionExpired},
+		{map[string]string{
+			"X-Amz-Object-Lock-Mode":              "COMPLIANCE",
+			"X-Amz-Object-Lock-Retain-Until-Date": "2019-06-01T00:00:00Z",
+			"X-Amz-Object-Lock-Legal-Hold":        "ON",
+		}, retentionLocked},
+		{map[string]string{"X-Amz-Object-Lock-Legal-Hold": "ON"}, retentionLocked},
+	}
+
+	report := newRetentionReportMessage("play/bucket")
+	for i, testCase := range testCases {
+		status := report.add("object", 10, parseObjectRetention(testCase.metadata), now)
+		if status != testCase.status {
+			t.Fatalf("Test %d: Expected %s, got %s", i+1, testCase.status, status)
+		}
+	}
+
+	if report.Total != 5 || report.Locked != 3 || report.Expired != 1 || report.Unlocked != 1 {
+		t.Fatalf("Unexpected report counts %+v", report)
+	}
+	if report.Governance != 1 || report.Compliance != 2 || report.LegalHold != 2 {
+		t.Fatalf("Unexpected report modes %+v", report)
+	}
+	// Object locked for two more days ends up in the '< 7 days' slot,
+	// objects under legal hold past or without a retention date in none.
+	for i, h := range report.Histogram {
+		expected := int64(0)
+		if i == 1 {
+			expected = 1
+		}
+		if h.Count != expected {
+			t.Fatalf("Expected %d objects in `%s`, got %d", expected, h.Label, h.Count)
+		}
+	}
+	if len(report.Eligible) != 1 {
+		t.Fatalf("Expected one object eligible for deletion, got %d", len(report.Eligible))
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/ioutils"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// rewindFlag lists and reads versioned buckets as they were at a point in time.
+var rewindFlag = cli.StringFlag{
+	Name:  "rewind",
+	Usage: "list and read objects as they were DURATION ago or at TIMESTAMP, on versioned buckets",
+}
+
+// Timestamps accepted by --rewind, in local time unless a zone is given.
+var rewindLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Sources of the running command which are listed and read as they
+// were at a point in time, set once by rewindSources.
+var rewound struct {
+	at   time.Time
+	urls []string
+}
+
+// parseRewind - parse the point in time of --rewind, either a duration
+// like `7d12h` back from now or a timestamp like `2019-06-01T10:00`.
+func parseRewind(value string) (time.Time, *probe.Error) {
+	for _, layout := range rewindLayouts {
+		if at, e := time.ParseInLocation(layout, value, time.Local); e == nil {
+			return at.UTC(), nil
+		}
+	}
+	d, e := ioutils.ParseDurationTime(value)
+	if e != nil || d < 0 {
+		return time.Time{}, errInvalidRewind(value)
+	}
+	return UTCNow().Add(-d), nil
+}
+
+// checkRewindable - only buckets which have or had versioning enabled
+// keep the versions needed to rewind.
+func checkRewindable(url string) (string, *probe.Error) {
+	clnt, err := newClient(url)
+	if err != nil {
+		return "", err.Trace(url)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return "", errRewindUnavailable(url)
+	}
+	versioning, err := s3Clnt.GetVersioning()
+	if err != nil {
+		return "", err.Trace(url)
+	}
+	if versioning == "" {
+		return "", errRewindUnavailable(url)
+	}
+	return clnt.GetURL().String(), nil
+}
+
+// rewindSources - list and read the given sources as they were at the
+// point in time of --rewind, returns the parsed point in time. Nothing
+// is rewound when value is empty.
+func rewindSources(value string, sourceURLs ...string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	at, err := parseRewind(value)
+	fatalIf(err.Trace(value), "Unable to parse --rewind=`"+value+"`.")
+	if at.After(UTCNow()) {
+		fatalIf(errInvalidRewind(value).Trace(value), "Unable to rewind to a point in time in the future.")
+	}
+	var urls []string
+	for _, sourceURL := range sourceURLs {
+		url, err := checkRewindable(sourceURL)
+		fatalIf(err, "Unable to rewind `"+sourceURL+"`.")
+		urls = append(urls, url)
+	}
+	rewound.at, rewound.urls = at, urls
+	return at
+}
+
+// rewindTime - point in time the object or prefix at url is rewound
+// to, zero if url is not below a rewound source.
+func rewindTime(url string) time.Time {
+	for _, source := range rewound.urls {
+		if url == source || strings.HasPrefix(url, strings.TrimSuffix(source, "/")+"/") {
+			return rewound.at
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,211 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestParseRewind(t *testing.T) {
+	now := UTCNow()
+	testCases := []struct {
+		value    string
+		expected time.Time
+		err      bool
+	}{
+		{"2019-06-01T10:00:00Z", time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC), false},
+		{"2019-06-01T12:00:00+02:00", time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC), false},
+		{"2019-06-01T10:00", time.Date(2019, 6, 1, 10, 0, 0, 0, time.Local).UTC(), false},
+		{"2019-06-01", time.Date(2019, 6, 1, 0, 0, 0, 0, time.Local).UTC(), false},
+		{"7d", now.Add(-7 * 24 * time.Hour), false},
+		{"1h30m", now.Add(-90 * time.Minute), false},
+		{"-1h", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for i, testCase := range testCases {
+		at, err := parseRewind(testCase.value)
+		if testCase.err {
+			if err == nil {
+				t.Errorf("Test %d: expected an error for %q", i+1, testCase.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %s", i+1, err)
+		}
+		// Durations are relative to the time of parsing.
+		if d := at.Sub(testCase.expected); d < 0 || d > time.Minute {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, at)
+		}
+	}
+}
+
+// rewindVersion - a version or delete marker served by rewindHandler.
+type rewindVersion struct {
+	key, versionID string
+	modTime        time.Time
+	deleteMarker   bool
+	body           string
+}
+
+// rewindHandler - fake S3 server of a versioned bucket, versions are
+// sorted by key and newest first.
+type rewindHandler struct {
+	versions []rewindVersion
+	pageSize int
+}
+
+func (h *rewindHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && len(query["versioning"]) > 0:
+		w.Write([]byte("<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>"))
+	case r.Method == "GET" && len(query["versions"]) > 0:
+		var versions []rewindVersion
+		for _, v := range h.versions {
+			if strings.HasPrefix(v.key, query.Get("prefix")) {
+				versions = append(versions, v)
+			}
+		}
+		start := 0
+		if marker := query.Get("key-marker"); marker != "" {
+			for i, v := range versions {
+				if v.key == marker && v.versionID == query.Get("version-id-marker") {
+					start = i + 1
+				}
+			}
+		}
+		end := start + h.pageSize
+		if end > len(versions) {
+			end = len(versions)
+		}
+		response := "<ListVersionsResult>"
+		for _, v := range versions[start:end] {
+			tag := "Version"
+			if v.deleteMarker {
+				tag = "DeleteMarker"
+			}
+			response += fmt.Sprintf("<%s><Key>%s</Key><VersionId>%s</VersionId><LastModified>%s</LastModified><Size>%d</Size></%s>",
+				tag, v.key, v.versionID, v.modTime.Format(time.RFC3339Nano), len(v.body), tag)
+		}
+		if end < len(versions) {
+			last := versions[end-1]
+			response += fmt.Sprintf("<IsTruncated>true</IsTruncated><NextKeyMarker>%s</NextKeyMarker><NextVersionIdMarker>%s</NextVersionIdMarker>", last.key, last.versionID)
+		} else {
+			response += "<IsTruncated>false</IsTruncated>"
+		}
+		w.Write([]byte(response + "</ListVersionsResult>"))
+	case r.Method == "GET" && len(query["versionId"]) > 0:
+		for _, v := range h.versions {
+			if v.key == object && v.versionID == query.Get("versionId") {
+				w.Write([]byte(v.body))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchVersion</Code><Message>The specified version does not exist.</Message></Error>"))
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	}
+}
+
+func (s *TestSuite) TestRewind(c *C) {
+	base := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	h := &rewindHandler{
+		versions: []rewindVersion{
+			{key: "a", versionID: "v3", modTime: at(30), body: "a3a3"},
+			{key: "a", versionID: "v1", modTime: at(10), body: "a1"},
+			{key: "b", versionID: "v4", modTime: at(25), deleteMarker: true},
+			{key: "b", versionID: "v2", modTime: at(15), body: "b2"},
+			{key: "dir/c", versionID: "v5", modTime: at(20), body: "c5"},
+			{key: "z", versionID: "v6", modTime: at(40), body: "z6"},
+		},
+		pageSize: 3,
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+	defer func() { rewound.at, rewound.urls = time.Time{}, nil }()
+
+	newRewindClient := func(path string) Client {
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket" + path
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		return clnt
+	}
+	listAt := func(minutes int, path string, isRecursive bool) []string {
+		rewound.at, rewound.urls = at(minutes), []string{server.URL + "/bucket"}
+		var keys []string
+		for content := range newRewindClient(path).List(globalContext, isRecursive, false, DirNone) {
+			c.Assert(content.Err, IsNil)
+			key := strings.TrimPrefix(content.URL.Path, "/bucket/")
+			if !content.Type.IsDir() {
+				key += "@" + content.VersionID
+			}
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	c.Assert(listAt(22, "/", true), DeepEquals, []string{"a@v1", "b@v2", "dir/c@v5"})
+	c.Assert(listAt(27, "/", true), DeepEquals, []string{"a@v1", "dir/c@v5"})
+	c.Assert(listAt(45, "/", false), DeepEquals, []string{"a@v3", "dir/", "z@v6"})
+	c.Assert(listAt(5, "/", true), IsNil)
+
+	rewound.at = at(35)
+	content, err := newRewindClient("/a").Stat(globalContext, false, false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(content.VersionID, Equals, "v3")
+	c.Assert(content.Size, Equals, int64(4))
+	content, err = newRewindClient("/dir").Stat(globalContext, false, false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+	_, err = newRewindClient("/b").Stat(globalContext, false, false, nil)
+	c.Assert(err, NotNil)
+	c.Assert(err.ToGoError(), FitsTypeOf, ObjectMissing{})
+
+	rewound.at = at(12)
+	reader, err := newRewindClient("/a").Get(nil)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	reader.Close()
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "a1")
+
+	// Only clients below a rewound source are rewound.
+	rewound.urls = []string{server.URL + "/bucket/dir"}
+	c.Assert(rewindTime(server.URL+"/bucket/dir/c").IsZero(), Equals, false)
+	c.Assert(rewindTime(server.URL+"/bucket/dirty").IsZero(), Equals, true)
+	c.Assert(rewindTime(server.URL+"/bucket/a").IsZero(), Equals, true)
+}
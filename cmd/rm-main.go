@@ -64,6 +64,13 @@ var (
 			Name:  "newer-than",
 			Usage: "remove objects newer than L days, M hours and N minutes",
 		},
+		cli.BoolFlag{
+			Name:  "to-trash",
+			Usage: "move local files to the trash, only remove the latest version of objects on versioned buckets",
+		},
+		maxErrorsFlag,
+		yesFlag,
+		interactiveFlag,
 	}
 )
 
@@ -116,6 +123,29 @@ EXAMPLES:
 
    10. Remove an encrypted object from Amazon S3 cloud storage.
       $ {{.HelpName}} --encrypt-key "s3/sql-backups/=32byteslongsecretkeymustbegiven1" s3/sql-backups/1999/old-backup.tgz
+
+   11. Abort incomplete uploads started more than 7 days ago on the bucket 'jazz-songs'.
+      $ {{.HelpName}} --incomplete --recursive --force --older-than 7d s3/jazz-songs/
+
+   12. Remove all objects of the bucket 'jazz-songs' from a script, without asking for confirmation.
+      $ {{.HelpName}} --recursive --force --yes s3/jazz-songs/
+
+   13. Move a local file to the trash instead of removing it.
+      $ {{.HelpName}} --to-trash ~/Downloads/old-backup.tgz
+
+   14. Remove all objects of the versioned bucket 'jazz-songs', keeping their versions behind delete markers.
+      $ {{.HelpName}} --recursive --force --to-trash s3/jazz-songs/
+
+   15. Remove old logs recursively past objects failing to be removed, giving up once more than 10 failed.
+      $ {{.HelpName}} --recursive --force --older-than 90d --max-errors 10 s3/logs/
+
+   16. Pick the objects to remove from the bucket 'jazz-songs' in a fuzzy finder.
+      $ {{.HelpName}} --recursive --interactive s3/jazz-songs/
+
+NOTE:
+   Removing all objects of a bucket, or of a host, asks for confirmation on a terminal unless '--yes' is given.
+   With '--to-trash' objects are only removed from versioned buckets, where they can be restored from their versions.
+   With '--interactive' only the picked objects are removed, a recursive removal needs no '--force'.
 `,
 }
 
@@ -146,6 +176,7 @@ func checkRmSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	isRecursive := ctx.Bool("recursive")
 	isStdin := ctx.Bool("stdin")
 	isDangerous := ctx.Bool("dangerous")
+	isInteractive := ctx.Bool("interactive")
 	isNamespaceRemoval := false
 
 	for _, url := range ctx.Args() {
@@ -164,8 +195,9 @@ func checkRmSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		cli.ShowCommandHelpAndExit(ctx, "rm", exitCode)
 	}
 
-	// For all recursive operations make sure to check for 'force' flag.
-	if (isRecursive || isStdin) && !isForce {
+	// For all recursive operations make sure to check for 'force' flag,
+	// interactive removals only remove the picked objects.
+	if (isRecursive || isStdin) && !isForce && !isInteractive {
 		if isNamespaceRemoval {
 			fatalIf(errDummy().Trace(),
 				"This operation results in site-wide removal of objects. If you are really sure, retry this command with ‘--dangerous’ and ‘--force’ flags.")
@@ -173,37 +205,87 @@ func checkRmSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		fatalIf(errDummy().Trace(),
 			"Removal requires --force flag. This operation is *IRREVERSIBLE*. Please review carefully before performing this *DANGEROUS* operation.")
 	}
+	if ctx.Bool("to-trash") && ctx.Bool("incomplete") {
+		fatalIf(errInvalidArgument().Trace(), "Incomplete uploads cannot be moved to the trash, ‘--to-trash’ and ‘--incomplete’ are mutually exclusive.")
+	}
 	if (isRecursive || isStdin) && isNamespaceRemoval && !isDangerous {
 		fatalIf(errDummy().Trace(),
 			"This operation results in site-wide removal of objects. If you are really sure, retry this command with ‘--dangerous’ and ‘--force’ flags.")
 	}
+	checkInteractiveFlag(ctx)
 }
 
-func removeSingle(url string, isIncomplete bool, isFake, isForce bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair) error {
+// checkTrashable - removal with --to-trash must leave a way to restore
+// what is removed: local files go to the trash, and objects are kept as
+// versions behind a delete marker, so the bucket must be versioned.
+func checkTrashable(url string) *probe.Error {
+	clnt, err := newClient(url)
+	if err != nil {
+		return err.Trace(url)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return nil
+	}
+	return checkVersionedTrash(s3Clnt, url)
+}
+
+// checkVersionedTrash - verify that objects removed from the bucket are
+// kept as versions.
+func checkVersionedTrash(clnt *s3Client, url string) *probe.Error {
+	versioning, err := clnt.GetVersioning()
+	if err != nil {
+		return err.Trace(url)
+	}
+	if versioning != "Enabled" {
+		return errTrashUnavailable(url)
+	}
+	return nil
+}
+
+// removeContents - remove the contents sent on contentCh, local files
+// are moved to the trash with --to-trash.
+func removeContents(clnt Client, isIncomplete, isTrash bool, contentCh <-chan *clientContent) <-chan *probe.Error {
+	if fsClnt, ok := clnt.(*fsClient); ok && isTrash {
+		return fsClnt.Trash(contentCh)
+	}
+	isRemoveBucket := false
+	return clnt.Remove(isIncomplete, isRemoveBucket, contentCh)
+}
+
+func removeSingle(url string, isIncomplete, isTrash bool, isFake, isForce bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair, fs *failureStatus) {
+	if isTrash {
+		if pErr := checkTrashable(url); pErr != nil {
+			errorIf(pErr.Trace(url), "Failed to remove `"+url+"`.")
+			fs.fail(pErr)
+			return
+		}
+	}
 	isRecursive := false
 	contents, pErr := statURL(url, isIncomplete, isRecursive, encKeyDB)
 	if pErr != nil {
 		errorIf(pErr.Trace(url), "Failed to remove `"+url+"`.")
-		return exitStatus(globalErrorExitStatus)
+		fs.fail(pErr)
+		return
 	}
 	if len(contents) == 0 {
 		if !isForce {
 			errorIf(errDummy().Trace(url), "Failed to remove `"+url+"`. Target object is not found")
-			return exitStatus(globalErrorExitStatus)
+			fs.fail(probe.NewError(ObjectMissing{}))
 		}
-		return nil
+		return
 	}
 
 	content := contents[0]
 
 	// Skip objects older than older--than parameter if specified
 	if olderThan != "" && isOlder(content.Time, olderThan) {
-		return nil
+		return
 	}
 
 	// Skip objects older than older--than parameter if specified
 	if newerThan != "" && isNewer(content.Time, newerThan) {
-		return nil
+		return
 	}
 
 	printMsg(rmMessage{
@@ -216,52 +298,77 @@ func removeSingle(url string, isIncomplete bool, isFake, isForce bool, olderThan
 		clnt, pErr := newClientFromAlias(targetAlias, targetURL)
 		if pErr != nil {
 			errorIf(pErr.Trace(url), "Invalid argument `"+url+"`.")
-			return exitStatus(globalErrorExitStatus) // End of journey.
+			fs.fail(pErr)
+			return // End of journey.
 		}
 
 		contentCh := make(chan *clientContent, 1)
 		contentCh <- &clientContent{URL: *newClientURL(targetURL)}
 		close(contentCh)
-		isRemoveBucket := false
-		errorCh := clnt.Remove(isIncomplete, isRemoveBucket, contentCh)
+		errorCh := removeContents(clnt, isIncomplete, isTrash, contentCh)
 		for pErr := range errorCh {
 			if pErr != nil {
 				errorIf(pErr.Trace(url), "Failed to remove `"+url+"`.")
+				fs.fail(pErr)
 				switch pErr.ToGoError().(type) {
 				case PathInsufficientPermission:
 					// Ignore Permission error.
 					continue
 				}
-				return exitStatus(globalErrorExitStatus)
+				return
 			}
 		}
 	}
-	return nil
+	fs.success()
 }
 
-func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair) error {
+// confirmRecursiveRemoval - ask before removing the contents of a whole
+// bucket, or of all buckets of an alias.
+func confirmRecursiveRemoval(url string, isIncomplete bool) {
+	clnt, err := newClient(url)
+	if err != nil {
+		// Reported by the removal.
+		return
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return
+	}
+	if _, object := s3Clnt.url2BucketAndObject(); object != "" {
+		return
+	}
+	confirmOrExit(fmt.Sprintf("Remove up to %s in `%s`? This operation is IRREVERSIBLE.",
+		describeObjectCount(countObjects(clnt, isIncomplete)), url))
+}
+
+func removeRecursive(url string, isIncomplete, isTrash bool, isFake bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair, fs *failureStatus) {
+	if isTrash {
+		if pErr := checkTrashable(url); pErr != nil {
+			errorIf(pErr.Trace(url), "Failed to remove `"+url+"` recursively.")
+			fs.fail(pErr)
+			return
+		}
+	}
 	targetAlias, targetURL, _ := mustExpandAlias(url)
 	clnt, pErr := newClientFromAlias(targetAlias, targetURL)
 	if pErr != nil {
 		errorIf(pErr.Trace(url), "Failed to remove `"+url+"` recursively.")
-		return exitStatus(globalErrorExitStatus) // End of journey.
+		fs.fail(pErr)
+		return // End of journey.
 	}
 	contentCh := make(chan *clientContent)
-	isRemoveBucket := false
-
-	errorCh := clnt.Remove(isIncomplete, isRemoveBucket, contentCh)
+	errorCh := removeContents(clnt, isIncomplete, isTrash, contentCh)
 
 	isRecursive := true
-	for content := range clnt.List(isRecursive, isIncomplete, DirLast) {
+	for content := range clnt.List(globalContext, isRecursive, isIncomplete, DirLast) {
 		if content.Err != nil {
 			errorIf(content.Err.Trace(url), "Failed to remove `"+url+"` recursively.")
-			switch content.Err.ToGoError().(type) {
-			case PathInsufficientPermission:
-				// Ignore Permission error.
+			fs.fail(content.Err)
+			if keepRemoving(content.Err, fs) {
 				continue
 			}
 			close(contentCh)
-			return exitStatus(globalErrorExitStatus)
+			return
 		}
 		urlString := content.URL.Path
 
@@ -290,30 +397,93 @@ func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newe
 					sent = true
 				case pErr := <-errorCh:
 					errorIf(pErr.Trace(urlString), "Failed to remove `"+urlString+"`.")
-					switch pErr.ToGoError().(type) {
-					case PathInsufficientPermission:
-						// Ignore Permission error.
+					fs.fail(pErr)
+					if keepRemoving(pErr, fs) {
 						continue
 					}
 					close(contentCh)
-					return exitStatus(globalErrorExitStatus)
+					return
 				}
 			}
 		}
+		fs.success()
 	}
 
 	close(contentCh)
 	for pErr := range errorCh {
 		errorIf(pErr.Trace(url), "Failed to remove `"+url+"` recursively.")
-		switch pErr.ToGoError().(type) {
-		case PathInsufficientPermission:
-			// Ignore Permission error.
+		fs.fail(pErr)
+		if keepRemoving(pErr, fs) {
 			continue
 		}
-		return exitStatus(globalErrorExitStatus)
+		return
 	}
+}
 
-	return nil
+// listRemovalCandidates - the objects an interactive removal picks from,
+// the objects listed recursively below each target or the targets.
+func listRemovalCandidates(urls []string, isRecursive, isIncomplete bool, olderThan, newerThan string, fs *failureStatus) []string {
+	if !isRecursive {
+		return urls
+	}
+	var candidates []string
+	for _, url := range urls {
+		targetAlias, targetURL, _ := mustExpandAlias(url)
+		clnt, pErr := newClientFromAlias(targetAlias, targetURL)
+		if pErr != nil {
+			errorIf(pErr.Trace(url), "Unable to list `"+url+"`.")
+			fs.fail(pErr)
+			continue
+		}
+		for content := range clnt.List(globalContext, isRecursive, isIncomplete, DirNone) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(url), "Unable to list `"+url+"`.")
+				fs.fail(content.Err)
+				break
+			}
+			if content.Type.IsDir() {
+				continue
+			}
+			if olderThan != "" && isOlder(content.Time, olderThan) {
+				continue
+			}
+			if newerThan != "" && isNewer(content.Time, newerThan) {
+				continue
+			}
+			candidates = append(candidates, targetAlias+content.URL.Path)
+		}
+	}
+	return candidates
+}
+
+// removeInteractive - remove the objects picked from the candidates.
+func removeInteractive(urls []string, isRecursive, isIncomplete, isTrash, isFake, isForce bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair, fs *failureStatus) {
+	candidates := listRemovalCandidates(urls, isRecursive, isIncomplete, olderThan, newerThan, fs)
+	picked, ok := pickObjects("Select objects to remove", candidates)
+	if !ok {
+		fatalIf(errDummy().Trace(), "Operation aborted, nothing was changed.")
+	}
+	for _, i := range picked {
+		removeSingle(candidates[i], isIncomplete, isTrash, isFake, isForce, olderThan, newerThan, encKeyDB, fs)
+		if err := fs.exceeded(); err != nil {
+			errorIf(err.Trace(candidates[i]), "Too many failed objects.")
+			return
+		}
+	}
+}
+
+// keepRemoving - true if a recursive removal goes on past a failure,
+// permission errors are skipped until more objects failed than
+// tolerated by '--max-errors'.
+func keepRemoving(pErr *probe.Error, fs *failureStatus) bool {
+	if fs.exceeded() != nil {
+		return false
+	}
+	switch pErr.ToGoError().(type) {
+	case PathInsufficientPermission:
+		return true
+	}
+	return fs.tolerated()
 }
 
 // main for rm command.
@@ -324,6 +494,8 @@ func mainRm(ctx *cli.Context) error {
 
 	// check 'rm' cli arguments.
 	checkRmSyntax(ctx, encKeyDB)
+	checkWritableTargets(ctx.Args()...)
+	maxErrors := checkMaxErrors(ctx)
 
 	// rm specific flags.
 	isIncomplete := ctx.Bool("incomplete")
@@ -333,42 +505,55 @@ func mainRm(ctx *cli.Context) error {
 	olderThan := ctx.String("older-than")
 	newerThan := ctx.String("newer-than")
 	isForce := ctx.Bool("force")
+	isTrash := ctx.Bool("to-trash")
+	isInteractive := ctx.Bool("interactive")
 
 	// Set color.
 	console.SetColor("Remove", color.New(color.FgGreen, color.Bold))
 
-	var rerr error
-	var e error
+	if isRecursive && !isFake && !isTrash && !isInteractive && isConfirmationNeeded(ctx) {
+		for _, url := range ctx.Args() {
+			confirmRecursiveRemoval(url, isIncomplete)
+		}
+	}
+
+	fs := &failureStatus{}
+	fs.setMaxErrors(maxErrors)
+	if isInteractive {
+		removeInteractive(ctx.Args(), isRecursive, isIncomplete, isTrash, isFake, isForce, olderThan, newerThan, encKeyDB, fs)
+		return fs.exitError()
+	}
+
 	// Support multiple targets.
 	for _, url := range ctx.Args() {
 		if isRecursive {
-			e = removeRecursive(url, isIncomplete, isFake, olderThan, newerThan, encKeyDB)
+			removeRecursive(url, isIncomplete, isTrash, isFake, olderThan, newerThan, encKeyDB, fs)
 		} else {
-			e = removeSingle(url, isIncomplete, isFake, isForce, olderThan, newerThan, encKeyDB)
+			removeSingle(url, isIncomplete, isTrash, isFake, isForce, olderThan, newerThan, encKeyDB, fs)
 		}
-
-		if rerr == nil {
-			rerr = e
+		if err := fs.exceeded(); err != nil {
+			errorIf(err.Trace(url), "Too many failed objects.")
+			return fs.exitError()
 		}
 	}
 
 	if !isStdin {
-		return rerr
+		return fs.exitError()
 	}
 
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
 		url := scanner.Text()
 		if isRecursive {
-			e = removeRecursive(url, isIncomplete, isFake, olderThan, newerThan, encKeyDB)
+			removeRecursive(url, isIncomplete, isTrash, isFake, olderThan, newerThan, encKeyDB, fs)
 		} else {
-			e = removeSingle(url, isIncomplete, isFake, isForce, olderThan, newerThan, encKeyDB)
+			removeSingle(url, isIncomplete, isTrash, isFake, isForce, olderThan, newerThan, encKeyDB, fs)
 		}
-
-		if rerr == nil {
-			rerr = e
+		if err := fs.exceeded(); err != nil {
+			errorIf(err.Trace(url), "Too many failed objects.")
+			return fs.exitError()
 		}
 	}
 
-	return rerr
+	return fs.exitError()
 }
@@ -0,0 +1,38 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http/httptest"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestCheckVersionedTrash(c *C) {
+	for _, versioning := range []string{"", "Suspended", "Enabled"} {
+		h := &lockHandler{versioning: versioning}
+		server := httptest.NewServer(h)
+		err := checkVersionedTrash(h.client(c, server), "s3/bucket")
+		server.Close()
+		if versioning == "Enabled" {
+			c.Assert(err, IsNil)
+			continue
+		}
+		c.Assert(err, NotNil)
+		c.Assert(err.ToGoError(), ErrorMatches, "Bucket of `s3/bucket` is not versioned.*")
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Policies of '--sanitize' for object names unsafe as local paths.
+const (
+	sanitizeSkip   = "skip"
+	sanitizeEscape = "escape"
+)
+
+// sanitizeFlag sets the policy for object names unsafe as local paths.
+var sanitizeFlag = cli.StringFlag{
+	Name:  "sanitize",
+	Usage: "skip or escape objects named with '..', empty names or control characters when copied to local paths. Valid options are '[skip, escape]' (default: skip)",
+}
+
+// setSanitizeFromContext - validate and set the policy of '--sanitize'.
+func setSanitizeFromContext(ctx *cli.Context) {
+	policy := strings.ToLower(ctx.String("sanitize"))
+	switch policy {
+	case "":
+		globalSanitize = sanitizeSkip
+	case sanitizeSkip, sanitizeEscape:
+		globalSanitize = policy
+	default:
+		fatalIf(errInvalidArgument().Trace(policy), "Unrecognized sanitize policy `"+policy+"`. Valid options are `[skip, escape]`.")
+	}
+}
+
+// isUnsafeSeparator - returns true for characters separating or rooting
+// paths on this platform only, such as '\' and ':' on Windows.
+func isUnsafeSeparator(c byte) bool {
+	return runtime.GOOS == "windows" && (c == '\\' || c == ':')
+}
+
+// unsafeNameReason - why a relative object name is unsafe to use as a
+// local path, empty if it is safe.
+func unsafeNameReason(name string) string {
+	for _, component := range strings.Split(strings.TrimSuffix(name, "/"), "/") {
+		switch component {
+		case "":
+			return "has an empty name or a leading slash"
+		case ".", "..":
+			return "has a `" + component + "` name"
+		}
+		for i := 0; i < len(component); i++ {
+			if c := component[i]; c < 0x20 || c == 0x7f {
+				return "has control characters"
+			} else if isUnsafeSeparator(c) {
+				return fmt.Sprintf("has a `%c`", c)
+			}
+		}
+	}
+	return ""
+}
+
+// escapeUnsafeName - escape a relative object name into a safe local
+// path, '.' and '..' names and control characters are escaped as '%XX',
+// empty names are dropped.
+func escapeUnsafeName(name string) string {
+	var components []string
+	for _, component := range strings.Split(strings.TrimSuffix(name, "/"), "/") {
+		switch component {
+		case "":
+			continue
+		case ".":
+			component = "%2E"
+		case "..":
+			component = "%2E%2E"
+		}
+		var b strings.Builder
+		for i := 0; i < len(component); i++ {
+			if c := component[i]; c < 0x20 || c == 0x7f || isUnsafeSeparator(c) {
+				fmt.Fprintf(&b, "%%%02X", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+		components = append(components, b.String())
+	}
+	if strings.HasSuffix(name, "/") {
+		return strings.Join(components, "/") + "/"
+	}
+	return strings.Join(components, "/")
+}
+
+// sanitizeSuffix - the suffix of an object copied from a remote source
+// to a local target, as a relative path which cannot leave the target.
+// Unsafe names are escaped or refused with an UnsafeObjectName error,
+// as '--sanitize' sets.
+func sanitizeSuffix(suffix string, isSourceLocal, isTargetLocal bool) (string, *probe.Error) {
+	if isSourceLocal || !isTargetLocal {
+		return suffix, nil
+	}
+	reason := unsafeNameReason(suffix)
+	if reason == "" {
+		return suffix, nil
+	}
+	if globalSanitize == sanitizeEscape {
+		return escapeUnsafeName(suffix), nil
+	}
+	return "", probe.NewError(UnsafeObjectName{Object: suffix, Reason: reason})
+}
+
+// isWithinDir - returns true if a local path is within a folder.
+func isWithinDir(dir, path string) bool {
+	rel, e := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return e == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// joinTargetPath - join the suffix of a source to the target, a suffix
+// copied from a remote source to a local target is sanitized first and
+// the joined path never leaves the target.
+func joinTargetPath(targetURL, suffix string, isSourceLocal, isTargetLocal bool) (string, *probe.Error) {
+	safeSuffix, err := sanitizeSuffix(strings.TrimPrefix(suffix, "/"), isSourceLocal, isTargetLocal)
+	if err != nil {
+		return "", err.Trace(targetURL)
+	}
+	targetPath := urlJoinPath(targetURL, safeSuffix)
+	if isTargetLocal && !isSourceLocal && !isWithinDir(targetURL, targetPath) {
+		return "", probe.NewError(UnsafeObjectName{Object: suffix, Reason: "leaves the target folder"}).Trace(targetURL)
+	}
+	return targetPath, nil
+}
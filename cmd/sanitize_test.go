@@ -0,0 +1,133 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUnsafeNameReason(t *testing.T) {
+	testCases := []struct {
+		name   string
+		unsafe bool
+	}{
+		{"a.txt", false},
+		{"dir/a.txt", false},
+		{"dir/", false},
+		{"..a/b..", false},
+		{"../a.txt", true},
+		{"dir/../../a.txt", true},
+		{"./a.txt", true},
+		{"/etc/passwd", true},
+		{"dir//a.txt", true},
+		{"a\x00.txt", true},
+		{"a\n.txt", true},
+		{"a\x7f.txt", true},
+	}
+	for i, testCase := range testCases {
+		if reason := unsafeNameReason(testCase.name); (reason != "") != testCase.unsafe {
+			t.Errorf("Test %d: %q expected unsafe %v, got %q", i+1, testCase.name, testCase.unsafe, reason)
+		}
+	}
+}
+
+func TestEscapeUnsafeName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"dir/a.txt", "dir/a.txt"},
+		{"../a.txt", "%2E%2E/a.txt"},
+		{"dir/./..", "dir/%2E/%2E%2E"},
+		{"/etc//passwd", "etc/passwd"},
+		{"a\x00\n.txt", "a%00%0A.txt"},
+		{"../dir/", "%2E%2E/dir/"},
+	}
+	for i, testCase := range testCases {
+		escaped := escapeUnsafeName(testCase.name)
+		if escaped != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, escaped)
+		}
+		if reason := unsafeNameReason(escaped); reason != "" {
+			t.Errorf("Test %d: escaped name %q is unsafe, %s", i+1, escaped, reason)
+		}
+	}
+}
+
+func TestJoinTargetPath(t *testing.T) {
+	savedSanitize := globalSanitize
+	defer func() { globalSanitize = savedSanitize }()
+
+	target := filepath.Join("tmp", "download")
+	testCases := []struct {
+		suffix        string
+		isSourceLocal bool
+		isTargetLocal bool
+		policy        string
+		expected      string
+		err           bool
+	}{
+		{"/dir/a.txt", false, true, sanitizeSkip, "tmp/download/dir/a.txt", false},
+		{"../../etc/passwd", false, true, sanitizeSkip, "", true},
+		{"//etc/passwd", false, true, sanitizeSkip, "", true},
+		{"a\x1b.txt", false, true, sanitizeSkip, "", true},
+		{"../../etc/passwd", false, true, sanitizeEscape, "tmp/download/%2E%2E/%2E%2E/etc/passwd", false},
+		{"//etc/passwd", false, true, sanitizeEscape, "tmp/download/etc/passwd", false},
+		// Only names copied from remote sources to local targets are checked.
+		{"../a.txt", false, false, sanitizeSkip, "tmp/download/../a.txt", false},
+		{"../a.txt", true, true, sanitizeSkip, "tmp/download/../a.txt", false},
+	}
+	for i, testCase := range testCases {
+		globalSanitize = testCase.policy
+		targetPath, err := joinTargetPath(target, testCase.suffix, testCase.isSourceLocal, testCase.isTargetLocal)
+		if testCase.err {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %q", i+1, targetPath)
+			} else if _, ok := err.ToGoError().(UnsafeObjectName); !ok {
+				t.Errorf("Test %d: expected UnsafeObjectName, got %v", i+1, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+			continue
+		}
+		if filepath.ToSlash(targetPath) != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, targetPath)
+		}
+	}
+}
+
+func TestIsWithinDir(t *testing.T) {
+	testCases := []struct {
+		dir, path string
+		within    bool
+	}{
+		{"download", "download/a.txt", true},
+		{"download/", "download/dir/../a.txt", true},
+		{"download", "download", true},
+		{"download", "download/../a.txt", false},
+		{"download", "downloads/a.txt", false},
+		{"/tmp/download", "/tmp/download/..a", true},
+	}
+	for i, testCase := range testCases {
+		if within := isWithinDir(filepath.FromSlash(testCase.dir), filepath.FromSlash(testCase.path)); within != testCase.within {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.within, within)
+		}
+	}
+}
@@ -90,16 +90,12 @@ func (c clearSessionMessage) JSON() string {
 // forceClear - Remove a saved session.
 // Used if --force flag is applied.
 func forceClear(sid string, session *sessionV8) {
-	if session != nil {
-		if err := session.Delete().Trace(sid); err == nil {
-			// Force unnecesseray removal successful.
-			printMsg(clearSessionMessage{Status: "success", SessionID: sid})
-			return
-		}
+	if !removeSession(sid, session) {
+		// Force unnecesseray removal successful.
+		printMsg(clearSessionMessage{Status: "success", SessionID: sid})
+		return
 	}
-	// Remove obsolete session files.
-	removeSessionFile(sid)
-	removeSessionDataFile(sid)
+	// Obsolete session files were removed.
 	printMsg(clearSessionMessage{Status: "forced", SessionID: sid})
 }
 
@@ -157,10 +157,11 @@ func migrateSessionV5ToV6() {
 		fatalIf(err.Trace(sid), "Unable to get session data file.")
 
 		console.Println("Removing unsupported session file `" + sessionFile + "` version `" + sV6Header.Version + "`.")
-		if e := os.Remove(sessionFile); e != nil {
+		// Files already removed by another mc process are skipped.
+		if e := os.Remove(sessionFile); e != nil && !os.IsNotExist(e) {
 			fatalIf(probe.NewError(e), "Unable to remove version `"+sV6Header.Version+"` session file `"+sessionFile+"`.")
 		}
-		if e := os.Remove(sessionDataFile); e != nil {
+		if e := os.Remove(sessionDataFile); e != nil && !os.IsNotExist(e) {
 			fatalIf(probe.NewError(e), "Unable to remove version `"+sV6Header.Version+"` session data file `"+sessionDataFile+"`.")
 		}
 	}
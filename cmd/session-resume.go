@@ -66,7 +66,15 @@ func sessionExecute(s *sessionV8) {
 		sseKeys := s.Header.CommandStringFlags["encrypt-key"]
 		sseServer := s.Header.CommandStringFlags["encrypt"]
 		encKeyDB, _ := parseAndValidateEncryptionKeys(sseKeys, sseServer)
-		doCopySession(s, encKeyDB)
+		if args := s.Header.CommandArgs; len(args) >= 2 {
+			rewindSources(s.Header.CommandStringFlags["rewind"], args[:len(args)-1]...)
+		}
+		doCopySession(s, encKeyDB, false)
+	case "mv":
+		sseKeys := s.Header.CommandStringFlags["encrypt-key"]
+		sseServer := s.Header.CommandStringFlags["encrypt"]
+		encKeyDB, _ := parseAndValidateEncryptionKeys(sseKeys, sseServer)
+		doCopySession(s, encKeyDB, true)
 	}
 }
 
@@ -0,0 +1,434 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+const sessionUploadsVersion = "1"
+
+// sessionUploadsFlushInterval - how often recorded parts are saved, a
+// part uploaded but not saved before a crash is listed from the server
+// on resume.
+const sessionUploadsFlushInterval = 5 * time.Second
+
+// sessionUploadPart - a part uploaded by a multipart upload.
+type sessionUploadPart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+}
+
+// sessionUpload - a multipart upload of a session, kept until it is
+// completed along with the source it uploads. A source which changed
+// since is uploaded again.
+type sessionUpload struct {
+	UploadID string              `json:"uploadId"`
+	Size     int64               `json:"size"`
+	ModTime  time.Time           `json:"lastModified"`
+	PartSize int64               `json:"partSize"`
+	Parts    []sessionUploadPart `json:"parts"`
+}
+
+// sessionUploads - multipart uploads in progress of a session by target
+// URL, saved in the session folder so that a resumed session only
+// uploads the parts missing from large objects. Started and removed
+// uploads are saved at once, parts at most every flush interval.
+type sessionUploads struct {
+	Version string                    `json:"version"`
+	Uploads map[string]*sessionUpload `json:"uploads"`
+
+	path    string
+	mutex   sync.Mutex
+	dirty   bool
+	flushed time.Time
+}
+
+// getSessionUploadsFile - get the multipart uploads file of a session.
+func getSessionUploadsFile(sid string) (string, *probe.Error) {
+	sessionDir, err := getSessionDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(sessionDir, sid+".uploads"), nil
+}
+
+// loadSessionUploads - load the multipart uploads of a session, an
+// unreadable file has no uploads and their parts are uploaded again.
+func loadSessionUploads(sid string) (*sessionUploads, *probe.Error) {
+	path, err := getSessionUploadsFile(sid)
+	if err != nil {
+		return nil, err.Trace(sid)
+	}
+	uploads := &sessionUploads{Version: sessionUploadsVersion, Uploads: map[string]*sessionUpload{}, path: path}
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		return uploads, nil
+	}
+	saved := &sessionUploads{}
+	if e = json.Unmarshal(data, saved); e == nil && saved.Version == sessionUploadsVersion && saved.Uploads != nil {
+		uploads.Uploads = saved.Uploads
+	}
+	return uploads, nil
+}
+
+// save - replace the uploads file. Must be called with the mutex held.
+func (s *sessionUploads) save() *probe.Error {
+	data, e := json.MarshalIndent(s, "", " ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	tmpFile, e := ioutil.TempFile(filepath.Dir(s.path), ".uploads-")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	_, e = tmpFile.Write(data)
+	if ce := tmpFile.Close(); e == nil {
+		e = ce
+	}
+	if e == nil {
+		e = os.Rename(tmpFile.Name(), s.path)
+	}
+	if e != nil {
+		os.Remove(tmpFile.Name())
+		return probe.NewError(e).Trace(s.path)
+	}
+	s.dirty = false
+	s.flushed = time.Now()
+	return nil
+}
+
+// flush - save the parts recorded since the last save.
+func (s *sessionUploads) flush() *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	return s.save()
+}
+
+// get - the upload of a target, nil if there is none.
+func (s *sessionUploads) get(targetURL string) *sessionUpload {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	upload, ok := s.Uploads[targetURL]
+	if !ok {
+		return nil
+	}
+	copied := *upload
+	copied.Parts = append([]sessionUploadPart(nil), upload.Parts...)
+	return &copied
+}
+
+// start - record a new upload of a target.
+func (s *sessionUploads) start(targetURL string, upload *sessionUpload) *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Uploads[targetURL] = &sessionUpload{
+		UploadID: upload.UploadID,
+		Size:     upload.Size,
+		ModTime:  upload.ModTime,
+		PartSize: upload.PartSize,
+	}
+	return s.save()
+}
+
+// addPart - record an uploaded part of the upload of a target.
+func (s *sessionUploads) addPart(targetURL string, part sessionUploadPart) *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	upload, ok := s.Uploads[targetURL]
+	if !ok {
+		return nil
+	}
+	upload.Parts = append(upload.Parts, part)
+	s.dirty = true
+	if time.Since(s.flushed) < sessionUploadsFlushInterval {
+		return nil
+	}
+	return s.save()
+}
+
+// remove - forget the upload of a target once completed or aborted.
+func (s *sessionUploads) remove(targetURL string) *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.Uploads[targetURL]; !ok {
+		return nil
+	}
+	delete(s.Uploads, targetURL)
+	return s.save()
+}
+
+// removeSessionUploads - abort the multipart uploads left by a session
+// and remove its uploads file. Their parts would be kept and billed.
+func removeSessionUploads(sid string) *probe.Error {
+	uploads, err := loadSessionUploads(sid)
+	if err != nil {
+		return err.Trace(sid)
+	}
+	for targetURL, upload := range uploads.Uploads {
+		clnt, err := newClient(targetURL)
+		if err != nil {
+			continue
+		}
+		if s3Clnt, ok := clnt.(*s3Client); ok {
+			bucket, object := s3Clnt.url2BucketAndObject()
+			core := minio.Core{Client: s3Clnt.api}
+			core.AbortMultipartUpload(bucket, object, upload.UploadID)
+		}
+	}
+	if e := os.Remove(uploads.path); e != nil && !os.IsNotExist(e) {
+		return probe.NewError(e).Trace(uploads.path)
+	}
+	return nil
+}
+
+// putTargetResumable - upload a source which can be read at any offset
+// to an S3 target in parts recorded in the session uploads. Other
+// targets, and objects below the multipart threshold, are uploaded with
+// a regular Put.
+func putTargetResumable(ctx context.Context, alias, urlStr, aliasedURL string, reader io.ReaderAt, size int64, modTime time.Time, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide, uploads *sessionUploads) (int64, *probe.Error) {
+	targetClnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return 0, err.Trace(alias, urlStr)
+	}
+	s3Clnt, ok := targetClnt.(*s3Client)
+	if !ok {
+		return putTargetStream(ctx, alias, urlStr, io.NewSectionReader(reader, 0, size), size, metadata, progress, sse)
+	}
+	partSize, isMultipart, err := s3Clnt.multipart.resumablePartSize(size)
+	if err != nil {
+		return 0, err.Trace(alias, urlStr)
+	}
+	if !isMultipart {
+		return putTargetStream(ctx, alias, urlStr, io.NewSectionReader(reader, 0, size), size, metadata, progress, sse)
+	}
+	n, err := s3Clnt.putResumable(ctx, reader, size, partSize, modTime, metadata, progress, sse, uploads, aliasedURL)
+	if err != nil {
+		return n, err.Trace(alias, urlStr)
+	}
+	return n, nil
+}
+
+// putResumable - upload an object in parts of partSize bytes, the parts
+// already uploaded by the recorded upload of the target are listed and
+// skipped. A cancelled or failed upload is kept to be resumed, it is
+// aborted when its session is removed.
+func (c *s3Client) putResumable(ctx context.Context, reader io.ReaderAt, size, partSize int64, modTime time.Time, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide, uploads *sessionUploads, targetURL string) (int64, *probe.Error) {
+	if err := c.checkWritable(); err != nil {
+		return 0, err
+	}
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	core := minio.Core{Client: c.api}
+
+	uploaded := map[int]string{}
+	upload := uploads.get(targetURL)
+	if upload != nil && (upload.Size != size || !upload.ModTime.Equal(modTime) || upload.PartSize != partSize) {
+		// The source changed since, its parts are stale.
+		core.AbortMultipartUpload(bucket, object, upload.UploadID)
+		if err := uploads.remove(targetURL); err != nil {
+			return 0, err.Trace(targetURL)
+		}
+		upload = nil
+	}
+	if upload != nil {
+		listed, e := listUploadedParts(core, bucket, object, upload.UploadID)
+		switch {
+		case e == nil:
+			// Parts are skipped when listed with the recorded ETag, parts
+			// uploaded but not recorded before a crash are listed only.
+			recorded := map[int]string{}
+			for _, part := range upload.Parts {
+				recorded[part.PartNumber] = part.ETag
+			}
+			for _, part := range listed {
+				etag := strings.Trim(part.ETag, "\"")
+				if recordedETag, ok := recorded[part.PartNumber]; ok && strings.Trim(recordedETag, "\"") != etag {
+					continue
+				}
+				if part.Size == uploadPartLength(size, partSize, part.PartNumber) {
+					uploaded[part.PartNumber] = part.ETag
+				}
+			}
+		case minio.ToErrorResponse(e).Code == "NoSuchUpload":
+			// Completed, aborted or expired since.
+			if err := uploads.remove(targetURL); err != nil {
+				return 0, err.Trace(targetURL)
+			}
+			upload = nil
+		default:
+			return 0, probe.NewError(e).Trace(targetURL)
+		}
+	}
+	if upload == nil {
+		opts := minio.PutObjectOptions{
+			UserMetadata:         make(map[string]string),
+			ServerSideEncryption: sse,
+		}
+		for k, v := range metadata {
+			switch k {
+			case "Content-Type":
+				opts.ContentType = v
+			case "X-Amz-Storage-Class":
+				opts.StorageClass = strings.ToUpper(v)
+			default:
+				opts.UserMetadata[k] = v
+			}
+		}
+		if opts.ContentType == "" {
+			opts.ContentType = "application/octet-stream"
+		}
+		uploadID, e := core.NewMultipartUpload(bucket, object, opts)
+		if e != nil {
+			return 0, probe.NewError(e).Trace(targetURL)
+		}
+		upload = &sessionUpload{UploadID: uploadID, Size: size, ModTime: modTime, PartSize: partSize}
+		if err := uploads.start(targetURL, upload); err != nil {
+			core.AbortMultipartUpload(bucket, object, uploadID)
+			return 0, err.Trace(targetURL)
+		}
+	}
+
+	totalParts := int((size + partSize - 1) / partSize)
+	parts := make([]minio.CompletePart, totalParts)
+	partCh := make(chan int)
+	errCh := make(chan *probe.Error, defaultMultipartThreadsNum)
+	var wg sync.WaitGroup
+	for i := 0; i < defaultMultipartThreadsNum; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNumber := range partCh {
+				length := uploadPartLength(size, partSize, partNumber)
+				section := io.NewSectionReader(reader, int64(partNumber-1)*partSize, length)
+				objectPart, e := core.PutObjectPart(bucket, object, upload.UploadID, partNumber,
+					hookreader.NewHook(section, progress), length, "", "", sse)
+				if e != nil {
+					errCh <- probe.NewError(e).Trace(targetURL)
+					return
+				}
+				parts[partNumber-1] = minio.CompletePart{PartNumber: partNumber, ETag: objectPart.ETag}
+				if err := uploads.addPart(targetURL, sessionUploadPart{PartNumber: partNumber, ETag: objectPart.ETag}); err != nil {
+					errCh <- err.Trace(targetURL)
+					return
+				}
+			}
+		}()
+	}
+
+	var skipped int64
+	for partNumber, etag := range uploaded {
+		if partNumber <= totalParts {
+			parts[partNumber-1] = minio.CompletePart{PartNumber: partNumber, ETag: etag}
+			skipped += uploadPartLength(size, partSize, partNumber)
+		}
+	}
+	if progress != nil && skipped > 0 {
+		io.CopyN(ioutil.Discard, progress, skipped)
+	}
+
+	var err *probe.Error
+send:
+	for partNumber := 1; partNumber <= totalParts; partNumber++ {
+		if parts[partNumber-1].PartNumber == partNumber {
+			continue
+		}
+		select {
+		case partCh <- partNumber:
+		case err = <-errCh:
+			break send
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(partCh)
+	wg.Wait()
+	// Keep the parts of an upload left to be resumed.
+	if flushErr := uploads.flush(); flushErr != nil && err == nil {
+		err = flushErr.Trace(targetURL)
+	}
+	if err == nil {
+		select {
+		case err = <-errCh:
+		default:
+		}
+	}
+	if ctx.Err() != nil || globalContext.Err() != nil {
+		return 0, errCancelled().Trace(c.targetURL.String())
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	if _, e := core.CompleteMultipartUpload(bucket, object, upload.UploadID, parts); e != nil {
+		// A rejected part list is not resumable.
+		core.AbortMultipartUpload(bucket, object, upload.UploadID)
+		uploads.remove(targetURL)
+		return 0, probe.NewError(e).Trace(targetURL)
+	}
+	if err := uploads.remove(targetURL); err != nil {
+		return size, err.Trace(targetURL)
+	}
+	return size, nil
+}
+
+// listUploadedParts - list all parts of a multipart upload.
+func listUploadedParts(core minio.Core, bucket, object, uploadID string) ([]minio.ObjectPart, error) {
+	var parts []minio.ObjectPart
+	partNumberMarker := 0
+	for {
+		result, e := core.ListObjectParts(bucket, object, uploadID, partNumberMarker, 1000)
+		if e != nil {
+			return nil, e
+		}
+		parts = append(parts, result.ObjectParts...)
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+}
+
+// uploadPartLength - length of a part of an upload, the last part holds
+// the remainder.
+func uploadPartLength(size, partSize int64, partNumber int) int64 {
+	offset := int64(partNumber-1) * partSize
+	if size-offset < partSize {
+		return size - offset
+	}
+	return partSize
+}
@@ -0,0 +1,209 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	. "gopkg.in/check.v1"
+)
+
+// resumeHandler - fake S3 server of a multipart upload, some of its
+// parts were uploaded before a restart.
+type resumeHandler struct {
+	mutex     sync.Mutex
+	parts     map[int]string
+	sizes     map[int]int64
+	put       []int
+	completed []int
+}
+
+func (h *resumeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	_, isLocation := query["location"]
+	switch {
+	case isLocation:
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`)
+	case r.Method == http.MethodGet && query.Get("uploadId") == "restarted":
+		fmt.Fprint(w, `<ListPartsResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>restarted</UploadId><IsTruncated>false</IsTruncated>`)
+		var numbers []int
+		for number := range h.parts {
+			numbers = append(numbers, number)
+		}
+		sort.Ints(numbers)
+		for _, number := range numbers {
+			fmt.Fprintf(w, `<Part><PartNumber>%d</PartNumber><ETag>"%s"</ETag><Size>%d</Size></Part>`, number, h.parts[number], h.sizes[number])
+		}
+		fmt.Fprint(w, `</ListPartsResult>`)
+	case r.Method == http.MethodPut && query.Get("uploadId") == "restarted":
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		n, _ := io.Copy(ioutil.Discard, r.Body)
+		h.put = append(h.put, number)
+		h.parts[number] = fmt.Sprintf("uploaded%d", number)
+		h.sizes[number] = n
+		w.Header().Set("ETag", `"`+h.parts[number]+`"`)
+	case r.Method == http.MethodPost && query.Get("uploadId") == "restarted":
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		xml.NewDecoder(r.Body).Decode(&complete)
+		for _, part := range complete.Parts {
+			h.completed = append(h.completed, part.PartNumber)
+		}
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"done-3"</ETag></CompleteMultipartUploadResult>`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func (s *TestSuite) TestPutResumable(c *C) {
+	handler := &resumeHandler{
+		parts: map[int]string{1: "uploaded1", 3: "uploaded3"},
+		sizes: map[int]int64{1: 5 * humanize.MiByte, 3: 2 * humanize.MiByte},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	tmp, e := ioutil.TempDir("", "session-uploads-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(tmp)
+
+	size := int64(12 * humanize.MiByte)
+	modTime := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	// Part 3 was uploaded with other data before the restart.
+	uploads := &sessionUploads{
+		Version: sessionUploadsVersion,
+		path:    filepath.Join(tmp, "session.uploads"),
+		Uploads: map[string]*sessionUpload{
+			"target/bucket/object": {
+				UploadID: "restarted",
+				Size:     size,
+				ModTime:  modTime,
+				PartSize: 5 * humanize.MiByte,
+				Parts:    []sessionUploadPart{{1, "uploaded1"}, {3, "changed"}},
+			},
+		},
+	}
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	conf.Multipart = multipartConfig{PartSize: 5 * humanize.MiByte}
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := clnt.(*s3Client)
+
+	partSize, isMultipart, err := s3Clnt.multipart.resumablePartSize(size)
+	c.Assert(err, IsNil)
+	c.Assert(isMultipart, Equals, true)
+	c.Assert(partSize, Equals, int64(5*humanize.MiByte))
+
+	reader := bytes.NewReader(make([]byte, size))
+	n, err := s3Clnt.putResumable(context.Background(), reader, size, partSize, modTime, map[string]string{}, nil, nil, uploads, "target/bucket/object")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, size)
+
+	sort.Ints(handler.put)
+	c.Assert(handler.put, DeepEquals, []int{2, 3})
+	c.Assert(handler.completed, DeepEquals, []int{1, 2, 3})
+	c.Assert(uploads.Uploads, HasLen, 0)
+
+	saved, e := ioutil.ReadFile(uploads.path)
+	c.Assert(e, IsNil)
+	c.Assert(string(saved), Not(Matches), ".*restarted.*")
+}
+
+func (s *TestSuite) TestResumablePartSize(c *C) {
+	testCases := []struct {
+		config      multipartConfig
+		size        int64
+		partSize    int64
+		isMultipart bool
+	}{
+		{multipartConfig{}, 100 * humanize.MiByte, 0, false},
+		{multipartConfig{}, 1 * humanize.GiByte, 64 * humanize.MiByte, true},
+		{multipartConfig{}, 1 * humanize.TiByte, 128 * humanize.MiByte, true},
+		{multipartConfig{PartSize: 16 * humanize.MiByte}, 20 * humanize.MiByte, 16 * humanize.MiByte, true},
+		{multipartConfig{Threshold: 32 * humanize.MiByte}, 20 * humanize.MiByte, 0, false},
+		{multipartConfig{Disabled: true}, 1 * humanize.GiByte, 0, false},
+	}
+	for i, testCase := range testCases {
+		partSize, isMultipart, err := testCase.config.resumablePartSize(testCase.size)
+		c.Assert(err, IsNil, Commentf("Test %d", i+1))
+		c.Assert(partSize, Equals, testCase.partSize, Commentf("Test %d", i+1))
+		c.Assert(isMultipart, Equals, testCase.isMultipart, Commentf("Test %d", i+1))
+	}
+}
+
+func (s *TestSuite) TestSessionUploadsFlush(c *C) {
+	tmp, e := ioutil.TempDir("", "session-uploads-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(tmp)
+
+	uploads := &sessionUploads{
+		Version: sessionUploadsVersion,
+		path:    filepath.Join(tmp, "session.uploads"),
+		Uploads: map[string]*sessionUpload{},
+	}
+	loadParts := func() []sessionUploadPart {
+		data, e := ioutil.ReadFile(uploads.path)
+		c.Assert(e, IsNil)
+		saved := &sessionUploads{}
+		c.Assert(json.Unmarshal(data, saved), IsNil)
+		c.Assert(saved.Uploads["target/bucket/object"], NotNil)
+		return saved.Uploads["target/bucket/object"].Parts
+	}
+
+	c.Assert(uploads.start("target/bucket/object", &sessionUpload{UploadID: "id", PartSize: 5 * humanize.MiByte}), IsNil)
+	c.Assert(loadParts(), HasLen, 0)
+
+	// Parts recorded right after a save are kept until the next flush.
+	for partNumber := 1; partNumber <= 100; partNumber++ {
+		c.Assert(uploads.addPart("target/bucket/object", sessionUploadPart{partNumber, "etag"}), IsNil)
+	}
+	c.Assert(loadParts(), HasLen, 0)
+
+	c.Assert(uploads.flush(), IsNil)
+	c.Assert(loadParts(), HasLen, 100)
+
+	// Parts recorded once the flush interval elapsed are saved at once.
+	uploads.flushed = time.Now().Add(-sessionUploadsFlushInterval)
+	c.Assert(uploads.addPart("target/bucket/object", sessionUploadPart{101, "etag"}), IsNil)
+	c.Assert(loadParts(), HasLen, 101)
+}
@@ -236,6 +236,12 @@ func (s *sessionV8) Save() *probe.Error {
 	if err != nil {
 		return err.Trace(s.SessionID)
 	}
+
+	lock, err := lockMcConfig()
+	if err != nil {
+		return err.Trace(s.SessionID)
+	}
+	defer lock.Release()
 	e = qs.Save(sessionFile)
 	if e != nil {
 		return probe.NewError(e).Trace(sessionFile)
@@ -247,21 +253,55 @@ func (s *sessionV8) Save() *probe.Error {
 // Used by newSession.
 func (s *sessionV8) setGlobals() {
 	s.Header.GlobalBoolFlags["quiet"] = globalQuiet
+	s.Header.GlobalBoolFlags["noProgress"] = globalNoProgress
 	s.Header.GlobalBoolFlags["debug"] = globalDebug
 	s.Header.GlobalBoolFlags["json"] = globalJSON
 	s.Header.GlobalBoolFlags["noColor"] = globalNoColor
 	s.Header.GlobalBoolFlags["insecure"] = globalInsecure
+	s.Header.GlobalBoolFlags["readOnly"] = globalReadOnly
+	s.Header.GlobalBoolFlags["noHumanize"] = globalNoHumanize
+	s.Header.GlobalStringFlags["proxy"] = globalProxy
+	s.Header.GlobalStringFlags["traceFile"] = globalTraceFile
+	s.Header.GlobalBoolFlags["requesterPays"] = globalRequesterPays
+	s.Header.GlobalStringFlags["headers"] = strings.Join(formatHTTPHeaders(globalHeaders), "\n")
+	s.Header.GlobalStringFlags["output"] = globalOutput
+	s.Header.GlobalStringFlags["timeZone"] = globalTimeZone.String()
+	s.Header.GlobalIntFlags["fsBufferSize"] = globalFSBufferSize
+	s.Header.GlobalBoolFlags["fsDirect"] = globalFSDirect
+	s.Header.GlobalBoolFlags["windowsCompat"] = globalWindowsCompat
+	s.Header.GlobalIntFlags["partSize"] = int(globalMultipart.PartSize)
+	s.Header.GlobalIntFlags["multipartThreshold"] = int(globalMultipart.Threshold)
+	s.Header.GlobalBoolFlags["disableMultipart"] = globalMultipart.Disabled
 }
 
 // RestoreGlobals restores the state of global variables.
 // Used by resumeSession.
 func (s sessionV8) restoreGlobals() {
 	quiet := s.Header.GlobalBoolFlags["quiet"]
+	noProgress := s.Header.GlobalBoolFlags["noProgress"]
 	debug := s.Header.GlobalBoolFlags["debug"]
 	json := s.Header.GlobalBoolFlags["json"]
 	noColor := s.Header.GlobalBoolFlags["noColor"]
 	insecure := s.Header.GlobalBoolFlags["insecure"]
-	setGlobals(quiet, debug, json, noColor, insecure)
+	readOnly := s.Header.GlobalBoolFlags["readOnly"]
+	noHumanize := s.Header.GlobalBoolFlags["noHumanize"]
+	proxy := s.Header.GlobalStringFlags["proxy"]
+	traceFile := s.Header.GlobalStringFlags["traceFile"]
+	output := s.Header.GlobalStringFlags["output"]
+	timeZone := s.Header.GlobalStringFlags["timeZone"]
+	setGlobals(quiet, noProgress, debug, json, noColor, insecure, readOnly, noHumanize, proxy, traceFile, output, timeZone)
+	globalRequesterPays = globalRequesterPays || s.Header.GlobalBoolFlags["requesterPays"]
+	if headers := s.Header.GlobalStringFlags["headers"]; headers != "" {
+		if h, err := parseHTTPHeaders(strings.Split(headers, "\n")); err == nil {
+			setHeaderGlobals(h)
+		}
+	}
+	setFSGlobals(s.Header.GlobalIntFlags["fsBufferSize"], s.Header.GlobalBoolFlags["fsDirect"], s.Header.GlobalBoolFlags["windowsCompat"])
+	setMultipartGlobals(multipartConfig{
+		PartSize:  uint64(s.Header.GlobalIntFlags["partSize"]),
+		Threshold: uint64(s.Header.GlobalIntFlags["multipartThreshold"]),
+		Disabled:  s.Header.GlobalBoolFlags["disableMultipart"],
+	})
 }
 
 // IsModified - returns if in memory session header has changed from
@@ -301,6 +341,12 @@ func (s *sessionV8) save() *probe.Error {
 		return err.Trace(s.SessionID)
 	}
 
+	lock, err := lockMcConfig()
+	if err != nil {
+		return err.Trace(s.SessionID)
+	}
+	defer lock.Release()
+
 	// Verify if sessionFile is modified.
 	modified, err := s.isModified(sessionFile)
 	if err != nil {
@@ -339,6 +385,12 @@ func (s *sessionV8) Delete() *probe.Error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	lock, err := lockMcConfig()
+	if err != nil {
+		return err.Trace(s.SessionID)
+	}
+	defer lock.Release()
+
 	if s.DataFP != nil {
 		name := s.DataFP.Name()
 		// close file pro-actively before deleting
@@ -366,6 +418,9 @@ func (s *sessionV8) Delete() *probe.Error {
 	// Remove session backup file if any, ignore any error.
 	os.Remove(sessionFile + ".old")
 
+	// Abort the multipart uploads left unfinished.
+	removeSessionUploads(s.SessionID)
+
 	return nil
 }
 
@@ -375,6 +430,13 @@ func (s sessionV8) CloseAndDie() {
 	console.Fatalln("Session safely terminated. To resume session `mc session resume " + s.SessionID + "`")
 }
 
+// CloseAndInterrupt - close an interrupted session and exit with
+// globalInterruptExitStatus.
+func (s sessionV8) CloseAndInterrupt() {
+	s.Close()
+	console.FatalStatusln(globalInterruptExitStatus, "Session safely terminated. To resume session `mc session resume "+s.SessionID+"`")
+}
+
 // Create a factory function to simplify checking if
 // object was last operated on.
 func isLastFactory(lastURL string) func(string) bool {
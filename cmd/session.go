@@ -136,3 +136,18 @@ func removeSessionDataFile(sid string) {
 	}
 	os.Remove(dataFile)
 }
+
+// removeSession - remove a saved session, its data and the multipart
+// uploads it left. A session which cannot be loaded is removed file by
+// file, forced is true then.
+func removeSession(sid string, session *sessionV8) (forced bool) {
+	if session != nil {
+		if err := session.Delete().Trace(sid); err == nil {
+			return false
+		}
+	}
+	removeSessionFile(sid)
+	removeSessionDataFile(sid)
+	removeSessionUploads(sid)
+	return true
+}
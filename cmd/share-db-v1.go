@@ -38,6 +38,14 @@ type shareDBV1 struct {
 	Version string `json:"version"`
 	mutex   *sync.Mutex
 
+	// Number of expired entries dropped by Load.
+	expired int
+
+	// Shares loaded and unchanged since, and shares deleted since
+	// loading, neither are saved again when merging with the shares
+	// saved by other mc processes.
+	loaded, deleted map[string]bool
+
 	// key is unique share URL.
 	Shares map[string]shareEntryV1 `json:"shares"`
 }
@@ -48,6 +56,8 @@ func newShareDBV1() *shareDBV1 {
 		Version: "1",
 	}
 	s.Shares = make(map[string]shareEntryV1)
+	s.loaded = make(map[string]bool)
+	s.deleted = make(map[string]bool)
 	s.mutex = &sync.Mutex{}
 	return s
 }
@@ -57,6 +67,7 @@ func (s *shareDBV1) Set(objectURL string, shareURL string, expiry time.Duration,
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	delete(s.loaded, shareURL)
 	s.Shares[shareURL] = shareEntryV1{
 		URL:         objectURL,
 		Date:        UTCNow(),
@@ -71,16 +82,20 @@ func (s *shareDBV1) Delete(objectURL string) {
 	defer s.mutex.Unlock()
 
 	delete(s.Shares, objectURL)
+	s.deleted[objectURL] = true
 }
 
-// Delete all expired uploads.
-func (s *shareDBV1) deleteAllExpired() {
+// Delete all expired uploads, returns the number of deleted entries.
+func (s *shareDBV1) deleteAllExpired() (expired int) {
 	for shareURL, share := range s.Shares {
 		if (share.Expiry - time.Since(share.Date)) <= 0 {
 			// Expired entry. Safe to drop.
 			delete(s.Shares, shareURL)
+			s.deleted[shareURL] = true
+			expired++
 		}
 	}
+	return expired
 }
 
 // Load shareDB entries from disk. Any entries held in memory are reset.
@@ -106,30 +121,59 @@ func (s *shareDBV1) Load(filename string) *probe.Error {
 	// Copy map over.
 	for k, v := range qs.Data().(*shareDBV1).Shares {
 		s.Shares[k] = v
+		s.loaded[k] = true
 	}
 
 	// Filter out expired entries and save changes back to disk.
-	s.deleteAllExpired()
+	s.expired = s.deleteAllExpired()
 	s.save(filename)
 
 	return nil
 }
 
-// Persist share uploads to disk.
-func (s shareDBV1) save(filename string) *probe.Error {
+// Persist share uploads to disk, merged with the shares saved by other
+// mc processes since loading. The config folder is locked meanwhile.
+func (s *shareDBV1) save(filename string) *probe.Error {
+	lock, err := lockMcConfig()
+	if err != nil {
+		return err.Trace(filename)
+	}
+	defer lock.Release()
+
+	merged := newShareDBV1()
+	if _, e := os.Stat(filename); e == nil {
+		if _, e = quick.LoadConfig(filename, nil, merged); e != nil {
+			return probe.NewError(e).Trace(filename)
+		}
+	}
+	for shareURL := range s.deleted {
+		delete(merged.Shares, shareURL)
+	}
+	for shareURL, share := range s.Shares {
+		if !s.loaded[shareURL] {
+			merged.Shares[shareURL] = share
+		}
+	}
+
 	// Initialize a new quick file.
-	qs, e := quick.NewConfig(s, nil)
+	qs, e := quick.NewConfig(merged, nil)
 	if e != nil {
 		return probe.NewError(e).Trace(filename)
 	}
 	if e := qs.Save(filename); e != nil {
 		return probe.NewError(e).Trace(filename)
 	}
+	// All shares are saved now, as if loaded again.
+	s.Shares, s.deleted = merged.Shares, make(map[string]bool)
+	s.loaded = make(map[string]bool)
+	for shareURL := range s.Shares {
+		s.loaded[shareURL] = true
+	}
 	return nil
 }
 
 // Persist share uploads to disk.
-func (s shareDBV1) Save(filename string) *probe.Error {
+func (s *shareDBV1) Save(filename string) *probe.Error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
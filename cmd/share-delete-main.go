@@ -0,0 +1,116 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var (
+	shareDeleteFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "share removal of all objects recursively",
+		},
+		shareFlagExpire,
+		shareFlagParam,
+	}
+)
+
+// Share removal of objects via URL.
+var shareDelete = cli.Command{
+	Name:   "delete",
+	Usage:  "generate URLs for removal of objects",
+	Action: mainShareDelete,
+	Before: setGlobalsFromMutatingContext,
+	Flags:  append(shareDeleteFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   A DELETE URL removes a single object, anyone holding it may remove the
+   object until the URL expires. DELETE URLs are not saved, they are not
+   listed by 'mc share list'. They are refused in read-only mode.
+
+DEFAULT EXPIRY:
+   Without '--expire' the expiry saved with the alias by 'mc config host add
+   --share-expire' is used, 7 days otherwise.
+
+EXAMPLES:
+   1. Let a cleanup job remove this object within the next hour.
+      $ {{.HelpName}} --expire=1h s3/uploads/tmp/upload.part
+
+   2. Share the removal of every object under this folder, one URL per object.
+      $ {{.HelpName}} --recursive --expire=2h s3/uploads/tmp/
+
+   3. Share the removal of a single version of this object.
+      $ {{.HelpName}} --param versionId=3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY s3/backup/2006-Mar-1/backup.tar.gz
+
+`,
+}
+
+// checkShareDeleteSyntax - validate command-line args.
+func checkShareDeleteSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "delete", 1) // last argument is exit code.
+	}
+	if ctx.IsSet("expire") {
+		_, err := parseShareExpiry(ctx.String("expire"))
+		fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
+	}
+}
+
+// main for share delete.
+func mainShareDelete(ctx *cli.Context) error {
+	checkShareDeleteSyntax(ctx)
+
+	// Additional command speific theme customization.
+	shareSetColor()
+
+	var expiry time.Duration
+	if ctx.IsSet("expire") {
+		var err *probe.Error
+		expiry, err = parseShareExpiry(ctx.String("expire"))
+		fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
+	}
+	reqParams := make(url.Values)
+	fatalIf(parseShareParams(reqParams, ctx.StringSlice("param")), "Invalid query parameter.")
+
+	for _, targetURL := range ctx.Args() {
+		err := doShareRequestURL(http.MethodDelete, targetURL, ctx.Bool("recursive"), expiry, reqParams)
+		if err != nil {
+			switch err.ToGoError().(type) {
+			case APINotImplemented:
+				fatalIf(err.Trace(), "Unable to share a non S3 url `"+targetURL+"`.")
+			default:
+				fatalIf(err.Trace(targetURL), "Unable to share target `"+targetURL+"`.")
+			}
+		}
+	}
+	return nil
+}
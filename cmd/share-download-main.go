@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"net/url"
 	"strings"
 	"time"
 
@@ -31,9 +32,33 @@ var (
 			Usage: "share all objects recursively",
 		},
 		shareFlagExpire,
+		cli.StringFlag{
+			Name:  "content-disposition",
+			Usage: "override the Content-Disposition header of the download",
+		},
+		cli.StringFlag{
+			Name:  "content-type, T",
+			Usage: "override the Content-Type header of the download",
+		},
+		cli.StringSliceFlag{
+			Name:  "response-header",
+			Usage: "override a response header of the download in KEY=VALUE format",
+		},
+		shareFlagParam,
 	}
 )
 
+// Response headers which may be overridden by a presigned download URL,
+// mapped to the query parameter naming them.
+var shareResponseHeaders = map[string]string{
+	"cache-control":       "response-cache-control",
+	"content-disposition": "response-content-disposition",
+	"content-encoding":    "response-content-encoding",
+	"content-language":    "response-content-language",
+	"content-type":        "response-content-type",
+	"expires":             "response-expires",
+}
+
 // Share documents via URL.
 var shareDownload = cli.Command{
 	Name:   "download",
@@ -50,6 +75,14 @@ USAGE:
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
+RESPONSE HEADERS:
+   Cache-Control, Content-Disposition, Content-Encoding, Content-Language,
+   Content-Type and Expires may be overridden by '--response-header'.
+
+DEFAULT EXPIRY:
+   Without '--expire' the expiry saved with the alias by 'mc config host add
+   --share-expire' is used, 7 days otherwise.
+
 EXAMPLES:
    1. Share this object with 7 days default expiry.
       $ {{.HelpName}} s3/backup/2006-Mar-1/backup.tar.gz
@@ -63,9 +96,51 @@ EXAMPLES:
    4. Share all objects under this bucket and all its folders and sub-folders with 5 days expiry.
       $ {{.HelpName}} --recursive --expire=120h s3/backup/
 
+   5. Share this object to be saved as "backup.tar.gz" by browsers.
+      $ {{.HelpName}} --content-disposition 'attachment; filename="backup.tar.gz"' s3/backup/2006-Mar-1/backup.tar.gz
+
+   6. Share this object to be played by browsers and not cached.
+      $ {{.HelpName}} --content-type video/mp4 --response-header "Cache-Control=no-store" s3/videos/intro.mp4
+
+   7. Share a previous version of this object.
+      $ {{.HelpName}} --param versionId=3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY s3/backup/2006-Mar-1/backup.tar.gz
+
 `,
 }
 
+// parseShareResponseHeaders - build the query parameters of a presigned
+// download URL overriding response headers.
+func parseShareResponseHeaders(contentDisposition, contentType string, headers []string) (url.Values, *probe.Error) {
+	reqParams := make(url.Values)
+	for _, header := range headers {
+		kv := strings.SplitN(header, "=", 2)
+		if len(kv) != 2 {
+			return nil, errInvalidArgument().Trace(header)
+		}
+		param, ok := shareResponseHeaders[strings.ToLower(strings.TrimSpace(kv[0]))]
+		if !ok {
+			return nil, errInvalidArgument().Trace(header)
+		}
+		reqParams.Set(param, kv[1])
+	}
+	if contentDisposition != "" {
+		reqParams.Set("response-content-disposition", contentDisposition)
+	}
+	if contentType != "" {
+		reqParams.Set("response-content-type", contentType)
+	}
+	return reqParams, nil
+}
+
+// getShareDownloadExpiry - default expiry of an alias, 7 days unless
+// configured with 'mc config host add --share-expire'.
+func getShareDownloadExpiry(hostCfg *hostConfigV9) (time.Duration, *probe.Error) {
+	if hostCfg == nil || hostCfg.ShareExpiry == "" {
+		return shareDefaultExpiry, nil
+	}
+	return parseShareExpiry(hostCfg.ShareExpiry)
+}
+
 // checkShareDownloadSyntax - validate command-line args.
 func checkShareDownloadSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	args := ctx.Args()
@@ -73,28 +148,21 @@ func checkShareDownloadSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEP
 		cli.ShowCommandHelpAndExit(ctx, "download", 1) // last argument is exit code.
 	}
 
-	// Parse expiry.
-	expiry := shareDefaultExpiry
-	expireArg := ctx.String("expire")
-	if expireArg != "" {
-		var e error
-		expiry, e = time.ParseDuration(expireArg)
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+expireArg+"`.")
-	}
-
 	// Validate expiry.
-	if expiry.Seconds() < 1 {
-		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be lesser than 1 second.")
-	}
-	if expiry.Seconds() > 604800 {
-		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be larger than 7 days.")
+	if ctx.IsSet("expire") {
+		_, err := parseShareExpiry(ctx.String("expire"))
+		fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
 	}
 
+	// Validate response header overrides.
+	_, err := parseShareResponseHeaders(ctx.String("content-disposition"), ctx.String("content-type"), ctx.StringSlice("response-header"))
+	fatalIf(err, "Invalid response header, valid headers are `[Cache-Control, Content-Disposition, Content-Encoding, Content-Language, Content-Type, Expires]`.")
+
 	// Validate if object exists only if the `--recursive` flag was NOT specified
 	isRecursive := ctx.Bool("recursive")
 	if !isRecursive {
 		for _, url := range ctx.Args() {
-			_, _, err := url2Stat(url, false, encKeyDB)
+			_, _, err := url2Stat(globalContext, url, false, encKeyDB)
 			if err != nil {
 				fatalIf(err.Trace(url), "Unable to stat `"+url+"`.")
 			}
@@ -102,17 +170,19 @@ func checkShareDownloadSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEP
 	}
 }
 
-// doShareURL share files from target.
-func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration) *probe.Error {
-	targetAlias, targetURLFull, _, err := expandAlias(targetURL)
+// doShareURL share files from target, the default expiry of the target
+// alias is used for a zero expiry.
+func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration, reqParams url.Values) *probe.Error {
+	targetAlias, targetURLFull, hostCfg, err := expandAlias(targetURL)
 	if err != nil {
 		return err.Trace(targetURL)
 	}
-	clnt, err := newClientFromAlias(targetAlias, targetURLFull)
-	if err != nil {
-		return err.Trace(targetURL)
+	if expiry == 0 {
+		expiry, err = getShareDownloadExpiry(hostCfg)
+		if err != nil {
+			return err.Trace(targetAlias)
+		}
 	}
-
 	// Load previously saved upload-shares. Add new entries and write it back.
 	shareDB := newShareDBV1()
 	shareDownloadsFile := getShareDownloadsFile()
@@ -122,69 +192,22 @@ func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration
 	}
 
 	// Generate share URL for each target.
-	isIncomplete := false
-	isFetchMeta := false
-	// Channel which will receive objects whose URLs need to be shared
-	objectsCh := make(chan *clientContent)
-
-	content, err := clnt.Stat(isIncomplete, isFetchMeta, nil)
-	if err != nil {
-		return err.Trace(clnt.GetURL().String())
-	}
-
-	if !content.Type.IsDir() {
-		go func() {
-			defer close(objectsCh)
-			objectsCh <- content
-		}()
-	} else {
-		if !strings.HasSuffix(targetURLFull, string(clnt.GetURL().Separator)) {
-			targetURLFull = targetURLFull + string(clnt.GetURL().Separator)
-		}
-		clnt, err = newClientFromAlias(targetAlias, targetURLFull)
-		if err != nil {
-			return err.Trace(targetURLFull)
-		}
-		// Recursive mode: Share list of objects
-		go func() {
-			defer close(objectsCh)
-			for content := range clnt.List(isRecursive, isIncomplete, DirNone) {
-				objectsCh <- content
-			}
-		}()
-	}
-
-	// Iterate over all objects to generate share URL
-	for content := range objectsCh {
-		if content.Err != nil {
-			return content.Err.Trace(clnt.GetURL().String())
-		}
-		// if any incoming directories, we don't need to calculate.
-		if content.Type.IsDir() {
-			continue
-		}
-		objectURL := content.URL.String()
-		newClnt, err := newClientFromAlias(targetAlias, objectURL)
-		if err != nil {
-			return err.Trace(objectURL)
-		}
-
-		// Generate share URL.
-		shareURL, err := newClnt.ShareDownload(expiry)
+	err = forEachShareObject(targetAlias, targetURLFull, isRecursive, func(clnt Client) *probe.Error {
+		objectURL := clnt.GetURL().String()
+		shareURL, err := clnt.ShareDownload(expiry, reqParams)
 		if err != nil {
 			// add objectURL and expiry as part of the trace arguments.
 			return err.Trace(objectURL, "expiry="+expiry.String())
 		}
 
 		// Make new entries to shareDB.
-		contentType := "" // Not useful for download shares.
+		contentType := reqParams.Get("response-content-type")
 		shareDB.Set(objectURL, shareURL, expiry, contentType)
-		printMsg(shareMesssage{
-			ObjectURL:   objectURL,
-			ShareURL:    shareURL,
-			TimeLeft:    expiry,
-			ContentType: contentType,
-		})
+		printMsg(newShareMessage(objectURL, shareURL, UTCNow(), expiry, contentType))
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Save downloads and return.
@@ -208,15 +231,17 @@ func mainShareDownload(ctx *cli.Context) error {
 
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
-	expiry := shareDefaultExpiry
-	if ctx.String("expire") != "" {
-		var e error
-		expiry, e = time.ParseDuration(ctx.String("expire"))
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+ctx.String("expire")+"`.")
+	var expiry time.Duration
+	if ctx.IsSet("expire") {
+		expiry, err = parseShareExpiry(ctx.String("expire"))
+		fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
 	}
+	reqParams, err := parseShareResponseHeaders(ctx.String("content-disposition"), ctx.String("content-type"), ctx.StringSlice("response-header"))
+	fatalIf(err, "Invalid response header.")
+	fatalIf(parseShareParams(reqParams, ctx.StringSlice("param")), "Invalid query parameter.")
 
 	for _, targetURL := range ctx.Args() {
-		err := doShareDownloadURL(targetURL, isRecursive, expiry)
+		err := doShareDownloadURL(targetURL, isRecursive, expiry, reqParams)
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case APINotImplemented:
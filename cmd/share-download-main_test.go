@@ -0,0 +1,87 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseShareResponseHeaders(t *testing.T) {
+	testCases := []struct {
+		contentDisposition string
+		contentType        string
+		headers            []string
+		reqParams          url.Values
+		success            bool
+	}{
+		{"", "", nil, url.Values{}, true},
+		{"attachment", "video/mp4", nil, url.Values{
+			"response-content-disposition": {"attachment"},
+			"response-content-type":        {"video/mp4"},
+		}, true},
+		{"", "", []string{"Cache-Control=no-store", "content-language=sv"}, url.Values{
+			"response-cache-control":    {"no-store"},
+			"response-content-language": {"sv"},
+		}, true},
+		// Values may contain '='.
+		{"", "", []string{"Content-Disposition=attachment; filename=\"a=b.txt\""}, url.Values{
+			"response-content-disposition": {"attachment; filename=\"a=b.txt\""},
+		}, true},
+		// Dedicated flags win over generic overrides.
+		{"", "text/plain", []string{"Content-Type=text/html"}, url.Values{
+			"response-content-type": {"text/plain"},
+		}, true},
+		{"", "", []string{"Cache-Control"}, nil, false},
+		{"", "", []string{"X-Amz-Meta-Foo=bar"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		reqParams, err := parseShareResponseHeaders(testCase.contentDisposition, testCase.contentType, testCase.headers)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if testCase.success && !reflect.DeepEqual(reqParams, testCase.reqParams) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.reqParams, reqParams)
+		}
+	}
+}
+
+func TestGetShareDownloadExpiry(t *testing.T) {
+	testCases := []struct {
+		hostCfg *hostConfigV9
+		expiry  time.Duration
+		success bool
+	}{
+		{nil, shareDefaultExpiry, true},
+		{&hostConfigV9{}, shareDefaultExpiry, true},
+		{&hostConfigV9{ShareExpiry: "24h"}, 24 * time.Hour, true},
+		{&hostConfigV9{ShareExpiry: "0s"}, 0, false},
+		{&hostConfigV9{ShareExpiry: "169h"}, 0, false},
+		{&hostConfigV9{ShareExpiry: "1d"}, 0, false},
+	}
+	for i, testCase := range testCases {
+		expiry, err := getShareDownloadExpiry(testCase.hostCfg)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if expiry != testCase.expiry {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expiry, expiry)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var (
+	shareHeadFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "share all objects recursively",
+		},
+		shareFlagExpire,
+		shareFlagParam,
+	}
+)
+
+// Share object metadata via URL.
+var shareHead = cli.Command{
+	Name:   "head",
+	Usage:  "generate URLs for access to object metadata",
+	Action: mainShareHead,
+	Before: setGlobalsFromContext,
+	Flags:  append(shareHeadFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   A HEAD URL tells whether an object exists, its size, type and metadata,
+   without granting access to its data. HEAD URLs are not saved, they are
+   not listed by 'mc share list'.
+
+DEFAULT EXPIRY:
+   Without '--expire' the expiry saved with the alias by 'mc config host add
+   --share-expire' is used, 7 days otherwise.
+
+EXAMPLES:
+   1. Share the metadata of this object with 10 minutes expiry.
+      $ {{.HelpName}} --expire=10m s3/backup/2006-Mar-1/backup.tar.gz
+
+   2. Share the metadata of all objects under this bucket, for a monitoring job to check.
+      $ {{.HelpName}} --recursive --expire=24h s3/backup/
+
+   3. Share the metadata of a previous version of this object.
+      $ {{.HelpName}} --param versionId=3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY s3/backup/2006-Mar-1/backup.tar.gz
+
+`,
+}
+
+// checkShareHeadSyntax - validate command-line args.
+func checkShareHeadSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "head", 1) // last argument is exit code.
+	}
+	if ctx.IsSet("expire") {
+		_, err := parseShareExpiry(ctx.String("expire"))
+		fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
+	}
+}
+
+// main for share head.
+func mainShareHead(ctx *cli.Context) error {
+	checkShareHeadSyntax(ctx)
+
+	// Additional command speific theme customization.
+	shareSetColor()
+
+	var expiry time.Duration
+	if ctx.IsSet("expire") {
+		var err *probe.Error
+		expiry, err = parseShareExpiry(ctx.String("expire"))
+		fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
+	}
+	reqParams := make(url.Values)
+	fatalIf(parseShareParams(reqParams, ctx.StringSlice("param")), "Invalid query parameter.")
+
+	for _, targetURL := range ctx.Args() {
+		err := doShareRequestURL(http.MethodHead, targetURL, ctx.Bool("recursive"), expiry, reqParams)
+		if err != nil {
+			switch err.ToGoError().(type) {
+			case APINotImplemented:
+				fatalIf(err.Trace(), "Unable to share a non S3 url `"+targetURL+"`.")
+			default:
+				fatalIf(err.Trace(targetURL), "Unable to share target `"+targetURL+"`.")
+			}
+		}
+	}
+	return nil
+}
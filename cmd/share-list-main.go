@@ -18,7 +18,6 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
@@ -91,12 +90,7 @@ func doShareList(cmd string) *probe.Error {
 
 	// Print previously shared entries.
 	for shareURL, share := range shareDB.Shares {
-		printMsg(shareMesssage{
-			ObjectURL:   share.URL,
-			ShareURL:    shareURL,
-			TimeLeft:    share.Expiry - time.Since(share.Date),
-			ContentType: share.ContentType,
-		})
+		printMsg(newShareMessage(share.URL, shareURL, share.Date, share.Expiry, share.ContentType))
 	}
 	return nil
 }
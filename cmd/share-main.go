@@ -40,7 +40,10 @@ var shareCmd = cli.Command{
 	Subcommands: []cli.Command{
 		shareDownload,
 		shareUpload,
+		shareHead,
+		shareDelete,
 		shareList,
+		shareRm,
 	},
 }
 
@@ -54,8 +57,9 @@ func migrateShare() {
 	oldShareFile := filepath.Join(mustGetShareDir(), "urls.json")
 	if _, e := os.Stat(oldShareFile); e == nil {
 		// Old file exits.
-		e := os.Remove(oldShareFile)
-		fatalIf(probe.NewError(e), "Unable to delete old `"+oldShareFile+"`.")
+		if e := os.Remove(oldShareFile); e != nil && !os.IsNotExist(e) {
+			fatalIf(probe.NewError(e), "Unable to delete old `"+oldShareFile+"`.")
+		}
 		console.Infof("Removed older version of share `%s` file.\n", oldShareFile)
 	}
 }
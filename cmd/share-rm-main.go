@@ -0,0 +1,194 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var (
+	shareRmFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "expired",
+			Usage: "remove all expired shares",
+		},
+	}
+)
+
+// Remove previously shared URLs.
+var shareRm = cli.Command{
+	Name:   "rm",
+	Usage:  "remove previously shared URLs from the list",
+	Action: mainShareRm,
+	Before: setGlobalsFromContext,
+	Flags:  append(shareRmFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} [FLAGS] ID|URL [ID|URL...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   Shares are matched by the ID shown by 'share list', the shared URL or
+   the URL of the object, which removes all its shares.
+
+   Removing a share only forgets it, a shared URL is signed with the access
+   key of the alias and stays valid until it expires, unless that key is
+   changed or removed on the server.
+
+EXAMPLES:
+   1. Remove a share by its ID.
+       $ {{.HelpName}} 3b1c4f2a
+
+   2. Remove all shares of an object.
+       $ {{.HelpName}} https://play.min.io/mybucket/myobject.txt
+
+   3. Remove all expired shares.
+       $ {{.HelpName}} --expired
+
+`,
+}
+
+// shareRmMessage container for a removed share.
+type shareRmMessage struct {
+	Status    string `json:"status"`
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ObjectURL string `json:"url"`
+}
+
+// String colorized share rm message.
+func (s shareRmMessage) String() string {
+	return console.Colorize("ShareRm", fmt.Sprintf("Removed %s share `%s` of `%s`.", s.Type, s.ID, s.ObjectURL))
+}
+
+// JSON jsonified share rm message.
+func (s shareRmMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// shareExpiredMessage container for the number of removed expired shares.
+type shareExpiredMessage struct {
+	Status  string `json:"status"`
+	Expired int    `json:"expired"`
+}
+
+// String colorized share expired message.
+func (s shareExpiredMessage) String() string {
+	return console.Colorize("ShareRm", fmt.Sprintf("Removed %d expired shares.", s.Expired))
+}
+
+// JSON jsonified share expired message.
+func (s shareExpiredMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// validate command-line args.
+func checkShareRmSyntax(ctx *cli.Context) {
+	if ctx.Args().Present() == ctx.Bool("expired") {
+		cli.ShowCommandHelpAndExit(ctx, "rm", 1) // last argument is exit code.
+	}
+}
+
+// removeShares removes the shares of the database in filename matching
+// any of the args, matched args are marked in found.
+func removeShares(filename, shareType string, args []string, found map[string]bool) *probe.Error {
+	shareDB := newShareDBV1()
+	if err := shareDB.Load(filename); err != nil {
+		return err.Trace(filename)
+	}
+	for shareURL, share := range shareDB.Shares {
+		id := shareID(shareURL)
+		for _, arg := range args {
+			if arg != id && arg != shareURL && arg != share.URL {
+				continue
+			}
+			found[arg] = true
+			shareDB.Delete(shareURL)
+			printMsg(shareRmMessage{
+				ID:        id,
+				Type:      shareType,
+				ObjectURL: share.URL,
+			})
+			break
+		}
+	}
+	return shareDB.Save(filename).Trace(filename)
+}
+
+// pruneShares removes expired shares, returns their number.
+func pruneShares(filename string) (int, *probe.Error) {
+	shareDB := newShareDBV1()
+	if err := shareDB.Load(filename); err != nil {
+		return 0, err.Trace(filename)
+	}
+	return shareDB.expired, nil
+}
+
+// main entry point for share rm.
+func mainShareRm(ctx *cli.Context) error {
+	// validate command-line args.
+	checkShareRmSyntax(ctx)
+
+	// Additional command speific theme customization.
+	shareSetColor()
+
+	// Initialize share config folder.
+	initShareConfig()
+
+	if ctx.Bool("expired") {
+		var expired int
+		for _, filename := range []string{getShareUploadsFile(), getShareDownloadsFile()} {
+			n, err := pruneShares(filename)
+			fatalIf(err, "Unable to remove expired shares.")
+			expired += n
+		}
+		printMsg(shareExpiredMessage{Expired: expired})
+		return nil
+	}
+
+	args := ctx.Args()
+	found := make(map[string]bool)
+	fatalIf(removeShares(getShareUploadsFile(), "upload", args, found), "Unable to remove shared uploads.")
+	fatalIf(removeShares(getShareDownloadsFile(), "download", args, found), "Unable to remove shared downloads.")
+
+	var fs failureStatus
+	for _, arg := range args {
+		if found[arg] {
+			fs.success()
+			continue
+		}
+		err := errTargetNotFound(arg)
+		errorIf(err, "Unable to remove share.")
+		fs.fail(err)
+	}
+	return fs.exitError()
+}
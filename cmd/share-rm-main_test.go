@@ -0,0 +1,64 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestShareRm(c *C) {
+	dir, e := ioutil.TempDir("", "mc-share-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "downloads.json")
+
+	shareDB := newShareDBV1()
+	shareDB.Set("https://play.min.io/bucket/a.txt", "https://play.min.io/bucket/a.txt?X-Amz-Signature=1", time.Hour, "")
+	shareDB.Set("https://play.min.io/bucket/a.txt", "https://play.min.io/bucket/a.txt?X-Amz-Signature=2", time.Hour, "")
+	shareDB.Set("https://play.min.io/bucket/b.txt", "https://play.min.io/bucket/b.txt?X-Amz-Signature=3", time.Hour, "")
+	shareDB.Shares["https://play.min.io/bucket/c.txt?X-Amz-Signature=4"] = shareEntryV1{
+		URL:    "https://play.min.io/bucket/c.txt",
+		Date:   UTCNow().Add(-2 * time.Hour),
+		Expiry: time.Hour,
+	}
+	c.Assert(shareDB.Save(filename), IsNil)
+
+	// IDs are stable and short.
+	id := shareID("https://play.min.io/bucket/b.txt?X-Amz-Signature=3")
+	c.Assert(id, HasLen, 8)
+	c.Assert(shareID("https://play.min.io/bucket/b.txt?X-Amz-Signature=3"), Equals, id)
+
+	expired, err := pruneShares(filename)
+	c.Assert(err, IsNil)
+	c.Assert(expired, Equals, 1)
+
+	found := make(map[string]bool)
+	c.Assert(removeShares(filename, "download", []string{"https://play.min.io/bucket/a.txt", "missing"}, found), IsNil)
+	c.Assert(found, DeepEquals, map[string]bool{"https://play.min.io/bucket/a.txt": true})
+
+	c.Assert(removeShares(filename, "download", []string{id}, found), IsNil)
+	c.Assert(found[id], Equals, true)
+
+	shareDB = newShareDBV1()
+	c.Assert(shareDB.Load(filename), IsNil)
+	c.Assert(shareDB.Shares, HasLen, 0)
+}
@@ -18,9 +18,12 @@ package cmd
 
 import (
 	"fmt"
+	"html"
+	"sort"
 	"strings"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
 )
@@ -33,6 +36,14 @@ var (
 		},
 		shareFlagExpire,
 		shareFlagContentType,
+		cli.StringFlag{
+			Name:  "max-size",
+			Usage: "reject uploads larger than the given size, like '64MiB'",
+		},
+		cli.BoolFlag{
+			Name:  "html",
+			Usage: "generate an HTML upload form instead of a curl command",
+		},
 	}
 )
 
@@ -52,6 +63,11 @@ USAGE:
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
+DESCRIPTION:
+   The content type and size conditions are part of the signed POST policy,
+   uploads not matching them are rejected by the server. With '--json' the
+   POST URL and form fields are printed as well, to build other clients.
+
 EXAMPLES:
    1. Generate a curl command to allow upload access for a single object. Command expires in 7 days (default).
       $ {{.HelpName}} s3/backup/2006-Mar-1/backup.tar.gz
@@ -65,6 +81,9 @@ EXAMPLES:
    4. Generate a curl command to allow upload access to any objects matching the key prefix 'backup/'. Command expires in 2 hours.
       $ {{.HelpName}} --recursive --expire=2h s3/backup/2007-Mar-2/backup/
 
+   5. Generate an HTML form to upload files of at most 10MiB to a folder, named after the uploaded file.
+      $ {{.HelpName}} --recursive --html --max-size=10MiB s3/incoming/
+
 `,
 }
 
@@ -77,24 +96,18 @@ func checkShareUploadSyntax(ctx *cli.Context) {
 
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
-	expireArg := ctx.String("expire")
-
-	// Parse expiry.
-	expiry := shareDefaultExpiry
-	if expireArg != "" {
-		var e error
-		expiry, e = time.ParseDuration(expireArg)
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+expireArg+"`.")
-	}
 
 	// Validate expiry.
-	if expiry.Seconds() < 1 {
-		fatalIf(errDummy().Trace(expiry.String()),
-			"Expiry cannot be lesser than 1 second.")
-	}
-	if expiry.Seconds() > 604800 {
-		fatalIf(errDummy().Trace(expiry.String()),
-			"Expiry cannot be larger than 7 days.")
+	_, err := parseShareExpiry(ctx.String("expire"))
+	fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
+
+	// Validate size limit.
+	if maxSize := ctx.String("max-size"); maxSize != "" {
+		size, e := humanize.ParseBytes(maxSize)
+		fatalIf(probe.NewError(e).Trace(maxSize), "Unable to parse max-size=`"+maxSize+"`.")
+		if size == 0 {
+			fatalIf(errInvalidArgument().Trace(maxSize), "Maximum size cannot be zero.")
+		}
 	}
 
 	for _, targetURL := range ctx.Args() {
@@ -106,16 +119,28 @@ func checkShareUploadSyntax(ctx *cli.Context) {
 	}
 }
 
+// sortedFormFields - names of the form fields in uploadInfo except key,
+// in a stable order.
+func sortedFormFields(uploadInfo map[string]string) []string {
+	var fields []string
+	for k := range uploadInfo {
+		if k != "key" {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 // makeCurlCmd constructs curl command-line.
 func makeCurlCmd(key, postURL string, isRecursive bool, uploadInfo map[string]string) (string, *probe.Error) {
 	postURL += " "
 	curlCommand := "curl " + postURL
-	for k, v := range uploadInfo {
-		if k == "key" {
-			key = v
-			continue
-		}
-		curlCommand += fmt.Sprintf("-F %s=%s ", k, v)
+	if v, ok := uploadInfo["key"]; ok {
+		key = v
+	}
+	for _, k := range sortedFormFields(uploadInfo) {
+		curlCommand += fmt.Sprintf("-F %s=%s ", k, uploadInfo[k])
 	}
 	// If key starts with is enabled prefix it with the output.
 	if isRecursive {
@@ -127,6 +152,27 @@ func makeCurlCmd(key, postURL string, isRecursive bool, uploadInfo map[string]st
 	return curlCommand, nil
 }
 
+// makeHTMLForm constructs an HTML form uploading a file chosen in the
+// browser. Recursive shares name the object after the uploaded file.
+func makeHTMLForm(key, postURL string, isRecursive bool, uploadInfo map[string]string) (string, *probe.Error) {
+	if v, ok := uploadInfo["key"]; ok {
+		key = v
+	}
+	if isRecursive {
+		key += "${filename}"
+	}
+	form := fmt.Sprintf("<form action=\"%s\" method=\"post\" enctype=\"multipart/form-data\">\n", html.EscapeString(postURL))
+	form += fmt.Sprintf("  <input type=\"hidden\" name=\"key\" value=\"%s\">\n", html.EscapeString(key))
+	for _, k := range sortedFormFields(uploadInfo) {
+		form += fmt.Sprintf("  <input type=\"hidden\" name=\"%s\" value=\"%s\">\n", html.EscapeString(k), html.EscapeString(uploadInfo[k]))
+	}
+	// The file must be the last field of the form.
+	form += "  <input type=\"file\" name=\"file\">\n"
+	form += "  <input type=\"submit\" value=\"Upload\">\n"
+	form += "</form>"
+	return form, nil
+}
+
 // save shared URL to disk.
 func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, contentType string) *probe.Error {
 	// Load previously saved upload-shares.
@@ -142,37 +188,49 @@ func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, cont
 	return nil
 }
 
+// shareUploadOpts - conditions and output format of an upload share.
+type shareUploadOpts struct {
+	isRecursive bool
+	expiry      time.Duration
+	contentType string
+	maxSize     int64
+	isHTML      bool
+}
+
 // doShareUploadURL uploads files to the target.
-func doShareUploadURL(objectURL string, isRecursive bool, expiry time.Duration, contentType string) *probe.Error {
+func doShareUploadURL(objectURL string, opts shareUploadOpts) *probe.Error {
 	clnt, err := newClient(objectURL)
 	if err != nil {
 		return err.Trace(objectURL)
 	}
 
 	// Generate pre-signed access info.
-	shareURL, uploadInfo, err := clnt.ShareUpload(isRecursive, expiry, contentType)
+	postURL, uploadInfo, err := clnt.ShareUpload(opts.isRecursive, opts.expiry, opts.contentType, opts.maxSize)
 	if err != nil {
-		return err.Trace(objectURL, "expiry="+expiry.String(), "contentType="+contentType)
+		return err.Trace(objectURL, "expiry="+opts.expiry.String(), "contentType="+opts.contentType)
 	}
 
 	// Get the new expanded url.
 	objectURL = clnt.GetURL().String()
 
-	// Generate curl command.
-	curlCmd, err := makeCurlCmd(objectURL, shareURL, isRecursive, uploadInfo)
+	// Generate curl command or HTML form.
+	makeShare := makeCurlCmd
+	if opts.isHTML {
+		makeShare = makeHTMLForm
+	}
+	shareCmd, err := makeShare(objectURL, postURL, opts.isRecursive, uploadInfo)
 	if err != nil {
 		return err.Trace(objectURL)
 	}
 
-	printMsg(shareMesssage{
-		ObjectURL:   objectURL,
-		ShareURL:    curlCmd,
-		TimeLeft:    expiry,
-		ContentType: contentType,
-	})
+	shareMsg := newShareMessage(objectURL, shareCmd, UTCNow(), opts.expiry, opts.contentType)
+	shareMsg.PostURL = postURL
+	shareMsg.FormData = uploadInfo
+	shareMsg.MaxSize = opts.maxSize
+	printMsg(shareMsg)
 
 	// save shared URL to disk.
-	return saveSharedURL(objectURL, curlCmd, expiry, contentType)
+	return saveSharedURL(objectURL, shareCmd, opts.expiry, opts.contentType)
 }
 
 // main for share upload command.
@@ -188,18 +246,22 @@ func mainShareUpload(ctx *cli.Context) error {
 	shareSetColor()
 
 	// Set command flags from context.
-	isRecursive := ctx.Bool("recursive")
-	expireArg := ctx.String("expire")
-	expiry := shareDefaultExpiry
-	contentType := ctx.String("content-type")
-	if expireArg != "" {
-		var e error
-		expiry, e = time.ParseDuration(expireArg)
-		fatalIf(probe.NewError(e), "Unable to parse expire=`"+expireArg+"`.")
+	opts := shareUploadOpts{
+		isRecursive: ctx.Bool("recursive"),
+		contentType: ctx.String("content-type"),
+		isHTML:      ctx.Bool("html"),
+	}
+	var err *probe.Error
+	opts.expiry, err = parseShareExpiry(ctx.String("expire"))
+	fatalIf(err, "Invalid expire=`"+ctx.String("expire")+"`.")
+	if maxSize := ctx.String("max-size"); maxSize != "" {
+		size, e := humanize.ParseBytes(maxSize)
+		fatalIf(probe.NewError(e).Trace(maxSize), "Unable to parse max-size=`"+maxSize+"`.")
+		opts.maxSize = int64(size)
 	}
 
 	for _, targetURL := range ctx.Args() {
-		err := doShareUploadURL(targetURL, isRecursive, expiry, contentType)
+		err := doShareUploadURL(targetURL, opts)
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case APINotImplemented:
@@ -0,0 +1,87 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestMakeShareUpload(t *testing.T) {
+	uploadInfo := map[string]string{
+		"bucket":       "incoming",
+		"key":          "photos/",
+		"policy":       "eyJleHBpcmF0aW9uIjoiIn0=",
+		"Content-Type": "image/png",
+	}
+	testCases := []struct {
+		isRecursive bool
+		curl        string
+		form        string
+	}{
+		{
+			false,
+			"curl https://play.min.io/incoming -F Content-Type=image/png -F bucket=incoming -F policy=eyJleHBpcmF0aW9uIjoiIn0= -F key=photos/ -F file=@<FILE>",
+			`<form action="https://play.min.io/incoming" method="post" enctype="multipart/form-data">
+  <input type="hidden" name="key" value="photos/">
+  <input type="hidden" name="Content-Type" value="image/png">
+  <input type="hidden" name="bucket" value="incoming">
+  <input type="hidden" name="policy" value="eyJleHBpcmF0aW9uIjoiIn0=">
+  <input type="file" name="file">
+  <input type="submit" value="Upload">
+</form>`,
+		},
+		{
+			true,
+			"curl https://play.min.io/incoming -F Content-Type=image/png -F bucket=incoming -F policy=eyJleHBpcmF0aW9uIjoiIn0= -F key=photos/<NAME> -F file=@<FILE>",
+			`<form action="https://play.min.io/incoming" method="post" enctype="multipart/form-data">
+  <input type="hidden" name="key" value="photos/${filename}">
+  <input type="hidden" name="Content-Type" value="image/png">
+  <input type="hidden" name="bucket" value="incoming">
+  <input type="hidden" name="policy" value="eyJleHBpcmF0aW9uIjoiIn0=">
+  <input type="file" name="file">
+  <input type="submit" value="Upload">
+</form>`,
+		},
+	}
+	for i, testCase := range testCases {
+		curl, err := makeCurlCmd("play/incoming/photos/", "https://play.min.io/incoming", testCase.isRecursive, uploadInfo)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %s", i+1, err)
+		}
+		if curl != testCase.curl {
+			t.Errorf("Test %d: expected curl command\n%s\ngot\n%s", i+1, testCase.curl, curl)
+		}
+		form, err := makeHTMLForm("play/incoming/photos/", "https://play.min.io/incoming", testCase.isRecursive, uploadInfo)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %s", i+1, err)
+		}
+		if form != testCase.form {
+			t.Errorf("Test %d: expected HTML form\n%s\ngot\n%s", i+1, testCase.form, form)
+		}
+	}
+
+	// Values are escaped in forms.
+	form, _ := makeHTMLForm("", "https://play.min.io/a?b&c", false, map[string]string{"key": `"><script>`})
+	expected := `<form action="https://play.min.io/a?b&amp;c" method="post" enctype="multipart/form-data">
+  <input type="hidden" name="key" value="&#34;&gt;&lt;script&gt;">
+  <input type="file" name="file">
+  <input type="submit" value="Upload">
+</form>`
+	if form != expected {
+		t.Errorf("expected HTML form\n%s\ngot\n%s", expected, form)
+	}
+}
@@ -18,12 +18,17 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
@@ -47,24 +52,163 @@ var (
 		Value: "168h",
 		Usage: "set expiry in NN[h|m|s]",
 	}
+	shareFlagParam = cli.StringSliceFlag{
+		Name:  "param",
+		Usage: "add a query parameter signed with the URL in KEY=VALUE format, e.g. versionId=ID",
+	}
 )
 
+// parseShareParams - add the query parameters given in KEY=VALUE format
+// to reqParams.
+func parseShareParams(reqParams url.Values, params []string) *probe.Error {
+	for _, param := range params {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return errInvalidArgument().Trace(param)
+		}
+		reqParams.Set(strings.TrimSpace(kv[0]), kv[1])
+	}
+	return nil
+}
+
+// forEachShareObject - call fn with a client of the target object, or
+// of each object in the target folder, recursively if isRecursive is set.
+func forEachShareObject(targetAlias, targetURLFull string, isRecursive bool, fn func(clnt Client) *probe.Error) *probe.Error {
+	clnt, err := newClientFromAlias(targetAlias, targetURLFull)
+	if err != nil {
+		return err.Trace(targetURLFull)
+	}
+	isIncomplete := false
+	isFetchMeta := false
+	content, err := clnt.Stat(globalContext, isIncomplete, isFetchMeta, nil)
+	if err != nil {
+		return err.Trace(clnt.GetURL().String())
+	}
+	if !content.Type.IsDir() {
+		return fn(clnt)
+	}
+
+	if !strings.HasSuffix(targetURLFull, string(clnt.GetURL().Separator)) {
+		targetURLFull = targetURLFull + string(clnt.GetURL().Separator)
+	}
+	clnt, err = newClientFromAlias(targetAlias, targetURLFull)
+	if err != nil {
+		return err.Trace(targetURLFull)
+	}
+	for content := range clnt.List(globalContext, isRecursive, isIncomplete, DirNone) {
+		if content.Err != nil {
+			return content.Err.Trace(clnt.GetURL().String())
+		}
+		// if any incoming directories, we don't need to calculate.
+		if content.Type.IsDir() {
+			continue
+		}
+		objectURL := content.URL.String()
+		objectClnt, err := newClientFromAlias(targetAlias, objectURL)
+		if err != nil {
+			return err.Trace(objectURL)
+		}
+		if err = fn(objectClnt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseShareExpiry - parse and validate an expiry in NN[h|m|s], presigned
+// URLs are valid for at least 1 second and at most 7 days.
+func parseShareExpiry(expireArg string) (time.Duration, *probe.Error) {
+	expiry, e := time.ParseDuration(expireArg)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(expireArg)
+	}
+	if expiry.Seconds() < 1 {
+		return 0, probe.NewError(errors.New("expiry cannot be lesser than 1 second")).Trace(expireArg)
+	}
+	if expiry.Seconds() > 604800 {
+		return 0, probe.NewError(errors.New("expiry cannot be larger than 7 days")).Trace(expireArg)
+	}
+	return expiry, nil
+}
+
+// doShareRequestURL - print presigned URLs of method, such as HEAD or
+// DELETE, for the objects of the target. The default expiry of the
+// target alias is used for a zero expiry.
+func doShareRequestURL(method, targetURL string, isRecursive bool, expiry time.Duration, reqParams url.Values) *probe.Error {
+	targetAlias, targetURLFull, hostCfg, err := expandAlias(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	if expiry == 0 {
+		expiry, err = getShareDownloadExpiry(hostCfg)
+		if err != nil {
+			return err.Trace(targetAlias)
+		}
+	}
+	return forEachShareObject(targetAlias, targetURLFull, isRecursive, func(clnt Client) *probe.Error {
+		objectURL := clnt.GetURL().String()
+		shareURL, err := clnt.ShareRequest(method, expiry, reqParams)
+		if err != nil {
+			return err.Trace(objectURL, "expiry="+expiry.String())
+		}
+		msg := newShareMessage(objectURL, shareURL, UTCNow(), expiry, "")
+		msg.Method = method
+		printMsg(msg)
+		return nil
+	})
+}
+
 // Structured share command message.
 type shareMesssage struct {
 	Status      string        `json:"status"`
+	ID          string        `json:"id"`
 	ObjectURL   string        `json:"url"`
 	ShareURL    string        `json:"share"`
 	TimeLeft    time.Duration `json:"timeLeft"`
+	ExpiresAt   time.Time     `json:"expiresAt"`
 	ContentType string        `json:"contentType,omitempty"` // Only used by upload cmd.
+	Method      string        `json:"method,omitempty"`      // Only used by head and delete cmds.
+
+	// POST policy of an upload share, to build other clients than curl.
+	PostURL  string            `json:"postURL,omitempty"`
+	FormData map[string]string `json:"formData,omitempty"`
+	MaxSize  int64             `json:"maxSize,omitempty"`
+}
+
+// newShareMessage - message of a share entry, expiring at date + expiry.
+func newShareMessage(objectURL, shareURL string, date time.Time, expiry time.Duration, contentType string) shareMesssage {
+	return shareMesssage{
+		ID:          shareID(shareURL),
+		ObjectURL:   objectURL,
+		ShareURL:    shareURL,
+		TimeLeft:    expiry - time.Since(date),
+		ExpiresAt:   date.Add(expiry),
+		ContentType: contentType,
+	}
+}
+
+// shareID - short identifier of a shared URL, for `share rm`.
+func shareID(shareURL string) string {
+	sum := sha256.Sum256([]byte(shareURL))
+	return hex.EncodeToString(sum[:])[:8]
 }
 
 // String - Themefied string message for console printing.
 func (s shareMesssage) String() string {
 	msg := console.Colorize("URL", fmt.Sprintf("URL: %s\n", s.ObjectURL))
+	if s.ID != "" {
+		msg += console.Colorize("ID", fmt.Sprintf("ID: %s\n", s.ID))
+	}
 	msg += console.Colorize("Expire", fmt.Sprintf("Expire: %s\n", timeDurationToHumanizedDuration(s.TimeLeft)))
+	if s.Method != "" {
+		msg += console.Colorize("Content-type", fmt.Sprintf("Method: %s\n", s.Method))
+	}
 	if s.ContentType != "" {
 		msg += console.Colorize("Content-type", fmt.Sprintf("Content-Type: %s\n", s.ContentType))
 	}
+	if s.MaxSize > 0 {
+		msg += console.Colorize("Content-type", fmt.Sprintf("Max-Size: %s\n", humanize.IBytes(uint64(s.MaxSize))))
+	}
 
 	// Highlight <FILE> specifically. "share upload" sub-commands use this identifier.
 	shareURL := strings.Replace(s.ShareURL, "<FILE>", console.Colorize("File", "<FILE>"), 1)
@@ -96,10 +240,12 @@ func (s shareMesssage) JSON() string {
 func shareSetColor() {
 	// Additional command speific theme customization.
 	console.SetColor("URL", color.New(color.Bold))
+	console.SetColor("ID", color.New(color.FgYellow))
 	console.SetColor("Expire", color.New(color.FgCyan))
 	console.SetColor("Content-type", color.New(color.FgBlue))
 	console.SetColor("Share", color.New(color.FgGreen))
 	console.SetColor("File", color.New(color.FgRed, color.Bold))
+	console.SetColor("ShareRm", color.New(color.FgGreen, color.Bold))
 }
 
 // Get share dir name.
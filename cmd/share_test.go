@@ -0,0 +1,102 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseShareParams(t *testing.T) {
+	testCases := []struct {
+		params    []string
+		reqParams url.Values
+		success   bool
+	}{
+		{nil, url.Values{}, true},
+		{[]string{"versionId=3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY"}, url.Values{
+			"versionId": {"3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY"},
+		}, true},
+		// Values may be empty or contain '='.
+		{[]string{"versionId=", "x-id=a=b"}, url.Values{
+			"versionId": {""},
+			"x-id":      {"a=b"},
+		}, true},
+		{[]string{"versionId"}, nil, false},
+		{[]string{"=value"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		reqParams := make(url.Values)
+		err := parseShareParams(reqParams, testCase.params)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if testCase.success && !reflect.DeepEqual(reqParams, testCase.reqParams) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.reqParams, reqParams)
+		}
+	}
+}
+
+func TestShareDBMerge(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-share-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "downloads.json")
+
+	saved := newShareDBV1()
+	saved.Set("https://play.min.io/bucket/a.txt", "https://play.min.io/bucket/a.txt?X-Amz-Signature=1", time.Hour, "")
+	if err := saved.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two processes load the shares at once, both of their changes are
+	// saved.
+	first, second := newShareDBV1(), newShareDBV1()
+	for _, shareDB := range []*shareDBV1{first, second} {
+		if err := shareDB.Load(filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first.Set("https://play.min.io/bucket/b.txt", "https://play.min.io/bucket/b.txt?X-Amz-Signature=2", time.Hour, "")
+	first.Delete("https://play.min.io/bucket/a.txt?X-Amz-Signature=1")
+	second.Set("https://play.min.io/bucket/c.txt", "https://play.min.io/bucket/c.txt?X-Amz-Signature=3", time.Hour, "")
+	for _, shareDB := range []*shareDBV1{first, second} {
+		if err := shareDB.Save(filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	shareDB := newShareDBV1()
+	if err := shareDB.Load(filename); err != nil {
+		t.Fatal(err)
+	}
+	var shareURLs []string
+	for shareURL := range shareDB.Shares {
+		shareURLs = append(shareURLs, shareURL)
+	}
+	if len(shareURLs) != 2 || shareDB.Shares["https://play.min.io/bucket/b.txt?X-Amz-Signature=2"].URL == "" ||
+		shareDB.Shares["https://play.min.io/bucket/c.txt?X-Amz-Signature=3"].URL == "" {
+		t.Errorf("expected the shares of b.txt and c.txt, found %v", shareURLs)
+	}
+}
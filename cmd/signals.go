@@ -19,10 +19,17 @@ package cmd
 import (
 	"os"
 	"os/signal"
+	"sync/atomic"
 )
 
+// Number of signal traps set by commands stopping on their own once
+// interrupted.
+var signalTraps int32
+
 // signalTrap traps the registered signals and notifies the caller.
 func signalTrap(sig ...os.Signal) <-chan bool {
+	atomic.AddInt32(&signalTraps, 1)
+
 	// channel to notify the caller.
 	trapCh := make(chan bool, 1)
 
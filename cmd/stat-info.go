@@ -0,0 +1,286 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+)
+
+// statAliasTLS - TLS state of the endpoint of an alias.
+type statAliasTLS struct {
+	Version     string    `json:"version,omitempty"`
+	Verified    bool      `json:"verified"`
+	Pinned      bool      `json:"pinned"`
+	VerifyError string    `json:"verifyError,omitempty"`
+	Expires     time.Time `json:"expires,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// statAliasMessage - configuration and TLS state of an alias.
+type statAliasMessage struct {
+	Status string        `json:"status"`
+	Alias  string        `json:"alias"`
+	URL    string        `json:"url"`
+	API    string        `json:"api"`
+	Lookup string        `json:"lookup"`
+	Region string        `json:"region,omitempty"`
+	TLS    *statAliasTLS `json:"tls,omitempty"`
+}
+
+// String colorized alias message.
+func (s statAliasMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, console.Colorize("Name", fmt.Sprintf("%-10s: %s", "Alias", s.Alias)))
+	fmt.Fprintf(&b, "%-10s: %s\n", "URL", s.URL)
+	fmt.Fprintf(&b, "%-10s: %s\n", "API", s.API)
+	fmt.Fprintf(&b, "%-10s: %s\n", "Lookup", s.Lookup)
+	region := s.Region
+	if region == "" {
+		region = "looked up per bucket"
+	}
+	fmt.Fprintf(&b, "%-10s: %s\n", "Region", region)
+	switch {
+	case s.TLS == nil:
+		fmt.Fprintf(&b, "%-10s: %s\n", "TLS", "disabled")
+	case s.TLS.Error != "":
+		fmt.Fprintf(&b, "%-10s: %s\n", "TLS", console.Colorize("NotVerified", "unreachable, "+s.TLS.Error))
+	default:
+		verification := "certificate authority"
+		if s.TLS.Pinned {
+			verification = "pinned fingerprint"
+		}
+		state := console.Colorize("Verified", "verified")
+		if !s.TLS.Verified {
+			state = console.Colorize("NotVerified", "not verified, "+s.TLS.VerifyError)
+		}
+		fmt.Fprintf(&b, "%-10s: %s, %s (%s)\n", "TLS", s.TLS.Version, state, verification)
+		fmt.Fprintf(&b, "%-10s: %s\n", "Expires", formatDate(s.TLS.Expires))
+	}
+	return b.String()
+}
+
+// JSON jsonified alias message.
+func (s statAliasMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// statAlias - describe an alias, the TLS endpoint is connected to but
+// failures to connect are reported in the message.
+func statAlias(alias string, hostCfg *hostConfigV9) statAliasMessage {
+	msg := statAliasMessage{
+		Alias:  alias,
+		URL:    hostCfg.URL,
+		API:    hostCfg.API,
+		Lookup: hostCfg.Lookup,
+		Region: hostCfg.Region,
+	}
+	if !strings.HasPrefix(hostCfg.URL, "https://") {
+		return msg
+	}
+	tlsMsg, err := getAliasTLS(alias, hostCfg)
+	if err != nil {
+		msg.TLS = &statAliasTLS{Error: err.ToGoError().Error()}
+		return msg
+	}
+	msg.TLS = &statAliasTLS{
+		Version:     tlsMsg.Version,
+		Verified:    tlsMsg.Verified,
+		Pinned:      tlsMsg.Pinned,
+		VerifyError: tlsMsg.VerifyError,
+	}
+	if len(tlsMsg.Certificates) > 0 {
+		msg.TLS.Expires = tlsMsg.Certificates[0].NotAfter
+	}
+	return msg
+}
+
+// statBucketLock - object lock of a bucket and its default retention.
+type statBucketLock struct {
+	Mode     string `json:"mode,omitempty"`
+	Validity string `json:"validity,omitempty"`
+}
+
+// statBucketMessage - properties of a bucket.
+type statBucketMessage struct {
+	Status     string          `json:"status"`
+	Bucket     string          `json:"bucket"`
+	Created    time.Time       `json:"created"`
+	Region     string          `json:"region"`
+	Versioning string          `json:"versioning"`
+	ObjectLock *statBucketLock `json:"objectLock,omitempty"`
+	Policy     string          `json:"policy"`
+	ACL        string          `json:"acl,omitempty"`
+}
+
+// String colorized bucket message.
+func (s statBucketMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, console.Colorize("Name", fmt.Sprintf("%-10s: %s", "Bucket", s.Bucket)))
+	if !s.Created.IsZero() {
+		fmt.Fprintf(&b, "%-10s: %s\n", "Created", formatDate(s.Created))
+	}
+	lock := "disabled"
+	if s.ObjectLock != nil {
+		lock = "enabled"
+		if s.ObjectLock.Mode != "" {
+			lock += fmt.Sprintf(", %s for %s by default", s.ObjectLock.Mode, s.ObjectLock.Validity)
+		}
+	}
+	for _, property := range []struct{ name, value string }{
+		{"Region", s.Region}, {"Versioning", s.Versioning}, {"Lock", lock}, {"Policy", s.Policy}, {"ACL", s.ACL},
+	} {
+		if property.value == "" {
+			property.value = "-"
+		}
+		fmt.Fprintf(&b, "%-10s: %s\n", property.name, property.value)
+	}
+	return b.String()
+}
+
+// JSON jsonified bucket message.
+func (s statBucketMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// isNotImplemented - servers and gateways without a bucket feature.
+func isNotImplemented(err *probe.Error) bool {
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "NotImplemented", "XNotImplemented":
+		return true
+	}
+	return false
+}
+
+// getObjectLockConfig - object lock configuration of the bucket, nil if
+// object lock is not enabled.
+func (c *s3Client) getObjectLockConfig() (*statBucketLock, *probe.Error) {
+	var lock struct {
+		ObjectLockEnabled string `xml:"ObjectLockEnabled"`
+		Rule              struct {
+			DefaultRetention struct {
+				Mode  string `xml:"Mode"`
+				Days  int    `xml:"Days"`
+				Years int    `xml:"Years"`
+			} `xml:"DefaultRetention"`
+		} `xml:"Rule"`
+	}
+	if err := c.getBucketConfig("object-lock", &lock); err != nil {
+		switch minio.ToErrorResponse(err.ToGoError()).Code {
+		case "ObjectLockConfigurationNotFoundError", "NotImplemented":
+			return nil, nil
+		}
+		return nil, err.Trace(c.targetURL.String())
+	}
+	if lock.ObjectLockEnabled != "Enabled" {
+		return nil, nil
+	}
+	retention := lock.Rule.DefaultRetention
+	config := &statBucketLock{Mode: retention.Mode}
+	switch {
+	case retention.Years > 0:
+		config.Validity = fmt.Sprintf("%dy", retention.Years)
+	case retention.Days > 0:
+		config.Validity = fmt.Sprintf("%dd", retention.Days)
+	}
+	return config, nil
+}
+
+// statBucket - describe a bucket. Properties which cannot be read, such
+// as the policy without permission, are reported and left empty.
+func statBucket(aliasedURL string, clnt *s3Client) (statBucketMessage, *probe.Error) {
+	bucket, _ := clnt.url2BucketAndObject()
+	msg := statBucketMessage{Bucket: aliasedURL}
+	if _, err := clnt.bucketStat(bucket); err != nil {
+		return msg, err.Trace(aliasedURL)
+	}
+
+	// The creation date is only listed with all buckets.
+	if buckets, e := clnt.api.ListBuckets(); e == nil {
+		for _, b := range buckets {
+			if b.Name == bucket {
+				msg.Created = b.CreationDate.In(globalTimeZone)
+			}
+		}
+	}
+
+	var e error
+	if msg.Region, e = clnt.api.GetBucketLocation(bucket); e != nil {
+		errorIf(probe.NewError(e).Trace(aliasedURL), "Unable to get the region of `"+aliasedURL+"`.")
+	}
+
+	var err *probe.Error
+	if msg.Versioning, err = clnt.GetVersioning(); err != nil && !isNotImplemented(err) {
+		errorIf(err, "Unable to get the versioning of `"+aliasedURL+"`.")
+	} else if err == nil && msg.Versioning == "" {
+		msg.Versioning = "Unversioned"
+	}
+	if msg.ObjectLock, err = clnt.getObjectLockConfig(); err != nil {
+		errorIf(err, "Unable to get the object lock configuration of `"+aliasedURL+"`.")
+	}
+	if msg.Policy, _, err = clnt.GetAccess(); err != nil && !isNotImplemented(err) {
+		errorIf(err.Trace(aliasedURL), "Unable to get the policy of `"+aliasedURL+"`.")
+	}
+	if msg.ACL, err = clnt.getCannedACL(bucket, ""); err != nil && !isNotImplemented(err) {
+		errorIf(err.Trace(aliasedURL), "Unable to get the ACL of `"+aliasedURL+"`.")
+	}
+	return msg, nil
+}
+
+// statAliasOrBucket - describe the alias or bucket of a target without a
+// trailing separator, false for other targets, which are listed.
+func statAliasOrBucket(targetURL string) (message, bool, *probe.Error) {
+	separator := string(newClientURL(targetURL).Separator)
+	if strings.HasSuffix(targetURL, separator) {
+		return nil, false, nil
+	}
+	alias, urlStrFull, hostCfg, err := expandAlias(targetURL)
+	if err != nil || hostCfg == nil {
+		return nil, false, nil
+	}
+	clnt, err := newClientFromAlias(alias, urlStrFull)
+	if err != nil {
+		return nil, false, err.Trace(targetURL)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return nil, false, nil
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	switch {
+	case bucket == "":
+		return statAlias(alias, hostCfg), true, nil
+	case object == "":
+		msg, err := statBucket(targetURL, s3Clnt)
+		return msg, true, err
+	}
+	return nil, false, nil
+}
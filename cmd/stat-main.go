@@ -31,6 +31,8 @@ var (
 			Name:  "recursive, r",
 			Usage: "stat all objects recursively",
 		},
+		rewindFlag,
+		formatFlag,
 	}
 )
 
@@ -50,6 +52,11 @@ USAGE:
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
+DESCRIPTION:
+  An alias or a bucket without a trailing separator is described, with a trailing separator
+  its contents are shown. Bucket usage is reported by 'mc admin bucket info', MinIO servers of
+  this admin API version have no bucket quotas.
+
 ENVIRONMENT VARIABLES:
    MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
 
@@ -65,6 +72,18 @@ EXAMPLES:
 
    4. Stat encrypted files on Amazon S3 cloud storage.
       $ {{.HelpName}} --encrypt-key "s3/personal-docs/=32byteslongsecretkeymustbegiven1" s3/personal-docs/2018-account_report.docx
+
+   5. Stat an object of a versioned bucket as it was on the first of June.
+      $ {{.HelpName}} --rewind 2019-06-01 s3/mybucket/report.pdf
+
+   6. Print the content type and storage class of all objects in mybucket.
+      $ {{.HelpName}} --recursive --format '{{"{{"}}.Key{{"}}"}} {{"{{"}}.Type{{"}}"}} {{"{{"}}.StorageClass{{"}}"}}' s3/mybucket/
+
+   7. Show the endpoint, API signature, region and TLS state of the alias s3.
+      $ {{.HelpName}} s3
+
+   8. Show the creation date, region, versioning, object lock and policy of mybucket.
+      $ {{.HelpName}} s3/mybucket
 `,
 }
 
@@ -85,7 +104,7 @@ func checkStatSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 	isIncomplete := false
 
 	for _, url := range URLs {
-		_, _, err := url2Stat(url, false, encKeyDB)
+		_, _, err := url2Stat(globalContext, url, false, encKeyDB)
 		if err != nil && !isURLPrefixExists(url, isIncomplete) {
 			fatalIf(err.Trace(url), "Unable to stat `"+url+"`.")
 		}
@@ -102,12 +121,15 @@ func mainStat(ctx *cli.Context) error {
 
 	console.SetColor("EncryptionHeaders", color.New(color.FgWhite))
 	console.SetColor("Metadata", color.New(color.FgWhite))
+	console.SetColor("Verified", color.New(color.FgGreen, color.Bold))
+	console.SetColor("NotVerified", color.New(color.FgRed, color.Bold))
 
 	// Parse encryption keys per command.
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
 	// check 'stat' cli arguments.
+	rewindSources(ctx.String("rewind"), ctx.Args()...)
 	checkStatSyntax(ctx, encKeyDB)
 
 	// Set command flags from context.
@@ -121,16 +143,29 @@ func mainStat(ctx *cli.Context) error {
 
 	var cErr error
 	for _, targetURL := range args {
+		// Aliases and buckets are described, unless listed with a
+		// trailing separator.
+		if !isRecursive && ctx.String("rewind") == "" {
+			msg, ok, err := statAliasOrBucket(targetURL)
+			fatalIf(err, "Unable to stat `"+targetURL+"`.")
+			if ok {
+				printMsg(msg)
+				continue
+			}
+		}
 		stats, err := statURL(targetURL, false, isRecursive, encKeyDB)
 		if err != nil {
 			fatalIf(err, "Unable to stat `"+targetURL+"`.")
 		}
 		for _, stat := range stats {
 			st := parseStat(stat)
-			if !globalJSON {
+			switch {
+			case globalFormat != nil:
+				printFormatted(st)
+			case !globalJSON:
 				printStat(st)
-			} else {
-				console.Println(st.JSON())
+			default:
+				console.Println(formatStructured(st.JSON()))
 			}
 		}
 	}
@@ -22,7 +22,6 @@ import (
 	"strings"
 	"time"
 
-	humanize "github.com/dustin/go-humanize"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
@@ -36,9 +35,66 @@ type statMessage struct {
 	Size              int64             `json:"size"`
 	ETag              string            `json:"etag"`
 	Type              string            `json:"type"`
+	VersionID         string            `json:"versionId,omitempty"`
 	Expires           time.Time         `json:"expires"`
+	CacheControl      string            `json:"cacheControl,omitempty"`
+	ContentEncoding   string            `json:"contentEncoding,omitempty"`
 	EncryptionHeaders map[string]string `json:"encryption,omitempty"`
 	Metadata          map[string]string `json:"metadata"`
+	StorageClass      string            `json:"storageClass,omitempty"`
+	Replication       string            `json:"replicationStatus,omitempty"`
+	Retention         *statRetention    `json:"retention,omitempty"`
+	LegalHold         string            `json:"legalHold,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	ACL               string            `json:"acl,omitempty"`
+	Checksum          *statChecksum     `json:"checksum,omitempty"`
+}
+
+// statRetention - object lock retention of an object.
+type statRetention struct {
+	Mode        string    `json:"mode"`
+	RetainUntil time.Time `json:"retainUntil"`
+}
+
+// statChecksum - checksum stored with an object.
+type statChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// Headers of object properties shown apart from the other metadata.
+const (
+	amzStorageClass       = "X-Amz-Storage-Class"
+	amzReplicationStatus  = "X-Amz-Replication-Status"
+	amzChecksumPrefix     = "X-Amz-Checksum-"
+	cacheControlHeader    = "Cache-Control"
+	contentEncodingHeader = "Content-Encoding"
+)
+
+// Checksum headers and the algorithms they were computed with.
+var amzChecksumAlgorithms = []string{"CRC32", "CRC32C", "SHA1", "SHA256"}
+
+// isStatPropertyHeader - returns true for headers shown apart from the
+// other metadata.
+func isStatPropertyHeader(key string) bool {
+	for _, header := range []string{amzStorageClass, amzReplicationStatus, amzObjectLockMode,
+		amzObjectLockRetainUntilDate, amzObjectLockLegalHold, amzTaggingCount,
+		cacheControlHeader, contentEncodingHeader} {
+		if strings.EqualFold(key, header) {
+			return true
+		}
+	}
+	return len(key) > len(amzChecksumPrefix) && strings.EqualFold(key[:len(amzChecksumPrefix)], amzChecksumPrefix)
+}
+
+// metadataValue - value of a metadata header, whatever its case.
+func metadataValue(metadata map[string]string, key string) string {
+	for k, v := range metadata {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
 }
 
 // String colorized string message.
@@ -46,24 +102,69 @@ func printStat(stat statMessage) {
 	// Format properly for alignment based on maxKey length
 	stat.Key = fmt.Sprintf("%-10s: %s", "Name", stat.Key)
 	console.Println(console.Colorize("Name", stat.Key))
-	console.Println(fmt.Sprintf("%-10s: %s ", "Date", stat.Date.Format(printDate)))
-	console.Println(fmt.Sprintf("%-10s: %-6s ", "Size", humanize.IBytes(uint64(stat.Size))))
+	console.Println(fmt.Sprintf("%-10s: %s ", "Date", formatDate(stat.Date)))
+	console.Println(fmt.Sprintf("%-10s: %-6s ", "Size", formatSize(stat.Size)))
 	if stat.ETag != "" {
 		console.Println(fmt.Sprintf("%-10s: %s ", "ETag", stat.ETag))
 	}
+	if stat.VersionID != "" {
+		console.Println(fmt.Sprintf("%-10s: %s ", "VersionID", stat.VersionID))
+	}
 	console.Println(fmt.Sprintf("%-10s: %s ", "Type", stat.Type))
 	if !stat.Expires.IsZero() {
-		console.Println(fmt.Sprintf("%-10s: %s ", "Expires", stat.Expires.Format(printDate)))
+		console.Println(fmt.Sprintf("%-10s: %s ", "Expires", formatDate(stat.Expires)))
+	}
+	if stat.CacheControl != "" {
+		console.Println(fmt.Sprintf("%-10s: %s ", "Cache", stat.CacheControl))
+	}
+	if stat.ContentEncoding != "" {
+		console.Println(fmt.Sprintf("%-10s: %s ", "Encoding", stat.ContentEncoding))
+	}
+	if stat.StorageClass != "" {
+		console.Println(fmt.Sprintf("%-10s: %s ", "Class", stat.StorageClass))
+	}
+	if stat.ACL != "" {
+		console.Println(fmt.Sprintf("%-10s: %s ", "ACL", stat.ACL))
+	}
+	if stat.Replication != "" {
+		console.Println(fmt.Sprintf("%-10s: %s ", "Replicated", stat.Replication))
+	}
+	if stat.Retention != nil {
+		console.Println(fmt.Sprintf("%-10s: %s until %s ", "Retention", stat.Retention.Mode, formatDate(stat.Retention.RetainUntil)))
+	}
+	if stat.LegalHold != "" {
+		console.Println(fmt.Sprintf("%-10s: %s ", "LegalHold", stat.LegalHold))
+	}
+	if stat.Checksum != nil {
+		console.Println(fmt.Sprintf("%-10s: %s %s ", "Checksum", stat.Checksum.Algorithm, stat.Checksum.Value))
 	}
 	var maxKey = 0
-	for k := range stat.Metadata {
+	for k := range stat.Tags {
+		if len(k) > maxKey {
+			maxKey = len(k)
+		}
+	}
+	if len(stat.Tags) > 0 {
+		console.Println(fmt.Sprintf("%-10s:", "Tags"))
+		for k, v := range stat.Tags {
+			console.Println(fmt.Sprintf("  %-*.*s: %s ", maxKey, maxKey, k, v))
+		}
+	}
+	metadata := make(map[string]string)
+	for k, v := range stat.Metadata {
+		if !isStatPropertyHeader(k) {
+			metadata[k] = v
+		}
+	}
+	maxKey = 0
+	for k := range metadata {
 		if len(k) > maxKey {
 			maxKey = len(k)
 		}
 	}
-	if len(stat.Metadata) > 0 {
+	if len(metadata) > 0 {
 		console.Println(fmt.Sprintf("%-10s:", "Metadata"))
-		for k, v := range stat.Metadata {
+		for k, v := range metadata {
 			console.Println(fmt.Sprintf("  %-*.*s: %s ", maxKey, maxKey, k, v))
 		}
 	}
@@ -94,7 +195,7 @@ func (c statMessage) JSON() string {
 // parseStat parses client Content container into statMessage struct.
 func parseStat(c *clientContent) statMessage {
 	content := statMessage{}
-	content.Date = c.Time.Local()
+	content.Date = c.Time.In(globalTimeZone)
 	// guess file type.
 	content.Type = func() string {
 		if c.Type.IsDir() {
@@ -109,9 +210,45 @@ func parseStat(c *clientContent) statMessage {
 	content.ETag = strings.TrimSuffix(content.ETag, "\"")
 	content.Expires = c.Expires
 	content.EncryptionHeaders = c.EncryptionHeaders
+	content.VersionID = c.VersionID
+	content.CacheControl = metadataValue(c.Metadata, cacheControlHeader)
+	content.ContentEncoding = metadataValue(c.Metadata, contentEncodingHeader)
+	content.StorageClass = metadataValue(c.Metadata, amzStorageClass)
+	content.Replication = metadataValue(c.Metadata, amzReplicationStatus)
+	if retention := parseObjectRetention(c.Metadata); retention.Mode != "" {
+		content.Retention = &statRetention{Mode: retention.Mode, RetainUntil: retention.RetainUntil.In(globalTimeZone)}
+	}
+	content.LegalHold = strings.ToUpper(metadataValue(c.Metadata, amzObjectLockLegalHold))
+	content.Tags = c.Tags
+	content.ACL = c.ACL
+	for _, algorithm := range amzChecksumAlgorithms {
+		if value := metadataValue(c.Metadata, amzChecksumPrefix+algorithm); value != "" {
+			content.Checksum = &statChecksum{Algorithm: algorithm, Value: value}
+			break
+		}
+	}
 	return content
 }
 
+// statObjectACL - the canned ACL of an object on object storage, empty
+// when it cannot be read, which needs its own permission.
+func statObjectACL(urlStr string) string {
+	clnt, err := newClient(urlStr)
+	if err != nil {
+		return ""
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return ""
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	acl, err := s3Clnt.getCannedACL(bucket, object)
+	if err != nil {
+		return ""
+	}
+	return acl
+}
+
 // statURL - simple or recursive listing
 func statURL(targetURL string, isIncomplete, isRecursive bool, encKeyDB map[string][]prefixSSEPair) ([]*clientContent, *probe.Error) {
 	var stats []*clientContent
@@ -129,7 +266,7 @@ func statURL(targetURL string, isIncomplete, isRecursive bool, encKeyDB map[stri
 		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
 	}
 	var cErr error
-	for content := range clnt.List(isRecursive, isIncomplete, DirNone) {
+	for content := range clnt.List(globalContext, isRecursive, isIncomplete, DirNone) {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
 			// handle this specifically for filesystem related errors.
@@ -150,7 +287,7 @@ func statURL(targetURL string, isIncomplete, isRecursive bool, encKeyDB map[stri
 				continue
 			}
 			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
-			cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
+			cErr = exitStatus(errorExitStatus(content.Err)) // Set the exit status.
 			continue
 		}
 		url := targetAlias + getKey(content)
@@ -159,9 +296,11 @@ func statURL(targetURL string, isIncomplete, isRecursive bool, encKeyDB map[stri
 			return nil, errTargetNotFound(targetURL)
 		}
 
-		_, stat, err := url2Stat(url, true, encKeyDB)
+		_, stat, err := url2Stat(globalContext, url, true, encKeyDB)
 		if err != nil {
 			stat = content
+		} else if !stat.Type.IsDir() {
+			stat.ACL = statObjectACL(url)
 		}
 		// Convert any os specific delimiters to "/".
 		contentURL := filepath.ToSlash(stat.URL.Path)
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"time"
@@ -56,3 +58,132 @@ func (s *TestSuite) TestParseStat(c *C) {
 		c.Assert(etag, Equals, statMsg.ETag)
 	}
 }
+
+// propertiesHandler - fake S3 server of an object with tags, retention,
+// replication and a checksum.
+type propertiesHandler struct{}
+
+func (h propertiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"></LocationConstraint>"))
+	case r.Method == "GET" && r.URL.Path == "/bucket/":
+		w.Write([]byte("<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated></ListBucketResult>"))
+	case r.Method == "GET" && len(query["tagging"]) > 0 && r.URL.Path == "/bucket/object":
+		w.Write([]byte("<Tagging><TagSet><Tag><Key>project</Key><Value>apollo</Value></Tag></TagSet></Tagging>"))
+	case r.Method == "HEAD" && r.URL.Path == "/bucket/object":
+		w.Header().Set("Content-Length", "12")
+		w.Header().Set("Last-Modified", UTCNow().Format(http.TimeFormat))
+		w.Header().Set("ETag", "9af2f8218b150c351ad802c6f3d66abe")
+		w.Header().Set("X-Amz-Storage-Class", "REDUCED_REDUNDANCY")
+		w.Header().Set("X-Amz-Replication-Status", "COMPLETED")
+		w.Header().Set("X-Amz-Object-Lock-Mode", "COMPLIANCE")
+		w.Header().Set("X-Amz-Object-Lock-Retain-Until-Date", "2020-01-01T00:00:00Z")
+		w.Header().Set("X-Amz-Object-Lock-Legal-Hold", "ON")
+		w.Header().Set("X-Amz-Tagging-Count", "1")
+		if r.Header.Get("X-Amz-Checksum-Mode") == "ENABLED" {
+			w.Header().Set("X-Amz-Checksum-Crc32c", "yZRlqg==")
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *TestSuite) TestStatProperties(c *C) {
+	server := httptest.NewServer(propertiesHandler{})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	content, err := clnt.Stat(globalContext, false, true, nil)
+	c.Assert(err, IsNil)
+	c.Assert(content.Tags, DeepEquals, map[string]string{"project": "apollo"})
+
+	statMsg := parseStat(content)
+	c.Assert(statMsg.StorageClass, Equals, "REDUCED_REDUNDANCY")
+	c.Assert(statMsg.Replication, Equals, "COMPLETED")
+	c.Assert(statMsg.Retention, NotNil)
+	c.Assert(statMsg.Retention.Mode, Equals, "COMPLIANCE")
+	c.Assert(statMsg.Retention.RetainUntil.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), Equals, true)
+	c.Assert(statMsg.LegalHold, Equals, "ON")
+	c.Assert(statMsg.Tags, DeepEquals, map[string]string{"project": "apollo"})
+	c.Assert(statMsg.Checksum, DeepEquals, &statChecksum{Algorithm: "CRC32C", Value: "yZRlqg=="})
+
+	// Properties are shown apart from the other metadata.
+	c.Assert(isStatPropertyHeader("X-Amz-Checksum-Crc32c"), Equals, true)
+	c.Assert(isStatPropertyHeader("x-amz-tagging-count"), Equals, true)
+	c.Assert(isStatPropertyHeader("Content-Type"), Equals, false)
+
+	// Tags are only read when the server counts some.
+	content, err = clnt.Stat(globalContext, false, false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(content.Tags, IsNil)
+	c.Assert(parseStat(content).Checksum, IsNil)
+}
+
+// bucketPropertiesHandler - fake S3 server of a versioned bucket with
+// object lock and without policy.
+type bucketPropertiesHandler struct{}
+
+func (h bucketPropertiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["location"]) > 0:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\">eu-west-1</LocationConstraint>"))
+	case r.Method == "GET" && len(query["versioning"]) > 0:
+		w.Write([]byte("<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>"))
+	case r.Method == "GET" && len(query["object-lock"]) > 0:
+		w.Write([]byte("<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled><Rule><DefaultRetention>" +
+			"<Mode>GOVERNANCE</Mode><Years>1</Years></DefaultRetention></Rule></ObjectLockConfiguration>"))
+	case r.Method == "GET" && len(query["acl"]) > 0:
+		w.Write([]byte("<AccessControlPolicy><Owner><ID>owner</ID></Owner><AccessControlList>" +
+			"<Grant><Grantee><ID>owner</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>" +
+			"<Grant><Grantee><URI>" + aclAllUsers + "</URI></Grantee><Permission>READ</Permission></Grant>" +
+			"</AccessControlList></AccessControlPolicy>"))
+	case r.Method == "GET" && len(query["policy"]) > 0:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchBucketPolicy</Code><Message>The bucket policy does not exist</Message></Error>"))
+	case r.Method == "GET" && r.URL.Path == "/":
+		w.Write([]byte("<ListAllMyBucketsResult><Buckets><Bucket><Name>bucket</Name>" +
+			"<CreationDate>2019-06-01T10:00:00.000Z</CreationDate></Bucket></Buckets></ListAllMyBucketsResult>"))
+	case r.Method == "HEAD" && r.URL.Path == "/bucket/":
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *TestSuite) TestStatBucketProperties(c *C) {
+	server := httptest.NewServer(bucketPropertiesHandler{})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	msg, err := statBucket("myminio/bucket", clnt.(*s3Client))
+	c.Assert(err, IsNil)
+	c.Assert(msg.Created.Equal(time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC)), Equals, true)
+	c.Assert(msg.Region, Equals, "eu-west-1")
+	c.Assert(msg.Versioning, Equals, "Enabled")
+	c.Assert(msg.ObjectLock, DeepEquals, &statBucketLock{Mode: "GOVERNANCE", Validity: "1y"})
+	c.Assert(msg.Policy, Equals, "none")
+	c.Assert(msg.ACL, Equals, "public-read")
+
+	// Buckets which do not exist are not described.
+	conf.HostURL = server.URL + "/missing"
+	clnt, err = s3New(conf)
+	c.Assert(err, IsNil)
+	_, err = statBucket("myminio/missing", clnt.(*s3Client))
+	c.Assert(err, NotNil)
+}
@@ -91,7 +91,7 @@ func (ds *DummyStatus) PrintMsg(msg message) {
 	if !globalJSON {
 		console.Println(msg.String())
 	} else {
-		console.Println(msg.JSON())
+		console.Println(formatStructured(msg.JSON()))
 	}
 }
 
@@ -157,12 +157,15 @@ func (qs *QuietStatus) Add(v int64) Status {
 func (qs *QuietStatus) Println(data ...interface{}) {
 }
 
-// PrintMsg prints message
+// PrintMsg prints message, unless only the summary is printed
 func (qs *QuietStatus) PrintMsg(msg message) {
+	if globalNoProgress {
+		return
+	}
 	if !globalJSON {
 		console.Println(msg.String())
 	} else {
-		console.Println(msg.JSON())
+		console.Println(formatStructured(msg.JSON()))
 	}
 }
 
@@ -170,9 +173,9 @@ func (qs *QuietStatus) PrintMsg(msg message) {
 func (qs *QuietStatus) Start() {
 }
 
-// Finish displays the accounting summary
+// Finish stops the accounting, the summary is printed by mirror
 func (qs *QuietStatus) Finish() {
-	console.Println(console.Colorize("Mirror", qs.accounter.Stat().String()))
+	qs.accounter.Stat()
 }
 
 // Update is ignored for quietstatus
@@ -189,8 +192,12 @@ func (qs *QuietStatus) fatalIf(err *probe.Error, msg string) {
 
 // NewProgressStatus returns a progress status object
 func NewProgressStatus(hook io.Reader) Status {
+	bar := newProgressBar(0)
+	if parallel, ok := hook.(*ParallelManager); ok {
+		bar.SetWorkers(parallel.workers)
+	}
 	return &ProgressStatus{
-		newProgressBar(0),
+		bar,
 		hook,
 	}
 }
@@ -231,7 +238,7 @@ func (ps *ProgressStatus) Add(v int64) Status {
 
 // Println prints line, ignored for quietstatus
 func (ps *ProgressStatus) Println(data ...interface{}) {
-	console.Eraseline()
+	ps.progressBar.Erase()
 	console.Println(data...)
 }
 
@@ -256,7 +263,7 @@ func (ps *ProgressStatus) Update() {
 
 func (ps *ProgressStatus) errorIf(err *probe.Error, msg string) {
 	// remove progressbar
-	console.Eraseline()
+	ps.progressBar.Erase()
 	errorIf(err, msg)
 
 	ps.progressBar.Update()
@@ -264,7 +271,7 @@ func (ps *ProgressStatus) errorIf(err *probe.Error, msg string) {
 
 func (ps *ProgressStatus) fatalIf(err *probe.Error, msg string) {
 	// remove progressbar
-	console.Eraseline()
+	ps.progressBar.Erase()
 	fatalIf(err, msg)
 
 	ps.progressBar.Update()
@@ -0,0 +1,414 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	// Each connectivity check of an alias gives up after this long.
+	diagCheckTimeout = 10 * time.Second
+
+	// Requests of a trace file included in the diagnostics.
+	diagTraceLines = 1000
+)
+
+var supportDiagFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "archive",
+		Usage: "write the diagnostics to FILE, mc-diag-<time>.zip by default",
+	},
+	cli.StringFlag{
+		Name:  "trace",
+		Usage: "include the last requests of a trace FILE written with --trace-file",
+	},
+	cli.BoolFlag{
+		Name:  "server-info",
+		Usage: "include the health of the servers of every alias, needs admin credentials",
+	},
+}
+
+var supportDiagCmd = cli.Command{
+	Name:   "diag",
+	Usage:  "collect client diagnostics into a zip for support tickets",
+	Action: mainSupportDiag,
+	Before: setGlobalsFromContext,
+	Flags:  append(supportDiagFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [ALIAS...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  The archive holds the version of mc and the operating system, the config
+  without secret keys, MC_* and proxy environment variables with secrets
+  redacted, and the result of DNS, TCP and S3 checks of every alias. All
+  aliases are checked unless some are given.
+
+EXAMPLES:
+   1. Collect diagnostics of all aliases.
+      $ {{.HelpName}}
+
+   2. Collect diagnostics of 'myminio' with the health of its servers, as 'ticket-1234.zip'.
+      $ {{.HelpName}} --server-info --archive ticket-1234.zip myminio
+
+   3. Reproduce a failure with a trace, then collect diagnostics with the last requests of the trace.
+      $ mc --trace-file mc-trace.json cp myfile.txt myminio/mybucket
+      $ {{.HelpName}} --trace mc-trace.json myminio
+`,
+}
+
+// diagSystem - version of mc and the system it runs on.
+type diagSystem struct {
+	Version    string    `json:"version"`
+	ReleaseTag string    `json:"releaseTag"`
+	CommitID   string    `json:"commitID"`
+	GoVersion  string    `json:"goVersion"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	CPUs       int       `json:"cpus"`
+	Time       time.Time `json:"time"`
+}
+
+// diagCheck - connectivity of an alias, step by step.
+type diagCheck struct {
+	Alias     string   `json:"alias"`
+	URL       string   `json:"url"`
+	Addresses []string `json:"addresses,omitempty"`
+	Resolve   string   `json:"resolve,omitempty"`
+	Connect   string   `json:"connect,omitempty"`
+	Request   string   `json:"request,omitempty"`
+	Buckets   int      `json:"buckets"`
+	OK        bool     `json:"ok"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// supportDiagMessage container for the written diagnostics.
+type supportDiagMessage struct {
+	Status  string      `json:"status"`
+	Archive string      `json:"archive"`
+	Checks  []diagCheck `json:"checks"`
+}
+
+// JSON jsonified diagnostics message.
+func (s supportDiagMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// String colorized diagnostics message.
+func (s supportDiagMessage) String() string {
+	var b strings.Builder
+	for _, check := range s.Checks {
+		if check.OK {
+			fmt.Fprintf(&b, "%s: %s (%d buckets listed in %s)\n", check.Alias, console.Colorize("Verified", "ok"), check.Buckets, check.Request)
+		} else {
+			fmt.Fprintf(&b, "%s: %s (%s)\n", check.Alias, console.Colorize("NotVerified", "failed"), check.Error)
+		}
+	}
+	b.WriteString(console.Colorize("Diag", "Diagnostics written to `"+s.Archive+"`, attach it to your support ticket."))
+	return b.String()
+}
+
+// checkAliasConnectivity - resolve, connect and list buckets of an alias,
+// stopping at the first failed step.
+func checkAliasConnectivity(alias string, hostCfg *hostConfigV9) diagCheck {
+	check := diagCheck{Alias: alias, URL: hostCfg.URL}
+	u, e := url.Parse(hostCfg.URL)
+	if e != nil {
+		check.Error = e.Error()
+		return check
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	// Hosts behind a proxy may not resolve or be reachable directly, the
+	// S3 request is made in all cases.
+	start := time.Now()
+	// The first failed step is reported, later ones fail because of it.
+	addresses, e := net.LookupHost(u.Hostname())
+	if e != nil {
+		check.Error = e.Error()
+	} else {
+		check.Addresses = addresses
+		check.Resolve = time.Since(start).String()
+		start = time.Now()
+		conn, e := net.DialTimeout("tcp", net.JoinHostPort(u.Hostname(), port), diagCheckTimeout)
+		if e != nil {
+			check.Error = e.Error()
+		} else {
+			check.Connect = time.Since(start).String()
+			conn.Close()
+		}
+	}
+
+	fail := func(e error) diagCheck {
+		if check.Error == "" {
+			check.Error = e.Error()
+		}
+		return check
+	}
+	clnt, err := newClient(alias)
+	if err != nil {
+		return fail(err.ToGoError())
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return fail(errors.New("alias is not an S3 host"))
+	}
+	type listResult struct {
+		buckets int
+		e       error
+	}
+	resultCh := make(chan listResult, 1)
+	start = time.Now()
+	go func() {
+		buckets, e := s3Clnt.api.ListBuckets()
+		resultCh <- listResult{len(buckets), e}
+	}()
+	select {
+	case result := <-resultCh:
+		if result.e != nil {
+			return fail(result.e)
+		}
+		check.Request = time.Since(start).String()
+		check.Buckets = result.buckets
+		check.OK = true
+		check.Error = ""
+	case <-time.After(diagCheckTimeout):
+		return fail(fmt.Errorf("no response to ListBuckets in %s", diagCheckTimeout))
+	}
+	return check
+}
+
+// diagServerInfo - health of the servers of an alias, the error if it
+// cannot be fetched.
+func diagServerInfo(alias string) interface{} {
+	client, err := newAdminClient(alias)
+	if err != nil {
+		return map[string]string{"error": err.ToGoError().Error()}
+	}
+	serversInfo, e := client.ServerInfo()
+	if e != nil {
+		return map[string]string{"error": e.Error()}
+	}
+	return serversInfo
+}
+
+// diagEnvironment - MC_* and proxy variables of an environment, values
+// carrying secrets are redacted.
+func diagEnvironment(environ []string) map[string]string {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			continue
+		}
+		name, value := kv[:i], kv[i+1:]
+		switch strings.ToUpper(name) {
+		case "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY":
+			if u, e := url.Parse(value); e == nil && u.User != nil {
+				value = redactProxyURL(u)
+			}
+		case "MC_ENCRYPT_KEY", configPassphraseEnv:
+			value = traceRedacted
+		default:
+			if !strings.HasPrefix(name, "MC_") {
+				continue
+			}
+			if strings.HasPrefix(name, mcEnvHostPrefix) || strings.HasPrefix(name, mcEnvHostsDeprecatedPrefix) {
+				value = traceRedacted
+			}
+		}
+		env[name] = value
+	}
+	return env
+}
+
+// tailLines - the last n lines of a reader.
+func tailLines(reader io.Reader, n int) ([]string, error) {
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = append(lines[:0], lines[1:]...)
+		}
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// diagFile - a file of the diagnostics archive, values other than bytes
+// are written as JSON.
+type diagFile struct {
+	name  string
+	value interface{}
+}
+
+// writeDiagFile - add a file to the archive.
+func writeDiagFile(archive *zip.Writer, file diagFile) *probe.Error {
+	data, ok := file.value.([]byte)
+	if !ok {
+		var e error
+		if data, e = json.MarshalIndent(file.value, "", " "); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	w, e := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: UTCNow()})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if _, e = w.Write(data); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// checkSupportDiagSyntax - validate all the passed arguments.
+func checkSupportDiagSyntax(ctx *cli.Context) {
+	for _, alias := range ctx.Args() {
+		if !isValidAlias(alias) {
+			fatalIf(errInvalidAlias(alias), "Invalid alias.")
+		}
+	}
+}
+
+// mainSupportDiag is the handle for "mc support diag" command.
+func mainSupportDiag(ctx *cli.Context) error {
+	checkSupportDiagSyntax(ctx)
+
+	console.SetColor("Diag", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Verified", color.New(color.FgGreen, color.Bold))
+	console.SetColor("NotVerified", color.New(color.FgRed, color.Bold))
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
+
+	aliases := []string(ctx.Args())
+	hosts := make(map[string]hostConfigV9)
+	if len(aliases) == 0 {
+		for alias := range conf.Hosts {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+	}
+	for _, alias := range aliases {
+		hostCfg := mustGetHostConfig(alias)
+		if hostCfg == nil {
+			fatalIf(errInvalidAliasedURL(alias).Trace(alias), "No such alias `"+alias+"` found.")
+		}
+		hosts[alias] = *hostCfg
+	}
+	config, err := exportHosts(hosts, aliases, true, nil)
+	fatalIf(err, "Unable to export hosts.")
+	for alias, hostCfg := range config.Hosts {
+		if u, e := url.Parse(hostCfg.Proxy); e == nil && u.User != nil {
+			hostCfg.Proxy = redactProxyURL(u)
+			config.Hosts[alias] = hostCfg
+		}
+	}
+
+	var trace []byte
+	if traceFile := ctx.String("trace"); traceFile != "" {
+		f, e := os.Open(traceFile)
+		fatalIf(probe.NewError(e).Trace(traceFile), "Unable to open the trace file.")
+		lines, e := tailLines(f, diagTraceLines)
+		f.Close()
+		fatalIf(probe.NewError(e).Trace(traceFile), "Unable to read the trace file.")
+		trace = []byte(strings.Join(lines, "\n") + "\n")
+	}
+
+	checks := make([]diagCheck, 0, len(aliases))
+	serverInfo := make(map[string]interface{})
+	for _, alias := range aliases {
+		hostCfg := hosts[alias]
+		checks = append(checks, checkAliasConnectivity(alias, &hostCfg))
+		if ctx.Bool("server-info") {
+			serverInfo[alias] = diagServerInfo(alias)
+		}
+	}
+
+	now := UTCNow()
+	archivePath := ctx.String("archive")
+	if archivePath == "" {
+		archivePath = "mc-diag-" + now.Format("20060102T150405Z") + ".zip"
+	}
+	f, e := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	fatalIf(probe.NewError(e).Trace(archivePath), "Unable to create the diagnostics archive.")
+	archive := zip.NewWriter(f)
+
+	files := []diagFile{
+		{"system.json", diagSystem{
+			Version:    Version,
+			ReleaseTag: ReleaseTag,
+			CommitID:   CommitID,
+			GoVersion:  runtime.Version(),
+			OS:         runtime.GOOS,
+			Arch:       runtime.GOARCH,
+			CPUs:       runtime.NumCPU(),
+			Time:       now,
+		}},
+		{"config.json", config},
+		{"environment.json", diagEnvironment(os.Environ())},
+		{"connectivity.json", checks},
+	}
+	if trace != nil {
+		files = append(files, diagFile{"trace.json", trace})
+	}
+	if ctx.Bool("server-info") {
+		files = append(files, diagFile{"server-info.json", serverInfo})
+	}
+	for _, file := range files {
+		fatalIf(writeDiagFile(archive, file).Trace(archivePath), "Unable to write the diagnostics archive.")
+	}
+	fatalIf(probe.NewError(archive.Close()).Trace(archivePath), "Unable to write the diagnostics archive.")
+	fatalIf(probe.NewError(f.Close()).Trace(archivePath), "Unable to write the diagnostics archive.")
+
+	printMsg(supportDiagMessage{Archive: archivePath, Checks: checks})
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+const (
+	// Admin API path of the raw files of the drives of a server.
+	inspectDataPath = "/minio/admin/v3/inspect-data"
+
+	// Format of an inspect response with the key ahead of the data.
+	inspectFormatKey = 1
+
+	// Size of the key the data of an inspect response is encrypted with.
+	inspectKeySize = 32
+)
+
+var supportInspectFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "archive",
+		Usage: "write the encrypted data to FILE, mc-inspect-<time>.enc by default",
+	},
+}
+
+var supportInspectCmd = cli.Command{
+	Name:   "inspect",
+	Usage:  "download the raw files of an object from the drives of a server",
+	Action: mainSupportInspect,
+	Before: setGlobalsFromContext,
+	Flags:  append(supportInspectFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  TARGET is ALIAS/BUCKET/PATH of files on the drives of the servers, such
+  as the 'xl.meta' and the parts of an object, '*' matches any name. The
+  server sends the files of all drives zipped and encrypted with a key of
+  its own, the data is written as received and the key is printed. Send
+  the archive and the key separately to support. Needs admin credentials
+  and a server with the inspect admin API.
+
+EXAMPLES:
+   1. Download the metadata of the object 'photos/2019/beach.jpg' from all drives of 'myminio'.
+      $ {{.HelpName}} myminio/mybucket/photos/2019/beach.jpg/xl.meta
+
+   2. Download the metadata and the raw parts of an object as 'ticket-1234.enc'.
+      $ {{.HelpName}} --archive ticket-1234.enc myminio/mybucket/photos/2019/beach.jpg/*
+
+   3. Download the metadata of all objects below a prefix.
+      $ {{.HelpName}} myminio/mybucket/photos/2019/*/xl.meta
+`,
+}
+
+// supportInspectMessage container for the downloaded data.
+type supportInspectMessage struct {
+	Status  string `json:"status"`
+	Target  string `json:"target"`
+	Archive string `json:"archive"`
+	Key     string `json:"key"`
+	Size    int64  `json:"size"`
+}
+
+// JSON jsonified inspect message.
+func (s supportInspectMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// String colorized inspect message.
+func (s supportInspectMessage) String() string {
+	return console.Colorize("Inspect", fmt.Sprintf("Encrypted data of `%s` written to `%s`.", s.Target, s.Archive)) + "\n" +
+		"Decryption key: " + console.Colorize("Key", s.Key) + "\n" +
+		"Send the archive and the key separately to support."
+}
+
+// inspectDataWriter - writes the data of an inspect response, past the
+// format and the key the server sends ahead of it.
+type inspectDataWriter struct {
+	header []byte
+	data   io.Writer
+	size   int64
+}
+
+func (w *inspectDataWriter) Write(p []byte) (n int, e error) {
+	if len(w.header) < 1+inspectKeySize {
+		n = 1 + inspectKeySize - len(w.header)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.header = append(w.header, p[:n]...)
+		p = p[n:]
+		if w.header[0] != inspectFormatKey {
+			return n, fmt.Errorf("unsupported inspect data format %d", w.header[0])
+		}
+	}
+	m, e := w.data.Write(p)
+	w.size += int64(m)
+	return n + m, e
+}
+
+// inspectData - request the raw files matching a path of a volume from
+// the drives of the server and write them to data. The data is encrypted
+// by the server with the key it sends ahead of it.
+func (c *adminAPIClient) inspectData(volume, file string, data io.Writer) (key []byte, size int64, err *probe.Error) {
+	query := url.Values{}
+	query.Set("volume", volume)
+	query.Set("file", file)
+	w := &inspectDataWriter{data: data}
+	if err = c.do(http.MethodGet, inspectDataPath, query, nil, w); err != nil {
+		return nil, 0, err.Trace(volume, file)
+	}
+	if len(w.header) < 1+inspectKeySize {
+		return nil, 0, probe.NewError(io.ErrUnexpectedEOF).Trace(volume, file)
+	}
+	return w.header[1:], w.size, nil
+}
+
+// checkSupportInspectSyntax - validate all the passed arguments.
+func checkSupportInspectSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "inspect", 1) // last argument is exit code
+	}
+}
+
+// writeInspectData - write the inspected data to a new file, removed if
+// it is not completely written, and return its key.
+func writeInspectData(archivePath string, clnt *adminAPIClient, volume, file string) (key []byte, size int64, err *probe.Error) {
+	f, e := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if e != nil {
+		return nil, 0, probe.NewError(e)
+	}
+	key, size, err = clnt.inspectData(volume, file, f)
+	if e = f.Close(); e != nil && err == nil {
+		err = probe.NewError(e)
+	}
+	if err != nil {
+		os.Remove(archivePath)
+		return nil, 0, err.Trace(archivePath)
+	}
+	return key, size, nil
+}
+
+// mainSupportInspect is the handle for "mc support inspect" command.
+func mainSupportInspect(ctx *cli.Context) error {
+	checkSupportInspectSyntax(ctx)
+
+	console.SetColor("Inspect", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Key", color.New(color.FgYellow, color.Bold))
+
+	target := ctx.Args().Get(0)
+	alias, path := url2Alias(target)
+	pathParts := splitStr(filepath.ToSlash(path), "/", 2)
+	volume, file := pathParts[0], pathParts[1]
+	if volume == "" || file == "" {
+		fatalIf(errInvalidArgument().Trace(target), "Inspect needs a bucket and the path of the files, such as `ALIAS/BUCKET/OBJECT/xl.meta`.")
+	}
+	clnt, err := newAdminAPIClient(alias)
+	fatalIf(err.Trace(target), "Unable to initialize admin connection with `"+alias+"`.")
+
+	archivePath := ctx.String("archive")
+	if archivePath == "" {
+		archivePath = "mc-inspect-" + UTCNow().Format("20060102T150405Z") + ".enc"
+	}
+	key, size, err := writeInspectData(archivePath, clnt, volume, file)
+	fatalIf(err.Trace(target), "Unable to inspect `"+target+"`.")
+
+	printMsg(supportInspectMessage{Target: target, Archive: archivePath, Key: hex.EncodeToString(key), Size: size})
+	return nil
+}
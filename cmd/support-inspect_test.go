@@ -0,0 +1,89 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInspectData(t *testing.T) {
+	key := bytes.Repeat([]byte{0xab}, inspectKeySize)
+	response := append(append([]byte{inspectFormatKey}, key...), "encrypted zip"...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != inspectDataPath || !strings.HasPrefix(r.Header.Get("Authorization"), signV4Algorithm):
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Query().Get("volume") == "missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"Code":"XMinioAdminNoSuchBucket","Message":"The specified bucket does not exist"}`))
+		case r.URL.Query().Get("volume") == "newer":
+			w.Write([]byte{2})
+		case r.URL.Query().Get("volume") != "bucket" || r.URL.Query().Get("file") != "my dir/object/xl.meta":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.Write(response)
+		}
+	}))
+	defer server.Close()
+
+	clnt, err := s3New(&Config{
+		HostURL:   server.URL,
+		AccessKey: "WLGDGYAQYIGI833EV05A",
+		SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+		Signature: "S3v4",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiClient := &adminAPIClient{s3Client: clnt.(*s3Client)}
+	file := "my dir/object/xl.meta"
+
+	dir, e := ioutil.TempDir("", "mc-inspect-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	archivePath := filepath.Join(dir, "inspect.enc")
+	received, size, err := writeInspectData(archivePath, apiClient, "bucket", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(received, key) {
+		t.Errorf("expected key %x, got %x", key, received)
+	}
+	if written, e := ioutil.ReadFile(archivePath); e != nil || string(written) != "encrypted zip" || size != int64(len(written)) {
+		t.Errorf("expected the encrypted data to be written, got %q %d %v", written, size, e)
+	}
+
+	if _, _, err = writeInspectData(archivePath, apiClient, "missing", file); err == nil || !strings.Contains(err.ToGoError().Error(), "bucket does not exist") {
+		t.Errorf("expected the error of the server, got %v", err)
+	}
+	if _, _, err = apiClient.inspectData("newer", file, ioutil.Discard); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	// The archive of a failed inspect is removed.
+	if _, e = os.Stat(archivePath); !os.IsNotExist(e) {
+		t.Errorf("expected the archive to be removed, got %v", e)
+	}
+}
@@ -0,0 +1,310 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var supportTLSCmd = cli.Command{
+	Name:   "tls",
+	Usage:  "show TLS connection details of an alias",
+	Action: mainSupportTLS,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Show negotiated TLS version, cipher and certificate chain of 'myminio'.
+      $ {{.HelpName}} myminio
+
+   2. Show certificate chain of 'myminio' in JSON, e.g. to copy a fingerprint to pin.
+      $ {{.HelpName}} --json myminio
+`,
+}
+
+// tlsVersionNames maps TLS protocol versions to their names.
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// tlsCipherSuiteNames maps the cipher suites of crypto/tls to their names.
+var tlsCipherSuiteNames = map[uint16]string{
+	tls.TLS_RSA_WITH_RC4_128_SHA:                "TLS_RSA_WITH_RC4_128_SHA",
+	tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA:           "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA:            "TLS_RSA_WITH_AES_128_CBC_SHA",
+	tls.TLS_RSA_WITH_AES_256_CBC_SHA:            "TLS_RSA_WITH_AES_256_CBC_SHA",
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA256:         "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256:         "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384:         "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	tls.TLS_ECDHE_ECDSA_WITH_RC4_128_SHA:        "TLS_ECDHE_ECDSA_WITH_RC4_128_SHA",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA:    "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA:    "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	tls.TLS_ECDHE_RSA_WITH_RC4_128_SHA:          "TLS_ECDHE_RSA_WITH_RC4_128_SHA",
+	tls.TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA:     "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA:      "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA:      "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256: "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256:   "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305:    "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305:  "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	tls.TLS_AES_128_GCM_SHA256:                  "TLS_AES_128_GCM_SHA256",
+	tls.TLS_AES_256_GCM_SHA384:                  "TLS_AES_256_GCM_SHA384",
+	tls.TLS_CHACHA20_POLY1305_SHA256:            "TLS_CHACHA20_POLY1305_SHA256",
+}
+
+// tlsCipherSuiteName - the name of a cipher suite, its hex ID if unknown.
+func tlsCipherSuiteName(id uint16) string {
+	if name, ok := tlsCipherSuiteNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", id)
+}
+
+// tlsCertificateInfo - details of a certificate presented by the server.
+type tlsCertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serialNumber"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	ExpiresIn    int       `json:"expiresInDays"`
+	DNSNames     []string  `json:"dnsNames,omitempty"`
+	IPAddresses  []string  `json:"ipAddresses,omitempty"`
+	Fingerprint  string    `json:"fingerprint"`
+}
+
+// supportTLSMessage container for TLS connection details.
+type supportTLSMessage struct {
+	Status       string               `json:"status"`
+	Alias        string               `json:"alias"`
+	Endpoint     string               `json:"endpoint"`
+	Version      string               `json:"version"`
+	CipherSuite  string               `json:"cipherSuite"`
+	Pinned       bool                 `json:"pinned"`
+	Verified     bool                 `json:"verified"`
+	VerifyError  string               `json:"verifyError,omitempty"`
+	Certificates []tlsCertificateInfo `json:"certificates"`
+}
+
+// JSON jsonified TLS connection details.
+func (s supportTLSMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// String colorized TLS connection details.
+func (s supportTLSMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, console.Colorize("TLS", fmt.Sprintf("TLS connection to `%s` (%s):", s.Endpoint, s.Alias)))
+	fmt.Fprintf(&b, "  %-12s: %s\n", "Version", s.Version)
+	fmt.Fprintf(&b, "  %-12s: %s\n", "Cipher", s.CipherSuite)
+	verification := "certificate authority"
+	if s.Pinned {
+		verification = "pinned fingerprint"
+	}
+	if s.Verified {
+		fmt.Fprintf(&b, "  %-12s: %s (%s)\n", "Verified", console.Colorize("Verified", "yes"), verification)
+	} else {
+		fmt.Fprintf(&b, "  %-12s: %s (%s: %s)\n", "Verified", console.Colorize("NotVerified", "no"), verification, s.VerifyError)
+	}
+	for i, cert := range s.Certificates {
+		fmt.Fprintln(&b, console.Colorize("TLS", fmt.Sprintf("Certificate #%d:", i)))
+		fmt.Fprintf(&b, "  %-12s: %s\n", "Subject", cert.Subject)
+		fmt.Fprintf(&b, "  %-12s: %s\n", "Issuer", cert.Issuer)
+		if len(cert.DNSNames) > 0 || len(cert.IPAddresses) > 0 {
+			fmt.Fprintf(&b, "  %-12s: %s\n", "SANs", strings.Join(append(cert.DNSNames, cert.IPAddresses...), ", "))
+		}
+		fmt.Fprintf(&b, "  %-12s: %s\n", "Valid from", cert.NotBefore.Format(printDate))
+		expiry := fmt.Sprintf("%s (in %d days)", cert.NotAfter.Format(printDate), cert.ExpiresIn)
+		if cert.ExpiresIn < 0 {
+			expiry = console.Colorize("NotVerified", fmt.Sprintf("%s (expired %d days ago)", cert.NotAfter.Format(printDate), -cert.ExpiresIn))
+		}
+		fmt.Fprintf(&b, "  %-12s: %s\n", "Valid until", expiry)
+		fmt.Fprintf(&b, "  %-12s: %s\n", "SHA-256", cert.Fingerprint)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// newTLSCertificateInfo extracts the details shown for a certificate.
+func newTLSCertificateInfo(cert *x509.Certificate, now time.Time) tlsCertificateInfo {
+	info := tlsCertificateInfo{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		ExpiresIn:    int(cert.NotAfter.Sub(now).Hours() / 24),
+		DNSNames:     cert.DNSNames,
+		Fingerprint:  formatFingerprint(certFingerprint(cert)),
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	return info
+}
+
+// verifyTLSChain verifies the certificates presented by the server the
+// same way regular commands would, either by the pinned fingerprint or
+// against the configured certificate authorities.
+func verifyTLSChain(certs []*x509.Certificate, serverName string, config *Config, tlsConfig *tls.Config) error {
+	if len(certs) == 0 {
+		return fmt.Errorf("server did not present any certificate")
+	}
+	if config.Fingerprint != "" {
+		fingerprint, err := parseFingerprint(config.Fingerprint)
+		if err != nil {
+			return err.ToGoError()
+		}
+		if !bytes.Equal(certFingerprint(certs[0]), fingerprint) {
+			return fmt.Errorf("fingerprint does not match pinned fingerprint %s", formatFingerprint(fingerprint))
+		}
+		return nil
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, e := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         tlsConfig.RootCAs,
+		Intermediates: intermediates,
+	})
+	return e
+}
+
+// getAliasTLS - connect to the TLS endpoint of an alias, reporting the
+// negotiated connection and the certificate chain presented.
+func getAliasTLS(alias string, hostCfg *hostConfigV9) (supportTLSMessage, *probe.Error) {
+	u, e := url.Parse(hostCfg.URL)
+	if e != nil {
+		return supportTLSMessage{}, probe.NewError(e).Trace(hostCfg.URL)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	s3Config := newS3Config(hostCfg.URL, hostCfg)
+	tlsConfig := &tls.Config{RootCAs: globalRootCAs}
+	if err := setHostTLSConfig(tlsConfig, s3Config); err != nil {
+		return supportTLSMessage{}, err.Trace(alias)
+	}
+
+	// Always complete the handshake so the chain can be inspected,
+	// verification is done separately below.
+	dialConfig := tlsConfig.Clone()
+	dialConfig.ServerName = u.Hostname()
+	dialConfig.InsecureSkipVerify = true
+	dialConfig.VerifyPeerCertificate = nil
+
+	conn, e := tls.DialWithDialer(&net.Dialer{Timeout: 15 * time.Second}, "tcp", host, dialConfig)
+	if e != nil {
+		return supportTLSMessage{}, probe.NewError(e).Trace(host)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	msg := supportTLSMessage{
+		Alias:       alias,
+		Endpoint:    host,
+		Version:     tlsVersionNames[state.Version],
+		CipherSuite: tlsCipherSuiteName(state.CipherSuite),
+		Pinned:      s3Config.Fingerprint != "",
+	}
+	if msg.Version == "" {
+		msg.Version = fmt.Sprintf("0x%04x", state.Version)
+	}
+	now := UTCNow()
+	for _, cert := range state.PeerCertificates {
+		msg.Certificates = append(msg.Certificates, newTLSCertificateInfo(cert, now))
+	}
+	if e = verifyTLSChain(state.PeerCertificates, u.Hostname(), s3Config, tlsConfig); e != nil {
+		if globalInsecure {
+			msg.VerifyError = e.Error() + ", ignored due to --insecure"
+		} else {
+			msg.VerifyError = e.Error()
+		}
+	} else {
+		msg.Verified = true
+	}
+
+	return msg, nil
+}
+
+// checkSupportTLSSyntax - validate all the passed arguments
+func checkSupportTLSSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "tls", 1) // last argument is exit code
+	}
+}
+
+// mainSupportTLS is the handle for "mc support tls" command.
+func mainSupportTLS(ctx *cli.Context) error {
+	checkSupportTLSSyntax(ctx)
+
+	console.SetColor("TLS", color.New(color.FgCyan, color.Bold))
+	console.SetColor("Verified", color.New(color.FgGreen, color.Bold))
+	console.SetColor("NotVerified", color.New(color.FgRed, color.Bold))
+
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+	alias, _, hostCfg, err := expandAlias(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to get alias configuration.")
+	if hostCfg == nil {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "No such alias `"+alias+"` found.")
+	}
+
+	if !strings.HasPrefix(hostCfg.URL, "https://") {
+		fatalIf(errInvalidArgument().Trace(hostCfg.URL), "Alias `"+alias+"` does not use TLS.")
+	}
+	msg, err := getAliasTLS(alias, hostCfg)
+	fatalIf(err, "Unable to establish TLS connection to `"+alias+"`.")
+
+	printMsg(msg)
+	return nil
+}
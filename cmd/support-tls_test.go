@@ -0,0 +1,39 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestTLSCipherSuiteName(t *testing.T) {
+	testCases := []struct {
+		id   uint16
+		name string
+	}{
+		{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		{tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305, "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256"},
+		{tls.TLS_AES_256_GCM_SHA384, "TLS_AES_256_GCM_SHA384"},
+		{0x1234, "0x1234"},
+	}
+	for i, testCase := range testCases {
+		if name := tlsCipherSuiteName(testCase.id); name != testCase.name {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.name, name)
+		}
+	}
+}
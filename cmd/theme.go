@@ -0,0 +1,207 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Built-in themes.
+const (
+	themeDefault      = "default"
+	themeHighContrast = "high-contrast"
+	themeMonochrome   = "monochrome"
+)
+
+// Environment variable overriding the theme of the config file, like
+// "theme=monochrome:Size=yellow,bold:Time=none".
+const mcColorsEnv = "MC_COLORS"
+
+// Names of the attributes of a color spec.
+var colorSpecAttributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"blink":     color.BlinkSlow,
+	"reverse":   color.ReverseVideo,
+}
+
+// Names of the colors of a color spec, 'hi-' selects the bright variant
+// and 'bg-' the background.
+var colorSpecColors = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+// parseColorSpec parses a color like "hi-red,bold" or "bg-blue,white",
+// "none" disables the color of a tag.
+func parseColorSpec(spec string) (*color.Color, *probe.Error) {
+	var attrs []color.Attribute
+	for _, name := range strings.Split(strings.ToLower(spec), ",") {
+		name = strings.TrimSpace(name)
+		if name == "none" || name == "" {
+			continue
+		}
+		if attr, ok := colorSpecAttributes[name]; ok {
+			attrs = append(attrs, attr)
+			continue
+		}
+		var offset color.Attribute
+		if strings.HasPrefix(name, "bg-") {
+			name, offset = strings.TrimPrefix(name, "bg-"), color.BgBlack-color.FgBlack
+		}
+		if strings.HasPrefix(name, "hi-") {
+			name, offset = strings.TrimPrefix(name, "hi-"), offset+color.FgHiBlack-color.FgBlack
+		}
+		attr, ok := colorSpecColors[name]
+		if !ok {
+			return nil, probe.NewError(fmt.Errorf("unknown color `%s`", name)).Trace(spec)
+		}
+		attrs = append(attrs, attr+offset)
+	}
+	return color.New(attrs...), nil
+}
+
+// isForeground - returns true if an attribute is a foreground color.
+func isForeground(attr color.Attribute) bool {
+	return (attr >= color.FgBlack && attr <= color.FgWhite) || (attr >= color.FgHiBlack && attr <= color.FgHiWhite)
+}
+
+// isBackground - returns true if an attribute is a background color.
+func isBackground(attr color.Attribute) bool {
+	return (attr >= color.BgBlack && attr <= color.BgWhite) || (attr >= color.BgHiBlack && attr <= color.BgHiWhite)
+}
+
+// highContrastColors - bright colors instead of dim, faint and dark
+// blue ones, which are hard to read on many terminals.
+func highContrastColors(attrs []color.Attribute) []color.Attribute {
+	var filtered []color.Attribute
+	for _, attr := range attrs {
+		switch {
+		case attr == color.Faint:
+			continue
+		case attr == color.FgBlue || attr == color.FgHiBlue:
+			attr = color.FgHiCyan
+		case attr >= color.FgBlack && attr <= color.FgWhite:
+			attr += color.FgHiBlack - color.FgBlack
+		}
+		filtered = append(filtered, attr)
+	}
+	return filtered
+}
+
+// monochromeColors - no colors, bold, underlined and reversed text is
+// kept to tell tags apart.
+func monochromeColors(attrs []color.Attribute) []color.Attribute {
+	var filtered []color.Attribute
+	for _, attr := range attrs {
+		if isForeground(attr) || isBackground(attr) || attr == color.Faint {
+			continue
+		}
+		filtered = append(filtered, attr)
+	}
+	return filtered
+}
+
+// themeColorFilter - the color filter of a built-in theme.
+func themeColorFilter(theme string) (func([]color.Attribute) []color.Attribute, *probe.Error) {
+	switch theme {
+	case "", themeDefault:
+		return nil, nil
+	case themeHighContrast:
+		return highContrastColors, nil
+	case themeMonochrome:
+		return monochromeColors, nil
+	}
+	return nil, probe.NewError(fmt.Errorf("unknown theme `%s`, `%s`, `%s` or `%s` is expected", theme, themeDefault, themeHighContrast, themeMonochrome))
+}
+
+// parseColorsEnv parses MC_COLORS, a list of tag=color separated by ':'
+// where the tag 'theme' selects a built-in theme.
+func parseColorsEnv(env string) (*themeConfigV9, *probe.Error) {
+	theme := &themeConfigV9{Colors: map[string]string{}}
+	for _, entry := range strings.Split(env, ":") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, probe.NewError(fmt.Errorf("`%s` is not a tag=color pair", entry)).Trace(env)
+		}
+		if kv[0] == "theme" {
+			theme.Name = kv[1]
+			continue
+		}
+		theme.Colors[kv[0]] = kv[1]
+	}
+	return theme, nil
+}
+
+// applyTheme - set the colors of a theme for the entire session.
+func applyTheme(theme *themeConfigV9) *probe.Error {
+	filter, err := themeColorFilter(strings.ToLower(theme.Name))
+	if err != nil {
+		return err.Trace(theme.Name)
+	}
+	// Sorted, so that errors are reported in the same order every time.
+	var tags []string
+	for tag := range theme.Colors {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		cl, err := parseColorSpec(theme.Colors[tag])
+		if err != nil {
+			return err.Trace(tag)
+		}
+		console.SetColorOverride(tag, cl)
+	}
+	if filter != nil {
+		console.SetColorFilter(filter)
+	}
+	return nil
+}
+
+// setThemeFromConfig - set the colors of the theme of the config file,
+// MC_COLORS replaces it when set.
+func setThemeFromConfig() {
+	if env, ok := os.LookupEnv(mcColorsEnv); ok {
+		theme, err := parseColorsEnv(env)
+		fatalIf(err, "Unable to parse `"+mcColorsEnv+"`.")
+		fatalIf(applyTheme(theme), "Unable to use the colors of `"+mcColorsEnv+"`.")
+		return
+	}
+	config, err := loadMcConfig()
+	if err != nil || config.Theme == nil {
+		return
+	}
+	fatalIf(applyTheme(config.Theme), "Unable to use the theme of `"+mustGetMcConfigPath()+"`.")
+}
@@ -0,0 +1,77 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/minio/mc/pkg/console"
+)
+
+func TestParseColorSpec(t *testing.T) {
+	testCases := []struct {
+		spec    string
+		color   *color.Color
+		success bool
+	}{
+		{"red", color.New(color.FgRed), true},
+		{"Hi-Red, bold", color.New(color.FgHiRed, color.Bold), true},
+		{"bg-blue,white", color.New(color.BgBlue, color.FgWhite), true},
+		{"bg-hi-yellow,black,underline", color.New(color.BgHiYellow, color.FgBlack, color.Underline), true},
+		{"none", color.New(), true},
+		{"purple", nil, false},
+		{"bg-bold", nil, false},
+	}
+	for i, testCase := range testCases {
+		cl, err := parseColorSpec(testCase.spec)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: %q: expected success %v, got %v", i+1, testCase.spec, testCase.success, err)
+		}
+		if err == nil && !cl.Equals(testCase.color) {
+			t.Errorf("Test %d: %q: unexpected attributes %v", i+1, testCase.spec, console.ColorAttributes(cl))
+		}
+	}
+}
+
+func TestParseColorsEnv(t *testing.T) {
+	theme, err := parseColorsEnv("theme=monochrome:Size=yellow,bold::Time=none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &themeConfigV9{Name: themeMonochrome, Colors: map[string]string{"Size": "yellow,bold", "Time": "none"}}
+	if !reflect.DeepEqual(theme, expected) {
+		t.Errorf("expected %+v, got %+v", expected, theme)
+	}
+	if _, err = parseColorsEnv("Size"); err == nil {
+		t.Error("expected an error for a tag without color")
+	}
+}
+
+func TestThemeColorFilters(t *testing.T) {
+	attrs := []color.Attribute{color.FgBlue, color.Bold, color.BgRed, color.Faint, color.FgGreen}
+	if got, expected := highContrastColors(attrs), []color.Attribute{color.FgHiCyan, color.Bold, color.BgRed, color.FgHiGreen}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("high contrast: expected %v, got %v", expected, got)
+	}
+	if got, expected := monochromeColors(attrs), []color.Attribute{color.Bold}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("monochrome: expected %v, got %v", expected, got)
+	}
+	if _, err := themeColorFilter("sepia"); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+}
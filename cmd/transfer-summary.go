@@ -0,0 +1,121 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// transferSummary - counts the objects transferred, skipped and failed
+// by cp and mirror, for the summary printed once they are done.
+type transferSummary struct {
+	mutex     sync.Mutex
+	startTime time.Time
+	objects   transferObjects
+	bytes     int64
+}
+
+// transferObjects - number of objects by outcome.
+type transferObjects struct {
+	Transferred int64 `json:"transferred"`
+	Skipped     int64 `json:"skipped"`
+	Failed      int64 `json:"failed"`
+}
+
+// newTransferSummary - start counting a transfer.
+func newTransferSummary() *transferSummary {
+	return &transferSummary{startTime: time.Now()}
+}
+
+// done - count the outcome of a copy, removals are not counted.
+func (s *transferSummary) done(sURLs URLs) {
+	if sURLs.SourceContent == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	switch {
+	case sURLs.Error != nil:
+		s.objects.Failed++
+	case sURLs.Skipped:
+		s.objects.Skipped++
+	default:
+		s.objects.Transferred++
+		s.bytes += sURLs.SourceContent.Size
+	}
+}
+
+// message - the summary of the transfer so far, total is the size of
+// all objects to transfer.
+func (s *transferSummary) message(total int64) transferSummaryMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	duration := time.Since(s.startTime)
+	msg := transferSummaryMessage{
+		Total:       total,
+		Transferred: s.bytes,
+		Duration:    duration.Seconds(),
+		Objects:     s.objects,
+	}
+	if duration > 0 {
+		msg.Speed = float64(s.bytes) / duration.Seconds()
+	}
+	return msg
+}
+
+// transferSummaryMessage container for the summary printed at the end
+// of cp and mirror. Sizes are in bytes, the speed in bytes per second
+// and the duration in seconds.
+type transferSummaryMessage struct {
+	Status      string          `json:"status"`
+	Total       int64           `json:"total"`
+	Transferred int64           `json:"transferred"`
+	Speed       float64         `json:"speed"`
+	Duration    float64         `json:"duration"`
+	Objects     transferObjects `json:"objects"`
+
+	// Set if the transfer was interrupted before completion.
+	Interrupted bool `json:"-"`
+}
+
+// String colorized transfer summary message.
+func (s transferSummaryMessage) String() string {
+	stat := accountStat{Total: s.Total, Transferred: s.Transferred, Speed: s.Speed}
+	duration := time.Duration(s.Duration * float64(time.Second)).Round(time.Second)
+	if s.Interrupted {
+		return fmt.Sprintf("Interrupted, %s, Duration: %s, Objects: %d transferred, %d skipped, %d failed",
+			stat, duration, s.Objects.Transferred, s.Objects.Skipped, s.Objects.Failed)
+	}
+	return fmt.Sprintf("%s, Duration: %s, Objects: %d transferred, %d skipped, %d failed",
+		stat, duration, s.Objects.Transferred, s.Objects.Skipped, s.Objects.Failed)
+}
+
+// JSON jsonified transfer summary message.
+func (s transferSummaryMessage) JSON() string {
+	s.Status = "success"
+	if s.Interrupted {
+		s.Status = "interrupted"
+	}
+	summaryJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal transfer summary.")
+	return string(summaryJSONBytes)
+}
@@ -0,0 +1,75 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTransferSummary(t *testing.T) {
+	summary := newTransferSummary()
+	for _, sURLs := range []URLs{
+		{SourceContent: &clientContent{Size: 100}},
+		{SourceContent: &clientContent{Size: 200}},
+		{SourceContent: &clientContent{Size: 400}, Skipped: true},
+		{SourceContent: &clientContent{Size: 800}, Error: errDummy()},
+		// Removals are not counted.
+		{TargetContent: &clientContent{Size: 1600}},
+	} {
+		summary.done(sURLs)
+	}
+	msg := summary.message(1500)
+	expected := transferObjects{Transferred: 2, Skipped: 1, Failed: 1}
+	if msg.Objects != expected {
+		t.Errorf("expected %v, got %v", expected, msg.Objects)
+	}
+	if msg.Total != 1500 || msg.Transferred != 300 {
+		t.Errorf("expected 300 of 1500 bytes, got %d of %d", msg.Transferred, msg.Total)
+	}
+
+	if str := msg.String(); !strings.HasSuffix(str, "Objects: 2 transferred, 1 skipped, 1 failed") {
+		t.Errorf("unexpected summary %q", str)
+	}
+	var parsed map[string]interface{}
+	if e := json.Unmarshal([]byte(msg.JSON()), &parsed); e != nil {
+		t.Fatal(e)
+	}
+	for _, key := range []string{"status", "total", "transferred", "speed", "duration", "objects"} {
+		if _, ok := parsed[key]; !ok {
+			t.Errorf("missing %q in %v", key, parsed)
+		}
+	}
+}
+
+func TestTransferSummaryInterrupted(t *testing.T) {
+	summary := newTransferSummary()
+	summary.done(URLs{SourceContent: &clientContent{Size: 100}})
+	msg := summary.message(1000)
+	msg.Interrupted = true
+	if str := msg.String(); !strings.HasPrefix(str, "Interrupted, ") {
+		t.Errorf("unexpected summary %q", str)
+	}
+	var parsed map[string]interface{}
+	if e := json.Unmarshal([]byte(msg.JSON()), &parsed); e != nil {
+		t.Fatal(e)
+	}
+	if parsed["status"] != "interrupted" {
+		t.Errorf("expected status interrupted, got %v", parsed["status"])
+	}
+}
@@ -0,0 +1,381 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// transformsFlag is shared by all commands able to filter downloaded
+// objects with the built-in transforms.
+var transformsFlag = cli.StringSliceFlag{
+	Name:  "transform",
+	Usage: "transform each source object with a built-in filter: gunzip, bunzip2, gzip, jq:FILTER or jq-raw:FILTER",
+}
+
+// transformFunc - a built-in transform of a source stream.
+type transformFunc func(source io.Reader) (io.ReadCloser, error)
+
+// parseTransform - the built-in transform of a --transform value, like
+// 'gunzip' or 'jq:.items[].name'.
+func parseTransform(spec string) (transformFunc, *probe.Error) {
+	name, arg := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		name, arg = spec[:i], spec[i+1:]
+	}
+	switch name {
+	case "gunzip":
+		if arg != "" {
+			break
+		}
+		return func(source io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(source)
+		}, nil
+	case "bunzip2":
+		if arg != "" {
+			break
+		}
+		return func(source io.Reader) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bzip2.NewReader(source)), nil
+		}, nil
+	case "gzip":
+		if arg != "" {
+			break
+		}
+		return func(source io.Reader) (io.ReadCloser, error) {
+			return newPipeTransform(func(w io.Writer) error {
+				gw := gzip.NewWriter(w)
+				if _, e := io.Copy(gw, source); e != nil {
+					return e
+				}
+				return gw.Close()
+			}), nil
+		}, nil
+	case "jq", "jq-raw":
+		steps, e := parseJQFilter(arg)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(spec)
+		}
+		raw := name == "jq-raw"
+		return func(source io.Reader) (io.ReadCloser, error) {
+			return newPipeTransform(func(w io.Writer) error {
+				return filterJSON(w, source, steps, raw)
+			}), nil
+		}, nil
+	}
+	return nil, errInvalidArgument().Trace(spec)
+}
+
+// checkTransforms - exit on --transform values which are no built-in
+// transforms, before anything is copied.
+func checkTransforms(transforms []string) {
+	for _, spec := range transforms {
+		_, err := parseTransform(spec)
+		fatalIf(err, "Invalid transform `"+spec+"`, expected gunzip, bunzip2, gzip, jq:FILTER or jq-raw:FILTER.")
+	}
+}
+
+// newPipeTransform - the output written by transform, which runs in the
+// background until it is done or until the output is closed.
+func newPipeTransform(transform func(w io.Writer) error) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(transform(pw))
+	}()
+	return pr
+}
+
+// transformChain - the output of the transforms of an object, closing
+// it closes all of them, the last one first.
+type transformChain struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (c *transformChain) Close() error {
+	for i := len(c.closers) - 1; i >= 0; i-- {
+		c.closers[i].Close()
+	}
+	return nil
+}
+
+// newTransformsReader - the source transformed by the built-in
+// transforms in order, and then by the filter program command if any.
+func newTransformsReader(transforms []string, command string, source io.Reader, sourceURL, targetURL string) (io.ReadCloser, *probe.Error) {
+	chain := &transformChain{Reader: source}
+	for _, spec := range transforms {
+		transform, err := parseTransform(spec)
+		if err != nil {
+			chain.Close()
+			return nil, err.Trace(sourceURL)
+		}
+		reader, e := transform(chain.Reader)
+		if e != nil {
+			chain.Close()
+			return nil, probe.NewError(fmt.Errorf("transform `%s` failed: %s", spec, e)).Trace(sourceURL)
+		}
+		chain.Reader = reader
+		chain.closers = append(chain.closers, reader)
+	}
+	if command != "" {
+		reader, err := newTransformReader(command, chain.Reader, sourceURL, targetURL)
+		if err != nil {
+			chain.Close()
+			return nil, err.Trace(sourceURL)
+		}
+		chain.Reader = reader
+		chain.closers = append(chain.closers, reader)
+	}
+	return chain, nil
+}
+
+// jqStep - a step of a jq path, the value of a key of objects, of an
+// index of arrays, or all values of arrays and objects.
+type jqStep struct {
+	key     string
+	index   int
+	isKey   bool
+	isIndex bool
+}
+
+// parseJQFilter - parse the paths of jq supported by the jq transform,
+// like '.', '.items[].name', '.["a key"][0]' and pipes of paths.
+func parseJQFilter(filter string) ([]jqStep, error) {
+	var steps []jqStep
+	s := strings.TrimSpace(filter)
+	for {
+		if !strings.HasPrefix(s, ".") {
+			return nil, fmt.Errorf("jq filter `%s` is not a path like .items[].name", filter)
+		}
+		s = s[1:]
+		for first := true; s != "" && s[0] != '|' && s[0] != ' '; first = false {
+			var step jqStep
+			var e error
+			switch {
+			case s[0] == '[':
+				step, s, e = parseJQBracket(s)
+			case s[0] == '.' && !first:
+				step.key, s, e = parseJQKey(s[1:])
+				step.isKey = true
+			case first:
+				step.key, s, e = parseJQKey(s)
+				step.isKey = true
+			default:
+				e = fmt.Errorf("unexpected `%s`", s)
+			}
+			if e != nil {
+				return nil, fmt.Errorf("jq filter `%s`: %s", filter, e)
+			}
+			steps = append(steps, step)
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return steps, nil
+		}
+		if s[0] != '|' {
+			return nil, fmt.Errorf("jq filter `%s`: unexpected `%s`", filter, s)
+		}
+		// A pipe of paths is the path of both.
+		s = strings.TrimSpace(s[1:])
+	}
+}
+
+// parseJQKey - parse a key, an identifier or a quoted string.
+func parseJQKey(s string) (string, string, error) {
+	if strings.HasPrefix(s, `"`) {
+		return parseJQString(s)
+	}
+	i := 0
+	for i < len(s) && (s[i] == '_' || 'a' <= s[i] && s[i] <= 'z' || 'A' <= s[i] && s[i] <= 'Z' || i > 0 && '0' <= s[i] && s[i] <= '9') {
+		i++
+	}
+	if i == 0 {
+		return "", s, fmt.Errorf("expected a key at `%s`", s)
+	}
+	return s[:i], s[i:], nil
+}
+
+// parseJQString - parse the JSON string at the start of s.
+func parseJQString(s string) (string, string, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			var key string
+			if e := json.Unmarshal([]byte(s[:i+1]), &key); e != nil {
+				return "", s, fmt.Errorf("invalid string %s", s[:i+1])
+			}
+			return key, s[i+1:], nil
+		}
+	}
+	return "", s, fmt.Errorf("unterminated string %s", s)
+}
+
+// parseJQBracket - parse '[]', '[N]' or '["key"]'.
+func parseJQBracket(s string) (jqStep, string, error) {
+	var step jqStep
+	s = s[1:]
+	switch {
+	case strings.HasPrefix(s, "]"):
+		return step, s[1:], nil
+	case strings.HasPrefix(s, `"`):
+		key, rest, e := parseJQString(s)
+		if e != nil {
+			return step, s, e
+		}
+		step.key, step.isKey, s = key, true, rest
+	default:
+		end := strings.Index(s, "]")
+		if end < 0 {
+			return step, s, fmt.Errorf("unterminated `[%s`", s)
+		}
+		index, e := strconv.Atoi(strings.TrimSpace(s[:end]))
+		if e != nil {
+			return step, s, fmt.Errorf("invalid index `%s`", s[:end])
+		}
+		step.index, step.isIndex, s = index, true, s[end:]
+	}
+	if !strings.HasPrefix(s, "]") {
+		return step, s, fmt.Errorf("expected `]` at `%s`", s)
+	}
+	return step, s[1:], nil
+}
+
+// jqType - the name of the type of a JSON value in jq.
+func jqType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// applyJQStep - the values of a step of a path of all values, like jq
+// keys and indexes of null are null.
+func applyJQStep(values []interface{}, step jqStep) ([]interface{}, error) {
+	var results []interface{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			switch {
+			case step.isKey:
+				results = append(results, v[step.key])
+			case step.isIndex:
+				return nil, fmt.Errorf("cannot index object with number")
+			default:
+				// Values of objects are in the order of their keys.
+				keys := make([]string, 0, len(v))
+				for key := range v {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					results = append(results, v[key])
+				}
+			}
+		case []interface{}:
+			switch {
+			case step.isKey:
+				return nil, fmt.Errorf("cannot index array with %q", step.key)
+			case step.isIndex:
+				index := step.index
+				if index < 0 {
+					index += len(v)
+				}
+				if index < 0 || index >= len(v) {
+					results = append(results, nil)
+				} else {
+					results = append(results, v[index])
+				}
+			default:
+				results = append(results, v...)
+			}
+		default:
+			switch {
+			case v == nil && (step.isKey || step.isIndex):
+				results = append(results, nil)
+			case step.isKey:
+				return nil, fmt.Errorf("cannot index %s with %q", jqType(v), step.key)
+			case step.isIndex:
+				return nil, fmt.Errorf("cannot index %s with number", jqType(v))
+			default:
+				return nil, fmt.Errorf("cannot iterate over %s", jqType(v))
+			}
+		}
+	}
+	return results, nil
+}
+
+// filterJSON - write the results of the path of every JSON value of
+// source, a JSON value per line, with raw strings as they are.
+func filterJSON(w io.Writer, source io.Reader, steps []jqStep, raw bool) error {
+	decoder := json.NewDecoder(source)
+	decoder.UseNumber()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	for {
+		var value interface{}
+		if e := decoder.Decode(&value); e != nil {
+			if e == io.EOF {
+				return nil
+			}
+			return e
+		}
+		values := []interface{}{value}
+		for _, step := range steps {
+			var e error
+			if values, e = applyJQStep(values, step); e != nil {
+				return e
+			}
+		}
+		buf.Reset()
+		for _, result := range values {
+			if s, ok := result.(string); ok && raw {
+				buf.WriteString(s + "\n")
+				continue
+			}
+			if e := encoder.Encode(result); e != nil {
+				return e
+			}
+		}
+		if _, e := w.Write(buf.Bytes()); e != nil {
+			return e
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJQFilter(t *testing.T) {
+	testCases := []struct {
+		filter  string
+		steps   []jqStep
+		success bool
+	}{
+		{".", nil, true},
+		{" . ", nil, true},
+		{".items", []jqStep{{key: "items", isKey: true}}, true},
+		{".items[].name", []jqStep{{key: "items", isKey: true}, {}, {key: "name", isKey: true}}, true},
+		{`."a key"[0]`, []jqStep{{key: "a key", isKey: true}, {index: 0, isIndex: true}}, true},
+		{`.["a\"b"][-1]`, []jqStep{{key: `a"b`, isKey: true}, {index: -1, isIndex: true}}, true},
+		{".[] | .name", []jqStep{{}, {key: "name", isKey: true}}, true},
+		{".a.b_2", []jqStep{{key: "a", isKey: true}, {key: "b_2", isKey: true}}, true},
+		{"", nil, false},
+		{"items", nil, false},
+		{".items[", nil, false},
+		{".[x]", nil, false},
+		{`.["a`, nil, false},
+		{".a b", nil, false},
+		{".a |", nil, false},
+		{".2", nil, false},
+	}
+	for i, testCase := range testCases {
+		steps, e := parseJQFilter(testCase.filter)
+		if testCase.success != (e == nil) {
+			t.Fatalf("Test %d: %q expected success %v, got error %v", i+1, testCase.filter, testCase.success, e)
+		}
+		if testCase.success && !reflect.DeepEqual(steps, testCase.steps) {
+			t.Fatalf("Test %d: %q expected %+v, got %+v", i+1, testCase.filter, testCase.steps, steps)
+		}
+	}
+}
+
+func TestFilterJSON(t *testing.T) {
+	testCases := []struct {
+		input   string
+		filter  string
+		raw     bool
+		output  string
+		success bool
+	}{
+		{`{"b":1,"a":"x<y"}`, ".", false, `{"a":"x<y","b":1}` + "\n", true},
+		{`{"items":[{"name":"a"},{"name":"b"}]}`, ".items[].name", false, "\"a\"\n\"b\"\n", true},
+		{`{"items":[{"name":"a"},{"name":"b"}]}`, ".items[].name", true, "a\nb\n", true},
+		{`{"n":1.50} {"n":2}`, ".n", true, "1.50\n2\n", true},
+		{`{"b":2,"a":1}`, ".[]", false, "1\n2\n", true},
+		{`[1,2,3]`, ".[-1]", false, "3\n", true},
+		{`[1,2,3]`, ".[5]", false, "null\n", true},
+		{`null`, ".a[0]", false, "null\n", true},
+		{`{"a":1}`, ".a.b", false, "", false},
+		{`[1]`, ".a", false, "", false},
+		{`"x"`, ".[]", false, "", false},
+		{`{"a":`, ".", false, "", false},
+	}
+	for i, testCase := range testCases {
+		steps, e := parseJQFilter(testCase.filter)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		var buf bytes.Buffer
+		e = filterJSON(&buf, strings.NewReader(testCase.input), steps, testCase.raw)
+		if testCase.success != (e == nil) {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, e)
+		}
+		if testCase.success && buf.String() != testCase.output {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.output, buf.String())
+		}
+	}
+}
+
+func TestNewTransformsReader(t *testing.T) {
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	gw.Write([]byte(`{"items":[{"name":"a"},{"name":"b"}]}`))
+	gw.Close()
+
+	testCases := []struct {
+		transforms []string
+		output     string
+		success    bool
+	}{
+		{[]string{"gunzip", "jq-raw:.items[].name"}, "a\nb\n", true},
+		{[]string{"gunzip", "gzip", "gunzip", "jq:.items[0]"}, "{\"name\":\"a\"}\n", true},
+		{[]string{"gunzip:x"}, "", false},
+		{[]string{"unzip"}, "", false},
+		{[]string{"jq:items"}, "", false},
+	}
+	for i, testCase := range testCases {
+		reader, err := newTransformsReader(testCase.transforms, "", bytes.NewReader(compressed.Bytes()), "src", "-")
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+		if err != nil {
+			continue
+		}
+		output, e := ioutil.ReadAll(reader)
+		reader.Close()
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if string(output) != testCase.output {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.output, output)
+		}
+	}
+}
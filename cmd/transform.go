@@ -0,0 +1,138 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// transformFlag is shared by all commands able to filter downloaded objects.
+var transformFlag = cli.StringFlag{
+	Name:  "transform-exec",
+	Usage: "pipe each source object through a local filter program, e.g. 'gpg --decrypt'",
+}
+
+// Maximum amount of standard error of the filter program kept for error messages.
+const transformMaxStderr = 4 * 1024
+
+// limitedBuffer keeps the first bytes written to it and discards the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := l.limit - l.Len(); remaining > 0 {
+		if len(p) > remaining {
+			l.Buffer.Write(p[:remaining])
+		} else {
+			l.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// transformReader streams an object through a filter program, the
+// object is written to the standard input of the program while its
+// standard output is read back. Nothing is buffered on disk.
+type transformReader struct {
+	command string
+	cmd     *exec.Cmd
+	stdout  io.ReadCloser
+	stderr  *limitedBuffer
+	done    bool
+}
+
+// newTransformReader starts command in a shell with source as standard
+// input. The source and target URLs are exported to the program as
+// MC_TRANSFORM_SOURCE and MC_TRANSFORM_TARGET.
+func newTransformReader(command string, source io.Reader, sourceURL, targetURL string) (io.ReadCloser, *probe.Error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("/bin/sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(),
+		"MC_TRANSFORM_SOURCE="+sourceURL,
+		"MC_TRANSFORM_TARGET="+targetURL)
+	cmd.Stdin = source
+
+	t := &transformReader{
+		command: command,
+		cmd:     cmd,
+		stderr:  &limitedBuffer{limit: transformMaxStderr},
+	}
+	cmd.Stderr = t.stderr
+
+	stdout, e := cmd.StdoutPipe()
+	if e != nil {
+		return nil, probe.NewError(e).Trace(command)
+	}
+	t.stdout = stdout
+	if e = cmd.Start(); e != nil {
+		return nil, probe.NewError(e).Trace(command)
+	}
+	return t, nil
+}
+
+// wait reaps the filter program, a non zero exit status is an error.
+func (t *transformReader) wait() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if e := t.cmd.Wait(); e != nil {
+		if msg := strings.TrimSpace(t.stderr.String()); msg != "" {
+			return fmt.Errorf("transform `%s` failed: %s: %s", t.command, e, msg)
+		}
+		return fmt.Errorf("transform `%s` failed: %s", t.command, e)
+	}
+	return nil
+}
+
+// Read implements io.Reader, reads the output of the filter program.
+// The exit status of the program is checked once its output ends.
+func (t *transformReader) Read(p []byte) (n int, err error) {
+	n, err = t.stdout.Read(p)
+	if err == io.EOF {
+		if e := t.wait(); e != nil {
+			return n, e
+		}
+	}
+	return n, err
+}
+
+// Close implements io.Closer, stops the filter program if its output
+// was not read till the end.
+func (t *transformReader) Close() error {
+	if !t.done && t.cmd.Process != nil {
+		t.cmd.Process.Kill()
+	}
+	t.stdout.Close()
+	t.wait()
+	return nil
+}
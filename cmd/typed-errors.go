@@ -96,11 +96,13 @@ var errInvalidTarget = func(URL string) *probe.Error {
 	return probe.NewError(invalidTargetErr(errors.New(msg))).Untrace()
 }
 
-type targetNotFoundErr error
+type targetNotFoundErr struct {
+	error
+}
 
 var errTargetNotFound = func(URL string) *probe.Error {
 	msg := "Target `" + URL + "` not found."
-	return probe.NewError(targetNotFoundErr(errors.New(msg))).Untrace()
+	return probe.NewError(targetNotFoundErr{errors.New(msg)}).Untrace()
 }
 
 type overwriteNotAllowedErr struct {
@@ -125,3 +127,80 @@ var errConflictSSE = func(sseServer, sseKeys string) *probe.Error {
 	err := fmt.Errorf("SSE alias '%s' overlaps with SSE-C aliases '%s'", sseServer, sseKeys)
 	return probe.NewError(conflictSSEErr(err)).Untrace()
 }
+
+type trashUnavailableErr error
+
+var errTrashUnavailable = func(URL string) *probe.Error {
+	msg := "Bucket of `" + URL + "` is not versioned, removed objects could not be restored."
+	return probe.NewError(trashUnavailableErr(errors.New(msg))).Untrace()
+}
+
+type moveVerificationErr error
+
+var errMoveVerification = func(sourceURL, targetURL string) *probe.Error {
+	msg := "Target `" + targetURL + "` does not match source `" + sourceURL + "`, source is kept."
+	return probe.NewError(moveVerificationErr(errors.New(msg))).Untrace()
+}
+
+type moveOntoItselfErr error
+
+var errMoveOntoItself = func(URL string) *probe.Error {
+	msg := "Source and target `" + URL + "` are the same object."
+	return probe.NewError(moveOntoItselfErr(errors.New(msg))).Untrace()
+}
+
+type moveIntoSourceErr error
+
+var errMoveIntoSource = func(sourceURL, targetURL string) *probe.Error {
+	msg := "Target `" + targetURL + "` is inside source `" + sourceURL + "`."
+	return probe.NewError(moveIntoSourceErr(errors.New(msg))).Untrace()
+}
+
+type rewindUnavailableErr error
+
+var errRewindUnavailable = func(URL string) *probe.Error {
+	msg := "`" + URL + "` is not in a versioned bucket, only buckets with versioning keep previous versions of objects."
+	return probe.NewError(rewindUnavailableErr(errors.New(msg))).Untrace()
+}
+
+type invalidRewindErr error
+
+var errInvalidRewind = func(value string) *probe.Error {
+	msg := "Invalid point in time `" + value + "`, a duration like `7d` or a timestamp like `2019-06-01T10:00` is expected."
+	return probe.NewError(invalidRewindErr(errors.New(msg))).Untrace()
+}
+
+type tooManyErrorsErr error
+
+var errTooManyErrors = func(maxErrors int) *probe.Error {
+	msg := fmt.Sprintf("More than %d objects failed, aborting as requested by `--max-errors`.", maxErrors)
+	return probe.NewError(tooManyErrorsErr(errors.New(msg))).Untrace()
+}
+
+type noSuchUserErr error
+
+var errNoSuchUser = func(accessKey string) *probe.Error {
+	msg := "User `" + accessKey + "` does not exist."
+	return probe.NewError(noSuchUserErr(errors.New(msg))).Untrace()
+}
+
+type loginExpiredErr error
+
+var errLoginExpired = func(alias string) *probe.Error {
+	msg := "Login of alias `" + alias + "` expired, sign in again with `mc login " + alias + "`."
+	return probe.NewError(loginExpiredErr(errors.New(msg))).Untrace()
+}
+
+type requesterPaysV2Err error
+
+var errRequesterPaysV2 = func(URL string) *probe.Error {
+	msg := "Requester pays requests to `" + URL + "` need signature S3v4, add the alias again with `--api S3v4`."
+	return probe.NewError(requesterPaysV2Err(errors.New(msg))).Untrace()
+}
+
+type configLockedErr error
+
+var errConfigLocked = func(path string) *probe.Error {
+	msg := "Timed out waiting for lock `" + path + "` held by another mc process."
+	return probe.NewError(configLockedErr(errors.New(msg))).Untrace()
+}
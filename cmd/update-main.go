@@ -17,7 +17,9 @@
 package cmd
 
 import (
+	"bytes"
 	"crypto"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
@@ -33,6 +35,7 @@ import (
 	isatty "github.com/mattn/go-isatty"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/probe"
 	_ "github.com/minio/sha256-simd" // Needed for sha256 hash verifier.
 	"github.com/segmentio/go-prompt"
@@ -52,6 +55,19 @@ var updateCmd = cli.Command{
 			Name:  "json",
 			Usage: "enable JSON formatted output",
 		},
+		cli.BoolFlag{
+			Name:  "check, check-only",
+			Usage: "only check for an update, exit with status 1 if one is available",
+		},
+		cli.StringFlag{
+			Name:  "channel",
+			Value: mcReleaseChannelStable,
+			Usage: "release channel to update from, 'stable' or 'edge'",
+		},
+		cli.StringFlag{
+			Name:  "from",
+			Usage: "update from a release FILE downloaded beforehand, with FILE.sha256sum and FILE.minisig",
+		},
 	},
 	CustomHelpTemplate: `Name:
    {{.HelpName}} - {{.Usage}}
@@ -64,12 +80,27 @@ FLAGS:
   {{end}}{{end}}
 EXIT STATUS:
    0 - you are already running the most recent version
-   1 - new update was applied successfully
+   1 - new update was applied successfully, or is available with '--check'
   -1 - error in getting update information
 
+DESCRIPTION:
+  A downloaded release replaces mc only once its SHA-256 checksum and its
+  minisign signature are verified.
+
 EXAMPLES:
    1. Check and update mc:
       $ {{.HelpName}}
+
+   2. Check for an update without downloading it, e.g. from a cron job.
+      $ {{.HelpName}} --check --json
+
+   3. Update mc to the latest edge build.
+      $ {{.HelpName}} --channel edge
+
+   4. Update mc on an air-gapped host, from a release copied with its checksum and signature.
+      $ ls /media/usb
+      mc.RELEASE.2019-10-09T22-54-57Z  mc.RELEASE.2019-10-09T22-54-57Z.minisig  mc.RELEASE.2019-10-09T22-54-57Z.sha256sum
+      $ {{.HelpName}} --from /media/usb/mc.RELEASE.2019-10-09T22-54-57Z
 `,
 }
 
@@ -77,21 +108,32 @@ const (
 	mcReleaseTagTimeLayout = "2006-01-02T15-04-05Z"
 	mcOSARCH               = runtime.GOOS + "-" + runtime.GOARCH
 	mcReleaseURL           = "https://dl.min.io/client/mc/release/" + mcOSARCH + "/"
+	mcEdgeURL              = "https://dl.min.io/client/mc/edge/" + mcOSARCH + "/"
+
+	mcReleaseChannelStable = "stable"
+	mcReleaseChannelEdge   = "edge"
 )
 
-var (
-	// Newer official download info URLs appear earlier below.
-	mcReleaseInfoURLs = []string{
-		mcReleaseURL + "mc.sha256sum",
-		mcReleaseURL + "mc.shasum",
-	}
+// Download URLs of the release channels.
+var mcReleaseChannelURLs = map[string]string{
+	mcReleaseChannelStable: mcReleaseURL,
+	mcReleaseChannelEdge:   mcEdgeURL,
+}
 
+// getReleaseInfoURLs - release info URLs of a channel, newer official
+// download info URLs appear earlier.
+func getReleaseInfoURLs(channel string) []string {
+	binary := "mc"
 	// For windows our files have .exe additionally.
-	mcReleaseWindowsInfoURLs = []string{
-		mcReleaseURL + "mc.exe.sha256sum",
-		mcReleaseURL + "mc.exe.shasum",
+	if runtime.GOOS == "windows" {
+		binary = "mc.exe"
 	}
-)
+	releaseURL := mcReleaseChannelURLs[channel]
+	return []string{
+		releaseURL + binary + ".sha256sum",
+		releaseURL + binary + ".shasum",
+	}
+}
 
 // mcVersionToReleaseTime - parses a standard official release
 // mc version string.
@@ -245,9 +287,15 @@ func downloadReleaseURL(releaseChecksumURL string, timeout time.Duration) (conte
 	}
 	req.Header.Set("User-Agent", getUserAgent())
 
+	proxyFunc, err := getProxyFunc(&Config{Proxy: globalProxy, Debug: globalDebug})
+	if err != nil {
+		return content, err.Trace(globalProxy)
+	}
+
 	client := &http.Client{
 		Timeout: timeout,
 		Transport: &http.Transport{
+			Proxy: proxyFunc,
 			// need to close connection after usage.
 			DisableKeepAlives: true,
 		},
@@ -273,12 +321,10 @@ func downloadReleaseURL(releaseChecksumURL string, timeout time.Duration) (conte
 	return string(contentBytes), nil
 }
 
-// DownloadReleaseData - downloads release data from mc official server.
-func DownloadReleaseData(timeout time.Duration) (data string, err *probe.Error) {
-	releaseURLs := mcReleaseInfoURLs
-	if runtime.GOOS == "windows" {
-		releaseURLs = mcReleaseWindowsInfoURLs
-	}
+// DownloadReleaseData - downloads release data of a channel from mc
+// official server.
+func DownloadReleaseData(timeout time.Duration, channel string) (data string, err *probe.Error) {
+	releaseURLs := getReleaseInfoURLs(channel)
 	return func() (data string, err *probe.Error) {
 		for _, url := range releaseURLs {
 			data, err = downloadReleaseURL(url, timeout)
@@ -323,8 +369,8 @@ func parseReleaseData(data string) (sha256Hex string, releaseTime time.Time, err
 	return sha256Hex, releaseTime, nil
 }
 
-func getLatestReleaseTime(timeout time.Duration) (sha256Hex string, releaseTime time.Time, err *probe.Error) {
-	data, err := DownloadReleaseData(timeout)
+func getLatestReleaseTime(timeout time.Duration, channel string) (sha256Hex string, releaseTime time.Time, err *probe.Error) {
+	data, err := DownloadReleaseData(timeout, channel)
 	if err != nil {
 		return sha256Hex, releaseTime, err.Trace()
 	}
@@ -332,28 +378,31 @@ func getLatestReleaseTime(timeout time.Duration) (sha256Hex string, releaseTime
 	return parseReleaseData(data)
 }
 
-func getDownloadURL(releaseTag string) (downloadURL string) {
+func getDownloadURL(channel, releaseTag string) (downloadURL string) {
 	// Check if we are docker environment, return docker update command
 	if IsDocker() {
+		if channel == mcReleaseChannelEdge {
+			return "docker pull minio/mc:edge"
+		}
 		// Construct release tag name.
 		return fmt.Sprintf("docker pull minio/mc:%s", releaseTag)
 	}
 
 	// For binary only installations, we return link to the latest binary.
 	if runtime.GOOS == "windows" {
-		return mcReleaseURL + "mc.exe"
+		return mcReleaseChannelURLs[channel] + "mc.exe"
 	}
 
-	return mcReleaseURL + "mc"
+	return mcReleaseChannelURLs[channel] + "mc"
 }
 
-func getUpdateInfo(timeout time.Duration) (updateMsg string, sha256Hex string, currentReleaseTime, latestReleaseTime time.Time, err *probe.Error) {
+func getUpdateInfo(timeout time.Duration, channel string) (updateMsg string, sha256Hex string, currentReleaseTime, latestReleaseTime time.Time, err *probe.Error) {
 	currentReleaseTime, err = GetCurrentReleaseTime()
 	if err != nil {
 		return updateMsg, sha256Hex, currentReleaseTime, latestReleaseTime, err.Trace()
 	}
 
-	sha256Hex, latestReleaseTime, err = getLatestReleaseTime(timeout)
+	sha256Hex, latestReleaseTime, err = getLatestReleaseTime(timeout, channel)
 	if err != nil {
 		return updateMsg, sha256Hex, currentReleaseTime, latestReleaseTime, err.Trace()
 	}
@@ -362,7 +411,7 @@ func getUpdateInfo(timeout time.Duration) (updateMsg string, sha256Hex string, c
 	var downloadURL string
 	if latestReleaseTime.After(currentReleaseTime) {
 		older = latestReleaseTime.Sub(currentReleaseTime)
-		downloadURL = getDownloadURL(releaseTimeToReleaseTag(latestReleaseTime))
+		downloadURL = getDownloadURL(channel, releaseTimeToReleaseTag(latestReleaseTime))
 	}
 
 	return prepareUpdateMessage(downloadURL, older), sha256Hex, currentReleaseTime, latestReleaseTime, nil
@@ -397,37 +446,99 @@ var (
 	}()
 )
 
-func doUpdate(sha256Hex string, latestReleaseTime time.Time, ok bool) (updateStatusMsg string, err *probe.Error) {
+func doUpdate(channel, sha256Hex string, latestReleaseTime time.Time, ok bool) (updateStatusMsg string, err *probe.Error) {
 	if !ok {
 		updateStatusMsg = colorGreenBold("mc update to version RELEASE.%s canceled.",
 			latestReleaseTime.Format(mcReleaseTagTimeLayout))
 		return updateStatusMsg, nil
 	}
-	var sha256Sum []byte
-	var e error
-	sha256Sum, e = hex.DecodeString(sha256Hex)
-	if e != nil {
-		return updateStatusMsg, probe.NewError(e)
+	proxyFunc, err := getProxyFunc(&Config{Proxy: globalProxy, Debug: globalDebug})
+	if err != nil {
+		return updateStatusMsg, err.Trace(globalProxy)
 	}
+	client := &http.Client{Transport: &http.Transport{Proxy: proxyFunc}}
 
-	resp, e := http.Get(getDownloadURL(releaseTimeToReleaseTag(latestReleaseTime)))
+	downloadURL := getDownloadURL(channel, releaseTimeToReleaseTag(latestReleaseTime))
+	resp, e := client.Get(downloadURL)
 	if e != nil {
 		return updateStatusMsg, probe.NewError(e)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return updateStatusMsg, probe.NewError(fmt.Errorf("Error downloading URL %s. Response: %v", downloadURL, resp.Status))
+	}
+	binary, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return updateStatusMsg, probe.NewError(e)
+	}
+
+	signature, err := downloadReleaseURL(downloadURL+".minisig", 30*time.Second)
+	if err != nil {
+		return updateStatusMsg, err.Trace(downloadURL)
+	}
+	if err = applyRelease(binary, sha256Hex, signature, ""); err != nil {
+		return updateStatusMsg, err.Trace(downloadURL)
+	}
 
-	// FIXME: add support for gpg verification as well.
-	if e = update.Apply(resp.Body,
+	return colorGreenBold("mc updated to version RELEASE.%s successfully.",
+		latestReleaseTime.Format(mcReleaseTagTimeLayout)), nil
+}
+
+// verifyRelease - verify the checksum and the signature of a release.
+func verifyRelease(binary []byte, sha256Hex, signature string) *probe.Error {
+	sha256Sum, e := hex.DecodeString(sha256Hex)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if sum := sha256.Sum256(binary); !bytes.Equal(sum[:], sha256Sum) {
+		return probe.NewError(fmt.Errorf("Release checksum %x does not match the expected %s", sum, sha256Hex))
+	}
+	// The release is only applied once signed by MinIO.
+	return verifyMinisign(getMinisignPubKey(), binary, signature)
+}
+
+// applyRelease - replace the binary at targetPath, the running binary if
+// empty, with a release once its checksum and signature are verified.
+func applyRelease(binary []byte, sha256Hex, signature, targetPath string) *probe.Error {
+	if err := verifyRelease(binary, sha256Hex, signature); err != nil {
+		return err.Trace()
+	}
+	sha256Sum, e := hex.DecodeString(sha256Hex)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	if e = update.Apply(bytes.NewReader(binary),
 		update.Options{
-			Hash:     crypto.SHA256,
-			Checksum: sha256Sum,
+			TargetPath: targetPath,
+			Hash:       crypto.SHA256,
+			Checksum:   sha256Sum,
 		},
 	); e != nil {
-		return updateStatusMsg, probe.NewError(e)
+		return probe.NewError(e)
 	}
+	return nil
+}
 
-	return colorGreenBold("mc updated to version RELEASE.%s successfully.",
-		latestReleaseTime.Format(mcReleaseTagTimeLayout)), nil
+// readLocalRelease - read a release binary copied from dl.min.io along
+// with its release info 'FILE.sha256sum' and signature 'FILE.minisig'.
+func readLocalRelease(path string) (binary []byte, sha256Hex string, releaseTime time.Time, signature string, err *probe.Error) {
+	binary, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, "", releaseTime, "", probe.NewError(e).Trace(path)
+	}
+	data, e := ioutil.ReadFile(path + ".sha256sum")
+	if e != nil {
+		return nil, "", releaseTime, "", probe.NewError(e).Trace(path + ".sha256sum")
+	}
+	if sha256Hex, releaseTime, err = parseReleaseData(string(data)); err != nil {
+		return nil, "", releaseTime, "", err.Trace(path + ".sha256sum")
+	}
+	sig, e := ioutil.ReadFile(path + ".minisig")
+	if e != nil {
+		return nil, "", releaseTime, "", probe.NewError(e).Trace(path + ".minisig")
+	}
+	return binary, sha256Hex, releaseTime, string(sig), nil
 }
 
 func shouldUpdate(quiet bool, sha256Hex string, latestReleaseTime time.Time) (ok bool) {
@@ -439,8 +550,13 @@ func shouldUpdate(quiet bool, sha256Hex string, latestReleaseTime time.Time) (ok
 }
 
 type updateMessage struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	Status            string `json:"status"`
+	Message           string `json:"message"`
+	Channel           string `json:"channel,omitempty"`
+	CurrentReleaseTag string `json:"currentReleaseTag,omitempty"`
+	LatestReleaseTag  string `json:"latestReleaseTag,omitempty"`
+	UpdateAvailable   bool   `json:"updateAvailable,omitempty"`
+	Updated           bool   `json:"updated,omitempty"`
 }
 
 // String colorized make bucket message.
@@ -464,39 +580,116 @@ func mainUpdate(ctx *cli.Context) {
 
 	globalQuiet = ctx.Bool("quiet") || ctx.GlobalBool("quiet")
 	globalJSON = ctx.Bool("json") || ctx.GlobalBool("json")
+	globalNoColor = ctx.GlobalBool("no-color") || os.Getenv("NO_COLOR") != ""
+	if globalNoColor || globalQuiet {
+		console.SetColorOff()
+	}
+
+	if from := ctx.String("from"); from != "" {
+		updateFromFile(from, ctx.Bool("check"))
+	}
 
-	updateMsg, sha256Hex, _, latestReleaseTime, err := getUpdateInfo(10 * time.Second)
+	channel := ctx.String("channel")
+	releaseURL, ok := mcReleaseChannelURLs[channel]
+	if !ok {
+		errorIf(errInvalidArgument().Trace(channel), "Unknown release channel `"+channel+"`, valid options are '[stable, edge]'.")
+		os.Exit(-1)
+	}
+
+	updateMsg, sha256Hex, currentReleaseTime, latestReleaseTime, err := getUpdateInfo(10*time.Second, channel)
 	if err != nil {
 		errorIf(err, "Unable to update ‘mc’.")
 		os.Exit(-1)
 	}
+	msg := updateMessage{
+		Status:            "success",
+		Channel:           channel,
+		CurrentReleaseTag: releaseTimeToReleaseTag(currentReleaseTime),
+		LatestReleaseTag:  releaseTimeToReleaseTag(latestReleaseTime),
+	}
 
 	// Nothing to update running the latest release.
 	color.New(color.FgGreen, color.Bold)
 	if updateMsg == "" {
-		printMsg(updateMessage{
-			Status:  "success",
-			Message: colorGreenBold("You are already running the most recent version of ‘mc’."),
-		})
+		msg.Message = colorGreenBold("You are already running the most recent version of ‘mc’.")
+		printMsg(msg)
 		os.Exit(0)
 	}
 
-	printMsg(updateMessage{
-		Status:  "success",
-		Message: updateMsg,
-	})
+	msg.Message = updateMsg
+	msg.UpdateAvailable = true
+	printMsg(msg)
+	if ctx.Bool("check") {
+		os.Exit(1)
+	}
 
 	// Avoid updating mc development, source builds.
-	if strings.Contains(updateMsg, mcReleaseURL) {
+	if strings.Contains(updateMsg, releaseURL) {
 		isUpdate := shouldUpdate(globalQuiet || globalJSON, sha256Hex, latestReleaseTime)
 		var updateStatusMsg string
 		var err *probe.Error
-		updateStatusMsg, err = doUpdate(sha256Hex, latestReleaseTime, isUpdate)
+		updateStatusMsg, err = doUpdate(channel, sha256Hex, latestReleaseTime, isUpdate)
 		if err != nil {
 			errorIf(err, "Unable to update ‘mc’.")
 			os.Exit(-1)
 		}
-		printMsg(updateMessage{Status: "success", Message: updateStatusMsg})
+		msg.Message = updateStatusMsg
+		msg.Updated = isUpdate
+		printMsg(msg)
 		os.Exit(1)
 	}
 }
+
+// updateFromFile - update from a release downloaded beforehand, for hosts
+// which cannot reach dl.min.io. Exits with the status of mainUpdate.
+func updateFromFile(path string, isCheck bool) {
+	binary, sha256Hex, releaseTime, signature, err := readLocalRelease(path)
+	if err != nil {
+		errorIf(err, "Unable to read the release `"+path+"`.")
+		os.Exit(-1)
+	}
+	if err = verifyRelease(binary, sha256Hex, signature); err != nil {
+		errorIf(err.Trace(path), "Unable to verify the release `"+path+"`.")
+		os.Exit(-1)
+	}
+	currentReleaseTime, err := GetCurrentReleaseTime()
+	if err != nil {
+		errorIf(err, "Unable to update ‘mc’.")
+		os.Exit(-1)
+	}
+	msg := updateMessage{
+		Status:            "success",
+		CurrentReleaseTag: releaseTimeToReleaseTag(currentReleaseTime),
+		LatestReleaseTag:  releaseTimeToReleaseTag(releaseTime),
+	}
+
+	if !releaseTime.After(currentReleaseTime) {
+		msg.Message = colorGreenBold("You are already running RELEASE.%s of ‘mc’ or a more recent one.",
+			releaseTime.Format(mcReleaseTagTimeLayout))
+		printMsg(msg)
+		os.Exit(0)
+	}
+	msg.UpdateAvailable = true
+	if isCheck {
+		msg.Message = colorGreenBold("`%s` is a more recent release RELEASE.%s of ‘mc’.",
+			path, releaseTime.Format(mcReleaseTagTimeLayout))
+		printMsg(msg)
+		os.Exit(1)
+	}
+
+	if !shouldUpdate(globalQuiet || globalJSON, sha256Hex, releaseTime) {
+		msg.Message = colorGreenBold("mc update to version RELEASE.%s canceled.",
+			releaseTime.Format(mcReleaseTagTimeLayout))
+		printMsg(msg)
+		os.Exit(0)
+	}
+	if err = applyRelease(binary, sha256Hex, signature, ""); err != nil {
+		errorIf(err.Trace(path), "Unable to update ‘mc’.")
+		os.Exit(-1)
+	}
+	msg.Message = colorGreenBold("mc updated to version RELEASE.%s successfully.",
+		releaseTime.Format(mcReleaseTagTimeLayout))
+	msg.Updated = true
+	printMsg(msg)
+	os.Exit(1)
+}
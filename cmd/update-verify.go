@@ -0,0 +1,97 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	// Public key of the minisign signatures of official releases.
+	mcMinisignPubKey = "RWTx5Zr1tiHQLwG9keckT0c45M3AGeHD6IvimQHpyRywVWGbP1aVSGav"
+
+	// Overrides the public key, for builds signed by others.
+	mcMinisignPubKeyEnv = "MC_UPDATE_MINISIGN_PUBKEY"
+)
+
+// getMinisignPubKey - public key verifying downloaded releases.
+func getMinisignPubKey() string {
+	if pubKey := os.Getenv(mcMinisignPubKeyEnv); pubKey != "" {
+		return pubKey
+	}
+	return mcMinisignPubKey
+}
+
+// verifyMinisign - verify the minisign signature of data, both legacy
+// and pre-hashed signatures are accepted. The trusted comment must be
+// signed by the same key.
+//
+// A public key is the base64 of the 'Ed' algorithm, an 8 bytes key ID
+// and the 32 bytes Ed25519 key. A signature file is made of an untrusted
+// comment, the base64 of the algorithm, the key ID and the 64 bytes
+// signature, a trusted comment and the base64 signature of the signature
+// followed by the trusted comment.
+func verifyMinisign(pubKey string, data []byte, signature string) *probe.Error {
+	key, e := base64.StdEncoding.DecodeString(strings.TrimSpace(pubKey))
+	if e != nil || len(key) != 2+8+ed25519.PublicKeySize || string(key[:2]) != "Ed" {
+		return probe.NewError(errors.New("invalid minisign public key"))
+	}
+	keyID, publicKey := key[2:10], ed25519.PublicKey(key[10:])
+
+	lines := strings.Split(strings.Replace(signature, "\r\n", "\n", -1), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return probe.NewError(errors.New("invalid minisign signature"))
+	}
+	sig, e := base64.StdEncoding.DecodeString(lines[1])
+	if e != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return probe.NewError(errors.New("invalid minisign signature"))
+	}
+	if !bytes.Equal(sig[2:10], keyID) {
+		return probe.NewError(errors.New("release is signed by another key"))
+	}
+
+	message := data
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		sum := blake2b.Sum512(data)
+		message = sum[:]
+	default:
+		return probe.NewError(errors.New("unsupported minisign signature algorithm"))
+	}
+	if !ed25519.Verify(publicKey, message, sig[10:]) {
+		return probe.NewError(errors.New("release signature does not match"))
+	}
+
+	globalSig, e := base64.StdEncoding.DecodeString(lines[3])
+	if e != nil || len(globalSig) != ed25519.SignatureSize {
+		return probe.NewError(errors.New("invalid minisign signature"))
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(publicKey, append(sig[10:], trustedComment...), globalSig) {
+		return probe.NewError(errors.New("release trusted comment signature does not match"))
+	}
+	return nil
+}
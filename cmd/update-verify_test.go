@@ -0,0 +1,174 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
+)
+
+// newMinisignKey - generate a minisign public key and a function signing
+// with its private key.
+func newMinisignKey(t *testing.T, keyID []byte) (string, func(algorithm string, keyID []byte, data []byte, trustedComment string) string) {
+	publicKey, privateKey, e := ed25519.GenerateKey(rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	pubKey := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), publicKey...))
+	return pubKey, func(algorithm string, keyID []byte, data []byte, trustedComment string) string {
+		message := data
+		if algorithm == "ED" {
+			sum := blake2b.Sum512(data)
+			message = sum[:]
+		}
+		sig := ed25519.Sign(privateKey, message)
+		globalSig := ed25519.Sign(privateKey, append(append([]byte{}, sig...), trustedComment...))
+		return strings.Join([]string{
+			"untrusted comment: signature from minisign secret key",
+			base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), keyID...), sig...)),
+			"trusted comment: " + trustedComment,
+			base64.StdEncoding.EncodeToString(globalSig),
+			"",
+		}, "\n")
+	}
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	keyID := []byte("12345678")
+	pubKey, sign := newMinisignKey(t, keyID)
+
+	data := []byte("mc release binary")
+
+	signature := sign("ED", keyID, data, "timestamp:1570000000\tfile:mc")
+	tampered := strings.Replace(signature, "file:mc", "file:mc.exe", 1)
+	testCases := []struct {
+		pubKey    string
+		data      []byte
+		signature string
+		valid     bool
+	}{
+		{pubKey, data, signature, true},
+		{pubKey, data, sign("Ed", keyID, data, "timestamp:1570000000"), true},
+		{pubKey, data, strings.Replace(signature, "\n", "\r\n", -1), true},
+		// Signed by another key, or for other data.
+		{pubKey, data, sign("ED", []byte("87654321"), data, ""), false},
+		{pubKey, []byte("mc release binary, modified"), signature, false},
+		// The trusted comment cannot be changed.
+		{pubKey, data, tampered, false},
+		{pubKey, data, sign("EX", keyID, data, ""), false},
+		{pubKey, data, "untrusted comment: \n", false},
+		{"invalid", data, signature, false},
+		{mcMinisignPubKey, data, signature, false},
+	}
+	for i, testCase := range testCases {
+		err := verifyMinisign(testCase.pubKey, testCase.data, testCase.signature)
+		if testCase.valid && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i+1, err)
+		}
+		if !testCase.valid && err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
+
+func TestGetReleaseInfoURLs(t *testing.T) {
+	for channel, releaseURL := range mcReleaseChannelURLs {
+		for _, infoURL := range getReleaseInfoURLs(channel) {
+			if !strings.HasPrefix(infoURL, releaseURL) {
+				t.Errorf("channel %s: unexpected release info URL %s", channel, infoURL)
+			}
+		}
+	}
+	if mcReleaseChannelURLs[mcReleaseChannelStable] == mcReleaseChannelURLs[mcReleaseChannelEdge] {
+		t.Error("expected distinct URLs for the stable and edge channels")
+	}
+}
+
+func TestApplyLocalRelease(t *testing.T) {
+	keyID := []byte("12345678")
+	pubKey, sign := newMinisignKey(t, keyID)
+	defer os.Setenv(mcMinisignPubKeyEnv, os.Getenv(mcMinisignPubKeyEnv))
+	os.Setenv(mcMinisignPubKeyEnv, pubKey)
+
+	root, e := ioutil.TempDir("", "mc-update-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+
+	binary := []byte("mc release binary")
+	sum := sha256.Sum256(binary)
+	release := filepath.Join(root, "mc.RELEASE.2019-10-09T22-54-57Z")
+	files := map[string]string{
+		release:                string(binary),
+		release + ".sha256sum": hex.EncodeToString(sum[:]) + " mc.RELEASE.2019-10-09T22-54-57Z\n",
+		release + ".minisig":   sign("ED", keyID, binary, "file:mc"),
+	}
+	for path, content := range files {
+		if e = ioutil.WriteFile(path, []byte(content), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	data, sha256Hex, releaseTime, signature, err := readLocalRelease(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if releaseTimeToReleaseTag(releaseTime) != "RELEASE.2019-10-09T22-54-57Z" {
+		t.Errorf("unexpected release time %s", releaseTime)
+	}
+	if err = verifyRelease(data, sha256Hex, signature); err != nil {
+		t.Fatal(err)
+	}
+	// Checksum and signature of another binary are refused.
+	if err = verifyRelease([]byte("modified"), sha256Hex, signature); err == nil {
+		t.Error("expected an error for a modified binary")
+	}
+	modified := []byte("modified")
+	modifiedSum := sha256.Sum256(modified)
+	if err = verifyRelease(modified, hex.EncodeToString(modifiedSum[:]), signature); err == nil {
+		t.Error("expected an error for a binary signed by nobody")
+	}
+
+	target := filepath.Join(root, "mc")
+	if e = ioutil.WriteFile(target, []byte("old mc"), 0700); e != nil {
+		t.Fatal(e)
+	}
+	if err = applyRelease(data, sha256Hex, signature, target); err != nil {
+		t.Fatal(err)
+	}
+	if content, e := ioutil.ReadFile(target); e != nil || !bytes.Equal(content, binary) {
+		t.Errorf("expected the release to replace the binary, got %q, %v", content, e)
+	}
+
+	// A release without signature is not read.
+	os.Remove(release + ".minisig")
+	if _, _, _, _, err = readLocalRelease(release); err == nil {
+		t.Error("expected an error for a release without signature")
+	}
+}
@@ -29,7 +29,17 @@ type URLs struct {
 	TotalCount    int64
 	TotalSize     int64
 	encKeyDB      map[string][]prefixSSEPair
-	Error         *probe.Error `json:"-"`
+	TransformExec string        `json:"-"`
+	Transforms    []string      `json:"-"`
+	Condition     copyCondition `json:"-"`
+	Skipped       bool          `json:"-"`
+	Error         *probe.Error  `json:"-"`
+
+	// Tags of the source are copied along with its metadata.
+	PreserveMetadata bool `json:"-"`
+
+	// Multipart uploads of the session, resumed part by part.
+	Uploads *sessionUploads `json:"-"`
 }
 
 // WithError sets the error and returns object
@@ -19,8 +19,11 @@ package cmd
 import (
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
+	"net/http"
+	"net/url"
 	"os"
 	"runtime"
 	"sort"
@@ -33,6 +36,7 @@ import (
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/ioutils"
 	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/net/http/httpproxy"
 )
 
 func isErrIgnored(err *probe.Error) (ignored bool) {
@@ -109,6 +113,65 @@ func dumpTLSCertificates(t *tls.ConnectionState) {
 	}
 }
 
+// parseProxyURL parses and validates a proxy URL.
+func parseProxyURL(proxy string) (*url.URL, *probe.Error) {
+	u, e := url.Parse(proxy)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, probe.NewError(fmt.Errorf("unsupported proxy scheme `%s`, valid options are '[http, https, socks5]'", u.Scheme)).Trace(proxy)
+	}
+	if u.Host == "" {
+		return nil, errInvalidArgument().Trace(proxy)
+	}
+	return u, nil
+}
+
+// redactProxyURL hides the password of proxy URLs printed in traces.
+func redactProxyURL(u *url.URL) string {
+	if _, ok := u.User.Password(); ok {
+		redacted := *u
+		redacted.User = url.UserPassword(u.User.Username(), "REDACTED")
+		return redacted.String()
+	}
+	return u.String()
+}
+
+// getProxyFunc returns the proxy selection for the transport of a
+// host. A proxy set in config applies to both HTTP and HTTPS endpoints
+// in place of HTTP_PROXY and HTTPS_PROXY, NO_PROXY is always honored.
+func getProxyFunc(config *Config) (func(*http.Request) (*url.URL, error), *probe.Error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if config.Proxy != "" {
+		if _, err := parseProxyURL(config.Proxy); err != nil {
+			return nil, err.Trace(config.Proxy)
+		}
+		proxyConfig.HTTPProxy = config.Proxy
+		proxyConfig.HTTPSProxy = config.Proxy
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+	if !config.Debug {
+		return func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}, nil
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, e := proxyFunc(req.URL)
+		switch {
+		case e != nil:
+			console.Debugln("Proxy: unable to select proxy for " + req.URL.Host + ": " + e.Error())
+		case proxyURL != nil:
+			console.Debugln("Proxy: connecting to " + req.URL.Host + " through " + redactProxyURL(proxyURL))
+		case proxyConfig.HTTPProxy != "" || proxyConfig.HTTPSProxy != "":
+			console.Debugln("Proxy: connecting to " + req.URL.Host + " directly, excluded by NO_PROXY")
+		}
+		return proxyURL, e
+	}, nil
+}
+
 // isStdIO checks if the input parameter is one of the standard input/output streams
 func isStdIO(reader io.Reader) bool {
 	return reader == os.Stdin || reader == os.Stdout || reader == os.Stderr
@@ -142,8 +205,26 @@ func newS3Config(urlStr string, hostCfg *hostConfigV9) *Config {
 	if hostCfg != nil {
 		s3Config.AccessKey = hostCfg.AccessKey
 		s3Config.SecretKey = hostCfg.SecretKey
+		s3Config.SessionToken = hostCfg.SessionToken
 		s3Config.Signature = hostCfg.API
+		s3Config.CACert = hostCfg.CACert
+		s3Config.ClientCert = hostCfg.ClientCert
+		s3Config.ClientKey = hostCfg.ClientKey
+		s3Config.Fingerprint = hostCfg.Fingerprint
+		s3Config.Proxy = hostCfg.Proxy
+		s3Config.Region = hostCfg.Region
+		s3Config.Accelerate = hostCfg.Accelerate
+		s3Config.DualStack = hostCfg.DualStack
+		s3Config.Endpoints = hostCfg.Endpoints
+	}
+	// Proxy from the command line overrides the configured one.
+	if globalProxy != "" {
+		s3Config.Proxy = globalProxy
 	}
+	s3Config.Headers = hostHeaders(hostCfg)
+	s3Config.RequesterPays = globalRequesterPays || (hostCfg != nil && hostCfg.RequesterPays)
+	// Multipart flags from the command line override the configured ones.
+	s3Config.Multipart = newMultipartConfig(hostCfg).override(globalMultipart)
 	s3Config.Lookup = getLookupType(hostCfg.Lookup)
 	return s3Config
 }
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"net/http"
+	"os"
 	"reflect"
 	"testing"
 
@@ -159,3 +161,81 @@ func TestParseEncryptionKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFingerprint(t *testing.T) {
+	fingerprint := "6A:2B:3C:4D:5E:6F:70:81:92:A3:B4:C5:D6:E7:F8:09:1A:2B:3C:4D:5E:6F:70:81:92:A3:B4:C5:D6:E7:F8:09"
+	testCases := []struct {
+		fingerprint string
+		success     bool
+	}{
+		{fingerprint, true},
+		{"6a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f809", true},
+		{"6A:2B:3C", false},
+		{"not-a-fingerprint", false},
+		{"", false},
+	}
+	for i, testCase := range testCases {
+		b, err := parseFingerprint(testCase.fingerprint)
+		if err != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if testCase.success && formatFingerprint(b) != fingerprint {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, fingerprint, formatFingerprint(b))
+		}
+	}
+}
+
+func TestGetProxyFunc(t *testing.T) {
+	for _, env := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy"} {
+		if value, ok := os.LookupEnv(env); ok {
+			defer os.Setenv(env, value)
+			os.Unsetenv(env)
+		}
+	}
+	os.Setenv("NO_PROXY", "internal.example.com")
+	defer os.Unsetenv("NO_PROXY")
+
+	testCases := []struct {
+		proxy    string
+		reqURL   string
+		expected string
+		success  bool
+	}{
+		{"", "https://s3.amazonaws.com/bucket", "", true},
+		{"socks5://127.0.0.1:1080", "https://s3.amazonaws.com/bucket", "socks5://127.0.0.1:1080", true},
+		{"http://proxy:3128", "http://play.min.io/bucket", "http://proxy:3128", true},
+		{"http://proxy:3128", "https://minio.internal.example.com/bucket", "", true},
+		{"ftp://proxy:21", "https://s3.amazonaws.com/bucket", "", false},
+		{"http://", "https://s3.amazonaws.com/bucket", "", false},
+	}
+	for i, testCase := range testCases {
+		proxyFunc, err := getProxyFunc(&Config{Proxy: testCase.proxy})
+		if err != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if !testCase.success {
+			continue
+		}
+		req, e := http.NewRequest("GET", testCase.reqURL, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		proxyURL, e := proxyFunc(req)
+		if e != nil {
+			t.Fatalf("Test %d: Unexpected error %s", i+1, e)
+		}
+		var got string
+		if proxyURL != nil {
+			got = proxyURL.String()
+		}
+		if got != testCase.expected {
+			t.Errorf("Test %d: Expected proxy `%s`, got `%s`", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// verify specific flags.
+var (
+	verifyFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "verify all objects of a folder recursively",
+		},
+		cli.StringFlag{
+			Name:  "manifest",
+			Usage: "verify the objects of a TARGET folder against the checksums of a manifest FILE, '-' reads the standard input",
+		},
+		hashFlag,
+	}
+)
+
+// Verify the content of objects.
+var verifyCmd = cli.Command{
+	Name:   "verify",
+	Usage:  "verify objects have the same content, comparing checksums",
+	Action: mainVerify,
+	Before: setGlobalsFromContext,
+	Flags:  append(append(verifyFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE TARGET
+  {{.HelpName}} [FLAGS] --manifest FILE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+
+DESCRIPTION:
+  Both objects are read in full and their checksums compared, ETags and metadata are not
+  trusted. Checksums are SHA-256 sums unless --hash selects BLAKE3 or the non-cryptographic
+  xxh64, both faster to compute on fast drives, xxh64 detects corruption but not tampering.
+  Objects of SOURCE missing in TARGET or differing in size are reported without being read,
+  objects only in TARGET are ignored. A recursive verification only prints the objects
+  which do not match, followed by the totals. Exits with status 1 if any object does not
+  match.
+
+  With --manifest the objects listed in FILE are verified below TARGET instead. FILE has
+  JSON lines written by 'mc ls --checksum-manifest' or 'mc mirror --write-manifest', verified
+  with the checksums they have unless --hash is given, or the lines of 'sha256sum', 'b3sum' or
+  'xxhsum' with the checksums of --hash.
+
+EXAMPLES:
+   1. Verify an object copied to Amazon S3 cloud storage.
+      $ {{.HelpName}} backup/2019-10-09.tar.gz s3/backups/2019-10-09.tar.gz
+
+   2. Verify a folder mirrored to MinIO cloud storage, recursively.
+      $ {{.HelpName}} --recursive /var/backups/ myminio/backups/
+
+   3. Verify a bucket replicated to another site, from a cron job alerting on failures.
+      $ {{.HelpName}} --recursive --json site1/mybucket site2/mybucket > verify.log || mail -s "verify failed" ops < verify.log
+
+   4. Verify a data delivery against the manifest written when it was mirrored.
+      $ {{.HelpName}} --manifest delivery.manifest s3/deliveries/2019-10/
+
+   5. Verify a folder copied between NVMe drives, comparing xxh64 sums.
+      $ {{.HelpName}} --recursive --hash xxh64 /mnt/nvme0/dataset/ /mnt/nvme1/dataset/
+
+   6. Verify a bucket against the lines of 'b3sum', the BLAKE3 sums of the files uploaded.
+      $ {{.HelpName}} --manifest dataset.b3sum --hash blake3 s3/datasets/
+`,
+}
+
+// Results of the verification of an object.
+const (
+	verifyMatch    = "match"
+	verifyMismatch = "mismatch"
+	verifyMissing  = "missing"
+	verifySize     = "size"
+)
+
+// verifyMessage - result of the verification of an object.
+type verifyMessage struct {
+	Status       string `json:"status"`
+	Source       string `json:"source"`
+	Target       string `json:"target"`
+	Result       string `json:"result"`
+	SourceSHA256 string `json:"sourceSha256,omitempty"`
+	TargetSHA256 string `json:"targetSha256,omitempty"`
+
+	// Checksums by the algorithm of --hash, SHA-256 sums are also set
+	// above as before.
+	Hash      checksumAlgorithm `json:"hash,omitempty"`
+	SourceSum string            `json:"sourceSum,omitempty"`
+	TargetSum string            `json:"targetSum,omitempty"`
+}
+
+// setSums - set the checksums of the source and the target, and the
+// result comparing them.
+func (v *verifyMessage) setSums(algorithm checksumAlgorithm, sourceSum, targetSum string) {
+	v.Hash, v.SourceSum, v.TargetSum = algorithm, sourceSum, targetSum
+	if algorithm == checksumSHA256 {
+		v.SourceSHA256, v.TargetSHA256 = sourceSum, targetSum
+	}
+	v.Result = verifyMatch
+	if sourceSum != targetSum {
+		v.Result = verifyMismatch
+	}
+}
+
+// String colorized verify message.
+func (v verifyMessage) String() string {
+	switch v.Result {
+	case verifyMatch:
+		return console.Colorize("VerifyMatch", "= "+v.Target)
+	case verifyMissing:
+		return console.Colorize("VerifyMismatch", "< "+v.Source+" (missing)")
+	case verifySize:
+		return console.Colorize("VerifyMismatch", "! "+v.Target+" (size)")
+	}
+	return console.Colorize("VerifyMismatch", "! "+v.Target+" ("+string(v.Hash)+" "+v.TargetSum+", expected "+v.SourceSum+")")
+}
+
+// JSON jsonified verify message.
+func (v verifyMessage) JSON() string {
+	v.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(v, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// verifySummaryMessage - totals of a verification.
+type verifySummaryMessage struct {
+	Status   string `json:"status"`
+	Matching int64  `json:"matching"`
+	Failing  int64  `json:"failing"`
+	Missing  int64  `json:"missing"`
+}
+
+// add counts the result of an object.
+func (s *verifySummaryMessage) add(v verifyMessage) {
+	switch v.Result {
+	case verifyMatch:
+		s.Matching++
+	case verifyMissing:
+		s.Missing++
+	default:
+		s.Failing++
+	}
+}
+
+// String colorized verify summary message.
+func (s verifySummaryMessage) String() string {
+	return console.Colorize("VerifySummary", fmt.Sprintf("Total: %d matching, %d not matching, %d missing", s.Matching, s.Failing, s.Missing))
+}
+
+// JSON jsonified verify summary message.
+func (s verifySummaryMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(struct {
+		Status  string               `json:"status"`
+		Summary verifySummaryMessage `json:"summary"`
+	}{
+		Status:  s.Status,
+		Summary: s,
+	}, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// verifyContent - compare the checksums of an object of the source and
+// the target, both are read at the same time.
+func verifyContent(srcAlias, srcURL, tgtAlias, tgtURL string, encKeyDB map[string][]prefixSSEPair, algorithm checksumAlgorithm) (verifyMessage, *probe.Error) {
+	msg := verifyMessage{Source: srcURL, Target: tgtURL}
+	type sum struct {
+		value string
+		err   *probe.Error
+	}
+	tgtSumCh := make(chan sum, 1)
+	go func() {
+		value, err := contentChecksum(tgtAlias, tgtURL, encKeyDB, algorithm)
+		tgtSumCh <- sum{value, err}
+	}()
+	srcSum, srcErr := contentChecksum(srcAlias, srcURL, encKeyDB, algorithm)
+	tgtSum := <-tgtSumCh
+	if srcErr != nil {
+		return msg, srcErr
+	}
+	if tgtSum.err != nil {
+		return msg, tgtSum.err
+	}
+	msg.setSums(algorithm, srcSum, tgtSum.value)
+	return msg, nil
+}
+
+// verifyFolder - verify all objects of a source folder against the
+// objects at the same path below the target folder.
+func verifyFolder(srcURL, tgtURL string, encKeyDB map[string][]prefixSSEPair, algorithm checksumAlgorithm, summary *verifySummaryMessage) {
+	// Source and targets are always directories
+	if sep := string(newClientURL(srcURL).Separator); !strings.HasSuffix(srcURL, sep) {
+		srcURL += sep
+	}
+	if sep := string(newClientURL(tgtURL).Separator); !strings.HasSuffix(tgtURL, sep) {
+		tgtURL += sep
+	}
+	srcAlias, srcURL, _ := mustExpandAlias(srcURL)
+	tgtAlias, tgtURL, _ := mustExpandAlias(tgtURL)
+
+	srcClnt, err := newClientFromAlias(srcAlias, srcURL)
+	fatalIf(err.Trace(srcURL), "Unable to verify `"+srcURL+"`.")
+	tgtClnt, err := newClientFromAlias(tgtAlias, tgtURL)
+	fatalIf(err.Trace(tgtURL), "Unable to verify `"+tgtURL+"`.")
+
+	for diffMsg := range similarObjectDifference(globalContext, srcClnt, tgtClnt, srcURL, tgtURL) {
+		if diffMsg.Error != nil {
+			errorIf(diffMsg.Error, "Unable to list the objects to verify.")
+			continue
+		}
+		var msg verifyMessage
+		switch diffMsg.Diff {
+		case differInSecond:
+			// Only in the target, nothing to verify.
+			continue
+		case differInFirst:
+			msg = verifyMessage{Source: diffMsg.FirstURL, Result: verifyMissing}
+		case differInSize, differInType:
+			msg = verifyMessage{Source: diffMsg.FirstURL, Target: diffMsg.SecondURL, Result: verifySize}
+		default:
+			// Same size, the content decides whatever the times.
+			msg, err = verifyContent(srcAlias, diffMsg.FirstURL, tgtAlias, diffMsg.SecondURL, encKeyDB, algorithm)
+			if err != nil {
+				errorIf(err, "Unable to verify `%s`.", diffMsg.SecondURL)
+				summary.Failing++
+				continue
+			}
+		}
+		summary.add(msg)
+		if msg.Result != verifyMatch {
+			printMsg(msg)
+		}
+	}
+}
+
+// verifyObject - verify a single object of the source against the
+// target object, or the object of the same name if target is a folder.
+func verifyObject(srcURL, tgtURL string, encKeyDB map[string][]prefixSSEPair, algorithm checksumAlgorithm) verifyMessage {
+	_, srcContent, err := url2Stat(globalContext, srcURL, false, encKeyDB)
+	fatalIf(err.Trace(srcURL), "Unable to stat `"+srcURL+"`.")
+	_, tgtContent, err := url2Stat(globalContext, tgtURL, false, encKeyDB)
+	if err == nil && tgtContent.Type.IsDir() {
+		tgtURL = urlJoinPath(tgtURL, filepath.Base(newClientURL(srcURL).Path))
+		_, tgtContent, err = url2Stat(globalContext, tgtURL, false, encKeyDB)
+	}
+
+	srcAlias, expandedSrcURL, _ := mustExpandAlias(srcURL)
+	tgtAlias, expandedTgtURL, _ := mustExpandAlias(tgtURL)
+	switch {
+	case err != nil:
+		if errorExitStatus(err) != globalNotFoundExitStatus {
+			fatalIf(err.Trace(tgtURL), "Unable to stat `"+tgtURL+"`.")
+		}
+		return verifyMessage{Source: expandedSrcURL, Result: verifyMissing}
+	case srcContent.Size != tgtContent.Size:
+		return verifyMessage{Source: expandedSrcURL, Target: expandedTgtURL, Result: verifySize}
+	}
+	msg, err := verifyContent(srcAlias, expandedSrcURL, tgtAlias, expandedTgtURL, encKeyDB, algorithm)
+	fatalIf(err, "Unable to verify `"+tgtURL+"`.")
+	return msg
+}
+
+// verifyManifest - verify the objects of a manifest against the objects
+// at the same path below the target folder, comparing the sums of
+// algorithm, or of the algorithm of each entry if empty.
+func verifyManifest(entries []checksumManifestEntry, tgtURL string, encKeyDB map[string][]prefixSSEPair, algorithm checksumAlgorithm, summary *verifySummaryMessage) {
+	tgtAlias, _, _ := mustExpandAlias(tgtURL)
+	for _, entry := range entries {
+		entryURL := urlJoinPath(tgtURL, entry.Name)
+		msg := verifyMessage{Source: entry.Name, Target: entryURL}
+		entryAlgorithm, entrySum := entry.checksum(algorithm)
+		if entrySum == "" {
+			errorIf(errInvalidArgument().Trace(entry.Name), "The manifest has no %s checksum of `%s`.", algorithm, entry.Name)
+			summary.Failing++
+			continue
+		}
+		_, content, err := url2Stat(globalContext, entryURL, false, encKeyDB)
+		switch {
+		case err != nil:
+			if errorExitStatus(err) != globalNotFoundExitStatus {
+				errorIf(err.Trace(entryURL), "Unable to stat `%s`.", entryURL)
+				summary.Failing++
+				continue
+			}
+			msg.Result = verifyMissing
+		case content.Type.IsDir() || (entry.Size >= 0 && content.Size != entry.Size):
+			msg.Result = verifySize
+		default:
+			_, expandedURL, _ := mustExpandAlias(entryURL)
+			sum, err := contentChecksum(tgtAlias, expandedURL, encKeyDB, entryAlgorithm)
+			if err != nil {
+				errorIf(err, "Unable to verify `%s`.", entryURL)
+				summary.Failing++
+				continue
+			}
+			msg.setSums(entryAlgorithm, entrySum, sum)
+		}
+		summary.add(msg)
+		if msg.Result != verifyMatch {
+			printMsg(msg)
+		}
+	}
+}
+
+// checkVerifySyntax - validate all the passed arguments.
+func checkVerifySyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
+	if ctx.String("manifest") != "" {
+		if len(ctx.Args()) != 1 || ctx.Bool("recursive") {
+			cli.ShowCommandHelpAndExit(ctx, "verify", 1) // last argument is exit code
+		}
+	} else if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "verify", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	if ctx.String("manifest") != "" {
+		return
+	}
+	srcURL := ctx.Args().Get(0)
+	_, srcContent, err := url2Stat(globalContext, srcURL, false, encKeyDB)
+	fatalIf(err.Trace(srcURL), "Unable to stat `"+srcURL+"`.")
+	if srcContent.Type.IsDir() && !ctx.Bool("recursive") {
+		fatalIf(errInvalidArgument().Trace(srcURL), "To verify a folder requires --recursive flag.")
+	}
+}
+
+// mainVerify is the entry point for verify command.
+func mainVerify(ctx *cli.Context) error {
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(ctx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	// check 'verify' cli arguments.
+	checkVerifySyntax(ctx, encKeyDB)
+
+	// Additional command specific theme customization.
+	console.SetColor("VerifyMatch", color.New(color.FgGreen))
+	console.SetColor("VerifyMismatch", color.New(color.FgRed, color.Bold))
+	console.SetColor("VerifySummary", color.New(color.FgGreen, color.Bold))
+
+	srcURL, tgtURL := ctx.Args().Get(0), ctx.Args().Get(1)
+	algorithm := getChecksumAlgorithm(ctx)
+	var summary verifySummaryMessage
+	if manifest := ctx.String("manifest"); manifest != "" {
+		entries, err := readChecksumManifest(manifest, algorithm)
+		fatalIf(err, "Unable to read the manifest `"+manifest+"`.")
+		// Entries are verified with the sums they have unless --hash
+		// is given.
+		if !ctx.IsSet("hash") {
+			algorithm = ""
+		}
+		verifyManifest(entries, srcURL, encKeyDB, algorithm, &summary)
+	} else if ctx.Bool("recursive") {
+		verifyFolder(srcURL, tgtURL, encKeyDB, algorithm, &summary)
+	} else {
+		msg := verifyObject(srcURL, tgtURL, encKeyDB, algorithm)
+		summary.add(msg)
+		printMsg(msg)
+	}
+
+	printSummaryMsg(summary)
+	if summary.Failing+summary.Missing > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
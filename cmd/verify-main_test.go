@@ -0,0 +1,96 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestVerifyFolder(t *testing.T) {
+	savedLoadMcConfig := loadMcConfig
+	defer func() { loadMcConfig = savedLoadMcConfig }()
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		return newConfigV9(), nil
+	}
+
+	root, e := ioutil.TempDir("", "mc-verify-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	source, target := filepath.Join(root, "source"), filepath.Join(root, "target")
+	files := map[string]string{
+		"source/same":       "same content",
+		"target/same":       "same content",
+		"source/dir/other":  "some content",
+		"target/dir/other":  "other content",
+		"source/touched":    "same text",
+		"target/touched":    "same text",
+		"source/missing":    "missing",
+		"target/extra":      "extra",
+		"source/dir/swap":   "abc",
+		"target/dir/swap":   "abd",
+		"source/dir/resize": "a",
+		"target/dir/resize": "ab",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if e = os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte(content), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	// Times do not matter, only the content does.
+	past := time.Now().Add(-time.Hour)
+	if e = os.Chtimes(filepath.Join(target, "touched"), past, past); e != nil {
+		t.Fatal(e)
+	}
+
+	for _, algorithm := range checksumAlgorithms {
+		var summary verifySummaryMessage
+		verifyFolder(source, target, nil, algorithm, &summary)
+		expected := verifySummaryMessage{Matching: 2, Failing: 3, Missing: 1}
+		if summary != expected {
+			t.Errorf("%s: Expected %+v, got %+v", algorithm, expected, summary)
+		}
+	}
+
+	testCases := []struct {
+		source, target string
+		result         string
+	}{
+		{"source/same", "target/same", verifyMatch},
+		{"source/dir/swap", "target/dir/swap", verifyMismatch},
+		{"source/same", "target", verifyMatch},
+		{"source/missing", "target/missing", verifyMissing},
+		{"source/dir/resize", "target/dir/resize", verifySize},
+	}
+	for i, testCase := range testCases {
+		msg := verifyObject(filepath.Join(root, testCase.source), filepath.Join(root, testCase.target), nil, checksumXXH64)
+		if msg.Result != testCase.result {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.result, msg.Result)
+		}
+	}
+}
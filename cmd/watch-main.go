@@ -58,7 +58,7 @@ var watchCmd = cli.Command{
 	Usage:  "listen for object notification events",
 	Action: mainWatch,
 	Before: setGlobalsFromContext,
-	Flags:  append(watchFlags, globalFlags...),
+	Flags:  append(append(watchFlags, metricsFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -83,6 +83,9 @@ EXAMPLES:
 
    5. Watch for events on local directory.
       $ {{.HelpName}} /usr/share
+
+   6. Watch new events and count them by type for Prometheus on localhost:9464.
+      $ {{.HelpName}} --metrics-addr :9464 play/testbucket
 `,
 }
 
@@ -107,6 +110,21 @@ type watchMessage struct {
 		Port      string `json:"port,omitempty"`
 		UserAgent string `json:"userAgent,omitempty"`
 	} `json:"source,omitempty"`
+	// Replayed is set for missed events replayed by 'event listen'.
+	Replayed bool `json:"replayed,omitempty"`
+}
+
+// newWatchMessage - message of a notification event.
+func newWatchMessage(event EventInfo) watchMessage {
+	msg := watchMessage{}
+	msg.Event.Path = event.Path
+	msg.Event.Size = event.Size
+	msg.Event.Time = event.Time
+	msg.Event.Type = event.Type
+	msg.Source.Host = event.Host
+	msg.Source.Port = event.Port
+	msg.Source.UserAgent = event.UserAgent
+	return msg
 }
 
 func (u watchMessage) JSON() string {
@@ -125,41 +143,15 @@ func (u watchMessage) String() string {
 	}
 	msg += console.Colorize("EventType", fmt.Sprintf("%s ", u.Event.Type))
 	msg += console.Colorize("ObjectName", u.Event.Path)
+	if u.Replayed {
+		msg += console.Colorize("Replayed", " (replayed)")
+	}
 	return msg
 }
 
-func mainWatch(ctx *cli.Context) error {
-	console.SetColor("Time", color.New(color.FgGreen))
-	console.SetColor("Size", color.New(color.FgYellow))
-	console.SetColor("EventType", color.New(color.FgCyan, color.Bold))
-	console.SetColor("ObjectName", color.New(color.Bold))
-
-	checkWatchSyntax(ctx)
-
-	args := ctx.Args()
-	path := args[0]
-
-	prefix := ctx.String("prefix")
-	suffix := ctx.String("suffix")
-	events := strings.Split(ctx.String("events"), ",")
-	recursive := ctx.Bool("recursive")
-
-	s3Client, pErr := newClient(path)
-	if pErr != nil {
-		fatalIf(pErr.Trace(), "Cannot parse the provided url.")
-	}
-
-	params := watchParams{
-		recursive: recursive,
-		events:    events,
-		prefix:    prefix,
-		suffix:    suffix,
-	}
-
-	// Start watching on events
-	wo, err := s3Client.Watch(params)
-	fatalIf(err, "Cannot watch on the specified bucket.")
-
+// printEvents - print the events of the watch until it ends or a
+// signal is received, events are counted by the metrics.
+func printEvents(wo *watchObject, metrics *operationMetrics) {
 	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM)
 
 	// Initialize.. waitgroup to track the go-routine.
@@ -183,15 +175,8 @@ func mainWatch(ctx *cli.Context) error {
 				if !ok {
 					return
 				}
-				msg := watchMessage{}
-				msg.Event.Path = event.Path
-				msg.Event.Size = event.Size
-				msg.Event.Time = event.Time
-				msg.Event.Type = event.Type
-				msg.Source.Host = event.Host
-				msg.Source.Port = event.Port
-				msg.Source.UserAgent = event.UserAgent
-				printMsg(msg)
+				printMsg(newWatchMessage(event))
+				metrics.event(event)
 			case err, ok := <-wo.Errors():
 				if !ok {
 					return
@@ -204,6 +189,44 @@ func mainWatch(ctx *cli.Context) error {
 
 	// Wait on the routine to be finished or exit.
 	wg.Wait()
+}
+
+func mainWatch(ctx *cli.Context) error {
+	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Size", color.New(color.FgYellow))
+	console.SetColor("EventType", color.New(color.FgCyan, color.Bold))
+	console.SetColor("ObjectName", color.New(color.Bold))
+
+	checkWatchSyntax(ctx)
+
+	args := ctx.Args()
+	path := args[0]
+
+	prefix := ctx.String("prefix")
+	suffix := ctx.String("suffix")
+	events := strings.Split(ctx.String("events"), ",")
+	recursive := ctx.Bool("recursive")
+
+	s3Client, pErr := newClient(path)
+	if pErr != nil {
+		fatalIf(pErr.Trace(), "Cannot parse the provided url.")
+	}
+
+	params := watchParams{
+		recursive: recursive,
+		events:    events,
+		prefix:    prefix,
+		suffix:    suffix,
+	}
+
+	metrics, err := newOperationMetrics("watch", ctx.String("metrics-addr"), ctx.String("metrics-statsd"))
+	fatalIf(err, "Unable to serve the metrics.")
+	defer metrics.Close()
+
+	// Start watching on events
+	wo, err := s3Client.Watch(params)
+	fatalIf(err, "Cannot watch on the specified bucket.")
 
+	printEvents(wo, metrics)
 	return nil
 }
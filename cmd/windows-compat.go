@@ -0,0 +1,165 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Device names Windows reserves in every folder, with any extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// isWindowsReservedName - returns true if a file name is a reserved
+// device name, such as 'aux' or 'con.txt'.
+func isWindowsReservedName(name string) bool {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	return windowsReservedNames[strings.ToUpper(strings.TrimRight(name, " "))]
+}
+
+// isWindowsInvalidChar - returns true if a character is not allowed in
+// Windows file names.
+func isWindowsInvalidChar(c byte) bool {
+	return c < 0x20 || strings.IndexByte(`<>:"\|?*`, c) >= 0
+}
+
+// isWindowsEscaped - returns true if a character is escaped by
+// encodeWindowsName, the escapes of all other characters are kept
+// as they are.
+func isWindowsEscaped(c byte) bool {
+	return isWindowsInvalidChar(c) || strings.IndexByte("%. ACLNPacnlp", c) >= 0
+}
+
+// windowsEscape - the character escaped at s[i], if any. Escapes are
+// written in upper case only.
+func windowsEscape(s string, i int) (byte, bool) {
+	if s[i] != '%' || i+2 >= len(s) {
+		return 0, false
+	}
+	const hexDigits = "0123456789ABCDEF"
+	hi, lo := strings.IndexByte(hexDigits, s[i+1]), strings.IndexByte(hexDigits, s[i+2])
+	if hi < 0 || lo < 0 {
+		return 0, false
+	}
+	c := byte(hi<<4 | lo)
+	return c, isWindowsEscaped(c)
+}
+
+// encodeWindowsName - map an object key into a relative path valid on
+// Windows. Characters not allowed in file names, trailing dots and
+// spaces and the first character of reserved device names are escaped
+// as '%XX', as is '%' when it would be read back as an escape.
+func encodeWindowsName(key string) string {
+	components := strings.Split(key, "/")
+	for i, component := range components {
+		var b strings.Builder
+		for j := 0; j < len(component); j++ {
+			c := component[j]
+			_, isEscape := windowsEscape(component, j)
+			switch {
+			case isWindowsInvalidChar(c), isEscape,
+				j == 0 && isWindowsReservedName(component),
+				j == len(component)-1 && (c == '.' || c == ' '):
+				fmt.Fprintf(&b, "%%%02X", c)
+			default:
+				b.WriteByte(c)
+			}
+		}
+		components[i] = b.String()
+	}
+	return strings.Join(components, "/")
+}
+
+// decodeWindowsName - map a relative path written by encodeWindowsName
+// back into its object key.
+func decodeWindowsName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if c, ok := windowsEscape(name, i); ok {
+			b.WriteByte(c)
+			i += 2
+			continue
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
+
+// mapWindowsCompatSuffix - in '--windows-compat' mode, map the suffix of
+// a source into the suffix of its target, keys are encoded when copied
+// to the local filesystem and decoded when copied from it.
+func mapWindowsCompatSuffix(suffix string, isSourceLocal, isTargetLocal bool) string {
+	if !globalWindowsCompat || isSourceLocal == isTargetLocal {
+		return suffix
+	}
+	if isSourceLocal {
+		return decodeWindowsName(suffix)
+	}
+	return encodeWindowsName(suffix)
+}
+
+// sortByWindowsKey - sort a local listing escaped by encodeWindowsName
+// in the order of the object keys of its names, the way difference
+// compares them. The whole listing is read first.
+func sortByWindowsKey(contentCh <-chan *clientContent, listURL, targetURL string) <-chan *clientContent {
+	return sortContents(contentCh, func(content *clientContent) string {
+		suffix := decodeWindowsName(strings.TrimPrefix(content.URL.String(), listURL))
+		return normalizeKey(urlJoinPath(targetURL, suffix))
+	})
+}
+
+// sortContents - sort a listing by the keys of its contents, the whole
+// listing is read first.
+func sortContents(contentCh <-chan *clientContent, keyOf func(*clientContent) string) <-chan *clientContent {
+	type keyedContent struct {
+		key     string
+		content *clientContent
+	}
+	var contents []keyedContent
+	for content := range contentCh {
+		if content.Err != nil {
+			// Differences end at the first error.
+			contents = []keyedContent{{content: content}}
+			go drainContents(contentCh)
+			break
+		}
+		contents = append(contents, keyedContent{keyOf(content), content})
+	}
+	sort.SliceStable(contents, func(i, j int) bool { return contents[i].key < contents[j].key })
+
+	sortedCh := make(chan *clientContent, len(contents))
+	for _, content := range contents {
+		sortedCh <- content.content
+	}
+	close(sortedCh)
+	return sortedCh
+}
+
+// drainContents - read the rest of a listing no longer compared.
+func drainContents(contentCh <-chan *clientContent) {
+	for range contentCh {
+	}
+}
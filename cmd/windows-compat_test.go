@@ -0,0 +1,91 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindowsName(t *testing.T) {
+	testCases := []struct {
+		key  string
+		name string
+	}{
+		{"photos/2019/beach.jpg", "photos/2019/beach.jpg"},
+		{"aux", "%61ux"},
+		{"dir/CON.txt/lpt1", "dir/%43ON.txt/%6Cpt1"},
+		{"auxiliary/console", "auxiliary/console"},
+		{"what?/a<b>:c", "what%3F/a%3Cb%3E%3Ac"},
+		{"back\\slash|pipe*", "back%5Cslash%7Cpipe%2A"},
+		{"trailing./dots..", "trailing%2E/dots.%2E"},
+		{"space /. /..", "space%20/.%20/.%2E"},
+		{"100%", "100%"},
+		{"%42%zz", "%42%zz"},
+		{"%41", "%2541"},
+		{"%3F", "%253F"},
+		{"%25", "%2525"},
+		{"%2e", "%2e"},
+		{"tab\tnewline\n", "tab%09newline%0A"},
+	}
+	for i, testCase := range testCases {
+		name := encodeWindowsName(testCase.key)
+		if name != testCase.name {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.name, name)
+		}
+		if key := decodeWindowsName(name); key != testCase.key {
+			t.Errorf("Test %d: expected key %q back, got %q", i+1, testCase.key, key)
+		}
+	}
+
+	savedWindowsCompat := globalWindowsCompat
+	defer func() { globalWindowsCompat = savedWindowsCompat }()
+	mapCases := []struct {
+		windowsCompat                bool
+		isSourceLocal, isTargetLocal bool
+		suffix, expected             string
+	}{
+		{false, false, true, "a?", "a?"},
+		{true, false, true, "a?", "a%3F"},
+		{true, true, false, "a%3F", "a?"},
+		{true, true, true, "a%3F", "a%3F"},
+		{true, false, false, "a?", "a?"},
+	}
+	for i, testCase := range mapCases {
+		globalWindowsCompat = testCase.windowsCompat
+		if mapped := mapWindowsCompatSuffix(testCase.suffix, testCase.isSourceLocal, testCase.isTargetLocal); mapped != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, mapped)
+		}
+	}
+}
+
+func TestSortByWindowsKey(t *testing.T) {
+	contentCh := make(chan *clientContent, 3)
+	// Listed in the order of the escaped names, '|' sorts after 'b'.
+	for _, name := range []string{"/backup/%7Cpipe", "/backup/b", "/backup/c"} {
+		contentCh <- &clientContent{URL: *newClientURL(name)}
+	}
+	close(contentCh)
+	var names []string
+	for content := range sortByWindowsKey(contentCh, "/backup/", "s3/bucket/") {
+		names = append(names, content.URL.Path)
+	}
+	expected := []string{"/backup/b", "/backup/c", "/backup/%7Cpipe"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
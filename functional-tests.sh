@@ -609,9 +609,9 @@ function test_config_host_add_error()
     show "${FUNCNAME[0]}"
     start_time=$(get_time)
 
-    out=$("${MC_CMD[@]}" --json config host add "${SERVER_ALIAS}1" "$ENDPOINT" "$ACCESS_KEY" "invalid-secret")
+    out=$("${MC_CMD[@]}" --json config host add "${SERVER_ALIAS}1" "$ENDPOINT" "$ACCESS_KEY" "invalid-secret" 2>&1 >/dev/null)
     assert_failure "$start_time" "${FUNCNAME[0]}" show_on_success $? "adding host should fail"
-    got_code=$(echo "$out" | jq -r .error.cause.error.Code)
+    got_code=$(echo "$out" | jq -r .error.code)
     if [ "${got_code}" != "SignatureDoesNotMatch" ]; then
         assert_failure "$start_time" "${FUNCNAME[0]}" show_on_failure 1 "incorrect error code ${got_code} returned by server"
     fi
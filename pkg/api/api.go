@@ -0,0 +1,267 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package api lists, stats, copies and mirrors objects from other Go
+// programs the way the commands ls, stat, cp and mirror of mc do, without
+// printing anything. Every function takes a context, which cancels its
+// requests and transfers, and returns errors instead of exiting. Targets
+// are aliased URLs like 'myminio/mybucket/myobject' or local paths,
+// aliases are read from the config file of mc, see SetConfigDir, or from
+// MC_HOST_<alias> environment variables.
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ObjectInfo - an object, or a folder, of a bucket or a filesystem.
+type ObjectInfo struct {
+	// Aliased URL of the object, which can be passed to other functions.
+	URL string
+	// Key relative to the listed target, set by ListObjects only.
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ETag         string
+	VersionID    string
+	UploadID     string
+	IsDir        bool
+	Metadata     map[string]string
+	UserMetadata map[string]string
+}
+
+// ListOptions - options of ListObjects.
+type ListOptions struct {
+	Recursive bool
+	// Incomplete lists incomplete uploads instead of objects.
+	Incomplete bool
+}
+
+// CopyOptions - options of CopyObjects.
+type CopyOptions struct {
+	Recursive bool
+	// Encrypt and EncryptKey are the --encrypt and --encrypt-key of cp.
+	Encrypt    string
+	EncryptKey string
+	// Progress is called with the number of bytes transferred.
+	Progress func(n int64)
+}
+
+// TransferResult - the objects copied and removed by CopyObjects and MirrorObjects.
+type TransferResult struct {
+	Copied  int64
+	Bytes   int64
+	Removed int64
+	// Objects with names which can not be used as local paths.
+	Skipped []string
+}
+
+// ObjectMissing - the object or folder at URL does not exist.
+type ObjectMissing struct {
+	URL string
+}
+
+func (e ObjectMissing) Error() string {
+	return "Object `" + e.URL + "` does not exist."
+}
+
+// StatObject - the object or folder at target.
+func StatObject(ctx context.Context, target string) (ObjectInfo, error) {
+	if e := ctx.Err(); e != nil {
+		return ObjectInfo{}, e
+	}
+	loc, e := newLocation(target)
+	if e != nil {
+		return ObjectInfo{}, e
+	}
+	info, e := loc.store.stat(ctx, loc.path, nil)
+	if e != nil {
+		return ObjectInfo{}, e
+	}
+	return loc.aliased(info), nil
+}
+
+// ListObjects - call fn with every object and folder at target in key order,
+// until fn returns an error, which ListObjects returns.
+func ListObjects(ctx context.Context, target string, opts ListOptions, fn func(ObjectInfo) error) error {
+	if e := ctx.Err(); e != nil {
+		return e
+	}
+	loc, e := newLocation(target)
+	if e != nil {
+		return e
+	}
+	// Keys are relative to the folder of target like in the output of ls.
+	listFn := func(info ObjectInfo) error {
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+		info.Key = loc.suffix(info.URL, loc.parent())
+		return fn(loc.aliased(info))
+	}
+	if !loc.isFolderPath() {
+		info, e := loc.store.stat(ctx, loc.path, nil)
+		if e != nil {
+			return e
+		}
+		if !info.IsDir {
+			return listFn(info)
+		}
+	}
+	if e = loc.store.list(ctx, loc.folder(), opts.Recursive, opts.Incomplete, listFn); e != nil {
+		return e
+	}
+	return ctx.Err()
+}
+
+// CopyObjects - copy sources to target like cp, stopping at the first error.
+func CopyObjects(ctx context.Context, sources []string, target string, opts CopyOptions) (TransferResult, error) {
+	var result TransferResult
+	if e := ctx.Err(); e != nil {
+		return result, e
+	}
+	keys, e := parseEncryptionKeys(opts.EncryptKey, opts.Encrypt)
+	if e != nil {
+		return result, e
+	}
+	tgt, e := newLocation(target)
+	if e != nil {
+		return result, e
+	}
+	srcs := make([]*location, len(sources))
+	for i, source := range sources {
+		if srcs[i], e = newLocation(source); e != nil {
+			return result, e
+		}
+	}
+	isTargetDir := tgt.isDir(ctx)
+	if len(srcs) > 1 && !isTargetDir {
+		return result, fmt.Errorf("target `%s` is not a folder", target)
+	}
+	t := &transfer{ctx: ctx, keys: keys, progress: opts.Progress, result: &result}
+	for _, src := range srcs {
+		info, e := src.store.stat(ctx, src.path, keys.sse(src.url))
+		if e != nil {
+			return result, e
+		}
+		switch {
+		case info.IsDir && !opts.Recursive:
+			return result, fmt.Errorf("source `%s` is a folder, it is copied with Recursive", src.url)
+		case info.IsDir && src.contains(tgt):
+			return result, fmt.Errorf("source `%s` can not be copied into itself", src.url)
+		case info.IsDir:
+			// Objects under the folder keep their path relative to the
+			// folder of the source, like they are listed.
+			e = src.store.list(ctx, src.folder(), true, false, func(info ObjectInfo) error {
+				return t.copy(src, info, tgt, tgt.join(src, src.suffix(info.URL, src.parent())))
+			})
+		case isTargetDir:
+			e = t.copy(src, info, tgt, tgt.join(src, path.Base(src.suffix(src.path, ""))))
+		default:
+			e = t.copy(src, info, tgt, tgt.path)
+		}
+		if e != nil {
+			return result, e
+		}
+	}
+	return result, ctx.Err()
+}
+
+// transfer - copies of a call of CopyObjects or MirrorObjects.
+type transfer struct {
+	ctx      context.Context
+	keys     encryptionKeys
+	progress func(n int64)
+	result   *TransferResult
+}
+
+// copy - copy the object info of src to the path tgtPath of tgt.
+func (t *transfer) copy(src *location, info ObjectInfo, tgt *location, tgtPath string) error {
+	if e := t.ctx.Err(); e != nil {
+		return e
+	}
+	srcURL := src.aliased(info).URL
+	if tgtPath == "" {
+		// Objects with names which leave a local target are not copied.
+		t.result.Skipped = append(t.result.Skipped, srcURL)
+		return nil
+	}
+	reader, e := src.store.get(t.ctx, info.URL, t.keys.sse(srcURL))
+	if e != nil {
+		return e
+	}
+	defer reader.Close()
+	var r io.Reader = &contextReader{ctx: t.ctx, reader: reader}
+	if t.progress != nil {
+		r = &progressReader{reader: r, progress: t.progress}
+	}
+	tgtURL := tgt.aliased(ObjectInfo{URL: tgtPath}).URL
+	if e = tgt.store.put(t.ctx, tgtPath, r, info.Size, t.keys.sse(tgtURL)); e != nil {
+		return e
+	}
+	t.result.Copied++
+	t.result.Bytes += info.Size
+	return nil
+}
+
+// contextReader - a reader which fails once its context is done.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if e := r.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return r.reader.Read(p)
+}
+
+// progressReader - a reader which reports the bytes read.
+type progressReader struct {
+	reader   io.Reader
+	progress func(n int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, e := r.reader.Read(p)
+	if n > 0 {
+		r.progress(int64(n))
+	}
+	return n, e
+}
+
+// unsafeName - whether a relative object name is unsafe to use as a
+// local path, with empty, '.' or '..' names or control characters.
+func unsafeName(name string) bool {
+	for _, component := range strings.Split(name, "/") {
+		switch component {
+		case "", ".", "..":
+			return true
+		}
+		for i := 0; i < len(component); i++ {
+			if c := component[i]; c < 0x20 || c == 0x7f || c == '\\' {
+				return true
+			}
+		}
+	}
+	return false
+}
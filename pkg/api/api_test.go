@@ -0,0 +1,147 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestObjectsAPI(t *testing.T) {
+	root, e := ioutil.TempDir("", "mc-api-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(root)
+	// Without a config file the defaults are used.
+	SetConfigDir(filepath.Join(root, "config"))
+	source, target := filepath.Join(root, "source"), filepath.Join(root, "target")
+	for name, data := range map[string]string{"a.txt": "a", "dir/b.txt": "bb", "dir/c.txt": "ccc"} {
+		path := filepath.Join(source, filepath.FromSlash(name))
+		if e = os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(path, []byte(data), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	ctx := context.Background()
+
+	var progress int64
+	result, err := CopyObjects(ctx, []string{source + string(os.PathSeparator)}, target, CopyOptions{
+		Recursive: true,
+		Progress:  func(n int64) { progress += n },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (TransferResult{Copied: 3, Bytes: 6}); !reflect.DeepEqual(result, expected) || progress != 6 {
+		t.Errorf("copy: expected %+v and 6 bytes of progress, got %+v and %d", expected, result, progress)
+	}
+
+	var keys []string
+	if err = ListObjects(ctx, target, ListOptions{Recursive: true}, func(info ObjectInfo) error {
+		keys = append(keys, filepath.ToSlash(info.Key))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"target/a.txt", "target/dir/b.txt", "target/dir/c.txt"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("list: expected %v, got %v", expected, keys)
+	}
+
+	info, err := StatObject(ctx, filepath.Join(target, "dir", "c.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != 3 || info.IsDir {
+		t.Errorf("stat: unexpected %+v", info)
+	}
+	if _, err = StatObject(ctx, filepath.Join(target, "missing.txt")); err == nil {
+		t.Error("stat: expected an error for a missing file")
+	}
+
+	// Sources which can not be copied are errors, mc is not exited.
+	if _, err = CopyObjects(ctx, []string{source}, filepath.Join(source, "dir"), CopyOptions{Recursive: true}); err == nil {
+		t.Error("copy: expected an error for a target in the source")
+	}
+	if _, err = CopyObjects(ctx, []string{filepath.Join(source, "missing.txt")}, target, CopyOptions{}); err == nil {
+		t.Error("copy: expected an error for a missing source")
+	}
+
+	if e = os.Remove(filepath.Join(source, "a.txt")); e != nil {
+		t.Fatal(e)
+	}
+	testCases := []struct {
+		opts     MirrorOptions
+		expected TransferResult
+	}{
+		{MirrorOptions{Remove: true, DryRun: true}, TransferResult{Removed: 1}},
+		{MirrorOptions{Remove: true}, TransferResult{Removed: 1}},
+		{MirrorOptions{Remove: true}, TransferResult{}},
+	}
+	for i, testCase := range testCases {
+		result, err = MirrorObjects(ctx, source, target, testCase.opts)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(result, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, result)
+		}
+	}
+
+	// Objects differing from the source are only overwritten with Overwrite.
+	if e = ioutil.WriteFile(filepath.Join(source, "dir", "b.txt"), []byte("bbbb"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	if _, err = MirrorObjects(ctx, source, target, MirrorOptions{}); err == nil {
+		t.Error("mirror: expected an error for an object to overwrite")
+	}
+	overwriteCases := []struct {
+		opts     MirrorOptions
+		expected TransferResult
+	}{
+		{MirrorOptions{Exclude: []string{"dir/b*"}}, TransferResult{}},
+		{MirrorOptions{Overwrite: true}, TransferResult{Copied: 1, Bytes: 4}},
+		{MirrorOptions{Overwrite: true}, TransferResult{}},
+	}
+	for i, testCase := range overwriteCases {
+		result, err = MirrorObjects(ctx, source, target, testCase.opts)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(result, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, result)
+		}
+	}
+
+	if _, err = MirrorObjects(ctx, filepath.Join(source, "dir", "b.txt"), target, MirrorOptions{}); err == nil {
+		t.Error("mirror: expected an error for a file source")
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err = ListObjects(cancelled, target, ListOptions{}, func(ObjectInfo) error { return nil }); err != context.Canceled {
+		t.Errorf("list: expected %v, got %v", context.Canceled, err)
+	}
+	if _, err = StatObject(cancelled, target); err != context.Canceled {
+		t.Errorf("stat: expected %v, got %v", context.Canceled, err)
+	}
+}
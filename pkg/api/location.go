@@ -0,0 +1,192 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// location - a resolved target, the path of a bucket and an object of an
+// alias, or a local path.
+type location struct {
+	// Target as given.
+	url string
+	// Alias of an URL, empty for local paths.
+	alias string
+	// 'bucket/object' of an URL, or a local path.
+	path  string
+	store storage
+}
+
+// newLocation - resolve the alias of target, targets without an alias are
+// local paths.
+func newLocation(target string) (*location, error) {
+	slashed := filepath.ToSlash(target)
+	parts := strings.SplitN(slashed, "/", 2)
+	hostCfg, e := getHostConfig(parts[0])
+	if e != nil {
+		return nil, e
+	}
+	if hostCfg == nil {
+		return &location{url: target, path: filepath.FromSlash(target), store: fsStorage{}}, nil
+	}
+	store, e := newS3Storage(parts[0], hostCfg)
+	if e != nil {
+		return nil, fmt.Errorf("unable to initialize alias `%s`: %v", parts[0], e)
+	}
+	loc := &location{url: target, alias: parts[0], store: store}
+	if len(parts) == 2 {
+		loc.path = parts[1]
+	}
+	return loc, nil
+}
+
+// aliased - info with its aliased URL instead of its path.
+func (l *location) aliased(info ObjectInfo) ObjectInfo {
+	if l.alias == "" {
+		info.URL = filepath.ToSlash(info.URL)
+	} else {
+		info.URL = l.alias + "/" + info.URL
+	}
+	return info
+}
+
+// isFolderPath - whether the path of l ends with a separator, or is an
+// alias by itself.
+func (l *location) isFolderPath() bool {
+	return l.alias != "" && l.path == "" || strings.HasSuffix(l.path, l.store.separator())
+}
+
+// folder - the path of l as a folder, with a trailing separator.
+func (l *location) folder() string {
+	if l.isFolderPath() {
+		return l.path
+	}
+	return l.path + l.store.separator()
+}
+
+// parent - the folder of the path of l, itself when it is a folder path.
+func (l *location) parent() string {
+	sep := l.store.separator()
+	return l.path[:strings.LastIndex(l.path, sep)+len(sep)]
+}
+
+// isDir - whether l is a folder path or an existing folder.
+func (l *location) isDir(ctx context.Context) bool {
+	if l.isFolderPath() {
+		return true
+	}
+	info, e := l.store.stat(ctx, l.path, nil)
+	return e == nil && info.IsDir
+}
+
+// suffix - the path p relative to a folder of l, with '/' separators.
+func (l *location) suffix(p, folder string) string {
+	rel := strings.TrimPrefix(p, folder)
+	if l.alias == "" {
+		return filepath.ToSlash(rel)
+	}
+	return rel
+}
+
+// contains - whether the folder of other is the folder of l or under it.
+func (l *location) contains(other *location) bool {
+	return l.alias == other.alias && strings.HasPrefix(other.folder(), l.folder())
+}
+
+// join - the path of the suffix of an object of src under the folder of
+// l, which is empty when the name of a bucket object is not safe as a
+// local path.
+func (l *location) join(src *location, suffix string) string {
+	if l.alias != "" {
+		return l.folder() + suffix
+	}
+	if src.alias != "" && unsafeName(suffix) {
+		return ""
+	}
+	return l.folder() + filepath.FromSlash(suffix)
+}
+
+// prefixSSE - the encryption of the objects of an aliased prefix.
+type prefixSSE struct {
+	prefix string
+	sse    encrypt.ServerSide
+}
+
+// encryptionKeys - the encryption of objects, longer prefixes first.
+type encryptionKeys []prefixSSE
+
+// sse - the encryption of the object at an aliased URL, nil without one.
+func (keys encryptionKeys) sse(aliasedURL string) encrypt.ServerSide {
+	for _, k := range keys {
+		if strings.HasPrefix(aliasedURL, k.prefix) {
+			return k.sse
+		}
+	}
+	return nil
+}
+
+// parseEncryptionKeys - the encryption keys of --encrypt-key, comma
+// separated 'alias/prefix=key' values with 32 bytes long keys, and of
+// --encrypt, comma separated 'alias/prefix' values.
+func parseEncryptionKeys(sseKeys, sse string) (encryptionKeys, error) {
+	const sseKeyLen = 32
+	var keys encryptionKeys
+	for index := 0; index < len(sseKeys); {
+		i := strings.Index(sseKeys[index:], "=")
+		if i == -1 {
+			return nil, errors.New("SSE-C prefix should be of the form prefix1=key1,... ")
+		}
+		prefix := sseKeys[index : index+i]
+		vs := index + i + 1
+		if vs+sseKeyLen > len(sseKeys) {
+			return nil, errors.New("SSE-C key should be 32 bytes long")
+		}
+		if vs+sseKeyLen < len(sseKeys) && sseKeys[vs+sseKeyLen] != ',' {
+			return nil, errors.New("SSE-C prefix=secret should be delimited by , and secret should be 32 bytes long")
+		}
+		sseC, e := encrypt.NewSSEC([]byte(sseKeys[vs : vs+sseKeyLen]))
+		if e != nil {
+			return nil, e
+		}
+		keys = append(keys, prefixSSE{prefix: prefix, sse: sseC})
+		index = vs + sseKeyLen + 1
+	}
+	if sse != "" {
+		for _, prefix := range strings.Split(sse, ",") {
+			keys = append(keys, prefixSSE{prefix: prefix, sse: encrypt.NewSSE()})
+		}
+	}
+	for _, k := range keys {
+		hostCfg, e := getHostConfig(strings.SplitN(k.prefix, "/", 2)[0])
+		if e != nil {
+			return nil, e
+		}
+		if hostCfg == nil {
+			return nil, errors.New("SSE prefix " + k.prefix + " has invalid alias")
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return len(keys[i].prefix) > len(keys[j].prefix) })
+	return keys, nil
+}
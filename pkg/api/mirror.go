@@ -0,0 +1,182 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// MirrorOptions - options of MirrorObjects.
+type MirrorOptions struct {
+	// Overwrite objects of the target which differ from the source.
+	Overwrite bool
+	// Remove objects of the target which are not in the source.
+	Remove bool
+	// DryRun only counts the objects to copy and remove.
+	DryRun bool
+	// Exclude objects with a name matching one of the patterns.
+	Exclude []string
+	// Encrypt and EncryptKey are the --encrypt and --encrypt-key of mirror.
+	Encrypt    string
+	EncryptKey string
+	// Progress is called with the number of bytes transferred.
+	Progress func(n int64)
+}
+
+// listed - an object of a listing, by its key relative to the listed
+// folder, or the error which ended the listing.
+type listed struct {
+	key  string
+	info ObjectInfo
+	err  error
+}
+
+// listKeys - list the objects under the folder of loc recursively, the
+// listing stops when ctx is done.
+func listKeys(ctx context.Context, loc *location) <-chan listed {
+	listCh := make(chan listed)
+	go func() {
+		defer close(listCh)
+		folder := loc.folder()
+		e := loc.store.list(ctx, folder, true, false, func(info ObjectInfo) error {
+			select {
+			case listCh <- listed{key: loc.suffix(info.URL, folder), info: info}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if e != nil {
+			select {
+			case listCh <- listed{err: e}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return listCh
+}
+
+// isExcluded - whether the key matches a pattern of exclude.
+func isExcluded(exclude []string, key string) bool {
+	for _, pattern := range exclude {
+		if wildcard.Match(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// MirrorObjects - mirror the folder source to target like mirror, stopping at
+// the first error.
+func MirrorObjects(ctx context.Context, source, target string, opts MirrorOptions) (TransferResult, error) {
+	var result TransferResult
+	if e := ctx.Err(); e != nil {
+		return result, e
+	}
+	keys, e := parseEncryptionKeys(opts.EncryptKey, opts.Encrypt)
+	if e != nil {
+		return result, e
+	}
+	src, e := newLocation(source)
+	if e != nil {
+		return result, e
+	}
+	tgt, e := newLocation(target)
+	if e != nil {
+		return result, e
+	}
+	info, e := src.store.stat(ctx, src.path, nil)
+	if e != nil {
+		return result, e
+	}
+	if !info.IsDir {
+		return result, fmt.Errorf("source `%s` is not a folder", source)
+	}
+	if src.contains(tgt) || tgt.contains(src) {
+		return result, fmt.Errorf("source `%s` and target `%s` overlap", source, target)
+	}
+
+	// Both sides are listed in key order and merge-joined.
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	srcCh, tgtCh := listKeys(listCtx, src), listKeys(listCtx, tgt)
+	srcObj, srcOk := <-srcCh
+	tgtObj, tgtOk := <-tgtCh
+	t := &transfer{ctx: ctx, keys: keys, progress: opts.Progress, result: &result}
+	for srcOk || tgtOk {
+		switch {
+		case srcOk && srcObj.err != nil:
+			return result, srcObj.err
+		case tgtOk && tgtObj.err != nil:
+			if _, ok := tgtObj.err.(ObjectMissing); !ok {
+				return result, tgtObj.err
+			}
+			// A missing target is mirrored to.
+			tgtObj, tgtOk = <-tgtCh
+			continue
+		}
+		// The objects to copy or remove, before listing the next ones.
+		var copyInfo, removeInfo *ObjectInfo
+		srcInfo, tgtInfo := srcObj.info, tgtObj.info
+		switch {
+		case !tgtOk || srcOk && srcObj.key < tgtObj.key:
+			// Only in the source.
+			if !isExcluded(opts.Exclude, srcObj.key) {
+				copyInfo = &srcInfo
+			}
+			srcObj, srcOk = <-srcCh
+		case !srcOk || tgtObj.key < srcObj.key:
+			// Only in the target.
+			if opts.Remove && !isExcluded(opts.Exclude, tgtObj.key) {
+				removeInfo = &tgtInfo
+			}
+			tgtObj, tgtOk = <-tgtCh
+		default:
+			if !isExcluded(opts.Exclude, srcObj.key) &&
+				(srcInfo.Size != tgtInfo.Size || srcInfo.LastModified.After(tgtInfo.LastModified)) {
+				if !opts.Overwrite && !opts.DryRun {
+					return result, fmt.Errorf("overwrite of `%s` not allowed, it differs from the source", tgt.aliased(tgtInfo).URL)
+				}
+				copyInfo = &srcInfo
+			}
+			srcObj, srcOk = <-srcCh
+			tgtObj, tgtOk = <-tgtCh
+		}
+
+		switch {
+		case copyInfo != nil && opts.DryRun:
+			result.Copied++
+			result.Bytes += copyInfo.Size
+		case copyInfo != nil:
+			tgtPath := tgt.join(src, src.suffix(copyInfo.URL, src.folder()))
+			if e = t.copy(src, *copyInfo, tgt, tgtPath); e != nil {
+				return result, e
+			}
+		case removeInfo != nil && opts.DryRun:
+			result.Removed++
+		case removeInfo != nil:
+			if e = tgt.store.remove(ctx, removeInfo.URL); e != nil {
+				return result, e
+			}
+			result.Removed++
+		}
+	}
+	return result, ctx.Err()
+}
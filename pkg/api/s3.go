@@ -0,0 +1,243 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+
+	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio-go/v6/pkg/credentials"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// s3Storage - the buckets of an alias.
+type s3Storage struct {
+	alias  string
+	client *minio.Client
+}
+
+// newS3Storage - the storage of the host of an alias.
+func newS3Storage(alias string, hostCfg *hostConfig) (*s3Storage, error) {
+	u, e := url.Parse(hostCfg.URL)
+	if e != nil {
+		return nil, e
+	}
+	creds := credentials.NewStaticV4(hostCfg.AccessKey, hostCfg.SecretKey, hostCfg.SessionToken)
+	if strings.ToUpper(hostCfg.API) == "S3V2" {
+		creds = credentials.NewStaticV2(hostCfg.AccessKey, hostCfg.SecretKey, hostCfg.SessionToken)
+	}
+	lookup := minio.BucketLookupAuto
+	switch strings.ToLower(hostCfg.Lookup) {
+	case "dns":
+		lookup = minio.BucketLookupDNS
+	case "path":
+		lookup = minio.BucketLookupPath
+	}
+	client, e := minio.NewWithOptions(u.Host, &minio.Options{
+		Creds:        creds,
+		Secure:       u.Scheme == "https",
+		Region:       hostCfg.Region,
+		BucketLookup: lookup,
+	})
+	if e != nil {
+		return nil, e
+	}
+	return &s3Storage{alias: alias, client: client}, nil
+}
+
+func (s *s3Storage) separator() string {
+	return "/"
+}
+
+// splitPath - the bucket and the object of a path.
+func splitPath(p string) (bucket, object string) {
+	parts := strings.SplitN(p, "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// s3Error - an error of a path, missing buckets and objects are
+// ObjectMissing.
+func (s *s3Storage) s3Error(e error, p string) error {
+	switch minio.ToErrorResponse(e).Code {
+	case "NoSuchBucket", "NoSuchKey":
+		return ObjectMissing{URL: s.alias + "/" + p}
+	}
+	return e
+}
+
+// getOptions - the options of reading an object, only SSE-C is sent.
+func getOptions(sse encrypt.ServerSide) minio.GetObjectOptions {
+	var opts minio.GetObjectOptions
+	if sse != nil && sse.Type() == encrypt.SSEC {
+		opts.ServerSideEncryption = sse
+	}
+	return opts
+}
+
+// newObjectInfo - the info of an object of a bucket.
+func newObjectInfo(bucket string, object minio.ObjectInfo) ObjectInfo {
+	info := ObjectInfo{
+		URL:          bucket + "/" + object.Key,
+		Size:         object.Size,
+		LastModified: object.LastModified,
+		ETag:         strings.Trim(object.ETag, "\""),
+		VersionID:    object.Metadata.Get("X-Amz-Version-Id"),
+	}
+	if len(object.Metadata) > 0 {
+		info.Metadata = make(map[string]string)
+		info.UserMetadata = make(map[string]string)
+		for k := range object.Metadata {
+			info.Metadata[k] = object.Metadata.Get(k)
+			if strings.HasPrefix(k, "X-Amz-Meta-") {
+				info.UserMetadata[strings.TrimPrefix(k, "X-Amz-Meta-")] = object.Metadata.Get(k)
+			}
+		}
+	}
+	return info
+}
+
+func (s *s3Storage) stat(ctx context.Context, p string, sse encrypt.ServerSide) (ObjectInfo, error) {
+	bucket, object := splitPath(p)
+	switch {
+	case bucket == "":
+		return ObjectInfo{URL: p, IsDir: true}, nil
+	case object == "":
+		ok, e := s.client.BucketExists(bucket)
+		if e != nil {
+			return ObjectInfo{}, s.s3Error(e, p)
+		}
+		if !ok {
+			return ObjectInfo{}, ObjectMissing{URL: s.alias + "/" + p}
+		}
+		return ObjectInfo{URL: p, IsDir: true}, nil
+	case !strings.HasSuffix(object, "/"):
+		st, e := s.client.StatObject(bucket, object, minio.StatObjectOptions{GetObjectOptions: getOptions(sse)})
+		if e == nil {
+			return newObjectInfo(bucket, st), nil
+		}
+		if minio.ToErrorResponse(e).Code != "NoSuchKey" {
+			return ObjectInfo{}, s.s3Error(e, p)
+		}
+	}
+	// Prefixes with objects under them are folders.
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	for object := range s.client.ListObjectsV2(bucket, strings.TrimSuffix(object, "/")+"/", false, doneCh) {
+		if object.Err != nil {
+			return ObjectInfo{}, s.s3Error(object.Err, p)
+		}
+		return ObjectInfo{URL: p, IsDir: true}, nil
+	}
+	return ObjectInfo{}, ObjectMissing{URL: s.alias + "/" + p}
+}
+
+func (s *s3Storage) list(ctx context.Context, folder string, isRecursive, isIncomplete bool, fn func(ObjectInfo) error) error {
+	if folder == "" {
+		// The buckets of the alias.
+		buckets, e := s.client.ListBuckets()
+		if e != nil {
+			return e
+		}
+		for _, bucket := range buckets {
+			if isRecursive {
+				e = s.list(ctx, bucket.Name+"/", isRecursive, isIncomplete, fn)
+			} else if !isIncomplete {
+				e = fn(ObjectInfo{URL: bucket.Name + "/", LastModified: bucket.CreationDate, IsDir: true})
+			}
+			if e != nil {
+				return e
+			}
+		}
+		return nil
+	}
+
+	bucket, prefix := splitPath(folder)
+	// The listing stops when returning.
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	if isIncomplete {
+		for upload := range s.client.ListIncompleteUploads(bucket, prefix, isRecursive, doneCh) {
+			if upload.Err != nil {
+				return s.s3Error(upload.Err, folder)
+			}
+			if e := ctx.Err(); e != nil {
+				return e
+			}
+			info := ObjectInfo{URL: bucket + "/" + upload.Key, Size: upload.Size, LastModified: upload.Initiated, UploadID: upload.UploadID}
+			if e := fn(info); e != nil {
+				return e
+			}
+		}
+		return nil
+	}
+	for object := range s.client.ListObjectsV2(bucket, prefix, isRecursive, doneCh) {
+		if object.Err != nil {
+			return s.s3Error(object.Err, folder)
+		}
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+		info := newObjectInfo(bucket, object)
+		if strings.HasSuffix(object.Key, "/") {
+			// Recursive listings have no folders, but empty objects
+			// marking folders.
+			if isRecursive {
+				continue
+			}
+			info.IsDir = true
+		}
+		if e := fn(info); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *s3Storage) get(ctx context.Context, p string, sse encrypt.ServerSide) (io.ReadCloser, error) {
+	bucket, object := splitPath(p)
+	reader, e := s.client.GetObjectWithContext(ctx, bucket, object, getOptions(sse))
+	if e != nil {
+		return nil, s.s3Error(e, p)
+	}
+	return reader, nil
+}
+
+func (s *s3Storage) put(ctx context.Context, p string, reader io.Reader, size int64, sse encrypt.ServerSide) error {
+	bucket, object := splitPath(p)
+	if object == "" || strings.HasSuffix(object, "/") {
+		return errors.New("`" + s.alias + "/" + p + "` is not the name of an object")
+	}
+	_, e := s.client.PutObjectWithContext(ctx, bucket, object, reader, size, minio.PutObjectOptions{
+		ContentType:          mime.TypeByExtension(path.Ext(object)),
+		ServerSideEncryption: sse,
+	})
+	return s.s3Error(e, p)
+}
+
+func (s *s3Storage) remove(ctx context.Context, p string) error {
+	bucket, object := splitPath(p)
+	return s.s3Error(s.client.RemoveObject(bucket, object), p)
+}
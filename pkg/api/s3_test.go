@@ -0,0 +1,79 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestS3Storage(t *testing.T) {
+	// A bucket with the object dir/a.txt and the folder dir/sub/.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, isLocation := r.URL.Query()["location"]
+		switch {
+		case isLocation:
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint>us-east-1</LocationConstraint>`)
+		case r.Method == http.MethodHead && r.URL.Path == "/bucket/dir/a.txt":
+			w.Header().Set("Content-Length", "3")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			w.Header().Set("ETag", `"abc"`)
+			w.Header().Set("X-Amz-Meta-Color", "blue")
+		case r.Method == http.MethodGet && r.URL.Query().Get("prefix") == "dir/":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>bucket</Name><Prefix>dir/</Prefix>`+
+				`<KeyCount>2</KeyCount><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>`+
+				`<Contents><Key>dir/a.txt</Key><Size>3</Size><ETag>"abc"</ETag></Contents>`+
+				`<CommonPrefixes><Prefix>dir/sub/</Prefix></CommonPrefixes></ListBucketResult>`)
+		case r.Method == http.MethodHead || r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer server.Close()
+	os.Setenv("MC_HOST_mytest", server.URL)
+	defer os.Unsetenv("MC_HOST_mytest")
+	ctx := context.Background()
+
+	info, err := StatObject(ctx, "mytest/bucket/dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.URL != "mytest/bucket/dir/a.txt" || info.Size != 3 || info.ETag != "abc" || info.UserMetadata["Color"] != "blue" {
+		t.Errorf("stat: unexpected %+v", info)
+	}
+	// Prefixes with objects under them are folders.
+	if info, err = StatObject(ctx, "mytest/bucket/dir"); err != nil || !info.IsDir {
+		t.Errorf("stat: expected a folder, got %+v and %v", info, err)
+	}
+
+	var keys []string
+	if err = ListObjects(ctx, "mytest/bucket/dir", ListOptions{}, func(info ObjectInfo) error {
+		keys = append(keys, fmt.Sprintf("%s %v", info.Key, info.IsDir))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"dir/a.txt false", "dir/sub/ true"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("list: expected %v, got %v", expected, keys)
+	}
+}
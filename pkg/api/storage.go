@@ -0,0 +1,163 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/mc/pkg/ioutils"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// storage - the objects of an alias or of the filesystem, by path.
+// Infos returned have their path as URL.
+type storage interface {
+	// separator - the separator of folders in paths.
+	separator() string
+	stat(ctx context.Context, path string, sse encrypt.ServerSide) (ObjectInfo, error)
+	// list - call fn in key order with the objects under a folder
+	// path, the folders under it too when it is not recursive.
+	list(ctx context.Context, folder string, isRecursive, isIncomplete bool, fn func(ObjectInfo) error) error
+	get(ctx context.Context, path string, sse encrypt.ServerSide) (io.ReadCloser, error)
+	// put - write an object of size bytes, or of an unknown size
+	// when size is -1.
+	put(ctx context.Context, path string, reader io.Reader, size int64, sse encrypt.ServerSide) error
+	remove(ctx context.Context, path string) error
+}
+
+// Suffix of local files while they are written, which are listed as
+// incomplete uploads.
+const partSuffix = ".part.minio"
+
+// fsStorage - the files of the filesystem.
+type fsStorage struct{}
+
+func (fsStorage) separator() string {
+	return string(os.PathSeparator)
+}
+
+// fsError - an error of a path, missing paths are ObjectMissing.
+func fsError(e error, path string) error {
+	if os.IsNotExist(e) {
+		return ObjectMissing{URL: filepath.ToSlash(path)}
+	}
+	return e
+}
+
+// newFileInfo - the info of a file or folder.
+func newFileInfo(path string, fi os.FileInfo) ObjectInfo {
+	info := ObjectInfo{URL: path, LastModified: fi.ModTime(), IsDir: fi.IsDir()}
+	if !fi.IsDir() {
+		info.Size = fi.Size()
+	}
+	return info
+}
+
+func (fsStorage) stat(ctx context.Context, path string, sse encrypt.ServerSide) (ObjectInfo, error) {
+	fi, e := os.Stat(path)
+	if e != nil {
+		return ObjectInfo{}, fsError(e, path)
+	}
+	return newFileInfo(path, fi), nil
+}
+
+func (s fsStorage) list(ctx context.Context, folder string, isRecursive, isIncomplete bool, fn func(ObjectInfo) error) error {
+	// Files being written are listed as incomplete uploads only.
+	listFile := func(path string, fi os.FileInfo) error {
+		if fi.Mode()&os.ModeSymlink != 0 {
+			// Links are listed as the files they point to.
+			var e error
+			if fi, e = os.Stat(path); e != nil || fi.IsDir() {
+				return nil
+			}
+		}
+		if !fi.Mode().IsRegular() || strings.HasSuffix(path, partSuffix) != isIncomplete {
+			return nil
+		}
+		return fn(newFileInfo(path, fi))
+	}
+	if !isRecursive {
+		e := ioutils.ReadDirFunc(folder, func(fi os.FileInfo) error {
+			path := filepath.Join(folder, fi.Name())
+			if fi.IsDir() {
+				if isIncomplete {
+					return nil
+				}
+				info := newFileInfo(path+s.separator(), fi)
+				return fn(info)
+			}
+			return listFile(path, fi)
+		})
+		return fsError(e, folder)
+	}
+	return ioutils.FTW(folder, func(path string, fi os.FileInfo, e error) error {
+		if e != nil {
+			return fsError(e, path)
+		}
+		if e = ctx.Err(); e != nil {
+			return e
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		return listFile(path, fi)
+	})
+}
+
+func (fsStorage) get(ctx context.Context, path string, sse encrypt.ServerSide) (io.ReadCloser, error) {
+	f, e := os.Open(path)
+	if e != nil {
+		return nil, fsError(e, path)
+	}
+	return f, nil
+}
+
+// put - write the file next to path first, and rename it once it is
+// complete.
+func (fsStorage) put(ctx context.Context, path string, reader io.Reader, size int64, sse encrypt.ServerSide) error {
+	if e := os.MkdirAll(filepath.Dir(path), 0777); e != nil {
+		return e
+	}
+	partPath := path + partSuffix
+	f, e := os.OpenFile(partPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if e != nil {
+		return e
+	}
+	n, e := io.Copy(f, reader)
+	if ce := f.Close(); e == nil {
+		e = ce
+	}
+	if e == nil && size >= 0 && n != size {
+		e = fmt.Errorf("`%s` has %d bytes instead of %d", filepath.ToSlash(path), n, size)
+	}
+	if e == nil {
+		e = os.Rename(partPath, path)
+	}
+	if e != nil {
+		os.Remove(partPath)
+	}
+	return e
+}
+
+func (fsStorage) remove(ctx context.Context, path string) error {
+	return fsError(os.Remove(path), path)
+}
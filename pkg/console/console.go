@@ -61,6 +61,12 @@ var (
 		consolePrintln("Print", Theme["Print"], data...)
 	}
 
+	// Eprintln prints a message with a new line to stderr, without the
+	// error prefix, like structured error messages.
+	Eprintln = func(data ...interface{}) {
+		consolePrintln("Eprint", Theme["Print"], data...)
+	}
+
 	// Fatal print a error message and exit.
 	Fatal = func(data ...interface{}) {
 		consolePrint("Fatal", Theme["Fatal"], data...)
@@ -79,6 +85,12 @@ var (
 		os.Exit(1)
 	}
 
+	// FatalStatusln print a error message with a new line and exit with status.
+	FatalStatusln = func(status int, data ...interface{}) {
+		consolePrintln("Fatal", Theme["Fatal"], data...)
+		os.Exit(status)
+	}
+
 	// Error prints a error message.
 	Error = func(data ...interface{}) {
 		consolePrint("Error", Theme["Error"], data...)
@@ -306,6 +318,15 @@ func consolePrintln(tag string, c *color.Color, a ...interface{}) {
 			fmt.Fprintln(color.Output, a...)
 		}
 		color.Output = output
+	case "Eprint":
+		output := color.Output
+		color.Output = stderrColoredOutput
+		if isatty.IsTerminal(os.Stderr.Fd()) {
+			c.Println(a...)
+		} else {
+			fmt.Fprintln(color.Output, a...)
+		}
+		color.Output = output
 	case "Info":
 		// if no arguments are given do not invoke info printer.
 		if len(a) == 0 {
@@ -40,3 +40,23 @@ func (s *MySuite) TestLock(c *C) {
 	Print("") // Test for deadlocks.
 	Unlock()
 }
+
+func (s *MySuite) TestColorAttributes(c *C) {
+	attrs := ColorAttributes(color.New(color.FgRed, color.Bold))
+	c.Assert(attrs, DeepEquals, []color.Attribute{color.FgRed, color.Bold})
+	c.Assert(ColorAttributes(color.New()), IsNil)
+}
+
+func (s *MySuite) TestColorOverride(c *C) {
+	SetColorOverride("Overridden", color.New(color.FgBlue))
+	SetColor("Overridden", color.New(color.FgRed))
+	c.Assert(Theme["Overridden"].Equals(color.New(color.FgBlue)), Equals, true)
+
+	SetColorFilter(func(attrs []color.Attribute) []color.Attribute {
+		return append(attrs, color.Underline)
+	})
+	defer SetColorFilter(nil)
+	SetColor("Filtered", color.New(color.FgRed))
+	c.Assert(Theme["Filtered"].Equals(color.New(color.FgRed, color.Underline)), Equals, true)
+	c.Assert(Theme["Overridden"].Equals(color.New(color.FgBlue)), Equals, true)
+}
@@ -16,7 +16,12 @@
 
 package console
 
-import "github.com/fatih/color"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
 
 var (
 	// Theme contains default color mapping.
@@ -29,6 +34,12 @@ var (
 		"PrintB": color.New(color.FgBlue, color.Bold),
 		"PrintC": color.New(color.FgGreen, color.Bold),
 	}
+
+	// Colors of tags set by the user, replacing those set by SetColor.
+	colorOverrides = map[string]*color.Color{}
+
+	// Filter of the attributes of all colors, set by built-in themes.
+	colorFilter func([]color.Attribute) []color.Attribute
 )
 
 // SetColorOff disables coloring for the entire session.
@@ -45,10 +56,63 @@ func SetColorOn() {
 	color.NoColor = false
 }
 
-// SetColor sets a color for a particular tag.
+// SetColor sets a color for a particular tag, unless the user set
+// another color for it with SetColorOverride.
 func SetColor(tag string, cl *color.Color) {
 	privateMutex.Lock()
 	defer privateMutex.Unlock()
 	// add new theme
+	Theme[tag] = themeColor(tag, cl)
+}
+
+// SetColorOverride sets the color of a tag for the entire session,
+// colors set later by SetColor for the tag are ignored.
+func SetColorOverride(tag string, cl *color.Color) {
+	privateMutex.Lock()
+	defer privateMutex.Unlock()
+	colorOverrides[tag] = cl
 	Theme[tag] = cl
 }
+
+// SetColorFilter sets a filter of the attributes of all colors for the
+// entire session, except those set by SetColorOverride.
+func SetColorFilter(filter func([]color.Attribute) []color.Attribute) {
+	privateMutex.Lock()
+	defer privateMutex.Unlock()
+	colorFilter = filter
+	for tag, cl := range Theme {
+		Theme[tag] = themeColor(tag, cl)
+	}
+}
+
+// themeColor - the color of a tag after the user overrides and filter.
+func themeColor(tag string, cl *color.Color) *color.Color {
+	if override, ok := colorOverrides[tag]; ok {
+		return override
+	}
+	if colorFilter == nil {
+		return cl
+	}
+	return color.New(colorFilter(ColorAttributes(cl))...)
+}
+
+// ColorAttributes returns the attributes of a color. The color package
+// keeps them private, they are read back from the escape sequence of a
+// copy of the color with coloring enabled.
+func ColorAttributes(cl *color.Color) []color.Attribute {
+	enabled := *cl
+	enabled.EnableColor()
+	sequence := enabled.Sprint("")
+	start := strings.Index(sequence, "\x1b[")
+	end := strings.Index(sequence, "m")
+	if start < 0 || end < start {
+		return nil
+	}
+	var attrs []color.Attribute
+	for _, field := range strings.Split(sequence[start+2:end], ";") {
+		if attr, e := strconv.Atoi(field); e == nil {
+			attrs = append(attrs, color.Attribute(attr))
+		}
+	}
+	return attrs
+}
@@ -31,6 +31,13 @@ type HTTPTracer interface {
 	Response(res *http.Response) error
 }
 
+// RoundTripTracer is implemented by tracers that also want a summary of
+// every round trip, including the ones which failed without a response.
+type RoundTripTracer interface {
+	HTTPTracer
+	RoundTripDone(req *http.Request, res *http.Response, err error, duration time.Duration)
+}
+
 // RoundTripTrace interposes HTTP transport requests and respsonses using HTTPTracer hooks
 type RoundTripTrace struct {
 	Trace     HTTPTracer        // User provides callback methods
@@ -45,6 +52,15 @@ func (t RoundTripTrace) RoundTrip(req *http.Request) (res *http.Response, err er
 		return nil, errors.New("Invalid Argument")
 	}
 
+	rt, isRoundTripTracer := t.Trace.(RoundTripTracer)
+	if isRoundTripTracer {
+		// Tracers keep state per request until it is done, however
+		// the round trip ends.
+		defer func() {
+			rt.RoundTripDone(req, res, err, time.Since(timeStamp))
+		}()
+	}
+
 	res, err = t.Transport.RoundTrip(req)
 	if err != nil {
 		return res, err
@@ -53,14 +69,18 @@ func (t RoundTripTrace) RoundTrip(req *http.Request) (res *http.Response, err er
 	if t.Trace != nil {
 		err = t.Trace.Request(req)
 		if err != nil {
+			res.Body.Close()
 			return nil, err
 		}
 
 		err = t.Trace.Response(res)
 		if err != nil {
+			res.Body.Close()
 			return nil, err
 		}
-		console.Debugln("Response Time: ", time.Since(timeStamp).String()+"\n")
+		if !isRoundTripTracer {
+			console.Debugln("Response Time: ", time.Since(timeStamp).String()+"\n")
+		}
 	}
 	return res, err
 }
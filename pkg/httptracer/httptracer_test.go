@@ -17,7 +17,11 @@
 package httptracer
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	. "gopkg.in/check.v1"
 )
@@ -31,3 +35,42 @@ var _ = Suite(&MySuite{})
 func (s *MySuite) TestHTTPTracer(c *C) {
 	//
 }
+
+// roundTripTracer - records the round trips done, its hooks fail with
+// the errors set.
+type roundTripTracer struct {
+	requestErr, responseErr error
+	done                    []error
+}
+
+func (t *roundTripTracer) Request(req *http.Request) error   { return t.requestErr }
+func (t *roundTripTracer) Response(res *http.Response) error { return t.responseErr }
+func (t *roundTripTracer) RoundTripDone(req *http.Request, res *http.Response, err error, duration time.Duration) {
+	t.done = append(t.done, err)
+}
+
+func (s *MySuite) TestRoundTripDone(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	errRequest, errResponse := errors.New("request hook"), errors.New("response hook")
+	testCases := []struct {
+		tracer *roundTripTracer
+		err    error
+	}{
+		{&roundTripTracer{}, nil},
+		{&roundTripTracer{requestErr: errRequest}, errRequest},
+		{&roundTripTracer{responseErr: errResponse}, errResponse},
+	}
+	for i, testCase := range testCases {
+		req, e := http.NewRequest(http.MethodGet, server.URL, nil)
+		c.Assert(e, IsNil)
+		res, e := GetNewTraceTransport(testCase.tracer, http.DefaultTransport).RoundTrip(req)
+		c.Assert(e, Equals, testCase.err, Commentf("Test %d", i+1))
+		if res != nil {
+			res.Body.Close()
+		}
+		// Done once, also when a hook failed.
+		c.Assert(testCase.tracer.done, DeepEquals, []error{testCase.err}, Commentf("Test %d", i+1))
+	}
+}
@@ -18,11 +18,16 @@
 package ioutils
 
 import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 )
 
 // IsDirEmpty Check if a directory is empty
@@ -49,37 +54,279 @@ func FTW(root string, walkFn FTWFunc) error {
 	return walk(root, info, walkFn)
 }
 
+// Directory entries read from the system at once.
+const readDirBatch = 1024
+
+// Directory entries sorted in memory by ReadDirFunc, larger directories
+// are sorted in runs of this size spilled to temporary files.
+var readDirRunSize = 64 * 1024
+
+// dirEntry is the part of os.FileInfo listings use. The platform
+// specific Sys() data is dropped to make each entry smaller.
+type dirEntry struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (d *dirEntry) Name() string       { return d.name }
+func (d *dirEntry) Size() int64        { return d.size }
+func (d *dirEntry) Mode() os.FileMode  { return d.mode }
+func (d *dirEntry) ModTime() time.Time { return d.modTime }
+func (d *dirEntry) IsDir() bool        { return d.mode.IsDir() }
+func (d *dirEntry) Sys() interface{}   { return nil }
+
+// sortKey - the key a directory entry is sorted by, folders sort with
+// their trailing separator.
+func sortKey(fi os.FileInfo) string {
+	if fi.IsDir() {
+		return fi.Name() + string(os.PathSeparator)
+	}
+	return fi.Name()
+}
+
 // byName implements sort.Interface for sorting os.FileInfo list.
 type byName []os.FileInfo
 
-func (f byName) Len() int      { return len(f) }
-func (f byName) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
-func (f byName) Less(i, j int) bool {
-	n1 := f[i].Name()
-	if f[i].IsDir() {
-		n1 = n1 + string(os.PathSeparator)
+func (f byName) Len() int           { return len(f) }
+func (f byName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f byName) Less(i, j int) bool { return sortKey(f[i]) < sortKey(f[j]) }
+
+// ReadDir reads the directory named by dirname and returns a list of
+// directory entries sorted the way object storage lists keys, folders
+// sort with their trailing separator. All entries of the directory are
+// kept to be sorted, memory grows with the size of the directory.
+// Entries read before an error are returned along with the error.
+func ReadDir(dirname string) (fi []os.FileInfo, err error) {
+	f, err := os.Open(dirname)
+	if err != nil {
+		return nil, err
 	}
+	defer f.Close()
+	for {
+		var batch []os.FileInfo
+		batch, err = f.Readdir(readDirBatch)
+		for _, info := range batch {
+			fi = append(fi, &dirEntry{
+				name:    info.Name(),
+				size:    info.Size(),
+				mode:    info.Mode(),
+				modTime: info.ModTime(),
+			})
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	sort.Sort(byName(fi))
+	return fi, err
+}
 
-	n2 := f[j].Name()
-	if f[j].IsDir() {
-		n2 = n2 + string(os.PathSeparator)
+// ReadDirFunc calls fn for each entry of the directory named by dirname
+// in the order of ReadDir. Directories larger than a run are sorted in
+// runs spilled to temporary files which are merged, memory does not grow
+// with the size of the directory. The directory is read whole before fn
+// is first called, an error reading it is returned without calling fn.
+// An error returned by fn stops the listing and is returned.
+func ReadDirFunc(dirname string, fn func(os.FileInfo) error) error {
+	f, err := os.Open(dirname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s := &dirSorter{}
+	defer s.close()
+	for {
+		batch, err := f.Readdir(readDirBatch)
+		for _, info := range batch {
+			s.entries = append(s.entries, &dirEntry{
+				name:    info.Name(),
+				size:    info.Size(),
+				mode:    info.Mode(),
+				modTime: info.ModTime(),
+			})
+			if len(s.entries) < readDirRunSize {
+				continue
+			}
+			if err := s.spill(); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
 	}
+	f.Close()
+	return s.merge(fn)
+}
 
-	return n1 < n2
+// dirSorter sorts the entries of a directory, those which do not fit in
+// one run are spilled to sorted runs in temporary files.
+type dirSorter struct {
+	entries []os.FileInfo
+	runs    []*os.File
 }
 
-// readDir reads the directory named by dirname and returns
-// a sorted list of directory entries.
-func readDir(dirname string) (fi []os.FileInfo, err error) {
-	f, err := os.Open(dirname)
-	if err == nil {
-		defer f.Close()
-		if fi, err = f.Readdir(-1); fi != nil {
-			sort.Sort(byName(fi))
+// spill - sort the entries held in memory and write them to a new run.
+func (s *dirSorter) spill() error {
+	sort.Sort(byName(s.entries))
+	run, err := ioutil.TempFile("", "mc-readdir-")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+	w := bufio.NewWriter(run)
+	for _, fi := range s.entries {
+		if err = writeDirEntry(w, fi); err != nil {
+			return err
 		}
 	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	s.entries = s.entries[:0]
+	_, err = run.Seek(0, io.SeekStart)
+	return err
+}
 
-	return
+// close - remove the runs.
+func (s *dirSorter) close() {
+	for _, run := range s.runs {
+		run.Close()
+		os.Remove(run.Name())
+	}
+}
+
+// merge - call fn for all entries in order, the entries left in memory
+// are merged with the runs as one more run.
+func (s *dirSorter) merge(fn func(os.FileInfo) error) error {
+	sort.Sort(byName(s.entries))
+	if len(s.runs) == 0 {
+		for _, fi := range s.entries {
+			if err := fn(fi); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	h := &dirRunHeap{}
+	entries := s.entries
+	memRun := &dirRun{next: func() (os.FileInfo, error) {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		fi := entries[0]
+		entries = entries[1:]
+		return fi, nil
+	}}
+	for _, run := range append([]*dirRun{memRun}, s.fileRuns()...) {
+		if err := h.pushNext(run); err != nil {
+			return err
+		}
+	}
+	for h.Len() > 0 {
+		run := (*h)[0]
+		if err := fn(run.head); err != nil {
+			return err
+		}
+		heap.Pop(h)
+		if err := h.pushNext(run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileRuns - the runs spilled to temporary files.
+func (s *dirSorter) fileRuns() (runs []*dirRun) {
+	for _, run := range s.runs {
+		r := bufio.NewReader(run)
+		runs = append(runs, &dirRun{next: func() (os.FileInfo, error) {
+			return readDirEntry(r)
+		}})
+	}
+	return runs
+}
+
+// dirRun is a sorted run of directory entries, head is its next entry.
+type dirRun struct {
+	head os.FileInfo
+	next func() (os.FileInfo, error)
+}
+
+// dirRunHeap implements heap.Interface for the runs merged, ordered by
+// their next entry.
+type dirRunHeap []*dirRun
+
+func (h dirRunHeap) Len() int            { return len(h) }
+func (h dirRunHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h dirRunHeap) Less(i, j int) bool  { return sortKey(h[i].head) < sortKey(h[j].head) }
+func (h *dirRunHeap) Push(x interface{}) { *h = append(*h, x.(*dirRun)) }
+func (h *dirRunHeap) Pop() interface{} {
+	old := *h
+	run := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return run
+}
+
+// pushNext - push a run back with its next entry, unless it is done.
+func (h *dirRunHeap) pushNext(run *dirRun) error {
+	fi, err := run.next()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	run.head = fi
+	heap.Push(h, run)
+	return nil
+}
+
+// writeDirEntry - write an entry to a run.
+func writeDirEntry(w *bufio.Writer, fi os.FileInfo) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, v := range []uint64{uint64(len(fi.Name())), uint64(fi.Size()), uint64(fi.Mode()), uint64(fi.ModTime().UnixNano())} {
+		if _, err := w.Write(buf[:binary.PutUvarint(buf, v)]); err != nil {
+			return err
+		}
+	}
+	_, err := w.WriteString(fi.Name())
+	return err
+}
+
+// readDirEntry - read an entry written by writeDirEntry, io.EOF at the
+// end of the run.
+func readDirEntry(r *bufio.Reader) (os.FileInfo, error) {
+	var values [4]uint64
+	for i := range values {
+		v, err := binary.ReadUvarint(r)
+		if err == io.EOF && i > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	name := make([]byte, values[0])
+	if _, err := io.ReadFull(r, name); err != nil {
+		return nil, err
+	}
+	return &dirEntry{
+		name:    string(name),
+		size:    int64(values[1]),
+		mode:    os.FileMode(values[2]),
+		modTime: time.Unix(0, int64(values[3])),
+	}, nil
 }
 
 // FTWFunc is the type of the function called for each file or directory
@@ -120,25 +367,16 @@ func walk(path string, info os.FileInfo, walkFn FTWFunc) error {
 		return nil
 	}
 
-	fis, err := readDir(path)
-	if err != nil {
+	isListed := false
+	err = ReadDirFunc(path, func(fileInfo os.FileInfo) error {
+		isListed = true
+		return walk(filepath.Join(path, fileInfo.Name()), fileInfo, walkFn)
+	})
+	switch {
+	case err == nil || err == ErrSkipDir || err == ErrSkipFile:
+		return nil
+	case !isListed:
 		return walkFn(path, info, err)
 	}
-	for _, fileInfo := range fis {
-		filename := filepath.Join(path, fileInfo.Name())
-		if err != nil {
-			if err = walkFn(filename, fileInfo, err); err != nil && err != ErrSkipDir && err != ErrSkipFile {
-				return err
-			}
-		} else {
-			err = walk(filename, fileInfo, walkFn)
-			if err != nil {
-				if err == ErrSkipDir || err == ErrSkipFile {
-					return nil
-				}
-				return err
-			}
-		}
-	}
-	return nil
+	return err
 }
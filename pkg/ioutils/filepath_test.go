@@ -0,0 +1,93 @@
+/*
+ * MinIO Client (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ioutils
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadDirFunc(t *testing.T) {
+	path, err := ioutil.TempDir("", "minio-ioutils_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	names := []string{"a-b", "a0", "b", "c\xff", "d", "e", "f", "g", "h", "i", "j"}
+	for _, name := range names {
+		if err = ioutil.WriteFile(filepath.Join(path, name), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = os.Mkdir(filepath.Join(path, "a"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2019, 10, 1, 12, 0, 0, 123, time.UTC)
+	if err = os.Chtimes(filepath.Join(path, "b"), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ReadDir(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(runSize int) { readDirRunSize = runSize }(readDirRunSize)
+	// In memory, and merged from runs of 3 entries and the rest.
+	for _, runSize := range []int{64 * 1024, 3} {
+		readDirRunSize = runSize
+		var listed []os.FileInfo
+		if err = ReadDirFunc(path, func(fi os.FileInfo) error {
+			listed = append(listed, fi)
+			return nil
+		}); err != nil {
+			t.Fatalf("Run size %d: %s", runSize, err)
+		}
+		if len(listed) != len(expected) {
+			t.Fatalf("Run size %d: expected %d entries, got %d", runSize, len(expected), len(listed))
+		}
+		for i := range expected {
+			if listed[i].Name() != expected[i].Name() || listed[i].Size() != expected[i].Size() ||
+				listed[i].Mode() != expected[i].Mode() || !listed[i].ModTime().Equal(expected[i].ModTime()) {
+				t.Errorf("Run size %d: entry %d: expected %v, got %v", runSize, i, expected[i], listed[i])
+			}
+		}
+
+		// An error of fn stops the listing.
+		errStop := errors.New("stop")
+		var stopped []string
+		err = ReadDirFunc(path, func(fi os.FileInfo) error {
+			stopped = append(stopped, fi.Name())
+			if len(stopped) == 2 {
+				return errStop
+			}
+			return nil
+		})
+		if err != errStop || !reflect.DeepEqual(stopped, []string{"a-b", "a"}) {
+			t.Errorf("Run size %d: expected %v after 2 entries, got %v after %v", runSize, errStop, err, stopped)
+		}
+	}
+
+	if err = ReadDirFunc(filepath.Join(path, "missing"), func(os.FileInfo) error { return nil }); !os.IsNotExist(err) {
+		t.Errorf("Expected a not exist error, got %v", err)
+	}
+}
@@ -17,8 +17,10 @@
 package ioutils_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -80,3 +82,32 @@ func TestParseDurationTime(t *testing.T) {
 		}
 	}
 }
+
+func (s *MySuite) TestReadDir(c *C) {
+	path, err := ioutil.TempDir(os.TempDir(), "minio-ioutils_test")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(path)
+
+	// Folders sort with their trailing separator, 'a/' after 'a-b'.
+	c.Assert(os.Mkdir(filepath.Join(path, "a"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(path, "a-b"), []byte("a-b"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(path, "a0"), []byte("a0"), 0600), IsNil)
+	// More entries than read in a single batch.
+	for i := 0; i < 1100; i++ {
+		c.Assert(ioutil.WriteFile(filepath.Join(path, fmt.Sprintf("z%04d", i)), nil, 0600), IsNil)
+	}
+
+	fis, err := ioutils.ReadDir(path)
+	c.Assert(err, IsNil)
+	c.Assert(len(fis), Equals, 1103)
+	c.Assert(fis[0].Name(), Equals, "a-b")
+	c.Assert(fis[0].Size(), Equals, int64(3))
+	c.Assert(fis[1].Name(), Equals, "a")
+	c.Assert(fis[1].IsDir(), Equals, true)
+	c.Assert(fis[2].Name(), Equals, "a0")
+	c.Assert(fis[1102].Name(), Equals, "z1099")
+	c.Assert(fis[1102].Sys(), IsNil)
+
+	_, err = ioutils.ReadDir(filepath.Join(path, "missing"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}